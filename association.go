@@ -0,0 +1,370 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements association management: linking and unlinking related models.
+//
+// Association binds a saved model to one of its relations, and its methods maintain
+// the keys that link them, in one transaction (or in the current one):
+//   - HasOne/HasMany: the foreign key column of the children
+//   - BelongsTo: the foreign key column of the model itself
+//   - ManyToMany: the rows of the join table
+//
+// Usage example:
+//
+//	posts := sqlc.Association(userRepo, user, generated.User_Posts)
+//	err := posts.Append(ctx, &models.Post{Title: "new"}, existingPost) // new posts are created
+//	err = posts.Delete(ctx, existingPost)                               // posts.user_id = NULL
+//
+//	roles := sqlc.Association(userRepo, user, generated.User_Roles)
+//	err = roles.Replace(ctx, admin, editor) // join rows of other roles are deleted
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// RelationAssociation manages the related models of one model, created by Association().
+type RelationAssociation[P, C any, K comparable] struct {
+	session *Session
+	schema  Schema[P]
+	parent  *P
+	rel     Relation[P, C, K]
+}
+
+// Association creates the association of parent's relation rel, executed on the
+// repository's session.
+//
+// Parameters:
+//   - repo: Repository of the parent model; a repository on a transaction session keeps
+//     the changes in that transaction
+//   - parent: Saved parent model
+//   - rel: Relation of the parent model (e.g. generated.User_Posts)
+//
+// Returns:
+//   - RelationAssociation[P, C, K]: Association with Append, Replace, Delete and Clear
+//
+// Example:
+//
+//	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+//	    repo := sqlc.NewRepository[models.User](tx)
+//	    return sqlc.Association(repo, user, generated.User_Roles).Append(ctx, role)
+//	})
+//
+// Note:
+//   - Key fields of the passed models are updated along with the database; relation
+//     fields (e.g. user.Posts) are not, reload them with Preload
+//   - Children with a zero primary key are created with their repository's Create
+//   - Key fields must be convertible from the relation's key type, or pointers to it
+func Association[P, C any, K comparable](repo *Repository[P], parent *P, rel Relation[P, C, K]) RelationAssociation[P, C, K] {
+	return RelationAssociation[P, C, K]{session: repo.session, schema: repo.schema, parent: parent, rel: rel}
+}
+
+// Append links children to the parent, creating the new ones.
+// For HasOne and BelongsTo, which link a single model, Append replaces the linked model.
+func (a RelationAssociation[P, C, K]) Append(ctx context.Context, children ...*C) error {
+	if len(children) == 0 {
+		return nil
+	}
+	return a.session.Transaction(ctx, func(tx *Session) error {
+		switch a.rel.Type {
+		case RelationHasOne:
+			return a.replaceChildren(ctx, tx, children)
+		case RelationBelongsTo:
+			return a.setOwner(ctx, tx, children)
+		case RelationManyToMany:
+			return a.appendLinks(ctx, tx, children)
+		default:
+			return a.appendChildren(ctx, tx, children)
+		}
+	})
+}
+
+// Replace links exactly children to the parent: children are appended, and the models
+// linked before but not passed are unlinked. Replace without children is Clear.
+func (a RelationAssociation[P, C, K]) Replace(ctx context.Context, children ...*C) error {
+	if len(children) == 0 {
+		return a.Clear(ctx)
+	}
+	return a.session.Transaction(ctx, func(tx *Session) error {
+		switch a.rel.Type {
+		case RelationBelongsTo:
+			return a.setOwner(ctx, tx, children)
+		case RelationManyToMany:
+			if err := a.appendLinks(ctx, tx, children); err != nil {
+				return err
+			}
+			return a.deleteLinks(ctx, tx, sq.NotEq{a.rel.JoinReferences.Name: a.targetKeys(children)})
+		default:
+			return a.replaceChildren(ctx, tx, children)
+		}
+	})
+}
+
+// Delete unlinks children from the parent without deleting them: their foreign key is
+// set to NULL (HasOne, HasMany), the parent's foreign key is set to NULL if it references
+// them (BelongsTo), or their join rows are deleted (ManyToMany).
+func (a RelationAssociation[P, C, K]) Delete(ctx context.Context, children ...*C) error {
+	if len(children) == 0 {
+		return nil
+	}
+	return a.session.Transaction(ctx, func(tx *Session) error {
+		switch a.rel.Type {
+		case RelationBelongsTo:
+			if !slices.Contains(a.targetKeys(children), any(a.rel.GetLocalKeyValue(a.parent))) {
+				return nil
+			}
+			return a.clearOwner(ctx, tx)
+		case RelationManyToMany:
+			return a.deleteLinks(ctx, tx, sq.Eq{a.rel.JoinReferences.Name: a.targetKeys(children)})
+		default:
+			childPK := LoadSchema[C]().PK(nil).Column.Name
+			if err := a.unlinkChildren(ctx, tx, sq.Eq{childPK: a.childPKs(children)}); err != nil {
+				return err
+			}
+			for _, child := range children {
+				if err := setColumnValue(tx, child, a.rel.ForeignKey.Name, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	})
+}
+
+// Clear unlinks all models linked to the parent, without deleting them.
+func (a RelationAssociation[P, C, K]) Clear(ctx context.Context) error {
+	return a.session.Transaction(ctx, func(tx *Session) error {
+		switch a.rel.Type {
+		case RelationBelongsTo:
+			return a.clearOwner(ctx, tx)
+		case RelationManyToMany:
+			return a.deleteLinks(ctx, tx, nil)
+		default:
+			return a.unlinkChildren(ctx, tx, nil)
+		}
+	})
+}
+
+// appendChildren sets the foreign key of HasOne/HasMany children to the parent key.
+func (a RelationAssociation[P, C, K]) appendChildren(ctx context.Context, tx *Session, children []*C) error {
+	key := a.rel.GetLocalKeyValue(a.parent)
+	if isZeroValue(key) {
+		return fmt.Errorf("sqlc: association parent %v must be saved first", reflect.TypeFor[P]())
+	}
+
+	repo := NewRepository[C](tx)
+	var existing []any
+	for _, child := range children {
+		if err := setColumnValue(tx, child, a.rel.ForeignKey.Name, key); err != nil {
+			return err
+		}
+		if pk := repo.schema.PK(child).Value; !isZeroValue(pk) {
+			existing = append(existing, pk)
+			continue
+		}
+		if err := repo.Create(ctx, child); err != nil {
+			return err
+		}
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+	return execSqlizer(ctx, tx, sq.Update(repo.schema.TableName()).
+		Set(a.rel.ForeignKey.Name, key).
+		Where(sq.Eq{repo.schema.PK(nil).Column.Name: existing}))
+}
+
+// replaceChildren appends HasOne/HasMany children and unlinks the other children.
+func (a RelationAssociation[P, C, K]) replaceChildren(ctx context.Context, tx *Session, children []*C) error {
+	if a.rel.Type == RelationHasOne && len(children) > 1 {
+		return fmt.Errorf("sqlc: HasOne association of %v links one model, got %d", reflect.TypeFor[P](), len(children))
+	}
+	if err := a.appendChildren(ctx, tx, children); err != nil {
+		return err
+	}
+	childPK := LoadSchema[C]().PK(nil).Column.Name
+	return a.unlinkChildren(ctx, tx, sq.NotEq{childPK: a.childPKs(children)})
+}
+
+// unlinkChildren sets the foreign key of the parent's HasOne/HasMany children matching
+// cond (nil: all children) to NULL.
+func (a RelationAssociation[P, C, K]) unlinkChildren(ctx context.Context, tx *Session, cond sq.Sqlizer) error {
+	b := sq.Update(LoadSchema[C]().TableName()).
+		Set(a.rel.ForeignKey.Name, nil).
+		Where(sq.Eq{a.rel.ForeignKey.Name: a.rel.GetLocalKeyValue(a.parent)})
+	if cond != nil {
+		b = b.Where(cond)
+	}
+	return execSqlizer(ctx, tx, b)
+}
+
+// setOwner links the parent of a BelongsTo relation to the owner in children.
+func (a RelationAssociation[P, C, K]) setOwner(ctx context.Context, tx *Session, children []*C) error {
+	if len(children) > 1 {
+		return fmt.Errorf("sqlc: BelongsTo association of %v links one model, got %d", reflect.TypeFor[P](), len(children))
+	}
+	owner := children[0]
+	repo := NewRepository[C](tx)
+	if isZeroValue(repo.schema.PK(owner).Value) {
+		if err := repo.Create(ctx, owner); err != nil {
+			return err
+		}
+	}
+	return a.setParentKey(ctx, tx, a.rel.GetForeignKeyValue(owner))
+}
+
+// clearOwner sets the foreign key of the parent of a BelongsTo relation to NULL.
+func (a RelationAssociation[P, C, K]) clearOwner(ctx context.Context, tx *Session) error {
+	return a.setParentKey(ctx, tx, nil)
+}
+
+// setParentKey sets the foreign key column of the parent to key, in the model and the database.
+func (a RelationAssociation[P, C, K]) setParentKey(ctx context.Context, tx *Session, key any) error {
+	pk := a.schema.PK(a.parent)
+	if isZeroValue(pk.Value) {
+		return fmt.Errorf("sqlc: association parent %v must be saved first", reflect.TypeFor[P]())
+	}
+	if err := setColumnValue(tx, a.parent, a.rel.LocalKey.Name, key); err != nil {
+		return err
+	}
+	return execSqlizer(ctx, tx, sq.Update(a.schema.TableName()).
+		Set(a.rel.LocalKey.Name, key).
+		Where(sq.Eq{pk.Column.Name: pk.Value}))
+}
+
+// appendLinks creates the new ManyToMany children and inserts the missing join rows.
+func (a RelationAssociation[P, C, K]) appendLinks(ctx context.Context, tx *Session, children []*C) error {
+	key := a.rel.GetLocalKeyValue(a.parent)
+	if isZeroValue(key) {
+		return fmt.Errorf("sqlc: association parent %v must be saved first", reflect.TypeFor[P]())
+	}
+
+	repo := NewRepository[C](tx)
+	for _, child := range children {
+		if isZeroValue(repo.schema.PK(child).Value) {
+			if err := repo.Create(ctx, child); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Skip children already linked, so join tables need no unique constraint
+	query, args, err := sq.Select(a.rel.JoinReferences.Name).
+		From(a.rel.JoinTable).
+		Where(sq.Eq{a.rel.JoinForeignKey.Name: key, a.rel.JoinReferences.Name: a.targetKeys(children)}).
+		PlaceholderFormat(tx.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+	var linked []K
+	if err := tx.Select(ctx, &linked, query, args...); err != nil {
+		return fmt.Errorf("sqlc: failed to read join table %s: %w", a.rel.JoinTable, err)
+	}
+	seen := make(map[K]struct{}, len(linked)+len(children))
+	for _, k := range linked {
+		seen[k] = struct{}{}
+	}
+
+	insert := sq.Insert(a.rel.JoinTable).Columns(a.rel.JoinForeignKey.Name, a.rel.JoinReferences.Name)
+	rows := 0
+	for _, child := range children {
+		target := a.rel.GetForeignKeyValue(child)
+		if _, ok := seen[target]; ok {
+			continue
+		}
+		seen[target] = struct{}{}
+		insert = insert.Values(key, target)
+		rows++
+	}
+	if rows == 0 {
+		return nil
+	}
+	return execSqlizer(ctx, tx, insert)
+}
+
+// deleteLinks deletes the parent's join rows matching cond (nil: all join rows).
+func (a RelationAssociation[P, C, K]) deleteLinks(ctx context.Context, tx *Session, cond sq.Sqlizer) error {
+	b := sq.Delete(a.rel.JoinTable).
+		Where(sq.Eq{a.rel.JoinForeignKey.Name: a.rel.GetLocalKeyValue(a.parent)})
+	if cond != nil {
+		b = b.Where(cond)
+	}
+	return execSqlizer(ctx, tx, b)
+}
+
+// targetKeys returns the key values of children referenced by the relation.
+func (a RelationAssociation[P, C, K]) targetKeys(children []*C) []any {
+	keys := make([]any, len(children))
+	for i, child := range children {
+		keys[i] = a.rel.GetForeignKeyValue(child)
+	}
+	return keys
+}
+
+// childPKs returns the primary key values of children.
+func (a RelationAssociation[P, C, K]) childPKs(children []*C) []any {
+	schema := LoadSchema[C]()
+	pks := make([]any, len(children))
+	for i, child := range children {
+		pks[i] = schema.PK(child).Value
+	}
+	return pks
+}
+
+// placeholderBuilder is a squirrel statement builder with a placeholder format.
+type placeholderBuilder[B any] interface {
+	sq.Sqlizer
+	PlaceholderFormat(sq.PlaceholderFormat) B
+}
+
+// execSqlizer builds b with the session's placeholder format and executes it.
+func execSqlizer[B placeholderBuilder[B]](ctx context.Context, s *Session, b B) error {
+	query, args, err := b.PlaceholderFormat(s.dialect.PlaceholderFormat()).ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+	_, err = s.Exec(ctx, query, args...)
+	return err
+}
+
+// setColumnValue sets the field of model mapped to column to value, converting it to the
+// field type; a nil value sets the zero value.
+func setColumnValue(s *Session, model any, column string, value any) error {
+	v := reflect.ValueOf(model).Elem()
+	index := s.db.Mapper.TraversalsByName(v.Type(), []string{column})[0]
+	if len(index) == 0 {
+		return fmt.Errorf("sqlc: %v has no field for column %s", v.Type(), column)
+	}
+	field := reflectx.FieldByIndexes(v, index)
+	if value == nil {
+		field.SetZero()
+		return nil
+	}
+
+	target := field.Type()
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+	val := reflect.ValueOf(value)
+	if !val.Type().ConvertibleTo(target) {
+		return fmt.Errorf("sqlc: cannot set %v field for column %s to %T", field.Type(), column, value)
+	}
+	val = val.Convert(target)
+	if field.Kind() == reflect.Pointer {
+		ptr := reflect.New(target)
+		ptr.Elem().Set(val)
+		val = ptr
+	}
+	field.Set(val)
+	return nil
+}
+
+// isZeroValue reports whether v is nil or the zero value of its type.
+func isZeroValue(v any) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
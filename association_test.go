@@ -0,0 +1,234 @@
+package sqlc_test
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestAssociation(t *testing.T) {
+	ctx := context.Background()
+
+	// memberNames returns the names of the members linked to department id, by id
+	memberNames := func(t *testing.T, db *sql.DB, id int64) []string {
+		t.Helper()
+		rows, err := db.Query("SELECT name FROM members WHERE department_id = ? ORDER BY id", id)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		defer rows.Close()
+		names := []string{}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatalf("Scan failed: %v", err)
+			}
+			names = append(names, name)
+		}
+		return names
+	}
+	newMember := func(name string) *Member {
+		return &Member{Name: name, Email: name + "@test.com", CreatedAt: time.Now()}
+	}
+
+	t.Run("HasMany", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		deptRepo := sqlc.NewRepository[Department](session)
+		memberRepo := sqlc.NewRepository[Member](session)
+
+		eng := &Department{Name: "Engineering"}
+		if err := deptRepo.Create(ctx, eng); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		bob := newMember("bob")
+		if err := memberRepo.Create(ctx, bob); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		members := sqlc.Association(deptRepo, eng, DepartmentHasMembers)
+
+		// New members are created, existing ones relinked
+		alice := newMember("alice")
+		if err := members.Append(ctx, alice, bob); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		if alice.ID == 0 || alice.DepartmentID != int(eng.ID) || bob.DepartmentID != int(eng.ID) {
+			t.Errorf("expected linked models, got alice %+v, bob %+v", alice, bob)
+		}
+		if got := memberNames(t, db, eng.ID); !reflect.DeepEqual(got, []string{"bob", "alice"}) {
+			t.Errorf("after Append: got %v", got)
+		}
+
+		if err := members.Delete(ctx, bob); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if got := memberNames(t, db, eng.ID); !reflect.DeepEqual(got, []string{"alice"}) || bob.DepartmentID != 0 {
+			t.Errorf("after Delete: got %v, bob %+v", got, bob)
+		}
+
+		carol := newMember("carol")
+		if err := members.Replace(ctx, bob, carol); err != nil {
+			t.Fatalf("Replace failed: %v", err)
+		}
+		if got := memberNames(t, db, eng.ID); !reflect.DeepEqual(got, []string{"bob", "carol"}) {
+			t.Errorf("after Replace: got %v", got)
+		}
+
+		if err := members.Clear(ctx); err != nil {
+			t.Fatalf("Clear failed: %v", err)
+		}
+		if got := memberNames(t, db, eng.ID); len(got) != 0 {
+			t.Errorf("after Clear: got %v", got)
+		}
+		// Unlinked members are kept
+		if n, _ := memberRepo.Query().Count(ctx); n != 3 {
+			t.Errorf("expected 3 members, got %d", n)
+		}
+	})
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		memberRepo := sqlc.NewRepository[Member](session)
+
+		alice := newMember("alice")
+		if err := memberRepo.Create(ctx, alice); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		dept := sqlc.Association(memberRepo, alice, MemberDepartment)
+
+		sales := &Department{Name: "Sales"}
+		if err := dept.Append(ctx, sales); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		if sales.ID == 0 || alice.DepartmentID != int(sales.ID) {
+			t.Errorf("expected alice in the created department, got %+v, %+v", alice, sales)
+		}
+		if got := memberNames(t, db, sales.ID); !reflect.DeepEqual(got, []string{"alice"}) {
+			t.Errorf("after Append: got %v", got)
+		}
+
+		// Deleting another owner leaves the link alone
+		if err := dept.Delete(ctx, &Department{ID: sales.ID + 1}); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if alice.DepartmentID != int(sales.ID) {
+			t.Errorf("expected link kept, got %+v", alice)
+		}
+		if err := dept.Delete(ctx, sales); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if got := memberNames(t, db, sales.ID); len(got) != 0 || alice.DepartmentID != 0 {
+			t.Errorf("after Delete: got %v, alice %+v", got, alice)
+		}
+
+		if err := dept.Append(ctx, sales, &Department{Name: "Other"}); err == nil {
+			t.Error("expected error appending two owners")
+		}
+	})
+
+	t.Run("ManyToMany", func(t *testing.T) {
+		sqlc.RegisterSchema(StudentSchema{})
+		sqlc.RegisterSchema(CourseSchema{})
+		db, session := setupTestDB(t)
+		defer db.Close()
+		for _, ddl := range []string{
+			`CREATE TABLE students (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`,
+			`CREATE TABLE courses (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT)`,
+			`CREATE TABLE enrollments (student_id INTEGER, course_id INTEGER, PRIMARY KEY (student_id, course_id))`,
+		} {
+			if _, err := db.Exec(ddl); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+		}
+		studentRepo := sqlc.NewRepository[Student](session)
+		alice := &Student{Name: "alice"}
+		if err := studentRepo.Create(ctx, alice); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		courses := sqlc.Association(studentRepo, alice, StudentCourses)
+
+		titles := func() []string {
+			got, err := studentRepo.Query().
+				WithPreload(sqlc.Preload(StudentCourses)).
+				Where(clause.Eq{Column: clause.Column{Name: "id"}, Value: alice.ID}).
+				First(ctx)
+			if err != nil {
+				t.Fatalf("First failed: %v", err)
+			}
+			out := []string{}
+			for _, c := range got.Courses {
+				out = append(out, c.Title)
+			}
+			slices.Sort(out)
+			return out
+		}
+
+		db1, algebra := &Course{Title: "Databases"}, &Course{Title: "Algebra"}
+		if err := courses.Append(ctx, db1, algebra); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		// Appending a linked course again does not duplicate its join row
+		if err := courses.Append(ctx, db1); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		if got := titles(); !reflect.DeepEqual(got, []string{"Algebra", "Databases"}) {
+			t.Errorf("after Append: got %v", got)
+		}
+
+		compilers := &Course{Title: "Compilers"}
+		if err := courses.Replace(ctx, algebra, compilers); err != nil {
+			t.Fatalf("Replace failed: %v", err)
+		}
+		if got := titles(); !reflect.DeepEqual(got, []string{"Algebra", "Compilers"}) {
+			t.Errorf("after Replace: got %v", got)
+		}
+
+		if err := courses.Delete(ctx, algebra); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if got := titles(); !reflect.DeepEqual(got, []string{"Compilers"}) {
+			t.Errorf("after Delete: got %v", got)
+		}
+
+		if err := courses.Clear(ctx); err != nil {
+			t.Fatalf("Clear failed: %v", err)
+		}
+		if got := titles(); len(got) != 0 {
+			t.Errorf("after Clear: got %v", got)
+		}
+		// Courses themselves are kept
+		if n, _ := sqlc.NewRepository[Course](session).Query().Count(ctx); n != 3 {
+			t.Errorf("expected 3 courses, got %d", n)
+		}
+	})
+
+	t.Run("RollbackInTransaction", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		eng := &Department{Name: "Engineering"}
+		if err := sqlc.NewRepository[Department](session).Create(ctx, eng); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			repo := sqlc.NewRepository[Department](tx)
+			if err := sqlc.Association(repo, eng, DepartmentHasMembers).Append(ctx, newMember("dave")); err != nil {
+				return err
+			}
+			return sql.ErrTxDone // Roll back
+		})
+		if err != sql.ErrTxDone {
+			t.Fatalf("expected rollback error, got %v", err)
+		}
+		if got := memberNames(t, db, eng.ID); len(got) != 0 {
+			t.Errorf("expected rolled back append, got %v", got)
+		}
+	})
+}
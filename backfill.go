@@ -0,0 +1,326 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements rate-limited, resumable backfills over large tables.
+//
+// Data migrations (filling a new column, reindexing, re-encrypting) walk a whole table in
+// batches. Backfill provides the scaffolding such jobs share:
+//   - Keyset chunking: batches are read in primary key order with WHERE pk > last, so each
+//     batch is an index range scan however far the job is, and no row is skipped or read
+//     twice when rows are inserted or deleted meanwhile
+//   - Rate limiting: batches are paced to a maximum number of rows per second, leaving
+//     headroom for production traffic
+//   - Checkpoints: with WithBackfillCheckpoint, the last processed key is saved to a control
+//     table after each batch, and a restarted job resumes after it
+//
+// Usage example:
+//
+//	// Lowercase every email, 500 rows per batch, at most 2000 rows per second
+//	err := sqlc.Backfill(ctx, userRepo.Query().Where(generated.User.EmailLower.Eq("")), 500, 2000,
+//	    func(users []*models.User) error {
+//	        for _, u := range users {
+//	            lower := generated.User.EmailLower.Set(strings.ToLower(u.Email))
+//	            if err := userRepo.UpdateColumns(ctx, u.ID, lower); err != nil {
+//	                return err
+//	            }
+//	        }
+//	        return nil
+//	    },
+//	    sqlc.WithBackfillCheckpoint("users-email-lower"),
+//	)
+package sqlc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// DefaultBackfillTable is the control table holding backfill checkpoints.
+const DefaultBackfillTable = "sqlc_backfills"
+
+// BackfillTableDDL returns a portable CREATE TABLE statement for a backfill control table.
+// The statement works on MySQL, PostgreSQL and SQLite. Backfill runs it before saving
+// the first checkpoint; use it to create the table from migrations instead.
+//
+// Example:
+//
+//	_, err := session.Exec(ctx, sqlc.BackfillTableDDL(sqlc.DefaultBackfillTable))
+func BackfillTableDDL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	name VARCHAR(255) NOT NULL PRIMARY KEY,
+	last_key VARCHAR(255) NOT NULL,
+	processed BIGINT NOT NULL DEFAULT 0,
+	completed INT NOT NULL DEFAULT 0,
+	updated_at TIMESTAMP NOT NULL
+)`, table)
+}
+
+// BackfillProgress reports the progress of a backfill after each batch.
+type BackfillProgress struct {
+	Name      string // Checkpoint name ("" without WithBackfillCheckpoint)
+	Batches   int    // Batches processed by this run
+	Processed int64  // Rows processed, including those of resumed runs
+	LastKey   any    // Primary key of the last processed row
+}
+
+// backfillConfig is the configuration of Backfill
+type backfillConfig struct {
+	name     string                 // Checkpoint name ("" = no checkpoints)
+	table    string                 // Control table
+	progress func(BackfillProgress) // Called after each batch
+}
+
+// BackfillOption configures Backfill.
+// Uses functional options pattern to provide flexible configuration.
+type BackfillOption func(*backfillConfig)
+
+// WithBackfillCheckpoint saves the progress of the backfill under name after each batch,
+// and resumes a previous run of the same name after its last checkpoint.
+// A completed backfill is not run again; delete its control table row to rerun it.
+func WithBackfillCheckpoint(name string) BackfillOption {
+	return func(c *backfillConfig) {
+		c.name = name
+	}
+}
+
+// WithBackfillTable sets the control table of the checkpoints (default DefaultBackfillTable).
+func WithBackfillTable(table string) BackfillOption {
+	return func(c *backfillConfig) {
+		c.table = table
+	}
+}
+
+// WithBackfillProgress calls fn after each processed batch, e.g. to log or export progress.
+func WithBackfillProgress(fn func(BackfillProgress)) BackfillOption {
+	return func(c *backfillConfig) {
+		c.progress = fn
+	}
+}
+
+// backfillCheckpoint is a row of the control table
+type backfillCheckpoint struct {
+	LastKey   string `db:"last_key"`
+	Processed int64  `db:"processed"`
+	Completed int    `db:"completed"`
+}
+
+// Backfill processes the records matching q in batches of batchSize, in primary key order,
+// at most rate rows per second.
+//
+// Parameters:
+//   - ctx: Context for cancellation; canceling stops between batches
+//   - q: Query selecting the records to process (conditions, preloads, Filter/Map stages)
+//   - batchSize: Maximum number of records per batch (must be positive)
+//   - rate: Maximum rows per second; 0 for no limit
+//   - fn: Callback processing each batch; returning an error stops the backfill
+//   - opts: Checkpoint and progress options
+//
+// Returns:
+//   - error: Query, checkpoint or callback error, or ctx.Err() if canceled
+//
+// Example:
+//
+//	err := sqlc.Backfill(ctx, orderRepo.Query(), 1000, 5000, reindexOrders,
+//	    sqlc.WithBackfillCheckpoint("orders-reindex-2024-06"),
+//	    sqlc.WithBackfillProgress(func(p sqlc.BackfillProgress) {
+//	        log.Printf("%s: %d rows, last id %v", p.Name, p.Processed, p.LastKey)
+//	    }),
+//	)
+//
+// Note:
+//   - Batches are checkpointed after fn returns, so a batch interrupted by a crash is
+//     processed again on resume: fn must be idempotent
+//   - Rows whose primary key is below the last checkpoint are not revisited, including
+//     rows that start matching q meanwhile
+//   - q must not be ordered: batches are ordered by primary key
+func Backfill[T any](
+	ctx context.Context,
+	q *QueryBuilder[T],
+	batchSize int,
+	rate float64,
+	fn func(batch []*T) error,
+	opts ...BackfillOption,
+) error {
+	if q.err != nil {
+		return q.err
+	}
+	if batchSize <= 0 {
+		return fmt.Errorf("sqlc: backfill batch size must be positive, got %d", batchSize)
+	}
+	if q.ordered {
+		return fmt.Errorf("sqlc: backfill orders by primary key, remove OrderBy from the query")
+	}
+	cfg := backfillConfig{table: DefaultBackfillTable}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	pkCol := q.schema.PK(nil).Column
+	if q.hasJoin {
+		pkCol.Table = q.table
+	}
+	keyType := reflect.TypeOf(q.schema.PK(new(T)).Value)
+
+	// Resume after the last checkpoint
+	var last any
+	progress := BackfillProgress{Name: cfg.name}
+	if cfg.name != "" {
+		cp, err := loadBackfillCheckpoint(ctx, q.session, cfg)
+		if err != nil {
+			return err
+		}
+		if cp != nil && cp.Completed != 0 {
+			return nil
+		}
+		if cp != nil && cp.LastKey != "" {
+			key := reflect.New(keyType)
+			if err := json.Unmarshal([]byte(cp.LastKey), key.Interface()); err != nil {
+				return fmt.Errorf("sqlc: invalid checkpoint %q of backfill %s: %w", cp.LastKey, cfg.name, err)
+			}
+			last = key.Elem().Interface()
+			progress.Processed = cp.Processed
+			progress.LastKey = last
+		}
+	}
+
+	// Stages run per batch after paging decisions, since Filter() may shrink a batch
+	page := q.Clone()
+	page.stages = nil
+	page = page.OrderBy(clause.OrderByColumn{Column: pkCol}).Limit(uint64(batchSize))
+
+	var next time.Time // Earliest start of the next batch
+	for {
+		if err := waitUntil(ctx, next); err != nil {
+			return err
+		}
+		start := time.Now()
+
+		batch := page
+		if last != nil {
+			batch = batch.Where(clause.Gt{Column: pkCol, Value: last})
+		}
+		results, err := batch.Find(ctx)
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			break
+		}
+
+		filtered, err := q.applyStages(results)
+		if err != nil {
+			return err
+		}
+		if len(filtered) > 0 {
+			if err := fn(filtered); err != nil {
+				return err
+			}
+		}
+
+		last = q.schema.PK(results[len(results)-1]).Value
+		progress.Batches++
+		progress.Processed += int64(len(results))
+		progress.LastKey = last
+		done := len(results) < batchSize
+		if cfg.name != "" {
+			if err := saveBackfillCheckpoint(ctx, q.session, cfg, last, progress.Processed, done); err != nil {
+				return err
+			}
+		}
+		if cfg.progress != nil {
+			cfg.progress(progress)
+		}
+		if done {
+			return nil
+		}
+		if rate > 0 {
+			next = start.Add(time.Duration(float64(len(results)) / rate * float64(time.Second)))
+		}
+	}
+
+	// The last batch was full, or nothing matched
+	if cfg.name != "" {
+		return saveBackfillCheckpoint(ctx, q.session, cfg, last, progress.Processed, true)
+	}
+	return nil
+}
+
+// waitUntil sleeps until t, returning early with ctx.Err() if ctx is canceled.
+func waitUntil(ctx context.Context, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// loadBackfillCheckpoint creates the control table if needed and reads the checkpoint
+// of the backfill, or nil if it never ran.
+func loadBackfillCheckpoint(ctx context.Context, session *Session, cfg backfillConfig) (*backfillCheckpoint, error) {
+	if _, err := session.Exec(ctx, BackfillTableDDL(cfg.table)); err != nil {
+		return nil, fmt.Errorf("sqlc: failed to create backfill table: %w", err)
+	}
+	query, args, err := sq.Select("last_key", "processed", "completed").
+		From(cfg.table).
+		Where(sq.Eq{"name": cfg.name}).
+		PlaceholderFormat(session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	var rows []backfillCheckpoint
+	if err := session.Select(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("sqlc: failed to load backfill checkpoint: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// saveBackfillCheckpoint records the last processed key of the backfill.
+func saveBackfillCheckpoint(ctx context.Context, session *Session, cfg backfillConfig, last any, processed int64, done bool) error {
+	lastKey := ""
+	if last != nil {
+		b, err := json.Marshal(last)
+		if err != nil {
+			return fmt.Errorf("sqlc: failed to encode backfill checkpoint: %w", err)
+		}
+		lastKey = string(b)
+	}
+	completed := 0
+	if done {
+		completed = 1
+	}
+
+	updateCols := []string{"last_key", "processed", "completed", "updated_at"}
+	query, args, err := sq.Insert(cfg.table).
+		Columns(append([]string{"name"}, updateCols...)...).
+		Values(cfg.name, lastKey, processed, completed, time.Now().UTC()).
+		Suffix(session.dialect.UpsertClause(cfg.table, []string{"name"}, updateCols)).
+		PlaceholderFormat(session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	if _, err := session.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlc: failed to save backfill checkpoint: %w", err)
+	}
+	return nil
+}
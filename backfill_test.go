@@ -0,0 +1,150 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestBackfill(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, n int) (*sqlc.Session, *sqlc.Repository[Member]) {
+		t.Helper()
+		db, session := setupIntegrationDB(t)
+		t.Cleanup(func() { db.Close() })
+		memberRepo := sqlc.NewRepository[Member](session)
+		var members []*Member
+		for i := 0; i < n; i++ {
+			members = append(members, &Member{
+				Name:      fmt.Sprintf("Backfill%d", i),
+				Email:     fmt.Sprintf("backfill%d@test.com", i),
+				Level:     i % 2,
+				CreatedAt: time.Now(),
+			})
+		}
+		if err := memberRepo.BatchCreate(ctx, members); err != nil {
+			t.Fatalf("BatchCreate failed: %v", err)
+		}
+		return session, memberRepo
+	}
+	level := field.Number[int]{}.WithColumn("level")
+	id := field.Number[int64]{}.WithColumn("id")
+
+	t.Run("Batches", func(t *testing.T) {
+		_, memberRepo := setup(t, 7)
+		var sizes []int
+		var names []string
+		var progress []sqlc.BackfillProgress
+		err := sqlc.Backfill(ctx, memberRepo.Query(), 3, 0, func(batch []*Member) error {
+			sizes = append(sizes, len(batch))
+			for _, m := range batch {
+				names = append(names, m.Name)
+			}
+			return nil
+		}, sqlc.WithBackfillProgress(func(p sqlc.BackfillProgress) { progress = append(progress, p) }))
+		if err != nil {
+			t.Fatalf("Backfill failed: %v", err)
+		}
+		if fmt.Sprint(sizes) != "[3 3 1]" {
+			t.Errorf("expected batch sizes [3 3 1], got %v", sizes)
+		}
+		if len(names) != 7 || names[0] != "Backfill0" || names[6] != "Backfill6" {
+			t.Errorf("expected members in id order, got %v", names)
+		}
+		if len(progress) != 3 || progress[2].Batches != 3 || progress[2].Processed != 7 {
+			t.Errorf("unexpected progress %+v", progress)
+		}
+	})
+
+	t.Run("Conditions", func(t *testing.T) {
+		_, memberRepo := setup(t, 7)
+		var names []string
+		err := sqlc.Backfill(ctx, memberRepo.Query().Where(level.Eq(1)), 2, 0, func(batch []*Member) error {
+			for _, m := range batch {
+				names = append(names, m.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Backfill failed: %v", err)
+		}
+		if fmt.Sprint(names) != "[Backfill1 Backfill3 Backfill5]" {
+			t.Errorf("unexpected members %v", names)
+		}
+	})
+
+	t.Run("ResumeFromCheckpoint", func(t *testing.T) {
+		session, memberRepo := setup(t, 7)
+		defer session.Exec(ctx, "DROP TABLE "+sqlc.DefaultBackfillTable)
+		errStop := errors.New("stop")
+		var names []string
+		run := func(failAt int) error {
+			batches := 0
+			return sqlc.Backfill(ctx, memberRepo.Query(), 3, 0, func(batch []*Member) error {
+				if batches++; batches == failAt {
+					return errStop
+				}
+				for _, m := range batch {
+					names = append(names, m.Name)
+				}
+				return nil
+			}, sqlc.WithBackfillCheckpoint("members-test"))
+		}
+
+		if err := run(2); !errors.Is(err, errStop) {
+			t.Fatalf("expected the callback error, got %v", err)
+		}
+		if err := run(0); err != nil {
+			t.Fatalf("Backfill failed: %v", err)
+		}
+		want := "[Backfill0 Backfill1 Backfill2 Backfill3 Backfill4 Backfill5 Backfill6]"
+		if fmt.Sprint(names) != want {
+			t.Errorf("expected the second run to resume after the first batch\ngot  %v\nwant %s", names, want)
+		}
+
+		// Completed: a rerun processes nothing
+		names = nil
+		if err := run(0); err != nil || names != nil {
+			t.Errorf("expected completed backfill to be skipped, got %v, %v", names, err)
+		}
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		_, memberRepo := setup(t, 6)
+		start := time.Now()
+		// 2 rows per batch at 100 rows/s: 20ms between batch starts
+		if err := sqlc.Backfill(ctx, memberRepo.Query(), 2, 100, func([]*Member) error { return nil }); err != nil {
+			t.Fatalf("Backfill failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("expected at least 40ms for 3 batches, took %v", elapsed)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		_, memberRepo := setup(t, 6)
+		ctx, cancel := context.WithCancel(ctx)
+		batches := 0
+		err := sqlc.Backfill(ctx, memberRepo.Query(), 2, 0, func([]*Member) error {
+			batches++
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) || batches != 1 {
+			t.Errorf("expected cancellation after 1 batch, got %d batches, %v", batches, err)
+		}
+	})
+
+	t.Run("OrderedRejected", func(t *testing.T) {
+		_, memberRepo := setup(t, 1)
+		err := sqlc.Backfill(ctx, memberRepo.Query().OrderBy(id.Desc()), 10, 0, func([]*Member) error { return nil })
+		if err == nil {
+			t.Fatal("expected error for an ordered query")
+		}
+	})
+}
@@ -0,0 +1,270 @@
+// Package bench runs standardized sqlc workloads against a Session and reports
+// throughput and allocations, so dialects, drivers and sqlc versions can be
+// compared on the same hardware.
+//
+// The workloads use their own tables (sqlc_bench_authors, sqlc_bench_posts), which
+// are created at the start of a run and dropped at the end.
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL)
+//	results, err := bench.Run(ctx, session, bench.WithDuration(2*time.Second))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	bench.WriteReport(os.Stdout, results)
+//
+// Results are JSON-serializable; store them as a baseline and gate later runs with Compare:
+//
+//	if regressions := bench.Compare(baseline, results, 0.10); len(regressions) > 0 {
+//	    log.Fatalf("performance regressions: %v", regressions)
+//	}
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arllen133/sqlc"
+)
+
+// Option configures a benchmark run.
+type Option func(*config)
+
+type config struct {
+	workloads  []Workload    // Workloads to run, in order
+	duration   time.Duration // Target measured time per workload
+	iterations int           // Fixed iteration count (overrides duration when > 0)
+}
+
+// WithWorkloads replaces the standard workloads.
+func WithWorkloads(workloads ...Workload) Option {
+	return func(c *config) {
+		c.workloads = workloads
+	}
+}
+
+// WithDuration sets the target measured time per workload (default 1s).
+func WithDuration(d time.Duration) Option {
+	return func(c *config) {
+		c.duration = d
+	}
+}
+
+// WithIterations runs every workload exactly n times instead of calibrating to a duration.
+func WithIterations(n int) Option {
+	return func(c *config) {
+		c.iterations = n
+	}
+}
+
+// Result is the measurement of one workload.
+type Result struct {
+	Workload    string        `json:"workload"`
+	Dialect     string        `json:"dialect"`
+	N           int           `json:"n"`             // Iterations measured
+	Elapsed     time.Duration `json:"elapsed"`       // Total measured time
+	NsPerOp     int64         `json:"ns_per_op"`     // Average latency
+	AllocsPerOp int64         `json:"allocs_per_op"` // Heap allocations per operation
+	BytesPerOp  int64         `json:"bytes_per_op"`  // Heap bytes allocated per operation
+}
+
+// OpsPerSec returns the throughput of the workload.
+func (r Result) OpsPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.N) / r.Elapsed.Seconds()
+}
+
+// Run creates the workload tables, measures every workload and drops the tables.
+//
+// Each measurement starts from empty tables. Unless WithIterations is given, the
+// iteration count is calibrated like `go test -bench`: it grows until one run takes
+// at least the target duration.
+//
+// Note:
+//   - Allocations are process-wide; avoid concurrent work in the process while benchmarking
+//   - Use a dedicated database: the workload tables are dropped afterwards
+func Run(ctx context.Context, s *sqlc.Session, opts ...Option) (results []Result, err error) {
+	cfg := config{workloads: Workloads(), duration: time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	dialect := s.Dialect().Name()
+	ddl, err := createTables(dialect)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range ddl {
+		if _, err := s.Exec(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("bench: failed to create tables: %w", err)
+		}
+	}
+	defer func() {
+		err = errors.Join(err, dropTables(ctx, s))
+	}()
+
+	for _, w := range cfg.workloads {
+		r, err := measure(ctx, s, w, cfg)
+		if err != nil {
+			return results, fmt.Errorf("bench: workload %s: %w", w.Name, err)
+		}
+		r.Workload, r.Dialect = w.Name, dialect
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// measure runs w with a calibrated (or fixed) iteration count.
+func measure(ctx context.Context, s *sqlc.Session, w Workload, cfg config) (Result, error) {
+	if cfg.iterations > 0 {
+		return runN(ctx, s, w, cfg.iterations)
+	}
+	n := 1
+	for {
+		r, err := runN(ctx, s, w, n)
+		if err != nil || r.Elapsed >= cfg.duration || n >= 1e9 {
+			return r, err
+		}
+		n = predictN(n, r.Elapsed, cfg.duration)
+	}
+}
+
+// predictN estimates the iteration count reaching target, growing at most 100x per round.
+func predictN(n int, elapsed, target time.Duration) int {
+	next := n * 100
+	if elapsed > 0 {
+		// Overshoot by 20% so the next round most likely reaches the target
+		next = int(float64(n) * float64(target) / float64(elapsed) * 1.2)
+	}
+	return max(min(next, n*100, 1e9), n+1)
+}
+
+// runN prepares w for n iterations on empty tables and times them.
+func runN(ctx context.Context, s *sqlc.Session, w Workload, n int) (Result, error) {
+	if err := truncateTables(ctx, s); err != nil {
+		return Result{}, err
+	}
+	op, err := w.Prepare(ctx, s, n)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := range n {
+		if err := op(ctx, i); err != nil {
+			return Result{}, err
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return Result{
+		N:           n,
+		Elapsed:     elapsed,
+		NsPerOp:     elapsed.Nanoseconds() / int64(n),
+		AllocsPerOp: int64(after.Mallocs-before.Mallocs) / int64(n),
+		BytesPerOp:  int64(after.TotalAlloc-before.TotalAlloc) / int64(n),
+	}, nil
+}
+
+// truncateTables empties the workload tables.
+func truncateTables(ctx context.Context, s *sqlc.Session) error {
+	for _, table := range []string{postsTable, authorsTable} {
+		if _, err := s.Exec(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("bench: failed to clear %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// dropTables removes the workload tables.
+func dropTables(ctx context.Context, s *sqlc.Session) error {
+	for _, table := range []string{postsTable, authorsTable} {
+		if _, err := s.Exec(ctx, "DROP TABLE IF EXISTS "+table); err != nil {
+			return fmt.Errorf("bench: failed to drop %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// WriteReport writes results as an aligned table.
+func WriteReport(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "workload\tdialect\tn\tns/op\tops/sec\tallocs/op\tB/op\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.0f\t%d\t%d\t\n",
+			r.Workload, r.Dialect, r.N, r.NsPerOp, r.OpsPerSec(), r.AllocsPerOp, r.BytesPerOp)
+	}
+	return tw.Flush()
+}
+
+// Regression is a workload that got slower or allocates more than its baseline.
+type Regression struct {
+	Workload string
+	Metric   string  // "ns/op", "allocs/op" or "B/op"
+	Baseline int64   // Baseline value
+	Current  int64   // Current value
+	Change   float64 // Relative change, e.g. 0.25 for 25% worse
+}
+
+// String formats the regression for logs.
+func (r Regression) String() string {
+	return fmt.Sprintf("%s %s: %d -> %d (%+.1f%%)", r.Workload, r.Metric, r.Baseline, r.Current, r.Change*100)
+}
+
+// Compare reports metrics of current that are worse than baseline by more than
+// tolerance (0.10 = 10%). Workloads are matched by name and dialect; workloads
+// missing from either side are ignored.
+//
+// Note:
+//   - Latency varies between runs; compare results from the same machine and use a
+//     tolerance well above the run-to-run noise. Allocation counts are stable.
+func Compare(baseline, current []Result, tolerance float64) []Regression {
+	type key struct{ workload, dialect string }
+	base := make(map[key]Result, len(baseline))
+	for _, r := range baseline {
+		base[key{r.Workload, r.Dialect}] = r
+	}
+
+	var regressions []Regression
+	for _, cur := range current {
+		b, ok := base[key{cur.Workload, cur.Dialect}]
+		if !ok {
+			continue
+		}
+		metrics := []struct {
+			name      string
+			old, curr int64
+		}{
+			{"ns/op", b.NsPerOp, cur.NsPerOp},
+			{"allocs/op", b.AllocsPerOp, cur.AllocsPerOp},
+			{"B/op", b.BytesPerOp, cur.BytesPerOp},
+		}
+		for _, m := range metrics {
+			if m.old <= 0 {
+				continue
+			}
+			if change := float64(m.curr-m.old) / float64(m.old); change > tolerance {
+				regressions = append(regressions, Regression{
+					Workload: cur.Workload,
+					Metric:   m.name,
+					Baseline: m.old,
+					Current:  m.curr,
+					Change:   change,
+				})
+			}
+		}
+	}
+	return regressions
+}
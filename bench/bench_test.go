@@ -0,0 +1,113 @@
+package bench_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/bench"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newSession(t *testing.T) *sqlc.Session {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	db.SetMaxOpenConns(1) // Single in-memory database
+	t.Cleanup(func() { db.Close() })
+	return sqlc.NewSession(db, sqlc.SQLite)
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	session := newSession(t)
+
+	results, err := bench.Run(ctx, session, bench.WithIterations(20))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != len(bench.Workloads()) {
+		t.Fatalf("expected %d results, got %d", len(bench.Workloads()), len(results))
+	}
+	for _, r := range results {
+		if r.N != 20 || r.Dialect != "sqlite3" || r.NsPerOp <= 0 || r.OpsPerSec() <= 0 || r.AllocsPerOp <= 0 {
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bench.WriteReport(&buf, results); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Preload") || !strings.Contains(buf.String(), "ops/sec") {
+		t.Errorf("unexpected report:\n%s", buf.String())
+	}
+
+	// Tables are dropped after the run
+	var n int
+	if err := session.Get(ctx, &n, "SELECT COUNT(*) FROM sqlite_master WHERE name LIKE 'sqlc_bench_%'"); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("expected workload tables to be dropped, %d left", n)
+	}
+}
+
+func TestRunCalibrates(t *testing.T) {
+	ctx := context.Background()
+	var prepared []int
+	w := bench.Workload{
+		Name: "Noop",
+		Prepare: func(ctx context.Context, s *sqlc.Session, n int) (bench.Op, error) {
+			prepared = append(prepared, n)
+			return func(ctx context.Context, i int) error { return nil }, nil
+		},
+	}
+	results, err := bench.Run(ctx, newSession(t), bench.WithWorkloads(w), bench.WithDuration(1e6))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(prepared) < 2 || prepared[0] != 1 || results[0].N != prepared[len(prepared)-1] {
+		t.Errorf("unexpected calibration rounds %v, result %+v", prepared, results[0])
+	}
+}
+
+func TestRunWorkloadError(t *testing.T) {
+	boom := errors.New("boom")
+	w := bench.Workload{
+		Name: "Failing",
+		Prepare: func(ctx context.Context, s *sqlc.Session, n int) (bench.Op, error) {
+			return func(ctx context.Context, i int) error { return boom }, nil
+		},
+	}
+	_, err := bench.Run(context.Background(), newSession(t), bench.WithWorkloads(w), bench.WithIterations(1))
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "Failing") {
+		t.Errorf("expected wrapped workload error, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := []bench.Result{
+		{Workload: "FindOne", Dialect: "sqlite3", NsPerOp: 1000, AllocsPerOp: 50, BytesPerOp: 2000},
+		{Workload: "Create", Dialect: "sqlite3", NsPerOp: 1000, AllocsPerOp: 50, BytesPerOp: 2000},
+	}
+	current := []bench.Result{
+		{Workload: "FindOne", Dialect: "sqlite3", NsPerOp: 1050, AllocsPerOp: 60, BytesPerOp: 2000},
+		{Workload: "Create", Dialect: "mysql", NsPerOp: 5000, AllocsPerOp: 500, BytesPerOp: 9000}, // Different dialect
+		{Workload: "Delete", Dialect: "sqlite3", NsPerOp: 5000},                                   // No baseline
+	}
+	regressions := bench.Compare(baseline, current, 0.10)
+	if len(regressions) != 1 {
+		t.Fatalf("expected 1 regression, got %v", regressions)
+	}
+	want := "FindOne allocs/op: 50 -> 60 (+20.0%)"
+	if got := regressions[0].String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
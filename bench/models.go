@@ -0,0 +1,116 @@
+package bench
+
+import (
+	"fmt"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Table names used by the standard workloads; prefixed to avoid clashing with application tables.
+const (
+	authorsTable = "sqlc_bench_authors"
+	postsTable   = "sqlc_bench_posts"
+)
+
+// author is the parent model of the standard workloads.
+type author struct {
+	ID    int64   `db:"id"`
+	Name  string  `db:"name"`
+	Email string  `db:"email"`
+	Age   int     `db:"age"`
+	Posts []*post `db:"-"`
+}
+
+// post is the child model of the standard workloads.
+type post struct {
+	ID       int64  `db:"id"`
+	AuthorID int64  `db:"author_id"`
+	Title    string `db:"title"`
+	Body     string `db:"body"`
+}
+
+type authorSchema struct{}
+
+func (authorSchema) TableName() string { return authorsTable }
+func (authorSchema) SelectColumns() []string {
+	return []string{"id", "name", "email", "age"}
+}
+func (authorSchema) InsertRow(m *author) ([]string, []any) {
+	return []string{"name", "email", "age"}, []any{m.Name, m.Email, m.Age}
+}
+func (authorSchema) UpdateMap(m *author) map[string]any {
+	return map[string]any{"name": m.Name, "email": m.Email, "age": m.Age}
+}
+func (authorSchema) PK(m *author) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (authorSchema) SetPK(m *author, val int64) { m.ID = val }
+func (authorSchema) AutoIncrement() bool        { return true }
+func (authorSchema) SoftDeleteColumn() string   { return "" }
+func (authorSchema) SoftDeleteValue() any       { return nil }
+func (authorSchema) SetDeletedAt(m *author)     {}
+
+type postSchema struct{}
+
+func (postSchema) TableName() string { return postsTable }
+func (postSchema) SelectColumns() []string {
+	return []string{"id", "author_id", "title", "body"}
+}
+func (postSchema) InsertRow(m *post) ([]string, []any) {
+	return []string{"author_id", "title", "body"}, []any{m.AuthorID, m.Title, m.Body}
+}
+func (postSchema) UpdateMap(m *post) map[string]any {
+	return map[string]any{"author_id": m.AuthorID, "title": m.Title, "body": m.Body}
+}
+func (postSchema) PK(m *post) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (postSchema) SetPK(m *post, val int64) { m.ID = val }
+func (postSchema) AutoIncrement() bool      { return true }
+func (postSchema) SoftDeleteColumn() string { return "" }
+func (postSchema) SoftDeleteValue() any     { return nil }
+func (postSchema) SetDeletedAt(m *post)     {}
+
+func init() {
+	sqlc.RegisterSchema(authorSchema{})
+	sqlc.RegisterSchema(postSchema{})
+}
+
+// authorPosts is the HasMany relation used by the Preload workload.
+var authorPosts = sqlc.HasMany[author, post, int64](
+	clause.Column{Name: "author_id"},
+	clause.Column{Name: "id"},
+	func(a *author, posts []*post) { a.Posts = posts },
+	func(a *author) int64 { return a.ID },
+	func(p *post) int64 { return p.AuthorID },
+)
+
+// createTables returns the DDL of the workload tables for dialect.
+func createTables(dialect string) ([]string, error) {
+	var id, text string
+	switch dialect {
+	case "sqlite3":
+		id, text = "INTEGER PRIMARY KEY AUTOINCREMENT", "TEXT"
+	case "mysql":
+		id, text = "BIGINT PRIMARY KEY AUTO_INCREMENT", "VARCHAR(255)"
+	case "postgres":
+		id, text = "BIGSERIAL PRIMARY KEY", "TEXT"
+	default:
+		return nil, fmt.Errorf("bench: unsupported dialect %q", dialect)
+	}
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id %s, name %s NOT NULL, email %s NOT NULL, age INTEGER NOT NULL)",
+			authorsTable, id, text, text),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id %s, author_id BIGINT NOT NULL, title %s NOT NULL, body %s NOT NULL)",
+			postsTable, id, text, text),
+	}, nil
+}
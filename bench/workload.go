@@ -0,0 +1,170 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Op is one timed operation of a workload; i is the iteration index in [0, n).
+type Op func(ctx context.Context, i int) error
+
+// Workload is a named benchmark scenario.
+type Workload struct {
+	// Name identifies the workload in results and reports.
+	Name string
+
+	// Prepare seeds the data needed for n iterations (untimed) and returns the
+	// operation to time. The workload tables are empty when Prepare is called.
+	Prepare func(ctx context.Context, s *sqlc.Session, n int) (Op, error)
+}
+
+// Standard workload sizes
+const (
+	seedAuthors    = 100 // Rows read by FindOne, FindMany and Update
+	preloadAuthors = 20  // Parents loaded per Preload iteration
+	postsPerAuthor = 5   // Children per parent for Preload
+	batchSize      = 100 // Rows inserted per BatchCreate iteration
+)
+
+// Workloads returns the standard workloads:
+//   - Create: insert one row
+//   - BatchCreate: insert 100 rows in one statement
+//   - FindOne: select one row by primary key
+//   - FindMany: select 100 rows
+//   - Update: update one row by primary key
+//   - Preload: select 20 parents and preload their 100 children
+//   - Delete: delete one row by primary key
+func Workloads() []Workload {
+	return []Workload{
+		{Name: "Create", Prepare: prepareCreate},
+		{Name: "BatchCreate", Prepare: prepareBatchCreate},
+		{Name: "FindOne", Prepare: prepareFindOne},
+		{Name: "FindMany", Prepare: prepareFindMany},
+		{Name: "Update", Prepare: prepareUpdate},
+		{Name: "Preload", Prepare: preparePreload},
+		{Name: "Delete", Prepare: prepareDelete},
+	}
+}
+
+func newAuthor(i int) *author {
+	return &author{Name: fmt.Sprintf("author-%d", i), Email: fmt.Sprintf("author-%d@bench.test", i), Age: 20 + i%50}
+}
+
+func prepareCreate(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	return func(ctx context.Context, i int) error {
+		return repo.Create(ctx, newAuthor(i))
+	}, nil
+}
+
+func prepareBatchCreate(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	return func(ctx context.Context, i int) error {
+		batch := make([]*author, batchSize)
+		for j := range batch {
+			batch[j] = newAuthor(i*batchSize + j)
+		}
+		return repo.BatchCreate(ctx, batch)
+	}, nil
+}
+
+func prepareFindOne(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	authors, err := seed(ctx, s, seedAuthors, 0)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, i int) error {
+		_, err := repo.FindOne(ctx, authors[i%len(authors)].ID)
+		return err
+	}, nil
+}
+
+func prepareFindMany(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	if _, err := seed(ctx, s, seedAuthors, 0); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, i int) error {
+		authors, err := repo.Query().Limit(seedAuthors).Find(ctx)
+		if err == nil && len(authors) != seedAuthors {
+			err = fmt.Errorf("bench: expected %d rows, got %d", seedAuthors, len(authors))
+		}
+		return err
+	}, nil
+}
+
+func prepareUpdate(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	authors, err := seed(ctx, s, seedAuthors, 0)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, i int) error {
+		a := authors[i%len(authors)]
+		a.Age++
+		return repo.Update(ctx, a)
+	}, nil
+}
+
+func preparePreload(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	if _, err := seed(ctx, s, preloadAuthors, postsPerAuthor); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, i int) error {
+		authors, err := repo.Query().Limit(preloadAuthors).WithPreload(sqlc.Preload(authorPosts)).Find(ctx)
+		if err == nil && (len(authors) != preloadAuthors || len(authors[0].Posts) != postsPerAuthor) {
+			err = fmt.Errorf("bench: unexpected preload result")
+		}
+		return err
+	}, nil
+}
+
+func prepareDelete(ctx context.Context, s *sqlc.Session, n int) (Op, error) {
+	repo := sqlc.NewRepository[author](s)
+	authors, err := seed(ctx, s, n, 0)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, i int) error {
+		return repo.Delete(ctx, authors[i].ID)
+	}, nil
+}
+
+// seed inserts n authors with posts children each and returns the authors ordered by ID.
+func seed(ctx context.Context, s *sqlc.Session, n, posts int) ([]*author, error) {
+	authorRepo := sqlc.NewRepository[author](s)
+	postRepo := sqlc.NewRepository[post](s)
+
+	// BatchCreate does not return IDs; insert in batches and read them back
+	for start := 0; start < n; start += batchSize {
+		batch := make([]*author, min(batchSize, n-start))
+		for j := range batch {
+			batch[j] = newAuthor(start + j)
+		}
+		if err := authorRepo.BatchCreate(ctx, batch); err != nil {
+			return nil, fmt.Errorf("bench: failed to seed authors: %w", err)
+		}
+	}
+	authors, err := authorRepo.Query().OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).Find(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bench: failed to read seeded authors: %w", err)
+	}
+
+	if posts > 0 {
+		batch := make([]*post, 0, len(authors)*posts)
+		for _, a := range authors {
+			for j := range posts {
+				batch = append(batch, &post{AuthorID: a.ID, Title: fmt.Sprintf("post-%d", j), Body: "lorem ipsum"})
+			}
+		}
+		if err := postRepo.BatchCreate(ctx, batch); err != nil {
+			return nil, fmt.Errorf("bench: failed to seed posts: %w", err)
+		}
+	}
+	return authors, nil
+}
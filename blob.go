@@ -0,0 +1,320 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements streaming reads and writes of large binary columns.
+//
+// Files, images and exports stored in []byte columns can be far larger than what should be
+// held in memory at once. OpenBlob reads such a column in chunks (SELECT SUBSTRING(...))
+// behind a BlobReader, and WriteBlob fills it from an io.Reader by appending chunks.
+// On PostgreSQL the column may instead hold a large object OID (WithLargeObject), read
+// and written with lo_get / lo_put.
+//
+// Usage example:
+//
+//	// Stream a stored file to an HTTP response
+//	blob, err := fileRepo.OpenBlob(ctx, fileID, generated.File.Content)
+//	if err != nil {
+//	    return err
+//	}
+//	defer blob.Close()
+//	_, err = io.Copy(w, blob.Reader(ctx))
+//
+//	// Store an upload without buffering it
+//	n, err := fileRepo.WriteBlob(ctx, fileID, generated.File.Content, r.Body)
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/clause/funcs"
+)
+
+// defaultBlobChunkSize is the number of bytes read or written per statement
+const defaultBlobChunkSize = 1 << 20
+
+// blobConfig configures blob streaming
+type blobConfig struct {
+	chunkSize   int  // Bytes per statement
+	largeObject bool // Column holds a PostgreSQL large object OID
+}
+
+// BlobOption configures OpenBlob and WriteBlob.
+// Uses functional options pattern to provide flexible configuration.
+type BlobOption func(*blobConfig)
+
+// WithBlobChunkSize sets the number of bytes transferred per statement (default 1 MiB).
+func WithBlobChunkSize(n int) BlobOption {
+	return func(c *blobConfig) {
+		if n > 0 {
+			c.chunkSize = n
+		}
+	}
+}
+
+// WithLargeObject declares that the column holds the OID of a PostgreSQL large object
+// rather than the bytes themselves. Only supported on PostgreSQL.
+func WithLargeObject() BlobOption {
+	return func(c *blobConfig) {
+		c.largeObject = true
+	}
+}
+
+// blobOptions applies opts and validates them for the session dialect
+func (r *Repository[T]) blobOptions(opts []BlobOption) (blobConfig, error) {
+	cfg := blobConfig{chunkSize: defaultBlobChunkSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.largeObject && r.session.dialect.Name() != PostgreSQL.Name() {
+		return cfg, fmt.Errorf("sqlc: large objects are not supported by %s", r.session.dialect.Name())
+	}
+	return cfg, nil
+}
+
+// blobQuery returns the query selecting the record with the given id, honoring scopes
+func (r *Repository[T]) blobQuery(id any) *QueryBuilder[T] {
+	pk := r.schema.PK(nil).Column
+	return r.Query().Where(clause.Eq{Column: pk, Value: id}).Unordered()
+}
+
+// blobExists returns ErrNotFound unless the record with the given id exists.
+// Dry-run sessions return no rows, so they assume it does.
+func (r *Repository[T]) blobExists(ctx context.Context, id any) error {
+	if r.session.recorder != nil {
+		return nil
+	}
+	found, err := r.blobQuery(id).Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// OpenBlob opens a binary column of the record with the given id for streaming reads.
+// The column is fetched in chunks as the returned reader is consumed, so the value is
+// never fully materialized in memory.
+//
+// Parameters:
+//   - ctx: Context of the existence check; each read takes its own context
+//   - id: Primary key value
+//   - column: Binary column (e.g. generated.File.Content)
+//   - opts: Options such as WithBlobChunkSize or WithLargeObject
+//
+// Returns:
+//   - *BlobReader: Reader of the column's bytes; a NULL column reads as empty
+//   - error: ErrNotFound if no record matches the id and scopes, or query error
+//
+// Example:
+//
+//	blob, err := fileRepo.OpenBlob(ctx, id, generated.File.Content, sqlc.WithBlobChunkSize(256<<10))
+//	if err != nil {
+//	    return err
+//	}
+//	defer blob.Close()
+//	_, err = io.Copy(dst, blob.Reader(ctx))
+//
+// Note:
+//   - Each chunk is a separate query; open the blob in a transaction session for a
+//     consistent view of a column that may be rewritten concurrently
+func (r *Repository[T]) OpenBlob(ctx context.Context, id any, column clause.Columnar, opts ...BlobOption) (*BlobReader, error) {
+	cfg, err := r.blobOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.blobExists(ctx, id); err != nil {
+		return nil, err
+	}
+	query := r.blobQuery(id)
+	col := clause.Column{Name: column.ColumnName()}
+	fetch := func(ctx context.Context, offset int) ([]byte, error) {
+		return blobChunk(ctx, query, col, cfg, offset)
+	}
+	return &BlobReader{fetch: fetch, chunkSize: cfg.chunkSize}, nil
+}
+
+// blobChunk reads the chunk of a binary column starting at offset
+func blobChunk[T any](ctx context.Context, query *QueryBuilder[T], column clause.Column, cfg blobConfig, offset int) ([]byte, error) {
+	var chunk clause.Expression = funcs.Substring(column, offset+1, cfg.chunkSize)
+	if cfg.largeObject {
+		// lo_get offsets are zero-based
+		chunk = clause.Func{Name: "lo_get", Args: []any{column, offset, cfg.chunkSize}}
+	}
+	var rows []struct {
+		Chunk []byte `db:"chunk"`
+	}
+	if err := query.Select(clause.As(chunk, "chunk")).Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+	return rows[0].Chunk, nil
+}
+
+// BlobReader reads a binary column chunk by chunk, as returned by OpenBlob.
+// Every read takes the context of its chunk query: call ReadContext directly, or wrap
+// the reader with Reader(ctx) for io.Copy and other io.Reader consumers.
+type BlobReader struct {
+	fetch     func(ctx context.Context, offset int) ([]byte, error)
+	chunkSize int
+	offset    int    // Bytes fetched so far
+	buf       []byte // Fetched bytes not yet read
+	eof       bool   // The last chunk has been fetched
+	closed    bool
+}
+
+// ReadContext reads up to len(p) bytes of the column, fetching the next chunk with ctx
+// when the buffered bytes are exhausted. It returns io.EOF at the end of the value.
+func (b *BlobReader) ReadContext(ctx context.Context, p []byte) (int, error) {
+	if b.closed {
+		return 0, errors.New("sqlc: read from closed blob")
+	}
+	if len(b.buf) == 0 {
+		if b.eof {
+			return 0, io.EOF
+		}
+		chunk, err := b.fetch(ctx, b.offset)
+		if err != nil {
+			return 0, err
+		}
+		b.buf = chunk
+		b.offset += len(chunk)
+		b.eof = len(chunk) < b.chunkSize
+		if len(b.buf) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, b.buf)
+	b.buf = b.buf[n:]
+	return n, nil
+}
+
+// Reader returns an io.Reader whose reads call ReadContext with ctx.
+// It is meant to be created for a single copy and dropped with it, e.g.
+// io.Copy(w, blob.Reader(r.Context())); further reads may use another context.
+func (b *BlobReader) Reader(ctx context.Context) io.Reader {
+	return blobContextReader{blob: b, ctx: ctx}
+}
+
+// blobContextReader adapts a BlobReader to io.Reader for the duration of one call
+type blobContextReader struct {
+	blob *BlobReader
+	ctx  context.Context
+}
+
+func (r blobContextReader) Read(p []byte) (int, error) {
+	return r.blob.ReadContext(r.ctx, p)
+}
+
+// Close releases the buffered bytes; later reads fail.
+func (b *BlobReader) Close() error {
+	b.closed = true
+	b.buf = nil
+	return nil
+}
+
+// WriteBlob replaces a binary column of the record with the given id by the contents of src,
+// streamed in chunks so the value is never fully materialized in memory.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - id: Primary key value
+//   - column: Binary column (e.g. generated.File.Content)
+//   - src: Reader of the new value
+//   - opts: Options such as WithBlobChunkSize or WithLargeObject
+//
+// Returns:
+//   - int64: Number of bytes written
+//   - error: ErrNotFound if no record matches the id and scopes, or read/write error
+//
+// Example:
+//
+//	f, _ := os.Open("report.pdf")
+//	defer f.Close()
+//	n, err := fileRepo.WriteBlob(ctx, id, generated.File.Content, f)
+//
+// Note:
+//   - All chunks are written in one transaction (joining the session's transaction if any),
+//     so readers never observe a partially written value
+//   - The first chunk replaces the value; later chunks are appended (CONCAT / ||)
+//   - With WithLargeObject a new large object is created and its OID stored in the column;
+//     the previous large object is not unlinked
+//   - Does not trigger lifecycle hooks
+func (r *Repository[T]) WriteBlob(ctx context.Context, id any, column clause.Columnar, src io.Reader, opts ...BlobOption) (int64, error) {
+	cfg, err := r.blobOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+	col := clause.Column{Name: column.ColumnName()}
+
+	var written int64
+	err = r.session.Transaction(ctx, func(tx *Session) error {
+		repo := r.WithSession(tx)
+		if err := repo.blobExists(ctx, id); err != nil {
+			return err
+		}
+
+		var oid int64
+		buf := make([]byte, cfg.chunkSize)
+		for {
+			n, readErr := io.ReadFull(src, buf)
+			if n > 0 || written == 0 {
+				chunk := buf[:n]
+				if cfg.largeObject {
+					err = repo.writeLargeObjectChunk(ctx, &oid, written, chunk)
+				} else {
+					err = repo.writeBlobChunk(ctx, id, col, written, chunk)
+				}
+				if err != nil {
+					return err
+				}
+				written += int64(n)
+			}
+			if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+				break
+			}
+			if readErr != nil {
+				return readErr
+			}
+		}
+
+		if cfg.largeObject {
+			return repo.UpdateColumns(ctx, id, clause.Assignment{Column: col, Value: oid})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// writeBlobChunk stores chunk at offset of a binary column: the first chunk replaces the
+// value, later chunks are appended to it.
+func (r *Repository[T]) writeBlobChunk(ctx context.Context, id any, col clause.Column, offset int64, chunk []byte) error {
+	var value any = chunk
+	if offset > 0 {
+		var appended clause.Expression = funcs.Concat(col, chunk)
+		if r.session.dialect.Name() == SQLite.Name() {
+			// SQLite's || yields TEXT; keep the column a BLOB
+			appended = clause.Cast(appended, "blob")
+		}
+		value = clause.BindTypes(appended, typeNamer(r.session.dialect))
+	}
+	return r.UpdateColumns(ctx, id, clause.Assignment{Column: col, Value: value})
+}
+
+// writeLargeObjectChunk writes chunk at offset of a PostgreSQL large object,
+// creating the object (and setting *oid) with the first chunk.
+func (r *Repository[T]) writeLargeObjectChunk(ctx context.Context, oid *int64, offset int64, chunk []byte) error {
+	if offset == 0 {
+		return r.session.Get(ctx, oid, "SELECT lo_from_bytea(0, $1)", chunk)
+	}
+	_, err := r.session.Exec(ctx, "SELECT lo_put($1, $2, $3)", *oid, offset, chunk)
+	return err
+}
@@ -0,0 +1,207 @@
+package sqlc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/field"
+)
+
+// StoredFile keeps its content in a BLOB column
+type StoredFile struct {
+	ID      int64  `db:"id"`
+	Name    string `db:"name"`
+	Content []byte `db:"content"`
+}
+
+type StoredFileSchema struct{}
+
+func (StoredFileSchema) TableName() string       { return "stored_files" }
+func (StoredFileSchema) SelectColumns() []string { return []string{"id", "name", "content"} }
+func (StoredFileSchema) InsertRow(m *StoredFile) ([]string, []any) {
+	return []string{"name", "content"}, []any{m.Name, m.Content}
+}
+func (StoredFileSchema) UpdateMap(m *StoredFile) map[string]any {
+	return map[string]any{"name": m.Name, "content": m.Content}
+}
+func (StoredFileSchema) PK(m *StoredFile) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (StoredFileSchema) SetPK(m *StoredFile, val int64) { m.ID = val }
+func (StoredFileSchema) AutoIncrement() bool            { return true }
+func (StoredFileSchema) SoftDeleteColumn() string       { return "" }
+func (StoredFileSchema) SoftDeleteValue() any           { return nil }
+func (StoredFileSchema) SetDeletedAt(m *StoredFile)     {}
+
+func init() {
+	sqlc.RegisterSchema(StoredFileSchema{})
+}
+
+func TestBlobStreaming(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.Exec(`CREATE TABLE stored_files (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		content BLOB
+	)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	repo := sqlc.NewRepository[StoredFile](session)
+	file := &StoredFile{Name: "report.bin"}
+	if err := repo.Create(ctx, file); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	content := field.Bytes{}.WithColumn("content")
+
+	// Binary data including NUL bytes, not a multiple of any chunk size
+	data := make([]byte, 10_007)
+	rand.New(rand.NewSource(1)).Read(data)
+	data[0], data[500] = 0, 0
+
+	tests := []struct {
+		name      string
+		data      []byte
+		writeOpts []sqlc.BlobOption
+		readOpts  []sqlc.BlobOption
+	}{
+		{"Chunked", data, []sqlc.BlobOption{sqlc.WithBlobChunkSize(1000)}, []sqlc.BlobOption{sqlc.WithBlobChunkSize(333)}},
+		{"ExactChunks", data[:3000], []sqlc.BlobOption{sqlc.WithBlobChunkSize(1000)}, []sqlc.BlobOption{sqlc.WithBlobChunkSize(1000)}},
+		{"SingleChunk", data, nil, nil},
+		{"Empty", []byte{}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := repo.WriteBlob(ctx, file.ID, content, bytes.NewReader(tt.data), tt.writeOpts...)
+			if err != nil {
+				t.Fatalf("WriteBlob failed: %v", err)
+			}
+			if n != int64(len(tt.data)) {
+				t.Errorf("expected %d bytes written, got %d", len(tt.data), n)
+			}
+
+			blob, err := repo.OpenBlob(ctx, file.ID, content, tt.readOpts...)
+			if err != nil {
+				t.Fatalf("OpenBlob failed: %v", err)
+			}
+			defer blob.Close()
+			got, err := io.ReadAll(blob.Reader(ctx))
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.data) {
+				t.Errorf("blob mismatch: got %d bytes, want %d", len(got), len(tt.data))
+			}
+		})
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		if _, err := repo.OpenBlob(ctx, 999, content); !errors.Is(err, sqlc.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if _, err := repo.WriteBlob(ctx, 999, content, bytes.NewReader(data)); !errors.Is(err, sqlc.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ClosedReader", func(t *testing.T) {
+		blob, err := repo.OpenBlob(ctx, file.ID, content)
+		if err != nil {
+			t.Fatalf("OpenBlob failed: %v", err)
+		}
+		blob.Close()
+		if _, err := blob.ReadContext(ctx, make([]byte, 1)); err == nil {
+			t.Error("expected error reading closed blob")
+		}
+	})
+
+	t.Run("PerReadContext", func(t *testing.T) {
+		if _, err := repo.WriteBlob(ctx, file.ID, content, bytes.NewReader(data)); err != nil {
+			t.Fatalf("WriteBlob failed: %v", err)
+		}
+		blob, err := repo.OpenBlob(ctx, file.ID, content)
+		if err != nil {
+			t.Fatalf("OpenBlob failed: %v", err)
+		}
+		defer blob.Close()
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := blob.ReadContext(canceled, make([]byte, 1)); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+		got, err := io.ReadAll(blob.Reader(ctx))
+		if err != nil {
+			t.Fatalf("ReadAll failed: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("blob mismatch: got %d bytes, want %d", len(got), len(data))
+		}
+	})
+
+	t.Run("LargeObjectRequiresPostgres", func(t *testing.T) {
+		if _, err := repo.OpenBlob(ctx, file.ID, content, sqlc.WithLargeObject()); err == nil {
+			t.Error("expected error for large objects on SQLite")
+		}
+	})
+}
+
+func TestWriteBlobSQL(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		dialect sqlc.Dialect
+		want    []string
+	}{
+		{
+			name:    "PostgreSQL",
+			dialect: sqlc.PostgreSQL,
+			want: []string{
+				"BEGIN",
+				"UPDATE stored_files SET content = $1 WHERE id = $2",
+				"UPDATE stored_files SET content = (content || $1) WHERE id = $2",
+				"COMMIT",
+			},
+		},
+		{
+			name:    "MySQL",
+			dialect: sqlc.MySQL,
+			want: []string{
+				"BEGIN",
+				"UPDATE stored_files SET content = ? WHERE id = ?",
+				"UPDATE stored_files SET content = CONCAT(content, ?) WHERE id = ?",
+				"COMMIT",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dry := sqlc.NewSession(nil, tt.dialect).DryRun()
+			repo := sqlc.NewRepository[StoredFile](dry)
+			_, err := repo.WriteBlob(ctx, 1, field.Bytes{}.WithColumn("content"), bytes.NewReader([]byte("abcdef")), sqlc.WithBlobChunkSize(4))
+			if err != nil {
+				t.Fatalf("WriteBlob failed: %v", err)
+			}
+			var got []string
+			for _, stmt := range dry.Recorder().Statements() {
+				got = append(got, stmt.SQL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statements mismatch:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
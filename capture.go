@@ -0,0 +1,179 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements query capture: a ring buffer of the most recently executed statements.
+//
+// Verbose query logging is rarely enabled when an incident happens. A session created with
+// WithQueryCapture keeps the last N statements (SQL, arguments, duration, error) in memory
+// at the cost of a copy per statement, so a postmortem can reconstruct the exact statement
+// sequence that led to a failure: on demand via RecentQueries, or when a panic unwinds
+// through DumpOnPanic or Transaction.
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithQueryCapture(256))
+//
+//	// Dump the captured statements if the handler panics
+//	defer session.DumpOnPanic(os.Stderr)
+//
+//	// Or expose them on a debug endpoint
+//	for _, q := range session.RecentQueries(20) {
+//	    fmt.Fprintln(w, q)
+//	}
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CapturedQuery is a statement captured by a session created with WithQueryCapture.
+type CapturedQuery struct {
+	Start     time.Time     // Time the statement started
+	Operation string        // "exec", "select", "get", "query", "query_row", "begin", "commit" or "rollback"
+	Name      string        // Operation name of the context (WithOperationName), if any
+	SQL       string        // SQL text with dialect placeholders
+	Args      []any         // Bound arguments
+	Duration  time.Duration // Execution time (0 for query_row, which executes on Scan)
+	Err       error         // Execution error, if any
+}
+
+// String formats the query as a single log line.
+func (q CapturedQuery) String() string {
+	s := fmt.Sprintf("%s %s %s %s", q.Start.Format(time.RFC3339Nano), q.Operation, q.Duration, q.SQL)
+	if q.Name != "" {
+		s += " name=" + q.Name
+	}
+	if len(q.Args) > 0 {
+		s += fmt.Sprintf(" args=%v", q.Args)
+	}
+	if q.Err != nil {
+		s += " error=" + q.Err.Error()
+	}
+	return s
+}
+
+// queryRing is a fixed-size ring buffer of captured queries, shared by a session
+// and its transaction sessions. It is safe for concurrent use.
+type queryRing struct {
+	mu      sync.Mutex
+	entries []CapturedQuery
+	next    int  // Index of the next write
+	full    bool // The buffer has wrapped around
+}
+
+func (r *queryRing) add(q CapturedQuery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = q
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// recent returns up to n of the newest entries, oldest first (all if n <= 0).
+func (r *queryRing) recent(n int) []CapturedQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []CapturedQuery
+	if r.full {
+		all = append(slices.Clone(r.entries[r.next:]), r.entries[:r.next]...)
+	} else {
+		all = slices.Clone(r.entries[:r.next])
+	}
+	if n > 0 && n < len(all) {
+		all = all[len(all)-n:]
+	}
+	return all
+}
+
+// WithQueryCapture keeps the last size executed statements of the session, including its
+// transaction sessions, for RecentQueries, DumpOnPanic and PanicError.Queries.
+//
+// Parameters:
+//   - size: Number of statements kept; values <= 0 disable capture
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.MySQL, sqlc.WithQueryCapture(500))
+//
+// Note:
+//   - Arguments are kept as passed, so captured queries may contain sensitive values;
+//     treat them like logs
+//   - Statements rejected by guard rules are captured with their error
+func WithQueryCapture(size int) SessionOption {
+	return func(s *Session) {
+		if size <= 0 {
+			s.captured = nil
+			return
+		}
+		s.captured = &queryRing{entries: make([]CapturedQuery, size)}
+	}
+}
+
+// capture records a statement if query capture is enabled.
+func (s *Session) capture(ctx context.Context, operation, query string, args []any, start time.Time, duration time.Duration, err error) {
+	if s.captured == nil {
+		return
+	}
+	s.captured.add(CapturedQuery{
+		Start:     start,
+		Operation: operation,
+		Name:      OperationName(ctx),
+		SQL:       query,
+		Args:      slices.Clone(args),
+		Duration:  duration,
+		Err:       err,
+	})
+}
+
+// RecentQueries returns up to n of the most recently captured statements, oldest first.
+// If n <= 0, all captured statements are returned.
+//
+// Returns:
+//   - []CapturedQuery: Captured statements, or nil if the session was not created with WithQueryCapture
+//
+// Example:
+//
+//	if err := checkout(ctx, session); err != nil {
+//	    for _, q := range session.RecentQueries(10) {
+//	        slog.Error("recent query", "query", q.String())
+//	    }
+//	}
+func (s *Session) RecentQueries(n int) []CapturedQuery {
+	if s.captured == nil {
+		return nil
+	}
+	return s.captured.recent(n)
+}
+
+// DumpRecentQueries writes all captured statements to w, one per line, oldest first.
+// It writes nothing if the session was not created with WithQueryCapture.
+func (s *Session) DumpRecentQueries(w io.Writer) error {
+	for _, q := range s.RecentQueries(0) {
+		if _, err := fmt.Fprintln(w, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpOnPanic writes the captured statements to w if the goroutine is panicking,
+// then re-raises the panic. It must be called directly by defer.
+//
+// Example:
+//
+//	func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+//	    defer h.session.DumpOnPanic(os.Stderr)
+//	    // ...
+//	}
+func (s *Session) DumpOnPanic(w io.Writer) {
+	if p := recover(); p != nil {
+		fmt.Fprintf(w, "sqlc: panic: %v; recent queries:\n", p)
+		_ = s.DumpRecentQueries(w)
+		panic(p)
+	}
+}
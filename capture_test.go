@@ -0,0 +1,132 @@
+package sqlc_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestQueryCapture(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithQueryCapture(4), sqlc.WithPanicRecovery())
+
+	sqls := func(qs []sqlc.CapturedQuery) []string {
+		var out []string
+		for _, q := range qs {
+			out = append(out, q.SQL)
+		}
+		return out
+	}
+
+	t.Run("RingBuffer", func(t *testing.T) {
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			if _, err := session.Exec(ctx, "INSERT INTO users (username) VALUES (?)", name); err != nil {
+				t.Fatalf("Exec failed: %v", err)
+			}
+		}
+		var n int
+		if err := session.Get(sqlc.WithOperationName(ctx, "CountUsers"), &n, "SELECT COUNT(*) FROM users"); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+
+		all := session.RecentQueries(0)
+		if len(all) != 4 {
+			t.Fatalf("expected 4 captured queries, got %d", len(all))
+		}
+		// Oldest first: the first two inserts were evicted
+		if !reflect.DeepEqual(all[0].Args, []any{"c"}) || !reflect.DeepEqual(all[2].Args, []any{"e"}) {
+			t.Errorf("unexpected order: %v", all)
+		}
+		last := all[3]
+		if last.Operation != "get" || last.SQL != "SELECT COUNT(*) FROM users" || last.Name != "CountUsers" || last.Duration <= 0 {
+			t.Errorf("unexpected last query: %+v", last)
+		}
+
+		recent := session.RecentQueries(2)
+		if len(recent) != 2 || recent[1].SQL != last.SQL {
+			t.Errorf("expected the 2 newest queries, got %v", sqls(recent))
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := session.Exec(ctx, "INSERT INTO missing (id) VALUES (1)")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		last := session.RecentQueries(1)[0]
+		if last.Err == nil || !strings.Contains(last.String(), "error=") {
+			t.Errorf("expected captured error, got %q", last.String())
+		}
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			_, err := tx.Exec(ctx, "UPDATE users SET username = ? WHERE id = ?", "z", 1)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		want := []string{"INSERT INTO missing (id) VALUES (1)", "BEGIN", "UPDATE users SET username = ? WHERE id = ?", "COMMIT"}
+		if got := sqls(session.RecentQueries(0)); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("PanicError", func(t *testing.T) {
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			if _, err := tx.Exec(ctx, "DELETE FROM users WHERE id = ?", 2); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+		var pe *sqlc.PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("expected *PanicError, got %v", err)
+		}
+		want := []string{"COMMIT", "BEGIN", "DELETE FROM users WHERE id = ?", "ROLLBACK"}
+		if got := sqls(pe.Queries); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("DumpOnPanic", func(t *testing.T) {
+		var buf bytes.Buffer
+		func() {
+			defer func() { _ = recover() }()
+			defer session.DumpOnPanic(&buf)
+			panic("boom")
+		}()
+		out := buf.String()
+		if !strings.HasPrefix(out, "sqlc: panic: boom") || !strings.Contains(out, "DELETE FROM users WHERE id = ? args=[2]") {
+			t.Errorf("unexpected dump:\n%s", out)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		plain := sqlc.NewSession(db, &sqlc.SQLiteDialect{})
+		if _, err := plain.Exec(ctx, "DELETE FROM users WHERE id = ?", 3); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		if qs := plain.RecentQueries(0); qs != nil {
+			t.Errorf("expected nil without capture, got %v", qs)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements cascade creation of associated models.
+//
+// Create with WithAssociations also saves the models set on the relation fields of the
+// new model, in the same transaction: after the parent is inserted and its primary key
+// backfilled, each populated relation is linked through Association().Append, which
+// creates the new related models and sets their foreign keys (or join rows).
+//
+// Usage example:
+//
+//	user := &models.User{
+//	    Name:  "alice",
+//	    Posts: []*models.Post{{Title: "Hello"}, {Title: "World"}},
+//	}
+//	// INSERT INTO users ...; INSERT INTO posts (user_id, ...) VALUES (<user.ID>, ...) x2
+//	err := userRepo.Create(ctx, user, sqlc.WithAssociations("Posts"))
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// CreateOption configures Create.
+// Uses functional options pattern to provide flexible configuration.
+type CreateOption func(*createConfig)
+
+// createConfig is the configuration of Create
+type createConfig struct {
+	associations    []string // Relation names to save (nil = none)
+	allAssociations bool     // Save every registered relation
+}
+
+// WithAssociations makes Create save the related models set on the given relation
+// fields, in the same transaction as the parent. Relations are named as registered with
+// RegisterRelation (the relation field, e.g. "Posts"); without names, every registered
+// relation of the model is saved.
+//
+// Parameters:
+//   - names: Relation names; none for all registered relations
+//
+// Example:
+//
+//	// Creates the user, then its posts with posts.user_id = user.ID
+//	err := userRepo.Create(ctx, user, sqlc.WithAssociations("Posts"))
+//
+//	// Every populated relation field
+//	err = userRepo.Create(ctx, user, sqlc.WithAssociations())
+//
+// Note:
+//   - Nil or empty relation fields are skipped
+//   - Related models with a zero primary key are created; the others are linked
+//     (their foreign keys updated, or join rows inserted for ManyToMany)
+//   - BelongsTo owners are created after the parent, whose foreign key is then updated
+//   - Only one level is saved: relations of the related models are not
+//   - An unknown name fails Create before anything is written
+func WithAssociations(names ...string) CreateOption {
+	return func(c *createConfig) {
+		c.associations = append(c.associations, names...)
+		c.allAssociations = c.allAssociations || len(names) == 0
+	}
+}
+
+// createWithAssociations inserts model and saves the related models of the relations
+// selected by cfg, in one transaction.
+func (r *Repository[T]) createWithAssociations(ctx context.Context, model *T, cfg createConfig) error {
+	named, _ := relations[reflect.TypeFor[T]()].(map[string]namedRelation[T])
+	names := cfg.associations
+	if cfg.allAssociations {
+		names = make([]string, 0, len(named))
+		for name := range named {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+	}
+	rels := make([]namedRelation[T], 0, len(names))
+	for _, name := range names {
+		rel, ok := named[name]
+		if !ok {
+			return fmt.Errorf("sqlc: unknown relation %q of %v", name, reflect.TypeFor[T]())
+		}
+		rels = append(rels, rel)
+	}
+
+	return r.session.Transaction(ctx, func(tx *Session) error {
+		repo := r.WithSession(tx)
+		if err := repo.Create(ctx, model); err != nil {
+			return err
+		}
+		for _, rel := range rels {
+			if err := rel.associate(ctx, repo, model); err != nil {
+				return fmt.Errorf("sqlc: failed to create %s: %w", rel.info.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// relationField returns the related models set on the field of parent named name:
+// a *C field yields one model, a []*C field its elements. Nil fields yield none.
+func relationField[C any](parent any, name string) ([]*C, error) {
+	v := reflect.ValueOf(parent).Elem()
+	f := v.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return nil, fmt.Errorf("sqlc: %v has no exported relation field %s", v.Type(), name)
+	}
+	switch children := f.Interface().(type) {
+	case *C:
+		if children == nil {
+			return nil, nil
+		}
+		return []*C{children}, nil
+	case []*C:
+		return children, nil
+	default:
+		return nil, fmt.Errorf("sqlc: relation field %v.%s is %v, want *%v or []*%v",
+			v.Type(), name, f.Type(), reflect.TypeFor[C](), reflect.TypeFor[C]())
+	}
+}
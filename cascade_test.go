@@ -0,0 +1,106 @@
+package sqlc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestCreateWithAssociations(t *testing.T) {
+	ctx := context.Background()
+	newMember := func(name string) *Member {
+		return &Member{Name: name, Email: name + "@test.com", CreatedAt: time.Now()}
+	}
+	countMembers := func(t *testing.T, session *sqlc.Session, deptID int64) int64 {
+		t.Helper()
+		n, err := sqlc.NewRepository[Member](session).Query().
+			Where(clause.Eq{Column: clause.Column{Name: "department_id"}, Value: deptID}).
+			Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		return n
+	}
+
+	t.Run("HasMany", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+
+		eng := &Department{Name: "Engineering", Members: []*Member{newMember("alice"), newMember("bob")}}
+		if err := sqlc.NewRepository[Department](session).Create(ctx, eng, sqlc.WithAssociations("Members")); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		for _, m := range eng.Members {
+			if m.ID == 0 || m.DepartmentID != int(eng.ID) {
+				t.Errorf("expected created member of department %d, got %+v", eng.ID, m)
+			}
+		}
+		if n := countMembers(t, session, eng.ID); n != 2 {
+			t.Errorf("expected 2 members in the database, got %d", n)
+		}
+	})
+
+	t.Run("BelongsToAll", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+
+		alice := newMember("alice")
+		alice.Department = &Department{Name: "Sales"}
+		if err := sqlc.NewRepository[Member](session).Create(ctx, alice, sqlc.WithAssociations()); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if alice.Department.ID == 0 || alice.DepartmentID != int(alice.Department.ID) {
+			t.Errorf("expected alice in the created department, got %+v", alice)
+		}
+		if n := countMembers(t, session, alice.Department.ID); n != 1 {
+			t.Errorf("expected the department link saved, got %d members", n)
+		}
+	})
+
+	t.Run("WithoutOption", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+
+		eng := &Department{Name: "Engineering", Members: []*Member{newMember("alice")}}
+		if err := sqlc.NewRepository[Department](session).Create(ctx, eng); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if eng.Members[0].ID != 0 || countMembers(t, session, eng.ID) != 0 {
+			t.Errorf("expected members not saved, got %+v", eng.Members[0])
+		}
+	})
+
+	t.Run("UnknownRelation", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+
+		deptRepo := sqlc.NewRepository[Department](session)
+		if err := deptRepo.Create(ctx, &Department{Name: "Engineering"}, sqlc.WithAssociations("Projects")); err == nil {
+			t.Fatal("expected error for an unknown relation")
+		}
+		if n, _ := deptRepo.Query().Count(ctx); n != 0 {
+			t.Errorf("expected nothing written, got %d departments", n)
+		}
+	})
+
+	t.Run("RollbackOnChildFailure", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+
+		// Duplicate email: the second member violates the unique constraint
+		eng := &Department{Name: "Engineering", Members: []*Member{newMember("alice"), newMember("alice")}}
+		deptRepo := sqlc.NewRepository[Department](session)
+		if err := deptRepo.Create(ctx, eng, sqlc.WithAssociations("Members")); err == nil {
+			t.Fatal("expected error creating a duplicate member")
+		}
+		if n, _ := deptRepo.Query().Count(ctx); n != 0 {
+			t.Errorf("expected the department rolled back, got %d departments", n)
+		}
+		if n, _ := sqlc.NewRepository[Member](session).Query().Count(ctx); n != 0 {
+			t.Errorf("expected the members rolled back, got %d members", n)
+		}
+	})
+}
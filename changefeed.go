@@ -0,0 +1,404 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements change feeds: trigger-based change data capture without external infrastructure.
+//
+// A ChangeFeed installs triggers on a model's table that append a row to a change
+// table for every INSERT, UPDATE and DELETE. The feed polls the change table and
+// delivers typed Change events, so small applications can build projections, caches
+// or search indexes without running a CDC pipeline.
+//
+// Change table layout (shared by all feeds of a database):
+//
+//	seq        -- auto-increment position, used to resume a stream
+//	table_name -- table of the changed row
+//	op         -- "create", "update" or "delete"
+//	pk         -- primary key of the changed row, as text
+//	changed_at -- time of the change
+//
+// Usage example:
+//
+//	feed := sqlc.NewChangeFeed[models.Order](session, sqlc.WithChangePayload())
+//	if err := feed.Install(ctx); err != nil {
+//	    return err
+//	}
+//
+//	changes, errc := feed.Stream(ctx, lastSeq)
+//	for change := range changes {
+//	    project(change.Op, change.Model)
+//	    lastSeq = change.Seq
+//	}
+//	if err := <-errc; err != nil {
+//	    return err
+//	}
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// ChangeOp is the kind of a captured change.
+type ChangeOp string
+
+const (
+	ChangeCreate ChangeOp = "create" // Row inserted
+	ChangeUpdate ChangeOp = "update" // Row updated (including soft deletes and restores)
+	ChangeDelete ChangeOp = "delete" // Row deleted
+)
+
+// DefaultChangeTable is the change table used when WithChangeTable is not set.
+const DefaultChangeTable = "sqlc_changes"
+
+// Change is a captured change of a row of model T.
+type Change[T any] struct {
+	Seq   int64     // Position in the change table; resume a stream after it
+	Op    ChangeOp  // Kind of change
+	PK    string    // Primary key of the changed row, as text
+	At    time.Time // Time of the change
+	Model *T        // Current row with WithChangePayload; nil for deletes and rows deleted since
+}
+
+// changeRow is a row of the change table.
+type changeRow struct {
+	Seq int64     `db:"seq"`
+	Op  string    `db:"op"`
+	PK  string    `db:"pk"`
+	At  time.Time `db:"changed_at"`
+}
+
+// ChangeFeed configuration
+type changeFeedConfig struct {
+	table    string        // Change table name
+	interval time.Duration // Poll interval of Stream when no changes are pending
+	batch    int           // Maximum changes read per poll
+	payload  bool          // Whether to load the current rows
+}
+
+// ChangeFeedOption configures a ChangeFeed.
+type ChangeFeedOption func(*changeFeedConfig)
+
+// WithChangeTable sets the change table name (default DefaultChangeTable).
+func WithChangeTable(table string) ChangeFeedOption {
+	return func(c *changeFeedConfig) {
+		c.table = table
+	}
+}
+
+// WithPollInterval sets how often Stream polls while no changes are pending (default 1s).
+// Non-positive durations are ignored.
+func WithPollInterval(d time.Duration) ChangeFeedOption {
+	return func(c *changeFeedConfig) {
+		if d > 0 {
+			c.interval = d
+		}
+	}
+}
+
+// WithChangeBatchSize sets the maximum number of changes read per poll (default 100).
+// Non-positive sizes are ignored.
+func WithChangeBatchSize(n int) ChangeFeedOption {
+	return func(c *changeFeedConfig) {
+		if n > 0 {
+			c.batch = n
+		}
+	}
+}
+
+// WithChangePayload loads the current row of each create and update into Change.Model.
+// Rows are loaded with one query per poll; soft-deleted rows are included.
+func WithChangePayload() ChangeFeedOption {
+	return func(c *changeFeedConfig) {
+		c.payload = true
+	}
+}
+
+// ChangeFeed captures the changes of model T's table. It is safe for concurrent use.
+type ChangeFeed[T any] struct {
+	session *Session
+	schema  Schema[T]
+	cfg     changeFeedConfig
+}
+
+// NewChangeFeed creates a change feed for model T.
+// Call Install once (e.g. in a migration or at startup) before reading changes.
+//
+// Parameters:
+//   - session: Database session (MySQL, PostgreSQL 11+ or SQLite)
+//   - opts: Options (WithChangeTable, WithPollInterval, WithChangeBatchSize, WithChangePayload)
+//
+// Example:
+//
+//	feed := sqlc.NewChangeFeed[models.User](session,
+//	    sqlc.WithPollInterval(500*time.Millisecond),
+//	    sqlc.WithChangePayload(),
+//	)
+func NewChangeFeed[T any](session *Session, opts ...ChangeFeedOption) *ChangeFeed[T] {
+	cfg := changeFeedConfig{table: DefaultChangeTable, interval: time.Second, batch: 100}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &ChangeFeed[T]{session: session, schema: LoadSchema[T](), cfg: cfg}
+}
+
+// Install creates the change table (if missing) and the capture triggers of T's table.
+// It is idempotent: existing triggers are replaced.
+//
+// Note:
+//   - Changes made before Install are not captured
+//   - Triggers fire for every writer of the table, not only for sqlc sessions
+func (f *ChangeFeed[T]) Install(ctx context.Context) error {
+	stmts, err := f.installSQL()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		if _, err := f.session.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("sqlc: failed to install change feed: %w", err)
+		}
+	}
+	return nil
+}
+
+// changeTableDDL creates the change table.
+// Arguments: table name, seq column definition, timestamp type, extra table definitions.
+const changeTableDDL = `CREATE TABLE IF NOT EXISTS %s (
+	seq %s,
+	table_name VARCHAR(255) NOT NULL,
+	op VARCHAR(16) NOT NULL,
+	pk VARCHAR(255) NOT NULL,
+	changed_at %s NOT NULL DEFAULT CURRENT_TIMESTAMP%s
+)`
+
+// changeTriggers lists the captured events and the trigger row holding the primary key.
+var changeTriggers = []struct {
+	op    ChangeOp
+	event string // SQL trigger event
+	row   string // NEW or OLD
+}{
+	{ChangeCreate, "INSERT", "NEW"},
+	{ChangeUpdate, "UPDATE", "NEW"},
+	{ChangeDelete, "DELETE", "OLD"},
+}
+
+// installSQL returns the statements creating the change table and triggers for the session dialect.
+func (f *ChangeFeed[T]) installSQL() ([]string, error) {
+	table := f.schema.TableName()
+	pk := f.schema.PK(nil).Column.Name
+	name := changeTriggerName(table)
+	index := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_table_seq ON %s (table_name, seq)", strings.ReplaceAll(f.cfg.table, ".", "_"), f.cfg.table)
+	insert := func(op ChangeOp, pkExpr string) string {
+		return fmt.Sprintf("INSERT INTO %s (table_name, op, pk) VALUES ('%s', '%s', %s)", f.cfg.table, table, op, pkExpr)
+	}
+
+	var stmts []string
+	switch dialect := f.session.dialect.Name(); dialect {
+	case SQLite.Name():
+		stmts = append(stmts, fmt.Sprintf(changeTableDDL, f.cfg.table, "INTEGER PRIMARY KEY AUTOINCREMENT", "DATETIME", ""), index)
+		for _, t := range changeTriggers {
+			stmts = append(stmts,
+				fmt.Sprintf("DROP TRIGGER IF EXISTS %s_%s", name, t.op),
+				fmt.Sprintf("CREATE TRIGGER %s_%s AFTER %s ON %s BEGIN %s; END", name, t.op, t.event, table, insert(t.op, t.row+"."+pk)),
+			)
+		}
+	case MySQL.Name():
+		stmts = append(stmts, fmt.Sprintf(changeTableDDL, f.cfg.table, "BIGINT AUTO_INCREMENT PRIMARY KEY", "TIMESTAMP", ",\n\tINDEX (table_name, seq)"))
+		for _, t := range changeTriggers {
+			stmts = append(stmts,
+				fmt.Sprintf("DROP TRIGGER IF EXISTS %s_%s", name, t.op),
+				fmt.Sprintf("CREATE TRIGGER %s_%s AFTER %s ON %s FOR EACH ROW %s", name, t.op, t.event, table, insert(t.op, t.row+"."+pk)),
+			)
+		}
+	case PostgreSQL.Name():
+		var body strings.Builder
+		for i, t := range changeTriggers {
+			keyword := "ELSIF"
+			if i == 0 {
+				keyword = "IF"
+			}
+			fmt.Fprintf(&body, "\t%s TG_OP = '%s' THEN\n\t\t%s;\n", keyword, t.event, insert(t.op, t.row+"."+pk+"::text"))
+		}
+		stmts = append(stmts,
+			fmt.Sprintf(changeTableDDL, f.cfg.table, "BIGSERIAL PRIMARY KEY", "TIMESTAMPTZ", ""),
+			index,
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$\nBEGIN\n%s\tEND IF;\n\tRETURN NULL;\nEND\n$$ LANGUAGE plpgsql", name, body.String()),
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", name, table),
+			fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION %s()", name, table, name),
+		)
+	default:
+		return nil, fmt.Errorf("sqlc: change feeds are not supported by dialect %s", dialect)
+	}
+	return stmts, nil
+}
+
+// changeTriggerName returns the trigger (and PostgreSQL function) name prefix for table.
+func changeTriggerName(table string) string {
+	return "sqlc_capture_" + strings.Map(func(r rune) rune {
+		if r == '.' || r == '"' || r == '`' {
+			return '_'
+		}
+		return r
+	}, table)
+}
+
+// Poll reads up to the batch size of changes after sequence after, oldest first.
+// It returns an empty slice when no changes are pending.
+//
+// Example:
+//
+//	changes, err := feed.Poll(ctx, checkpoint)
+//	for _, c := range changes {
+//	    apply(c)
+//	    checkpoint = c.Seq
+//	}
+func (f *ChangeFeed[T]) Poll(ctx context.Context, after int64) ([]Change[T], error) {
+	query, args, err := sq.Select("seq", "op", "pk", "changed_at").
+		From(f.cfg.table).
+		Where(sq.Eq{"table_name": f.schema.TableName()}).
+		Where(sq.Gt{"seq": after}).
+		OrderBy("seq").
+		Limit(uint64(f.cfg.batch)).
+		PlaceholderFormat(f.session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	var rows []changeRow
+	if err := f.session.Select(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("sqlc: failed to read changes: %w", err)
+	}
+
+	changes := make([]Change[T], len(rows))
+	for i, row := range rows {
+		changes[i] = Change[T]{Seq: row.Seq, Op: ChangeOp(row.Op), PK: row.PK, At: row.At}
+	}
+	if f.cfg.payload {
+		if err := f.loadPayload(ctx, changes); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// loadPayload loads the current rows of creates and updates with a single query.
+func (f *ChangeFeed[T]) loadPayload(ctx context.Context, changes []Change[T]) error {
+	var pks []any
+	for _, c := range changes {
+		if c.Op != ChangeDelete {
+			pks = append(pks, c.PK)
+		}
+	}
+	if len(pks) == 0 {
+		return nil
+	}
+
+	pk := f.schema.PK(nil).Column
+	models, err := Query[T](f.session).WithTrashed().Where(clause.IN{Column: pk, Values: pks}).Find(ctx)
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to load change payload: %w", err)
+	}
+	byPK := make(map[string]*T, len(models))
+	for _, m := range models {
+		byPK[fmt.Sprint(f.schema.PK(m).Value)] = m
+	}
+	for i := range changes {
+		if changes[i].Op != ChangeDelete {
+			changes[i].Model = byPK[changes[i].PK]
+		}
+	}
+	return nil
+}
+
+// Stream delivers the changes after sequence after on a channel until ctx is canceled
+// or polling fails. Both channels are closed when the stream ends; errc then yields
+// the polling error, or nil if the stream ended by cancellation.
+//
+// Parameters:
+//   - ctx: Context; cancel it to stop the stream
+//   - after: Sequence to resume after (0 for all captured changes)
+//
+// Returns:
+//   - <-chan Change[T]: Changes in sequence order
+//   - <-chan error: Terminal error of the stream
+//
+// Example:
+//
+//	changes, errc := feed.Stream(ctx, checkpoint)
+//	for c := range changes {
+//	    if err := apply(c); err != nil {
+//	        cancel()
+//	        break
+//	    }
+//	    checkpoint = c.Seq // persist to resume after restarts
+//	}
+//	if err := <-errc; err != nil {
+//	    log.Printf("change feed stopped: %v", err)
+//	}
+//
+// Note:
+//   - Delivery is at-least-once across restarts: persist Seq after applying a change
+//   - Sequences are assigned when a write happens, not when it commits; a long transaction
+//     can commit a change below an already delivered sequence, which is then skipped
+func (f *ChangeFeed[T]) Stream(ctx context.Context, after int64) (<-chan Change[T], <-chan error) {
+	out := make(chan Change[T])
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(out)
+
+		ticker := time.NewTicker(f.cfg.interval)
+		defer ticker.Stop()
+
+		for {
+			changes, err := f.Poll(ctx, after)
+			if err != nil {
+				if ctx.Err() == nil {
+					errc <- err
+				}
+				return
+			}
+			for _, c := range changes {
+				select {
+				case out <- c:
+					after = c.Seq
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// A full batch means more changes are pending
+			if len(changes) == f.cfg.batch {
+				continue
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, errc
+}
+
+// Trim deletes the captured changes of T's table up to and including sequence through,
+// e.g. after all consumers have applied them.
+func (f *ChangeFeed[T]) Trim(ctx context.Context, through int64) error {
+	query, args, err := sq.Delete(f.cfg.table).
+		Where(sq.Eq{"table_name": f.schema.TableName()}).
+		Where(sq.LtOrEq{"seq": through}).
+		PlaceholderFormat(f.session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+	if _, err := f.session.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlc: failed to trim changes: %w", err)
+	}
+	return nil
+}
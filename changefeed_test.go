@@ -0,0 +1,175 @@
+package sqlc_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestChangeFeed(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // Single in-memory database for the stream goroutine
+	ctx := context.Background()
+
+	feed := sqlc.NewChangeFeed[Member](session, sqlc.WithChangePayload())
+	if err := feed.Install(ctx); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if err := feed.Install(ctx); err != nil {
+		t.Fatalf("Install is not idempotent: %v", err)
+	}
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	a := &Member{Name: "a", Email: "a@test.com", CreatedAt: time.Now()}
+	b := &Member{Name: "b", Email: "b@test.com", CreatedAt: time.Now()}
+	for _, m := range []*Member{a, b} {
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	name := field.String{}.WithColumn("name")
+	if err := memberRepo.UpdateColumns(ctx, a.ID, name.Set("a2")); err != nil {
+		t.Fatalf("UpdateColumns failed: %v", err)
+	}
+	if err := memberRepo.Delete(ctx, b.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	changes, err := feed.Poll(ctx, 0)
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	want := []struct {
+		op    sqlc.ChangeOp
+		id    int64
+		model string // Name of the loaded payload, "" for none
+	}{
+		{sqlc.ChangeCreate, a.ID, "a2"},
+		{sqlc.ChangeCreate, b.ID, ""}, // deleted since
+		{sqlc.ChangeUpdate, a.ID, "a2"},
+		{sqlc.ChangeDelete, b.ID, ""},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Op != w.op || c.PK != fmtID(w.id) {
+			t.Errorf("change %d: expected %s of %d, got %s of %s", i, w.op, w.id, c.Op, c.PK)
+		}
+		switch {
+		case w.model == "" && c.Model != nil:
+			t.Errorf("change %d: expected no payload, got %+v", i, c.Model)
+		case w.model != "" && (c.Model == nil || c.Model.Name != w.model):
+			t.Errorf("change %d: expected payload %q, got %+v", i, w.model, c.Model)
+		}
+	}
+
+	t.Run("Resume", func(t *testing.T) {
+		rest, err := feed.Poll(ctx, changes[1].Seq)
+		if err != nil {
+			t.Fatalf("Poll failed: %v", err)
+		}
+		if len(rest) != 2 || rest[0].Op != sqlc.ChangeUpdate {
+			t.Errorf("expected the last 2 changes, got %+v", rest)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		last := changes[len(changes)-1].Seq
+		stream, errc := sqlc.NewChangeFeed[Member](session, sqlc.WithPollInterval(time.Millisecond)).Stream(ctx, last)
+
+		c := &Member{Name: "c", Email: "c@test.com", CreatedAt: time.Now()}
+		if err := memberRepo.Create(ctx, c); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		select {
+		case got, ok := <-stream:
+			if !ok {
+				t.Fatalf("stream closed: %v", <-errc)
+			}
+			if got.Op != sqlc.ChangeCreate || got.PK != fmtID(c.ID) || got.Model != nil {
+				t.Errorf("unexpected change %+v", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for change")
+		}
+
+		cancel()
+		for range stream {
+		}
+		if err := <-errc; err != nil {
+			t.Errorf("expected nil error after cancel, got %v", err)
+		}
+	})
+
+	t.Run("NonPositiveOptions", func(t *testing.T) {
+		for _, n := range []int{0, -1} {
+			feed := sqlc.NewChangeFeed[Member](session, sqlc.WithChangeBatchSize(n), sqlc.WithPollInterval(time.Duration(n)))
+			got, err := feed.Poll(ctx, 0)
+			if err != nil {
+				t.Fatalf("Poll failed: %v", err)
+			}
+			if len(got) < len(changes) {
+				t.Errorf("batch size %d: expected the default batch size, got %d changes", n, len(got))
+			}
+
+			// The default poll interval applies: Stream neither panics nor spins
+			ctx, cancel := context.WithCancel(ctx)
+			stream, errc := feed.Stream(ctx, got[len(got)-1].Seq)
+			cancel()
+			for range stream {
+			}
+			if err := <-errc; err != nil {
+				t.Errorf("expected nil error after cancel, got %v", err)
+			}
+		}
+	})
+
+	t.Run("Trim", func(t *testing.T) {
+		if err := feed.Trim(ctx, changes[1].Seq); err != nil {
+			t.Fatalf("Trim failed: %v", err)
+		}
+		rest, err := feed.Poll(ctx, 0)
+		if err != nil {
+			t.Fatalf("Poll failed: %v", err)
+		}
+		if len(rest) == 0 || rest[0].Seq != changes[2].Seq {
+			t.Errorf("expected changes after the trimmed sequence, got %+v", rest)
+		}
+	})
+}
+
+func TestChangeFeedInstallPostgres(t *testing.T) {
+	dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+	if err := sqlc.NewChangeFeed[Member](dry, sqlc.WithChangeTable("audit.changes")).Install(context.Background()); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	var all strings.Builder
+	for _, stmt := range dry.Recorder().Statements() {
+		all.WriteString(stmt.SQL + ";\n")
+	}
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS audit.changes (\n\tseq BIGSERIAL PRIMARY KEY,",
+		"ELSIF TG_OP = 'DELETE' THEN\n\t\tINSERT INTO audit.changes (table_name, op, pk) VALUES ('members', 'delete', OLD.id::text);",
+		"CREATE TRIGGER sqlc_capture_members AFTER INSERT OR UPDATE OR DELETE ON members FOR EACH ROW EXECUTE FUNCTION sqlc_capture_members()",
+	} {
+		if !strings.Contains(all.String(), want) {
+			t.Errorf("install SQL missing %q\n%s", want, all.String())
+		}
+	}
+}
+
+func fmtID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
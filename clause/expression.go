@@ -3,6 +3,9 @@ package clause
 import (
 	"fmt"
 	"strings"
+
+	"github.com/arllen133/sqlc/internal/seal"
+	"github.com/jmoiron/sqlx"
 )
 
 // Columnar defines an interface for providing a column name.
@@ -26,6 +29,9 @@ func (c Column) ColumnName() string {
 	return c.Name
 }
 
+// SelectItem implements the SelectItem interface
+func (Column) SelectItem(seal.Token) {}
+
 var _ Columnar = Column{}
 
 // Expression is the base interface for all SQL expressions
@@ -33,6 +39,14 @@ type Expression interface {
 	Build() (sql string, args []any, err error)
 }
 
+// SelectItem is implemented by the module's types meant for a SELECT list
+// (QueryBuilder.Select): columns and generated fields, function and aggregate calls,
+// casts, CASE expressions, raw Exprs, and any of them aliased with As. The interface is
+// sealed; Select also accepts any other Columnar or Expression.
+type SelectItem interface {
+	SelectItem(seal.Token)
+}
+
 // Eq represents an equality expression (column = value)
 type Eq struct {
 	Column Column
@@ -154,6 +168,57 @@ func (i IN) Build() (string, []any, error) {
 	}
 }
 
+// TupleIn represents a multi-column IN expression for composite keys:
+// (user_id, product_id) IN ((?, ?), (?, ?)).
+// Each row of Values holds one value per column, in column order.
+// Bound with BindTypes to a dialect lacking row-value IN lists (see RowValueIn),
+// it expands to ((user_id = ? AND product_id = ?) OR (user_id = ? AND product_id = ?)).
+type TupleIn struct {
+	Columns []Column
+	Values  [][]any
+
+	expand bool // Render the AND/OR expansion instead of row values
+}
+
+func (t TupleIn) Build() (string, []any, error) {
+	if len(t.Columns) == 0 {
+		return "", nil, fmt.Errorf("clause: tuple IN requires at least one column")
+	}
+	args := make([]any, 0, len(t.Values)*len(t.Columns))
+	for i, row := range t.Values {
+		if len(row) != len(t.Columns) {
+			return "", nil, fmt.Errorf("clause: tuple IN row %d has %d values, expected %d", i, len(row), len(t.Columns))
+		}
+		args = append(args, row...)
+	}
+	if len(t.Values) == 0 {
+		return "1 = 0", nil, nil // IN with empty list is always false
+	}
+
+	names := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		names[i] = col.ColumnName()
+	}
+	rows := make([]string, len(t.Values))
+	if t.expand {
+		conds := make([]string, len(names))
+		for i, name := range names {
+			conds[i] = name + " = ?"
+		}
+		row := "(" + strings.Join(conds, " AND ") + ")"
+		for i := range rows {
+			rows[i] = row
+		}
+		return "(" + strings.Join(rows, " OR ") + ")", args, nil
+	}
+
+	row := "(" + strings.Repeat("?, ", len(names)-1) + "?)"
+	for i := range rows {
+		rows[i] = row
+	}
+	return fmt.Sprintf("(%s) IN (%s)", strings.Join(names, ", "), strings.Join(rows, ", ")), args, nil
+}
+
 // Between represents a BETWEEN expression
 type Between struct {
 	Column Column
@@ -225,17 +290,107 @@ func (n Not) Build() (string, []any, error) {
 	return "NOT (" + sql + ")", args, nil
 }
 
-// Expr represents a custom SQL expression
+// Expr represents a custom SQL expression with positional (?) arguments; use Named
+// for :name parameters. Build fails with a *BuildError if the number of placeholders
+// does not match the number of Vars.
+//
+//	clause.Expr{SQL: "level BETWEEN ? AND ?", Vars: []any{1, 5}}
 type Expr struct {
 	SQL  string
 	Vars []any
 }
 
+// SelectItem implements the SelectItem interface
+func (Expr) SelectItem(seal.Token) {}
+
 func (e Expr) Build() (string, []any, error) {
+	question, dollar := Placeholders(e.SQL)
+	if question == 0 {
+		// Raw $n placeholders written for PostgreSQL
+		question = dollar
+	}
+	if question != len(e.Vars) {
+		return "", nil, &BuildError{SQL: e.SQL, Placeholders: question, Args: len(e.Vars)}
+	}
 	return e.SQL, e.Vars, nil
 }
 
-// Assignment represents a column assignment for UPDATE
+// BuildError reports a raw SQL fragment whose placeholders do not match its arguments.
+// It is returned when the expression is built, before the statement reaches the driver.
+//
+//	var be *clause.BuildError
+//	if errors.As(err, &be) {
+//	    log.Printf("%q: %d placeholders, %d args", be.SQL, be.Placeholders, be.Args)
+//	}
+type BuildError struct {
+	SQL          string // SQL fragment
+	Placeholders int    // Placeholders found in SQL
+	Args         int    // Arguments supplied
+}
+
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("clause: expression %q has %d placeholder(s) but %d argument(s)", e.SQL, e.Placeholders, e.Args)
+}
+
+// Placeholders counts the bind placeholders of sql: question is the number of ?
+// placeholders and dollar the highest $n placeholder. Text in single, double or back
+// quotes is ignored, as is the escaped ?? (a literal ?, e.g. the PostgreSQL JSONB operator).
+func Placeholders(sql string) (question, dollar int) {
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; c {
+		case '\'', '"', '`':
+			// Skip to the closing quote; a doubled quote (escape) reopens the text
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				return question, dollar
+			}
+			i += end + 1
+		case '?':
+			if i+1 < len(sql) && sql[i+1] == '?' {
+				i++
+				continue
+			}
+			question++
+		case '$':
+			n, j := 0, i+1
+			for ; j < len(sql) && sql[j] >= '0' && sql[j] <= '9'; j++ {
+				n = n*10 + int(sql[j]-'0')
+			}
+			if j > i+1 {
+				dollar = max(dollar, n)
+				i = j - 1
+			}
+		}
+	}
+	return question, dollar
+}
+
+// Named represents a custom SQL expression with :name parameters.
+// Arg is a map[string]any or a struct with db tags; parameters are bound in
+// order of appearance. Literal colons are doubled, so a PostgreSQL cast
+// x::date is written x::::date.
+//
+//	clause.Named{SQL: "tenant_id = :tenant AND (owner_id = :user OR shared_with = :user)", Arg: params}
+type Named struct {
+	SQL string
+	Arg any
+}
+
+func (n Named) Build() (string, []any, error) {
+	return buildNamed(n.SQL, n.Arg)
+}
+
+// buildNamed compiles :name parameters into ? placeholders with positional args
+func buildNamed(sql string, arg any) (string, []any, error) {
+	query, args, err := sqlx.Named(sql, arg)
+	if err != nil {
+		return "", nil, fmt.Errorf("clause: named parameters: %w", err)
+	}
+	return query, args, nil
+}
+
+// Assignment represents a column assignment for UPDATE.
+// Repository updates render a Value implementing Expression inline (e.g. column = column + ?).
 type Assignment struct {
 	Column Column
 	Value  any
@@ -245,6 +400,18 @@ func (a Assignment) Build() (string, []any, error) {
 	return a.Column.ColumnName() + " = ?", []any{a.Value}, nil
 }
 
+// Incr returns an assignment that adds delta to the column's current value
+// (column = column + delta) in the database, without a read-modify-write round trip.
+func Incr(column Column, delta any) Assignment {
+	return Assignment{Column: column, Value: Expr{SQL: column.ColumnName() + " + ?", Vars: []any{delta}}}
+}
+
+// Decr returns an assignment that subtracts delta from the column's current value
+// (column = column - delta) in the database.
+func Decr(column Column, delta any) Assignment {
+	return Assignment{Column: column, Value: Expr{SQL: column.ColumnName() + " - ?", Vars: []any{delta}}}
+}
+
 // OrderByColumn represents an ORDER BY column
 type OrderByColumn struct {
 	Column Column
@@ -312,3 +479,119 @@ func (n NotExistsExpr) Build() (string, []any, error) {
 	}
 	return "NOT EXISTS (" + sql + ")", args, nil
 }
+
+// OrderByExpression represents an ORDER BY on an arbitrary expression (e.g. CASE, computed score)
+type OrderByExpression struct {
+	Expr Expression
+	Desc bool
+}
+
+func (o OrderByExpression) Build() (string, []any, error) {
+	sql, args, err := o.Expr.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	if o.Desc {
+		sql += " DESC"
+	}
+	return sql, args, nil
+}
+
+// When represents a single WHEN ... THEN ... branch of a CASE expression.
+// Cond and Result may be an Expression, a Columnar, or a plain value (bound as a parameter).
+type When struct {
+	Cond   any
+	Result any
+}
+
+// Case represents a CASE expression.
+// With Operand set it renders a simple CASE (CASE operand WHEN v THEN r ... END),
+// otherwise a searched CASE (CASE WHEN cond THEN r ... END).
+// A nil Default omits the ELSE branch.
+//
+//	clause.Case{Operand: status}.When("urgent", 0).When("high", 1).Else(2)
+type Case struct {
+	Operand  any
+	Branches []When
+	Default  any
+}
+
+// When returns a copy of the CASE expression with a branch appended.
+func (c Case) When(cond, result any) Case {
+	branches := make([]When, len(c.Branches), len(c.Branches)+1)
+	copy(branches, c.Branches)
+	c.Branches = append(branches, When{Cond: cond, Result: result})
+	return c
+}
+
+// Else returns a copy of the CASE expression with the ELSE result set.
+func (c Case) Else(result any) Case {
+	c.Default = result
+	return c
+}
+
+// Asc orders by the CASE expression ascending
+func (c Case) Asc() OrderByExpression { return OrderByExpression{Expr: c} }
+
+// Desc orders by the CASE expression descending
+func (c Case) Desc() OrderByExpression { return OrderByExpression{Expr: c, Desc: true} }
+
+// SelectItem implements the SelectItem interface
+func (Case) SelectItem(seal.Token) {}
+
+func (c Case) Build() (string, []any, error) {
+	if len(c.Branches) == 0 {
+		return "", nil, fmt.Errorf("clause: CASE requires at least one WHEN branch")
+	}
+
+	var sb strings.Builder
+	var args []any
+	write := func(v any) error {
+		sql, vArgs, err := buildOperand(v)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(sql)
+		args = append(args, vArgs...)
+		return nil
+	}
+
+	sb.WriteString("CASE")
+	if c.Operand != nil {
+		sb.WriteString(" ")
+		if err := write(c.Operand); err != nil {
+			return "", nil, err
+		}
+	}
+	for _, w := range c.Branches {
+		sb.WriteString(" WHEN ")
+		if err := write(w.Cond); err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(" THEN ")
+		if err := write(w.Result); err != nil {
+			return "", nil, err
+		}
+	}
+	if c.Default != nil {
+		sb.WriteString(" ELSE ")
+		if err := write(c.Default); err != nil {
+			return "", nil, err
+		}
+	}
+	sb.WriteString(" END")
+	return sb.String(), args, nil
+}
+
+// buildOperand renders an operand: expressions are built, columns are referenced
+// by name, and any other value is bound as a parameter.
+func buildOperand(v any) (string, []any, error) {
+	switch o := v.(type) {
+	case Expression:
+		return o.Build()
+	case Columnar:
+		return o.ColumnName(), nil, nil
+	default:
+		return "?", []any{v}, nil
+	}
+}
@@ -1,7 +1,9 @@
 package clause_test
 
 import (
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/arllen133/sqlc/clause"
@@ -92,12 +94,54 @@ func TestExpressions(t *testing.T) {
 			wantSQL:  "title LIKE ?",
 			wantArgs: []any{"%golang%"},
 		},
+		{
+			name: "TupleIn",
+			expr: clause.TupleIn{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+				Values:  [][]any{{1, 2}, {3, 4}},
+			},
+			wantSQL:  "(user_id, product_id) IN ((?, ?), (?, ?))",
+			wantArgs: []any{1, 2, 3, 4},
+		},
+		{
+			name:     "TupleIn Empty",
+			expr:     clause.TupleIn{Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}}},
+			wantSQL:  "1 = 0",
+			wantArgs: nil,
+		},
 		{
 			name:     "Assignment",
 			expr:     clause.Assignment{Column: clause.Column{Name: "email"}, Value: "new@example.com"},
 			wantSQL:  "email = ?",
 			wantArgs: []any{"new@example.com"},
 		},
+		{
+			name:     "Expr Positional",
+			expr:     clause.Expr{SQL: "age > ? AND age < ?", Vars: []any{18, 65}},
+			wantSQL:  "age > ? AND age < ?",
+			wantArgs: []any{18, 65},
+		},
+		{
+			name:     "Expr Map Var",
+			expr:     clause.Expr{SQL: "meta = ?", Vars: []any{map[string]any{"user": 7}}},
+			wantSQL:  "meta = ?",
+			wantArgs: []any{map[string]any{"user": 7}},
+		},
+		{
+			name:     "Named Map",
+			expr:     clause.Named{SQL: "owner_id = :user OR shared_with = :user AND level >= :min", Arg: map[string]any{"user": 7, "min": 2}},
+			wantSQL:  "owner_id = ? OR shared_with = ? AND level >= ?",
+			wantArgs: []any{7, 7, 2},
+		},
+		{
+			name: "Named Struct",
+			expr: clause.Named{SQL: "tenant_id = :tenant_id AND created_at::::date = :day", Arg: struct {
+				TenantID string `db:"tenant_id"`
+				Day      string `db:"day"`
+			}{"acme", "2024-01-01"}},
+			wantSQL:  "tenant_id = ? AND created_at::date = ?",
+			wantArgs: []any{"acme", "2024-01-01"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -117,6 +161,58 @@ func TestExpressions(t *testing.T) {
 	}
 }
 
+func TestNamedMissingParam(t *testing.T) {
+	_, _, err := clause.Named{SQL: "id = :id", Arg: map[string]any{"other": 1}}.Build()
+	if err == nil {
+		t.Error("expected error for missing named parameter")
+	}
+}
+
+func TestTupleInRowMismatch(t *testing.T) {
+	expr := clause.TupleIn{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		Values:  [][]any{{1, 2}, {3}},
+	}
+	if _, _, err := expr.Build(); err == nil || !strings.Contains(err.Error(), "row 1 has 1 values, expected 2") {
+		t.Errorf("expected row length error, got %v", err)
+	}
+}
+
+func TestExprPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    clause.Expr
+		wantErr bool
+	}{
+		{"Match", clause.Expr{SQL: "a = ? AND b > ?", Vars: []any{1, 2}}, false},
+		{"NoVars", clause.Expr{SQL: "users.id = orders.user_id"}, false},
+		{"EscapedQuestion", clause.Expr{SQL: "meta ?? 'key' AND id = ?", Vars: []any{1}}, false},
+		{"QuotedQuestion", clause.Expr{SQL: "title = 'why?' AND \"a?b\" = ?", Vars: []any{1}}, false},
+		{"DoubledQuote", clause.Expr{SQL: "title = 'it''s?' AND id = ?", Vars: []any{1}}, false},
+		{"Dollar", clause.Expr{SQL: "id = $1 OR parent_id = $1", Vars: []any{1}}, false},
+		{"TooFewArgs", clause.Expr{SQL: "a = ? AND b = ?", Vars: []any{1}}, true},
+		{"TooManyArgs", clause.Expr{SQL: "a = ?", Vars: []any{1, 2}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.expr.Build()
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var be *clause.BuildError
+			if !errors.As(err, &be) {
+				t.Fatalf("expected *BuildError, got %v", err)
+			}
+			if be.SQL != tt.expr.SQL || be.Args != len(tt.expr.Vars) {
+				t.Errorf("unexpected error details: %+v", be)
+			}
+		})
+	}
+}
+
 func TestOrderBy(t *testing.T) {
 	col := clause.Column{Name: "created_at"}
 	tests := []struct {
@@ -149,3 +245,67 @@ func TestOrderBy(t *testing.T) {
 		})
 	}
 }
+
+func TestCase(t *testing.T) {
+	status := clause.Column{Name: "status"}
+	tests := []struct {
+		name     string
+		expr     clause.Expression
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "Simple",
+			expr:     clause.Case{Operand: status}.When("urgent", 0).When("high", 1).Else(2),
+			wantSQL:  "CASE status WHEN ? THEN ? WHEN ? THEN ? ELSE ? END",
+			wantArgs: []any{"urgent", 0, "high", 1, 2},
+		},
+		{
+			name: "Searched",
+			expr: clause.Case{}.
+				When(clause.Gt{Column: clause.Column{Name: "score"}, Value: 90}, "A").
+				When(clause.IsNull{Column: clause.Column{Name: "score"}}, clause.Column{Name: "fallback"}),
+			wantSQL:  "CASE WHEN score > ? THEN ? WHEN score IS NULL THEN fallback END",
+			wantArgs: []any{90, "A"},
+		},
+		{
+			name: "OrderByExpressionDesc",
+			expr: clause.OrderByExpression{
+				Expr: clause.Expr{SQL: "score * ?", Vars: []any{2}},
+				Desc: true,
+			},
+			wantSQL:  "score * ? DESC",
+			wantArgs: []any{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.expr.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL: want %q, got %q", tt.wantSQL, gotSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("Args: want %v, got %v", tt.wantArgs, gotArgs)
+			}
+		})
+	}
+
+	t.Run("NoBranches", func(t *testing.T) {
+		if _, _, err := (clause.Case{Operand: status}).Build(); err == nil {
+			t.Error("expected error for CASE without WHEN")
+		}
+	})
+
+	t.Run("WhenDoesNotShareBranches", func(t *testing.T) {
+		base := clause.Case{Operand: status}.When("a", 1)
+		left := base.When("b", 2)
+		right := base.When("c", 3)
+		if left.Branches[1].Cond != "b" || right.Branches[1].Cond != "c" {
+			t.Errorf("branches shared between copies: %+v / %+v", left.Branches, right.Branches)
+		}
+	})
+}
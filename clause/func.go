@@ -0,0 +1,285 @@
+package clause
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arllen133/sqlc/internal/seal"
+)
+
+// TypeNamer maps portable cast type names (e.g. "bigint", "text") to dialect-specific ones
+type TypeNamer interface {
+	CastType(name string) string
+}
+
+// typeBinder is implemented by expressions that contain (or may contain) dialect-dependent type names
+type typeBinder interface {
+	bindTypes(namer TypeNamer) Expression
+}
+
+// DialectBinder is implemented by expressions defined outside this package whose SQL
+// depends on the dialect (e.g. the functions in clause/funcs). BindTypes passes them the
+// dialect so they can pick its spelling; they are responsible for binding their operands.
+type DialectBinder interface {
+	BindDialect(namer TypeNamer) Expression
+}
+
+// BindTypes returns expr with all nested cast type names resolved by namer.
+// Expressions without casts are returned unchanged.
+func BindTypes(expr Expression, namer TypeNamer) Expression {
+	if namer == nil {
+		return expr
+	}
+	switch b := expr.(type) {
+	case typeBinder:
+		return b.bindTypes(namer)
+	case DialectBinder:
+		return b.BindDialect(namer)
+	}
+	return expr
+}
+
+// RowValueIn is optionally implemented by a TypeNamer (dialect) to report whether it
+// supports row-value IN lists, e.g. (a, b) IN ((1, 2), (3, 4)). Dialects that do not
+// implement it are assumed to support them.
+type RowValueIn interface {
+	RowValueIn() bool
+}
+
+// bindOperand resolves cast type names in an operand if it is an expression
+func bindOperand(v any, namer TypeNamer) any {
+	if e, ok := v.(Expression); ok {
+		return BindTypes(e, namer)
+	}
+	return v
+}
+
+// Func represents a SQL function call, e.g. COALESCE(nickname, ?).
+// Args may be an Expression, a Columnar, or a plain value (bound as a parameter).
+type Func struct {
+	Name string
+	Args []any
+}
+
+// Coalesce returns the first non-NULL value: COALESCE(a, b, ...)
+func Coalesce(values ...any) Func {
+	return Func{Name: "COALESCE", Args: values}
+}
+
+// NullIf returns NULL when both values are equal: NULLIF(a, b)
+func NullIf(value, equal any) Func {
+	return Func{Name: "NULLIF", Args: []any{value, equal}}
+}
+
+// Count counts non-NULL values: COUNT(value)
+func Count(value any) Func {
+	return Func{Name: "COUNT", Args: []any{value}}
+}
+
+// CountAll counts rows: COUNT(*)
+func CountAll() Func {
+	return Func{Name: "COUNT", Args: []any{Expr{SQL: "*"}}}
+}
+
+// Sum adds up values: SUM(value)
+func Sum(value any) Func {
+	return Func{Name: "SUM", Args: []any{value}}
+}
+
+// Avg averages values: AVG(value)
+func Avg(value any) Func {
+	return Func{Name: "AVG", Args: []any{value}}
+}
+
+// Min returns the smallest value: MIN(value)
+func Min(value any) Func {
+	return Func{Name: "MIN", Args: []any{value}}
+}
+
+// Max returns the largest value: MAX(value)
+func Max(value any) Func {
+	return Func{Name: "MAX", Args: []any{value}}
+}
+
+func (f Func) Build() (string, []any, error) {
+	parts := make([]string, len(f.Args))
+	var args []any
+	for i, arg := range f.Args {
+		sql, argArgs, err := buildOperand(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		parts[i] = sql
+		args = append(args, argArgs...)
+	}
+	return f.Name + "(" + strings.Join(parts, ", ") + ")", args, nil
+}
+
+func (f Func) bindTypes(namer TypeNamer) Expression {
+	args := make([]any, len(f.Args))
+	for i, arg := range f.Args {
+		args[i] = bindOperand(arg, namer)
+	}
+	f.Args = args
+	return f
+}
+
+func (f Func) Eq(value any) Expression  { return Compare{Left: f, Op: "=", Right: value} }
+func (f Func) Neq(value any) Expression { return Compare{Left: f, Op: "<>", Right: value} }
+func (f Func) Gt(value any) Expression  { return Compare{Left: f, Op: ">", Right: value} }
+func (f Func) Gte(value any) Expression { return Compare{Left: f, Op: ">=", Right: value} }
+func (f Func) Lt(value any) Expression  { return Compare{Left: f, Op: "<", Right: value} }
+func (f Func) Lte(value any) Expression { return Compare{Left: f, Op: "<=", Right: value} }
+func (f Func) Asc() OrderByExpression   { return OrderByExpression{Expr: f} }
+func (f Func) Desc() OrderByExpression  { return OrderByExpression{Expr: f, Desc: true} }
+func (f Func) As(alias string) Alias    { return Alias{Expr: f, Name: alias} }
+
+// SelectItem implements the SelectItem interface
+func (Func) SelectItem(seal.Token) {}
+
+// CastExpr represents CAST(value AS type).
+// Type is a portable name; when the expression is used through a QueryBuilder
+// it is translated to the session dialect's spelling (e.g. bigint -> SIGNED on MySQL).
+type CastExpr struct {
+	Value any
+	Type  string
+	namer TypeNamer
+}
+
+// Cast converts a value to another SQL type: CAST(value AS type)
+func Cast(value any, typ string) CastExpr {
+	return CastExpr{Value: value, Type: typ}
+}
+
+func (c CastExpr) Build() (string, []any, error) {
+	if c.Type == "" {
+		return "", nil, fmt.Errorf("clause: CAST requires a target type")
+	}
+	sql, args, err := buildOperand(c.Value)
+	if err != nil {
+		return "", nil, err
+	}
+	typ := c.Type
+	if c.namer != nil {
+		typ = c.namer.CastType(typ)
+	}
+	return "CAST(" + sql + " AS " + typ + ")", args, nil
+}
+
+func (c CastExpr) bindTypes(namer TypeNamer) Expression {
+	c.Value = bindOperand(c.Value, namer)
+	c.namer = namer
+	return c
+}
+
+func (c CastExpr) Eq(value any) Expression  { return Compare{Left: c, Op: "=", Right: value} }
+func (c CastExpr) Neq(value any) Expression { return Compare{Left: c, Op: "<>", Right: value} }
+func (c CastExpr) Gt(value any) Expression  { return Compare{Left: c, Op: ">", Right: value} }
+func (c CastExpr) Gte(value any) Expression { return Compare{Left: c, Op: ">=", Right: value} }
+func (c CastExpr) Lt(value any) Expression  { return Compare{Left: c, Op: "<", Right: value} }
+func (c CastExpr) Lte(value any) Expression { return Compare{Left: c, Op: "<=", Right: value} }
+func (c CastExpr) Asc() OrderByExpression   { return OrderByExpression{Expr: c} }
+func (c CastExpr) Desc() OrderByExpression  { return OrderByExpression{Expr: c, Desc: true} }
+func (c CastExpr) As(alias string) Alias    { return Alias{Expr: c, Name: alias} }
+
+// SelectItem implements the SelectItem interface
+func (CastExpr) SelectItem(seal.Token) {}
+
+// Compare represents a binary comparison between two operands (left op right).
+// Operands may be an Expression, a Columnar, or a plain value (bound as a parameter).
+type Compare struct {
+	Left  any
+	Op    string
+	Right any
+}
+
+func (c Compare) Build() (string, []any, error) {
+	left, leftArgs, err := buildOperand(c.Left)
+	if err != nil {
+		return "", nil, err
+	}
+	right, rightArgs, err := buildOperand(c.Right)
+	if err != nil {
+		return "", nil, err
+	}
+	var args []any
+	args = append(append(args, leftArgs...), rightArgs...)
+	return left + " " + c.Op + " " + right, args, nil
+}
+
+func (c Compare) bindTypes(namer TypeNamer) Expression {
+	c.Left = bindOperand(c.Left, namer)
+	c.Right = bindOperand(c.Right, namer)
+	return c
+}
+
+// Alias represents an aliased select expression (expr AS name)
+type Alias struct {
+	Expr Expression
+	Name string
+}
+
+// As aliases an expression for use in a SELECT list: expr AS alias
+func As(expr Expression, alias string) Alias {
+	return Alias{Expr: expr, Name: alias}
+}
+
+// SelectItem implements the SelectItem interface
+func (Alias) SelectItem(seal.Token) {}
+
+func (a Alias) Build() (string, []any, error) {
+	sql, args, err := a.Expr.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	return sql + " AS " + a.Name, args, nil
+}
+
+func (a Alias) bindTypes(namer TypeNamer) Expression {
+	a.Expr = BindTypes(a.Expr, namer)
+	return a
+}
+
+func (a And) bindTypes(namer TypeNamer) Expression {
+	out := make(And, len(a))
+	for i, e := range a {
+		out[i] = BindTypes(e, namer)
+	}
+	return out
+}
+
+func (o Or) bindTypes(namer TypeNamer) Expression {
+	out := make(Or, len(o))
+	for i, e := range o {
+		out[i] = BindTypes(e, namer)
+	}
+	return out
+}
+
+func (t TupleIn) bindTypes(namer TypeNamer) Expression {
+	if r, ok := namer.(RowValueIn); ok && !r.RowValueIn() {
+		t.expand = true
+	}
+	return t
+}
+
+func (n Not) bindTypes(namer TypeNamer) Expression {
+	n.Expr = BindTypes(n.Expr, namer)
+	return n
+}
+
+func (o OrderByExpression) bindTypes(namer TypeNamer) Expression {
+	o.Expr = BindTypes(o.Expr, namer)
+	return o
+}
+
+func (c Case) bindTypes(namer TypeNamer) Expression {
+	c.Operand = bindOperand(c.Operand, namer)
+	branches := make([]When, len(c.Branches))
+	for i, w := range c.Branches {
+		branches[i] = When{Cond: bindOperand(w.Cond, namer), Result: bindOperand(w.Result, namer)}
+	}
+	c.Branches = branches
+	c.Default = bindOperand(c.Default, namer)
+	return c
+}
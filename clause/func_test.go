@@ -0,0 +1,197 @@
+package clause_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// upperTypes is a test TypeNamer that upper-cases type names
+type upperTypes struct{}
+
+func (upperTypes) CastType(name string) string { return strings.ToUpper(name) }
+
+// noRowValues is a test TypeNamer of a dialect without row-value IN lists
+type noRowValues struct{ upperTypes }
+
+func (noRowValues) RowValueIn() bool { return false }
+
+func TestFuncExpressions(t *testing.T) {
+	nickname := clause.Column{Name: "nickname"}
+	username := clause.Column{Table: "users", Name: "username"}
+	score := clause.Column{Name: "score"}
+
+	tests := []struct {
+		name     string
+		expr     clause.Expression
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "CoalesceColumns",
+			expr:    clause.Coalesce(nickname, username),
+			wantSQL: "COALESCE(nickname, users.username)",
+		},
+		{
+			name:     "CoalesceFallbackValue",
+			expr:     clause.Coalesce(nickname, "anonymous"),
+			wantSQL:  "COALESCE(nickname, ?)",
+			wantArgs: []any{"anonymous"},
+		},
+		{
+			name:     "NullIf",
+			expr:     clause.NullIf(score, 0),
+			wantSQL:  "NULLIF(score, ?)",
+			wantArgs: []any{0},
+		},
+		{
+			name:    "Cast",
+			expr:    clause.Cast(score, "bigint"),
+			wantSQL: "CAST(score AS bigint)",
+		},
+		{
+			name:     "NestedInWhere",
+			expr:     clause.Cast(clause.Coalesce(score, 0), "bigint").Gte(10),
+			wantSQL:  "CAST(COALESCE(score, ?) AS bigint) >= ?",
+			wantArgs: []any{0, 10},
+		},
+		{
+			name:    "CompareColumns",
+			expr:    clause.Coalesce(nickname, username).Neq(username),
+			wantSQL: "COALESCE(nickname, users.username) <> users.username",
+		},
+		{
+			name:    "OrderDesc",
+			expr:    clause.NullIf(score, clause.Expr{SQL: "0"}).Desc(),
+			wantSQL: "NULLIF(score, 0) DESC",
+		},
+		{
+			name:    "Alias",
+			expr:    clause.Coalesce(nickname, username).As("display_name"),
+			wantSQL: "COALESCE(nickname, users.username) AS display_name",
+		},
+		{
+			name:    "CountAll",
+			expr:    clause.As(clause.CountAll(), "total"),
+			wantSQL: "COUNT(*) AS total",
+		},
+		{
+			name:     "Aggregates",
+			expr:     clause.And{clause.Count(score).Gt(1), clause.Sum(score).Lt(10), clause.Avg(score).Gte(2), clause.Min(score).Eq(0), clause.Max(score).Lte(9)},
+			wantSQL:  "(COUNT(score) > ?) AND (SUM(score) < ?) AND (AVG(score) >= ?) AND (MIN(score) = ?) AND (MAX(score) <= ?)",
+			wantArgs: []any{1, 10, 2, 0, 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.expr.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL: want %q, got %q", tt.wantSQL, gotSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("Args: want %v, got %v", tt.wantArgs, gotArgs)
+			}
+		})
+	}
+
+	t.Run("CastWithoutType", func(t *testing.T) {
+		if _, _, err := clause.Cast(score, "").Build(); err == nil {
+			t.Error("expected error for CAST without type")
+		}
+	})
+}
+
+func TestBindTypes(t *testing.T) {
+	score := clause.Column{Name: "score"}
+	tests := []struct {
+		name    string
+		expr    clause.Expression
+		wantSQL string
+	}{
+		{
+			name:    "Cast",
+			expr:    clause.Cast(score, "bigint"),
+			wantSQL: "CAST(score AS BIGINT)",
+		},
+		{
+			name:    "InsideAndNot",
+			expr:    clause.And{clause.Not{Expr: clause.Cast(score, "text").Eq("1")}},
+			wantSQL: "(NOT (CAST(score AS TEXT) = ?))",
+		},
+		{
+			name:    "InsideCase",
+			expr:    clause.Case{}.When(clause.Cast(score, "int").Gt(1), clause.Cast(score, "real")).Desc(),
+			wantSQL: "CASE WHEN CAST(score AS INT) > ? THEN CAST(score AS REAL) END DESC",
+		},
+		{
+			name:    "NoCast",
+			expr:    clause.Eq{Column: score, Value: 1},
+			wantSQL: "score = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, _, err := clause.BindTypes(tt.expr, upperTypes{}).Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL: want %q, got %q", tt.wantSQL, gotSQL)
+			}
+		})
+	}
+}
+
+func TestBindTypesTupleIn(t *testing.T) {
+	tuple := clause.TupleIn{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		Values:  [][]any{{1, 2}, {3, 4}},
+	}
+	tests := []struct {
+		name    string
+		expr    clause.Expression
+		namer   clause.TypeNamer
+		wantSQL string
+	}{
+		{
+			name:    "RowValues",
+			expr:    tuple,
+			namer:   upperTypes{},
+			wantSQL: "(user_id, product_id) IN ((?, ?), (?, ?))",
+		},
+		{
+			name:    "Expanded",
+			expr:    tuple,
+			namer:   noRowValues{},
+			wantSQL: "((user_id = ? AND product_id = ?) OR (user_id = ? AND product_id = ?))",
+		},
+		{
+			name:    "ExpandedInsideNot",
+			expr:    clause.Not{Expr: tuple},
+			namer:   noRowValues{},
+			wantSQL: "NOT (((user_id = ? AND product_id = ?) OR (user_id = ? AND product_id = ?)))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := clause.BindTypes(tt.expr, tt.namer).Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL: want %q, got %q", tt.wantSQL, gotSQL)
+			}
+			if want := []any{1, 2, 3, 4}; !reflect.DeepEqual(gotArgs, want) {
+				t.Errorf("args: want %v, got %v", want, gotArgs)
+			}
+		})
+	}
+}
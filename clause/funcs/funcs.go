@@ -0,0 +1,252 @@
+// Package funcs provides portable SQL functions that render the correct spelling for
+// each supported dialect (MySQL, PostgreSQL and SQLite).
+//
+// The functions are regular clause expressions: they can be compared, ordered, aliased
+// and nested inside other expressions (clause.Count, clause.Coalesce, ...). When used
+// through a QueryBuilder the session dialect is bound automatically; built on their own
+// they render the PostgreSQL spelling.
+//
+// Usage example:
+//
+//	// WHERE LOWER(email) = ?  (all dialects)
+//	repo.Query().Where(funcs.Lower(generated.User.Email).Eq("alice@example.com"))
+//
+//	// Daily signups: DATE_TRUNC('day', created_at) on PostgreSQL,
+//	// strftime('%Y-%m-%d 00:00:00', created_at) on SQLite, ...
+//	repo.Query().
+//	    Select(funcs.DateTrunc("day", generated.User.CreatedAt).As("day"), clause.CountAll().As("signups")).
+//	    Scan(ctx, &rows)
+package funcs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
+)
+
+// Dialect names as reported by the sqlc dialects' Name method. Any other dialect
+// (including none) gets the PostgreSQL spelling, which follows standard SQL.
+const (
+	mysql  = "mysql"
+	sqlite = "sqlite3"
+)
+
+// Func is a SQL function call whose spelling depends on the dialect.
+// Args may be an Expression, a Columnar, or a plain value (bound as a parameter).
+type Func struct {
+	args    []any
+	dialect string
+	// render spells the call for dialect from the built operands; vars are appended
+	// after the operands' own arguments
+	render func(dialect string, args []string) (sql string, vars []any, err error)
+}
+
+// call renders NAME(arg1, arg2, ...)
+func call(name string) func(string, []string) (string, []any, error) {
+	return func(_ string, args []string) (string, []any, error) {
+		return name + "(" + strings.Join(args, ", ") + ")", nil, nil
+	}
+}
+
+// Lower converts a string to lower case: LOWER(value)
+func Lower(value any) Func {
+	return Func{args: []any{value}, render: call("LOWER")}
+}
+
+// Upper converts a string to upper case: UPPER(value)
+func Upper(value any) Func {
+	return Func{args: []any{value}, render: call("UPPER")}
+}
+
+// Length returns the number of characters of a string.
+// MySQL: CHAR_LENGTH(value) (LENGTH counts bytes there); others: LENGTH(value)
+func Length(value any) Func {
+	return Func{args: []any{value}, render: func(dialect string, args []string) (string, []any, error) {
+		if dialect == mysql {
+			return call("CHAR_LENGTH")(dialect, args)
+		}
+		return call("LENGTH")(dialect, args)
+	}}
+}
+
+// Concat joins strings. MySQL: CONCAT(a, b, ...); others: (a || b || ...)
+//
+// Note:
+//   - The result is NULL if any value is NULL on every dialect; wrap nullable
+//     values in clause.Coalesce(value, "")
+func Concat(values ...any) Func {
+	return Func{args: values, render: func(dialect string, args []string) (string, []any, error) {
+		if len(args) == 0 {
+			return "", nil, fmt.Errorf("funcs: CONCAT requires at least one value")
+		}
+		if dialect == mysql {
+			return call("CONCAT")(dialect, args)
+		}
+		return "(" + strings.Join(args, " || ") + ")", nil, nil
+	}}
+}
+
+// Substring extracts length characters starting at start (1-based).
+// MySQL: SUBSTRING(value, ?, ?); PostgreSQL: SUBSTRING(value FROM ? FOR ?); SQLite: SUBSTR(value, ?, ?)
+func Substring(value any, start, length int) Func {
+	return Func{args: []any{value, start, length}, render: func(dialect string, args []string) (string, []any, error) {
+		switch dialect {
+		case mysql:
+			return call("SUBSTRING")(dialect, args)
+		case sqlite:
+			return call("SUBSTR")(dialect, args)
+		default:
+			return "SUBSTRING(" + args[0] + " FROM " + args[1] + " FOR " + args[2] + ")", nil, nil
+		}
+	}}
+}
+
+// Now returns the current date and time.
+// MySQL and PostgreSQL: NOW(); SQLite: CURRENT_TIMESTAMP (UTC)
+func Now() Func {
+	return Func{render: func(dialect string, args []string) (string, []any, error) {
+		if dialect == sqlite {
+			return "CURRENT_TIMESTAMP", nil, nil
+		}
+		return "NOW()", nil, nil
+	}}
+}
+
+// dateTruncFormats maps DateTrunc units to MySQL DATE_FORMAT and SQLite strftime formats
+var dateTruncFormats = map[string][2]string{
+	"year":   {"%Y-01-01", "%Y-01-01 00:00:00"},
+	"month":  {"%Y-%m-01", "%Y-%m-01 00:00:00"},
+	"day":    {"%Y-%m-%d", "%Y-%m-%d 00:00:00"},
+	"hour":   {"%Y-%m-%d %H:00:00", "%Y-%m-%d %H:00:00"},
+	"minute": {"%Y-%m-%d %H:%i:00", "%Y-%m-%d %H:%M:00"},
+	"second": {"%Y-%m-%d %H:%i:%s", "%Y-%m-%d %H:%M:%S"},
+}
+
+// DateTrunc truncates a timestamp to the start of unit
+// (year, month, day, hour, minute or second).
+//
+// Example:
+//
+//	// PostgreSQL: DATE_TRUNC('month', created_at)
+//	// MySQL:      CAST(DATE_FORMAT(created_at, '%Y-%m-01') AS DATETIME)
+//	// SQLite:     strftime('%Y-%m-01 00:00:00', created_at)
+//	funcs.DateTrunc("month", generated.Order.CreatedAt)
+//
+// Note:
+//   - An unsupported unit is reported when the query is built
+//   - SQLite returns the truncated timestamp as text (YYYY-MM-DD HH:MM:SS)
+func DateTrunc(unit string, value any) Func {
+	unit = strings.ToLower(unit)
+	return Func{args: []any{value}, render: func(dialect string, args []string) (string, []any, error) {
+		formats, ok := dateTruncFormats[unit]
+		if !ok {
+			return "", nil, fmt.Errorf("funcs: unsupported DATE_TRUNC unit %q", unit)
+		}
+		switch dialect {
+		case mysql:
+			return "CAST(DATE_FORMAT(" + args[0] + ", '" + formats[0] + "') AS DATETIME)", nil, nil
+		case sqlite:
+			return "strftime('" + formats[1] + "', " + args[0] + ")", nil, nil
+		default:
+			return "DATE_TRUNC('" + unit + "', " + args[0] + ")", nil, nil
+		}
+	}}
+}
+
+// JSONExtract returns the value at path (e.g. "$.address.city") of a JSON document as text.
+// MySQL: JSON_UNQUOTE(JSON_EXTRACT(value, ?)); PostgreSQL: (value #>> CAST(? AS text[]));
+// SQLite: json_extract(value, ?)
+//
+// Note:
+//   - Objects and arrays are returned as JSON text
+//   - For comparisons against typed JSON values use the field/json package instead
+func JSONExtract(value any, path string) Func {
+	return Func{args: []any{value}, render: func(dialect string, args []string) (string, []any, error) {
+		switch dialect {
+		case mysql:
+			return "JSON_UNQUOTE(JSON_EXTRACT(" + args[0] + ", ?))", []any{path}, nil
+		case sqlite:
+			return "json_extract(" + args[0] + ", ?)", []any{path}, nil
+		default:
+			return "(" + args[0] + " #>> CAST(? AS text[]))", []any{postgresPath(path)}, nil
+		}
+	}}
+}
+
+// postgresPath converts a JSON path ($.a.b[0]) to a PostgreSQL text array literal ({a,b,0})
+func postgresPath(path string) string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	return "{" + strings.ReplaceAll(path, ".", ",") + "}"
+}
+
+func (f Func) Build() (string, []any, error) {
+	parts := make([]string, len(f.args))
+	var args []any
+	for i, arg := range f.args {
+		sql, argArgs, err := buildOperand(arg)
+		if err != nil {
+			return "", nil, err
+		}
+		parts[i] = sql
+		args = append(args, argArgs...)
+	}
+	sql, vars, err := f.render(f.dialect, parts)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, append(args, vars...), nil
+}
+
+// BindDialect implements clause.DialectBinder: it records the dialect's name and binds
+// nested operand expressions.
+func (f Func) BindDialect(namer clause.TypeNamer) clause.Expression {
+	args := make([]any, len(f.args))
+	for i, arg := range f.args {
+		if e, ok := arg.(clause.Expression); ok {
+			arg = clause.BindTypes(e, namer)
+		}
+		args[i] = arg
+	}
+	f.args = args
+	if d, ok := namer.(interface{ Name() string }); ok {
+		f.dialect = d.Name()
+	}
+	return f
+}
+
+func (f Func) Eq(value any) clause.Expression { return clause.Compare{Left: f, Op: "=", Right: value} }
+func (f Func) Neq(value any) clause.Expression {
+	return clause.Compare{Left: f, Op: "<>", Right: value}
+}
+func (f Func) Gt(value any) clause.Expression { return clause.Compare{Left: f, Op: ">", Right: value} }
+func (f Func) Gte(value any) clause.Expression {
+	return clause.Compare{Left: f, Op: ">=", Right: value}
+}
+func (f Func) Lt(value any) clause.Expression { return clause.Compare{Left: f, Op: "<", Right: value} }
+func (f Func) Lte(value any) clause.Expression {
+	return clause.Compare{Left: f, Op: "<=", Right: value}
+}
+func (f Func) Like(pattern string) clause.Expression {
+	return clause.Compare{Left: f, Op: "LIKE", Right: pattern}
+}
+func (f Func) Asc() clause.OrderByExpression  { return clause.OrderByExpression{Expr: f} }
+func (f Func) Desc() clause.OrderByExpression { return clause.OrderByExpression{Expr: f, Desc: true} }
+func (f Func) As(alias string) clause.Alias   { return clause.Alias{Expr: f, Name: alias} }
+
+// SelectItem implements the clause.SelectItem interface
+func (Func) SelectItem(seal.Token) {}
+
+// buildOperand renders a function argument like the clause package does
+func buildOperand(v any) (string, []any, error) {
+	switch o := v.(type) {
+	case clause.Expression:
+		return o.Build()
+	case clause.Columnar:
+		return o.ColumnName(), nil, nil
+	default:
+		return "?", []any{v}, nil
+	}
+}
@@ -0,0 +1,173 @@
+package funcs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/clause/funcs"
+)
+
+// dialect is a test TypeNamer reporting a dialect name
+type dialect string
+
+func (d dialect) Name() string              { return string(d) }
+func (dialect) CastType(name string) string { return name }
+
+func TestFuncs(t *testing.T) {
+	email := clause.Column{Name: "email"}
+	name := clause.Column{Table: "users", Name: "name"}
+	createdAt := clause.Column{Name: "created_at"}
+	meta := clause.Column{Name: "meta"}
+
+	tests := []struct {
+		name     string
+		expr     clause.Expression
+		want     map[dialect]string // keyed by dialect name
+		wantArgs []any
+	}{
+		{
+			name: "Lower",
+			expr: funcs.Lower(email).Eq("a@b.c"),
+			want: map[dialect]string{
+				"mysql":    "LOWER(email) = ?",
+				"postgres": "LOWER(email) = ?",
+				"sqlite3":  "LOWER(email) = ?",
+			},
+			wantArgs: []any{"a@b.c"},
+		},
+		{
+			name: "Length",
+			expr: funcs.Length(name).Gt(3),
+			want: map[dialect]string{
+				"mysql":    "CHAR_LENGTH(users.name) > ?",
+				"postgres": "LENGTH(users.name) > ?",
+				"sqlite3":  "LENGTH(users.name) > ?",
+			},
+			wantArgs: []any{3},
+		},
+		{
+			name: "Concat",
+			expr: funcs.Concat(name, " <", email, ">"),
+			want: map[dialect]string{
+				"mysql":    "CONCAT(users.name, ?, email, ?)",
+				"postgres": "(users.name || ? || email || ?)",
+				"sqlite3":  "(users.name || ? || email || ?)",
+			},
+			wantArgs: []any{" <", ">"},
+		},
+		{
+			name: "Substring",
+			expr: funcs.Substring(email, 1, 3),
+			want: map[dialect]string{
+				"mysql":    "SUBSTRING(email, ?, ?)",
+				"postgres": "SUBSTRING(email FROM ? FOR ?)",
+				"sqlite3":  "SUBSTR(email, ?, ?)",
+			},
+			wantArgs: []any{1, 3},
+		},
+		{
+			name: "Now",
+			expr: clause.Compare{Left: createdAt, Op: "<", Right: funcs.Now()},
+			want: map[dialect]string{
+				"mysql":    "created_at < NOW()",
+				"postgres": "created_at < NOW()",
+				"sqlite3":  "created_at < CURRENT_TIMESTAMP",
+			},
+		},
+		{
+			name: "DateTrunc",
+			expr: funcs.DateTrunc("Month", createdAt).As("month"),
+			want: map[dialect]string{
+				"mysql":    "CAST(DATE_FORMAT(created_at, '%Y-%m-01') AS DATETIME) AS month",
+				"postgres": "DATE_TRUNC('month', created_at) AS month",
+				"sqlite3":  "strftime('%Y-%m-01 00:00:00', created_at) AS month",
+			},
+		},
+		{
+			name: "JSONExtract",
+			expr: funcs.JSONExtract(meta, "$.address.city").Eq("Berlin"),
+			want: map[dialect]string{
+				"mysql":    "JSON_UNQUOTE(JSON_EXTRACT(meta, ?)) = ?",
+				"postgres": "(meta #>> CAST(? AS text[])) = ?",
+				"sqlite3":  "json_extract(meta, ?) = ?",
+			},
+		},
+		{
+			name: "NestedInAggregate",
+			expr: clause.Count(funcs.Upper(funcs.Substring(name, 1, 1))),
+			want: map[dialect]string{
+				"mysql":    "COUNT(UPPER(SUBSTRING(users.name, ?, ?)))",
+				"postgres": "COUNT(UPPER(SUBSTRING(users.name FROM ? FOR ?)))",
+				"sqlite3":  "COUNT(UPPER(SUBSTR(users.name, ?, ?)))",
+			},
+			wantArgs: []any{1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		for d, wantSQL := range tt.want {
+			t.Run(tt.name+"/"+string(d), func(t *testing.T) {
+				sql, args, err := clause.BindTypes(tt.expr, d).Build()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if sql != wantSQL {
+					t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", sql, wantSQL)
+				}
+				if tt.wantArgs != nil && !reflect.DeepEqual(args, tt.wantArgs) {
+					t.Errorf("args mismatch: got %v, want %v", args, tt.wantArgs)
+				}
+			})
+		}
+	}
+}
+
+func TestJSONExtractPathArgs(t *testing.T) {
+	expr := funcs.JSONExtract(clause.Column{Name: "meta"}, "$.tags[0]")
+	tests := []struct {
+		dialect dialect
+		want    []any
+	}{
+		{"mysql", []any{"$.tags[0]"}},
+		{"sqlite3", []any{"$.tags[0]"}},
+		{"postgres", []any{"{tags,0}"}},
+	}
+	for _, tt := range tests {
+		_, args, err := clause.BindTypes(expr, tt.dialect).Build()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(args, tt.want) {
+			t.Errorf("%s: args mismatch: got %v, want %v", tt.dialect, args, tt.want)
+		}
+	}
+}
+
+func TestFuncsUnbound(t *testing.T) {
+	// Without a dialect the PostgreSQL (standard SQL) spelling is used
+	sql, _, err := funcs.Concat(clause.Column{Name: "first"}, clause.Column{Name: "last"}).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "(first || last)"; sql != want {
+		t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", sql, want)
+	}
+}
+
+func TestFuncsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr clause.Expression
+	}{
+		{"UnsupportedUnit", funcs.DateTrunc("fortnight", clause.Column{Name: "created_at"})},
+		{"EmptyConcat", funcs.Concat()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := clause.BindTypes(tt.expr, dialect("mysql")).Build(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -153,6 +153,16 @@ var _ = gen.Config{
 	FieldTypeMap: map[string]string{
 		"sql.NullTime": "field.Time",
 	},
+	SoftDeleteColumns: map[string]string{
+		"User": "removed_at",
+	},
+	DefaultOrder: map[string]string{
+		"User": "created_at DESC",
+	},
+	Partials: map[string]string{
+		"User": "Identity: id, username",
+	},
+	Patches: []any{"User", &Post{}},
 }
 `
 	err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(configContent), 0644)
@@ -180,4 +190,20 @@ var _ = gen.Config{
 	if cfg.FieldTypeMap["sql.NullTime"] != "field.Time" {
 		t.Errorf("expected FieldTypeMap['sql.NullTime']='field.Time', got %v", cfg.FieldTypeMap)
 	}
+
+	if cfg.SoftDeleteColumns["User"] != "removed_at" {
+		t.Errorf("expected SoftDeleteColumns['User']='removed_at', got %v", cfg.SoftDeleteColumns)
+	}
+
+	if cfg.DefaultOrder["User"] != "created_at DESC" {
+		t.Errorf("expected DefaultOrder['User']='created_at DESC', got %v", cfg.DefaultOrder)
+	}
+
+	if cfg.Partials["User"] != "Identity: id, username" {
+		t.Errorf("expected Partials['User']='Identity: id, username', got %v", cfg.Partials)
+	}
+
+	if len(cfg.Patches) != 2 || cfg.Patches[0] != "User" || cfg.Patches[1] != "Post" {
+		t.Errorf("expected Patches ['User' 'Post'], got %v", cfg.Patches)
+	}
 }
@@ -7,8 +7,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"text/template"
+
+	"github.com/arllen133/sqlc"
 )
 
 var Version = "dev"
@@ -36,12 +39,18 @@ import (
 	{{if .HasJSONField}}json "github.com/arllen133/sqlc/field/json"{{end}}
 	{{if .ModulePath}}{{if .PackagePath}}"{{.ModulePath}}/{{.PackagePath}}"{{else}}"{{.ModulePath}}"{{end}}{{end}}
 	{{if .HasJSON}}"encoding/json"{{end}}
-	{{if .SoftDeleteField}}"time"{{end}}
+	{{if and .SoftDeleteField (ne .SoftDeleteFieldType "bool")}}"time"{{end}}
 	{{if eq .SoftDeleteFieldType "sql.NullTime"}}"database/sql"{{end}}
+	{{- range .ExtraImports}}
+	{{.}}
+	{{- end}}
 )
 
 func init(){
 	sqlc.RegisterSchema(&{{.ModelName}})
+	{{- range .Relations}}
+	sqlc.RegisterRelation("{{.FieldName}}", {{$.ModelName}}_{{.FieldName}})
+	{{- end}}
 }
 
 // {{.ModelName}} Schema:
@@ -158,14 +167,12 @@ func (s *{{.SchemaStructName}}) SoftDeleteColumn() string {
 
 func (s *{{.SchemaStructName}}) SoftDeleteValue() any {
 	{{- if .SoftDeleteField}}
-	{{- if or (eq .SoftDeleteFieldType "*time.Time") (eq .SoftDeleteFieldType "time.Time") (eq .SoftDeleteFieldType "sql.NullTime")}}
-	return time.Now()
-	{{- else if or (eq .SoftDeleteFieldType "int64") (eq .SoftDeleteFieldType "uint64")}}
-	return time.Now().Unix()
-	{{- else if or (eq .SoftDeleteFieldType "int32") (eq .SoftDeleteFieldType "uint32")}}
-	return int32(time.Now().Unix())
+	{{- if eq .SoftDeleteFieldType "bool"}}
+	return true
+	{{- else if .SoftDeleteIsUnix}}
+	return {{.SoftDeleteUnixNow}}
 	{{- else}}
-	return time.Now() // Default fallback
+	return time.Now()
 	{{- end}}
 	{{- else}}
 	return nil
@@ -176,10 +183,10 @@ func (s *{{.SchemaStructName}}) SetDeletedAt(m *{{.ParentPackage}}.{{.ModelName}
 	{{- if .SoftDeleteField}}
 	{{- if eq .SoftDeleteFieldType "sql.NullTime"}}
 	m.{{.SoftDeleteField}} = sql.NullTime{Time: time.Now(), Valid: true}
-	{{- else if or (eq .SoftDeleteFieldType "int64") (eq .SoftDeleteFieldType "uint64")}}
-	m.{{.SoftDeleteField}} = time.Now().Unix()
-	{{- else if or (eq .SoftDeleteFieldType "int32") (eq .SoftDeleteFieldType "uint32")}}
-	m.{{.SoftDeleteField}} = int32(time.Now().Unix())
+	{{- else if eq .SoftDeleteFieldType "bool"}}
+	m.{{.SoftDeleteField}} = true
+	{{- else if .SoftDeleteIsUnix}}
+	m.{{.SoftDeleteField}} = {{.SoftDeleteUnixNow}}
 	{{- else if hasPrefix .SoftDeleteFieldType "*"}}
 	now := time.Now()
 	m.{{.SoftDeleteField}} = &now
@@ -188,6 +195,126 @@ func (s *{{.SchemaStructName}}) SetDeletedAt(m *{{.ParentPackage}}.{{.ModelName}
 	{{- end}}
 	{{- end}}
 }
+{{- if eq .SoftDeleteFieldType "bool"}}
+
+// SoftDeleteActiveValue implements sqlc.SoftDeleteActive: live records have {{.SoftDeleteColumn}} = false
+func (s *{{.SchemaStructName}}) SoftDeleteActiveValue() any {
+	return false
+}
+{{- else if .SoftDeleteIsUnix}}
+
+// SoftDeleteActiveValue implements sqlc.SoftDeleteActive: live records have {{.SoftDeleteColumn}} = 0
+func (s *{{.SchemaStructName}}) SoftDeleteActiveValue() any {
+	return 0
+}
+{{- end}}
+{{- with .DefaultOrder}}
+
+// DefaultOrder implements sqlc.DefaultOrderer: queries without OrderBy are sorted by these columns
+func (s *{{$.SchemaStructName}}) DefaultOrder() []clause.OrderByColumn {
+	return []clause.OrderByColumn{
+		{{- range .}}
+		{Column: clause.Column{Name: "{{.Column}}"}{{if .Desc}}, Desc: true{{end}}},
+		{{- end}}
+	}
+}
+{{- end}}
+{{- with .ValidatedFields}}
+
+// ValidationRules implements sqlc.ValidatedSchema: the validate tags of {{$.ModelName}}, checked before writes
+func (s *{{$.SchemaStructName}}) ValidationRules() []sqlc.FieldRule[{{$.ParentPackage}}.{{$.ModelName}}] {
+	return []sqlc.FieldRule[{{$.ParentPackage}}.{{$.ModelName}}]{
+		{{- range .}}
+		{Field: "{{.FieldName}}", Column: "{{.Column}}", Rules: {{printf "%q" .Validate}}, Value: func(m *{{$.ParentPackage}}.{{$.ModelName}}) any { return m.{{.FieldName}} }},
+		{{- end}}
+	}
+}
+{{- end}}
+{{- if .PartitionCount}}
+
+// {{.ModelName}}Partitions is the number of hash partitions of the {{.TableName}} table
+const {{.ModelName}}Partitions = {{.PartitionCount}}
+
+// {{.ModelName}}PartitionTables lists the physical partition tables of {{.TableName}}, in partition order.
+// Each table shares the column layout of {{.ModelName}}.
+var {{.ModelName}}PartitionTables = []string{
+	{{- range .PartitionTables}}
+	"{{.}}",
+	{{- end}}
+}
+
+// New{{.ModelName}}PartitionedRepository returns a repository routing {{.ModelName}} rows by {{.PartitionKeyField}}
+func New{{.ModelName}}PartitionedRepository(session *sqlc.Session) *sqlc.PartitionedRepository[{{.ParentPackage}}.{{.ModelName}}, {{.QualifyType .PartitionKeyType}}] {
+	return sqlc.NewPartitionedRepository(session, {{.ModelName}}Partitions,
+		func(m *{{.ParentPackage}}.{{.ModelName}}) {{.QualifyType .PartitionKeyType}} { return m.{{.PartitionKeyField}} },
+	)
+}
+{{- end}}
+{{- range .Counters}}
+{{- $name := printf "%s%sCounter" $.ModelName .FieldName}}
+
+// {{$name}}Table is the sharded counter table backing {{$.ModelName}}.{{.FieldName}}
+const {{$name}}Table = "{{$.TableName}}_{{.Column}}_counter"
+
+// {{$name}}DDL creates {{$name}}Table
+var {{$name}}DDL = sqlc.ShardedCounterDDL({{$name}}Table)
+
+// New{{$name}} returns the sharded counter for {{$.ModelName}}.{{.FieldName}} ({{.Shards}} shards)
+func New{{$name}}(session *sqlc.Session) *sqlc.ShardedCounter {
+	return sqlc.NewShardedCounter(session, {{$name}}Table, {{.Shards}})
+}
+{{- end}}
+{{- with .SortableFields}}
+
+// {{$.ModelName}}SortableFields whitelists the {{$.ModelName}} fields that may be used for
+// ordering and filtering from external input, keyed by column name (e.g. ?sort=created_at)
+var {{$.ModelName}}SortableFields = map[string]clause.Columnar{
+	{{- range .}}
+	"{{.Column}}": {{$.ModelName}}.{{.FieldName}},
+	{{- end}}
+}
+{{- end}}
+{{- range .Partials}}
+{{- $name := printf "%s%s" $.ModelName .Name}}
+
+// {{$name}} is a partial of {{$.ModelName}} holding only its {{range $i, $f := .Fields}}{{if $i}}, {{end}}{{$f.Column}}{{end}} columns,
+// for narrow reads with sqlc.FindPartial and sqlc.FirstPartial
+type {{$name}} struct {
+	{{- range .Fields}}
+	{{.FieldName}} {{$.QualifyFieldType .Type}} ` + "`" + `db:"{{.Column}}"` + "`" + `
+	{{- end}}
+}
+
+// SelectColumns implements sqlc.Partial
+func ({{$name}}) SelectColumns() []string {
+	return []string{
+		{{- range .Fields}}
+		"{{.Column}}",
+		{{- end}}
+	}
+}
+{{- end}}
+{{- if .Patch}}
+
+// {{.ModelName}}Patch is a partial update of {{.ModelName}}, decoded from PATCH request bodies:
+// nil fields are left unchanged, the others are assigned by ApplyTo
+type {{.ModelName}}Patch struct {
+	{{- range .PatchFields}}
+	{{.FieldName}} {{$.PatchFieldType .}} ` + "`" + `json:"{{or .JSONName .FieldName}},omitempty"` + "`" + `
+	{{- end}}
+}
+
+// ApplyTo appends an assignment of each non-nil field of p to assignments,
+// for Repository.UpdateColumns
+func (p *{{.ModelName}}Patch) ApplyTo(assignments []clause.Assignment) []clause.Assignment {
+	{{- range .PatchFields}}
+	if p.{{.FieldName}} != nil {
+		assignments = append(assignments, clause.Assignment{Column: {{$.ModelName}}.{{.FieldName}}.Column(), Value: {{if hasPrefix .Type "*"}}p.{{.FieldName}}{{else}}*p.{{.FieldName}}{{end}}})
+	}
+	{{- end}}
+	return assignments
+}
+{{- end}}
 {{end}}
 {{- range .JSONFields}}
 {{- $col := .ColumnName}}
@@ -203,19 +330,27 @@ var {{.TypeName}} = struct {
 }
 {{end}}
 {{- range .Relations}}
-// {{$.ModelName}}_{{.FieldName}} defines {{.RelType}} relation: {{$.ModelName}} has {{if eq .RelType "hasMany"}}many{{else}}one{{end}} {{.TargetType}}
-var {{$.ModelName}}_{{.FieldName}} = sqlc.{{if eq .RelType "hasMany"}}HasMany{{else}}HasOne{{end}}(
-	{{if eq .RelType "belongsTo"}}clause.Column{Name: "{{.LocalKey}}"},
+// {{$.ModelName}}_{{.FieldName}} defines {{.RelType}} relation: {{$.ModelName}} {{if eq .RelType "belongsTo"}}belongs to{{else if eq .RelType "hasMany"}}has many{{else if eq .RelType "many2many"}}has many{{else}}has one{{end}} {{.TargetType}}{{if eq .RelType "many2many"}} through {{.JoinTable}}{{end}}
+var {{$.ModelName}}_{{.FieldName}} = sqlc.{{if eq .RelType "belongsTo"}}BelongsTo{{else if eq .RelType "hasMany"}}HasMany{{else if .Through}}HasManyThrough{{else if eq .RelType "many2many"}}ManyToMany{{else}}HasOne{{end}}(
+	{{if eq .RelType "many2many"}}"{{.JoinTable}}",
 	clause.Column{Name: "{{.ForeignKey}}"},
+	clause.Column{Name: "{{.JoinReferences}}"},
+	clause.Column{Name: "{{.LocalKey}}"},
+	clause.Column{Name: "{{or .TargetKey "id"}}"},
+	func(p *{{$.ParentPackage}}.{{$.ModelName}}, children []*{{$.ParentPackage}}.{{.TargetType}}) { p.{{.FieldName}} = children },
+	func(p *{{$.ParentPackage}}.{{$.ModelName}}) {{$.PKFieldType}} { return p.{{$.PKFieldName}} },
+	func(c *{{$.ParentPackage}}.{{.TargetType}}) {{$.PKFieldType}} { return c.{{.TargetPKField}} },
+	{{else if eq .RelType "belongsTo"}}clause.Column{Name: "{{.ForeignKey}}"},
+	clause.Column{Name: "{{.LocalKey}}"},
 	func(p *{{$.ParentPackage}}.{{$.ModelName}}, child *{{$.ParentPackage}}.{{.TargetType}}) { p.{{.FieldName}} = child },
-	func(p *{{$.ParentPackage}}.{{$.ModelName}}) {{$.PKFieldType}} { return p.{{.ForeignKeyField}} },
+	func(p *{{$.ParentPackage}}.{{$.ModelName}}) {{$.PKFieldType}} { {{.ForeignKeyReturn "p" $.PKFieldType}} },
 	func(c *{{$.ParentPackage}}.{{.TargetType}}) {{$.PKFieldType}} { return c.{{.TargetPKField}} },
 	{{else}}clause.Column{Name: "{{.ForeignKey}}"},
 	clause.Column{Name: "{{.LocalKey}}"},
 	{{if eq .RelType "hasMany"}}func(p *{{$.ParentPackage}}.{{$.ModelName}}, children []*{{$.ParentPackage}}.{{.TargetType}}) { p.{{.FieldName}} = children },
 	{{else}}func(p *{{$.ParentPackage}}.{{$.ModelName}}, child *{{$.ParentPackage}}.{{.TargetType}}) { p.{{.FieldName}} = child },
 	{{end}}func(p *{{$.ParentPackage}}.{{$.ModelName}}) {{$.PKFieldType}} { return p.{{$.PKFieldName}} },
-	func(c *{{$.ParentPackage}}.{{.TargetType}}) {{$.PKFieldType}} { return {{if .ForeignKeyFieldType}}{{.ForeignKeyFieldType}}(c.{{.ForeignKeyField}}){{else}}c.{{.ForeignKeyField}}{{end}} },
+	func(c *{{$.ParentPackage}}.{{.TargetType}}) {{$.PKFieldType}} { {{.ForeignKeyReturn "c" $.PKFieldType}} },
 	{{end}}
 )
 {{end}}
@@ -232,6 +367,13 @@ func GenerateFile(meta ModelMeta, outDir string) error {
 	// Populate dynamic fields
 	meta.CliVersion = Version
 
+	// Reject invalid validate tags here rather than on the first write
+	for _, f := range meta.ValidatedFields() {
+		if err := sqlc.CheckValidationTag(f.Validate); err != nil {
+			return fmt.Errorf("field %s: %w", f.FieldName, err)
+		}
+	}
+
 	for _, f := range meta.Fields {
 		if strings.Contains(meta.GetFieldType(f.Type), "field.JSON") {
 			meta.HasJSON = true
@@ -251,6 +393,10 @@ func GenerateFile(meta ModelMeta, outDir string) error {
 		}
 	}
 
+	for i := 0; i < meta.PartitionCount; i++ {
+		meta.PartitionTables = append(meta.PartitionTables, fmt.Sprintf("%s_%d", meta.TableName, i))
+	}
+
 	funcMap := template.FuncMap{
 		"hasPrefix": strings.HasPrefix,
 	}
@@ -317,10 +463,16 @@ func (m ModelMeta) GetFieldType(goType string) string {
 		return fmt.Sprintf("field.Field[%s]", typeName)
 	}
 
-	// 4. Map to field types
+	// 4. Pointers of nullable columns (e.g. *int64 parent_id) use the generic Field[*T];
+	// *time.Time maps to field.Time below
+	if strings.HasPrefix(resolvedType, "*") && resolvedType != "*time.Time" {
+		return fmt.Sprintf("field.Field[%s]", m.QualifyFieldType(goType))
+	}
+
+	// 5. Map to field types
 	fieldType := m.mapToFieldType(resolvedType)
 
-	// 5. If mapToFieldType returns basic "field.Field[any]", upgrade to generic "field.Field[T]" if possible
+	// 6. If mapToFieldType returns basic "field.Field[any]", upgrade to generic "field.Field[T]" if possible
 	if fieldType == "field.Field[any]" {
 		// Check if it's a struct or something we can use generic Field for?
 		// For now, let's assume unknown types are better off as field.Field[Type] if they are not standard.
@@ -424,6 +576,88 @@ func (m ModelMeta) QualifyPKType() string {
 	return typ
 }
 
+// QualifyType returns a Go type name usable from the generated package.
+// Built-in types are returned as-is; types declared in the model package are qualified.
+func (m ModelMeta) QualifyType(typ string) string {
+	if _, isAlias := m.TypeAliases[typ]; !isAlias && (m.isBuiltin(typ) || m.IsNumeric(typ)) {
+		return typ
+	}
+	if !strings.Contains(typ, ".") && m.ParentPackage != "" {
+		return m.ParentPackage + "." + typ
+	}
+	return typ
+}
+
+// QualifyFieldType returns a field's Go type usable from the generated package,
+// qualifying model package types behind pointer, slice and map prefixes (e.g. *Status -> *models.Status).
+func (m ModelMeta) QualifyFieldType(typ string) string {
+	for _, prefix := range []string{"*", "[]"} {
+		if rest, ok := strings.CutPrefix(typ, prefix); ok {
+			return prefix + m.QualifyFieldType(rest)
+		}
+	}
+	if key, elem, ok := strings.Cut(strings.TrimPrefix(typ, "map["), "]"); ok && strings.HasPrefix(typ, "map[") {
+		return "map[" + m.QualifyFieldType(key) + "]" + m.QualifyFieldType(elem)
+	}
+	if typ == "any" || typ == "interface{}" || typ == "[]byte" {
+		return typ
+	}
+	return m.QualifyType(typ)
+}
+
+// ExtraImports returns the import specs needed by the field types of the model's
+// partials and patch struct that the schema template does not import already.
+func (m ModelMeta) ExtraImports() []string {
+	imported := map[string]bool{
+		"github.com/arllen133/sqlc":        true,
+		"github.com/arllen133/sqlc/clause": true,
+		"github.com/arllen133/sqlc/field":  true,
+		"encoding/json":                    m.HasJSON,
+		"time":                             m.SoftDeleteField != "" && m.SoftDeleteFieldType != "bool",
+		"database/sql":                     m.SoftDeleteFieldType == "sql.NullTime",
+	}
+	var types []string
+	for _, p := range m.Partials {
+		for _, f := range p.Fields {
+			types = append(types, m.QualifyFieldType(f.Type))
+		}
+	}
+	if m.Patch {
+		for _, f := range m.PatchFields() {
+			types = append(types, m.PatchFieldType(f))
+		}
+	}
+	var specs []string
+	for _, typ := range types {
+		pkg, _, ok := strings.Cut(strings.TrimLeft(typ, "*[]"), ".")
+		path, known := m.FileImports[pkg]
+		if !ok || !known || imported[path] {
+			continue
+		}
+		imported[path] = true
+		spec := strconv.Quote(path)
+		if path[strings.LastIndex(path, "/")+1:] != pkg {
+			spec = pkg + " " + spec
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// PatchFieldType returns the type of a field in the model's Patch struct: a pointer
+// to the field's qualified type, or the type itself if it is a pointer already
+// (e.g. string -> *string, sqlc.JSON[Settings] -> *sqlc.JSON[models.Settings]).
+func (m ModelMeta) PatchFieldType(f FieldMeta) string {
+	typ := m.QualifyFieldType(f.Type)
+	if wrapper, _, ok := strings.Cut(f.Type, "["); ok && f.IsJSON && f.JSONTypeName != "" {
+		typ = wrapper + "[" + m.ParentPackage + "." + f.JSONTypeName + "]"
+	}
+	if strings.HasPrefix(f.Type, "*") {
+		return typ
+	}
+	return "*" + typ
+}
+
 // GoIsNonZero returns the Go expression to check if a field is NOT zero value
 func (m ModelMeta) GoIsNonZero(fieldName, goType string) string {
 	if strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map") {
@@ -471,7 +705,7 @@ var {{$.ModelName}}_{{.FieldName}} = sqlc.{{if eq .RelType "hasMany"}}HasMany{{e
 	{{if eq .RelType "hasMany"}}func(p *{{$.ParentPackage}}.{{$.ModelName}}, children []*{{$.ParentPackage}}.{{.TargetType}}) { p.{{.FieldName}} = children },
 	{{else}}func(p *{{$.ParentPackage}}.{{$.ModelName}}, child *{{$.ParentPackage}}.{{.TargetType}}) { p.{{.FieldName}} = child },
 	{{end}}func(p *{{$.ParentPackage}}.{{$.ModelName}}) {{$.PKFieldType}} { return p.{{$.PKFieldName}} },
-	func(c *{{$.ParentPackage}}.{{.TargetType}}) {{$.PKFieldType}} { return {{if .ForeignKeyFieldType}}{{.ForeignKeyFieldType}}(c.{{.ForeignKeyField}}){{else}}c.{{.ForeignKeyField}}{{end}} },
+	func(c *{{$.ParentPackage}}.{{.TargetType}}) {{$.PKFieldType}} { {{.ForeignKeyReturn "c" $.PKFieldType}} },
 )
 {{end}}
 `
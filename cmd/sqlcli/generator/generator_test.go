@@ -0,0 +1,831 @@
+package generator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc/cmd/sqlcli/generator"
+)
+
+func TestGenerateFile_Partitioned(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "Event",
+		TableName:        "events",
+		SchemaStructName: "eventSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "UserID", Column: "user_id", Type: "UserID"},
+		},
+		PKFieldName:       "ID",
+		PKColumnName:      "id",
+		PKFieldType:       "int64",
+		PartitionCount:    3,
+		PartitionKeyField: "UserID",
+		PartitionKeyType:  "UserID",
+		TypeAliases:       map[string]string{"UserID": "int64"},
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "event_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		"const EventPartitions = 3",
+		`"events_0",`,
+		`"events_2",`,
+		"func NewEventPartitionedRepository(session *sqlc.Session) *sqlc.PartitionedRepository[models.Event, models.UserID]",
+		"return m.UserID",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, `"events_3"`) {
+		t.Errorf("generated code has too many partition tables\n%s", src)
+	}
+}
+
+func TestGenerateFile_NotPartitioned(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "User",
+		TableName:        "users",
+		SchemaStructName: "userSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if strings.Contains(string(content), "Partition") {
+		t.Errorf("unpartitioned model must not emit partition code\n%s", content)
+	}
+}
+
+func TestGenerateFile_Counters(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "Post",
+		TableName:        "posts",
+		SchemaStructName: "postSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "Views", Column: "views", Type: "int64"},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+		Counters: []generator.CounterMeta{
+			{FieldName: "Views", Column: "views", Shards: 16},
+			{FieldName: "Likes", Column: "likes", Shards: 4},
+		},
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "post_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		`const PostViewsCounterTable = "posts_views_counter"`,
+		"var PostViewsCounterDDL = sqlc.ShardedCounterDDL(PostViewsCounterTable)",
+		"func NewPostViewsCounter(session *sqlc.Session) *sqlc.ShardedCounter",
+		"sqlc.NewShardedCounter(session, PostViewsCounterTable, 16)",
+		"sqlc.NewShardedCounter(session, PostLikesCounterTable, 4)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateFile_SortableFields(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "User",
+		TableName:        "users",
+		SchemaStructName: "userSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "Email", Column: "email", Type: "string"},
+			{FieldName: "CreatedAt", Column: "created_at", Type: "time.Time", Sortable: true},
+			{FieldName: "Name", Column: "name", Type: "string", Sortable: true},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		"var UserSortableFields = map[string]clause.Columnar{",
+		`"created_at": User.CreatedAt,`,
+		`"name":       User.Name,`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, `"email": User.Email`) {
+		t.Errorf("untagged field must not be sortable\n%s", src)
+	}
+
+	// Models without sortable fields emit no whitelist
+	meta.Fields = meta.Fields[:2]
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if strings.Contains(string(content), "SortableFields") {
+		t.Errorf("unexpected whitelist without sortable fields\n%s", content)
+	}
+}
+
+func TestGenerateFile_ValidationRules(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+type User struct {
+	ID     int64  ` + "`db:\"id,primaryKey,autoIncrement\"`" + `
+	Email  string ` + "`db:\"email\" validate:\"required,email\"`" + `
+	Status string ` + "`db:\"status\" validate:\"oneof=active suspended\"`" + `
+	Bio    string ` + "`db:\"bio\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write models: %v", err)
+	}
+	models, err := generator.ParseModels(dir)
+	if err != nil {
+		t.Fatalf("ParseModels failed: %v", err)
+	}
+	if len(models) != 1 || len(models[0].ValidatedFields()) != 2 {
+		t.Fatalf("expected 2 validated fields, got %+v", models)
+	}
+	user := models[0]
+	user.ModulePath = "example.com/app"
+	user.PackagePath = "models"
+	if err := generator.GenerateFile(user, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, want := range []string{
+		"func (s *userSchema) ValidationRules() []sqlc.FieldRule[models.User] {",
+		`{Field: "Email", Column: "email", Rules: "required,email", Value: func(m *models.User) any { return m.Email }},`,
+		`{Field: "Status", Column: "status", Rules: "oneof=active suspended", Value: func(m *models.User) any { return m.Status }},`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("generated code missing %q\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), `Field: "Bio"`) {
+		t.Errorf("untagged field must not be validated\n%s", content)
+	}
+
+	// Invalid tags fail generation
+	user.Fields[1].Validate = "required,maxlen=10"
+	if err := generator.GenerateFile(user, dir); err == nil || !strings.Contains(err.Error(), "Email") {
+		t.Errorf("expected an error naming the field, got %v", err)
+	}
+}
+
+func TestGenerateFile_SoftDeleteFlag(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "Post",
+		TableName:        "posts",
+		SchemaStructName: "postSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "DeletedAt", Column: "deleted_at", Type: "*time.Time"},
+			{FieldName: "IsDeleted", Column: "is_deleted", Type: "bool"},
+		},
+		PKFieldName:         "ID",
+		PKColumnName:        "id",
+		PKFieldType:         "int64",
+		SoftDeleteField:     "DeletedAt",
+		SoftDeleteColumn:    "deleted_at",
+		SoftDeleteFieldType: "*time.Time",
+	}
+	if err := meta.SetSoftDeleteColumn("is_deleted"); err != nil {
+		t.Fatalf("SetSoftDeleteColumn failed: %v", err)
+	}
+	if err := meta.SetSoftDeleteColumn("missing"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "post_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		`return "is_deleted"`,
+		"m.IsDeleted = true",
+		"func (s *postSchema) SoftDeleteActiveValue() any {\n\treturn false\n}",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, `"time"`) {
+		t.Errorf("flag soft delete must not import time\n%s", src)
+	}
+}
+
+func TestGenerateFile_SoftDeleteUnixMilli(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "Event",
+		TableName:        "events",
+		SchemaStructName: "eventSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "Name", Column: "name", Type: "string"},
+			{FieldName: "DeletedAt", Column: "deleted_at", Type: "int64"},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+	}
+	for _, bad := range []string{"name:unixMilli", "deleted_at:unixNano"} {
+		if err := meta.SetSoftDeleteColumn(bad); err == nil {
+			t.Errorf("expected error for soft delete spec %q", bad)
+		}
+	}
+	if err := meta.SetSoftDeleteColumn("deleted_at:unixMilli"); err != nil {
+		t.Fatalf("SetSoftDeleteColumn failed: %v", err)
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "event_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		`return "deleted_at"`,
+		"func (s *eventSchema) SoftDeleteValue() any {\n\treturn time.Now().UnixMilli()\n}",
+		"m.DeletedAt = time.Now().UnixMilli()",
+		"func (s *eventSchema) SoftDeleteActiveValue() any {\n\treturn 0\n}",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateFile_DefaultOrder(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModelName:        "Post",
+		TableName:        "posts",
+		SchemaStructName: "postSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "CreatedAt", Column: "created_at", Type: "time.Time"},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+	}
+	for _, bad := range []string{"missing DESC", "created_at SIDEWAYS", "created_at DESC,", "created_at DESC id"} {
+		if err := meta.SetDefaultOrder(bad); err == nil {
+			t.Errorf("expected error for default order %q", bad)
+		}
+	}
+	if err := meta.SetDefaultOrder("created_at desc, id"); err != nil {
+		t.Fatalf("SetDefaultOrder failed: %v", err)
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "post_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	want := `func (s *postSchema) DefaultOrder() []clause.OrderByColumn {
+	return []clause.OrderByColumn{
+		{Column: clause.Column{Name: "created_at"}, Desc: true},
+		{Column: clause.Column{Name: "id"}},
+	}
+}`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("generated code missing default order\n%s", content)
+	}
+}
+
+func TestGenerateFile_Partials(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModulePath:       "example.com/app",
+		PackagePath:      "models",
+		ModelName:        "User",
+		TableName:        "users",
+		SchemaStructName: "userSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "Username", Column: "username", Type: "string"},
+			{FieldName: "Role", Column: "role", Type: "Role"},
+			{FieldName: "Bio", Column: "bio", Type: "string"},
+			{FieldName: "LastLogin", Column: "last_login", Type: "sql.NullTime"},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+		TypeAliases:  map[string]string{"Role": "string"},
+		FileImports:  map[string]string{"sql": "database/sql"},
+	}
+	for _, bad := range []string{"Identity id", "identity: id", "Identity: id, missing", "A: id; A: username"} {
+		if err := meta.SetPartials(bad); err == nil {
+			t.Errorf("expected error for partials %q", bad)
+		}
+	}
+	if err := meta.SetPartials("Identity: id, username, role; Activity: id, last_login"); err != nil {
+		t.Fatalf("SetPartials failed: %v", err)
+	}
+
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		`type UserIdentity struct {
+	ID       int64       ` + "`db:\"id\"`" + `
+	Username string      ` + "`db:\"username\"`" + `
+	Role     models.Role ` + "`db:\"role\"`" + `
+}`,
+		`func (UserIdentity) SelectColumns() []string {
+	return []string{
+		"id",
+		"username",
+		"role",
+	}
+}`,
+		`type UserActivity struct {`,
+		`"database/sql"`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "Bio string `db") {
+		t.Errorf("partials must not include unlisted columns\n%s", src)
+	}
+}
+
+func TestGenerateFile_Relations(t *testing.T) {
+	dir := t.TempDir()
+
+	meta := generator.ModelMeta{
+		PackageName:      "generated",
+		ParentPackage:    "models",
+		ModulePath:       "example.com/app",
+		PackagePath:      "models",
+		ModelName:        "User",
+		TableName:        "users",
+		SchemaStructName: "userSchema",
+		Fields: []generator.FieldMeta{
+			{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+			{FieldName: "OrgID", Column: "org_id", Type: "int64"},
+		},
+		PKFieldName:  "ID",
+		PKColumnName: "id",
+		PKFieldType:  "int64",
+		Relations: []generator.RelationMeta{
+			{FieldName: "Posts", RelType: "hasMany", ForeignKey: "user_id", LocalKey: "id", TargetType: "Post", TargetSlice: true, ForeignKeyField: "UserID"},
+			{FieldName: "Org", RelType: "belongsTo", ForeignKey: "org_id", LocalKey: "id", TargetType: "Org", ForeignKeyField: "OrgID", TargetPKField: "ID"},
+		},
+	}
+	if err := generator.GenerateFile(meta, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		`var User_Posts = sqlc.HasMany(`,
+		`sqlc.RegisterRelation("Posts", User_Posts)`,
+		`var User_Org = sqlc.BelongsTo(
+	clause.Column{Name: "org_id"},
+	clause.Column{Name: "id"},
+	func(p *models.User, child *models.Org) { p.Org = child },
+	func(p *models.User) int64 { return p.OrgID },
+	func(c *models.Org) int64 { return c.ID },
+)`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateRelationTable(t *testing.T) {
+	dir := t.TempDir()
+
+	models := []generator.ModelMeta{
+		{
+			PackageName: "generated", ModelName: "User", TableName: "users",
+			Relations: []generator.RelationMeta{
+				{FieldName: "Posts", RelType: "hasMany", ForeignKey: "user_id", LocalKey: "id", TargetType: "Post", TargetSlice: true},
+			},
+		},
+		{
+			PackageName: "generated", ModelName: "Post", TableName: "posts",
+			Relations: []generator.RelationMeta{
+				{FieldName: "Author", RelType: "belongsTo", ForeignKey: "user_id", LocalKey: "id", TargetType: "User"},
+			},
+		},
+	}
+	if err := generator.GenerateRelationTable(models, dir); err != nil {
+		t.Fatalf("GenerateRelationTable failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "relations_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	src := string(content)
+
+	for _, want := range []string{
+		`var Relations = sqlc.RelationTable{`,
+		`{Model: "User", Table: "users", Name: "Posts", Kind: sqlc.KindHasMany, Target: "Post", TargetTable: "posts", ForeignKey: "user_id", References: "id"},`,
+		`{Model: "Post", Table: "posts", Name: "Author", Kind: sqlc.KindBelongsTo, Target: "User", TargetTable: "users", ForeignKey: "user_id", References: "id"},`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated code missing %q\n%s", want, src)
+		}
+	}
+
+	// No relations: no file
+	empty := t.TempDir()
+	if err := generator.GenerateRelationTable([]generator.ModelMeta{{PackageName: "generated", ModelName: "Tag", TableName: "tags"}}, empty); err != nil {
+		t.Fatalf("GenerateRelationTable failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(empty, "generated", "relations_gen.go")); !os.IsNotExist(err) {
+		t.Errorf("expected no relations file, got %v", err)
+	}
+}
+
+func TestGenerateFile_ManyToMany(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+type User struct {
+	ID    int64   ` + "`db:\"id,primaryKey,autoIncrement\"`" + `
+	Roles []*Role ` + "`db:\"-\" relation:\"many2many,joinTable:user_roles\"`" + `
+}
+
+type Role struct {
+	ID    int64   ` + "`db:\"id,primaryKey,autoIncrement\"`" + `
+	Users []*User ` + "`db:\"-\" relation:\"many2many,joinTable:user_roles,foreignKey:role_id,references:user_id\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write models: %v", err)
+	}
+	models, err := generator.ParseModels(dir)
+	if err != nil {
+		t.Fatalf("ParseModels failed: %v", err)
+	}
+	generator.ResolveRelationFields(models)
+
+	var user generator.ModelMeta
+	for _, m := range models {
+		if m.ModelName == "User" {
+			user = m
+		}
+	}
+	if len(user.Relations) != 1 {
+		t.Fatalf("expected 1 relation on User, got %+v", user.Relations)
+	}
+	want := generator.RelationMeta{
+		FieldName: "Roles", RelType: "many2many", ForeignKey: "user_id", LocalKey: "id", TargetType: "Role", TargetSlice: true,
+		TargetPKField: "ID", JoinTable: "user_roles", JoinReferences: "role_id", TargetKey: "id",
+	}
+	if user.Relations[0] != want {
+		t.Errorf("unexpected relation:\ngot  %+v\nwant %+v", user.Relations[0], want)
+	}
+
+	user.ModulePath = "example.com/app"
+	user.PackagePath = "models"
+	if err := generator.GenerateFile(user, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	if err := generator.GenerateRelationTable(models, dir); err != nil {
+		t.Fatalf("GenerateRelationTable failed: %v", err)
+	}
+
+	for file, wants := range map[string][]string{
+		"user_gen.go": {`var User_Roles = sqlc.ManyToMany(
+	"user_roles",
+	clause.Column{Name: "user_id"},
+	clause.Column{Name: "role_id"},
+	clause.Column{Name: "id"},
+	clause.Column{Name: "id"},
+	func(p *models.User, children []*models.Role) { p.Roles = children },
+	func(p *models.User) int64 { return p.ID },
+	func(c *models.Role) int64 { return c.ID },
+)`},
+		"relations_gen.go": {
+			`{Model: "User", Table: "users", Name: "Roles", Kind: sqlc.KindManyToMany, Target: "Role", TargetTable: "roles", ForeignKey: "user_id", References: "id", JoinTable: "user_roles", JoinReferences: "role_id"},`,
+			`{Model: "Role", Table: "roles", Name: "Users", Kind: sqlc.KindManyToMany, Target: "User", TargetTable: "users", ForeignKey: "role_id", References: "id", JoinTable: "user_roles", JoinReferences: "user_id"},`,
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(dir, "generated", file))
+		if err != nil {
+			t.Fatalf("failed to read generated file: %v", err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s missing %q\n%s", file, want, content)
+			}
+		}
+	}
+}
+
+func TestGenerateFile_HasManyThrough(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+type User struct {
+	ID    int64   ` + "`db:\"id,primaryKey,autoIncrement\"`" + `
+	Teams []*Team ` + "`db:\"-\" relation:\"hasManyThrough,through:memberships,throughKey:team_id\"`" + `
+}
+
+type Team struct {
+	ID int64 ` + "`db:\"id,primaryKey,autoIncrement\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write models: %v", err)
+	}
+	models, err := generator.ParseModels(dir)
+	if err != nil {
+		t.Fatalf("ParseModels failed: %v", err)
+	}
+	generator.ResolveRelationFields(models)
+
+	var user generator.ModelMeta
+	for _, m := range models {
+		if m.ModelName == "User" {
+			user = m
+		}
+	}
+	want := generator.RelationMeta{
+		FieldName: "Teams", RelType: "many2many", Through: true, ForeignKey: "user_id", LocalKey: "id", TargetType: "Team", TargetSlice: true,
+		TargetPKField: "ID", JoinTable: "memberships", JoinReferences: "team_id", TargetKey: "id",
+	}
+	if len(user.Relations) != 1 || user.Relations[0] != want {
+		t.Fatalf("unexpected relations:\ngot  %+v\nwant %+v", user.Relations, want)
+	}
+
+	user.ModulePath = "example.com/app"
+	user.PackagePath = "models"
+	if err := generator.GenerateFile(user, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	wantCode := `var User_Teams = sqlc.HasManyThrough(
+	"memberships",
+	clause.Column{Name: "user_id"},
+	clause.Column{Name: "team_id"},
+	clause.Column{Name: "id"},
+	clause.Column{Name: "id"},
+	func(p *models.User, children []*models.Team) { p.Teams = children },`
+	if !strings.Contains(string(content), wantCode) {
+		t.Errorf("user_gen.go missing %q\n%s", wantCode, content)
+	}
+}
+
+func TestGenerateFile_Patch(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+import "time"
+
+type User struct {
+	ID        int64      ` + "`db:\"id,primaryKey,autoIncrement\" json:\"id\"`" + `
+	Name      string     ` + "`db:\"name\" json:\"name\"`" + `
+	Verified  *time.Time ` + "`db:\"verified_at\" json:\"verifiedAt,omitempty\"`" + `
+	Password  string     ` + "`db:\"password_hash\" json:\"-\"`" + `
+	Age       int        ` + "`db:\"age\"`" + `
+	LastLogin time.Time  ` + "`db:\"last_login\" json:\"lastLogin\"`" + `
+	DeletedAt *time.Time ` + "`db:\"deleted_at\" json:\"deletedAt\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write models: %v", err)
+	}
+	models, err := generator.ParseModels(dir)
+	if err != nil {
+		t.Fatalf("ParseModels failed: %v", err)
+	}
+	if len(models) != 1 {
+		t.Fatalf("expected 1 model, got %d", len(models))
+	}
+	user := models[0]
+	user.ModulePath = "example.com/app"
+	user.PackagePath = "models"
+
+	// Without the Patches config no patch struct is generated
+	if err := generator.GenerateFile(user, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	if strings.Contains(string(content), "UserPatch") {
+		t.Errorf("unexpected patch struct without config\n%s", content)
+	}
+
+	user.Patch = true
+	if err := generator.GenerateFile(user, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(dir, "generated", "user_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+	for _, want := range []string{
+		`type UserPatch struct {
+	Name      *string    ` + "`json:\"name,omitempty\"`" + `
+	Verified  *time.Time ` + "`json:\"verifiedAt,omitempty\"`" + `
+	Age       *int       ` + "`json:\"Age,omitempty\"`" + `
+	LastLogin *time.Time ` + "`json:\"lastLogin,omitempty\"`" + `
+}`,
+		`func (p *UserPatch) ApplyTo(assignments []clause.Assignment) []clause.Assignment {
+	if p.Name != nil {
+		assignments = append(assignments, clause.Assignment{Column: User.Name.Column(), Value: *p.Name})
+	}
+	if p.Verified != nil {
+		assignments = append(assignments, clause.Assignment{Column: User.Verified.Column(), Value: p.Verified})
+	}`,
+		`"time"`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("user_gen.go missing %q\n%s", want, content)
+		}
+	}
+}
+
+func TestGenerateFile_SelfRelation(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+type Category struct {
+	ID       int64       ` + "`db:\"id,primaryKey,autoIncrement\"`" + `
+	Name     string      ` + "`db:\"name\"`" + `
+	ParentID *int64      ` + "`db:\"parent_id\"`" + `
+	Parent   *Category   ` + "`db:\"-\" relation:\"belongsTo,foreignKey:parent_id\"`" + `
+	Children []*Category ` + "`db:\"-\" relation:\"hasMany,foreignKey:parent_id\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("failed to write models: %v", err)
+	}
+	models, err := generator.ParseModels(dir)
+	if err != nil {
+		t.Fatalf("ParseModels failed: %v", err)
+	}
+	generator.ResolveRelationFields(models)
+	if len(models) != 1 {
+		t.Fatalf("expected 1 model, got %d", len(models))
+	}
+	category := models[0]
+	category.ModulePath = "example.com/app"
+	category.PackagePath = "models"
+	if err := generator.GenerateFile(category, dir); err != nil {
+		t.Fatalf("GenerateFile failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "generated", "category_gen.go"))
+	if err != nil {
+		t.Fatalf("failed to read generated file: %v", err)
+	}
+
+	for _, want := range []string{
+		`ParentID field.Field[*int64]`,
+		`var Category_Parent = sqlc.BelongsTo(
+	clause.Column{Name: "parent_id"},
+	clause.Column{Name: "id"},
+	func(p *models.Category, child *models.Category) { p.Parent = child },
+	func(p *models.Category) int64 {
+		if p.ParentID == nil {
+			var zero int64
+			return zero
+		}
+		return *p.ParentID
+	},`,
+		`var Category_Children = sqlc.HasMany(
+	clause.Column{Name: "parent_id"},
+	clause.Column{Name: "id"},
+	func(p *models.Category, children []*models.Category) { p.Children = children },
+	func(p *models.Category) int64 { return p.ID },
+	func(c *models.Category) int64 {
+		if c.ParentID == nil {
+			var zero int64
+			return zero
+		}
+		return *c.ParentID
+	},`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("category_gen.go missing %q\n%s", want, content)
+		}
+	}
+}
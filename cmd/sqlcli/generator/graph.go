@@ -0,0 +1,217 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Graph output formats supported by RenderGraph
+const (
+	GraphMermaid = "mermaid"
+	GraphDOT     = "dot"
+)
+
+// RenderGraph writes an ER diagram of the parsed models and their relations.
+// format is GraphMermaid (erDiagram, renders on GitHub/GitLab) or GraphDOT (Graphviz).
+// JSON-only structs are omitted; relation targets that are not among models are
+// referenced by their type name.
+func RenderGraph(w io.Writer, models []ModelMeta, format string) error {
+	g := newERGraph(models)
+	switch format {
+	case GraphMermaid:
+		return g.writeMermaid(w)
+	case GraphDOT:
+		return g.writeDOT(w)
+	default:
+		return fmt.Errorf("unsupported graph format %q (want %s or %s)", format, GraphMermaid, GraphDOT)
+	}
+}
+
+// erGraph is the format-independent view of models used for rendering
+type erGraph struct {
+	models []ModelMeta
+	tables map[string]string          // model name -> table name
+	fks    map[string]map[string]bool // table name -> foreign key columns
+	edges  []erEdge
+}
+
+// erEdge is a relation between two tables, oriented from the referenced ("one") side.
+// hasOne renders as one-to-one; hasMany and belongsTo render as one-to-many;
+// many2many renders as many-to-many.
+type erEdge struct {
+	From, To string // table names
+	Label    string // relation field and key mapping
+	RelType  string // hasOne, hasMany, belongsTo, many2many
+}
+
+func newERGraph(models []ModelMeta) *erGraph {
+	g := &erGraph{
+		tables: make(map[string]string),
+		fks:    make(map[string]map[string]bool),
+	}
+	for _, m := range models {
+		if m.IsJSONOnly {
+			continue
+		}
+		g.models = append(g.models, m)
+		g.tables[m.ModelName] = m.TableName
+	}
+
+	markFK := func(table, column string) {
+		if g.fks[table] == nil {
+			g.fks[table] = make(map[string]bool)
+		}
+		g.fks[table][column] = true
+	}
+
+	for _, m := range g.models {
+		for _, rel := range m.Relations {
+			target := g.tableOf(rel.TargetType)
+			switch rel.RelType {
+			case "belongsTo":
+				// FK lives on this model and points at the target's key
+				markFK(m.TableName, rel.ForeignKey)
+				g.edges = append(g.edges, erEdge{
+					From:    target,
+					To:      m.TableName,
+					Label:   fmt.Sprintf("%s (%s -> %s)", rel.FieldName, rel.ForeignKey, rel.LocalKey),
+					RelType: rel.RelType,
+				})
+			case "many2many":
+				// FKs live on the join table, which is usually not a model
+				g.edges = append(g.edges, erEdge{
+					From:    m.TableName,
+					To:      target,
+					Label:   fmt.Sprintf("%s (via %s)", rel.FieldName, rel.JoinTable),
+					RelType: rel.RelType,
+				})
+			default:
+				// hasOne / hasMany: FK lives on the target
+				markFK(target, rel.ForeignKey)
+				g.edges = append(g.edges, erEdge{
+					From:    m.TableName,
+					To:      target,
+					Label:   fmt.Sprintf("%s (%s -> %s)", rel.FieldName, rel.LocalKey, rel.ForeignKey),
+					RelType: rel.RelType,
+				})
+			}
+		}
+	}
+	return g
+}
+
+// tableOf returns the table of a model, falling back to the type name for unknown models
+func (g *erGraph) tableOf(modelName string) string {
+	if table, ok := g.tables[modelName]; ok {
+		return table
+	}
+	return modelName
+}
+
+// columnKeys returns the key markers of a column (PK, FK)
+func (g *erGraph) columnKeys(m ModelMeta, f FieldMeta) []string {
+	var keys []string
+	if f.IsPK {
+		keys = append(keys, "PK")
+	}
+	if g.fks[m.TableName][f.Column] {
+		keys = append(keys, "FK")
+	}
+	return keys
+}
+
+func (g *erGraph) writeMermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+	for _, m := range g.models {
+		fmt.Fprintf(&sb, "    %s {\n", m.TableName)
+		for _, f := range m.Fields {
+			fmt.Fprintf(&sb, "        %s %s", mermaidType(f.Type), f.Column)
+			if keys := g.columnKeys(m, f); len(keys) > 0 {
+				sb.WriteString(" " + strings.Join(keys, ","))
+			}
+			if f.Column == m.SoftDeleteColumn && m.SoftDeleteColumn != "" {
+				sb.WriteString(` "soft delete"`)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("    }\n")
+	}
+	for _, e := range g.edges {
+		card := "||--o{"
+		switch e.RelType {
+		case "hasOne":
+			card = "||--o|"
+		case "many2many":
+			card = "}o--o{"
+		}
+		fmt.Fprintf(&sb, "    %s %s %s : %q\n", e.From, card, e.To, e.Label)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (g *erGraph) writeDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph sqlc {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=record, fontname=\"Helvetica\"];\n")
+	for _, m := range g.models {
+		rows := []string{dotEscape(m.TableName + " (" + m.ModelName + ")")}
+		for _, f := range m.Fields {
+			row := f.Column + " : " + f.Type
+			if keys := g.columnKeys(m, f); len(keys) > 0 {
+				row += " [" + strings.Join(keys, ",") + "]"
+			}
+			if f.Column == m.SoftDeleteColumn && m.SoftDeleteColumn != "" {
+				row += " (soft delete)"
+			}
+			rows = append(rows, dotEscape(row)+`\l`)
+		}
+		attrs := ""
+		if m.SoftDeleteColumn != "" {
+			attrs = ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "    %q [label=\"{%s}\"%s];\n", m.TableName, strings.Join(rows, "|"), attrs)
+	}
+	for _, e := range g.edges {
+		arrow := "crow"
+		if e.RelType == "hasOne" {
+			arrow = "tee"
+		}
+		extra := ""
+		if e.RelType == "many2many" {
+			extra = ", dir=both, arrowtail=crow"
+		}
+		fmt.Fprintf(&sb, "    %q -> %q [label=%q, arrowhead=%s%s];\n", e.From, e.To, e.Label, arrow, extra)
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var (
+	// qualifierRegexp matches package qualifiers such as "time." or "models."
+	qualifierRegexp = regexp.MustCompile(`\w+\.`)
+	// mermaidInvalidRegexp matches characters not allowed in Mermaid attribute types
+	mermaidInvalidRegexp = regexp.MustCompile(`[^\w\-()\[\]]`)
+)
+
+// mermaidType converts a Go type into a valid Mermaid attribute type
+// (e.g. *time.Time -> Time, []byte -> byte[]).
+func mermaidType(goType string) string {
+	typ := strings.TrimLeft(goType, "*")
+	typ = qualifierRegexp.ReplaceAllString(typ, "")
+	if rest, ok := strings.CutPrefix(typ, "[]"); ok {
+		typ = strings.TrimLeft(rest, "*") + "[]"
+	}
+	return mermaidInvalidRegexp.ReplaceAllString(typ, "_")
+}
+
+// dotEscape escapes characters that are special in Graphviz record labels
+func dotEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+	return r.Replace(s)
+}
@@ -0,0 +1,141 @@
+package generator_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc/cmd/sqlcli/generator"
+)
+
+func graphTestModels() []generator.ModelMeta {
+	return []generator.ModelMeta{
+		{
+			ModelName: "User",
+			TableName: "users",
+			Fields: []generator.FieldMeta{
+				{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+				{FieldName: "Name", Column: "name", Type: "string"},
+				{FieldName: "DeletedAt", Column: "deleted_at", Type: "*time.Time"},
+			},
+			SoftDeleteField:  "DeletedAt",
+			SoftDeleteColumn: "deleted_at",
+			Relations: []generator.RelationMeta{
+				{FieldName: "Posts", RelType: "hasMany", TargetType: "Post", ForeignKey: "user_id", LocalKey: "id"},
+				{FieldName: "Profile", RelType: "hasOne", TargetType: "Profile", ForeignKey: "user_id", LocalKey: "id"},
+			},
+		},
+		{
+			ModelName: "Post",
+			TableName: "posts",
+			Fields: []generator.FieldMeta{
+				{FieldName: "ID", Column: "id", Type: "int64", IsPK: true},
+				{FieldName: "UserID", Column: "user_id", Type: "int64"},
+				{FieldName: "Body", Column: "body", Type: "[]byte"},
+			},
+			Relations: []generator.RelationMeta{
+				{FieldName: "Author", RelType: "belongsTo", TargetType: "User", ForeignKey: "user_id", LocalKey: "id"},
+			},
+		},
+		{
+			ModelName:  "Metadata",
+			IsJSONOnly: true,
+		},
+	}
+}
+
+func TestRenderGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "Mermaid",
+			format: generator.GraphMermaid,
+			want: []string{
+				"erDiagram",
+				"    users {",
+				"        int64 id PK",
+				`        Time deleted_at "soft delete"`,
+				"        int64 user_id FK",
+				"        byte[] body",
+				`    users ||--o{ posts : "Posts (id -> user_id)"`,
+				`    users ||--o| Profile : "Profile (id -> user_id)"`,
+				`    users ||--o{ posts : "Author (user_id -> id)"`,
+			},
+			notWant: []string{"Metadata"},
+		},
+		{
+			name:   "DOT",
+			format: generator.GraphDOT,
+			want: []string{
+				"digraph sqlc {",
+				`"users" [label="{users (User)|id : int64 [PK]\l|name : string\l|deleted_at : *time.Time (soft delete)\l}", style=dashed];`,
+				`user_id : int64 [FK]\l`,
+				`"users" -> "posts" [label="Posts (id -> user_id)", arrowhead=crow];`,
+				`"users" -> "Profile" [label="Profile (id -> user_id)", arrowhead=tee];`,
+			},
+			notWant: []string{"Metadata"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			if err := generator.RenderGraph(&buf, graphTestModels(), tt.format); err != nil {
+				t.Fatalf("RenderGraph failed: %v", err)
+			}
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, notWant) {
+					t.Errorf("output must not contain %q\n%s", notWant, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderGraph_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := generator.RenderGraph(&buf, graphTestModels(), "svg"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestRenderGraph_ManyToMany(t *testing.T) {
+	models := []generator.ModelMeta{
+		{
+			ModelName: "User",
+			TableName: "users",
+			Fields:    []generator.FieldMeta{{FieldName: "ID", Column: "id", Type: "int64", IsPK: true}},
+			Relations: []generator.RelationMeta{
+				{FieldName: "Roles", RelType: "many2many", TargetType: "Role", ForeignKey: "user_id", LocalKey: "id", JoinTable: "user_roles", JoinReferences: "role_id"},
+			},
+		},
+		{
+			ModelName: "Role",
+			TableName: "roles",
+			Fields:    []generator.FieldMeta{{FieldName: "ID", Column: "id", Type: "int64", IsPK: true}},
+		},
+	}
+	for format, want := range map[string]string{
+		generator.GraphMermaid: `    users }o--o{ roles : "Roles (via user_roles)"`,
+		generator.GraphDOT:     `    "users" -> "roles" [label="Roles (via user_roles)", arrowhead=crow, dir=both, arrowtail=crow];`,
+	} {
+		var buf bytes.Buffer
+		if err := generator.RenderGraph(&buf, models, format); err != nil {
+			t.Fatalf("RenderGraph failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s: output missing %q\n%s", format, want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// NameMapping is the resolved naming of the parsed models, as printed by `sqlcli names`.
+// Its JSON form is meant for review before generation and for data-governance tooling.
+type NameMapping struct {
+	Version string         `json:"version"`
+	Models  []ModelMapping `json:"models"`
+}
+
+// ModelMapping is the naming of one model: struct to table, fields to columns
+type ModelMapping struct {
+	Package    string             `json:"package"`
+	Model      string             `json:"model"`
+	Table      string             `json:"table"`
+	PrimaryKey *KeyMapping        `json:"primaryKey,omitempty"`
+	SoftDelete *SoftDeleteMapping `json:"softDelete,omitempty"`
+	Fields     []FieldMapping     `json:"fields"`
+	Relations  []RelationMapping  `json:"relations"`
+	Partitions *PartitionMapping  `json:"partitions,omitempty"`
+}
+
+// KeyMapping is the primary key of a model
+type KeyMapping struct {
+	Field         string `json:"field"`
+	Column        string `json:"column"`
+	AutoIncrement bool   `json:"autoIncrement"`
+}
+
+// SoftDeleteMapping is the soft delete column of a model
+type SoftDeleteMapping struct {
+	Field     string `json:"field"`
+	Column    string `json:"column"`
+	UnixMilli bool   `json:"unixMilli,omitempty"`
+}
+
+// FieldMapping maps a struct field to its column
+type FieldMapping struct {
+	Field  string `json:"field"`
+	Column string `json:"column"`
+	Type   string `json:"type"`
+	JSON   bool   `json:"json,omitempty"`
+}
+
+// RelationMapping is a relation of a model and the columns it joins on.
+// ForeignKey is on the target for hasOne/hasMany, on the model for belongsTo and on
+// the join table for many2many/hasManyThrough; References is the column it refers to.
+type RelationMapping struct {
+	Field          string `json:"field"`
+	Kind           string `json:"kind"`
+	Target         string `json:"target"`
+	TargetTable    string `json:"targetTable,omitempty"`
+	ForeignKey     string `json:"foreignKey"`
+	References     string `json:"references"`
+	JoinTable      string `json:"joinTable,omitempty"`
+	JoinReferences string `json:"joinReferences,omitempty"`
+}
+
+// PartitionMapping is the hash partitioning of a model
+type PartitionMapping struct {
+	Count    int    `json:"count"`
+	KeyField string `json:"keyField"`
+}
+
+// ResolveNames builds the name mapping of models. JSON-only structs are omitted;
+// relation targets that are not among models get an empty target table.
+// Call ResolveRelationFields first so many2many target keys are resolved.
+func ResolveNames(models []ModelMeta) NameMapping {
+	tables := make(map[string]string)
+	for _, m := range models {
+		if !m.IsJSONOnly {
+			tables[m.ModelName] = m.TableName
+		}
+	}
+
+	mapping := NameMapping{Version: Version, Models: []ModelMapping{}}
+	for _, m := range models {
+		if m.IsJSONOnly {
+			continue
+		}
+		mm := ModelMapping{
+			Package:   m.ParentPackage,
+			Model:     m.ModelName,
+			Table:     m.TableName,
+			Fields:    []FieldMapping{},
+			Relations: []RelationMapping{},
+		}
+		if m.PKFieldName != "" {
+			mm.PrimaryKey = &KeyMapping{Field: m.PKFieldName, Column: m.PKColumnName, AutoIncrement: m.IsAutoIncrementPK}
+		}
+		if m.SoftDeleteColumn != "" {
+			mm.SoftDelete = &SoftDeleteMapping{Field: m.SoftDeleteField, Column: m.SoftDeleteColumn, UnixMilli: m.SoftDeleteMilli}
+		}
+		if m.PartitionCount > 0 {
+			mm.Partitions = &PartitionMapping{Count: m.PartitionCount, KeyField: m.PartitionKeyField}
+		}
+		for _, f := range m.Fields {
+			mm.Fields = append(mm.Fields, FieldMapping{Field: f.FieldName, Column: f.Column, Type: f.Type, JSON: f.IsJSON})
+		}
+		for _, rel := range m.Relations {
+			kind := rel.RelType
+			if rel.Through {
+				kind = "hasManyThrough"
+			}
+			mm.Relations = append(mm.Relations, RelationMapping{
+				Field:          rel.FieldName,
+				Kind:           kind,
+				Target:         rel.TargetType,
+				TargetTable:    tables[rel.TargetType],
+				ForeignKey:     rel.ForeignKey,
+				References:     rel.LocalKey,
+				JoinTable:      rel.JoinTable,
+				JoinReferences: rel.JoinReferences,
+			})
+		}
+		mapping.Models = append(mapping.Models, mm)
+	}
+	return mapping
+}
+
+// RenderNames writes the name mapping of models as indented JSON.
+func RenderNames(w io.Writer, models []ModelMeta) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ResolveNames(models))
+}
@@ -0,0 +1,57 @@
+package generator_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc/cmd/sqlcli/generator"
+)
+
+func TestRenderNames(t *testing.T) {
+	models := graphTestModels()
+	models[0].PKFieldName, models[0].PKColumnName, models[0].IsAutoIncrementPK = "ID", "id", true
+	models[0].Relations = append(models[0].Relations, generator.RelationMeta{
+		FieldName: "Teams", RelType: "many2many", Through: true, TargetType: "Team",
+		ForeignKey: "user_id", LocalKey: "id", JoinTable: "memberships", JoinReferences: "team_id",
+	})
+
+	var buf bytes.Buffer
+	if err := generator.RenderNames(&buf, models); err != nil {
+		t.Fatalf("RenderNames failed: %v", err)
+	}
+	var got generator.NameMapping
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(got.Models) != 2 {
+		t.Fatalf("expected 2 models (JSON-only omitted), got %+v", got.Models)
+	}
+	user, post := got.Models[0], got.Models[1]
+	if user.Model != "User" || user.Table != "users" || post.Table != "posts" {
+		t.Errorf("unexpected tables: %+v, %+v", user, post)
+	}
+	if want := (&generator.KeyMapping{Field: "ID", Column: "id", AutoIncrement: true}); !reflect.DeepEqual(user.PrimaryKey, want) {
+		t.Errorf("primaryKey = %+v, want %+v", user.PrimaryKey, want)
+	}
+	if want := (&generator.SoftDeleteMapping{Field: "DeletedAt", Column: "deleted_at"}); !reflect.DeepEqual(user.SoftDelete, want) {
+		t.Errorf("softDelete = %+v, want %+v", user.SoftDelete, want)
+	}
+	if post.SoftDelete != nil || post.PrimaryKey != nil {
+		t.Errorf("expected no soft delete or cached PK on posts, got %+v", post)
+	}
+	if want := (generator.FieldMapping{Field: "DeletedAt", Column: "deleted_at", Type: "*time.Time"}); user.Fields[2] != want {
+		t.Errorf("field = %+v, want %+v", user.Fields[2], want)
+	}
+
+	wantRelations := []generator.RelationMapping{
+		{Field: "Posts", Kind: "hasMany", Target: "Post", TargetTable: "posts", ForeignKey: "user_id", References: "id"},
+		{Field: "Profile", Kind: "hasOne", Target: "Profile", ForeignKey: "user_id", References: "id"},
+		{Field: "Teams", Kind: "hasManyThrough", Target: "Team", ForeignKey: "user_id", References: "id", JoinTable: "memberships", JoinReferences: "team_id"},
+	}
+	if !reflect.DeepEqual(user.Relations, wantRelations) {
+		t.Errorf("relations =\n%+v\nwant\n%+v", user.Relations, wantRelations)
+	}
+}
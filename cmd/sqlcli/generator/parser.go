@@ -7,6 +7,8 @@ import (
 	"go/token"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -14,10 +16,14 @@ import (
 
 // GenConfig holds parsed configuration from config.go
 type GenConfig struct {
-	OutPath        string
-	IncludeStructs []string
-	ExcludeStructs []string
-	FieldTypeMap   map[string]string
+	OutPath           string
+	IncludeStructs    []string
+	ExcludeStructs    []string
+	FieldTypeMap      map[string]string
+	SoftDeleteColumns map[string]string
+	DefaultOrder      map[string]string
+	Partials          map[string]string
+	Patches           []string
 }
 
 // ParseConfig parses config.go in the given directory for gen.Config
@@ -92,6 +98,14 @@ func ParseConfig(dir string) (*GenConfig, error) {
 					cfg.ExcludeStructs = parseStringSlice(kv.Value)
 				case "FieldTypeMap":
 					cfg.FieldTypeMap = parseStringMap(kv.Value)
+				case "SoftDeleteColumns":
+					cfg.SoftDeleteColumns = parseStringMap(kv.Value)
+				case "DefaultOrder":
+					cfg.DefaultOrder = parseStringMap(kv.Value)
+				case "Partials":
+					cfg.Partials = parseStringMap(kv.Value)
+				case "Patches":
+					cfg.Patches = parseStringSlice(kv.Value)
 				}
 			}
 			return cfg, nil
@@ -191,21 +205,211 @@ type ModelMeta struct {
 	SoftDeleteField     string            // Name of the soft delete field (e.g. "DeletedAt")
 	SoftDeleteColumn    string            // Name of the soft delete column (e.g. "deleted_at")
 	SoftDeleteFieldType string            // Type of the soft delete field (e.g. "*time.Time")
+	SoftDeleteMilli     bool              // Integer soft delete field stores Unix milliseconds (softDelete:unixMilli)
+	PartitionCount      int               // Number of hash partitions (0 = not partitioned)
+	PartitionKeyField   string            // Go field name of the partition key (e.g. "UserID")
+	PartitionKeyType    string            // Go type of the partition key (e.g. "int64")
+	PartitionTables     []string          // Physical partition table names (populated during generation)
 	TypeAliases         map[string]string // type A int → {"A": "int"}
 	FieldTypeMap        map[string]string // User-defined type mappings from config
+	Counters            []CounterMeta     // Sharded counters declared with counter:N
+	DefaultOrder        []OrderMeta       // Default ORDER BY of queries (from config)
+	Partials            []PartialMeta     // Column subset structs (from config)
+	Patch               bool              // Generate a <Model>Patch struct for partial updates (from config)
+	FileImports         map[string]string // Imports of the model's source file: package name → path
+}
+
+// PartialMeta is a column subset struct of a model (e.g. UserIdentity)
+type PartialMeta struct {
+	Name   string      // Suffix appended to the model name (e.g. "Identity")
+	Fields []FieldMeta // Fields of the subset, in spec order
+}
+
+// OrderMeta is a column of a model's default order
+type OrderMeta struct {
+	Column string // Column name (e.g. "created_at")
+	Desc   bool   // Descending order
+}
+
+// SetSoftDeleteColumn makes column the soft delete column of the model,
+// replacing the one detected from the DeletedAt field or the softDelete tag.
+// A ":unixMilli" suffix (e.g. "deleted_at:unixMilli") stores Unix milliseconds
+// in an integer column instead of seconds.
+func (m *ModelMeta) SetSoftDeleteColumn(spec string) error {
+	column, strategy, _ := strings.Cut(spec, ":")
+	if strategy != "" && strategy != "unixMilli" {
+		return fmt.Errorf("model %s: unknown soft delete strategy %q (want unixMilli)", m.ModelName, strategy)
+	}
+	for _, f := range m.Fields {
+		if f.Column == column {
+			m.SoftDeleteField = f.FieldName
+			m.SoftDeleteColumn = f.Column
+			m.SoftDeleteFieldType = f.Type
+			m.SoftDeleteMilli = strategy == "unixMilli"
+			if m.SoftDeleteMilli && !m.SoftDeleteIsUnix() {
+				return fmt.Errorf("model %s: unixMilli soft delete requires an integer column, %q is %s", m.ModelName, column, f.Type)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("model %s has no column %q for soft delete", m.ModelName, column)
+}
+
+// SoftDeleteIsUnix reports whether the soft delete field is an integer Unix timestamp
+// (live records have 0).
+func (m ModelMeta) SoftDeleteIsUnix() bool {
+	switch m.SoftDeleteFieldType {
+	case "int", "int32", "int64", "uint", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// SoftDeleteUnixNow returns the Go expression of the current Unix timestamp
+// (seconds, or milliseconds for SoftDeleteMilli) in the soft delete field's type.
+func (m ModelMeta) SoftDeleteUnixNow() string {
+	now := "time.Now().Unix()"
+	if m.SoftDeleteMilli {
+		now = "time.Now().UnixMilli()"
+	}
+	if m.SoftDeleteFieldType == "int64" {
+		return now
+	}
+	return m.SoftDeleteFieldType + "(" + now + ")"
+}
+
+// SetDefaultOrder sets the default order of the model from spec, a comma-separated
+// list of columns each optionally followed by ASC or DESC (e.g. "created_at DESC, id").
+func (m *ModelMeta) SetDefaultOrder(spec string) error {
+	var orders []OrderMeta
+	for _, item := range strings.Split(spec, ",") {
+		parts := strings.Fields(item)
+		if len(parts) == 0 || len(parts) > 2 {
+			return fmt.Errorf("model %s: invalid default order %q", m.ModelName, spec)
+		}
+		order := OrderMeta{Column: parts[0]}
+		if len(parts) == 2 {
+			switch strings.ToUpper(parts[1]) {
+			case "ASC":
+			case "DESC":
+				order.Desc = true
+			default:
+				return fmt.Errorf("model %s: invalid sort direction %q in default order", m.ModelName, parts[1])
+			}
+		}
+		if !slices.ContainsFunc(m.Fields, func(f FieldMeta) bool { return f.Column == order.Column }) {
+			return fmt.Errorf("model %s has no column %q for default order", m.ModelName, order.Column)
+		}
+		orders = append(orders, order)
+	}
+	m.DefaultOrder = orders
+	return nil
+}
+
+// SetPartials sets the partial structs of the model from spec, a semicolon-separated list
+// of "Name: column, column" entries (e.g. "Identity: id, username; Card: id, avatar_url").
+func (m *ModelMeta) SetPartials(spec string) error {
+	var partials []PartialMeta
+	for _, item := range strings.Split(spec, ";") {
+		name, columns, ok := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		if !ok || !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("model %s: invalid partial %q, expected \"Name: column, ...\"", m.ModelName, strings.TrimSpace(item))
+		}
+		if slices.ContainsFunc(partials, func(p PartialMeta) bool { return p.Name == name }) {
+			return fmt.Errorf("model %s: duplicate partial %s", m.ModelName, name)
+		}
+		partial := PartialMeta{Name: name}
+		for _, column := range strings.Split(columns, ",") {
+			column = strings.TrimSpace(column)
+			i := slices.IndexFunc(m.Fields, func(f FieldMeta) bool { return f.Column == column })
+			if i < 0 {
+				return fmt.Errorf("model %s has no column %q for partial %s", m.ModelName, column, name)
+			}
+			partial.Fields = append(partial.Fields, m.Fields[i])
+		}
+		partials = append(partials, partial)
+	}
+	m.Partials = partials
+	return nil
+}
+
+// SortableFields returns the fields tagged sortable, in declaration order.
+func (m ModelMeta) SortableFields() []FieldMeta {
+	var fields []FieldMeta
+	for _, f := range m.Fields {
+		if f.Sortable {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// ValidatedFields returns the fields with a validate tag, in declaration order.
+func (m ModelMeta) ValidatedFields() []FieldMeta {
+	var fields []FieldMeta
+	for _, f := range m.Fields {
+		if f.Validate != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// PatchFields returns the fields of the model's Patch struct, in declaration order:
+// every field but the primary key, the soft delete field, the partition key and
+// fields tagged json:"-".
+func (m ModelMeta) PatchFields() []FieldMeta {
+	var fields []FieldMeta
+	for _, f := range m.Fields {
+		if f.IsPK || f.JSONName == "-" || f.FieldName == m.SoftDeleteField || f.FieldName == m.PartitionKeyField {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// CounterMeta holds information about a sharded counter declared on a field
+type CounterMeta struct {
+	FieldName string // Go field name (e.g. "Views")
+	Column    string // Column name used to derive the counter table (e.g. "views")
+	Shards    int    // Number of shard rows per counter
 }
 
 // RelationMeta holds information about a model relation
 type RelationMeta struct {
 	FieldName           string // Field name in parent model (e.g., "Posts")
-	RelType             string // Relation type: "hasOne", "hasMany", "belongsTo"
-	ForeignKey          string // Foreign key column (on child for hasOne/Many, on parent for belongsTo)
-	LocalKey            string // Local key column (on parent for hasOne/Many[default id], on child for belongsTo[default id])
+	RelType             string // Relation type: "hasOne", "hasMany", "belongsTo", "many2many"
+	Through             bool   // many2many declared as hasManyThrough: JoinTable is an intermediate model's table
+	ForeignKey          string // Foreign key column (on child for hasOne/Many, on parent for belongsTo, on join table for many2many)
+	LocalKey            string // Local key column (on parent for hasOne/Many/many2many[default id], on child for belongsTo[default id])
 	TargetType          string // Target model type name (e.g., "Post")
 	TargetSlice         bool   // True if field is a slice (hasMany)
 	ForeignKeyField     string // Go field name of foreign key (on parent for belongsTo, on target for hasOne/hasMany)
 	ForeignKeyFieldType string // Go type of FK field; set only if it differs from parent PK type (for type conversion)
-	TargetPKField       string // Go field name of PK on target model (used for belongsTo getForeignKey)
+	ForeignKeyNullable  bool   // FK field is a pointer (nullable column, e.g. parent_id of tree roots)
+	TargetPKField       string // Go field name of PK on target model (used for belongsTo and many2many)
+	JoinTable           string // Join table (many2many only)
+	JoinReferences      string // Join table column referencing the target (many2many only, default <target>_id)
+	TargetKey           string // Target key column referenced by JoinReferences (many2many only, default target PK)
+}
+
+// ForeignKeyReturn returns the statements of a function returning the foreign key
+// value of recv as keyType: nullable foreign keys return the zero key when nil.
+func (r RelationMeta) ForeignKeyReturn(recv, keyType string) string {
+	field := recv + "." + r.ForeignKeyField
+	value := field
+	if r.ForeignKeyNullable {
+		value = "*" + value
+	}
+	if r.ForeignKeyFieldType != "" {
+		value = r.ForeignKeyFieldType + "(" + value + ")"
+	}
+	if !r.ForeignKeyNullable {
+		return "return " + value
+	}
+	return fmt.Sprintf("if %s == nil {\nvar zero %s\nreturn zero\n}\nreturn %s", field, keyType, value)
 }
 
 // ResolveRelationFields resolves ForeignKeyField across models for hasOne/hasMany relations.
@@ -232,8 +436,9 @@ func ResolveRelationFields(models []ModelMeta) {
 				for _, f := range target.Fields {
 					if f.Column == rel.ForeignKey {
 						rel.ForeignKeyField = f.FieldName
+						rel.ForeignKeyNullable = strings.HasPrefix(f.Type, "*")
 						// If FK type differs from parent PK type, record it for type conversion
-						if f.Type != models[i].PKFieldType {
+						if strings.TrimPrefix(f.Type, "*") != models[i].PKFieldType {
 							rel.ForeignKeyFieldType = models[i].PKFieldType
 						}
 						break
@@ -242,6 +447,11 @@ func ResolveRelationFields(models []ModelMeta) {
 			case "belongsTo":
 				// TargetPKField = Go field name of PK on target model
 				rel.TargetPKField = target.PKFieldName
+			case "many2many":
+				rel.TargetPKField = target.PKFieldName
+				if rel.TargetKey == "" {
+					rel.TargetKey = target.PKColumnName
+				}
 			}
 		}
 	}
@@ -254,6 +464,9 @@ type FieldMeta struct {
 	IsPK         bool
 	AutoIncr     bool
 	IsJSON       bool     // Whether field is a JSON type
+	Sortable     bool     // Whether field may be used for ordering/filtering from external input
+	Validate     string   // Rules of the validate tag, checked by repositories before writes
+	JSONName     string   // Key in JSON documents from the json tag ("-" = never encoded)
 	JSONTypeName string   // Name of the JSON struct type (e.g. "UserMetadata")
 	Doc          []string // Documentation comments
 }
@@ -319,6 +532,15 @@ func ParseModels(dir string) ([]ModelMeta, error) {
 			if strings.HasSuffix(filename, "_gen.go") {
 				continue
 			}
+			fileImports := make(map[string]string)
+			for _, spec := range file.Imports {
+				path, _ := strconv.Unquote(spec.Path.Value)
+				name := path[strings.LastIndex(path, "/")+1:]
+				if spec.Name != nil {
+					name = spec.Name.Name
+				}
+				fileImports[name] = path
+			}
 			ast.Inspect(file, func(n ast.Node) bool {
 				ts, ok := n.(*ast.TypeSpec)
 				if !ok {
@@ -349,6 +571,7 @@ func ParseModels(dir string) ([]ModelMeta, error) {
 					Doc:              docComments,
 					SchemaStructName: schemaStructName,
 					TypeAliases:      typeAliases,
+					FileImports:      fileImports,
 				}
 
 				for _, field := range st.Fields.List {
@@ -429,6 +652,8 @@ func ParseModels(dir string) ([]ModelMeta, error) {
 
 					if field.Tag != nil {
 						tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+						meta.JSONName, _, _ = strings.Cut(tag.Get("json"), ",")
+						meta.Validate = tag.Get("validate")
 						ormTag := tag.Get("db")
 						if ormTag == "" {
 							ormTag = tag.Get("orm") // Fallback
@@ -486,10 +711,39 @@ func ParseModels(dir string) ([]ModelMeta, error) {
 											meta.JSONTypeName = meta.Type
 										}
 									}
+								case "sortable":
+									meta.Sortable = true
 								case "softDelete":
+									// softDelete:unixMilli stores milliseconds in an integer column
 									model.SoftDeleteField = meta.FieldName
 									model.SoftDeleteColumn = meta.Column
 									model.SoftDeleteFieldType = meta.Type
+									model.SoftDeleteMilli = len(kv) > 1 && kv[1] == "unixMilli"
+								case "counter":
+									// Sharded counter: counter:N emits an N-shard counter table
+									if len(kv) > 1 {
+										if n, err := strconv.Atoi(kv[1]); err == nil && n > 0 {
+											column := meta.Column
+											if column == "-" {
+												// Counter-only field, not stored in the model table
+												column = toSnakeCase(meta.FieldName)
+											}
+											model.Counters = append(model.Counters, CounterMeta{
+												FieldName: meta.FieldName,
+												Column:    column,
+												Shards:    n,
+											})
+										}
+									}
+								case "partition":
+									// Hash partition key: partition:N spreads rows over N tables
+									if len(kv) > 1 {
+										if n, err := strconv.Atoi(kv[1]); err == nil && n > 0 {
+											model.PartitionCount = n
+											model.PartitionKeyField = meta.FieldName
+											model.PartitionKeyType = meta.Type
+										}
+									}
 								}
 							}
 						}
@@ -543,12 +797,17 @@ func ParseModels(dir string) ([]ModelMeta, error) {
 					model.IsJSONOnly = true
 				}
 
-				// Resolve ForeignKeyField for belongsTo relations
+				// Resolve ForeignKeyField for belongsTo relations and the default
+				// join table column referencing this model for many2many relations
 				for i, rel := range model.Relations {
+					if rel.RelType == "many2many" && rel.ForeignKey == "" {
+						model.Relations[i].ForeignKey = toSnakeCase(model.ModelName) + "_id"
+					}
 					if rel.RelType == "belongsTo" {
 						for _, f := range model.Fields {
 							if f.Column == rel.ForeignKey {
 								model.Relations[i].ForeignKeyField = f.FieldName
+								model.Relations[i].ForeignKeyNullable = strings.HasPrefix(f.Type, "*")
 								break
 							}
 						}
@@ -705,9 +964,15 @@ func parseRelationTag(fieldName, fieldType, tag string) *RelationMeta {
 				rel.ForeignKey = val
 			case "localKey":
 				rel.LocalKey = val
+			case "joinTable", "through":
+				rel.JoinTable = val
+			case "references", "throughKey":
+				rel.JoinReferences = val
+			case "targetKey":
+				rel.TargetKey = val
 			}
 		} else {
-			// Relation type (hasOne, hasMany, belongsTo)
+			// Relation type (hasOne, hasMany, belongsTo, many2many, hasManyThrough)
 			switch strings.ToLower(part) {
 			case "hasone":
 				rel.RelType = "hasOne"
@@ -715,6 +980,12 @@ func parseRelationTag(fieldName, fieldType, tag string) *RelationMeta {
 				rel.RelType = "hasMany"
 			case "belongsto":
 				rel.RelType = "belongsTo"
+			case "many2many", "manytomany":
+				rel.RelType = "many2many"
+			case "hasmanythrough":
+				// Loaded like many2many, through the intermediate table
+				rel.RelType = "many2many"
+				rel.Through = true
 			}
 		}
 	}
@@ -728,6 +999,17 @@ func parseRelationTag(fieldName, fieldType, tag string) *RelationMeta {
 		}
 	}
 
+	// many2many: must have joinTable (through); foreignKey defaults to <model>_id (set by the caller)
+	if rel.RelType == "many2many" {
+		if rel.JoinTable == "" {
+			return nil
+		}
+		if rel.JoinReferences == "" {
+			rel.JoinReferences = toSnakeCase(rel.TargetType) + "_id"
+		}
+		return rel
+	}
+
 	// Validate: must have foreignKey
 	if rel.ForeignKey == "" {
 		return nil
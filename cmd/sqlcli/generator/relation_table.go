@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+const relationTableTemplate = `// Code generated by sqlcli. DO NOT EDIT.
+// Version: {{.CliVersion}}
+
+package {{.PackageName}}
+
+import "github.com/arllen133/sqlc"
+
+// Relations describes the relations of the models in this package, for navigating
+// the model graph at runtime (see sqlc.RelationTable)
+var Relations = sqlc.RelationTable{
+	{{- range .Relations}}
+	{Model: "{{.Model}}", Table: "{{.Table}}", Name: "{{.Name}}", Kind: sqlc.{{.Kind}}, Target: "{{.Target}}", TargetTable: "{{.TargetTable}}", ForeignKey: "{{.ForeignKey}}", References: "{{.References}}"{{if .JoinTable}}, JoinTable: "{{.JoinTable}}", JoinReferences: "{{.JoinReferences}}"{{end}}},
+	{{- end}}
+}
+`
+
+// relationTableData holds data for generating the relation table file
+type relationTableData struct {
+	PackageName string
+	CliVersion  string
+	Relations   []relationTableRow
+}
+
+// relationTableRow is one sqlc.RelationDesc literal
+type relationTableRow struct {
+	Model, Table, Name, Kind, Target, TargetTable, ForeignKey, References string
+	JoinTable, JoinReferences                                             string
+}
+
+// relationKinds maps RelationMeta.RelType to the sqlc.RelationKind constant
+var relationKinds = map[string]string{
+	"hasOne":    "KindHasOne",
+	"hasMany":   "KindHasMany",
+	"belongsTo": "KindBelongsTo",
+	"many2many": "KindManyToMany",
+}
+
+// GenerateRelationTable generates generated/relations_gen.go with the Relations table
+// of all models in a package. Nothing is written if the models declare no relations.
+// Target tables are resolved among models; targets outside models get an empty table.
+func GenerateRelationTable(models []ModelMeta, outDir string) error {
+	tables := make(map[string]string)
+	for _, m := range models {
+		if !m.IsJSONOnly {
+			tables[m.ModelName] = m.TableName
+		}
+	}
+
+	data := relationTableData{CliVersion: Version}
+	for _, m := range models {
+		if m.IsJSONOnly {
+			continue
+		}
+		data.PackageName = m.PackageName
+		for _, rel := range m.Relations {
+			kind, ok := relationKinds[rel.RelType]
+			if !ok {
+				return fmt.Errorf("model %s: unknown relation type %q for %s", m.ModelName, rel.RelType, rel.FieldName)
+			}
+			// ForeignKey is on the target for hasOne/hasMany, on this model for belongsTo and
+			// on the join table for many2many; LocalKey is the referenced column in all cases
+			data.Relations = append(data.Relations, relationTableRow{
+				Model:       m.ModelName,
+				Table:       m.TableName,
+				Name:        rel.FieldName,
+				Kind:        kind,
+				Target:      rel.TargetType,
+				TargetTable: tables[rel.TargetType],
+				ForeignKey:  rel.ForeignKey,
+				References:  rel.LocalKey,
+
+				JoinTable:      rel.JoinTable,
+				JoinReferences: rel.JoinReferences,
+			})
+		}
+	}
+	if len(data.Relations) == 0 {
+		return nil
+	}
+
+	tmpl, err := template.New("relationTable").Parse(relationTableTemplate)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	// Format the generated code
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format source: %w", err)
+	}
+
+	generatedDir := filepath.Join(outDir, "generated")
+	if err := os.MkdirAll(generatedDir, 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(generatedDir, "relations_gen.go"), formatted, 0644)
+}
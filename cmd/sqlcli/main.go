@@ -6,12 +6,23 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/arllen133/sqlc/cmd/sqlcli/generator"
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		runGraph(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "names" {
+		runNames(os.Args[2:])
+		return
+	}
+
 	inputDir := flag.String("i", ".", "input directory containing model files")
 	outDir := flag.String("o", "", "output directory (overrides config.go)")
 	modulePath := flag.String("module", "", "module path (e.g., github.com/user/project)")
@@ -206,6 +217,25 @@ func processDir(modelDir, outDir, modulePath, packagePath string) {
 		if cfg != nil && cfg.FieldTypeMap != nil {
 			models[i].FieldTypeMap = cfg.FieldTypeMap
 		}
+		// Apply soft delete column overrides from config
+		if cfg != nil {
+			if column, ok := cfg.SoftDeleteColumns[models[i].ModelName]; ok {
+				if err := models[i].SetSoftDeleteColumn(column); err != nil {
+					log.Fatalf("invalid SoftDeleteColumns config: %v", err)
+				}
+			}
+			if spec, ok := cfg.DefaultOrder[models[i].ModelName]; ok {
+				if err := models[i].SetDefaultOrder(spec); err != nil {
+					log.Fatalf("invalid DefaultOrder config: %v", err)
+				}
+			}
+			if spec, ok := cfg.Partials[models[i].ModelName]; ok {
+				if err := models[i].SetPartials(spec); err != nil {
+					log.Fatalf("invalid Partials config: %v", err)
+				}
+			}
+			models[i].Patch = slices.Contains(cfg.Patches, models[i].ModelName)
+		}
 	}
 
 	// Resolve cross-model relation fields (e.g., FK field names on target models)
@@ -217,6 +247,9 @@ func processDir(modelDir, outDir, modulePath, packagePath string) {
 			log.Fatalf("failed to generate file for %s: %v", m.ModelName, err)
 		}
 	}
+	if err := generator.GenerateRelationTable(models, effectiveOutDir); err != nil {
+		log.Fatalf("failed to generate relation table: %v", err)
+	}
 }
 
 // filterModels applies Include/Exclude filters from config
@@ -249,3 +282,87 @@ func filterModels(models []generator.ModelMeta, cfg *generator.GenConfig) []gene
 	}
 	return result
 }
+
+// runGraph renders an ER diagram of the models in a directory.
+//
+// Usage:
+//
+//	sqlcli graph -i ./models                   # Mermaid to stdout
+//	sqlcli graph -i ./models -format dot -o er.dot
+func runGraph(args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	inputDir := fs.String("i", ".", "input directory containing model files")
+	format := fs.String("format", generator.GraphMermaid, "output format: mermaid or dot")
+	outFile := fs.String("o", "", "output file (default stdout)")
+	_ = fs.Parse(args)
+
+	cfg, err := generator.ParseConfig(*inputDir)
+	if err != nil {
+		log.Fatalf("failed to parse config: %v", err)
+	}
+
+	models, err := generator.ParseModels(*inputDir)
+	if err != nil {
+		log.Fatalf("failed to parse models: %v", err)
+	}
+	if cfg != nil {
+		models = filterModels(models, cfg)
+	}
+	generator.ResolveRelationFields(models)
+
+	out := os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatalf("failed to create output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := generator.RenderGraph(out, models, *format); err != nil {
+		log.Fatalf("failed to render graph: %v", err)
+	}
+}
+
+// runNames prints the resolved table and column names of the models in a directory
+// as JSON, for review before generation.
+//
+// Usage:
+//
+//	sqlcli names -i ./models                   # JSON to stdout
+//	sqlcli names -i ./models -o names.json
+func runNames(args []string) {
+	fs := flag.NewFlagSet("names", flag.ExitOnError)
+	inputDir := fs.String("i", ".", "input directory containing model files")
+	outFile := fs.String("o", "", "output file (default stdout)")
+	_ = fs.Parse(args)
+
+	cfg, err := generator.ParseConfig(*inputDir)
+	if err != nil {
+		log.Fatalf("failed to parse config: %v", err)
+	}
+
+	models, err := generator.ParseModels(*inputDir)
+	if err != nil {
+		log.Fatalf("failed to parse models: %v", err)
+	}
+	if cfg != nil {
+		models = filterModels(models, cfg)
+	}
+	generator.ResolveRelationFields(models)
+
+	out := os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatalf("failed to create output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := generator.RenderNames(out, models); err != nil {
+		log.Fatalf("failed to render names: %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements sharded counters for high-write counting workloads.
+//
+// Incrementing a single row (views = views + 1) serializes all writers on one row
+// lock; under load this hot row becomes the bottleneck. A ShardedCounter stores each
+// logical counter as N rows (shards):
+//   - Increment updates one randomly chosen shard, spreading lock contention
+//   - Value sums all shards of the counter
+//
+// Counter table layout (see ShardedCounterDDL):
+//
+//	counter_name  VARCHAR(255)  -- logical counter, e.g. "post:42"
+//	shard         INT           -- 0 .. N-1
+//	counter_value BIGINT        -- partial count
+//	PRIMARY KEY (counter_name, shard)
+//
+// The generator emits the table name, DDL and a constructor for model fields
+// tagged with `db:"<column>,counter:N"`.
+//
+// Usage example:
+//
+//	views := sqlc.NewShardedCounter(session, "post_views_counter", 16)
+//
+//	// Hot path: touches one of 16 rows
+//	err := views.Increment(ctx, "post:42", 1)
+//
+//	// Read path: SUM over 16 rows
+//	total, err := views.Value(ctx, "post:42")
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Column names of the sharded counter table
+const (
+	counterNameColumn  = "counter_name"
+	counterShardColumn = "shard"
+	counterValueColumn = "counter_value"
+)
+
+// ShardedCounterDDL returns a portable CREATE TABLE statement for a sharded counter table.
+// The statement works on MySQL, PostgreSQL and SQLite.
+//
+// Example:
+//
+//	_, err := session.Exec(ctx, sqlc.ShardedCounterDDL("post_views_counter"))
+func ShardedCounterDDL(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	%s VARCHAR(255) NOT NULL,
+	%s INT NOT NULL,
+	%s BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (%s, %s)
+)`, table, counterNameColumn, counterShardColumn, counterValueColumn, counterNameColumn, counterShardColumn)
+}
+
+// ShardedCounter is a counter spread across multiple rows to avoid hot-row contention.
+// It is safe for concurrent use.
+type ShardedCounter struct {
+	session *Session // Database session
+	table   string   // Counter table name
+	shards  int      // Number of rows per logical counter
+}
+
+// NewShardedCounter creates a counter stored in table with shards rows per logical counter.
+//
+// Parameters:
+//   - session: Database session, can be regular session or transaction session
+//   - table: Counter table name (create it with ShardedCounterDDL)
+//   - shards: Rows per logical counter; more shards mean less contention but slower reads
+//
+// Returns:
+//   - *ShardedCounter: Initialized counter
+//
+// Note:
+//   - Panics if shards is not positive (configuration error)
+//   - Changing the shard count later is safe: Value sums all existing shards
+func NewShardedCounter(session *Session, table string, shards int) *ShardedCounter {
+	if shards <= 0 {
+		panic(fmt.Sprintf("sqlc: shard count must be positive, got %d", shards))
+	}
+	return &ShardedCounter{session: session, table: table, shards: shards}
+}
+
+// Increment adds delta (which may be negative) to the counter name.
+// A random shard is updated; shard rows are created on first use.
+//
+// Example:
+//
+//	err := likes.Increment(ctx, fmt.Sprintf("post:%d", post.ID), 1)
+func (c *ShardedCounter) Increment(ctx context.Context, name string, delta int64) error {
+	shard := rand.IntN(c.shards)
+
+	updated, err := c.add(ctx, name, shard, delta)
+	if err != nil {
+		return err
+	}
+	if updated {
+		return nil
+	}
+
+	// First increment of this counter: create all shard rows, then retry
+	if err := c.initShards(ctx, name); err != nil {
+		return err
+	}
+	if _, err := c.add(ctx, name, shard, delta); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Value returns the current value of the counter name (0 if it was never incremented).
+func (c *ShardedCounter) Value(ctx context.Context, name string) (int64, error) {
+	query, args, err := sq.Select(fmt.Sprintf("COALESCE(SUM(%s), 0)", counterValueColumn)).
+		From(c.table).
+		Where(sq.Eq{counterNameColumn: name}).
+		PlaceholderFormat(c.session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	var total int64
+	if err := c.session.Get(ctx, &total, query, args...); err != nil {
+		return 0, fmt.Errorf("sqlc: counter value failed: %w", err)
+	}
+	return total, nil
+}
+
+// Reset deletes all shards of the counter name, setting its value back to 0.
+func (c *ShardedCounter) Reset(ctx context.Context, name string) error {
+	query, args, err := sq.Delete(c.table).
+		Where(sq.Eq{counterNameColumn: name}).
+		PlaceholderFormat(c.session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	if _, err := c.session.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlc: counter reset failed: %w", err)
+	}
+	return nil
+}
+
+// add updates one shard and reports whether the shard row existed.
+func (c *ShardedCounter) add(ctx context.Context, name string, shard int, delta int64) (bool, error) {
+	query, args, err := sq.Update(c.table).
+		Set(counterValueColumn, sq.Expr(counterValueColumn+" + ?", delta)).
+		Where(sq.Eq{counterNameColumn: name, counterShardColumn: shard}).
+		PlaceholderFormat(c.session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	res, err := c.session.Exec(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("sqlc: counter increment failed: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("sqlc: counter increment failed: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// initShards creates the zero-valued shard rows of a counter.
+// Existing rows are left untouched, so concurrent initialization is safe.
+func (c *ShardedCounter) initShards(ctx context.Context, name string) error {
+	b := sq.Insert(c.table).Columns(counterNameColumn, counterShardColumn, counterValueColumn)
+	for i := 0; i < c.shards; i++ {
+		b = b.Values(name, i, 0)
+	}
+	// No-op update on conflict: portable "insert if missing" across dialects
+	conflictCols := []string{counterNameColumn, counterShardColumn}
+	upsert := c.session.dialect.UpsertClause(c.table, conflictCols, []string{counterShardColumn})
+
+	query, args, err := b.Suffix(upsert).
+		PlaceholderFormat(c.session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	if _, err := c.session.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("sqlc: counter init failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package sqlc_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestShardedCounter(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	if _, err := session.Exec(ctx, sqlc.ShardedCounterDDL("post_views_counter")); err != nil {
+		t.Fatalf("failed to create counter table: %v", err)
+	}
+	views := sqlc.NewShardedCounter(session, "post_views_counter", 4)
+
+	t.Run("NeverIncremented", func(t *testing.T) {
+		v, err := views.Value(ctx, "post:0")
+		if err != nil {
+			t.Fatalf("Value failed: %v", err)
+		}
+		if v != 0 {
+			t.Errorf("expected 0, got %d", v)
+		}
+	})
+
+	t.Run("Increment", func(t *testing.T) {
+		for range 50 {
+			if err := views.Increment(ctx, "post:1", 1); err != nil {
+				t.Fatalf("Increment failed: %v", err)
+			}
+		}
+		if err := views.Increment(ctx, "post:1", -5); err != nil {
+			t.Fatalf("Increment failed: %v", err)
+		}
+		if err := views.Increment(ctx, "post:2", 7); err != nil {
+			t.Fatalf("Increment failed: %v", err)
+		}
+
+		if v, _ := views.Value(ctx, "post:1"); v != 45 {
+			t.Errorf("expected post:1 = 45, got %d", v)
+		}
+		if v, _ := views.Value(ctx, "post:2"); v != 7 {
+			t.Errorf("expected post:2 = 7, got %d", v)
+		}
+
+		var rows int
+		if err := db.QueryRow("SELECT COUNT(*) FROM post_views_counter WHERE counter_name = 'post:1'").Scan(&rows); err != nil {
+			t.Fatal(err)
+		}
+		if rows != 4 {
+			t.Errorf("expected 4 shard rows, got %d", rows)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 10 {
+					if err := views.Increment(ctx, "post:3", 1); err != nil {
+						t.Errorf("Increment failed: %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		if v, _ := views.Value(ctx, "post:3"); v != 80 {
+			t.Errorf("expected 80, got %d", v)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		if err := views.Reset(ctx, "post:1"); err != nil {
+			t.Fatalf("Reset failed: %v", err)
+		}
+		if v, _ := views.Value(ctx, "post:1"); v != 0 {
+			t.Errorf("expected 0 after reset, got %d", v)
+		}
+		if v, _ := views.Value(ctx, "post:2"); v != 7 {
+			t.Errorf("reset must not touch other counters, got %d", v)
+		}
+	})
+}
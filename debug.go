@@ -0,0 +1,252 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements debug rendering of queries with their arguments inlined.
+//
+// Parameterized SQL cannot be pasted into a database console as is. ToSQLDebug
+// replaces the placeholders with SQL literals, quoted and escaped for the dialect,
+// and WithDebug logs that rendering whenever the query runs. InterpolateForDebug
+// does the same for any statement, e.g. one taken from a log or RecentQueries.
+//
+// Usage example:
+//
+//	sql, err := userRepo.Query().Where(generated.User.Name.Eq("O'Brien")).ToSQLDebug()
+//	// SELECT ... FROM users WHERE users.name = 'O''Brien'
+//
+// Note:
+//   - Debug output is for humans only; never execute it, use ToSQL() and bound arguments
+package sqlc
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// ToSQLDebug returns the SQL of the query with arguments interpolated as literals,
+// ready to be pasted into a database console.
+//
+// Returns:
+//   - string: SQL with placeholders replaced by quoted values
+//   - error: Query building error or an argument that cannot be rendered
+//
+// Example:
+//
+//	sql, _ := userRepo.Query().Where(generated.User.ID.In(1, 2)).Limit(10).ToSQLDebug()
+//	fmt.Println(sql) // SELECT ... FROM users WHERE users.id IN (1,2) LIMIT 10
+func (q *QueryBuilder[T]) ToSQLDebug() (string, error) {
+	query, args, err := q.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return interpolate(q.session.dialect, query, args)
+}
+
+// WithDebug logs the interpolated SQL of the query (see ToSQLDebug) each time it runs.
+// Applied by Find (and Take, First, Last), Scan and Count.
+// Logs at Info level to the session logger, or slog.Default() if none is configured.
+//
+// Example:
+//
+//	users, err := userRepo.Query().
+//	    Where(generated.User.Status.Eq("active")).
+//	    WithDebug().
+//	    Find(ctx)
+func (q *QueryBuilder[T]) WithDebug() *QueryBuilder[T] {
+	q = q.Clone()
+	q.debug = true
+	return q
+}
+
+// logDebug logs query with interpolated arguments if WithDebug() was called.
+func (q *QueryBuilder[T]) logDebug(ctx context.Context, query string, args []any) {
+	if !q.debug {
+		return
+	}
+	logger := q.session.observability().Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	sql, err := interpolate(q.session.dialect, query, args)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelInfo, "sqlc debug query",
+			slog.String("query", query), slog.Any("args", args), slog.String("error", err.Error()))
+		return
+	}
+	logger.LogAttrs(ctx, slog.LevelInfo, "sqlc debug query", slog.String("query", sql))
+}
+
+// InterpolateForDebug renders query with args inlined as SQL literals of dialect: strings
+// quoted and escaped, times formatted, booleans and bytes in the dialect's syntax. The result
+// is a best-effort executable statement for debugging, e.g. to paste after EXPLAIN in a
+// database console.
+//
+// DEBUGGING ONLY: the output must never be executed by programs. Escaping is best-effort
+// and not a defense against SQL injection; always execute query with bound args.
+//
+// Parameters:
+//   - query: SQL with database/sql placeholders (?) or numbered placeholders ($1, $2, ...)
+//   - args: Arguments of the placeholders
+//   - dialect: Dialect whose literal syntax is used
+//
+// Returns:
+//   - string: SQL with placeholders replaced by literals
+//   - error: Missing argument or an argument that cannot be rendered (e.g. NaN, structs)
+//
+// Example:
+//
+//	for _, q := range session.RecentQueries(5) {
+//	    sql, err := sqlc.InterpolateForDebug(q.SQL, q.Args, sqlc.PostgreSQL)
+//	    fmt.Println("EXPLAIN ANALYZE", sql, err)
+//	}
+//
+// Note:
+//   - Numbered placeholders are used for dialects with $n placeholders, and for any
+//     dialect when the query has no ? placeholders
+//   - Placeholders inside quoted literals and identifiers are left untouched
+//   - Pointer arguments are dereferenced; nil pointers render as NULL
+func InterpolateForDebug(query string, args []any, dialect Dialect) (string, error) {
+	return interpolate(dialect, query, args)
+}
+
+// interpolate replaces the placeholders of query (? or $n) with SQL literals of args.
+// Placeholders inside quoted literals and identifiers are left untouched.
+func interpolate(dialect Dialect, query string, args []any) (string, error) {
+	question, numbered := clause.Placeholders(query)
+	dollar := dialect.PlaceholderFormat() == sq.Dollar || (question == 0 && numbered > 0)
+	var b strings.Builder
+	next := 0 // Next argument for ? placeholders
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy the quoted section, including doubled quote escapes
+			end := i + 1
+			for end < len(query) && query[end] != c {
+				end++
+			}
+			b.WriteString(query[i:min(end+1, len(query))])
+			i = end
+		case c == '?' && !dollar:
+			if next >= len(args) {
+				return "", fmt.Errorf("sqlc: missing argument for placeholder %d", next+1)
+			}
+			lit, err := literal(dialect, args[next])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(lit)
+			next++
+		case c == '$' && dollar && i+1 < len(query) && isDigit(query[i+1]):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			n, _ := strconv.Atoi(query[i+1 : end])
+			if n < 1 || n > len(args) {
+				return "", fmt.Errorf("sqlc: missing argument for placeholder $%d", n)
+			}
+			lit, err := literal(dialect, args[n-1])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(lit)
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// literal renders v as a SQL literal for dialect.
+func literal(dialect Dialect, v any) (string, error) {
+	// Nil pointers are NULL, also for pointer types implementing driver.Valuer
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return "NULL", nil
+	}
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			return "", fmt.Errorf("sqlc: failed to render argument: %w", err)
+		}
+		v = dv
+	}
+
+	// Dereference pointers (*string, *time.Time, ...)
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		return literal(dialect, rv.Elem().Interface())
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return quoteString(dialect, x), nil
+	case []byte:
+		if x == nil {
+			return "NULL", nil
+		}
+		if dialect.Name() == PostgreSQL.Name() {
+			return `'\x` + hex.EncodeToString(x) + `'`, nil
+		}
+		return "X'" + hex.EncodeToString(x) + "'", nil
+	case bool:
+		switch {
+		case dialect.Name() == PostgreSQL.Name() && x:
+			return "TRUE", nil
+		case dialect.Name() == PostgreSQL.Name():
+			return "FALSE", nil
+		case x:
+			return "1", nil
+		default:
+			return "0", nil
+		}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(x), nil
+	case float32:
+		return formatFloat(float64(x), 32)
+	case float64:
+		return formatFloat(x, 64)
+	case time.Time:
+		layout := "2006-01-02 15:04:05.999999"
+		if dialect.Name() == PostgreSQL.Name() {
+			layout += "Z07:00"
+		}
+		return "'" + x.Format(layout) + "'", nil
+	case fmt.Stringer:
+		return quoteString(dialect, x.String()), nil
+	default:
+		return "", fmt.Errorf("sqlc: cannot render argument of type %T", v)
+	}
+}
+
+func formatFloat(f float64, bits int) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("sqlc: cannot render float %v", f)
+	}
+	return strconv.FormatFloat(f, 'g', -1, bits), nil
+}
+
+// quoteString quotes s as a string literal.
+// MySQL also treats backslash as an escape character in string literals.
+func quoteString(dialect Dialect, s string) string {
+	if dialect.Name() == MySQL.Name() {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,139 @@
+package sqlc_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestToSQLDebug(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name    string
+		dialect sqlc.Dialect
+		expr    clause.Expression
+		want    string
+	}{
+		{
+			name:    "MySQLEscapes",
+			dialect: sqlc.MySQL,
+			expr:    clause.Expr{SQL: "a = ? AND b = ? AND c = ?", Vars: []any{`O'Brien \ co`, true, nil}},
+			want:    `a = 'O''Brien \\ co' AND b = 1 AND c = NULL`,
+		},
+		{
+			name:    "PostgresTypes",
+			dialect: sqlc.PostgreSQL,
+			expr:    clause.Expr{SQL: "a = ? AND b = ? AND c = ? AND d = ?", Vars: []any{`O'Brien \ co`, false, ts, []byte{0xde, 0xad}}},
+			want:    `a = 'O''Brien \ co' AND b = FALSE AND c = '2024-05-06 07:08:09Z' AND d = '\xdead'`,
+		},
+		{
+			name:    "SQLiteNumbers",
+			dialect: sqlc.SQLite,
+			expr:    clause.Expr{SQL: "a = ? AND b = ? AND c = ?", Vars: []any{int64(-3), 1.5, []byte{0x01}}},
+			want:    `a = -3 AND b = 1.5 AND c = X'01'`,
+		},
+		{
+			name:    "QuotedPlaceholder",
+			dialect: sqlc.SQLite,
+			expr:    clause.Expr{SQL: "a = '?' AND b = ?", Vars: []any{"x"}},
+			want:    `a = '?' AND b = 'x'`,
+		},
+		{
+			name:    "PostgresNumbering",
+			dialect: sqlc.PostgreSQL,
+			expr:    clause.Expr{SQL: "a IN (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", Vars: []any{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+			want:    `a IN (1, 2, 3, 4, 5, 6, 7, 8, 9, 10)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := sqlc.NewSession(nil, tt.dialect)
+			got, err := sqlc.Query[GenUser](session).Where(tt.expr).ToSQLDebug()
+			if err != nil {
+				t.Fatalf("ToSQLDebug() error = %v", err)
+			}
+			want := "SELECT id, username, email, created_at FROM users WHERE " + tt.want
+			if got != want {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+
+	t.Run("UnrenderableArgument", func(t *testing.T) {
+		session := sqlc.NewSession(nil, sqlc.SQLite)
+		for _, v := range []any{math.NaN(), struct{}{}} {
+			q := sqlc.Query[GenUser](session).Where(clause.Expr{SQL: "a = ?", Vars: []any{v}})
+			if _, err := q.ToSQLDebug(); err == nil {
+				t.Errorf("expected error for %T", v)
+			}
+		}
+	})
+}
+
+func TestInterpolateForDebug(t *testing.T) {
+	name := "bob"
+	var missing *string
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name    string
+		dialect sqlc.Dialect
+		query   string
+		args    []any
+		want    string
+	}{
+		{"QuestionMarks", sqlc.MySQL, "SELECT * FROM users WHERE name = ? AND created_at > ?", []any{"O'Brien", ts}, "SELECT * FROM users WHERE name = 'O''Brien' AND created_at > '2024-05-06 07:08:09'"},
+		{"Numbered", sqlc.PostgreSQL, "UPDATE users SET name = $2 WHERE id = $1", []any{7, "bob"}, "UPDATE users SET name = 'bob' WHERE id = 7"},
+		// Numbered placeholders from a log, rendered for another dialect
+		{"NumberedOnSQLite", sqlc.SQLite, "DELETE FROM users WHERE id = $1", []any{int64(3)}, "DELETE FROM users WHERE id = 3"},
+		{"Pointers", sqlc.SQLite, "INSERT INTO users (name, bio) VALUES (?, ?)", []any{&name, missing}, "INSERT INTO users (name, bio) VALUES ('bob', NULL)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sqlc.InterpolateForDebug(tt.query, tt.args, tt.dialect)
+			if err != nil {
+				t.Fatalf("InterpolateForDebug() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := sqlc.InterpolateForDebug("SELECT ? + ?", []any{1}, sqlc.SQLite); err == nil {
+		t.Error("expected error for missing argument")
+	}
+}
+
+func TestWithDebug(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	session := sqlc.NewSession(db, sqlc.SQLite, sqlc.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	memberRepo := sqlc.NewRepository[Member](session)
+	name := field.String{}.WithColumn("name")
+
+	if _, err := memberRepo.Query().Where(name.Eq("O'Brien")).WithDebug().Find(ctx); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `WHERE name = 'O''Brien'`) {
+		t.Errorf("expected interpolated query in log, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err := memberRepo.Query().Where(name.Eq("quiet")).Find(ctx); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "quiet") {
+		t.Errorf("expected no debug log without WithDebug, got %s", buf.String())
+	}
+}
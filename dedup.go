@@ -0,0 +1,114 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements opt-in deduplication of concurrent identical SELECTs.
+//
+// Dashboard-style endpoints often fan out to many widgets that issue the same
+// lookups at the same time. With WithQueryDedup, concurrent Find and Count calls
+// with identical SQL and arguments share one database execution (singleflight):
+// the first caller runs the query, callers arriving while it is in flight wait
+// for its result. Every caller receives its own copy of the models.
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithQueryDedup())
+//
+//	// Issued concurrently by several widgets: one SELECT reaches the database
+//	plans, err := planRepo.Query().Where(generated.Plan.Active.Eq(true)).Find(ctx)
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// WithQueryDedup collapses concurrent identical SELECTs issued through the session
+// into a single database execution.
+//
+// Applies to Find (and Take, First, Last) and Count. Queries are identical when
+// the model type, SQL text and arguments are equal. Results are not cached:
+// a query issued after the shared execution finished runs again.
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.MySQL, sqlc.WithQueryDedup())
+//
+// Note:
+//   - Transaction sessions never deduplicate, they must see their own writes
+//   - Reads with a UsePrimary context, or a ReadYourWrites context that wrote, are not shared
+//   - Locking reads (ForUpdate/ForShare) are never deduplicated
+//   - Models are copied shallowly: slices, maps and pointers inside a model are shared
+//     between callers and must not be mutated in place
+//   - The shared execution is not canceled when one waiting caller's context is;
+//     each caller stops waiting when its own context is done
+func WithQueryDedup() SessionOption {
+	return func(s *Session) {
+		s.dedup = &singleflight.Group{}
+	}
+}
+
+// dedupKey identifies a query for deduplication.
+func dedupKey[T any](query string, args []any) string {
+	var b strings.Builder
+	t := reflect.TypeFor[T]()
+	b.WriteString(t.PkgPath())
+	b.WriteByte('.')
+	b.WriteString(t.String())
+	b.WriteByte(0)
+	b.WriteString(query)
+	for _, arg := range args {
+		b.WriteByte(0)
+		fmt.Fprintf(&b, "%T:%#v", arg, arg)
+	}
+	return b.String()
+}
+
+// deduplicate runs fn once for concurrent callers with the same key and shares its result.
+// Runs fn directly when deduplication is disabled or not applicable.
+func (s *Session) deduplicate(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	if s.dedup == nil || s.tx != nil || primaryOnly(ctx) {
+		return fn(ctx)
+	}
+	// The shared execution outlives the caller that started it
+	ch := s.dedup.DoChan(key, func() (any, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// findShared runs the SELECT of Find, sharing the execution with identical concurrent queries.
+// The returned models are a private copy for the caller.
+func (q *QueryBuilder[T]) findShared(ctx context.Context, query string, args []any) ([]*T, error) {
+	if q.session.dedup == nil || q.session.tx != nil || q.lock.Strength != LockNone {
+		return q.findRows(ctx, query, args)
+	}
+	v, err := q.session.deduplicate(ctx, dedupKey[T](query, args), func(ctx context.Context) (any, error) {
+		return q.findRows(ctx, query, args)
+	})
+	if err != nil {
+		return nil, err
+	}
+	shared := v.([]*T)
+	results := make([]*T, len(shared))
+	for i, m := range shared {
+		c := *m
+		results[i] = &c
+	}
+	return results, nil
+}
+
+// findRows runs the SELECT of Find.
+func (q *QueryBuilder[T]) findRows(ctx context.Context, query string, args []any) ([]*T, error) {
+	var results []*T
+	err := q.reader(ctx).withPlanCacheMode(ctx, q.plan.CacheMode, func(s *Session) error {
+		return s.Select(ctx, &results, q.plan.annotate(query), args...)
+	})
+	return results, err
+}
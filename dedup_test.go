@@ -0,0 +1,115 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestQueryDedup(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // Single in-memory database
+	ctx := context.Background()
+
+	// The guard counts SELECTs and holds them until released
+	var selects atomic.Int32
+	entered := make(chan struct{}, 16)
+	release := make(chan struct{})
+	hold := func(ctx context.Context, query string) error {
+		if strings.HasPrefix(query, "SELECT") {
+			selects.Add(1)
+			entered <- struct{}{}
+			<-release
+		}
+		return nil
+	}
+	session := sqlc.NewSession(db, sqlc.SQLite, sqlc.WithQueryDedup(), sqlc.WithSQLGuard(hold))
+	memberRepo := sqlc.NewRepository[Member](session)
+	if err := memberRepo.Create(ctx, &Member{Name: "Dedup", Email: "dedup@test.com", Level: 1, DepartmentID: 1, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	level := field.Number[int]{}.WithColumn("level")
+
+	// run starts n identical Finds once the first one is executing and returns all results
+	run := func(t *testing.T, n int, query func(ctx context.Context) ([]*Member, error)) [][]*Member {
+		t.Helper()
+		selects.Store(0)
+		results := make([][]*Member, n)
+		var wg sync.WaitGroup
+		for i := range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				members, err := query(ctx)
+				if err != nil {
+					t.Errorf("Find failed: %v", err)
+				}
+				results[i] = members
+			}()
+			if i == 0 {
+				<-entered
+			}
+		}
+		time.Sleep(50 * time.Millisecond) // Let the other callers join the flight
+		close(release)
+		wg.Wait()
+		release = make(chan struct{})
+		return results
+	}
+
+	t.Run("SharedExecution", func(t *testing.T) {
+		results := run(t, 5, memberRepo.Query().Where(level.Eq(1)).Find)
+		if n := selects.Load(); n != 1 {
+			t.Errorf("expected 1 SELECT, got %d", n)
+		}
+		for _, members := range results {
+			if len(members) != 1 || members[0].Name != "Dedup" {
+				t.Fatalf("unexpected result %v", members)
+			}
+		}
+		// Every caller owns its models
+		results[0][0].Name = "Changed"
+		if results[1][0] == results[0][0] || results[1][0].Name != "Dedup" {
+			t.Error("expected callers to receive independent copies")
+		}
+	})
+
+	t.Run("DifferentArgs", func(t *testing.T) {
+		var i atomic.Int32
+		run(t, 3, func(ctx context.Context) ([]*Member, error) {
+			return memberRepo.Query().Where(level.Eq(int(i.Add(1)))).Find(ctx)
+		})
+		if n := selects.Load(); n != 3 {
+			t.Errorf("expected 3 SELECTs, got %d", n)
+		}
+	})
+
+	t.Run("CallerCanceled", func(t *testing.T) {
+		selects.Store(0)
+		done := make(chan error, 1)
+		go func() {
+			_, err := memberRepo.Query().Count(ctx)
+			done <- err
+		}()
+		<-entered
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := memberRepo.Query().Count(canceled); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled for waiting caller, got %v", err)
+		}
+		close(release)
+		if err := <-done; err != nil {
+			t.Errorf("shared execution failed: %v", err)
+		}
+		release = make(chan struct{})
+	})
+}
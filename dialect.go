@@ -5,9 +5,11 @@
 //   - Database identification (MySQL, PostgreSQL, SQLite)
 //   - Placeholder format (? vs $1, $2)
 //   - Upsert syntax (ON DUPLICATE KEY vs ON CONFLICT)
+//   - Row locking clauses (FOR UPDATE / FOR SHARE)
+//   - CAST target type names (bigint vs SIGNED vs INTEGER)
 //
 // Currently supported databases:
-//   - MySQL 5.7+
+//   - MySQL 5.7+ (MariaDB 10.5+ via MariaDBDialect)
 //   - PostgreSQL 12+
 //   - SQLite 3.24+ (with UPSERT support)
 //
@@ -28,14 +30,126 @@ import (
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
 )
 
 var (
 	SQLite     = SQLiteDialect{}
 	MySQL      = MySQLDialect{}
 	PostgreSQL = PostgreSQLDialect{}
+	MariaDB    = MariaDBDialect{}
 )
 
+// InsertReturning is optionally implemented by dialects that support
+// INSERT ... RETURNING. BatchCreate uses it to backfill auto-increment IDs.
+type InsertReturning interface {
+	InsertReturning() bool
+}
+
+// PlaceholderLimit is optionally implemented by dialects that cap the number of
+// bind variables per statement. BatchCreate splits inserts to stay under the cap.
+type PlaceholderLimit interface {
+	MaxPlaceholders() int
+}
+
+// InsertIgnore is optionally implemented by dialects that skip conflicting rows with
+// INSERT IGNORE rather than ON CONFLICT DO NOTHING. CreateOrIgnore uses it.
+type InsertIgnore interface {
+	InsertIgnore() bool
+}
+
+// UpsertOutcome is optionally implemented by dialects that can tell whether an upsert
+// inserted its row or updated an existing one. UpsertInserted returns the RETURNING
+// expression that is true for an inserted row, or "" when the affected row count tells
+// them apart (1 inserted, 2 updated). Upsert uses it to report ChangeCreate to indexers.
+type UpsertOutcome interface {
+	UpsertInserted() string
+}
+
+// RecursiveCTE is optionally implemented by dialects that support WITH RECURSIVE.
+// PreloadTree uses it to load a whole tree with one query instead of one per level.
+type RecursiveCTE interface {
+	RecursiveCTE() bool
+}
+
+// ConflictPredicate is optionally implemented by dialects whose ON CONFLICT target accepts
+// an index predicate, ON CONFLICT (cols) WHERE predicate, which selects a partial unique
+// index. Upsert uses it for OnConflictWhere and OnConflictLive.
+type ConflictPredicate interface {
+	UpsertWhereClause(tableName string, conflictCols []string, predicate string, updateCols []string) string
+}
+
+// Merger is optionally implemented by dialects that support the SQL standard MERGE
+// statement (PostgreSQL 15+). MergeRowsSource renders in-memory rows as a MERGE source
+// whose columns have the types of the target table's columns. Repository.Merge uses it.
+type Merger interface {
+	MergeRowsSource(tableName string, columns []string, rows [][]any) (string, []any, error)
+}
+
+// RowLocker is optionally implemented by dialects that support row-level locking.
+// LockClause generates the locking clause appended to SELECT statements; dialects
+// without it (or returning "") run ForUpdate/ForShare queries without locking.
+//
+// Implementations:
+//   - MySQLDialect: "FOR UPDATE NOWAIT", "LOCK IN SHARE MODE"
+//   - PostgreSQLDialect: "FOR SHARE SKIP LOCKED"
+//   - SQLiteDialect: "" (database-level locking only)
+type RowLocker interface {
+	LockClause(mode LockMode) string
+}
+
+// lockClause returns the row locking clause of dialect d for mode, or "" if d has none.
+func lockClause(d Dialect, mode LockMode) string {
+	if l, ok := d.(RowLocker); ok {
+		return l.LockClause(mode)
+	}
+	return ""
+}
+
+// typeNamer returns the clause.TypeNamer binding the expressions of dialect d.
+// Dialects optionally implement clause.TypeNamer (CastType) to translate the portable type
+// names of clause.Cast into their own spelling, e.g. "bigint" to "SIGNED" on MySQL; for
+// other dialects the type names are passed through unchanged.
+func typeNamer(d Dialect) clause.TypeNamer {
+	if n, ok := d.(clause.TypeNamer); ok {
+		return n
+	}
+	return passthroughTypes{d}
+}
+
+// passthroughTypes adds an identity CastType to a dialect without one.
+type passthroughTypes struct {
+	Dialect
+}
+
+func (passthroughTypes) CastType(name string) string { return name }
+
+// recursiveCTE reports whether dialect d supports WITH RECURSIVE.
+func recursiveCTE(d Dialect) bool {
+	r, ok := d.(RecursiveCTE)
+	return ok && r.RecursiveCTE()
+}
+
+// insertIgnore reports whether dialect d uses INSERT IGNORE.
+func insertIgnore(d Dialect) bool {
+	i, ok := d.(InsertIgnore)
+	return ok && i.InsertIgnore()
+}
+
+// maxPlaceholders returns the bind variable limit of dialect d, or 0 if unknown.
+func maxPlaceholders(d Dialect) int {
+	if l, ok := d.(PlaceholderLimit); ok {
+		return l.MaxPlaceholders()
+	}
+	return 0
+}
+
+// insertReturning reports whether dialect d supports INSERT ... RETURNING.
+func insertReturning(d Dialect) bool {
+	r, ok := d.(InsertReturning)
+	return ok && r.InsertReturning()
+}
+
 // Dialect abstracts database-specific SQL features.
 // Different databases have SQL syntax differences, and the Dialect interface provides a unified abstraction layer.
 //
@@ -84,19 +198,95 @@ type Dialect interface {
 	UpsertClause(tableName string, conflictCols []string, updateCols []string) string
 }
 
+// LockStrength defines the strength of a row lock requested by a SELECT.
+type LockStrength int
+
+const (
+	// LockNone indicates no row locking (default).
+	LockNone LockStrength = iota
+
+	// LockForUpdate requests exclusive row locks (SELECT ... FOR UPDATE).
+	LockForUpdate
+
+	// LockForShare requests shared row locks (SELECT ... FOR SHARE).
+	LockForShare
+)
+
+// LockWait defines how a locking SELECT behaves when rows are already locked.
+type LockWait int
+
+const (
+	// LockWaitDefault blocks until the conflicting lock is released.
+	LockWaitDefault LockWait = iota
+
+	// LockNoWait fails immediately if a requested row is locked (NOWAIT).
+	LockNoWait
+
+	// LockSkipLocked silently skips rows that are locked (SKIP LOCKED).
+	LockSkipLocked
+)
+
+// LockMode combines lock strength and wait policy for a locking SELECT.
+type LockMode struct {
+	// Strength is the lock strength; LockNone disables locking.
+	Strength LockStrength
+
+	// Wait is the wait policy applied when rows are already locked.
+	Wait LockWait
+}
+
+// buildLockClause generates the standard FOR UPDATE / FOR SHARE clause
+// shared by PostgreSQL and MySQL 8.0+.
+//
+// Example:
+//
+//	buildLockClause(LockMode{Strength: LockForUpdate, Wait: LockSkipLocked})
+//	// Returns: "FOR UPDATE SKIP LOCKED"
+func buildLockClause(mode LockMode) string {
+	var clause string
+	switch mode.Strength {
+	case LockForUpdate:
+		clause = "FOR UPDATE"
+	case LockForShare:
+		clause = "FOR SHARE"
+	default:
+		return ""
+	}
+
+	switch mode.Wait {
+	case LockNoWait:
+		clause += " NOWAIT"
+	case LockSkipLocked:
+		clause += " SKIP LOCKED"
+	}
+	return clause
+}
+
+// splitCastType splits a type name into its lower-cased base name and
+// any parenthesized modifier, e.g. "DECIMAL(10,2)" -> ("decimal", "(10,2)").
+func splitCastType(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	base, mod := name, ""
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		base, mod = strings.TrimSpace(name[:i]), name[i:]
+	}
+	return strings.ToLower(base), mod
+}
+
 // buildOnConflictUpsert generates ON CONFLICT ... DO UPDATE SET clause.
 // This is the Upsert syntax used by PostgreSQL and SQLite.
 //
 // Syntax format:
 //
-//	ON CONFLICT (conflict_columns) DO UPDATE SET col1=EXCLUDED.col1, col2=EXCLUDED.col2
+//	ON CONFLICT (conflict_columns) [WHERE predicate] DO UPDATE SET col1=EXCLUDED.col1, col2=EXCLUDED.col2
 //	or
-//	ON CONFLICT (conflict_columns) DO NOTHING
+//	ON CONFLICT (conflict_columns) [WHERE predicate] DO NOTHING
 //
 // Parameters:
 //   - conflictCols: Conflict detection columns (e.g., ["email"] or ["user_id", "product_id"])
 //   - updateCols: Columns to update when conflict occurs (e.g., ["name", "updated_at"])
 //   - excludedPrefix: Reference to EXCLUDED table (PostgreSQL: "EXCLUDED", SQLite: "excluded")
+//   - predicate: Index predicate of a partial unique index (e.g., "deleted_at IS NULL"), or ""
 //
 // Returns:
 //   - string: Complete ON CONFLICT clause
@@ -108,29 +298,32 @@ type Dialect interface {
 // Example:
 //
 //	// PostgreSQL
-//	buildOnConflictUpsert([]string{"email"}, []string{"name", "updated_at"}, "EXCLUDED")
+//	buildOnConflictUpsert([]string{"email"}, []string{"name", "updated_at"}, "EXCLUDED", "")
 //	// Returns: "ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name,updated_at=EXCLUDED.updated_at"
 //
-//	// SQLite
-//	buildOnConflictUpsert([]string{"email"}, []string{"name"}, "excluded")
-//	// Returns: "ON CONFLICT (email) DO UPDATE SET name=excluded.name"
-func buildOnConflictUpsert(conflictCols, updateCols []string, excludedPrefix string) string {
+//	// SQLite, partial unique index on live rows
+//	buildOnConflictUpsert([]string{"email"}, []string{"name"}, "excluded", "deleted_at IS NULL")
+//	// Returns: "ON CONFLICT (email) WHERE deleted_at IS NULL DO UPDATE SET name=excluded.name"
+func buildOnConflictUpsert(conflictCols, updateCols []string, excludedPrefix, predicate string) string {
 	// No conflict columns, cannot generate valid Upsert clause
 	if len(conflictCols) == 0 {
 		return ""
 	}
 
-	// Build conflict target: ON CONFLICT (col1, col2, ...)
-	conflictTarget := strings.Join(conflictCols, ", ")
+	// Build conflict target: ON CONFLICT (col1, col2, ...) [WHERE predicate]
+	conflictTarget := "(" + strings.Join(conflictCols, ", ") + ")"
+	if predicate != "" {
+		conflictTarget += " WHERE " + predicate
+	}
 
 	// If no update columns, generate DO NOTHING
 	if len(updateCols) == 0 {
-		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", conflictTarget)
+		return fmt.Sprintf("ON CONFLICT %s DO NOTHING", conflictTarget)
 	}
 
 	// Build DO UPDATE SET clause
 	// Format: col1=EXCLUDED.col1, col2=EXCLUDED.col2, ...
-	clause := fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET ", conflictTarget)
+	clause := fmt.Sprintf("ON CONFLICT %s DO UPDATE SET ", conflictTarget)
 	updates := make([]string, len(updateCols))
 	for i, col := range updateCols {
 		// EXCLUDED is a special table reference containing the proposed insert row
@@ -141,6 +334,21 @@ func buildOnConflictUpsert(conflictCols, updateCols []string, excludedPrefix str
 	return clause + strings.Join(updates, ", ")
 }
 
+// buildOnConflictDoNothing generates the ON CONFLICT ... DO NOTHING clause used by
+// CreateOrIgnore on PostgreSQL and SQLite. Without conflictCols any unique or
+// primary key violation is ignored.
+//
+// Example:
+//
+//	buildOnConflictDoNothing([]string{"email"}) // "ON CONFLICT (email) DO NOTHING"
+//	buildOnConflictDoNothing(nil)               // "ON CONFLICT DO NOTHING"
+func buildOnConflictDoNothing(conflictCols []string) string {
+	if len(conflictCols) == 0 {
+		return "ON CONFLICT DO NOTHING"
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(conflictCols, ", "))
+}
+
 // MySQLDialect implements MySQL database dialect.
 //
 // MySQL features:
@@ -206,6 +414,89 @@ func (d MySQLDialect) UpsertClause(tableName string, conflictCols []string, upda
 	return clause + strings.Join(updates, ", ")
 }
 
+// LockClause generates MySQL's row locking clause.
+// Shared locks without a wait policy use LOCK IN SHARE MODE, which works on both
+// MySQL 5.7 and 8.0; NOWAIT and SKIP LOCKED require MySQL 8.0+.
+//
+// Example:
+//
+//	dialect.LockClause(LockMode{Strength: LockForShare})
+//	// Returns: "LOCK IN SHARE MODE"
+func (d MySQLDialect) LockClause(mode LockMode) string {
+	if mode.Strength == LockForShare && mode.Wait == LockWaitDefault {
+		return "LOCK IN SHARE MODE"
+	}
+	return buildLockClause(mode)
+}
+
+// CastType translates a portable type name into MySQL's CAST vocabulary.
+// MySQL only accepts a fixed set of CAST targets (SIGNED, UNSIGNED, CHAR, DECIMAL, DOUBLE, ...),
+// so integer and string types are mapped onto them.
+//
+// Example:
+//
+//	dialect.CastType("bigint") // Returns: "SIGNED"
+//	dialect.CastType("text")   // Returns: "CHAR"
+func (d MySQLDialect) CastType(name string) string {
+	base, mod := splitCastType(name)
+	switch base {
+	case "int", "integer", "bigint", "smallint", "tinyint", "signed":
+		return "SIGNED"
+	case "unsigned":
+		return "UNSIGNED"
+	case "text", "string", "varchar", "char":
+		return "CHAR" + mod
+	case "float", "real", "double", "double precision":
+		return "DOUBLE"
+	case "numeric", "decimal":
+		return "DECIMAL" + mod
+	case "timestamp", "datetime":
+		return "DATETIME" + mod
+	case "bytes", "blob", "bytea", "binary":
+		return "BINARY" + mod
+	}
+	return name
+}
+
+// MaxPlaceholders returns MySQL's limit of 65535 bind variables per prepared statement.
+func (d MySQLDialect) MaxPlaceholders() int {
+	return 65535
+}
+
+// InsertIgnore reports that MySQL skips conflicting rows with INSERT IGNORE.
+func (d MySQLDialect) InsertIgnore() bool {
+	return true
+}
+
+// UpsertInserted reports that MySQL tells inserted rows by the affected row count of
+// ON DUPLICATE KEY UPDATE: 1 for an inserted row, 2 for an updated one.
+func (d MySQLDialect) UpsertInserted() string {
+	return ""
+}
+
+// RecursiveCTE reports that MySQL (8.0+) supports WITH RECURSIVE.
+func (d MySQLDialect) RecursiveCTE() bool {
+	return true
+}
+
+// MariaDBDialect implements the MariaDB dialect: MySQL syntax plus INSERT ... RETURNING
+// (MariaDB 10.5+), so BatchCreate backfills auto-increment IDs.
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.MariaDB)
+//
+// Note:
+//   - Name() is "mysql", so MySQL-specific behavior and drivers apply unchanged
+type MariaDBDialect struct {
+	MySQLDialect
+}
+
+// InsertReturning reports that MariaDB supports INSERT ... RETURNING.
+func (d MariaDBDialect) InsertReturning() bool {
+	return true
+}
+
 // PostgreSQLDialect implements PostgreSQL database dialect.
 //
 // PostgreSQL features:
@@ -258,7 +549,89 @@ func (d PostgreSQLDialect) PlaceholderFormat() sq.PlaceholderFormat {
 //	dialect.UpsertClause("users", []string{"email"}, []string{"name", "updated_at"})
 //	// Returns: "ON CONFLICT (email) DO UPDATE SET name=EXCLUDED.name,updated_at=EXCLUDED.updated_at"
 func (d PostgreSQLDialect) UpsertClause(tableName string, conflictCols []string, updateCols []string) string {
-	return buildOnConflictUpsert(conflictCols, updateCols, "EXCLUDED")
+	return buildOnConflictUpsert(conflictCols, updateCols, "EXCLUDED", "")
+}
+
+// UpsertWhereClause generates PostgreSQL's Upsert clause targeting a partial unique index.
+// The predicate must imply the index predicate for PostgreSQL to infer the index.
+//
+// Example:
+//
+//	dialect.UpsertWhereClause("users", []string{"email"}, "deleted_at IS NULL", []string{"name"})
+//	// Returns: "ON CONFLICT (email) WHERE deleted_at IS NULL DO UPDATE SET name=EXCLUDED.name"
+func (d PostgreSQLDialect) UpsertWhereClause(tableName string, conflictCols []string, predicate string, updateCols []string) string {
+	return buildOnConflictUpsert(conflictCols, updateCols, "EXCLUDED", predicate)
+}
+
+// LockClause generates PostgreSQL's row locking clause.
+//
+// Example:
+//
+//	dialect.LockClause(LockMode{Strength: LockForUpdate, Wait: LockNoWait})
+//	// Returns: "FOR UPDATE NOWAIT"
+func (d PostgreSQLDialect) LockClause(mode LockMode) string {
+	return buildLockClause(mode)
+}
+
+// MaxPlaceholders returns PostgreSQL's limit of 65535 bind parameters per statement.
+func (d PostgreSQLDialect) MaxPlaceholders() int {
+	return 65535
+}
+
+// InsertReturning reports that PostgreSQL supports INSERT ... RETURNING.
+func (d PostgreSQLDialect) InsertReturning() bool {
+	return true
+}
+
+// UpsertInserted returns the expression telling rows inserted by INSERT ... ON CONFLICT
+// from updated ones: an inserted row version has no deleting transaction (xmax = 0).
+func (d PostgreSQLDialect) UpsertInserted() string {
+	return "(xmax = 0)"
+}
+
+// RecursiveCTE reports that PostgreSQL supports WITH RECURSIVE.
+func (d PostgreSQLDialect) RecursiveCTE() bool {
+	return true
+}
+
+// MergeRowsSource renders rows as a MERGE source for PostgreSQL (15+).
+// The rows are sent as a single JSON parameter and expanded with jsonb_populate_recordset
+// into records of the target table's row type, so every column keeps its declared type;
+// bind parameters in a VALUES list would all be typed text.
+//
+// Example:
+//
+//	dialect.MergeRowsSource("users", []string{"email", "name"}, rows)
+//	// Returns: "(SELECT * FROM jsonb_populate_recordset(NULL::users, CAST(? AS jsonb)))"
+func (d PostgreSQLDialect) MergeRowsSource(tableName string, columns []string, rows [][]any) (string, []any, error) {
+	payload, err := jsonRecordset(columns, rows)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(SELECT * FROM jsonb_populate_recordset(NULL::" + tableName + ", CAST(? AS jsonb)))", []any{payload}, nil
+}
+
+// CastType translates a portable type name into PostgreSQL's spelling.
+// PostgreSQL accepts most standard names directly; only aliases are mapped.
+//
+// Example:
+//
+//	dialect.CastType("double") // Returns: "double precision"
+func (d PostgreSQLDialect) CastType(name string) string {
+	base, mod := splitCastType(name)
+	switch base {
+	case "string":
+		return "text"
+	case "double", "float":
+		return "double precision"
+	case "datetime":
+		return "timestamp" + mod
+	case "bytes", "blob", "binary":
+		return "bytea"
+	case "signed":
+		return "bigint"
+	}
+	return name
 }
 
 // SQLiteDialect implements SQLite database dialect.
@@ -316,5 +689,67 @@ func (d SQLiteDialect) PlaceholderFormat() sq.PlaceholderFormat {
 //	// Returns: "ON CONFLICT (email) DO UPDATE SET name=excluded.name,updated_at=excluded.updated_at"
 func (d SQLiteDialect) UpsertClause(tableName string, conflictCols []string, updateCols []string) string {
 	// SQLite uses lowercase "excluded", different from PostgreSQL's "EXCLUDED"
-	return buildOnConflictUpsert(conflictCols, updateCols, "excluded")
+	return buildOnConflictUpsert(conflictCols, updateCols, "excluded", "")
+}
+
+// UpsertWhereClause generates SQLite's Upsert clause targeting a partial unique index.
+//
+// Example:
+//
+//	dialect.UpsertWhereClause("users", []string{"email"}, "deleted_at IS NULL", []string{"name"})
+//	// Returns: "ON CONFLICT (email) WHERE deleted_at IS NULL DO UPDATE SET name=excluded.name"
+func (d SQLiteDialect) UpsertWhereClause(tableName string, conflictCols []string, predicate string, updateCols []string) string {
+	return buildOnConflictUpsert(conflictCols, updateCols, "excluded", predicate)
+}
+
+// LockClause returns an empty string for SQLite.
+// SQLite has no row-level locks; write transactions lock the whole database,
+// so locking modifiers are silently ignored.
+func (d SQLiteDialect) LockClause(mode LockMode) string {
+	return ""
+}
+
+// CastType translates a portable type name into one of SQLite's storage classes
+// (INTEGER, REAL, TEXT, NUMERIC, BLOB), which determine CAST behavior.
+//
+// Example:
+//
+//	dialect.CastType("bigint")  // Returns: "INTEGER"
+//	dialect.CastType("varchar") // Returns: "TEXT"
+func (d SQLiteDialect) CastType(name string) string {
+	base, _ := splitCastType(name)
+	switch base {
+	case "int", "integer", "bigint", "smallint", "tinyint", "signed", "unsigned", "boolean", "bool":
+		return "INTEGER"
+	case "text", "string", "varchar", "char":
+		return "TEXT"
+	case "float", "real", "double", "double precision":
+		return "REAL"
+	case "numeric", "decimal":
+		return "NUMERIC"
+	case "bytes", "blob", "bytea", "binary":
+		return "BLOB"
+	}
+	return name
+}
+
+// MaxPlaceholders returns SQLite's default SQLITE_MAX_VARIABLE_NUMBER of builds before 3.32 (999).
+func (d SQLiteDialect) MaxPlaceholders() int {
+	return 999
+}
+
+// InsertReturning reports that SQLite (3.35+) supports INSERT ... RETURNING.
+func (d SQLiteDialect) InsertReturning() bool {
+	return true
+}
+
+// RecursiveCTE reports that SQLite (3.8.3+) supports WITH RECURSIVE.
+func (d SQLiteDialect) RecursiveCTE() bool {
+	return true
+}
+
+// RowValueIn reports that SQLite only accepts a subquery on the right of a row-value IN,
+// so multi-column IN lists (clause.TupleIn) are expanded to AND/OR conditions.
+func (d SQLiteDialect) RowValueIn() bool {
+	return false
 }
@@ -0,0 +1,158 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements detection of drift between model structs and their schemas.
+//
+// Schemas are generated from model structs. When a field is added to a model and the
+// code generator is not re-run, the schema does not select the new column and the
+// field is silently left at its zero value in every result. CheckColumns and
+// WithColumnCheck detect such drift:
+//   - CheckColumns[T]() compares a model with its schema, e.g. in a unit test
+//   - WithColumnCheck(fn) checks every Find that selects the schema's default columns
+//
+// Usage example:
+//
+//	func TestSchemasUpToDate(t *testing.T) {
+//	    if err := sqlc.CheckColumns[models.User](); err != nil {
+//	        t.Fatal(err) // run the code generator
+//	    }
+//	}
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ColumnDrift describes the differences between a model struct and the columns selected by its schema.
+type ColumnDrift struct {
+	Model   string   // Model type name
+	Table   string   // Table name of the schema
+	Missing []string // Columns of db-tagged fields the schema does not select (always zero in results)
+	Unknown []string // Selected columns without a matching struct field (scanning fails)
+}
+
+func (d *ColumnDrift) Error() string {
+	var parts []string
+	if len(d.Missing) > 0 {
+		parts = append(parts, "not selected: "+strings.Join(d.Missing, ", "))
+	}
+	if len(d.Unknown) > 0 {
+		parts = append(parts, "no matching field: "+strings.Join(d.Unknown, ", "))
+	}
+	return fmt.Sprintf("sqlc: schema of %s (%s) is out of date; %s", d.Model, d.Table, strings.Join(parts, "; "))
+}
+
+// driftCache holds the computed drift per model type (nil *ColumnDrift when in sync).
+var driftCache sync.Map // map[reflect.Type]*ColumnDrift
+
+// CheckColumns compares the db-tagged fields of model T with the columns selected by
+// its registered schema. Returns a *ColumnDrift error if they differ.
+//
+// Type parameter:
+//   - T: Model type (must be registered)
+//
+// Note:
+//   - Only fields with an explicit db tag are considered; db:"-" fields are ignored
+//   - Fields of embedded structs are included, like sqlx scans them
+func CheckColumns[T any]() error {
+	if drift := columnDrift[T](); drift != nil {
+		return drift
+	}
+	return nil
+}
+
+// columnDrift returns the cached drift of T, or nil if T matches its schema.
+func columnDrift[T any]() *ColumnDrift {
+	typ := reflect.TypeFor[T]()
+	if v, ok := driftCache.Load(typ); ok {
+		return v.(*ColumnDrift)
+	}
+
+	schema := LoadSchema[T]()
+	fields := taggedColumns(typ)
+	selected := schema.SelectColumns()
+
+	drift := &ColumnDrift{Model: typ.String(), Table: schema.TableName()}
+	for _, col := range fields {
+		if !slices.Contains(selected, col) {
+			drift.Missing = append(drift.Missing, col)
+		}
+	}
+	for _, col := range selected {
+		if !slices.Contains(fields, col) {
+			drift.Unknown = append(drift.Unknown, col)
+		}
+	}
+	if len(drift.Missing) == 0 && len(drift.Unknown) == 0 {
+		drift = nil
+	}
+	driftCache.Store(typ, drift)
+	return drift
+}
+
+// taggedColumns returns the column names of the db-tagged fields of struct type typ.
+func taggedColumns(typ reflect.Type) []string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	var cols []string
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		name, _, _ := strings.Cut(tag, ",")
+		switch {
+		case name == "-":
+		case f.Anonymous && !hasTag:
+			cols = append(cols, taggedColumns(f.Type)...)
+		case hasTag && name != "" && f.IsExported():
+			cols = append(cols, name)
+		}
+	}
+	return cols
+}
+
+// WithColumnCheck checks the model of every Find (and Take, First, Last) that selects
+// the schema's default columns and calls onDrift when model and schema differ.
+// Returning an error from onDrift fails the query (strict mode, e.g. in tests);
+// returning nil only reports the drift. Transaction sessions inherit the check.
+//
+// Parameters:
+//   - onDrift: Called with the *ColumnDrift for every drifted query
+//
+// Example:
+//
+//	// Log drift in production
+//	sqlc.WithColumnCheck(func(ctx context.Context, drift *sqlc.ColumnDrift) error {
+//	    slog.WarnContext(ctx, "schema drift", "error", drift)
+//	    return nil
+//	})
+//
+//	// Fail queries in tests
+//	sqlc.WithColumnCheck(func(ctx context.Context, drift *sqlc.ColumnDrift) error {
+//	    return drift
+//	})
+//
+// Note:
+//   - The comparison is computed once per model type; the callback runs on every drifted query
+func WithColumnCheck(onDrift func(ctx context.Context, drift *ColumnDrift) error) SessionOption {
+	return func(s *Session) {
+		s.columnCheck = onDrift
+	}
+}
+
+// checkColumns runs the session's column check for a Find with default columns.
+func (q *QueryBuilder[T]) checkColumns(ctx context.Context) error {
+	if q.session.columnCheck == nil || len(q.columns) > 0 {
+		return nil
+	}
+	if drift := columnDrift[T](); drift != nil {
+		return q.session.columnCheck(ctx, drift)
+	}
+	return nil
+}
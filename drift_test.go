@@ -0,0 +1,100 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// DriftUser gained Nickname after its schema was generated, and lost Legacy.
+type DriftUser struct {
+	Base
+	Name     string `db:"name"`
+	Nickname string `db:"nickname"`
+	Note     string `db:"-"`
+}
+
+// Base is embedded like a shared model prefix.
+type Base struct {
+	ID int64 `db:"id,primaryKey"`
+}
+
+type DriftUserSchema struct{}
+
+func (DriftUserSchema) TableName() string                        { return "drift_users" }
+func (DriftUserSchema) SelectColumns() []string                  { return []string{"id", "name", "legacy"} }
+func (DriftUserSchema) InsertRow(m *DriftUser) ([]string, []any) { return nil, nil }
+func (DriftUserSchema) UpdateMap(m *DriftUser) map[string]any    { return nil }
+func (DriftUserSchema) PK(m *DriftUser) sqlc.PK                  { return sqlc.PK{Column: clause.Column{Name: "id"}} }
+func (DriftUserSchema) SetPK(m *DriftUser, val int64)            {}
+func (DriftUserSchema) AutoIncrement() bool                      { return true }
+func (DriftUserSchema) SoftDeleteColumn() string                 { return "" }
+func (DriftUserSchema) SoftDeleteValue() any                     { return nil }
+func (DriftUserSchema) SetDeletedAt(m *DriftUser)                {}
+
+func init() {
+	sqlc.RegisterSchema(DriftUserSchema{})
+}
+
+func TestQualifiedSelectColumns(t *testing.T) {
+	if got, want := sqlc.QualifiedSelectColumns[GenUser]("u"), []string{"u.id", "u.username", "u.email", "u.created_at"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := sqlc.QualifiedSelectColumns[GenUser](""); got[0] != "users.id" {
+		t.Errorf("expected table name prefix, got %v", got)
+	}
+}
+
+func TestCheckColumns(t *testing.T) {
+	if err := sqlc.CheckColumns[Member](); err != nil {
+		t.Errorf("expected Member to match its schema, got %v", err)
+	}
+
+	var drift *sqlc.ColumnDrift
+	if err := sqlc.CheckColumns[DriftUser](); !errors.As(err, &drift) {
+		t.Fatalf("expected *ColumnDrift, got %v", err)
+	}
+	if !reflect.DeepEqual(drift.Missing, []string{"nickname"}) || !reflect.DeepEqual(drift.Unknown, []string{"legacy"}) {
+		t.Errorf("unexpected drift %+v", drift)
+	}
+	want := "sqlc: schema of sqlc_test.DriftUser (drift_users) is out of date; not selected: nickname; no matching field: legacy"
+	if drift.Error() != want {
+		t.Errorf("expected %q, got %q", want, drift.Error())
+	}
+}
+
+func TestWithColumnCheck(t *testing.T) {
+	ctx := context.Background()
+	reject := func(ctx context.Context, query string) error { return sqlc.ErrStatementRejected }
+
+	var reported []*sqlc.ColumnDrift
+	strict := func(ctx context.Context, drift *sqlc.ColumnDrift) error {
+		reported = append(reported, drift)
+		return drift
+	}
+	session := sqlc.NewSession(nil, sqlc.SQLite, sqlc.WithColumnCheck(strict), sqlc.WithSQLGuard(reject))
+
+	var drift *sqlc.ColumnDrift
+	if _, err := sqlc.Query[DriftUser](session).Find(ctx); !errors.As(err, &drift) {
+		t.Errorf("expected drift error, got %v", err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("expected 1 report, got %d", len(reported))
+	}
+
+	// Explicit column lists are not checked and in-sync models pass
+	name := clause.Column{Name: "name"}
+	if _, err := sqlc.Query[DriftUser](session).Select(name).Find(ctx); !errors.Is(err, sqlc.ErrStatementRejected) {
+		t.Errorf("expected query to run with explicit columns, got %v", err)
+	}
+	if _, err := sqlc.Query[Member](session).Find(ctx); !errors.Is(err, sqlc.ErrStatementRejected) {
+		t.Errorf("expected in-sync model query to run, got %v", err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("expected no further reports, got %d", len(reported))
+	}
+}
@@ -0,0 +1,169 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements dry-run sessions that record statements instead of executing them.
+//
+// A dry-run session runs the full query construction path (repositories, query
+// builders, hooks, guards, observability) but hands the final SQL and arguments to a
+// Recorder instead of the database. Services can unit-test the queries they build
+// without a live database.
+//
+// Usage example:
+//
+//	dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+//	_ = sqlc.NewRepository[models.User](dry).Create(ctx, &models.User{Name: "alice"})
+//
+//	stmts := dry.Recorder().Statements()
+//	// stmts[0].SQL == "INSERT INTO users (name) VALUES ($1)", stmts[0].Args == []any{"alice"}
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrDryRun is returned by operations that need rows from the database
+// (Query, QueryRow, Rows, ChunkStream) on a dry-run session.
+var ErrDryRun = errors.New("sqlc: rows are not available in dry run")
+
+// Statement is a statement captured by a dry-run session.
+type Statement struct {
+	Operation string // "exec", "select", "get", "query", "query_row", "begin", "commit" or "rollback"
+	SQL       string // SQL text with dialect placeholders
+	Args      []any  // Bound arguments
+}
+
+// Recorder collects the statements of a dry-run session.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	stmts []Statement
+}
+
+// Statements returns the recorded statements in execution order.
+func (r *Recorder) Statements() []Statement {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.stmts)
+}
+
+// Reset discards the recorded statements.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stmts = nil
+}
+
+func (r *Recorder) record(operation, query string, args []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stmts = append(r.stmts, Statement{Operation: operation, SQL: query, Args: slices.Clone(args)})
+}
+
+// DryRun returns a session that records statements instead of executing them.
+// The dry-run session keeps the dialect, guards and observability of s.
+//
+// Behavior of the dry-run session:
+//   - Exec succeeds with 0 rows affected; LastInsertId fails, so IDs are not backfilled
+//   - Select and Get succeed without scanning anything (empty slices, zero values)
+//   - Query and QueryRow fail with ErrDryRun
+//   - Begin/Commit/Rollback record "BEGIN"/"COMMIT"/"ROLLBACK"; BeforeCommit hooks run
+//
+// Example:
+//
+//	dry := session.DryRun()
+//	svc := NewCheckoutService(dry)
+//	_ = svc.PlaceOrder(ctx, order)
+//	for _, stmt := range dry.Recorder().Statements() {
+//	    fmt.Println(stmt.SQL, stmt.Args)
+//	}
+//
+// Note:
+//   - Guard rules still run; rejected statements are not recorded
+//   - Read results are empty, so code branching on query results takes the "not found" path
+func (s *Session) DryRun() *Session {
+	rec := &Recorder{}
+	return &Session{
+		db:       s.db,
+		executor: dryRunExecutor{rec: rec},
+		dialect:  s.dialect,
+		obs:      s.obs,
+		guards:   s.guards,
+
+		recoverPanics: s.recoverPanics,
+		strict:        s.strict,
+		columnCheck:   s.columnCheck,
+		recorder:      rec,
+		maxPerPage:    s.maxPerPage,
+		policies:      s.policies,
+	}
+}
+
+// Recorder returns the statement recorder of a dry-run session, or nil for regular sessions.
+func (s *Session) Recorder() *Recorder {
+	return s.recorder
+}
+
+// beginDryRun starts a recorded transaction on a dry-run session.
+func (s *Session) beginDryRun() *Session {
+	s.recorder.record("begin", "BEGIN", nil)
+	tx := *s
+	tx.tx = &txState{}
+	return &tx
+}
+
+// endDryRun records the end of a dry-run transaction.
+func (s *Session) endDryRun(ctx context.Context, commit bool) error {
+	if s.tx == nil {
+		return sql.ErrTxDone
+	}
+	if commit {
+		if err := s.runBeforeCommit(ctx); err != nil {
+			s.recorder.record("rollback", "ROLLBACK", nil)
+			return err
+		}
+		s.recorder.record("commit", "COMMIT", nil)
+		return nil
+	}
+	s.recorder.record("rollback", "ROLLBACK", nil)
+	return nil
+}
+
+// dryRunExecutor is the Executor of dry-run sessions.
+type dryRunExecutor struct {
+	rec *Recorder
+}
+
+func (e dryRunExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	e.rec.record("query", query, args)
+	return nil, ErrDryRun
+}
+
+func (e dryRunExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	e.rec.record("exec", query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (e dryRunExecutor) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	e.rec.record("query_row", query, args)
+	// *sql.Row cannot be built directly; a database that never connects yields one carrying ErrDryRun
+	return dryRunDB().QueryRowContext(ctx, query, args...)
+}
+
+func (e dryRunExecutor) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	e.rec.record("select", query, args)
+	return nil
+}
+
+func (e dryRunExecutor) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	e.rec.record("get", query, args)
+	return nil
+}
+
+// dryRunDB returns a database whose connections always fail with ErrDryRun,
+// opened on first use.
+var dryRunDB = sync.OnceValue(func() *sql.DB {
+	return sql.OpenDB(errConnector{err: ErrDryRun})
+})
@@ -0,0 +1,112 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+	rec := dry.Recorder()
+	memberRepo := sqlc.NewRepository[Member](dry)
+	name := field.String{}.WithColumn("name")
+
+	t.Run("RecordsStatements", func(t *testing.T) {
+		rec.Reset()
+		if err := memberRepo.Create(ctx, &Member{Name: "alice", Email: "a@test.com", Level: 2}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		members, err := memberRepo.Query().Where(name.Eq("alice")).Find(ctx)
+		if err != nil || len(members) != 0 {
+			t.Fatalf("expected empty Find result, got %v (err %v)", members, err)
+		}
+		if n, err := memberRepo.Query().Count(ctx); err != nil || n != 0 {
+			t.Fatalf("expected zero count, got %d (err %v)", n, err)
+		}
+
+		stmts := rec.Statements()
+		want := []struct{ op, sql string }{
+			{"exec", "INSERT INTO members (name,email,level,department_id,created_at) VALUES ($1,$2,$3,$4,$5)"},
+			{"select", "SELECT id, name, email, level, department_id, created_at FROM members WHERE name = $1"},
+			{"get", "SELECT COUNT(*) FROM members"},
+		}
+		if len(stmts) != len(want) {
+			t.Fatalf("expected %d statements, got %+v", len(want), stmts)
+		}
+		for i, w := range want {
+			if stmts[i].Operation != w.op || stmts[i].SQL != w.sql {
+				t.Errorf("statement %d: expected %s %q, got %s %q", i, w.op, w.sql, stmts[i].Operation, stmts[i].SQL)
+			}
+		}
+		if !reflect.DeepEqual(stmts[1].Args, []any{"alice"}) {
+			t.Errorf("unexpected args %v", stmts[1].Args)
+		}
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		rec.Reset()
+		var hookRan bool
+		err := dry.Transaction(ctx, func(tx *sqlc.Session) error {
+			if err := tx.BeforeCommit(func(ctx context.Context) error { hookRan = true; return nil }); err != nil {
+				return err
+			}
+			// Nested transactions join the outer one
+			return tx.Transaction(ctx, func(tx *sqlc.Session) error {
+				_, err := tx.Exec(ctx, "DELETE FROM users WHERE id = $1", 1)
+				return err
+			})
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		if !hookRan {
+			t.Error("expected BeforeCommit hook to run")
+		}
+		var ops []string
+		for _, stmt := range rec.Statements() {
+			ops = append(ops, stmt.Operation)
+		}
+		if want := []string{"begin", "exec", "commit"}; !reflect.DeepEqual(ops, want) {
+			t.Errorf("expected %v, got %v", want, ops)
+		}
+
+		rec.Reset()
+		boom := errors.New("boom")
+		if err := dry.Transaction(ctx, func(tx *sqlc.Session) error { return boom }); !errors.Is(err, boom) {
+			t.Errorf("expected callback error, got %v", err)
+		}
+		if stmts := rec.Statements(); len(stmts) != 2 || stmts[1].SQL != "ROLLBACK" {
+			t.Errorf("expected BEGIN, ROLLBACK, got %+v", stmts)
+		}
+	})
+
+	t.Run("RowsUnavailable", func(t *testing.T) {
+		var name string
+		if err := dry.QueryRow(ctx, "SELECT username FROM users").Scan(&name); !errors.Is(err, sqlc.ErrDryRun) {
+			t.Errorf("expected ErrDryRun from QueryRow, got %v", err)
+		}
+		if _, err := dry.Query(ctx, "SELECT username FROM users"); !errors.Is(err, sqlc.ErrDryRun) {
+			t.Errorf("expected ErrDryRun from Query, got %v", err)
+		}
+	})
+
+	t.Run("GuardsApply", func(t *testing.T) {
+		guarded := sqlc.NewSession(nil, sqlc.PostgreSQL, sqlc.WithSQLGuard(sqlc.DenyDeleteWithoutWhere())).DryRun()
+		if _, err := guarded.Exec(ctx, "DELETE FROM users"); !errors.Is(err, sqlc.ErrStatementRejected) {
+			t.Errorf("expected guard rejection, got %v", err)
+		}
+		if n := len(guarded.Recorder().Statements()); n != 0 {
+			t.Errorf("expected rejected statement not to be recorded, got %d", n)
+		}
+	})
+
+	if sqlc.NewSession(nil, sqlc.SQLite).Recorder() != nil {
+		t.Error("expected no recorder on a regular session")
+	}
+}
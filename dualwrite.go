@@ -0,0 +1,358 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements dual writes for zero-downtime migrations between databases.
+//
+// Migrating a table to a new database (or a new table layout) without downtime usually
+// goes through these phases:
+//  1. Dual write: every write goes to the old (primary) database and is mirrored to the
+//     new (secondary) one; existing rows are backfilled separately
+//  2. Read compare: reads are served by the primary and compared with the secondary,
+//     until no divergences are observed
+//  3. Switch: the secondary becomes the primary
+//
+// DualWrite wraps a primary and a secondary repository for phases 1 and 2. Secondary
+// failures never fail the caller's write; they are counted as divergences (DualWriteStats,
+// the sqlc.dualwrite.divergence metric) and reported to the divergence handler.
+//
+// Usage example:
+//
+//	dw := sqlc.NewDualWrite(
+//	    sqlc.NewRepository[models.User](oldSession),
+//	    sqlc.NewRepository[models.User](newSession),
+//	    sqlc.WithDualWriteAsync(1024),
+//	).WithReadCompare(nil)
+//	defer dw.Close()
+//
+//	err := dw.Create(ctx, user)       // written to old, mirrored to new in the background
+//	u, err := dw.FindOne(ctx, user.ID) // read from old, compared with new
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DivergenceKind is the kind of a divergence between the primary and secondary database.
+type DivergenceKind string
+
+const (
+	DivergenceWriteFailed  DivergenceKind = "write_failed"  // Mirrored write failed on the secondary
+	DivergenceQueueFull    DivergenceKind = "queue_full"    // Async mirror dropped: queue full
+	DivergenceReadMismatch DivergenceKind = "read_mismatch" // Secondary row differs, is missing or failed to load
+)
+
+// Divergence describes one divergence observed by a DualWrite.
+type Divergence struct {
+	Kind  DivergenceKind
+	Op    string // create, update, delete or find
+	Table string // Table of the primary repository
+	Key   any    // Primary key of the affected row
+	Err   error  // Secondary error, if any
+}
+
+// DualWriteStats are the counters of a DualWrite.
+type DualWriteStats struct {
+	Mirrored   int64 // Writes applied to the secondary
+	Failed     int64 // Writes failed on the secondary
+	Dropped    int64 // Async writes dropped because the queue was full
+	Compared   int64 // Reads compared with the secondary
+	Mismatched int64 // Compared reads that diverged
+}
+
+// DualWrite configuration
+type dualWriteConfig struct {
+	queueSize    int                                     // Async queue capacity (0: mirror synchronously)
+	onDivergence func(ctx context.Context, d Divergence) // Divergence callback
+}
+
+// DualWriteOption configures a DualWrite.
+type DualWriteOption func(*dualWriteConfig)
+
+// WithDualWriteAsync mirrors writes from a queue of the given capacity, drained in write
+// order by a background goroutine, so the secondary adds no latency to writes. When the
+// queue is full, the mirror is dropped and reported as a DivergenceQueueFull.
+func WithDualWriteAsync(queueSize int) DualWriteOption {
+	return func(c *dualWriteConfig) {
+		c.queueSize = max(1, queueSize)
+	}
+}
+
+// WithDivergenceHandler sets the callback for divergences, e.g. to log the keys to
+// re-sync. The default logs to slog.Default().
+func WithDivergenceHandler(fn func(ctx context.Context, d Divergence)) DualWriteOption {
+	return func(c *dualWriteConfig) {
+		c.onDivergence = fn
+	}
+}
+
+// DualWrite writes model T to a primary repository and mirrors the writes to a secondary
+// repository of model U. It is safe for concurrent use.
+type DualWrite[T, U any] struct {
+	primary   *Repository[T]
+	secondary *Repository[U]
+	convert   func(*T) *U
+	equal     func(want, got *U) bool // Row comparison of read-compare mode (nil: disabled)
+	cfg       dualWriteConfig
+
+	mu     sync.RWMutex // Guards queue against sends after Close
+	queue  chan dualWriteOp
+	closed bool
+	done   chan struct{}
+
+	mirrored, failed, dropped, compared, mismatched atomic.Int64
+}
+
+// dualWriteOp is a write to mirror to the secondary.
+type dualWriteOp struct {
+	ctx context.Context
+	op  string
+	key any
+	fn  func(ctx context.Context) error
+}
+
+// NewDualWrite creates a dual write between two repositories of the same model, e.g. on
+// sessions of the old and new database. Mirrored writes use a copy of the model, so the
+// secondary never modifies the caller's model.
+//
+// Parameters:
+//   - primary: Repository serving reads and writes (source of truth)
+//   - secondary: Repository receiving mirrored writes
+//   - opts: Options (WithDualWriteAsync, WithDivergenceHandler)
+//
+// Example:
+//
+//	dw := sqlc.NewDualWrite(
+//	    sqlc.NewRepository[models.Order](mysqlSession),
+//	    sqlc.NewRepository[models.Order](postgresSession),
+//	)
+func NewDualWrite[T any](primary, secondary *Repository[T], opts ...DualWriteOption) *DualWrite[T, T] {
+	return NewDualWriteTo(primary, secondary, func(m *T) *T {
+		c := *m
+		return &c
+	}, opts...)
+}
+
+// NewDualWriteTo creates a dual write to a secondary repository of another model, for
+// migrations to a new table layout. convert maps a primary model to a new secondary
+// model; it is called at write time, so later changes of the caller's model are not
+// mirrored.
+//
+// Example:
+//
+//	dw := sqlc.NewDualWriteTo(userRepo, accountRepo, func(u *models.User) *models.Account {
+//	    return &models.Account{ID: u.ID, Email: u.Email, DisplayName: u.FirstName + " " + u.LastName}
+//	})
+//
+// Note:
+//   - Delete by id uses the same key on both sides
+func NewDualWriteTo[T, U any](primary *Repository[T], secondary *Repository[U], convert func(*T) *U, opts ...DualWriteOption) *DualWrite[T, U] {
+	cfg := dualWriteConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.onDivergence == nil {
+		cfg.onDivergence = func(ctx context.Context, d Divergence) {
+			attrs := []slog.Attr{
+				slog.String("kind", string(d.Kind)), slog.String("op", d.Op),
+				slog.String("table", d.Table), slog.Any("key", d.Key),
+			}
+			if d.Err != nil {
+				attrs = append(attrs, slog.String("error", d.Err.Error()))
+			}
+			slog.Default().LogAttrs(ctx, slog.LevelWarn, "dual write divergence", attrs...)
+		}
+	}
+
+	dw := &DualWrite[T, U]{primary: primary, secondary: secondary, convert: convert, cfg: cfg}
+	if cfg.queueSize > 0 {
+		dw.queue = make(chan dualWriteOp, cfg.queueSize)
+		dw.done = make(chan struct{})
+		go dw.drain()
+	}
+	return dw
+}
+
+// WithReadCompare enables read-compare mode: FindOne also reads the row from the
+// secondary and compares it with the primary row. equal compares the secondary-typed
+// rows (the converted primary row first); nil uses reflect.DeepEqual.
+//
+// Example:
+//
+//	dw := sqlc.NewDualWriteTo(userRepo, accountRepo, toAccount).
+//	    WithReadCompare(func(want, got *models.Account) bool { return want.Email == got.Email })
+//
+// Note:
+//   - Call it right after construction, before the DualWrite is used concurrently
+func (dw *DualWrite[T, U]) WithReadCompare(equal func(want, got *U) bool) *DualWrite[T, U] {
+	if equal == nil {
+		equal = func(want, got *U) bool { return reflect.DeepEqual(want, got) }
+	}
+	dw.equal = equal
+	return dw
+}
+
+// Create creates model in the primary and mirrors it to the secondary.
+// Only the primary's error is returned.
+func (dw *DualWrite[T, U]) Create(ctx context.Context, model *T) error {
+	if err := dw.primary.Create(ctx, model); err != nil {
+		return err
+	}
+	mirror := dw.convert(model)
+	dw.mirror(ctx, "create", dw.primary.schema.PK(model).Value, func(ctx context.Context) error {
+		return dw.secondary.Create(ctx, mirror)
+	})
+	return nil
+}
+
+// Update updates model in the primary and mirrors it to the secondary.
+// Only the primary's error is returned.
+func (dw *DualWrite[T, U]) Update(ctx context.Context, model *T) error {
+	if err := dw.primary.Update(ctx, model); err != nil {
+		return err
+	}
+	mirror := dw.convert(model)
+	dw.mirror(ctx, "update", dw.primary.schema.PK(model).Value, func(ctx context.Context) error {
+		return dw.secondary.Update(ctx, mirror)
+	})
+	return nil
+}
+
+// DeleteModel deletes model from the primary and mirrors the deletion to the secondary.
+// Only the primary's error is returned.
+func (dw *DualWrite[T, U]) DeleteModel(ctx context.Context, model *T) error {
+	if err := dw.primary.DeleteModel(ctx, model); err != nil {
+		return err
+	}
+	mirror := dw.convert(model)
+	dw.mirror(ctx, "delete", dw.primary.schema.PK(model).Value, func(ctx context.Context) error {
+		return dw.secondary.DeleteModel(ctx, mirror)
+	})
+	return nil
+}
+
+// Delete deletes the record with primary key id from the primary and mirrors the
+// deletion to the secondary. Only the primary's error is returned.
+func (dw *DualWrite[T, U]) Delete(ctx context.Context, id any) error {
+	if err := dw.primary.Delete(ctx, id); err != nil {
+		return err
+	}
+	dw.mirror(ctx, "delete", id, func(ctx context.Context) error {
+		return dw.secondary.Delete(ctx, id)
+	})
+	return nil
+}
+
+// FindOne finds a record by primary key in the primary. In read-compare mode, the
+// secondary row is loaded and compared as well; divergences are reported, and the
+// primary result is returned either way.
+func (dw *DualWrite[T, U]) FindOne(ctx context.Context, id any) (*T, error) {
+	model, err := dw.primary.FindOne(ctx, id)
+	if dw.equal == nil || (err != nil && !errors.Is(err, ErrNotFound)) {
+		return model, err
+	}
+
+	dw.compared.Add(1)
+	got, secErr := dw.secondary.FindOne(ctx, id)
+	var diff error
+	switch {
+	case err != nil: // Not found in the primary
+		if secErr == nil {
+			diff = errors.New("sqlc: row exists only in the secondary")
+		} else if !errors.Is(secErr, ErrNotFound) {
+			diff = secErr
+		}
+	case secErr != nil:
+		diff = secErr
+	case !dw.equal(dw.convert(model), got):
+		diff = errors.New("sqlc: secondary row differs from the primary row")
+	}
+	if diff != nil {
+		dw.mismatched.Add(1)
+		dw.diverge(ctx, Divergence{Kind: DivergenceReadMismatch, Op: "find", Key: id, Err: diff})
+	}
+	return model, err
+}
+
+// Stats returns the current counters.
+func (dw *DualWrite[T, U]) Stats() DualWriteStats {
+	return DualWriteStats{
+		Mirrored:   dw.mirrored.Load(),
+		Failed:     dw.failed.Load(),
+		Dropped:    dw.dropped.Load(),
+		Compared:   dw.compared.Load(),
+		Mismatched: dw.mismatched.Load(),
+	}
+}
+
+// Close waits until queued mirrors are applied and stops the background goroutine
+// of async mode. Writes after Close are mirrored synchronously.
+func (dw *DualWrite[T, U]) Close() {
+	dw.mu.Lock()
+	if dw.queue == nil || dw.closed {
+		dw.mu.Unlock()
+		return
+	}
+	dw.closed = true
+	close(dw.queue)
+	dw.mu.Unlock()
+	<-dw.done
+}
+
+// mirror applies fn to the secondary, synchronously or through the async queue.
+func (dw *DualWrite[T, U]) mirror(ctx context.Context, op string, key any, fn func(ctx context.Context) error) {
+	// Mirrors outlive the caller's request in async mode; keep its values, not its cancellation
+	w := dualWriteOp{ctx: context.WithoutCancel(ctx), op: op, key: key, fn: fn}
+
+	dw.mu.RLock()
+	if dw.queue != nil && !dw.closed {
+		select {
+		case dw.queue <- w:
+			dw.mu.RUnlock()
+		default:
+			dw.mu.RUnlock()
+			dw.dropped.Add(1)
+			dw.diverge(w.ctx, Divergence{Kind: DivergenceQueueFull, Op: op, Key: key})
+		}
+		return
+	}
+	dw.mu.RUnlock()
+	dw.apply(w)
+}
+
+// drain applies queued mirrors in order until the queue is closed.
+func (dw *DualWrite[T, U]) drain() {
+	defer close(dw.done)
+	for w := range dw.queue {
+		dw.apply(w)
+	}
+}
+
+// apply runs a mirrored write and records its outcome.
+func (dw *DualWrite[T, U]) apply(w dualWriteOp) {
+	if err := w.fn(w.ctx); err != nil {
+		dw.failed.Add(1)
+		dw.diverge(w.ctx, Divergence{Kind: DivergenceWriteFailed, Op: w.op, Key: w.key,
+			Err: fmt.Errorf("sqlc: mirrored %s failed: %w", w.op, err)})
+		return
+	}
+	dw.mirrored.Add(1)
+}
+
+// diverge records a divergence in the metrics and reports it to the handler.
+func (dw *DualWrite[T, U]) diverge(ctx context.Context, d Divergence) {
+	d.Table = dw.primary.schema.TableName()
+	if m := dw.primary.session.observability().Metrics; m != nil {
+		m.DualWriteDivergence.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("db.sql.table", d.Table),
+			attribute.String("sqlc.divergence", string(d.Kind)),
+		))
+	}
+	dw.cfg.onDivergence(ctx, d)
+}
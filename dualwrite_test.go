@@ -0,0 +1,177 @@
+package sqlc_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestDualWrite(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (primary, secondary *sqlc.Repository[Member]) {
+		open := func() *sqlc.Repository[Member] {
+			db, session := setupIntegrationDB(t)
+			db.SetMaxOpenConns(1) // One in-memory database per connection
+			t.Cleanup(func() { db.Close() })
+			return sqlc.NewRepository[Member](session)
+		}
+		return open(), open()
+	}
+	newMember := func(name string) *Member {
+		return &Member{Name: name, Email: name + "@test.com", DepartmentID: 1, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	}
+
+	t.Run("Mirror", func(t *testing.T) {
+		primary, secondary := setup(t)
+		dw := sqlc.NewDualWrite(primary, secondary)
+
+		m := newMember("alice")
+		if err := dw.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		m.Level = 3
+		if err := dw.Update(ctx, m); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		got, err := secondary.FindOne(ctx, m.ID)
+		if err != nil {
+			t.Fatalf("secondary FindOne failed: %v", err)
+		}
+		if got.Name != "alice" || got.Level != 3 {
+			t.Errorf("secondary row = %+v, want alice level 3", got)
+		}
+
+		if err := dw.Delete(ctx, m.ID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if n, _ := secondary.Query().Count(ctx); n != 0 {
+			t.Errorf("secondary count = %d, want 0", n)
+		}
+		if s := dw.Stats(); s.Mirrored != 3 || s.Failed != 0 {
+			t.Errorf("stats = %+v, want 3 mirrored", s)
+		}
+	})
+
+	t.Run("SecondaryFailure", func(t *testing.T) {
+		primary, secondary := setup(t)
+		var divergences []sqlc.Divergence
+		dw := sqlc.NewDualWrite(primary, secondary, sqlc.WithDivergenceHandler(func(ctx context.Context, d sqlc.Divergence) {
+			divergences = append(divergences, d)
+		}))
+
+		// The secondary already has the email: its unique constraint fails the mirror
+		if err := secondary.Create(ctx, newMember("bob")); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if err := dw.Create(ctx, newMember("bob")); err != nil {
+			t.Fatalf("Create failed despite successful primary write: %v", err)
+		}
+
+		if s := dw.Stats(); s.Failed != 1 || s.Mirrored != 0 {
+			t.Errorf("stats = %+v, want 1 failed", s)
+		}
+		if len(divergences) != 1 {
+			t.Fatalf("got %d divergences, want 1", len(divergences))
+		}
+		d := divergences[0]
+		if d.Kind != sqlc.DivergenceWriteFailed || d.Op != "create" || d.Table != "members" || d.Err == nil {
+			t.Errorf("divergence = %+v", d)
+		}
+	})
+
+	t.Run("Async", func(t *testing.T) {
+		primary, secondary := setup(t)
+		dw := sqlc.NewDualWrite(primary, secondary, sqlc.WithDualWriteAsync(16))
+
+		for _, name := range []string{"a", "b", "c"} {
+			if err := dw.Create(ctx, newMember(name)); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+		}
+		dw.Close()
+
+		if n, _ := secondary.Query().Count(ctx); n != 3 {
+			t.Errorf("secondary count = %d, want 3", n)
+		}
+		if s := dw.Stats(); s.Mirrored != 3 {
+			t.Errorf("stats = %+v, want 3 mirrored", s)
+		}
+
+		// Writes after Close are mirrored synchronously
+		if err := dw.Create(ctx, newMember("d")); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if n, _ := secondary.Query().Count(ctx); n != 4 {
+			t.Errorf("secondary count = %d, want 4", n)
+		}
+	})
+
+	t.Run("ReadCompare", func(t *testing.T) {
+		primary, secondary := setup(t)
+		var mu sync.Mutex
+		var kinds []sqlc.DivergenceKind
+		dw := sqlc.NewDualWrite(primary, secondary,
+			sqlc.WithDivergenceHandler(func(ctx context.Context, d sqlc.Divergence) {
+				mu.Lock()
+				defer mu.Unlock()
+				kinds = append(kinds, d.Kind)
+			}),
+		).WithReadCompare(nil)
+
+		m := newMember("carol")
+		if err := dw.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if got, err := dw.FindOne(ctx, m.ID); err != nil || got.Name != "carol" {
+			t.Fatalf("FindOne = %+v, %v", got, err)
+		}
+		if s := dw.Stats(); s.Compared != 1 || s.Mismatched != 0 {
+			t.Errorf("stats = %+v, want 1 compared, 0 mismatched", s)
+		}
+
+		// Diverge the secondary behind the dual write's back
+		m.Level = 9
+		if err := secondary.Update(ctx, m); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		got, err := dw.FindOne(ctx, m.ID)
+		if err != nil {
+			t.Fatalf("FindOne failed: %v", err)
+		}
+		if got.Level != 0 {
+			t.Errorf("FindOne returned level %d, want the primary's 0", got.Level)
+		}
+		if s := dw.Stats(); s.Compared != 2 || s.Mismatched != 1 {
+			t.Errorf("stats = %+v, want 2 compared, 1 mismatched", s)
+		}
+		if len(kinds) != 1 || kinds[0] != sqlc.DivergenceReadMismatch {
+			t.Errorf("divergences = %v, want [read_mismatch]", kinds)
+		}
+	})
+
+	t.Run("ReadCompareCustom", func(t *testing.T) {
+		primary, secondary := setup(t)
+		// Levels are recomputed on the secondary: compare names only
+		dw := sqlc.NewDualWrite(primary, secondary).
+			WithReadCompare(func(want, got *Member) bool { return want.Name == got.Name })
+
+		m := newMember("dave")
+		if err := dw.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		m.Level = 9
+		if err := secondary.Update(ctx, m); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if _, err := dw.FindOne(ctx, m.ID); err != nil {
+			t.Fatalf("FindOne failed: %v", err)
+		}
+		if s := dw.Stats(); s.Compared != 1 || s.Mismatched != 0 {
+			t.Errorf("stats = %+v, want 1 compared, 0 mismatched", s)
+		}
+	})
+}
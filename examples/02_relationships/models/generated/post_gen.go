@@ -13,6 +13,7 @@ import (
 
 func init() {
 	sqlc.RegisterSchema(&Post)
+	sqlc.RegisterRelation("Author", Post_Author)
 }
 
 // Post Schema:
@@ -94,10 +95,10 @@ func (s *postSchema) SoftDeleteValue() any {
 func (s *postSchema) SetDeletedAt(m *models.Post) {
 }
 
-// Post_Author defines belongsTo relation: Post has one User
-var Post_Author = sqlc.HasOne(
-	clause.Column{Name: "id"},
+// Post_Author defines belongsTo relation: Post belongs to User
+var Post_Author = sqlc.BelongsTo(
 	clause.Column{Name: "user_id"},
+	clause.Column{Name: "id"},
 	func(p *models.Post, child *models.User) { p.Author = child },
 	func(p *models.Post) int64 { return p.UserID },
 	func(c *models.User) int64 { return c.ID },
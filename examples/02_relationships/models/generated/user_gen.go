@@ -13,6 +13,7 @@ import (
 
 func init() {
 	sqlc.RegisterSchema(&User)
+	sqlc.RegisterRelation("Posts", User_Posts)
 }
 
 // User Schema:
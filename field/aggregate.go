@@ -0,0 +1,82 @@
+package field
+
+import (
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
+	"golang.org/x/exp/constraints"
+)
+
+// Aggregate represents a typed aggregate function over a column, e.g. COUNT(*) or SUM(price).
+// Comparisons produce HAVING-safe expressions; As() names it for a SELECT list.
+type Aggregate[T constraints.Integer | constraints.Float] struct {
+	fn clause.Func
+}
+
+// Count counts non-NULL values of a column: COUNT(col)
+func Count(col clause.Columnar) Aggregate[int64] {
+	return Aggregate[int64]{fn: clause.Count(col)}
+}
+
+// CountAll counts rows: COUNT(*)
+func CountAll() Aggregate[int64] {
+	return Aggregate[int64]{fn: clause.CountAll()}
+}
+
+// Sum adds up a numeric field: SUM(col)
+func Sum[T constraints.Integer | constraints.Float](n Number[T]) Aggregate[T] {
+	return Aggregate[T]{fn: clause.Sum(n.column)}
+}
+
+// Avg averages a numeric field: AVG(col)
+func Avg[T constraints.Integer | constraints.Float](n Number[T]) Aggregate[float64] {
+	return Aggregate[float64]{fn: clause.Avg(n.column)}
+}
+
+// Min returns the smallest value of a numeric field: MIN(col)
+func Min[T constraints.Integer | constraints.Float](n Number[T]) Aggregate[T] {
+	return Aggregate[T]{fn: clause.Min(n.column)}
+}
+
+// Max returns the largest value of a numeric field: MAX(col)
+func Max[T constraints.Integer | constraints.Float](n Number[T]) Aggregate[T] {
+	return Aggregate[T]{fn: clause.Max(n.column)}
+}
+
+// Build implements the clause.Expression interface
+func (a Aggregate[T]) Build() (string, []any, error) {
+	return a.fn.Build()
+}
+
+// SelectItem implements the clause.SelectItem interface
+func (Aggregate[T]) SelectItem(seal.Token) {}
+
+var _ clause.Expression = Aggregate[int64]{}
+
+// Query functions
+
+// Eq creates an equality comparison expression (aggregate = value).
+func (a Aggregate[T]) Eq(value T) clause.Expression { return a.fn.Eq(value) }
+
+// Neq creates a not equal comparison expression (aggregate <> value).
+func (a Aggregate[T]) Neq(value T) clause.Expression { return a.fn.Neq(value) }
+
+// Gt creates a greater than comparison expression (aggregate > value).
+func (a Aggregate[T]) Gt(value T) clause.Expression { return a.fn.Gt(value) }
+
+// Gte creates a greater than or equal comparison expression (aggregate >= value).
+func (a Aggregate[T]) Gte(value T) clause.Expression { return a.fn.Gte(value) }
+
+// Lt creates a less than comparison expression (aggregate < value).
+func (a Aggregate[T]) Lt(value T) clause.Expression { return a.fn.Lt(value) }
+
+// Lte creates a less than or equal comparison expression (aggregate <= value).
+func (a Aggregate[T]) Lte(value T) clause.Expression { return a.fn.Lte(value) }
+
+// Asc creates an ascending order expression (ORDER BY aggregate ASC).
+func (a Aggregate[T]) Asc() clause.OrderByExpression { return a.fn.Asc() }
+
+// Desc creates a descending order expression (ORDER BY aggregate DESC).
+func (a Aggregate[T]) Desc() clause.OrderByExpression { return a.fn.Desc() }
+
+// As aliases the aggregate for a SELECT list (aggregate AS alias).
+func (a Aggregate[T]) As(alias string) clause.Alias { return a.fn.As(alias) }
@@ -1,6 +1,9 @@
 package field
 
-import "github.com/arllen133/sqlc/clause"
+import (
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
+)
 
 // Bool represents a boolean field for building SQL queries.
 type Bool struct {
@@ -15,6 +18,9 @@ func (b Bool) ColumnName() string {
 	return b.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (Bool) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = Bool{}
 
 // WithColumn creates a new Bool field with the specified column name.
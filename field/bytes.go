@@ -2,6 +2,7 @@ package field
 
 import (
 	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
 )
 
 // Bytes represents a binary data field (BLOB/BYTEA) for building SQL queries.
@@ -17,6 +18,9 @@ func (b Bytes) ColumnName() string {
 	return b.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (Bytes) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = Bytes{}
 
 // WithColumn creates a new Bytes field with the specified column name.
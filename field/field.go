@@ -1,6 +1,9 @@
 package field
 
-import "github.com/arllen133/sqlc/clause"
+import (
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
+)
 
 // Field represents a generic field for any type.
 // Use this for types that don't have a specific field type.
@@ -16,6 +19,9 @@ func (f Field[T]) ColumnName() string {
 	return f.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (Field[T]) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = Field[any]{}
 
 // WithColumn creates a new Field with the specified column name.
@@ -1,6 +1,7 @@
 package field_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -784,6 +785,46 @@ func TestColumnMethods(t *testing.T) {
 	})
 }
 
+// ============== Aggregate Tests ==============
+
+func TestAggregateField(t *testing.T) {
+	price := field.Number[float64]{}.WithColumn("price").WithTable("orders")
+	qty := field.Number[int]{}.WithColumn("qty")
+	id := field.Number[int64]{}.WithColumn("id")
+
+	tests := []struct {
+		name     string
+		expr     clause.Expression
+		wantSQL  string
+		wantArgs []any
+	}{
+		{"CountAllGte", field.CountAll().Gte(2), "COUNT(*) >= ?", []any{int64(2)}},
+		{"CountGt", field.Count(id).Gt(5), "COUNT(id) > ?", []any{int64(5)}},
+		{"SumLt", field.Sum(price).Lt(99.5), "SUM(orders.price) < ?", []any{99.5}},
+		{"AvgNeq", field.Avg(qty).Neq(1.5), "AVG(qty) <> ?", []any{1.5}},
+		{"MinEq", field.Min(qty).Eq(1), "MIN(qty) = ?", []any{1}},
+		{"MaxLte", field.Max(qty).Lte(10), "MAX(qty) <= ?", []any{10}},
+		{"Alias", field.Sum(qty).As("total"), "SUM(qty) AS total", nil},
+		{"OrderDesc", field.CountAll().Desc(), "COUNT(*) DESC", nil},
+		{"Bare", field.Max(price), "MAX(orders.price)", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.expr.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("Expected SQL %q, got %q", tt.wantSQL, sql)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Expected args %v, got %v", tt.wantArgs, args)
+			}
+		})
+	}
+}
+
 // ============== Complex Expression Tests ==============
 
 func TestComplexExpression(t *testing.T) {
@@ -826,6 +867,41 @@ func TestComplexExpression(t *testing.T) {
 	})
 }
 
+// ============== Tuple Tests ==============
+
+func TestTuple(t *testing.T) {
+	userID := field.Number[int64]{}.WithColumn("user_id").WithTable("cart_items")
+	productID := field.Number[int64]{}.WithColumn("product_id").WithTable("cart_items")
+	tuple := field.Columns(userID, productID)
+
+	t.Run("In", func(t *testing.T) {
+		sql, args, _ := tuple.In([]any{1, 2}, []any{3, 4}).Build()
+		expected := "(cart_items.user_id, cart_items.product_id) IN ((?, ?), (?, ?))"
+		if sql != expected {
+			t.Errorf("Expected '%s', got '%s'", expected, sql)
+		}
+		if len(args) != 4 || args[0] != 1 || args[3] != 4 {
+			t.Errorf("Args mismatch, got %v", args)
+		}
+	})
+
+	t.Run("NotIn", func(t *testing.T) {
+		sql, _, _ := tuple.NotIn([]any{1, 2}).Build()
+		expected := "NOT ((cart_items.user_id, cart_items.product_id) IN ((?, ?)))"
+		if sql != expected {
+			t.Errorf("Expected '%s', got '%s'", expected, sql)
+		}
+	})
+
+	t.Run("PlainColumnar", func(t *testing.T) {
+		sql, _, _ := field.Columns(clause.Column{Name: "a"}, productID).In([]any{1, 2}).Build()
+		expected := "(a, cart_items.product_id) IN ((?, ?))"
+		if sql != expected {
+			t.Errorf("Expected '%s', got '%s'", expected, sql)
+		}
+	})
+}
+
 // ============== OrderBy Tests ==============
 
 func TestOrderBy(t *testing.T) {
@@ -889,6 +965,28 @@ func TestAssignment(t *testing.T) {
 		}
 	})
 
+	t.Run("NumberIncr", func(t *testing.T) {
+		views := field.Number[int]{}.WithColumn("view_count")
+		sql, args, _ := views.Incr(1).Value.(clause.Expression).Build()
+		if sql != "view_count + ?" {
+			t.Errorf("Expected 'view_count + ?', got '%s'", sql)
+		}
+		if args[0] != 1 {
+			t.Errorf("Expected 1, got %v", args[0])
+		}
+	})
+
+	t.Run("NumberDecr", func(t *testing.T) {
+		stock := field.Number[int64]{}.WithColumn("stock")
+		sql, args, _ := stock.Decr(2).Value.(clause.Expression).Build()
+		if sql != "stock - ?" {
+			t.Errorf("Expected 'stock - ?', got '%s'", sql)
+		}
+		if args[0] != int64(2) {
+			t.Errorf("Expected 2, got %v", args[0])
+		}
+	})
+
 	t.Run("Bool", func(t *testing.T) {
 		active := field.Bool{}.WithColumn("is_active")
 		assign := active.Set(true)
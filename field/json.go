@@ -5,6 +5,7 @@ import (
 
 	"github.com/arllen133/sqlc/clause"
 	jsonpkg "github.com/arllen133/sqlc/field/json"
+	"github.com/arllen133/sqlc/internal/seal"
 )
 
 // JSON represents a JSON field for building SQL queries.
@@ -21,6 +22,9 @@ func (j JSON[T]) ColumnName() string {
 	return j.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (JSON[T]) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = JSON[any]{}
 
 // WithColumn creates a new JSON field with the specified column name.
@@ -2,6 +2,7 @@ package field
 
 import (
 	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
 	"golang.org/x/exp/constraints"
 )
 
@@ -19,6 +20,9 @@ func (n Number[T]) ColumnName() string {
 	return n.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (Number[T]) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = Number[int]{}
 
 // WithColumn creates a new Number field with the specified column name.
@@ -107,6 +111,16 @@ func (n Number[T]) Set(val T) clause.Assignment {
 	return clause.Assignment{Column: n.column, Value: val}
 }
 
+// Incr creates an atomic increment assignment for UPDATE operations (field = field + delta).
+func (n Number[T]) Incr(delta T) clause.Assignment {
+	return clause.Incr(n.column, delta)
+}
+
+// Decr creates an atomic decrement assignment for UPDATE operations (field = field - delta).
+func (n Number[T]) Decr(delta T) clause.Assignment {
+	return clause.Decr(n.column, delta)
+}
+
 // Order expressions for sorting operations
 
 // Asc creates an ascending order expression for ORDER BY clauses.
@@ -1,6 +1,9 @@
 package field
 
-import "github.com/arllen133/sqlc/clause"
+import (
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
+)
 
 // String represents a string field for building SQL queries.
 type String struct {
@@ -15,6 +18,9 @@ func (s String) ColumnName() string {
 	return s.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (String) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = String{}
 
 // WithColumn creates a new String field with the specified column name.
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/internal/seal"
 )
 
 // Time represents a time/date field for building SQL queries.
@@ -19,6 +20,9 @@ func (t Time) ColumnName() string {
 	return t.column.ColumnName()
 }
 
+// SelectItem implements the clause.SelectItem interface
+func (Time) SelectItem(seal.Token) {}
+
 var _ clause.Columnar = Time{}
 
 // WithColumn creates a new Time field with the specified column name.
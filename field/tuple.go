@@ -0,0 +1,36 @@
+package field
+
+import "github.com/arllen133/sqlc/clause"
+
+// Tuple is a group of columns compared together as a row value, e.g. a composite key.
+type Tuple []clause.Column
+
+// Columns groups fields into a Tuple for multi-column comparisons.
+//
+// Example:
+//
+//	// WHERE (user_id, product_id) IN ((?, ?), (?, ?))
+//	field.Columns(generated.CartItem.UserID, generated.CartItem.ProductID).
+//	    In([]any{1, 2}, []any{3, 4})
+func Columns(fields ...clause.Columnar) Tuple {
+	t := make(Tuple, len(fields))
+	for i, f := range fields {
+		if c, ok := f.(interface{ Column() clause.Column }); ok {
+			t[i] = c.Column()
+		} else {
+			t[i] = clause.Column{Name: f.ColumnName()}
+		}
+	}
+	return t
+}
+
+// In creates a multi-column IN expression ((a, b) IN ((?, ?), ...)).
+// Each row holds one value per column, in column order.
+func (t Tuple) In(rows ...[]any) clause.Expression {
+	return clause.TupleIn{Columns: t, Values: rows}
+}
+
+// NotIn creates a multi-column NOT IN expression (NOT ((a, b) IN ((?, ?), ...))).
+func (t Tuple) NotIn(rows ...[]any) clause.Expression {
+	return clause.Not{Expr: clause.TupleIn{Columns: t, Values: rows}}
+}
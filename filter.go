@@ -0,0 +1,237 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements filter struct reflection, turning optional filter DTOs into query conditions.
+//
+// Service layers commonly receive search requests as structs where every field is optional.
+// FromFilter walks such a struct and produces one condition per populated field:
+//   - Pointer fields are optional: nil pointers are skipped
+//   - Non-pointer fields are skipped when they hold their zero value
+//   - Slice fields are skipped when empty
+//
+// Tag format:
+//
+//	filter:"column[,operator]"
+//
+// Supported operators: eq (default), neq, gt, gte, lt, lte, like, notlike, in, notin, null.
+// The null operator expects a bool: true renders IS NULL, false renders IS NOT NULL.
+// Fields without a filter tag, or tagged with filter:"-", are ignored.
+//
+// Usage example:
+//
+//	type UserFilter struct {
+//	    Username *string  `filter:"username,like"`
+//	    MinAge   *int     `filter:"age,gte"`
+//	    Status   []string `filter:"status,in"`
+//	    Deleted  *bool    `filter:"deleted_at,null"`
+//	}
+//
+//	users, err := userRepo.Query().
+//	    Where(sqlc.FromFilter(UserFilter{Username: &name})).
+//	    Find(ctx)
+package sqlc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// filterTagName is the struct tag key read by FromFilter.
+const filterTagName = "filter"
+
+// filterField describes a single tagged field of a filter struct.
+type filterField struct {
+	index  []int         // Field index path for reflect.Value.FieldByIndex
+	column clause.Column // Target column (optionally table-qualified)
+	op     string        // Comparison operator
+}
+
+// filterFieldCache caches parsed filter field specs per struct type.
+var filterFieldCache sync.Map // map[reflect.Type][]filterField
+
+// filterErrExpr is an expression that always fails to build.
+// It lets FromFilter report invalid input through the regular Build() error path,
+// so QueryBuilder.Where() surfaces the error on execution.
+type filterErrExpr struct {
+	err error
+}
+
+func (e filterErrExpr) Build() (string, []any, error) {
+	return "", nil, e.err
+}
+
+// FromFilter builds a condition from a filter struct (or pointer to one).
+// Only populated fields contribute to the result; an empty filter builds to "1 = 1".
+//
+// Parameters:
+//   - filter: Struct value or pointer annotated with `filter` tags
+//
+// Returns:
+//   - clause.Expression: AND of all populated field conditions
+//
+// Note:
+//   - A nil pointer filter yields an empty condition
+//   - Invalid tags or unsupported operators are reported when the expression is built
+//
+// Example:
+//
+//	type OrderFilter struct {
+//	    UserID *int64   `filter:"user_id"`
+//	    Status []string `filter:"status,in"`
+//	}
+//
+//	orders, err := orderRepo.Query().
+//	    Where(sqlc.FromFilter(&OrderFilter{Status: []string{"paid", "shipped"}})).
+//	    Find(ctx)
+func FromFilter(filter any) clause.Expression {
+	v := reflect.ValueOf(filter)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return clause.And{}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return filterErrExpr{err: fmt.Errorf("sqlc: filter must be a struct, got %T", filter)}
+	}
+
+	fields, err := loadFilterFields(v.Type())
+	if err != nil {
+		return filterErrExpr{err: err}
+	}
+
+	conds := make(clause.And, 0, len(fields))
+	for _, f := range fields {
+		cond, ok := f.condition(v.FieldByIndex(f.index))
+		if ok {
+			conds = append(conds, cond)
+		}
+	}
+	return conds
+}
+
+// loadFilterFields returns the parsed filter specs for a struct type, using the cache when possible.
+func loadFilterFields(typ reflect.Type) ([]filterField, error) {
+	if cached, ok := filterFieldCache.Load(typ); ok {
+		return cached.([]filterField), nil
+	}
+
+	var fields []filterField
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag, ok := sf.Tag.Lookup(filterTagName)
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		f, err := parseFilterTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("sqlc: invalid filter tag on %s.%s: %w", typ.Name(), sf.Name, err)
+		}
+		f.index = sf.Index
+		fields = append(fields, f)
+	}
+
+	filterFieldCache.Store(typ, fields)
+	return fields, nil
+}
+
+// parseFilterTag parses a tag value like "users.username,like".
+func parseFilterTag(tag string) (filterField, error) {
+	parts := strings.Split(tag, ",")
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return filterField{}, fmt.Errorf("missing column name")
+	}
+
+	op := "eq"
+	if len(parts) > 1 {
+		op = strings.ToLower(strings.TrimSpace(parts[1]))
+	}
+	switch op {
+	case "eq", "neq", "gt", "gte", "lt", "lte", "like", "notlike", "in", "notin", "null":
+	default:
+		return filterField{}, fmt.Errorf("unsupported operator %q", op)
+	}
+
+	column := clause.Column{Name: name}
+	if table, col, found := strings.Cut(name, "."); found {
+		column = clause.Column{Table: table, Name: col}
+	}
+	return filterField{column: column, op: op}, nil
+}
+
+// condition renders the field value into an expression.
+// The boolean result is false when the field is unset and should be skipped.
+func (f filterField) condition(v reflect.Value) (clause.Expression, bool) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	} else if v.IsZero() {
+		return nil, false
+	}
+
+	switch f.op {
+	case "in", "notin":
+		values, ok := filterSliceValues(v)
+		if !ok {
+			return filterErrExpr{err: fmt.Errorf("sqlc: filter operator %s on %s requires a slice, got %s", f.op, f.column.ColumnName(), v.Type())}, true
+		}
+		if len(values) == 0 {
+			return nil, false
+		}
+		in := clause.IN{Column: f.column, Values: values}
+		if f.op == "notin" {
+			return clause.Not{Expr: in}, true
+		}
+		return in, true
+	case "null":
+		if v.Kind() != reflect.Bool {
+			return filterErrExpr{err: fmt.Errorf("sqlc: filter operator null on %s requires a bool, got %s", f.column.ColumnName(), v.Type())}, true
+		}
+		if v.Bool() {
+			return clause.IsNull{Column: f.column}, true
+		}
+		return clause.IsNotNull{Column: f.column}, true
+	case "like", "notlike":
+		if v.Kind() != reflect.String {
+			return filterErrExpr{err: fmt.Errorf("sqlc: filter operator %s on %s requires a string, got %s", f.op, f.column.ColumnName(), v.Type())}, true
+		}
+		if f.op == "notlike" {
+			return clause.NotLike{Column: f.column, Value: v.String()}, true
+		}
+		return clause.Like{Column: f.column, Value: v.String()}, true
+	}
+
+	value := v.Interface()
+	switch f.op {
+	case "neq":
+		return clause.Neq{Column: f.column, Value: value}, true
+	case "gt":
+		return clause.Gt{Column: f.column, Value: value}, true
+	case "gte":
+		return clause.Gte{Column: f.column, Value: value}, true
+	case "lt":
+		return clause.Lt{Column: f.column, Value: value}, true
+	case "lte":
+		return clause.Lte{Column: f.column, Value: value}, true
+	default:
+		return clause.Eq{Column: f.column, Value: value}, true
+	}
+}
+
+// filterSliceValues converts a slice or array value into []any.
+func filterSliceValues(v reflect.Value) ([]any, bool) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	values := make([]any, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values, true
+}
@@ -0,0 +1,109 @@
+package sqlc_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+)
+
+type userSearchFilter struct {
+	Username *string `filter:"users.username,like"`
+	MinID    *int64  `filter:"users.id,gte"`
+	Email    string  `filter:"users.email"`
+	IDs      []int64 `filter:"users.id,in"`
+	NoEmail  *bool   `filter:"users.email,null"`
+	Ignored  *string
+	Skipped  *string `filter:"-"`
+}
+
+func TestFromFilter(t *testing.T) {
+	name := "al%"
+	minID := int64(10)
+	noEmail := false
+
+	tests := []struct {
+		name     string
+		filter   any
+		wantSQL  string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{
+			name:    "EmptyFilter",
+			filter:  userSearchFilter{},
+			wantSQL: "1 = 1",
+		},
+		{
+			name:    "NilPointer",
+			filter:  (*userSearchFilter)(nil),
+			wantSQL: "1 = 1",
+		},
+		{
+			name:     "PopulatedFields",
+			filter:   &userSearchFilter{Username: &name, MinID: &minID, Email: "a@example.com"},
+			wantSQL:  "(users.username LIKE ?) AND (users.id >= ?) AND (users.email = ?)",
+			wantArgs: []any{"al%", int64(10), "a@example.com"},
+		},
+		{
+			name:     "InAndNull",
+			filter:   userSearchFilter{IDs: []int64{1, 2}, NoEmail: &noEmail},
+			wantSQL:  "(users.id IN (?, ?)) AND (users.email IS NOT NULL)",
+			wantArgs: []any{int64(1), int64(2)},
+		},
+		{
+			name:    "NotAStruct",
+			filter:  42,
+			wantErr: true,
+		},
+		{
+			name: "UnsupportedOperator",
+			filter: struct {
+				Name *string `filter:"name,between"`
+			}{Name: &name},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sql, args, err := sqlc.FromFilter(tt.filter).Build()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got SQL %q", sql)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", sql, tt.wantSQL)
+			}
+			if len(tt.wantArgs) > 0 && !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("Args mismatch: got %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFromFilterWithQuery(t *testing.T) {
+	session := setupGenSession()
+	userRepo := sqlc.NewRepository[GenUser](session)
+
+	name := "bob"
+	gotSQL, gotArgs, err := userRepo.Query().
+		Where(sqlc.FromFilter(userSearchFilter{Username: &name})).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	want := "SELECT id, username, email, created_at FROM users WHERE (users.username LIKE ?)"
+	if gotSQL != want {
+		t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, want)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "bob" {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+}
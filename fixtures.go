@@ -0,0 +1,388 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements declarative data fixtures loaded from YAML or JSON files.
+//
+// Fixture files map table names to labelled rows. A row may reference a row of another
+// table by label: a key naming an association (user) whose column (user_id) exists on
+// the model is replaced by the primary key of the referenced row.
+//
+//	# testdata/fixtures/users.yml
+//	users:
+//	  alice:
+//	    username: alice
+//	    email: alice@example.com
+//
+//	# testdata/fixtures/posts.yml
+//	posts:
+//	  hello:
+//	    title: Hello
+//	    user: alice        # -> user_id: <alice's id>
+//
+// Usage example:
+//
+//	//go:embed testdata/fixtures
+//	var fixturesFS embed.FS
+//
+//	fx, err := sqlc.LoadFixtures(ctx, session, fixturesFS)
+//	aliceID := fx.ID("users", "alice")
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureTables maps table names to type-erased fixture inserters.
+// Entries are added by RegisterSchema, so every registered model can be loaded from fixtures.
+var fixtureTables = make(map[string]fixtureTable)
+
+// fixtureTable inserts fixture rows into the table of one model type
+type fixtureTable struct {
+	// hasColumn reports whether the model maps the column
+	hasColumn func(column string) bool
+	// insert builds a model from row, inserts it and returns its primary key
+	insert func(ctx context.Context, session *Session, row map[string]any, hooks bool) (any, error)
+}
+
+// registerFixtureTable records how to insert fixture rows for model T
+func registerFixtureTable[T any](schema Schema[T]) {
+	fields := dbFields(reflect.TypeFor[T]())
+	fixtureTables[schema.TableName()] = fixtureTable{
+		hasColumn: func(column string) bool {
+			_, ok := fields[column]
+			return ok
+		},
+		insert: func(ctx context.Context, session *Session, row map[string]any, hooks bool) (any, error) {
+			model := new(T)
+			v := reflect.ValueOf(model).Elem()
+			for column, value := range row {
+				index, ok := fields[column]
+				if !ok {
+					return nil, fmt.Errorf("unknown column %q", column)
+				}
+				if err := setFixtureValue(v.FieldByIndex(index), value); err != nil {
+					return nil, fmt.Errorf("column %q: %w", column, err)
+				}
+			}
+
+			// insertBatch backfills generated IDs via RETURNING where LastInsertId is unavailable
+			repo := NewRepository[T](session)
+			if err := policyGranted[T](ctx, session, PolicyCreate); err != nil {
+				return nil, err
+			}
+			if hooks {
+				if err := triggerBeforeCreate(repo.hookContext(ctx), model); err != nil {
+					return nil, err
+				}
+			}
+			if err := repo.insertBatch(ctx, []*T{model}, ""); err != nil {
+				return nil, err
+			}
+			if hooks {
+				if err := triggerAfterCreate(repo.hookContext(ctx), model); err != nil {
+					return nil, err
+				}
+			}
+			return schema.PK(model).Value, nil
+		},
+	}
+}
+
+// dbFields maps db column tags of a struct type to field indexes, including embedded structs
+func dbFields(typ reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	if typ.Kind() != reflect.Struct {
+		return fields
+	}
+	for _, f := range reflect.VisibleFields(typ) {
+		name, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		fields[name] = f.Index
+	}
+	return fields
+}
+
+// fixtureTimeLayouts are the accepted layouts of timestamps written as strings
+var fixtureTimeLayouts = []string{time.RFC3339Nano, time.DateTime, time.DateOnly}
+
+// setFixtureValue assigns a decoded YAML/JSON value to a model field
+func setFixtureValue(dst reflect.Value, value any) error {
+	if value == nil {
+		dst.SetZero()
+		return nil
+	}
+	if dst.Kind() == reflect.Pointer {
+		ptr := reflect.New(dst.Type().Elem())
+		if err := setFixtureValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(ptr)
+		return nil
+	}
+
+	// Nested documents (JSON columns) are decoded through their JSON representation
+	switch value.(type) {
+	case map[string]any, []any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if s, ok := dst.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(data)
+		}
+		return json.Unmarshal(data, dst.Addr().Interface())
+	}
+
+	if s, ok := value.(string); ok && dst.Type() == reflect.TypeFor[time.Time]() {
+		for _, layout := range fixtureTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				dst.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	src := reflect.ValueOf(value)
+	switch {
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+	case dst.Kind() == reflect.String && src.Kind() != reflect.String:
+		// Refuse int -> string conversions (which would yield a rune)
+		return fmt.Errorf("cannot use %T as %s", value, dst.Type())
+	case src.Type().ConvertibleTo(dst.Type()):
+		dst.Set(src.Convert(dst.Type()))
+	default:
+		if s, ok := dst.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(value)
+		}
+		return fmt.Errorf("cannot use %T as %s", value, dst.Type())
+	}
+	return nil
+}
+
+// Fixtures holds the primary keys of loaded fixture rows, keyed by table name and label.
+type Fixtures map[string]map[string]any
+
+// ID returns the primary key of the fixture row label in table, or nil if it was not loaded.
+//
+// Example:
+//
+//	fx, _ := sqlc.LoadFixtures(ctx, session, fixturesFS)
+//	alice, err := userRepo.FindOne(ctx, fx.ID("users", "alice"))
+func (f Fixtures) ID(table, label string) any {
+	return f[table][label]
+}
+
+// fixtureConfig configures LoadFixtures
+type fixtureConfig struct {
+	hooks bool // Run BeforeCreate/AfterCreate hooks
+}
+
+// FixtureOption configures LoadFixtures.
+// Uses functional options pattern to provide flexible configuration.
+type FixtureOption func(*fixtureConfig)
+
+// SkipFixtureHooks inserts fixture rows without running BeforeCreate/AfterCreate hooks,
+// e.g. to keep hand-written timestamps or to avoid side effects in tests.
+func SkipFixtureHooks() FixtureOption {
+	return func(c *fixtureConfig) {
+		c.hooks = false
+	}
+}
+
+// fixtureRow is one labelled row of a fixture file
+type fixtureRow struct {
+	table  string
+	label  string
+	values map[string]any
+}
+
+// LoadFixtures inserts the fixture rows of all .yml, .yaml and .json files in fsys.
+//
+// Each file is a mapping of table names to rows keyed by label. Tables must belong to
+// models registered with RegisterSchema; rows are inserted through the model's schema in
+// file order (files sorted by path), with parents inserted before the rows referencing them.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - session: Database session; all rows are inserted in one transaction
+//   - fsys: File system holding the fixture files (e.g. embed.FS, os.DirFS, fstest.MapFS)
+//   - opts: Options such as SkipFixtureHooks
+//
+// Returns:
+//   - Fixtures: Primary keys of the inserted rows by table and label
+//   - error: Parse, reference or insert error; nothing is inserted on error
+//
+// Example:
+//
+//	fx, err := sqlc.LoadFixtures(ctx, session, os.DirFS("testdata/fixtures"))
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	posts, _ := postRepo.Query().Where(generated.Post.UserID.Eq(fx.ID("users", "alice"))).Find(ctx)
+//
+// Note:
+//   - A reference (user: alice) is resolved when user is not a column but user_id is and
+//     alice labels a row of exactly one table (or of the user/users table)
+//   - Timestamps may be written as RFC 3339, "2006-01-02 15:04:05" or "2006-01-02"
+//   - Nested mappings and lists are stored through the field's JSON representation
+func LoadFixtures(ctx context.Context, session *Session, fsys fs.FS, opts ...FixtureOption) (Fixtures, error) {
+	cfg := fixtureConfig{hooks: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	rows, err := readFixtures(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := make(Fixtures)
+	err = session.Transaction(ctx, func(tx *Session) error {
+		// Insert rows whose references are resolved; repeat until every row is inserted
+		pending := rows
+		for len(pending) > 0 {
+			var deferred []fixtureRow
+			for _, row := range pending {
+				values, ok, err := resolveFixtureRefs(row, rows, fixtures)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					deferred = append(deferred, row)
+					continue
+				}
+				id, err := fixtureTables[row.table].insert(ctx, tx, values, cfg.hooks)
+				if err != nil {
+					return fmt.Errorf("sqlc: fixture %s.%s: %w", row.table, row.label, err)
+				}
+				if fixtures[row.table] == nil {
+					fixtures[row.table] = make(map[string]any)
+				}
+				fixtures[row.table][row.label] = id
+			}
+			if len(deferred) == len(pending) {
+				return fmt.Errorf("sqlc: fixture %s.%s: circular reference", deferred[0].table, deferred[0].label)
+			}
+			pending = deferred
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fixtures, nil
+}
+
+// readFixtures parses all fixture files of fsys in path order, keeping row order within files
+func readFixtures(fsys fs.FS) ([]fixtureRow, error) {
+	var rows []fixtureRow
+	seen := make(map[string]bool)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch path.Ext(name) {
+		case ".yml", ".yaml", ".json":
+		default:
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		// JSON is a subset of YAML; decoding into nodes preserves document order
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("sqlc: fixture file %s: %w", name, err)
+		}
+		if len(doc.Content) == 0 {
+			return nil
+		}
+		tables := doc.Content[0]
+		if tables.Kind != yaml.MappingNode {
+			return fmt.Errorf("sqlc: fixture file %s: expected a mapping of table names", name)
+		}
+		for i := 0; i+1 < len(tables.Content); i += 2 {
+			table, labels := tables.Content[i].Value, tables.Content[i+1]
+			if _, ok := fixtureTables[table]; !ok {
+				return fmt.Errorf("sqlc: fixture file %s: no schema registered for table %q", name, table)
+			}
+			if labels.Kind != yaml.MappingNode {
+				return fmt.Errorf("sqlc: fixture file %s: expected a mapping of labels for table %q", name, table)
+			}
+			for j := 0; j+1 < len(labels.Content); j += 2 {
+				label := labels.Content[j].Value
+				if seen[table+"."+label] {
+					return fmt.Errorf("sqlc: fixture file %s: duplicate fixture %s.%s", name, table, label)
+				}
+				seen[table+"."+label] = true
+
+				var values map[string]any
+				if err := labels.Content[j+1].Decode(&values); err != nil {
+					return fmt.Errorf("sqlc: fixture file %s: %s.%s: %w", name, table, label, err)
+				}
+				rows = append(rows, fixtureRow{table: table, label: label, values: values})
+			}
+		}
+		return nil
+	})
+	return rows, err
+}
+
+// resolveFixtureRefs replaces references of row by the primary keys of the referenced rows.
+// ok is false while a referenced row has not been inserted yet.
+func resolveFixtureRefs(row fixtureRow, all []fixtureRow, loaded Fixtures) (values map[string]any, ok bool, err error) {
+	table := fixtureTables[row.table]
+	values = make(map[string]any, len(row.values))
+	for key, value := range row.values {
+		label, isLabel := value.(string)
+		if table.hasColumn(key) || !isLabel || !table.hasColumn(key+"_id") {
+			values[key] = value
+			continue
+		}
+		target, err := fixtureRefTable(key, label, all)
+		if err != nil {
+			return nil, false, fmt.Errorf("sqlc: fixture %s.%s: %w", row.table, row.label, err)
+		}
+		id, done := loaded[target][label]
+		if !done {
+			return nil, false, nil
+		}
+		values[key+"_id"] = id
+	}
+	return values, true, nil
+}
+
+// fixtureRefTable finds the table of the row labelled label referenced through association
+func fixtureRefTable(association, label string, all []fixtureRow) (string, error) {
+	var tables []string
+	for _, r := range all {
+		if r.label == label {
+			tables = append(tables, r.table)
+		}
+	}
+	switch len(tables) {
+	case 0:
+		return "", fmt.Errorf("%s references unknown fixture %q", association, label)
+	case 1:
+		return tables[0], nil
+	}
+	for _, t := range tables {
+		if t == association || t == association+"s" {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%s reference %q is ambiguous between tables %s", association, label, strings.Join(tables, ", "))
+}
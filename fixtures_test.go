@@ -0,0 +1,118 @@
+package sqlc_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestLoadFixtures(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	fx, err := sqlc.LoadFixtures(ctx, session, os.DirFS("testdata/fixtures"))
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+
+	alice, err := sqlc.NewRepository[Member](session).FindOne(ctx, fx.ID("members", "alice"))
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if alice.Name != "Alice" || alice.Level != 3 {
+		t.Errorf("unexpected member: %+v", alice)
+	}
+	// department: engineering -> department_id
+	if got, want := int64(alice.DepartmentID), fx.ID("departments", "engineering"); got != want {
+		t.Errorf("expected department_id %v, got %v", want, got)
+	}
+	if want := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC); !alice.CreatedAt.Equal(want) {
+		t.Errorf("expected created_at %v, got %v", want, alice.CreatedAt)
+	}
+
+	bob, err := sqlc.NewRepository[Member](session).FindOne(ctx, fx.ID("members", "bob"))
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if got, want := int64(bob.DepartmentID), fx.ID("departments", "sales"); got != want {
+		t.Errorf("expected department_id %v, got %v", want, got)
+	}
+}
+
+func TestLoadFixturesHooks(t *testing.T) {
+	sqlc.RegisterSchema(HookMemberSchema{})
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.Exec(`CREATE TABLE hook_members (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		created_at DATETIME
+	)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	fsys := fstest.MapFS{"hooks.yml": {Data: []byte("hook_members:\n  h1:\n    name: h1\n")}}
+	repo := sqlc.NewRepository[HookMember](session)
+
+	tests := []struct {
+		name     string
+		opts     []sqlc.FixtureOption
+		wantZero bool // created_at left unset by the skipped BeforeCreate hook
+	}{
+		{"RunsHooks", nil, false},
+		{"SkipHooks", []sqlc.FixtureOption{sqlc.SkipFixtureHooks()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx, err := sqlc.LoadFixtures(ctx, session, fsys, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadFixtures failed: %v", err)
+			}
+			m, err := repo.FindOne(ctx, fx.ID("hook_members", "h1"))
+			if err != nil {
+				t.Fatalf("FindOne failed: %v", err)
+			}
+			if m.CreatedAt.IsZero() != tt.wantZero {
+				t.Errorf("expected zero created_at %v, got %v", tt.wantZero, m.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestLoadFixturesErrors(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{"UnknownTable", "widgets:\n  w1:\n    name: w\n", `no schema registered for table "widgets"`},
+		{"UnknownColumn", "departments:\n  d1:\n    color: red\n", `unknown column "color"`},
+		{"UnknownReference", "members:\n  m1:\n    email: m1@test.com\n    department: nowhere\n", `unknown fixture "nowhere"`},
+		{"DuplicateLabel", "departments:\n  d1:\n    name: a\n  d1:\n    name: b\n", "d1"},
+		{"InvalidTimestamp", "members:\n  m1:\n    email: m1@test.com\n    created_at: yesterday\n", `invalid timestamp "yesterday"`},
+		{"RollsBack", "departments:\n  d1:\n    name: kept?\n  d2:\n    color: red\n", `unknown column "color"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"fixtures.yml": {Data: []byte(tt.file)}}
+			_, err := sqlc.LoadFixtures(ctx, session, fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if n, _ := sqlc.NewRepository[Department](session).Query().Count(ctx); n != 0 {
+				t.Errorf("expected no rows after failed load, got %d departments", n)
+			}
+		})
+	}
+}
@@ -19,6 +19,31 @@ type Config struct {
 	// FieldTypeMap maps Go types to field types.
 	// Example: map[string]string{"sql.NullTime": "field.Time"}
 	FieldTypeMap map[string]string
+
+	// SoftDeleteColumns maps model names to their soft delete column,
+	// overriding the DeletedAt field and the softDelete tag.
+	// Timestamp columns (time or integer fields) record the deletion time;
+	// bool columns are flags where false marks live records.
+	// Example: map[string]string{"Account": "removed_at", "Post": "is_deleted"}
+	SoftDeleteColumns map[string]string
+
+	// DefaultOrder maps model names to the ORDER BY applied to their queries
+	// when no OrderBy is given: comma-separated columns, each optionally
+	// followed by ASC or DESC. Queries opt out with Unordered().
+	// Example: map[string]string{"Post": "created_at DESC, id DESC"}
+	DefaultOrder map[string]string
+
+	// Partials maps model names to column subset structs generated next to the schema,
+	// as semicolon-separated "Name: column, ..." entries. Each partial is named after
+	// the model plus Name and is read with sqlc.FindPartial / sqlc.FirstPartial.
+	// Example: map[string]string{"User": "Identity: id, username; Card: id, avatar_url"}
+	Partials map[string]string
+
+	// Patches specifies the models that get a <Model>Patch struct for PATCH-style
+	// partial updates: pointer fields decoded from JSON request bodies, turned into
+	// UpdateColumns assignments of the non-nil fields by ApplyTo.
+	// Supports string names or type instances: []any{"User", &Post{}}
+	Patches []any
 }
 
 // ConfigFileName is the convention filename for configuration.
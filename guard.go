@@ -0,0 +1,209 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements the SQL statement guard, which inspects SQL before execution.
+//
+// When several teams share one database, a single careless statement (a DELETE without
+// WHERE, an ad-hoc DROP TABLE, a query reaching into another team's schema) can do a lot
+// of damage. Guards are session-wide rules that run before every statement and can
+// reject it before it reaches the database.
+//
+// Built-in rules:
+//   - DenyDeleteWithoutWhere / DenyUpdateWithoutWhere: block unbounded writes
+//   - DenyDDL: block CREATE/ALTER/DROP/TRUNCATE/RENAME
+//   - AllowStatements / DenyStatements: allow-list or deny-list statement kinds
+//   - DenyCrossSchema: block schema-qualified table references outside an allow-list
+//
+// Rules inspect SQL text; they are lightweight heuristics, not a full SQL parser.
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.MySQL,
+//	    sqlc.WithSQLGuard(
+//	        sqlc.DenyDeleteWithoutWhere(),
+//	        sqlc.DenyUpdateWithoutWhere(),
+//	        sqlc.DenyDDL(),
+//	        sqlc.DenyCrossSchema("orders"),
+//	    ),
+//	)
+//
+//	_, err := session.Exec(ctx, "DELETE FROM users")
+//	errors.Is(err, sqlc.ErrStatementRejected) // true
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ErrStatementRejected is returned when a guard rule rejects a statement.
+// Rule errors wrap it, so callers can check with errors.Is.
+var ErrStatementRejected = errors.New("sqlc: statement rejected by guard")
+
+// GuardRule inspects a SQL statement before execution.
+// Returning a non-nil error prevents the statement from being executed.
+// Custom rules should wrap ErrStatementRejected so callers can detect rejections.
+//
+// Example:
+//
+//	denySelectStar := func(ctx context.Context, query string) error {
+//	    if strings.Contains(query, "SELECT *") {
+//	        return fmt.Errorf("%w: SELECT * is not allowed", sqlc.ErrStatementRejected)
+//	    }
+//	    return nil
+//	}
+type GuardRule func(ctx context.Context, query string) error
+
+// WithSQLGuard adds guard rules to the session.
+// Rules run in order before every statement; the first rejection aborts execution.
+// Transaction sessions created via Begin/Transaction inherit the rules.
+//
+// Parameters:
+//   - rules: Guard rules to apply (variadic, appended to existing rules)
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL,
+//	    sqlc.WithSQLGuard(sqlc.DenyDeleteWithoutWhere(), sqlc.DenyDDL()),
+//	)
+func WithSQLGuard(rules ...GuardRule) SessionOption {
+	return func(s *Session) {
+		s.guards = append(s.guards, rules...)
+	}
+}
+
+// checkGuards runs all guard rules against a query.
+func (s *Session) checkGuards(ctx context.Context, query string) error {
+	for _, rule := range s.guards {
+		if err := rule(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DenyDeleteWithoutWhere rejects DELETE statements that have no WHERE clause.
+func DenyDeleteWithoutWhere() GuardRule {
+	return func(ctx context.Context, query string) error {
+		if statementKind(query) == "DELETE" && !whereRegexp.MatchString(query) {
+			return fmt.Errorf("%w: DELETE without WHERE", ErrStatementRejected)
+		}
+		return nil
+	}
+}
+
+// DenyUpdateWithoutWhere rejects UPDATE statements that have no WHERE clause.
+func DenyUpdateWithoutWhere() GuardRule {
+	return func(ctx context.Context, query string) error {
+		if statementKind(query) == "UPDATE" && !whereRegexp.MatchString(query) {
+			return fmt.Errorf("%w: UPDATE without WHERE", ErrStatementRejected)
+		}
+		return nil
+	}
+}
+
+// DenyDDL rejects schema-changing statements (CREATE, ALTER, DROP, TRUNCATE, RENAME).
+func DenyDDL() GuardRule {
+	return DenyStatements("CREATE", "ALTER", "DROP", "TRUNCATE", "RENAME")
+}
+
+// AllowStatements rejects every statement whose kind is not listed.
+// Kinds are leading SQL keywords and are matched case-insensitively.
+//
+// Example:
+//
+//	// Read-only reporting session
+//	sqlc.WithSQLGuard(sqlc.AllowStatements("SELECT", "WITH"))
+func AllowStatements(kinds ...string) GuardRule {
+	allowed := normalizeKinds(kinds)
+	return func(ctx context.Context, query string) error {
+		kind := statementKind(query)
+		if !slices.Contains(allowed, kind) {
+			return fmt.Errorf("%w: %s statements are not allowed", ErrStatementRejected, kind)
+		}
+		return nil
+	}
+}
+
+// DenyStatements rejects statements whose kind is listed.
+// Kinds are leading SQL keywords and are matched case-insensitively.
+func DenyStatements(kinds ...string) GuardRule {
+	denied := normalizeKinds(kinds)
+	return func(ctx context.Context, query string) error {
+		kind := statementKind(query)
+		if slices.Contains(denied, kind) {
+			return fmt.Errorf("%w: %s statements are not allowed", ErrStatementRejected, kind)
+		}
+		return nil
+	}
+}
+
+// DenyCrossSchema rejects statements referencing schema-qualified tables
+// (e.g. FROM billing.invoices) unless the schema is listed.
+// Unqualified table names always pass.
+//
+// Example:
+//
+//	// Only the orders schema may be addressed explicitly
+//	sqlc.WithSQLGuard(sqlc.DenyCrossSchema("orders"))
+func DenyCrossSchema(allowed ...string) GuardRule {
+	return func(ctx context.Context, query string) error {
+		for _, m := range qualifiedTableRegexp.FindAllStringSubmatch(query, -1) {
+			schema := strings.Trim(m[1], "`\"")
+			if !slices.ContainsFunc(allowed, func(s string) bool { return strings.EqualFold(s, schema) }) {
+				return fmt.Errorf("%w: access to schema %q is not allowed", ErrStatementRejected, schema)
+			}
+		}
+		return nil
+	}
+}
+
+var (
+	// whereRegexp matches a WHERE keyword anywhere in the statement.
+	whereRegexp = regexp.MustCompile(`(?i)\bWHERE\b`)
+
+	// qualifiedTableRegexp matches schema.table after keywords that introduce a table reference.
+	qualifiedTableRegexp = regexp.MustCompile("(?i)\\b(?:FROM|JOIN|INTO|UPDATE|TABLE)\\s+([`\"]?\\w+[`\"]?)\\.[`\"]?\\w+")
+)
+
+// statementKind returns the leading keyword of a statement in upper case,
+// skipping whitespace, comments and opening parentheses.
+func statementKind(query string) string {
+	s := query
+	for {
+		s = strings.TrimLeftFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '(' })
+		switch {
+		case strings.HasPrefix(s, "--"):
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				s = s[i+1:]
+				continue
+			}
+			return ""
+		case strings.HasPrefix(s, "/*"):
+			if i := strings.Index(s, "*/"); i >= 0 {
+				s = s[i+2:]
+				continue
+			}
+			return ""
+		}
+		break
+	}
+
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.ToUpper(s[:end])
+}
+
+// normalizeKinds upper-cases statement kinds for comparison.
+func normalizeKinds(kinds []string) []string {
+	out := make([]string, len(kinds))
+	for i, k := range kinds {
+		out[i] = strings.ToUpper(strings.TrimSpace(k))
+	}
+	return out
+}
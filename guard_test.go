@@ -0,0 +1,115 @@
+package sqlc_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSQLGuardRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    sqlc.GuardRule
+		query   string
+		wantErr bool
+	}{
+		{"DeleteWithWhere", sqlc.DenyDeleteWithoutWhere(), "DELETE FROM users WHERE id = ?", false},
+		{"DeleteWithoutWhere", sqlc.DenyDeleteWithoutWhere(), "DELETE FROM users", true},
+		{"DeleteLowercase", sqlc.DenyDeleteWithoutWhere(), "  delete from users", true},
+		{"DeleteAfterComment", sqlc.DenyDeleteWithoutWhere(), "/* cleanup */ DELETE FROM users", true},
+		{"UpdateWithoutWhere", sqlc.DenyUpdateWithoutWhere(), "UPDATE users SET name = ?", true},
+		{"UpdateWithWhere", sqlc.DenyUpdateWithoutWhere(), "UPDATE users SET name = ? WHERE id = ?", false},
+		{"UpdateRuleIgnoresDelete", sqlc.DenyUpdateWithoutWhere(), "DELETE FROM users", false},
+		{"DDLDrop", sqlc.DenyDDL(), "DROP TABLE users", true},
+		{"DDLTruncate", sqlc.DenyDDL(), "-- reset\ntruncate table users", true},
+		{"DDLSelect", sqlc.DenyDDL(), "SELECT id FROM users", false},
+		{"AllowListHit", sqlc.AllowStatements("select", "with"), "SELECT id FROM users", false},
+		{"AllowListMiss", sqlc.AllowStatements("select"), "INSERT INTO users (id) VALUES (?)", true},
+		{"DenyList", sqlc.DenyStatements("INSERT"), "INSERT INTO users (id) VALUES (?)", true},
+		{"CrossSchemaAllowed", sqlc.DenyCrossSchema("app"), "SELECT users.id FROM app.users JOIN posts ON posts.user_id = users.id", false},
+		{"CrossSchemaDenied", sqlc.DenyCrossSchema("app"), "SELECT i.id FROM users JOIN billing.invoices i ON i.user_id = users.id", true},
+		{"CrossSchemaQuoted", sqlc.DenyCrossSchema(), "SELECT * FROM `billing`.`invoices`", true},
+		{"CrossSchemaQualifiedColumns", sqlc.DenyCrossSchema(), "SELECT users.id FROM users WHERE users.id = ?", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.rule(context.Background(), tt.query)
+			if tt.wantErr {
+				if !errors.Is(err, sqlc.ErrStatementRejected) {
+					t.Errorf("expected ErrStatementRejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSQLGuardSession(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO users (id, username) VALUES (1, 'alice'), (2, 'bob')"); err != nil {
+		t.Fatalf("failed to seed table: %v", err)
+	}
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{},
+		sqlc.WithSQLGuard(sqlc.DenyDeleteWithoutWhere(), sqlc.DenyDDL()),
+	)
+
+	t.Run("RejectedExec", func(t *testing.T) {
+		_, err := session.Exec(ctx, "DELETE FROM users")
+		if !errors.Is(err, sqlc.ErrStatementRejected) {
+			t.Fatalf("expected ErrStatementRejected, got %v", err)
+		}
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Errorf("rejected statement must not execute, got %d rows", count)
+		}
+	})
+
+	t.Run("AllowedExec", func(t *testing.T) {
+		if _, err := session.Exec(ctx, "DELETE FROM users WHERE id = ?", 2); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("RejectedQueryRow", func(t *testing.T) {
+		var n int
+		err := session.QueryRow(ctx, "DROP TABLE users").Scan(&n)
+		if !errors.Is(err, sqlc.ErrStatementRejected) {
+			t.Fatalf("expected ErrStatementRejected from rejected QueryRow, got %v", err)
+		}
+		if _, err := db.Exec("SELECT 1 FROM users"); err != nil {
+			t.Errorf("table should still exist: %v", err)
+		}
+	})
+
+	t.Run("InheritedByTransaction", func(t *testing.T) {
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			_, err := tx.Exec(ctx, "DROP TABLE users")
+			return err
+		})
+		if !errors.Is(err, sqlc.ErrStatementRejected) {
+			t.Errorf("expected ErrStatementRejected, got %v", err)
+		}
+	})
+}
@@ -13,6 +13,13 @@
 //   - Create: BeforeCreate → INSERT → AfterCreate
 //   - Update: BeforeUpdate → UPDATE → AfterUpdate
 //   - Delete: BeforeDelete → DELETE → AfterDelete
+//   - Restore: BeforeRestore → UPDATE (clear soft delete marker) → AfterRestore
+//
+// Hooks are skipped for repositories returned by Repository.SkipHooks() and for
+// contexts returned by WithoutHooks.
+//
+// Hooks receive a context carrying the session of the write, so they can query and
+// write in the same transaction: see SessionFromContext and RepositoryFromContext.
 //
 // Usage example:
 //
@@ -128,8 +135,8 @@ type BeforeCreateInterface interface {
 //	        Type:    "order_created",
 //	        Message: fmt.Sprintf("Order #%d has been created", o.ID),
 //	    }
-//	    // Note: Need to access Repository via context or use global service
-//	    return notificationService.Send(ctx, notification)
+//	    // Written in the transaction of the order, if any
+//	    return sqlc.RepositoryFromContext[Notification](ctx).Create(ctx, notification)
 //	}
 type AfterCreateInterface interface {
 	AfterCreate(context.Context) error
@@ -179,8 +186,8 @@ type AfterCreateInterface interface {
 //	}
 //
 //	func (o *Order) BeforeUpdate(ctx context.Context) error {
-//	    // Load original order from database
-//	    original, err := orderRepo.FindOne(ctx, o.ID)
+//	    // Load original order from database, in the transaction of the update
+//	    original, err := sqlc.RepositoryFromContext[Order](ctx).FindOne(ctx, o.ID)
 //	    if err != nil {
 //	        return err
 //	    }
@@ -246,7 +253,7 @@ type BeforeUpdateInterface interface {
 //	        Price:     p.Price,
 //	        ChangedAt: time.Now(),
 //	    }
-//	    return priceHistoryRepo.Create(ctx, priceHistory)
+//	    return sqlc.RepositoryFromContext[PriceHistory](ctx).Create(ctx, priceHistory)
 //	}
 type AfterUpdateInterface interface {
 	AfterUpdate(context.Context) error
@@ -278,7 +285,7 @@ type AfterUpdateInterface interface {
 //
 //	func (u *User) BeforeDelete(ctx context.Context) error {
 //	    // Check if user has pending orders
-//	    count, err := orderRepo.Query().
+//	    count, err := sqlc.RepositoryFromContext[Order](ctx).Query().
 //	        Where(generated.Order.UserID.Eq(u.ID)).
 //	        Where(generated.Order.Status.Ne("completed")).
 //	        Count(ctx)
@@ -305,6 +312,7 @@ type AfterUpdateInterface interface {
 //
 //	func (o *Order) BeforeDelete(ctx context.Context) error {
 //	    // Delete all order items
+//	    orderItemRepo := sqlc.RepositoryFromContext[OrderItem](ctx)
 //	    orderItems, err := orderItemRepo.Query().
 //	        Where(generated.OrderItem.OrderID.Eq(o.ID)).
 //	        Find(ctx)
@@ -364,12 +372,126 @@ type BeforeDeleteInterface interface {
 //	        EntityID:  d.ID,
 //	        Timestamp: time.Now(),
 //	    }
-//	    return auditLogRepo.Create(ctx, auditLog)
+//	    return sqlc.RepositoryFromContext[AuditLog](ctx).Create(ctx, auditLog)
 //	}
 type AfterDeleteInterface interface {
 	AfterDelete(context.Context) error
 }
 
+// BeforeRestoreInterface defines the hook interface for before restore.
+// If a model implements this interface, RestoreModel() method will call BeforeRestore() before
+// clearing the soft delete marker.
+//
+// Use cases:
+//   - Validation: Refuse to restore records whose parent is still deleted
+//   - Uniqueness: Check that no live record took over a unique value meanwhile
+//
+// Notes:
+//   - If error is returned, restore operation will be aborted
+//   - Not triggered for Restore(), RestoreMany() or RestoreWhere() (no model instance)
+//
+// Example:
+//
+//	func (p *Post) BeforeRestore(ctx context.Context) error {
+//	    if _, err := sqlc.RepositoryFromContext[User](ctx).FindOne(ctx, p.UserID); err != nil {
+//	        return fmt.Errorf("cannot restore post of deleted user: %w", err)
+//	    }
+//	    return nil
+//	}
+type BeforeRestoreInterface interface {
+	BeforeRestore(context.Context) error
+}
+
+// AfterRestoreInterface defines the hook interface for after restore.
+// If a model implements this interface, RestoreModel() method will call AfterRestore() after the
+// soft delete marker was cleared.
+//
+// Use cases:
+//   - Search indexing: Re-index the restored record
+//   - Audit logging: Record restore operations
+//
+// Notes:
+//   - If error is returned inside a transaction, the restore is rolled back
+//   - The model's soft delete field is already cleared at this point
+//
+// Example:
+//
+//	func (d *Document) AfterRestore(ctx context.Context) error {
+//	    return searchService.IndexDocument(ctx, d)
+//	}
+type AfterRestoreInterface interface {
+	AfterRestore(context.Context) error
+}
+
+// skipHooksKey is the context key of WithoutHooks.
+type skipHooksKey struct{}
+
+// WithoutHooks returns a context in which writes do not run lifecycle hooks
+// (Before/After Create, Update, Delete and Restore), e.g. for bulk imports and data
+// migrations where hook side effects such as emails or timestamps are undesirable.
+//
+// Example:
+//
+//	ctx := sqlc.WithoutHooks(ctx)
+//	err := userRepo.BatchCreate(ctx, imported) // BeforeCreate/AfterCreate not called
+//
+// Note:
+//   - Hooks receive the context they are called with: a hook writing through another
+//     repository with ctx propagates the setting to that write
+//   - Only lifecycle hooks are skipped; search indexing (WithIndexer) still happens
+//   - Repository.SkipHooks() does the same for one repository
+func WithoutHooks(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipHooksKey{}, true)
+}
+
+// hooksSkipped reports whether lifecycle hooks are disabled for ctx.
+func hooksSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipHooksKey{}).(bool)
+	return skip
+}
+
+// hookSessionKey is the context key of the session passed to hooks.
+type hookSessionKey struct{}
+
+// withHookSession returns a context carrying the session of a write for its hooks.
+func withHookSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, hookSessionKey{}, session)
+}
+
+// SessionFromContext returns the session of the write running a lifecycle hook,
+// or nil outside hooks. Inside a transaction it is the transaction session, so the
+// queries and writes of the hook commit or roll back with the write.
+//
+// Example:
+//
+//	func (o *Order) AfterCreate(ctx context.Context) error {
+//	    session := sqlc.SessionFromContext(ctx)
+//	    _, err := session.Exec(ctx, "UPDATE users SET order_count = order_count + 1 WHERE id = ?", o.UserID)
+//	    return err
+//	}
+func SessionFromContext(ctx context.Context) *Session {
+	session, _ := ctx.Value(hookSessionKey{}).(*Session)
+	return session
+}
+
+// RepositoryFromContext returns a repository of model M on the session of the write
+// running a lifecycle hook (see SessionFromContext), or nil outside hooks.
+//
+// Example:
+//
+//	func (d *Document) AfterDelete(ctx context.Context) error {
+//	    return sqlc.RepositoryFromContext[AuditLog](ctx).Create(ctx, &AuditLog{
+//	        Action: "delete", Entity: "document", EntityID: d.ID,
+//	    })
+//	}
+func RepositoryFromContext[M any](ctx context.Context) *Repository[M] {
+	session := SessionFromContext(ctx)
+	if session == nil {
+		return nil
+	}
+	return NewRepository[M](session)
+}
+
 // triggerBeforeCreate triggers the BeforeCreate hook for a model.
 // If the model implements BeforeCreateInterface, calls its BeforeCreate method.
 //
@@ -396,7 +518,7 @@ type AfterDeleteInterface interface {
 func triggerBeforeCreate(ctx context.Context, model any) error {
 	// Use type assertion to check if model implements BeforeCreateInterface
 	// If implemented, call its BeforeCreate method
-	if m, ok := model.(BeforeCreateInterface); ok {
+	if m, ok := model.(BeforeCreateInterface); ok && !hooksSkipped(ctx) {
 		return m.BeforeCreate(ctx)
 	}
 	// Interface not implemented, return nil (no-op)
@@ -434,7 +556,7 @@ func triggerBeforeCreate(ctx context.Context, model any) error {
 func triggerAfterCreate(ctx context.Context, model any) error {
 	// Use type assertion to check if model implements AfterCreateInterface
 	// If implemented, call its AfterCreate method
-	if m, ok := model.(AfterCreateInterface); ok {
+	if m, ok := model.(AfterCreateInterface); ok && !hooksSkipped(ctx) {
 		return m.AfterCreate(ctx)
 	}
 	// Interface not implemented, return nil (no-op)
@@ -469,7 +591,7 @@ func triggerAfterCreate(ctx context.Context, model any) error {
 func triggerBeforeUpdate(ctx context.Context, model any) error {
 	// Use type assertion to check if model implements BeforeUpdateInterface
 	// If implemented, call its BeforeUpdate method
-	if m, ok := model.(BeforeUpdateInterface); ok {
+	if m, ok := model.(BeforeUpdateInterface); ok && !hooksSkipped(ctx) {
 		return m.BeforeUpdate(ctx)
 	}
 	// Interface not implemented, return nil (no-op)
@@ -503,7 +625,7 @@ func triggerBeforeUpdate(ctx context.Context, model any) error {
 func triggerAfterUpdate(ctx context.Context, model any) error {
 	// Use type assertion to check if model implements AfterUpdateInterface
 	// If implemented, call its AfterUpdate method
-	if m, ok := model.(AfterUpdateInterface); ok {
+	if m, ok := model.(AfterUpdateInterface); ok && !hooksSkipped(ctx) {
 		return m.AfterUpdate(ctx)
 	}
 	// Interface not implemented, return nil (no-op)
@@ -541,7 +663,7 @@ func triggerAfterUpdate(ctx context.Context, model any) error {
 func triggerBeforeDelete(ctx context.Context, model any) error {
 	// Use type assertion to check if model implements BeforeDeleteInterface
 	// If implemented, call its BeforeDelete method
-	if m, ok := model.(BeforeDeleteInterface); ok {
+	if m, ok := model.(BeforeDeleteInterface); ok && !hooksSkipped(ctx) {
 		return m.BeforeDelete(ctx)
 	}
 	// Interface not implemented, return nil (no-op)
@@ -577,9 +699,33 @@ func triggerBeforeDelete(ctx context.Context, model any) error {
 func triggerAfterDelete(ctx context.Context, model any) error {
 	// Use type assertion to check if model implements AfterDeleteInterface
 	// If implemented, call its AfterDelete method
-	if m, ok := model.(AfterDeleteInterface); ok {
+	if m, ok := model.(AfterDeleteInterface); ok && !hooksSkipped(ctx) {
 		return m.AfterDelete(ctx)
 	}
 	// Interface not implemented, return nil (no-op)
 	return nil
 }
+
+// triggerBeforeRestore triggers the BeforeRestore hook for a model.
+// If the model implements BeforeRestoreInterface, calls its BeforeRestore method.
+//
+// Usage scenarios:
+//   - Repository.RestoreModel() calls before clearing the soft delete marker
+func triggerBeforeRestore(ctx context.Context, model any) error {
+	if m, ok := model.(BeforeRestoreInterface); ok && !hooksSkipped(ctx) {
+		return m.BeforeRestore(ctx)
+	}
+	return nil
+}
+
+// triggerAfterRestore triggers the AfterRestore hook for a model.
+// If the model implements AfterRestoreInterface, calls its AfterRestore method.
+//
+// Usage scenarios:
+//   - Repository.RestoreModel() calls after clearing the soft delete marker
+func triggerAfterRestore(ctx context.Context, model any) error {
+	if m, ok := model.(AfterRestoreInterface); ok && !hooksSkipped(ctx) {
+		return m.AfterRestore(ctx)
+	}
+	return nil
+}
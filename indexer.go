@@ -0,0 +1,365 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements search index maintenance: delivering model changes to external indexes.
+//
+// Applications that mirror models into a search engine (Bleve, Elasticsearch, ...) usually
+// update the index from After* hooks, which runs before the transaction commits (indexing
+// rows that may be rolled back) and loses updates when the index is unavailable. An Indexer
+// attached to a session receives the changes written through repositories instead:
+//   - Changes are delivered after the transaction commits; rolled back changes are dropped
+//   - Changes are buffered and delivered in batches (WithIndexBatchSize)
+//   - Failed deliveries are kept in a retry queue and retried with Retry
+//
+// Indexed operations are those that trigger lifecycle hooks: Create, CreateOrIgnore,
+// BatchCreate, Upsert, Update, DeleteModel and RestoreModel. Column and bulk operations
+// (UpdateColumns, UpdateWhere, Delete by id, DeleteWhere, ...) have no model instance
+// and are not indexed.
+//
+// Usage example:
+//
+//	ix := sqlc.NewIndexer(sqlc.WithIndexBatchSize(100))
+//	sqlc.RegisterIndex(ix, func(ctx context.Context, op sqlc.ChangeOp, p *models.Product) error {
+//	    if op == sqlc.ChangeDelete {
+//	        return index.Delete(strconv.FormatInt(p.ID, 10))
+//	    }
+//	    return index.Index(strconv.FormatInt(p.ID, 10), p)
+//	})
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithIndexer(ix))
+//
+//	// Periodically (and on shutdown): deliver buffered changes, retry failed ones
+//	_ = ix.Flush(ctx)
+//	_ = ix.Retry(ctx)
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// IndexEvent is a model change delivered to a search index.
+type IndexEvent struct {
+	Op       ChangeOp // ChangeCreate, ChangeUpdate or ChangeDelete (soft or hard)
+	Table    string   // Table of the changed model
+	Model    any      // Changed model (*T)
+	Attempts int      // Failed deliveries so far
+}
+
+// IndexEntry is a typed change passed to batch index handlers (see RegisterBatchIndex).
+type IndexEntry[T any] struct {
+	Op    ChangeOp
+	Model *T
+}
+
+// indexHandler delivers events of one model type and returns the events that failed.
+type indexHandler func(ctx context.Context, events []IndexEvent) ([]IndexEvent, error)
+
+// Indexer configuration
+type indexerConfig struct {
+	batchSize   int                                                       // Buffered events that trigger a flush
+	maxAttempts int                                                       // Deliveries before an event is dropped
+	inTx        bool                                                      // Deliver synchronously inside the transaction
+	onError     func(ctx context.Context, events []IndexEvent, err error) // Delivery failure callback
+}
+
+// IndexerOption configures an Indexer.
+type IndexerOption func(*indexerConfig)
+
+// WithIndexBatchSize buffers changes until n are pending, then delivers them together
+// (default 1: deliver after every write or commit). Call Flush to deliver a partial batch.
+func WithIndexBatchSize(n int) IndexerOption {
+	return func(c *indexerConfig) {
+		c.batchSize = max(1, n)
+	}
+}
+
+// WithIndexMaxAttempts sets how often a change is delivered before it is dropped from
+// the retry queue (default 3). Dropped changes are reported to the error handler.
+func WithIndexMaxAttempts(n int) IndexerOption {
+	return func(c *indexerConfig) {
+		c.maxAttempts = max(1, n)
+	}
+}
+
+// WithIndexInTransaction delivers changes synchronously, inside the transaction, instead
+// of after commit. A failing handler fails the repository operation, so the transaction
+// rolls back: the index never misses a committed change, at the cost of index latency
+// in every write. Changes are not batched or retried in this mode.
+func WithIndexInTransaction() IndexerOption {
+	return func(c *indexerConfig) {
+		c.inTx = true
+	}
+}
+
+// WithIndexErrorHandler sets the callback for failed deliveries after commit, which
+// cannot fail the (already committed) write. Events with Attempts equal to the maximum
+// have been dropped; the others are in the retry queue. The default logs to slog.Default().
+func WithIndexErrorHandler(fn func(ctx context.Context, events []IndexEvent, err error)) IndexerOption {
+	return func(c *indexerConfig) {
+		c.onError = fn
+	}
+}
+
+// Indexer delivers model changes to registered search index handlers.
+// It is safe for concurrent use; deliveries are serialized and keep write order per batch.
+type Indexer struct {
+	cfg indexerConfig
+
+	mu       sync.Mutex
+	handlers map[reflect.Type]indexHandler // Registered handlers by model type
+	pending  []IndexEvent                  // Buffered events awaiting delivery
+	retry    []IndexEvent                  // Failed events awaiting Retry
+
+	deliverMu sync.Mutex // Serializes deliveries
+}
+
+// NewIndexer creates an indexer. Register handlers with RegisterIndex or
+// RegisterBatchIndex and attach it to sessions with WithIndexer.
+//
+// Parameters:
+//   - opts: Options (WithIndexBatchSize, WithIndexMaxAttempts, WithIndexInTransaction,
+//     WithIndexErrorHandler)
+//
+// Example:
+//
+//	ix := sqlc.NewIndexer(sqlc.WithIndexBatchSize(50), sqlc.WithIndexMaxAttempts(5))
+func NewIndexer(opts ...IndexerOption) *Indexer {
+	cfg := indexerConfig{batchSize: 1, maxAttempts: 3}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.onError == nil {
+		cfg.onError = func(ctx context.Context, events []IndexEvent, err error) {
+			slog.Default().LogAttrs(ctx, slog.LevelError, "search index update failed",
+				slog.Int("events", len(events)), slog.String("error", err.Error()))
+		}
+	}
+	return &Indexer{cfg: cfg, handlers: make(map[reflect.Type]indexHandler)}
+}
+
+// WithIndexer attaches ix to the session and its transaction sessions: changes written
+// through repositories of registered models are delivered to ix.
+func WithIndexer(ix *Indexer) SessionOption {
+	return func(s *Session) {
+		s.indexer = ix
+	}
+}
+
+// RegisterIndex registers fn as the index handler of model T, called once per change.
+// Registering a second handler for T replaces the first.
+//
+// Example:
+//
+//	sqlc.RegisterIndex(ix, func(ctx context.Context, op sqlc.ChangeOp, u *models.User) error {
+//	    if op == sqlc.ChangeDelete {
+//	        return search.Delete(ctx, "users", u.ID)
+//	    }
+//	    return search.Put(ctx, "users", u.ID, u)
+//	})
+func RegisterIndex[T any](ix *Indexer, fn func(ctx context.Context, op ChangeOp, model *T) error) {
+	ix.register(reflect.TypeFor[T](), func(ctx context.Context, events []IndexEvent) ([]IndexEvent, error) {
+		var failed []IndexEvent
+		var errs []error
+		for _, e := range events {
+			if err := fn(ctx, e.Op, e.Model.(*T)); err != nil {
+				failed = append(failed, e)
+				errs = append(errs, err)
+			}
+		}
+		return failed, errors.Join(errs...)
+	})
+}
+
+// RegisterBatchIndex registers fn as the index handler of model T, called with up to
+// the batch size of changes at once, e.g. for a bulk API. If fn fails, all its entries
+// are retried. Registering a second handler for T replaces the first.
+//
+// Example:
+//
+//	sqlc.RegisterBatchIndex(ix, func(ctx context.Context, entries []sqlc.IndexEntry[models.User]) error {
+//	    bulk := es.NewBulk()
+//	    for _, e := range entries {
+//	        bulk.Add(e.Op, e.Model)
+//	    }
+//	    return bulk.Do(ctx)
+//	})
+func RegisterBatchIndex[T any](ix *Indexer, fn func(ctx context.Context, entries []IndexEntry[T]) error) {
+	ix.register(reflect.TypeFor[T](), func(ctx context.Context, events []IndexEvent) ([]IndexEvent, error) {
+		entries := make([]IndexEntry[T], len(events))
+		for i, e := range events {
+			entries[i] = IndexEntry[T]{Op: e.Op, Model: e.Model.(*T)}
+		}
+		if err := fn(ctx, entries); err != nil {
+			return events, err
+		}
+		return nil, nil
+	})
+}
+
+func (ix *Indexer) register(typ reflect.Type, h indexHandler) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.handlers[typ] = h
+}
+
+// handles reports whether a handler is registered for typ
+func (ix *Indexer) handles(typ reflect.Type) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	_, ok := ix.handlers[typ]
+	return ok
+}
+
+// Pending returns the number of buffered changes awaiting Flush.
+func (ix *Indexer) Pending() int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.pending)
+}
+
+// Failed returns a copy of the retry queue: changes whose delivery failed and that
+// have not reached the maximum number of attempts.
+func (ix *Indexer) Failed() []IndexEvent {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return append([]IndexEvent(nil), ix.retry...)
+}
+
+// Flush delivers all buffered changes. Failed changes are moved to the retry queue.
+//
+// Returns:
+//   - error: Delivery errors (the changes are kept for Retry unless dropped)
+func (ix *Indexer) Flush(ctx context.Context) error {
+	ix.mu.Lock()
+	events := ix.pending
+	ix.pending = nil
+	ix.mu.Unlock()
+	return ix.deliver(ctx, events)
+}
+
+// Retry delivers the changes of the retry queue again. Changes failing for the
+// maximum number of attempts are dropped and reported to the error handler.
+//
+// Returns:
+//   - error: Delivery errors of this retry
+func (ix *Indexer) Retry(ctx context.Context) error {
+	ix.mu.Lock()
+	events := ix.retry
+	ix.retry = nil
+	ix.mu.Unlock()
+	return ix.deliver(ctx, events)
+}
+
+// enqueue buffers committed changes and flushes once a batch is complete.
+// Delivery failures are reported to the error handler, not returned: the write is committed.
+func (ix *Indexer) enqueue(ctx context.Context, events []IndexEvent) {
+	ix.mu.Lock()
+	ix.pending = append(ix.pending, events...)
+	full := len(ix.pending) >= ix.cfg.batchSize
+	ix.mu.Unlock()
+	if full {
+		_ = ix.Flush(ctx)
+	}
+}
+
+// deliver passes events to their handlers, in order, in batches of consecutive events
+// of the same model type. Failed events are queued for retry or dropped.
+func (ix *Indexer) deliver(ctx context.Context, events []IndexEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ix.deliverMu.Lock()
+	defer ix.deliverMu.Unlock()
+
+	var errs []error
+	for start := 0; start < len(events); {
+		typ := reflect.TypeOf(events[start].Model)
+		end := start + 1
+		for end < len(events) && end-start < ix.cfg.batchSize && reflect.TypeOf(events[end].Model) == typ {
+			end++
+		}
+		if err := ix.deliverBatch(ctx, typ, events[start:end]); err != nil {
+			errs = append(errs, err)
+		}
+		start = end
+	}
+	return errors.Join(errs...)
+}
+
+// deliverBatch passes events of model type typ (*T) to its handler.
+func (ix *Indexer) deliverBatch(ctx context.Context, typ reflect.Type, events []IndexEvent) error {
+	ix.mu.Lock()
+	h := ix.handlers[typ.Elem()]
+	ix.mu.Unlock()
+	if h == nil {
+		return nil
+	}
+
+	failed, err := h(ctx, events)
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("sqlc: search index update of %s failed: %w", events[0].Table, err)
+
+	reported := make([]IndexEvent, 0, len(failed))
+	var retry []IndexEvent
+	for _, e := range failed {
+		e.Attempts++
+		reported = append(reported, e)
+		if e.Attempts < ix.cfg.maxAttempts {
+			retry = append(retry, e)
+		}
+	}
+	ix.mu.Lock()
+	ix.retry = append(ix.retry, retry...)
+	ix.mu.Unlock()
+	ix.cfg.onError(ctx, reported, err)
+	return err
+}
+
+// indexModels records changes of models for the session's indexer: delivered at once
+// with WithIndexInTransaction, after commit inside a transaction, otherwise buffered.
+func indexModels[T any](ctx context.Context, s *Session, op ChangeOp, table string, models ...*T) error {
+	ix := s.indexer
+	if ix == nil || len(models) == 0 || !ix.handles(reflect.TypeFor[T]()) {
+		return nil
+	}
+	events := make([]IndexEvent, len(models))
+	for i, m := range models {
+		events[i] = IndexEvent{Op: op, Table: table, Model: m}
+	}
+
+	if ix.cfg.inTx {
+		ix.mu.Lock()
+		h := ix.handlers[reflect.TypeFor[T]()]
+		ix.mu.Unlock()
+		if _, err := h(ctx, events); err != nil {
+			return fmt.Errorf("sqlc: search index update of %s failed: %w", table, err)
+		}
+		return nil
+	}
+	if s.tx != nil {
+		s.tx.mu.Lock()
+		s.tx.indexEvents = append(s.tx.indexEvents, events...)
+		s.tx.mu.Unlock()
+		return nil
+	}
+	ix.enqueue(ctx, events)
+	return nil
+}
+
+// flushIndexEvents hands the changes of a committed transaction to the indexer.
+func (s *Session) flushIndexEvents(ctx context.Context) {
+	if s.indexer == nil || s.tx == nil {
+		return
+	}
+	s.tx.mu.Lock()
+	events := s.tx.indexEvents
+	s.tx.indexEvents = nil
+	s.tx.mu.Unlock()
+	if len(events) > 0 {
+		s.indexer.enqueue(ctx, events)
+	}
+}
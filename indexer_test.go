@@ -0,0 +1,254 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// indexLog records the changes delivered to an index handler
+type indexLog struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+func (l *indexLog) add(op sqlc.ChangeOp, name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changes = append(l.changes, string(op)+":"+name)
+}
+
+func (l *indexLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.changes...)
+}
+
+// upsertOutcomeDialect is SQLite with an UpsertOutcome standing in for PostgreSQL's
+// xmax test: rows upserted at level 0 count as inserted.
+type upsertOutcomeDialect struct {
+	sqlc.SQLiteDialect
+}
+
+func (upsertOutcomeDialect) UpsertInserted() string { return "(level = 0)" }
+
+func TestIndexer(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*sqlc.Session, *indexLog) {
+		db, _ := setupIntegrationDB(t)
+		t.Cleanup(func() { db.Close() })
+		ix := sqlc.NewIndexer()
+		log := &indexLog{}
+		sqlc.RegisterIndex(ix, func(ctx context.Context, op sqlc.ChangeOp, m *Member) error {
+			log.add(op, m.Name)
+			return nil
+		})
+		return sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithIndexer(ix)), log
+	}
+	newMember := func(name string) *Member {
+		return &Member{Name: name, Email: name + "@test.com", DepartmentID: 1, CreatedAt: time.Now()}
+	}
+
+	t.Run("Autocommit", func(t *testing.T) {
+		session, log := setup(t)
+		repo := sqlc.NewRepository[Member](session)
+
+		m := newMember("alice")
+		if err := repo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		m.Level = 2
+		if err := repo.Update(ctx, m); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if err := repo.DeleteModel(ctx, m); err != nil {
+			t.Fatalf("DeleteModel failed: %v", err)
+		}
+		// Models without a registered handler are not indexed
+		if err := sqlc.NewRepository[Department](session).Create(ctx, &Department{Name: "Sales"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		want := []string{"create:alice", "update:alice", "delete:alice"}
+		if got := log.get(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		session, log := setup(t)
+		repo := sqlc.NewRepository[Member](session)
+
+		// SQLite cannot tell an insert from an update: both are reported as updates
+		m := newMember("erin")
+		if err := repo.Upsert(ctx, m, sqlc.OnConflict(clause.Column{Name: "email"})); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		m.Level = 3
+		if err := repo.Upsert(ctx, m, sqlc.OnConflict(clause.Column{Name: "email"})); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+
+		// Dialects implementing UpsertOutcome report inserted rows as creates
+		db, _ := setupIntegrationDB(t)
+		defer db.Close()
+		ix := sqlc.NewIndexer()
+		sqlc.RegisterIndex(ix, func(ctx context.Context, op sqlc.ChangeOp, m *Member) error {
+			log.add(op, m.Name)
+			return nil
+		})
+		outcomeRepo := sqlc.NewRepository[Member](sqlc.NewSession(db, upsertOutcomeDialect{}, sqlc.WithIndexer(ix)))
+		f := newMember("fay")
+		if err := outcomeRepo.Upsert(ctx, f, sqlc.OnConflict(clause.Column{Name: "email"})); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		f.Level = 4
+		if err := outcomeRepo.Upsert(ctx, f, sqlc.OnConflict(clause.Column{Name: "email"})); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+
+		want := []string{"update:erin", "update:erin", "create:fay", "update:fay"}
+		if got := log.get(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("AfterCommit", func(t *testing.T) {
+		session, log := setup(t)
+
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			repo := sqlc.NewRepository[Member](tx)
+			if err := repo.BatchCreate(ctx, []*Member{newMember("bob"), newMember("carol")}); err != nil {
+				return err
+			}
+			if got := log.get(); len(got) != 0 {
+				t.Errorf("expected no delivery before commit, got %v", got)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		if got, want := log.get(), []string{"create:bob", "create:carol"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		// Rolled back changes are never delivered
+		_ = session.Transaction(ctx, func(tx *sqlc.Session) error {
+			if err := sqlc.NewRepository[Member](tx).Create(ctx, newMember("dave")); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		if got := log.get(); len(got) != 2 {
+			t.Errorf("expected rolled back change to be dropped, got %v", got)
+		}
+	})
+
+	t.Run("BatchFlush", func(t *testing.T) {
+		db, _ := setupIntegrationDB(t)
+		defer db.Close()
+		ix := sqlc.NewIndexer(sqlc.WithIndexBatchSize(3))
+		var batches [][]string
+		sqlc.RegisterBatchIndex(ix, func(ctx context.Context, entries []sqlc.IndexEntry[Member]) error {
+			var names []string
+			for _, e := range entries {
+				names = append(names, string(e.Op)+":"+e.Model.Name)
+			}
+			batches = append(batches, names)
+			return nil
+		})
+		repo := sqlc.NewRepository[Member](sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithIndexer(ix)))
+
+		for _, name := range []string{"a", "b", "c", "d"} {
+			if err := repo.Create(ctx, newMember(name)); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+		}
+		if ix.Pending() != 1 || len(batches) != 1 {
+			t.Fatalf("expected one full batch and 1 pending change, got %v and %d pending", batches, ix.Pending())
+		}
+		if err := ix.Flush(ctx); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		want := [][]string{{"create:a", "create:b", "create:c"}, {"create:d"}}
+		if !reflect.DeepEqual(batches, want) {
+			t.Errorf("got batches %v, want %v", batches, want)
+		}
+	})
+
+	t.Run("RetryQueue", func(t *testing.T) {
+		db, _ := setupIntegrationDB(t)
+		defer db.Close()
+		var reported []sqlc.IndexEvent
+		ix := sqlc.NewIndexer(
+			sqlc.WithIndexMaxAttempts(2),
+			sqlc.WithIndexErrorHandler(func(ctx context.Context, events []sqlc.IndexEvent, err error) {
+				reported = append(reported, events...)
+			}),
+		)
+		down := true
+		log := &indexLog{}
+		sqlc.RegisterIndex(ix, func(ctx context.Context, op sqlc.ChangeOp, m *Member) error {
+			if down || m.Name == "poison" {
+				return errors.New("index unavailable")
+			}
+			log.add(op, m.Name)
+			return nil
+		})
+		repo := sqlc.NewRepository[Member](sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithIndexer(ix)))
+
+		// The write succeeds although the index is down
+		for _, name := range []string{"erin", "poison"} {
+			if err := repo.Create(ctx, newMember(name)); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+		}
+		if failed := ix.Failed(); len(failed) != 2 || failed[0].Attempts != 1 || failed[0].Table != "members" {
+			t.Fatalf("expected 2 queued changes after 1 attempt, got %+v", failed)
+		}
+
+		down = false
+		if err := ix.Retry(ctx); err == nil {
+			t.Error("expected the poison change to fail again")
+		}
+		if got, want := log.get(), []string{"create:erin"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		// poison reached the maximum attempts and was dropped
+		if failed := ix.Failed(); len(failed) != 0 {
+			t.Errorf("expected empty retry queue, got %+v", failed)
+		}
+		if len(reported) != 3 || reported[2].Attempts != 2 || reported[2].Model.(*Member).Name != "poison" {
+			t.Errorf("unexpected reported failures: %+v", reported)
+		}
+	})
+
+	t.Run("InTransaction", func(t *testing.T) {
+		db, _ := setupIntegrationDB(t)
+		defer db.Close()
+		ix := sqlc.NewIndexer(sqlc.WithIndexInTransaction())
+		sqlc.RegisterIndex(ix, func(ctx context.Context, op sqlc.ChangeOp, m *Member) error {
+			return errors.New("index unavailable")
+		})
+		session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithIndexer(ix))
+
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			return sqlc.NewRepository[Member](tx).Create(ctx, newMember("frank"))
+		})
+		if err == nil {
+			t.Fatal("expected the index failure to fail the transaction")
+		}
+		count, err := sqlc.NewRepository[Member](session).Query().Count(ctx)
+		if err != nil || count != 0 {
+			t.Errorf("expected the insert to be rolled back, got %d, %v", count, err)
+		}
+	})
+}
@@ -3,6 +3,9 @@ package sqlc_test
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +13,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/arllen133/sqlc"
 	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/clause/funcs"
 	"github.com/arllen133/sqlc/field"
 )
 
@@ -45,10 +49,22 @@ type Member struct {
 	Level        int       `db:"level"`
 	DepartmentID int       `db:"department_id"`
 	CreatedAt    time.Time `db:"created_at"`
+
+	// Relation fields (not in DB, loaded via Preload)
+	Department *Department `db:"-"`
 }
 
 func (Member) TableName() string { return "members" }
 
+// MemberDepartment defines the BelongsTo relation: Member -> Department
+var MemberDepartment = sqlc.BelongsTo[Member, Department, int64](
+	clause.Column{Name: "department_id"},                   // Foreign key on Member
+	clause.Column{Name: "id"},                              // Primary key on Department
+	func(m *Member, d *Department) { m.Department = d },    // Setter
+	func(m *Member) int64 { return int64(m.DepartmentID) }, // Get foreign key
+	func(d *Department) int64 { return d.ID },              // Get owner key
+)
+
 // -- Schemas (Inline for tests) --
 
 // DeptSchema
@@ -212,17 +228,25 @@ func TestAdvancedIntegration(t *testing.T) {
 			t.Fatalf("BatchCreate Departments failed: %v", err)
 		}
 
-		// Fetch depts to get IDs (BatchCreate doesn't return IDs easily for all drivers)
-		// Assuming sequential IDs 1, 2 for SQLite
+		// IDs are backfilled via RETURNING
+		if depts[0].ID != 1 || depts[1].ID != 2 {
+			t.Fatalf("expected department IDs 1, 2, got %d, %d", depts[0].ID, depts[1].ID)
+		}
 
 		members := []*Member{
-			{Name: "Alice", Email: "alice@test.com", Level: 1, DepartmentID: 1, CreatedAt: time.Now()},
-			{Name: "Bob", Email: "bob@test.com", Level: 2, DepartmentID: 1, CreatedAt: time.Now()},
-			{Name: "Charlie", Email: "charlie@test.com", Level: 1, DepartmentID: 2, CreatedAt: time.Now()},
+			{Name: "Alice", Email: "alice@test.com", Level: 1, DepartmentID: int(depts[0].ID), CreatedAt: time.Now()},
+			{Name: "Bob", Email: "bob@test.com", Level: 2, DepartmentID: int(depts[0].ID), CreatedAt: time.Now()},
+			{Name: "Charlie", Email: "charlie@test.com", Level: 1, DepartmentID: int(depts[1].ID), CreatedAt: time.Now()},
 		}
-		if err := memberRepo.BatchCreate(ctx, members); err != nil {
+		// Match the returned IDs on the unique email column
+		if err := memberRepo.BatchCreate(ctx, members, sqlc.WithReturningKey(clause.Column{Name: "email"})); err != nil {
 			t.Fatalf("BatchCreate Members failed: %v", err)
 		}
+		for i, m := range members {
+			if m.ID != int64(i+1) {
+				t.Errorf("expected member %s to have ID %d, got %d", m.Name, i+1, m.ID)
+			}
+		}
 
 		count, _ := memberRepo.Query().Count(ctx)
 		if count != 3 {
@@ -257,6 +281,26 @@ func TestAdvancedIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("FullAndCrossJoin", func(t *testing.T) {
+		full, err := memberRepo.Query().
+			FullJoin(DeptSchema{}, sqlc.On(clause.Column{Name: "department_id"}, clause.Column{Name: "id"})).
+			Count(ctx)
+		if err != nil {
+			t.Fatalf("FULL JOIN failed: %v", err)
+		}
+		if full != 3 {
+			t.Errorf("Expected 3 rows from FULL JOIN, got %d", full)
+		}
+
+		cross, err := memberRepo.Query().CrossJoin(DeptSchema{}).Count(ctx)
+		if err != nil {
+			t.Fatalf("CROSS JOIN failed: %v", err)
+		}
+		if cross != 6 {
+			t.Errorf("Expected 3x2 rows from CROSS JOIN, got %d", cross)
+		}
+	})
+
 	// 3. Aggregates & GroupBy
 	t.Run("Aggregates", func(t *testing.T) {
 		// Max Level
@@ -267,7 +311,7 @@ func TestAdvancedIntegration(t *testing.T) {
 		// 3. Count
 		count, err := memberRepo.Query().
 			GroupBy(clause.Column{Name: "department_id"}).
-			Having(clause.Expr{SQL: "COUNT(*) >= 2"}).
+			Having(field.CountAll().Gte(2)).
 			Count(ctx)
 
 		if err == nil {
@@ -363,7 +407,7 @@ func TestAdvancedIntegration(t *testing.T) {
 		// If we Join, we might have ambiguity.
 		// A robust extensibility example should probably handle column selection too.
 
-		q.Select(
+		q = q.Select(
 			clause.Column{Name: "members.id"},
 			clause.Column{Name: "members.name"},
 			clause.Column{Name: "members.email"},
@@ -545,6 +589,69 @@ func TestAdvancedIntegration(t *testing.T) {
 		}
 	})
 
+	// 10b. Preload into map
+	t.Run("HasManyPreloadIntoMap", func(t *testing.T) {
+		res, err := sqlc.FindWithMap(ctx, deptRepo.Query(), sqlc.Preload(DepartmentHasMembers).IntoMap())
+		if err != nil {
+			t.Fatalf("FindWithMap failed: %v", err)
+		}
+		if len(res.Items) < 2 {
+			t.Fatalf("Expected at least 2 departments, got %d", len(res.Items))
+		}
+
+		total := 0
+		for _, d := range res.Items {
+			if d.Members != nil {
+				t.Errorf("map mode must not set parent relation fields, %s has %d members", d.Name, len(d.Members))
+			}
+			for _, m := range res.Related[d.ID] {
+				if int64(m.DepartmentID) != d.ID {
+					t.Errorf("member %s grouped under department %d", m.Name, d.ID)
+				}
+			}
+			total += len(res.Related[d.ID])
+		}
+		if total == 0 {
+			t.Error("Expected preloaded members in map, got 0")
+		}
+	})
+
+	// 10c. Hand-written preload function
+	t.Run("PreloadFunc", func(t *testing.T) {
+		var calls, loaded int
+		_, err := deptRepo.Query().
+			WithPreload(sqlc.PreloadFunc[Department](func(ctx context.Context, session *sqlc.Session, depts []*Department) error {
+				calls++
+				loaded = len(depts)
+				return nil
+			})).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Query with preload failed: %v", err)
+		}
+		if calls != 1 || loaded < 2 {
+			t.Errorf("expected one call with all departments, got %d calls with %d", calls, loaded)
+		}
+	})
+
+	// 10d. BelongsTo Preload
+	t.Run("BelongsToPreload", func(t *testing.T) {
+		members, err := memberRepo.Query().
+			WithPreload(sqlc.Preload(MemberDepartment)).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Query with preload failed: %v", err)
+		}
+		if len(members) == 0 {
+			t.Fatal("Expected members, got 0")
+		}
+		for _, m := range members {
+			if m.Department == nil || m.Department.ID != int64(m.DepartmentID) {
+				t.Errorf("member %s: expected department %d, got %+v", m.Name, m.DepartmentID, m.Department)
+			}
+		}
+	})
+
 	// 11. Distinct Query
 	t.Run("DistinctQuery", func(t *testing.T) {
 		// Create members with duplicate department_ids
@@ -665,155 +772,1191 @@ func TestBasicQueryFeatures(t *testing.T) {
 	})
 }
 
-func TestTransactions(t *testing.T) {
+// levelColumn is a Columnar declared outside the module
+type levelColumn string
+
+func (c levelColumn) ColumnName() string { return string(c) }
+
+func TestAggregates(t *testing.T) {
 	db, session := setupIntegrationDB(t)
 	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
 	ctx := context.Background()
 
-	t.Run("SuccessfulTransaction", func(t *testing.T) {
-		err := session.Transaction(ctx, func(txSession *sqlc.Session) error {
-			txRepo := sqlc.NewRepository[Member](txSession)
-			// Create 2 members
-			if err := txRepo.Create(ctx, &Member{Name: "Tx1", Email: "tx1@test.com"}); err != nil {
-				return err
-			}
-			if err := txRepo.Create(ctx, &Member{Name: "Tx2", Email: "tx2@test.com"}); err != nil {
-				return err
-			}
-			return nil
+	// Setup data: levels 1..6 spread over 3 departments
+	for i := 1; i <= 6; i++ {
+		_ = memberRepo.Create(ctx, &Member{
+			Name:         "Agg" + string(rune(i+64)),
+			Email:        "agg" + string(rune(i+64)) + "@test.com",
+			Level:        i,
+			DepartmentID: (i-1)%3 + 1,
+			CreatedAt:    time.Now(),
 		})
+	}
+
+	level := field.Number[int]{}.WithColumn("level")
+	deptID := field.Number[int]{}.WithColumn("department_id")
+
+	t.Run("Sum", func(t *testing.T) {
+		sum, err := memberRepo.Query().Limit(1).Sum(ctx, level)
 		if err != nil {
-			t.Fatalf("Transaction failed: %v", err)
+			t.Fatalf("Sum failed: %v", err)
 		}
+		if sum != 21 {
+			t.Errorf("Expected 21, got %v", sum)
+		}
+	})
 
-		// Verify
-		count, _ := sqlc.NewRepository[Member](session).Query().
-			Where(field.String{}.WithColumn("name").Like("Tx%")).
-			Count(ctx)
-		if count != 2 {
-			t.Errorf("Expected 2 members from tx, got %d", count)
+	t.Run("Avg", func(t *testing.T) {
+		avg, err := memberRepo.Query().Where(level.Gt(3)).Avg(ctx, level)
+		if err != nil {
+			t.Fatalf("Avg failed: %v", err)
+		}
+		if avg != 5 {
+			t.Errorf("Expected 5, got %v", avg)
 		}
 	})
 
-	t.Run("RollbackTransaction", func(t *testing.T) {
-		err := session.Transaction(ctx, func(txSession *sqlc.Session) error {
-			txRepo := sqlc.NewRepository[Member](txSession)
-			if err := txRepo.Create(ctx, &Member{Name: "Rollback", Email: "rb@test.com"}); err != nil {
-				return err
-			}
-			return sql.ErrConnDone // Force error
-		})
+	t.Run("MinMax", func(t *testing.T) {
+		minVal, err := memberRepo.Query().Min(ctx, level)
+		if err != nil {
+			t.Fatalf("Min failed: %v", err)
+		}
+		maxVal, err := memberRepo.Query().Max(ctx, level)
+		if err != nil {
+			t.Fatalf("Max failed: %v", err)
+		}
+		if minVal != int64(1) || maxVal != int64(6) {
+			t.Errorf("Expected 1..6, got %v..%v", minVal, maxVal)
+		}
+	})
 
-		if err == nil {
-			t.Error("Expected error")
+	t.Run("CountDistinct", func(t *testing.T) {
+		n, err := memberRepo.Query().Offset(2).CountDistinct(ctx, deptID)
+		if err != nil {
+			t.Fatalf("CountDistinct failed: %v", err)
+		}
+		if n != 3 {
+			t.Errorf("Expected 3 departments, got %d", n)
 		}
+	})
 
-		// Verify not created
-		count, _ := sqlc.NewRepository[Member](session).Query().
-			Where(field.String{}.WithColumn("name").Eq("Rollback")).
-			Count(ctx)
-		if count != 0 {
-			t.Errorf("Expected 0 members, got %d", count)
+	t.Run("ExpressionHelpers", func(t *testing.T) {
+		var rows []struct {
+			Name  string `db:"name"`
+			Label string `db:"label"`
+		}
+		err := memberRepo.Query().
+			Select(field.String{}.WithColumn("name")).
+			SelectExpr(clause.Cast(level, "text").As("label")).
+			Where(clause.Coalesce(clause.NullIf(level, 1), 0).Gt(4)).
+			OrderByExpr(clause.Cast(level, "bigint").Desc()).
+			Scan(ctx, &rows)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(rows) != 2 || rows[0].Label != "6" || rows[1].Label != "5" {
+			t.Errorf("unexpected rows: %+v", rows)
 		}
 	})
-}
 
-// HookTestModel
-type HookMember struct {
-	ID        int64     `db:"id"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
-}
+	t.Run("PortableFuncs", func(t *testing.T) {
+		name := field.String{}.WithColumn("name")
+		email := field.String{}.WithColumn("email")
+		var rows []struct {
+			Label string `db:"label"`
+			Day   string `db:"day"`
+		}
+		err := memberRepo.Query().
+			Select(
+				funcs.Concat(funcs.Lower(name), "-", funcs.Upper(funcs.Substring(email, 1, 3))).As("label"),
+				funcs.DateTrunc("day", field.Time{}.WithColumn("created_at")).As("day"),
+			).
+			Where(funcs.Lower(name).Eq("agga")).
+			Where(funcs.Length(email).Gt(5)).
+			Scan(ctx, &rows)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(rows) != 1 || rows[0].Label != "agga-AGG" || !strings.HasSuffix(rows[0].Day, " 00:00:00") {
+			t.Errorf("unexpected rows: %+v", rows)
+		}
+	})
 
-func (HookMember) TableName() string { return "hook_members" }
+	t.Run("SelectAggregates", func(t *testing.T) {
+		var rows []struct {
+			DepartmentID int     `db:"department_id"`
+			Members      int64   `db:"members"`
+			Total        int64   `db:"total"`
+			MaxLevel     int64   `db:"max_level"`
+			AvgLevel     float64 `db:"avg_level"`
+		}
+		err := memberRepo.Query().
+			Select(
+				deptID,
+				clause.As(clause.CountAll(), "members"),
+				clause.Sum(level).As("total"),
+				clause.As(clause.Max(level), "max_level"),
+				clause.Avg(level).As("avg_level"),
+			).
+			GroupBy(deptID).
+			OrderBy(deptID.Asc()).
+			Scan(ctx, &rows)
+		if err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		// Department 1 holds levels 1 and 4
+		if len(rows) != 3 || rows[0].DepartmentID != 1 || rows[0].Members != 2 ||
+			rows[0].Total != 5 || rows[0].MaxLevel != 4 || rows[0].AvgLevel != 2.5 {
+			t.Errorf("unexpected rows: %+v", rows)
+		}
+	})
 
-// Hooks
-func (h *HookMember) BeforeCreate(ctx context.Context) error {
-	if h.CreatedAt.IsZero() {
-		h.CreatedAt = time.Now()
-	}
-	return nil
-}
+	t.Run("SelectUnsupportedItem", func(t *testing.T) {
+		var rows []map[string]any
+		if err := memberRepo.Query().Select(42).Scan(ctx, &rows); err == nil {
+			t.Error("expected error for unsupported select item")
+		}
+	})
 
-func (h *HookMember) AfterCreate(ctx context.Context) error {
-	h.Name = h.Name + "_hooked"
-	return nil
-}
+	t.Run("SelectColumnarSlice", func(t *testing.T) {
+		// Columns built elsewhere, including custom Columnar types
+		cols := []clause.Columnar{deptID, levelColumn("level")}
+		items := make([]any, len(cols))
+		for i, col := range cols {
+			items[i] = col
+		}
+		var rows []struct {
+			DepartmentID int `db:"department_id"`
+			Level        int `db:"level"`
+		}
+		if err := memberRepo.Query().Select(items...).Scan(ctx, &rows); err != nil || len(rows) == 0 {
+			t.Errorf("expected rows from Columnar items, got %d (err %v)", len(rows), err)
+		}
+	})
 
-type HookMemberSchema struct{}
+	t.Run("TopNPerGroup", func(t *testing.T) {
+		ranked := memberRepo.Query().SelectExpr(clause.As(
+			clause.Expr{SQL: "ROW_NUMBER() OVER (PARTITION BY department_id ORDER BY level DESC)"}, "rn",
+		))
+		top, err := memberRepo.Query().
+			FromSubquery(ranked, "ranked").
+			Where(clause.Expr{SQL: "rn <= ?", Vars: []any{1}}).
+			OrderBy(deptID.Asc()).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		// Highest level per department: 4, 5, 6
+		if len(top) != 3 || top[0].Level != 4 || top[1].Level != 5 || top[2].Level != 6 {
+			t.Errorf("unexpected top members: %+v", top)
+		}
+	})
 
-func (HookMemberSchema) TableName() string       { return "hook_members" }
-func (HookMemberSchema) SelectColumns() []string { return []string{"id", "name", "created_at"} }
-func (HookMemberSchema) InsertRow(m *HookMember) ([]string, []any) {
-	return []string{"name", "created_at"}, []any{m.Name, m.CreatedAt}
-}
-func (HookMemberSchema) PK(m *HookMember) sqlc.PK {
-	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: m.ID}
+	t.Run("Exists", func(t *testing.T) {
+		found, err := memberRepo.Query().Where(level.Eq(3)).Limit(1).Offset(5).Exists(ctx)
+		if err != nil {
+			t.Fatalf("Exists failed: %v", err)
+		}
+		if !found {
+			t.Error("Expected matching member to exist")
+		}
+		found, err = memberRepo.Query().Where(level.Gt(100)).Exists(ctx)
+		if err != nil {
+			t.Fatalf("Exists failed: %v", err)
+		}
+		if found {
+			t.Error("Expected no matching member")
+		}
+	})
+
+	t.Run("EmptySet", func(t *testing.T) {
+		sum, err := memberRepo.Query().Where(level.Gt(100)).Sum(ctx, level)
+		if err != nil || sum != 0 {
+			t.Errorf("Expected 0 for empty set, got %v (err %v)", sum, err)
+		}
+	})
 }
-func (HookMemberSchema) SetPK(m *HookMember, val int64)         { m.ID = val }
-func (HookMemberSchema) AutoIncrement() bool                    { return true }
-func (HookMemberSchema) SoftDeleteColumn() string               { return "" }
-func (HookMemberSchema) SoftDeleteValue() any                   { return nil }
-func (HookMemberSchema) SetDeletedAt(m *HookMember)             {}
-func (HookMemberSchema) UpdateMap(m *HookMember) map[string]any { return nil } // Not used in this test
 
-func TestLifecycleHooks(t *testing.T) {
-	sqlc.RegisterSchema(HookMemberSchema{})
+func TestScopedWrites(t *testing.T) {
 	db, session := setupIntegrationDB(t)
 	defer db.Close()
 
-	// Create table for hooks
-	_, err := db.Exec("CREATE TABLE IF NOT EXISTS hook_members (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME)")
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	m := &Member{Name: "Scoped", Email: "scoped@test.com", Level: 3, DepartmentID: 1, CreatedAt: time.Now()}
+	if err := memberRepo.Create(ctx, m); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	level := field.Number[int]{}.WithColumn("level")
+	name := field.String{}.WithColumn("name")
+
+	// Scope does not match: no row changes
+	if err := memberRepo.Where(level.Gt(5)).UpdateColumns(ctx, m.ID, name.Set("Changed")); err != nil {
+		t.Fatalf("scoped UpdateColumns failed: %v", err)
+	}
+	if err := memberRepo.Where(level.Gt(5)).Delete(ctx, m.ID); err != nil {
+		t.Fatalf("scoped Delete failed: %v", err)
+	}
+	got, err := memberRepo.FindOne(ctx, m.ID)
 	if err != nil {
-		t.Fatalf("Failed to create hook_members table: %v", err)
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if got.Name != "Scoped" {
+		t.Errorf("scope should have prevented update, got name %q", got.Name)
 	}
 
-	repo := sqlc.NewRepository[HookMember](session)
-	ctx := context.Background()
+	// Scope matches
+	if err := memberRepo.Where(level.Eq(3)).Delete(ctx, m.ID); err != nil {
+		t.Fatalf("scoped Delete failed: %v", err)
+	}
+	if _, err := memberRepo.FindOne(ctx, m.ID); err == nil {
+		t.Error("expected member to be deleted")
+	}
+}
 
-	t.Run("Hooks", func(t *testing.T) {
-		m := &HookMember{Name: "HookTester"}
-		// BeforeCreate should set CreatedAt
-		// AfterCreate should append _hooked
+func TestUpdateSelectOmit(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
 
-		err := repo.Create(ctx, m)
-		if err != nil {
-			t.Fatalf("Create failed: %v", err)
-		}
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
 
-		if m.CreatedAt.IsZero() {
-			t.Error("BeforeCreate hook did not run (CreatedAt is zero)")
-		}
+	name := field.String{}.WithColumn("name")
+	email := field.String{}.WithColumn("email")
+	level := field.Number[int]{}.WithColumn("level")
+	createdAt := field.Time{}.WithColumn("created_at")
+	id := field.Number[int64]{}.WithColumn("id")
+
+	tests := []struct {
+		name    string
+		repo    func(r *sqlc.Repository[Member]) *sqlc.Repository[Member]
+		want    Member // expected name, email (without subtest prefix) and level after the update
+		wantErr bool
+	}{
+		{"All", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r },
+			Member{Name: "New", Email: "new@test.com", Level: 9}, false},
+		{"Select", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r.Select(name) },
+			Member{Name: "New", Email: "old@test.com", Level: 1}, false},
+		{"SelectChained", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r.Select(name).Select(level) },
+			Member{Name: "New", Email: "old@test.com", Level: 9}, false},
+		{"Omit", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r.Omit(email, createdAt) },
+			Member{Name: "New", Email: "old@test.com", Level: 9}, false},
+		{"SelectOmit", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r.Select(name, email).Omit(email) },
+			Member{Name: "New", Email: "old@test.com", Level: 1}, false},
+		{"NotUpdatable", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r.Select(id) }, Member{}, true},
+		{"Empty", func(r *sqlc.Repository[Member]) *sqlc.Repository[Member] { return r.Select(name).Omit(name) }, Member{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			// Emails are unique: make them per subtest
+			m := &Member{Name: "Old", Email: tt.name + "-old@test.com", Level: 1, DepartmentID: 1, CreatedAt: created}
+			if err := memberRepo.Create(ctx, m); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
 
-		if !strings.HasSuffix(m.Name, "_hooked") {
-			t.Errorf("AfterCreate hook did not run, name is %s", m.Name)
-		}
-	})
-}
+			m.Name, m.Email, m.Level = "New", tt.name+"-new@test.com", 9
+			err := tt.repo(memberRepo).Update(ctx, m)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
 
-// Tag Model (String PK)
-type Tag struct {
-	ID   string `db:"id,primaryKey"`
-	Name string `db:"name"`
+			got, err := memberRepo.FindOne(ctx, m.ID)
+			if err != nil {
+				t.Fatalf("FindOne failed: %v", err)
+			}
+			if wantEmail := tt.name + "-" + tt.want.Email; got.Name != tt.want.Name || got.Email != wantEmail || got.Level != tt.want.Level {
+				t.Errorf("got (%s, %s, %d), want (%s, %s, %d)",
+					got.Name, got.Email, got.Level, tt.want.Name, wantEmail, tt.want.Level)
+			}
+		})
+	}
 }
 
-func (Tag) TableName() string { return "tags" }
+func TestMutationResults(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
 
-type TagSchema struct{}
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
 
-func (TagSchema) TableName() string       { return "tags" }
-func (TagSchema) SelectColumns() []string { return []string{"id", "name"} }
-func (TagSchema) InsertRow(m *Tag) ([]string, []any) {
-	return []string{"id", "name"}, []any{m.ID, m.Name}
-}
-func (TagSchema) PK(m *Tag) sqlc.PK {
-	var val any
-	if m != nil {
-		val = m.ID
+	m := &Member{Name: "Counted", Email: "counted@test.com", Level: 3, DepartmentID: 1, CreatedAt: time.Now()}
+	if err := memberRepo.Create(ctx, m); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	level := field.Number[int]{}.WithColumn("level")
+	name := field.String{}.WithColumn("name")
+	missing := &Member{ID: m.ID + 100, Name: "Ghost"}
+
+	tests := []struct {
+		name string
+		run  func() (int64, error)
+		want int64
+	}{
+		{"UpdateMatched", func() (int64, error) { m.Name = "Renamed"; return memberRepo.UpdateResult(ctx, m) }, 1},
+		{"UpdateMissing", func() (int64, error) { return memberRepo.UpdateResult(ctx, missing) }, 0},
+		{"UpdateColumnsScopeMiss", func() (int64, error) {
+			return memberRepo.Where(level.Gt(5)).UpdateColumnsResult(ctx, m.ID, name.Set("Changed"))
+		}, 0},
+		{"UpdateColumnsMatched", func() (int64, error) { return memberRepo.UpdateColumnsResult(ctx, m.ID, name.Set("Changed")) }, 1},
+		{"DeleteModelMissing", func() (int64, error) { return memberRepo.DeleteModelResult(ctx, missing) }, 0},
+		{"DeleteMatched", func() (int64, error) { return memberRepo.DeleteResult(ctx, m.ID) }, 1},
+		{"DeleteRepeated", func() (int64, error) { return memberRepo.DeleteResult(ctx, m.ID) }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.run()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("expected %d rows affected, got %d", tt.want, n)
+			}
+		})
 	}
-	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
 }
-func (TagSchema) SetPK(m *Tag, val int64)         {} // String PK, no auto-increment
+
+func TestIncrementDecrement(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	m := &Member{Name: "Counter", Email: "counter@test.com", Level: 3, DepartmentID: 1, CreatedAt: time.Now()}
+	if err := memberRepo.Create(ctx, m); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	level := field.Number[int]{}.WithColumn("level")
+	name := field.String{}.WithColumn("name")
+
+	tests := []struct {
+		name string
+		run  func() error
+		want int
+	}{
+		{"Increment", func() error { return memberRepo.Increment(ctx, m.ID, level, 5) }, 8},
+		{"Decrement", func() error { return memberRepo.Decrement(ctx, m.ID, level, 2) }, 6},
+		{"ScopeMiss", func() error { return memberRepo.Where(level.Gt(10)).Increment(ctx, m.ID, level, 1) }, 6},
+		{"FieldIncr", func() error { return memberRepo.UpdateColumns(ctx, m.ID, level.Incr(4), name.Set("Bumped")) }, 10},
+		{"FieldDecr", func() error { return memberRepo.UpdateColumns(ctx, m.ID, level.Decr(1)) }, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := memberRepo.FindOne(ctx, m.ID)
+			if err != nil {
+				t.Fatalf("FindOne failed: %v", err)
+			}
+			if got.Level != tt.want {
+				t.Errorf("expected level %d, got %d", tt.want, got.Level)
+			}
+		})
+	}
+}
+
+func TestChunkStream(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	var members []*Member
+	for i := 0; i < 7; i++ {
+		members = append(members, &Member{
+			Name:         fmt.Sprintf("Stream%d", i),
+			Email:        fmt.Sprintf("stream%d@test.com", i),
+			Level:        i % 2,
+			DepartmentID: 1,
+			CreatedAt:    time.Now(),
+		})
+	}
+	if err := memberRepo.BatchCreate(ctx, members); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+
+	level := field.Number[int]{}.WithColumn("level")
+	id := field.Number[int64]{}.WithColumn("id")
+
+	t.Run("Batches", func(t *testing.T) {
+		var sizes []int
+		var names []string
+		err := memberRepo.Query().OrderBy(id.Asc()).ChunkStream(ctx, 3, func(batch []*Member) error {
+			sizes = append(sizes, len(batch))
+			for _, m := range batch {
+				names = append(names, m.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ChunkStream failed: %v", err)
+		}
+		if fmt.Sprint(sizes) != "[3 3 1]" {
+			t.Errorf("expected batch sizes [3 3 1], got %v", sizes)
+		}
+		if len(names) != 7 || names[0] != "Stream0" || names[6] != "Stream6" {
+			t.Errorf("unexpected rows: %v", names)
+		}
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		total := 0
+		err := memberRepo.Query().Where(level.Eq(1)).ChunkStream(ctx, 10, func(batch []*Member) error {
+			total += len(batch)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ChunkStream failed: %v", err)
+		}
+		if total != 3 {
+			t.Errorf("expected 3 rows, got %d", total)
+		}
+	})
+
+	t.Run("CallbackErrorStops", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := memberRepo.Query().ChunkStream(ctx, 2, func(batch []*Member) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("expected callback error, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected 1 callback, got %d", calls)
+		}
+	})
+
+	t.Run("InvalidSize", func(t *testing.T) {
+		if err := memberRepo.Query().ChunkStream(ctx, 0, func([]*Member) error { return nil }); err == nil {
+			t.Error("expected error for non-positive size")
+		}
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		err := memberRepo.Query().ChunkStream(cctx, 1, func([]*Member) error {
+			cancel()
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestRowsIterator(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		m := &Member{
+			Name:         fmt.Sprintf("Iter%d", i),
+			Email:        fmt.Sprintf("iter%d@test.com", i),
+			Level:        i,
+			DepartmentID: 1,
+			CreatedAt:    time.Now(),
+		}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	level := field.Number[int]{}.WithColumn("level")
+	id := field.Number[int64]{}.WithColumn("id")
+
+	t.Run("All", func(t *testing.T) {
+		var names []string
+		for m, err := range memberRepo.Query().Where(level.Gte(2)).OrderBy(id.Asc()).Rows(ctx) {
+			if err != nil {
+				t.Fatalf("iteration failed: %v", err)
+			}
+			names = append(names, m.Name)
+		}
+		if fmt.Sprint(names) != "[Iter2 Iter3 Iter4]" {
+			t.Errorf("unexpected rows: %v", names)
+		}
+	})
+
+	t.Run("BreakEarly", func(t *testing.T) {
+		n := 0
+		for _, err := range memberRepo.Query().Rows(ctx) {
+			if err != nil {
+				t.Fatalf("iteration failed: %v", err)
+			}
+			n++
+			if n == 2 {
+				break
+			}
+		}
+		if n != 2 {
+			t.Errorf("expected 2 iterations, got %d", n)
+		}
+
+		// Cursor must be released after break
+		if _, err := memberRepo.Query().Count(ctx); err != nil {
+			t.Errorf("query after break failed: %v", err)
+		}
+	})
+
+	t.Run("ContextCanceled", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var lastErr error
+		n := 0
+		for _, err := range memberRepo.Query().Rows(cctx) {
+			if err != nil {
+				lastErr = err
+				break
+			}
+			n++
+			cancel()
+		}
+		if !errors.Is(lastErr, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", lastErr)
+		}
+		if n != 1 {
+			t.Errorf("expected iteration to stop after cancel, got %d records", n)
+		}
+	})
+
+	t.Run("BuildError", func(t *testing.T) {
+		n := 0
+		for m, err := range memberRepo.Query().Where(clause.Case{}).Rows(ctx) {
+			n++
+			if err == nil || m != nil {
+				t.Errorf("expected build error, got %v, %v", m, err)
+			}
+		}
+		if n != 1 {
+			t.Errorf("expected a single error yield, got %d", n)
+		}
+	})
+}
+
+func TestFindChan(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		m := &Member{
+			Name:         fmt.Sprintf("Chan%d", i),
+			Email:        fmt.Sprintf("chan%d@test.com", i),
+			Level:        i,
+			DepartmentID: 1,
+			CreatedAt:    time.Now(),
+		}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	level := field.Number[int]{}.WithColumn("level")
+	id := field.Number[int64]{}.WithColumn("id")
+
+	t.Run("All", func(t *testing.T) {
+		for _, buf := range []int{0, 2, 10} {
+			members, errc := memberRepo.Query().Where(level.Gte(1)).OrderBy(id.Asc()).FindChan(ctx, buf)
+			var names []string
+			for m := range members {
+				names = append(names, m.Name)
+			}
+			if err := <-errc; err != nil {
+				t.Fatalf("buf %d: FindChan failed: %v", buf, err)
+			}
+			if fmt.Sprint(names) != "[Chan1 Chan2 Chan3 Chan4]" {
+				t.Errorf("buf %d: unexpected records: %v", buf, names)
+			}
+		}
+	})
+
+	t.Run("ConsumerStopsEarly", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		members, errc := memberRepo.Query().FindChan(cctx, 0)
+		<-members
+		cancel()
+		for range members {
+			// Drain records sent before the producer saw the cancellation
+		}
+		if err := <-errc; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+
+		// Cursor must be released after cancellation
+		if _, err := memberRepo.Query().Count(ctx); err != nil {
+			t.Errorf("query after cancel failed: %v", err)
+		}
+	})
+
+	t.Run("QueryError", func(t *testing.T) {
+		members, errc := memberRepo.Query().Where(clause.Case{}).FindChan(ctx, 1)
+		for m := range members {
+			t.Errorf("unexpected record %+v", m)
+		}
+		if err := <-errc; err == nil {
+			t.Error("expected build error")
+		}
+	})
+}
+
+func TestResultStages(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	var members []*Member
+	for i := 0; i < 6; i++ {
+		members = append(members, &Member{
+			Name:         fmt.Sprintf("Stage%d", i),
+			Email:        fmt.Sprintf("stage%d@test.com", i),
+			Level:        i,
+			DepartmentID: 1,
+			CreatedAt:    time.Now(),
+		})
+	}
+	if err := memberRepo.BatchCreate(ctx, members); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+
+	id := field.Number[int64]{}.WithColumn("id")
+	base := memberRepo.Query().OrderBy(id.Asc()).
+		Filter(func(m *Member) bool { return m.Level%2 == 0 }).
+		Map(func(m *Member) error {
+			m.Email = "" // strip
+			return nil
+		})
+
+	t.Run("Find", func(t *testing.T) {
+		got, err := base.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("expected 3 filtered members, got %d", len(got))
+		}
+		for _, m := range got {
+			if m.Level%2 != 0 || m.Email != "" {
+				t.Errorf("stages not applied: %+v", m)
+			}
+		}
+	})
+
+	t.Run("ChunkKeepsPaging", func(t *testing.T) {
+		total := 0
+		err := base.Chunk(ctx, 2, func(batch []*Member) error {
+			total += len(batch)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if total != 3 {
+			t.Errorf("expected 3 members across chunks, got %d", total)
+		}
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		n := 0
+		for m, err := range base.Rows(ctx) {
+			if err != nil {
+				t.Fatalf("Rows failed: %v", err)
+			}
+			if m.Email != "" {
+				t.Errorf("Map not applied: %+v", m)
+			}
+			n++
+		}
+		if n != 3 {
+			t.Errorf("expected 3 rows, got %d", n)
+		}
+	})
+
+	t.Run("MapError", func(t *testing.T) {
+		errStrip := errors.New("strip failed")
+		_, err := memberRepo.Query().Map(func(*Member) error { return errStrip }).Find(ctx)
+		if !errors.Is(err, errStrip) {
+			t.Errorf("expected stage error, got %v", err)
+		}
+	})
+}
+
+func TestTransactions(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	t.Run("SuccessfulTransaction", func(t *testing.T) {
+		err := session.Transaction(ctx, func(txSession *sqlc.Session) error {
+			txRepo := sqlc.NewRepository[Member](txSession)
+			// Create 2 members
+			if err := txRepo.Create(ctx, &Member{Name: "Tx1", Email: "tx1@test.com"}); err != nil {
+				return err
+			}
+			if err := txRepo.Create(ctx, &Member{Name: "Tx2", Email: "tx2@test.com"}); err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+
+		// Verify
+		count, _ := sqlc.NewRepository[Member](session).Query().
+			Where(field.String{}.WithColumn("name").Like("Tx%")).
+			Count(ctx)
+		if count != 2 {
+			t.Errorf("Expected 2 members from tx, got %d", count)
+		}
+	})
+
+	t.Run("RollbackTransaction", func(t *testing.T) {
+		err := session.Transaction(ctx, func(txSession *sqlc.Session) error {
+			txRepo := sqlc.NewRepository[Member](txSession)
+			if err := txRepo.Create(ctx, &Member{Name: "Rollback", Email: "rb@test.com"}); err != nil {
+				return err
+			}
+			return sql.ErrConnDone // Force error
+		})
+
+		if err == nil {
+			t.Error("Expected error")
+		}
+
+		// Verify not created
+		count, _ := sqlc.NewRepository[Member](session).Query().
+			Where(field.String{}.WithColumn("name").Eq("Rollback")).
+			Count(ctx)
+		if count != 0 {
+			t.Errorf("Expected 0 members, got %d", count)
+		}
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		serializable := &sql.TxOptions{Isolation: sql.LevelSerializable}
+		err := session.TransactionWithOptions(ctx, serializable, func(txSession *sqlc.Session) error {
+			if err := sqlc.NewRepository[Member](txSession).Create(ctx, &Member{Name: "Serial", Email: "serial@test.com"}); err != nil {
+				return err
+			}
+			// Joining the running transaction is allowed, changing its options is not
+			if err := txSession.TransactionWithOptions(ctx, nil, func(*sqlc.Session) error { return nil }); err != nil {
+				return err
+			}
+			err := txSession.TransactionWithOptions(ctx, &sql.TxOptions{ReadOnly: true}, func(*sqlc.Session) error { return nil })
+			if err == nil || !strings.Contains(err.Error(), "read-only") {
+				t.Errorf("expected nested read-only transaction to fail, got %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("TransactionWithOptions failed: %v", err)
+		}
+		count, _ := sqlc.NewRepository[Member](session).Query().
+			Where(field.String{}.WithColumn("name").Eq("Serial")).
+			Count(ctx)
+		if count != 1 {
+			t.Errorf("Expected 1 committed member, got %d", count)
+		}
+
+		txSession, err := session.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		if err := txSession.Rollback(); err != nil {
+			t.Errorf("Rollback failed: %v", err)
+		}
+	})
+
+	t.Run("BeforeCommitHooks", func(t *testing.T) {
+		name := field.String{}.WithColumn("name")
+		errInvariant := errors.New("invariant violated")
+
+		// Hook sees the transaction's writes and rejects the commit
+		var seen int64
+		err := session.Transaction(ctx, func(txSession *sqlc.Session) error {
+			txRepo := sqlc.NewRepository[Member](txSession)
+			if err := txSession.BeforeCommit(func(ctx context.Context) error {
+				n, err := txRepo.Query().Where(name.Like("Hook%")).Count(ctx)
+				if err != nil {
+					return err
+				}
+				seen = n
+				if n > 1 {
+					return errInvariant
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if err := txRepo.Create(ctx, &Member{Name: "Hook1", Email: "hook1@test.com"}); err != nil {
+				return err
+			}
+			// Nested Transaction shares the hooks of the outer transaction
+			return txSession.Transaction(ctx, func(inner *sqlc.Session) error {
+				return sqlc.NewRepository[Member](inner).Create(ctx, &Member{Name: "Hook2", Email: "hook2@test.com"})
+			})
+		})
+		if !errors.Is(err, errInvariant) {
+			t.Fatalf("expected invariant error, got %v", err)
+		}
+		if seen != 2 {
+			t.Errorf("hook should see uncommitted writes, saw %d", seen)
+		}
+		count, _ := sqlc.NewRepository[Member](session).Query().Where(name.Like("Hook%")).Count(ctx)
+		if count != 0 {
+			t.Errorf("expected rejected transaction to roll back, got %d members", count)
+		}
+
+		// Passing hooks let the transaction commit
+		calls := 0
+		err = session.Transaction(ctx, func(txSession *sqlc.Session) error {
+			for range 2 {
+				if err := txSession.BeforeCommit(func(ctx context.Context) error {
+					calls++
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			return sqlc.NewRepository[Member](txSession).Create(ctx, &Member{Name: "HookOK", Email: "hookok@test.com"})
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("expected 2 hook calls, got %d", calls)
+		}
+
+		// Hooks receive the context passed to CommitContext
+		type hookKey struct{}
+		txSession, err := session.Begin(ctx)
+		if err != nil {
+			t.Fatalf("Begin failed: %v", err)
+		}
+		var got any
+		if err := txSession.BeforeCommit(func(ctx context.Context) error {
+			got = ctx.Value(hookKey{})
+			return nil
+		}); err != nil {
+			t.Fatalf("BeforeCommit failed: %v", err)
+		}
+		if err := txSession.CommitContext(context.WithValue(ctx, hookKey{}, "commit")); err != nil {
+			t.Fatalf("CommitContext failed: %v", err)
+		}
+		if got != "commit" {
+			t.Errorf("expected hook to see the commit context, got %v", got)
+		}
+
+		if err := session.BeforeCommit(func(context.Context) error { return nil }); !errors.Is(err, sql.ErrTxDone) {
+			t.Errorf("expected sql.ErrTxDone outside a transaction, got %v", err)
+		}
+	})
+
+	t.Run("PanicRollsBackAndRepanics", func(t *testing.T) {
+		func() {
+			defer func() {
+				if p := recover(); p != "boom" {
+					t.Errorf("expected original panic value, got %v", p)
+				}
+			}()
+			_ = session.Transaction(ctx, func(txSession *sqlc.Session) error {
+				txRepo := sqlc.NewRepository[Member](txSession)
+				if err := txRepo.Create(ctx, &Member{Name: "Panic", Email: "panic@test.com"}); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Errorf("connection leaked after panic: %d in use", inUse)
+		}
+		count, err := sqlc.NewRepository[Member](session).Query().
+			Where(field.String{}.WithColumn("name").Eq("Panic")).
+			Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("Expected panicking transaction to roll back, got %d members", count)
+		}
+	})
+
+	t.Run("PanicRecoveredAsError", func(t *testing.T) {
+		recovering := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithPanicRecovery())
+		errBoom := errors.New("boom")
+
+		err := recovering.Transaction(ctx, func(txSession *sqlc.Session) error {
+			txRepo := sqlc.NewRepository[Member](txSession)
+			if err := txRepo.Create(ctx, &Member{Name: "Recovered", Email: "recovered@test.com"}); err != nil {
+				return err
+			}
+			panic(errBoom)
+		})
+
+		var pe *sqlc.PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("expected *sqlc.PanicError, got %v", err)
+		}
+		if !errors.Is(err, errBoom) {
+			t.Errorf("expected error to wrap panic value, got %v", err)
+		}
+		if len(pe.Stack) == 0 {
+			t.Error("expected stack trace to be captured")
+		}
+		if inUse := db.Stats().InUse; inUse != 0 {
+			t.Errorf("connection leaked after panic: %d in use", inUse)
+		}
+		count, err := sqlc.NewRepository[Member](session).Query().
+			Where(field.String{}.WithColumn("name").Eq("Recovered")).
+			Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("Expected panicking transaction to roll back, got %d members", count)
+		}
+	})
+}
+
+// HookTestModel
+type HookMember struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (HookMember) TableName() string { return "hook_members" }
+
+// Hooks
+func (h *HookMember) BeforeCreate(ctx context.Context) error {
+	if h.CreatedAt.IsZero() {
+		h.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+func (h *HookMember) AfterCreate(ctx context.Context) error {
+	h.Name = h.Name + "_hooked"
+	return nil
+}
+
+type HookMemberSchema struct{}
+
+func (HookMemberSchema) TableName() string       { return "hook_members" }
+func (HookMemberSchema) SelectColumns() []string { return []string{"id", "name", "created_at"} }
+func (HookMemberSchema) InsertRow(m *HookMember) ([]string, []any) {
+	return []string{"name", "created_at"}, []any{m.Name, m.CreatedAt}
+}
+func (HookMemberSchema) PK(m *HookMember) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (HookMemberSchema) SetPK(m *HookMember, val int64)         { m.ID = val }
+func (HookMemberSchema) AutoIncrement() bool                    { return true }
+func (HookMemberSchema) SoftDeleteColumn() string               { return "" }
+func (HookMemberSchema) SoftDeleteValue() any                   { return nil }
+func (HookMemberSchema) SetDeletedAt(m *HookMember)             {}
+func (HookMemberSchema) UpdateMap(m *HookMember) map[string]any { return nil } // Not used in this test
+
+func TestLifecycleHooks(t *testing.T) {
+	sqlc.RegisterSchema(HookMemberSchema{})
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	// Create table for hooks
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS hook_members (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME)")
+	if err != nil {
+		t.Fatalf("Failed to create hook_members table: %v", err)
+	}
+
+	repo := sqlc.NewRepository[HookMember](session)
+	ctx := context.Background()
+
+	t.Run("Hooks", func(t *testing.T) {
+		m := &HookMember{Name: "HookTester"}
+		// BeforeCreate should set CreatedAt
+		// AfterCreate should append _hooked
+
+		err := repo.Create(ctx, m)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		if m.CreatedAt.IsZero() {
+			t.Error("BeforeCreate hook did not run (CreatedAt is zero)")
+		}
+
+		if !strings.HasSuffix(m.Name, "_hooked") {
+			t.Errorf("AfterCreate hook did not run, name is %s", m.Name)
+		}
+	})
+
+	t.Run("SkipHooks", func(t *testing.T) {
+		for name, create := range map[string]func(m *HookMember) error{
+			"Repository": func(m *HookMember) error { return repo.SkipHooks().Create(ctx, m) },
+			"Context":    func(m *HookMember) error { return repo.Create(sqlc.WithoutHooks(ctx), m) },
+			"Batch": func(m *HookMember) error {
+				return repo.SkipHooks().BatchCreate(ctx, []*HookMember{m})
+			},
+		} {
+			m := &HookMember{Name: "Imported"}
+			if err := create(m); err != nil {
+				t.Fatalf("%s: Create failed: %v", name, err)
+			}
+			if !m.CreatedAt.IsZero() || m.Name != "Imported" || m.ID == 0 {
+				t.Errorf("%s: expected no hooks to run, got %+v", name, m)
+			}
+		}
+
+		// The original repository still runs hooks
+		m := &HookMember{Name: "Regular"}
+		if err := repo.Create(ctx, m); err != nil || m.Name != "Regular_hooked" {
+			t.Errorf("expected hooks on the original repository, got %s, %v", m.Name, err)
+		}
+	})
+}
+
+// AuditedNote records each creation in note_audits through the session of the write
+type AuditedNote struct {
+	ID    int64  `db:"id,primaryKey,autoIncrement"`
+	Title string `db:"title"`
+	Seq   int64  `db:"-"` // Notes existing after the insert, counted by AfterCreate
+}
+
+func (n *AuditedNote) AfterCreate(ctx context.Context) error {
+	count, err := sqlc.RepositoryFromContext[AuditedNote](ctx).Query().Count(ctx)
+	if err != nil {
+		return err
+	}
+	n.Seq = count
+	_, err = sqlc.SessionFromContext(ctx).Exec(ctx, "INSERT INTO note_audits (note_id) VALUES (?)", n.ID)
+	return err
+}
+
+type AuditedNoteSchema struct{}
+
+func (AuditedNoteSchema) TableName() string       { return "audited_notes" }
+func (AuditedNoteSchema) SelectColumns() []string { return []string{"id", "title"} }
+func (AuditedNoteSchema) InsertRow(m *AuditedNote) ([]string, []any) {
+	return []string{"title"}, []any{m.Title}
+}
+func (AuditedNoteSchema) PK(m *AuditedNote) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (AuditedNoteSchema) SetPK(m *AuditedNote, val int64)         { m.ID = val }
+func (AuditedNoteSchema) AutoIncrement() bool                     { return true }
+func (AuditedNoteSchema) SoftDeleteColumn() string                { return "" }
+func (AuditedNoteSchema) SoftDeleteValue() any                    { return nil }
+func (AuditedNoteSchema) SetDeletedAt(m *AuditedNote)             {}
+func (AuditedNoteSchema) UpdateMap(m *AuditedNote) map[string]any { return nil }
+
+func TestHookSession(t *testing.T) {
+	sqlc.RegisterSchema(AuditedNoteSchema{})
+	db, session := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	for _, stmt := range []string{
+		"CREATE TABLE audited_notes (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT)",
+		"CREATE TABLE note_audits (note_id INTEGER)",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+	}
+	audits := func() int {
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM note_audits").Scan(&n); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		return n
+	}
+	repo := sqlc.NewRepository[AuditedNote](session)
+
+	if sqlc.SessionFromContext(ctx) != nil || sqlc.RepositoryFromContext[AuditedNote](ctx) != nil {
+		t.Error("expected no session outside hooks")
+	}
+
+	// The hook sees the row of the transaction and writes in it: a rollback discards both
+	errRollback := errors.New("rollback")
+	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+		note := &AuditedNote{Title: "draft"}
+		if err := repo.WithSession(tx).Create(ctx, note); err != nil {
+			return err
+		}
+		if note.Seq != 1 {
+			t.Errorf("expected the hook to count the uncommitted note, got %d", note.Seq)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected the rollback error, got %v", err)
+	}
+	if n := audits(); n != 0 {
+		t.Errorf("expected the audit row rolled back, got %d", n)
+	}
+
+	if err := repo.Create(ctx, &AuditedNote{Title: "final"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if n := audits(); n != 1 {
+		t.Errorf("expected 1 audit row, got %d", n)
+	}
+}
+
+// Tag Model (String PK)
+type Tag struct {
+	ID   string `db:"id,primaryKey"`
+	Name string `db:"name"`
+}
+
+func (Tag) TableName() string { return "tags" }
+
+type TagSchema struct{}
+
+func (TagSchema) TableName() string       { return "tags" }
+func (TagSchema) SelectColumns() []string { return []string{"id", "name"} }
+func (TagSchema) InsertRow(m *Tag) ([]string, []any) {
+	return []string{"id", "name"}, []any{m.ID, m.Name}
+}
+func (TagSchema) PK(m *Tag) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (TagSchema) SetPK(m *Tag, val int64)         {} // String PK, no auto-increment
 func (TagSchema) AutoIncrement() bool             { return false }
 func (TagSchema) SoftDeleteColumn() string        { return "" }
 func (TagSchema) SoftDeleteValue() any            { return nil }
@@ -909,3 +2052,509 @@ func TestPreloadStringKey(t *testing.T) {
 		t.Errorf("Expected 2 preloaded items for string key 'golang', got %d. (The bug would return 0 or all items if normalization failed)", len(loadedItems))
 	}
 }
+
+func TestTupleInQuery(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+
+	for i, pair := range [][2]int{{1, 1}, {1, 2}, {2, 1}, {2, 2}} {
+		m := &Member{Name: fmt.Sprintf("m%d", i), Email: fmt.Sprintf("tuple%d@test.com", i), DepartmentID: pair[0], Level: pair[1], CreatedAt: time.Now()}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	pairs := field.Columns(field.Number[int]{}.WithColumn("department_id"), field.Number[int]{}.WithColumn("level"))
+
+	tests := []struct {
+		name string
+		expr clause.Expression
+		want []string
+	}{
+		{"In", pairs.In([]any{1, 2}, []any{2, 1}), []string{"m1", "m2"}},
+		{"NotIn", pairs.NotIn([]any{1, 2}, []any{2, 1}), []string{"m0", "m3"}},
+		{"Empty", pairs.In(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members, err := memberRepo.Query().Where(tt.expr).OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).Find(ctx)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			var got []string
+			for _, m := range members {
+				got = append(got, m.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFindOneForUpdate(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	memberRepo := sqlc.NewRepository[Member](session)
+	m := &Member{Name: "Locked", Email: "locked@test.com", Level: 1, DepartmentID: 1, CreatedAt: time.Now()}
+	if err := memberRepo.Create(ctx, m); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	t.Run("OutsideTransaction", func(t *testing.T) {
+		if _, err := memberRepo.FindOneForUpdate(ctx, m.ID); !errors.Is(err, sql.ErrTxDone) {
+			t.Errorf("expected sql.ErrTxDone, got %v", err)
+		}
+	})
+
+	t.Run("InTransaction", func(t *testing.T) {
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			locked, err := memberRepo.WithSession(tx).FindOneForUpdate(ctx, m.ID)
+			if err != nil {
+				return err
+			}
+			locked.Level++
+			return memberRepo.WithSession(tx).Update(ctx, locked)
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		got, err := memberRepo.FindOne(ctx, m.ID)
+		if err != nil {
+			t.Fatalf("FindOne failed: %v", err)
+		}
+		if got.Level != 2 {
+			t.Errorf("expected level 2, got %d", got.Level)
+		}
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		tx, err := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun().Begin(ctx)
+		if err != nil {
+			t.Fatalf("Begin failed: %v", err)
+		}
+		if _, err := memberRepo.WithSession(tx).FindOneForUpdate(ctx, 7); !errors.Is(err, sqlc.ErrNotFound) {
+			t.Errorf("expected ErrNotFound from dry run, got %v", err)
+		}
+		stmts := tx.Recorder().Statements()
+		last := stmts[len(stmts)-1]
+		if want := "FROM members WHERE id = $1 ORDER BY members.id LIMIT 1 FOR UPDATE"; !strings.HasSuffix(last.SQL, want) {
+			t.Errorf("expected SQL ending in %q, got %q", want, last.SQL)
+		}
+	})
+}
+
+func TestBatchCreateChunking(t *testing.T) {
+	ctx := context.Background()
+	newMembers := func(n int, prefix string) []*Member {
+		members := make([]*Member, n)
+		for i := range members {
+			members[i] = &Member{Name: prefix, Email: fmt.Sprintf("%s%d@test.com", prefix, i), DepartmentID: 1, CreatedAt: time.Now()}
+		}
+		return members
+	}
+
+	t.Run("Statements", func(t *testing.T) {
+		tests := []struct {
+			name string
+			opts []sqlc.BatchOption
+			want []string // Operations of the recorded statements
+		}{
+			// 5 columns per row: 999 / 5 = 199 rows per statement on SQLite
+			{"PlaceholderLimit", nil, []string{"exec", "exec", "exec"}},
+			{"BatchSize", []sqlc.BatchOption{sqlc.WithBatchSize(150)}, []string{"exec", "exec", "exec"}},
+			{"BatchSizeBelowLimit", []sqlc.BatchOption{sqlc.WithBatchSize(100)}, []string{"exec", "exec", "exec", "exec", "exec"}},
+			// A batch size above the placeholder limit is capped to 199 rows
+			{"Transaction", []sqlc.BatchOption{sqlc.WithBatchSize(250), sqlc.WithBatchTransaction()}, []string{"begin", "exec", "exec", "exec", "commit"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				dry := sqlc.NewSession(nil, sqlc.SQLite).DryRun()
+				if err := sqlc.NewRepository[Member](dry).BatchCreate(ctx, newMembers(450, "m"), tt.opts...); err != nil {
+					t.Fatalf("BatchCreate failed: %v", err)
+				}
+				var got []string
+				for _, stmt := range dry.Recorder().Statements() {
+					got = append(got, stmt.Operation)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("expected %v, got %v", tt.want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("BackfillsAllChunks", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		memberRepo := sqlc.NewRepository[Member](session)
+
+		members := newMembers(450, "bulk")
+		if err := memberRepo.BatchCreate(ctx, members); err != nil {
+			t.Fatalf("BatchCreate failed: %v", err)
+		}
+		for i, m := range members {
+			if m.ID != int64(i+1) {
+				t.Fatalf("expected member %d to have ID %d, got %d", i, i+1, m.ID)
+			}
+		}
+	})
+
+	t.Run("TransactionRollsBack", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		memberRepo := sqlc.NewRepository[Member](session)
+
+		// The last chunk violates the unique email constraint
+		members := newMembers(30, "tx")
+		members[29].Email = members[0].Email
+		err := memberRepo.BatchCreate(ctx, members, sqlc.WithBatchSize(10), sqlc.WithBatchTransaction())
+		if err == nil {
+			t.Fatal("expected unique constraint error")
+		}
+		if n, _ := memberRepo.Query().Count(ctx); n != 0 {
+			t.Errorf("expected rollback of all chunks, got %d rows", n)
+		}
+	})
+}
+
+// MemberCard is a partial of Member without email and created_at
+type MemberCard struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Level int    `db:"level"`
+}
+
+func (MemberCard) SelectColumns() []string { return []string{"id", "name", "level"} }
+
+func TestFindPartial(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	for i := 1; i <= 3; i++ {
+		m := &Member{Name: fmt.Sprintf("P%d", i), Email: fmt.Sprintf("p%d@test.com", i), Level: i, DepartmentID: 1, CreatedAt: time.Now()}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	level := field.Number[int]{}.WithColumn("level")
+
+	t.Run("Find", func(t *testing.T) {
+		cards, err := sqlc.FindPartial[MemberCard](ctx, memberRepo.Query().Where(level.Gt(1)).OrderBy(level.Desc()))
+		if err != nil {
+			t.Fatalf("FindPartial failed: %v", err)
+		}
+		if len(cards) != 2 || cards[0].Name != "P3" || cards[0].Level != 3 || cards[1].Name != "P2" {
+			t.Errorf("unexpected cards: %+v", cards)
+		}
+	})
+
+	t.Run("First", func(t *testing.T) {
+		card, err := sqlc.FirstPartial[MemberCard](ctx, memberRepo.Query().Select(level))
+		if err != nil {
+			t.Fatalf("FirstPartial failed: %v", err)
+		}
+		if card.Name != "P1" || card.ID == 0 {
+			t.Errorf("unexpected card: %+v", card)
+		}
+
+		_, err = sqlc.FirstPartial[MemberCard](ctx, memberRepo.Query().Where(level.Gt(10)))
+		if !errors.Is(err, sqlc.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+		q := sqlc.NewRepository[Member](dry).Query().
+			JoinTable("departments", clause.Expr{SQL: "departments.id = members.department_id"}).
+			Where(level.Gt(1))
+		if _, err := sqlc.FindPartial[MemberCard](ctx, q); err != nil {
+			t.Fatalf("FindPartial failed: %v", err)
+		}
+		stmts := dry.Recorder().Statements()
+		want := "SELECT members.id, members.name, members.level FROM members JOIN departments ON departments.id = members.department_id WHERE level > $1"
+		if len(stmts) != 1 || stmts[0].SQL != want {
+			t.Errorf("SQL mismatch:\ngot:  %v\nwant: %s", stmts, want)
+		}
+	})
+}
+
+func TestCreateOrIgnore(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+	email := field.String{}.WithColumn("email")
+
+	first := &Member{Name: "First", Email: "dup@test.com", Level: 1, DepartmentID: 1, CreatedAt: time.Now()}
+	inserted, err := memberRepo.CreateOrIgnore(ctx, first, email)
+	if err != nil || !inserted {
+		t.Fatalf("expected insert, got %v (err %v)", inserted, err)
+	}
+	if first.ID == 0 {
+		t.Error("expected ID to be backfilled")
+	}
+
+	// Same email: skipped without error, with and without a conflict target
+	for _, target := range [][]clause.Columnar{{email}, nil} {
+		dup := &Member{Name: "Second", Email: "dup@test.com", Level: 2, DepartmentID: 1, CreatedAt: time.Now()}
+		inserted, err = memberRepo.CreateOrIgnore(ctx, dup, target...)
+		if err != nil || inserted {
+			t.Fatalf("expected conflict to be ignored, got %v (err %v)", inserted, err)
+		}
+		if dup.ID != 0 {
+			t.Errorf("expected no ID for ignored row, got %d", dup.ID)
+		}
+	}
+	got, err := memberRepo.FindOne(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if got.Name != "First" {
+		t.Errorf("existing row was modified: %q", got.Name)
+	}
+
+	// SQL per dialect
+	tests := []struct {
+		dialect sqlc.Dialect
+		want    string
+	}{
+		{sqlc.MySQL, "INSERT IGNORE INTO members (name,email,level,department_id,created_at) VALUES (?,?,?,?,?)"},
+		{sqlc.PostgreSQL, "INSERT INTO members (name,email,level,department_id,created_at) VALUES ($1,$2,$3,$4,$5) ON CONFLICT (email) DO NOTHING"},
+		{sqlc.SQLite, "INSERT INTO members (name,email,level,department_id,created_at) VALUES (?,?,?,?,?) ON CONFLICT (email) DO NOTHING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			dry := sqlc.NewSession(nil, tt.dialect).DryRun()
+			inserted, err := sqlc.NewRepository[Member](dry).CreateOrIgnore(ctx, &Member{Name: "Dry", Email: "dry@test.com"}, email)
+			if err != nil {
+				t.Fatalf("CreateOrIgnore failed: %v", err)
+			}
+			if inserted {
+				t.Error("dry run should not report an insert")
+			}
+			stmts := dry.Recorder().Statements()
+			if len(stmts) != 1 || stmts[0].SQL != tt.want {
+				t.Errorf("expected %q, got %+v", tt.want, stmts)
+			}
+		})
+	}
+}
+
+func TestTouch(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	ctx := context.Background()
+	createdAt := field.Time{}.WithColumn("created_at")
+
+	old := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &Member{Name: "Touched", Email: "touched@test.com", Level: 1, DepartmentID: 1, CreatedAt: old}
+	if err := memberRepo.Create(ctx, m); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := memberRepo.Touch(ctx, m.ID, createdAt); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+	got, err := memberRepo.FindOne(ctx, m.ID)
+	if err != nil {
+		t.Fatalf("FindOne failed: %v", err)
+	}
+	if time.Since(got.CreatedAt) > time.Hour {
+		t.Errorf("expected created_at to be bumped to now, got %v", got.CreatedAt)
+	}
+	if err := memberRepo.Touch(ctx, m.ID); err == nil {
+		t.Error("expected error without columns")
+	}
+
+	tests := []struct {
+		dialect sqlc.Dialect
+		want    string
+	}{
+		{sqlc.MySQL, "UPDATE members SET created_at = NOW() WHERE id = ?"},
+		{sqlc.PostgreSQL, "UPDATE members SET created_at = NOW() WHERE id = $1"},
+		{sqlc.SQLite, "UPDATE members SET created_at = CURRENT_TIMESTAMP WHERE id = ?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect.Name(), func(t *testing.T) {
+			dry := sqlc.NewSession(nil, tt.dialect).DryRun()
+			if err := sqlc.NewRepository[Member](dry).Touch(ctx, 1, createdAt); err != nil {
+				t.Fatalf("Touch failed: %v", err)
+			}
+			if stmts := dry.Recorder().Statements(); len(stmts) != 1 || stmts[0].SQL != tt.want {
+				t.Errorf("expected %q, got %+v", tt.want, stmts)
+			}
+		})
+	}
+}
+
+func TestBelongsToPreloadZeroKeys(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithQueryCapture(64))
+	deptRepo := sqlc.NewRepository[Department](session)
+	memberRepo := sqlc.NewRepository[Member](session)
+
+	eng := &Department{Name: "Engineering"}
+	if err := deptRepo.Create(ctx, eng); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	for _, m := range []*Member{
+		{Name: "Alice", Email: "alice@test.com", DepartmentID: int(eng.ID), CreatedAt: time.Now()},
+		{Name: "Bob", Email: "bob@test.com", DepartmentID: int(eng.ID), CreatedAt: time.Now()},
+		{Name: "Carol", Email: "carol@test.com", CreatedAt: time.Now()},
+	} {
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	t.Run("SharedOwner", func(t *testing.T) {
+		members, err := memberRepo.Query().
+			WithPreload(sqlc.Preload(MemberDepartment)).
+			OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(members) != 3 {
+			t.Fatalf("expected 3 members, got %d", len(members))
+		}
+		if members[0].Department == nil || members[0].Department != members[1].Department {
+			t.Errorf("expected Alice and Bob to share the loaded department, got %+v, %+v", members[0].Department, members[1].Department)
+		}
+		if members[2].Department != nil {
+			t.Errorf("member without department: expected nil, got %+v", members[2].Department)
+		}
+		last := session.RecentQueries(1)[0]
+		if !strings.Contains(last.SQL, "FROM departments") || !reflect.DeepEqual(last.Args, []any{eng.ID}) {
+			t.Errorf("expected departments loaded by the non-zero key only, got %s %v", last.SQL, last.Args)
+		}
+	})
+
+	t.Run("AllKeysZero", func(t *testing.T) {
+		before := len(session.RecentQueries(0))
+		members, err := memberRepo.Query().
+			Where(clause.Eq{Column: clause.Column{Name: "department_id"}, Value: 0}).
+			WithPreload(sqlc.Preload(MemberDepartment)).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(members) != 1 || members[0].Department != nil {
+			t.Fatalf("expected 1 member without department, got %+v", members)
+		}
+		if after := len(session.RecentQueries(0)); after != before+1 {
+			t.Errorf("expected only the members query, got %d queries", after-before)
+		}
+	})
+}
+
+func TestNestedPreload(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithQueryCapture(64))
+	deptRepo := sqlc.NewRepository[Department](session)
+	memberRepo := sqlc.NewRepository[Member](session)
+
+	depts := []*Department{{Name: "Engineering"}, {Name: "Sales"}}
+	if err := deptRepo.BatchCreate(ctx, depts); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+	for i, name := range []string{"alice", "bob", "carol"} {
+		m := &Member{Name: name, Email: name + "@test.com", DepartmentID: int(depts[i%2].ID), CreatedAt: time.Now()}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// queries runs fn and returns the number of statements it executed
+	queries := func(fn func()) int {
+		before := len(session.RecentQueries(0))
+		fn()
+		return len(session.RecentQueries(0)) - before
+	}
+
+	t.Run("TwoLevels", func(t *testing.T) {
+		var got []*Department
+		n := queries(func() {
+			var err error
+			got, err = deptRepo.Query().
+				WithPreload(sqlc.Preload(DepartmentHasMembers).Then(MemberDepartment)).
+				Find(ctx)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+		})
+		if n != 3 {
+			t.Errorf("expected 3 queries, got %d", n)
+		}
+		for _, d := range got {
+			for _, m := range d.Members {
+				if m.Department == nil || m.Department.ID != d.ID {
+					t.Errorf("member %s: expected department %d, got %+v", m.Name, d.ID, m.Department)
+				}
+			}
+		}
+	})
+
+	t.Run("ThreeLevels", func(t *testing.T) {
+		var got []*Member
+		n := queries(func() {
+			var err error
+			got, err = memberRepo.Query().
+				Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: "alice"}).
+				WithPreload(sqlc.Preload(MemberDepartment).Then(
+					sqlc.Preload(DepartmentHasMembers).Then(MemberDepartment),
+				)).
+				Find(ctx)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+		})
+		if n != 4 {
+			t.Errorf("expected 4 queries, got %d", n)
+		}
+		if len(got) != 1 || got[0].Department == nil {
+			t.Fatalf("expected alice with her department, got %+v", got)
+		}
+		colleagues := got[0].Department.Members
+		if len(colleagues) != 2 {
+			t.Fatalf("expected 2 members in alice's department, got %d", len(colleagues))
+		}
+		for _, m := range colleagues {
+			if m.Department == nil || m.Department.Name != "Engineering" {
+				t.Errorf("member %s: expected Engineering at the third level, got %+v", m.Name, m.Department)
+			}
+		}
+	})
+
+	t.Run("ThenDoesNotModifyReceiver", func(t *testing.T) {
+		base := sqlc.Preload(DepartmentHasMembers)
+		_ = base.Then(MemberDepartment)
+		got, err := deptRepo.Query().WithPreload(base).Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		for _, d := range got {
+			for _, m := range d.Members {
+				if m.Department != nil {
+					t.Errorf("member %s: nested relation loaded by an unrelated preload", m.Name)
+				}
+			}
+		}
+	})
+}
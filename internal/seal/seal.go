@@ -0,0 +1,9 @@
+// Package seal provides the token of the module's sealed interfaces.
+//
+// A method taking a Token can only be declared inside this module, since other modules
+// cannot import this package; interfaces with such a method (clause.SelectItem) are
+// therefore implemented by the module's own types only.
+package seal
+
+// Token is the parameter type of sealing methods.
+type Token struct{}
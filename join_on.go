@@ -6,6 +6,8 @@
 //   - INNER JOIN: Inner join, returns only matching records
 //   - LEFT JOIN: Left join, returns all records from left table and matching records from right table
 //   - RIGHT JOIN: Right join, returns all records from right table and matching records from left table
+//   - FULL JOIN: Full outer join, returns all records from both tables (PostgreSQL, SQLite)
+//   - CROSS JOIN: Cartesian product of both tables
 //
 // Usage example:
 //
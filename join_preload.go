@@ -0,0 +1,269 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements JOIN-based eager loading of HasOne and BelongsTo relations.
+//
+// Preload loads related models with a second query per relation. For HasOne and
+// BelongsTo relations, PreloadJoin instead adds a LEFT JOIN to the main query and
+// selects the related columns under prefixed aliases, so models and their related
+// model are loaded in one round trip:
+//
+//	SELECT posts.id, posts.title, ..., sqlc_j1.id AS sqlc_j1__id, sqlc_j1.name AS sqlc_j1__name
+//	FROM posts LEFT JOIN users AS sqlc_j1 ON sqlc_j1.id = posts.user_id
+//
+// Each row is split into the model and the related model; rows without a match (NULL
+// join columns) leave the relation field unchanged.
+//
+// Usage example:
+//
+//	posts, err := postRepo.Query().
+//	    WithPreload(sqlc.PreloadJoin(generated.Post_Author)).
+//	    Where(generated.Post.Status.WithTable("posts").Eq("published")).
+//	    Find(ctx)
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// JoinPreload is a preload of a HasOne or BelongsTo relation through a LEFT JOIN of the
+// main query, created by PreloadJoin().
+type JoinPreload[P, C any, K comparable] struct {
+	rel Relation[P, C, K]
+}
+
+// joinedPreload is a preload that Find loads through a LEFT JOIN of the main query.
+// Other execution paths (query templates) fall back to its load method.
+type joinedPreload[P any] interface {
+	preloadExecutor[P]
+
+	// join returns the LEFT JOIN clause joining the related table as alias,
+	// and the related columns to select
+	join(parentTable, alias string) (join string, args []any, columns []string, err error)
+
+	// scanner returns the row scanner of the related columns selected by join
+	scanner(mapper *reflectx.Mapper, columns []string) (joinRowScanner[P], error)
+}
+
+// joinRowScanner prepares the scan of one row: dests receive the related columns,
+// and assign sets the related model scanned into them on the parent.
+type joinRowScanner[P any] func() (dests []any, assign func(parent *P))
+
+// PreloadJoin creates a preload that loads a HasOne or BelongsTo relation in the main
+// query through a LEFT JOIN, instead of a second query.
+//
+// Parameters:
+//   - rel: HasOne or BelongsTo relationship
+//
+// Returns:
+//   - JoinPreload[P, C, K]: Preload to pass to QueryBuilder.WithPreload()
+//
+// Example:
+//
+//	// One query instead of two
+//	users, err := userRepo.Query().
+//	    WithPreload(sqlc.PreloadJoin(generated.User_Profile)).
+//	    Find(ctx)
+//
+// Note:
+//   - HasMany and ManyToMany relations fail the query; use Preload() for them
+//   - The query selects from several tables: conditions on columns present in both
+//     tables must be table-qualified (e.g. generated.User.ID.WithTable("users"))
+//   - A HasOne relation matching several rows repeats the parent once per match
+//   - Soft-deleted related models are not joined
+//   - Only Find and the methods built on it (First, Take, FindOne, ...) join; templates
+//     load the relation with a second query like Preload()
+func PreloadJoin[P, C any, K comparable](rel Relation[P, C, K]) JoinPreload[P, C, K] {
+	return JoinPreload[P, C, K]{rel: rel}
+}
+
+// load implements preloadExecutor for execution paths without JOIN support.
+func (j JoinPreload[P, C, K]) load(ctx context.Context, session *Session, parents []*P) error {
+	return Preload(j.rel).load(ctx, session, parents)
+}
+
+// join implements joinedPreload.
+func (j JoinPreload[P, C, K]) join(parentTable, alias string) (string, []any, []string, error) {
+	if j.rel.Type != RelationHasOne && j.rel.Type != RelationBelongsTo {
+		return "", nil, nil, fmt.Errorf("sqlc: PreloadJoin of %v requires a HasOne or BelongsTo relation", reflect.TypeFor[C]())
+	}
+	schema := LoadSchema[C]()
+	join := fmt.Sprintf("%s AS %s ON %s.%s = %s.%s",
+		schema.TableName(), alias, alias, j.rel.ForeignKey.Name, parentTable, j.rel.LocalKey.Name)
+
+	var args []any
+	if sdCol := schema.SoftDeleteColumn(); sdCol != "" {
+		sdSQL, sdArgs, err := sq.Eq{alias + "." + sdCol: softDeleteActive(schema)}.ToSql()
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
+		}
+		join += " AND " + sdSQL
+		args = sdArgs
+	}
+	return join, args, schema.SelectColumns(), nil
+}
+
+// scanner implements joinedPreload.
+func (j JoinPreload[P, C, K]) scanner(mapper *reflectx.Mapper, columns []string) (joinRowScanner[P], error) {
+	typ := reflect.TypeFor[C]()
+	fields := mapper.TraversalsByName(typ, columns)
+	types := make([]reflect.Type, len(fields))
+	for i, field := range fields {
+		if len(field) == 0 {
+			return nil, fmt.Errorf("sqlc: missing destination name %s in %v", columns[i], typ)
+		}
+		types[i] = reflect.PointerTo(typ.FieldByIndex(field).Type)
+	}
+	// The related key is NULL in rows without a match
+	key := slices.Index(columns, j.rel.ForeignKey.Name)
+	if key < 0 {
+		return nil, fmt.Errorf("sqlc: PreloadJoin requires column %s in the select columns of %v", j.rel.ForeignKey.Name, typ)
+	}
+
+	return func() ([]any, func(*P)) {
+		// Scan into pointers, so NULL columns of unmatched rows scan without error
+		holders := make([]reflect.Value, len(types))
+		dests := make([]any, len(types))
+		for i, t := range types {
+			holders[i] = reflect.New(t)
+			dests[i] = holders[i].Interface()
+		}
+		return dests, func(parent *P) {
+			if holders[key].Elem().IsNil() {
+				return
+			}
+			child := new(C)
+			v := reflect.ValueOf(child).Elem()
+			for i, field := range fields {
+				if p := holders[i].Elem(); !p.IsNil() {
+					reflectx.FieldByIndexes(v, field).Set(p.Elem())
+				}
+			}
+			j.rel.Setter(parent, []*C{child})
+		}
+	}, nil
+}
+
+// joinedRelation is a joined preload of a query and its table alias
+type joinedRelation[T any] struct {
+	preload joinedPreload[T]
+	alias   string
+	columns []string
+}
+
+// findJoined runs Find with the joined preloads loaded through LEFT JOINs.
+func (q *QueryBuilder[T]) findJoined(ctx context.Context) ([]*T, error) {
+	q = q.Clone()
+	q.hasJoin = true
+
+	var joins []joinedRelation[T]
+	preloads := q.preloads[:0]
+	for _, preload := range q.preloads {
+		jp, ok := preload.(joinedPreload[T])
+		if !ok {
+			preloads = append(preloads, preload)
+			continue
+		}
+		alias := fmt.Sprintf("sqlc_j%d", len(joins)+1)
+		join, args, columns, err := jp.join(q.table, alias)
+		if err != nil {
+			return nil, err
+		}
+		q.builder = q.builder.LeftJoin(join, args...)
+		joins = append(joins, joinedRelation[T]{preload: jp, alias: alias, columns: columns})
+	}
+	q.preloads = preloads
+
+	b := q.applySelect(q.resolveBuilder())
+	joinedColumns := 0
+	for _, j := range joins {
+		for _, col := range j.columns {
+			b = b.Column(fmt.Sprintf("%s.%s AS %s__%s", j.alias, col, j.alias, col))
+		}
+		joinedColumns += len(j.columns)
+	}
+	query, args, err := q.applyLock(q.applyOrder(b)).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	if err := q.checkColumns(ctx); err != nil {
+		return nil, err
+	}
+	q.logDebug(ctx, query, args)
+	var results []*T
+	err = q.reader(ctx).withPlanCacheMode(ctx, q.plan.CacheMode, func(s *Session) error {
+		results, err = scanJoined(ctx, s, q.plan.annotate(query), args, joins, joinedColumns)
+		return err
+	})
+	if err != nil {
+		return nil, q.queryFailed(err)
+	}
+	return q.finish(ctx, results)
+}
+
+// scanJoined runs a query selecting the columns of T followed by the columns of joins,
+// and splits each row into a T and its joined related models.
+func scanJoined[T any](ctx context.Context, s *Session, query string, args []any, joins []joinedRelation[T], joinedColumns int) ([]*T, error) {
+	rows, err := s.queryx(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	typ := reflect.TypeFor[T]()
+	parentColumns := columns[:len(columns)-joinedColumns]
+	fields := rows.Mapper.TraversalsByName(typ, parentColumns)
+	for i, field := range fields {
+		if len(field) == 0 {
+			return nil, fmt.Errorf("sqlc: missing destination name %s in %v", parentColumns[i], typ)
+		}
+	}
+	scanners := make([]joinRowScanner[T], len(joins))
+	for i, j := range joins {
+		if scanners[i], err = j.preload.scanner(rows.Mapper, j.columns); err != nil {
+			return nil, err
+		}
+	}
+
+	results := []*T{}
+	dests := make([]any, 0, len(columns))
+	assigns := make([]func(*T), len(scanners))
+	for rows.Next() {
+		item := new(T)
+		v := reflect.ValueOf(item).Elem()
+		dests = dests[:0]
+		for _, field := range fields {
+			dests = append(dests, reflectx.FieldByIndexes(v, field).Addr().Interface())
+		}
+		for i, scanner := range scanners {
+			var joined []any
+			joined, assigns[i] = scanner()
+			dests = append(dests, joined...)
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+		for _, assign := range assigns {
+			assign(item)
+		}
+		results = append(results, item)
+	}
+	return results, rows.Err()
+}
+
+// hasJoinedPreloads reports whether Find loads some preloads through a JOIN.
+func (q *QueryBuilder[T]) hasJoinedPreloads() bool {
+	return slices.ContainsFunc(q.preloads, func(p preloadExecutor[T]) bool {
+		_, ok := p.(joinedPreload[T])
+		return ok
+	})
+}
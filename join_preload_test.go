@@ -0,0 +1,163 @@
+package sqlc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestPreloadJoin(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithQueryCapture(64))
+	deptRepo := sqlc.NewRepository[Department](session)
+	memberRepo := sqlc.NewRepository[Member](session)
+
+	eng := &Department{Name: "Engineering"}
+	sales := &Department{Name: "Sales"}
+	for _, d := range []*Department{eng, sales} {
+		if err := deptRepo.Create(ctx, d); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	for _, m := range []*Member{
+		{Name: "Alice", Email: "alice@test.com", DepartmentID: int(eng.ID), CreatedAt: time.Now()},
+		{Name: "Bob", Email: "bob@test.com", DepartmentID: int(sales.ID), CreatedAt: time.Now()},
+		{Name: "Carol", Email: "carol@test.com", CreatedAt: time.Now()},
+	} {
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	byID := clause.OrderByColumn{Column: clause.Column{Name: "id", Table: "members"}}
+
+	// queries returns the statements run by fn
+	queries := func(fn func()) []sqlc.CapturedQuery {
+		before := len(session.RecentQueries(0))
+		fn()
+		return session.RecentQueries(0)[before:]
+	}
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		var members []*Member
+		var err error
+		run := queries(func() {
+			members, err = memberRepo.Query().
+				WithPreload(sqlc.PreloadJoin(MemberDepartment)).
+				OrderBy(byID).
+				Find(ctx)
+		})
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(run) != 1 || !strings.Contains(run[0].SQL, "LEFT JOIN departments AS sqlc_j1") {
+			t.Fatalf("expected one query with a LEFT JOIN, got %+v", run)
+		}
+		if len(members) != 3 {
+			t.Fatalf("expected 3 members, got %d", len(members))
+		}
+		if d := members[0].Department; d == nil || d.ID != eng.ID || d.Name != "Engineering" {
+			t.Errorf("Alice: expected Engineering, got %+v", d)
+		}
+		if d := members[1].Department; d == nil || d.Name != "Sales" {
+			t.Errorf("Bob: expected Sales, got %+v", d)
+		}
+		if members[2].Department != nil {
+			t.Errorf("Carol: expected no department, got %+v", members[2].Department)
+		}
+		if members[0].Name != "Alice" || members[0].Email != "alice@test.com" {
+			t.Errorf("parent columns scanned wrong: %+v", members[0])
+		}
+	})
+
+	// deptMember is a HasOne relation recording the joined member of each department
+	joined := map[int64]string{}
+	deptMember := sqlc.HasOne[Department, Member, int64](
+		clause.Column{Name: "department_id"},
+		clause.Column{Name: "id"},
+		func(d *Department, m *Member) { joined[d.ID] = m.Name },
+		func(d *Department) int64 { return d.ID },
+		func(m *Member) int64 { return int64(m.DepartmentID) },
+	)
+
+	t.Run("HasOne", func(t *testing.T) {
+		clear(joined)
+		dept, err := deptRepo.Query().
+			WithPreload(sqlc.PreloadJoin(deptMember)).
+			Where(clause.Eq{Column: clause.Column{Name: "name", Table: "departments"}, Value: "Sales"}).
+			First(ctx)
+		if err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if dept.Name != "Sales" || joined[dept.ID] != "Bob" {
+			t.Errorf("expected Sales joined with Bob, got %+v, %v", dept, joined)
+		}
+	})
+
+	t.Run("WithRegularPreload", func(t *testing.T) {
+		clear(joined)
+		var depts []*Department
+		var err error
+		run := queries(func() {
+			depts, err = deptRepo.Query().
+				WithPreload(sqlc.Preload(DepartmentHasMembers)).
+				WithPreload(sqlc.PreloadJoin(deptMember)).
+				OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id", Table: "departments"}}).
+				Find(ctx)
+		})
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(run) != 2 {
+			t.Errorf("expected the joined query and one preload query, got %d", len(run))
+		}
+		if len(depts) != 2 || len(depts[0].Members) != 1 || depts[0].Members[0].Name != "Alice" {
+			t.Fatalf("expected Engineering with Alice preloaded, got %+v", depts)
+		}
+		if joined[eng.ID] != "Alice" || joined[sales.ID] != "Bob" {
+			t.Errorf("expected joined members Alice and Bob, got %v", joined)
+		}
+	})
+
+	t.Run("HasManyRejected", func(t *testing.T) {
+		_, err := deptRepo.Query().WithPreload(sqlc.PreloadJoin(DepartmentHasMembers)).Find(ctx)
+		if err == nil || !strings.Contains(err.Error(), "HasOne or BelongsTo") {
+			t.Errorf("expected HasOne or BelongsTo error, got %v", err)
+		}
+	})
+
+	t.Run("Chunk", func(t *testing.T) {
+		var loaded int
+		err := memberRepo.Query().
+			WithPreload(sqlc.PreloadJoin(MemberDepartment)).
+			Chunk(ctx, 10, func(members []*Member) error {
+				for _, m := range members {
+					if m.Department != nil {
+						loaded++
+					}
+				}
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if loaded != 2 {
+			t.Errorf("expected 2 members with a department, got %d", loaded)
+		}
+	})
+
+	t.Run("ChunkStreamRejected", func(t *testing.T) {
+		err := memberRepo.Query().
+			WithPreload(sqlc.PreloadJoin(MemberDepartment)).
+			ChunkStream(ctx, 10, func([]*Member) error { return nil })
+		if err == nil || !strings.Contains(err.Error(), "does not support Preload") {
+			t.Errorf("expected Preload rejection, got %v", err)
+		}
+	})
+}
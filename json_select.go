@@ -0,0 +1,148 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements partial selection of JSON columns.
+//
+// A list endpoint showing two keys of a large JSON document still transfers and decodes
+// the whole document. SelectJSONPaths makes the database extract the needed paths instead:
+// the column is selected as a smaller document holding only those paths, with the same
+// nesting, so the model's JSON[T] or LazyJSON[T] field decodes it unchanged:
+//
+//	SELECT id, JSON_OBJECT('name', JSON_EXTRACT(metadata, '$.name')) AS metadata FROM users
+//
+// Usage example:
+//
+//	users, err := userRepo.Query().
+//	    SelectJSONPaths(generated.User.Metadata, "name", "address.city").
+//	    Find(ctx)
+//	// users[i].Metadata.Data.Name and .Address.City are set; other keys are zero
+package sqlc
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// jsonPathKeyRegexp matches a key of a JSON path accepted by SelectJSONPaths.
+// Keys are inlined into the SQL, so only plain identifiers are accepted.
+var jsonPathKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// SelectJSONPaths selects only the given paths of a JSON column, as a document holding
+// those paths. Other columns are selected as usual.
+//
+// Parameters:
+//   - column: JSON column of the model, e.g. generated.User.Metadata
+//   - paths: Object key paths, dot-separated ("address.city"); a leading "$." is accepted
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	users, err := userRepo.Query().
+//	    SelectJSONPaths(generated.User.Metadata, "name", "tags").
+//	    Limit(100).
+//	    Find(ctx)
+//
+// Note:
+//   - Keys may contain letters, digits and underscores; array indexes are not supported
+//   - A path missing from a document is selected as null, which decodes to the zero value
+//   - Only applies when the model's columns are selected (not with Select)
+//   - Records read this way hold partial documents: do not write them back with Update
+func (q *QueryBuilder[T]) SelectJSONPaths(column clause.Columnar, paths ...string) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	name := column.ColumnName()
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if !slices.Contains(q.schema.SelectColumns(), name) {
+		q.err = fmt.Errorf("sqlc: SelectJSONPaths: %s is not a column of %s", name, q.table)
+		return q
+	}
+	if len(paths) == 0 {
+		q.err = fmt.Errorf("sqlc: SelectJSONPaths requires at least one path")
+		return q
+	}
+	keys := make([][]string, len(paths))
+	for i, path := range paths {
+		keys[i] = strings.Split(strings.TrimPrefix(path, "$."), ".")
+		for _, key := range keys[i] {
+			if !jsonPathKeyRegexp.MatchString(key) {
+				q.err = fmt.Errorf("sqlc: SelectJSONPaths: invalid JSON path %q", path)
+				return q
+			}
+		}
+	}
+	q.jsonPaths = maps.Clone(q.jsonPaths)
+	if q.jsonPaths == nil {
+		q.jsonPaths = make(map[string][][]string)
+	}
+	q.jsonPaths[name] = keys
+	return q
+}
+
+// jsonPathNode is a key of the document built by jsonProjection: a leaf is extracted,
+// other nodes are built as objects of their children.
+type jsonPathNode struct {
+	key      string
+	children []*jsonPathNode // nil for a leaf
+}
+
+// jsonPathTree merges paths into a tree; a path covers any longer path below it.
+func jsonPathTree(paths [][]string) *jsonPathNode {
+	root := &jsonPathNode{children: []*jsonPathNode{}}
+	for _, path := range paths {
+		node := root
+		for i, key := range path {
+			if node.children == nil {
+				break // An ancestor is extracted whole
+			}
+			j := slices.IndexFunc(node.children, func(n *jsonPathNode) bool { return n.key == key })
+			if j < 0 {
+				node.children = append(node.children, &jsonPathNode{key: key, children: []*jsonPathNode{}})
+				j = len(node.children) - 1
+			}
+			node = node.children[j]
+			if i == len(path)-1 {
+				node.children = nil
+			}
+		}
+	}
+	return root
+}
+
+// jsonProjection returns the SQL building a document of the given paths of column.
+func jsonProjection(dialect Dialect, column string, paths [][]string) string {
+	object, extract := "JSON_OBJECT(%s)", func(path []string) string {
+		return fmt.Sprintf("JSON_EXTRACT(%s, '$.%s')", column, strings.Join(path, "."))
+	}
+	switch dialect.Name() {
+	case "postgres":
+		object, extract = "jsonb_build_object(%s)", func(path []string) string {
+			return fmt.Sprintf("%s #> '{%s}'", column, strings.Join(path, ","))
+		}
+	case "sqlite3":
+		object, extract = "json_object(%s)", func(path []string) string {
+			return fmt.Sprintf("%s -> '$.%s'", column, strings.Join(path, "."))
+		}
+	}
+
+	var render func(node *jsonPathNode, path []string) string
+	render = func(node *jsonPathNode, path []string) string {
+		if node.children == nil {
+			return extract(path)
+		}
+		pairs := make([]string, len(node.children))
+		for i, child := range node.children {
+			pairs[i] = "'" + child.key + "', " + render(child, append(slices.Clip(path), child.key))
+		}
+		return fmt.Sprintf(object, strings.Join(pairs, ", "))
+	}
+	return render(jsonPathTree(paths), nil)
+}
@@ -0,0 +1,157 @@
+package sqlc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+type ProfileAddress struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type ProfileMeta struct {
+	Name    string         `json:"name"`
+	Tags    []string       `json:"tags"`
+	Address ProfileAddress `json:"address"`
+	Bio     string         `json:"bio"`
+}
+
+// Profile Model: a JSON document decoded eagerly and one decoded lazily
+type Profile struct {
+	ID       int64                          `db:"id,primaryKey,autoIncrement"`
+	Meta     sqlc.JSON[ProfileMeta]         `db:"meta"`
+	Settings sqlc.LazyJSON[map[string]bool] `db:"settings"`
+}
+
+type ProfileSchema struct{}
+
+func (ProfileSchema) TableName() string { return "profiles" }
+func (ProfileSchema) SelectColumns() []string {
+	return []string{"id", "meta", "settings"}
+}
+func (ProfileSchema) InsertRow(m *Profile) ([]string, []any) {
+	return []string{"meta", "settings"}, []any{m.Meta, m.Settings}
+}
+func (ProfileSchema) PK(m *Profile) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (ProfileSchema) SetPK(m *Profile, val int64) { m.ID = val }
+func (ProfileSchema) AutoIncrement() bool         { return true }
+func (ProfileSchema) SoftDeleteColumn() string    { return "" }
+func (ProfileSchema) SoftDeleteValue() any        { return nil }
+func (ProfileSchema) SetDeletedAt(m *Profile)     {}
+func (ProfileSchema) UpdateMap(m *Profile) map[string]any {
+	return map[string]any{"meta": m.Meta, "settings": m.Settings}
+}
+
+func TestJSONScanning(t *testing.T) {
+	sqlc.RegisterSchema(ProfileSchema{})
+	ctx := context.Background()
+	meta := clause.Column{Name: "meta"}
+
+	db, session := setupTestDB(t)
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE profiles (id INTEGER PRIMARY KEY AUTOINCREMENT, meta TEXT, settings TEXT)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	repo := sqlc.NewRepository[Profile](session)
+	err := repo.Create(ctx, &Profile{
+		Meta: sqlc.NewJSON(ProfileMeta{
+			Name:    "ann",
+			Tags:    []string{"go", "sql"},
+			Address: ProfileAddress{City: "Oslo", Country: "NO"},
+			Bio:     strings.Repeat("long text ", 100),
+		}),
+		Settings: sqlc.NewLazyJSON(map[string]bool{"beta": true}),
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	t.Run("SelectJSONPaths", func(t *testing.T) {
+		p, err := repo.Query().SelectJSONPaths(meta, "name", "$.tags", "address.city").First(ctx)
+		if err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		got := p.Meta.Data
+		if got.Name != "ann" || strings.Join(got.Tags, ",") != "go,sql" || got.Address.City != "Oslo" {
+			t.Errorf("expected the selected paths, got %+v", got)
+		}
+		if got.Bio != "" || got.Address.Country != "" {
+			t.Errorf("expected other paths left out, got %+v", got)
+		}
+
+		// A path covers the longer paths below it
+		p, err = repo.Query().SelectJSONPaths(meta, "address.city", "address").First(ctx)
+		if err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if p.Meta.Data.Address != (ProfileAddress{City: "Oslo", Country: "NO"}) {
+			t.Errorf("expected the whole address, got %+v", p.Meta.Data.Address)
+		}
+	})
+
+	t.Run("SelectJSONPathsSQL", func(t *testing.T) {
+		for _, tt := range []struct {
+			dialect sqlc.Dialect
+			want    string
+		}{
+			{sqlc.MySQLDialect{}, "JSON_OBJECT('name', JSON_EXTRACT(meta, '$.name'), 'address', JSON_OBJECT('city', JSON_EXTRACT(meta, '$.address.city'))) AS meta"},
+			{sqlc.PostgreSQLDialect{}, "jsonb_build_object('name', meta #> '{name}', 'address', jsonb_build_object('city', meta #> '{address,city}')) AS meta"},
+			{sqlc.SQLiteDialect{}, "json_object('name', meta -> '$.name', 'address', json_object('city', meta -> '$.address.city')) AS meta"},
+		} {
+			query, _, err := sqlc.NewRepository[Profile](sqlc.NewSession(db, tt.dialect)).Query().
+				SelectJSONPaths(meta, "name", "address.city").
+				ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL failed: %v", err)
+			}
+			if want := "SELECT id, " + tt.want + ", settings FROM profiles"; query != want {
+				t.Errorf("%s:\n got: %s\nwant: %s", tt.dialect.Name(), query, want)
+			}
+		}
+	})
+
+	t.Run("SelectJSONPathsInvalid", func(t *testing.T) {
+		for _, q := range []*sqlc.QueryBuilder[Profile]{
+			repo.Query().SelectJSONPaths(meta, "tags[0]"),
+			repo.Query().SelectJSONPaths(meta, "name') --"),
+			repo.Query().SelectJSONPaths(meta),
+			repo.Query().SelectJSONPaths(clause.Column{Name: "other"}, "name"),
+		} {
+			if _, err := q.Find(ctx); err == nil {
+				t.Error("expected error")
+			}
+		}
+	})
+
+	t.Run("LazyJSON", func(t *testing.T) {
+		p, err := repo.Query().First(ctx)
+		if err != nil {
+			t.Fatalf("First failed: %v", err)
+		}
+		if p.Settings.Decoded() {
+			t.Error("expected the document not decoded by the query")
+		}
+		// Written back without decoding
+		if err := repo.Update(ctx, p); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if p.Settings.Decoded() {
+			t.Error("expected the write not to decode the document")
+		}
+		settings, err := p.Settings.Get()
+		if err != nil || !settings["beta"] {
+			t.Errorf("unexpected settings %v, %v", settings, err)
+		}
+	})
+}
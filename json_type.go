@@ -1,6 +1,7 @@
 package sqlc
 
 import (
+	"bytes"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
@@ -67,3 +68,102 @@ func (j JSON[T]) MarshalJSON() ([]byte, error) {
 func (j *JSON[T]) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, &j.Data)
 }
+
+// LazyJSON is a JSON field wrapper that defers decoding until the data is accessed.
+// Scan keeps the raw document; Get decodes it on first call. Writing a record back, or
+// marshaling it into an API response, passes the raw document through without decoding.
+//
+// Use it for large documents that most reads do not touch, e.g. list endpoints loading
+// records with a big settings or payload column.
+//
+// Usage:
+//
+//	type Order struct {
+//	    Payload sqlc.LazyJSON[OrderPayload] `db:"payload"`
+//	}
+//
+//	payload, err := order.Payload.Get() // Decoded here, once
+//
+// Note:
+//   - Not safe for concurrent use before the first Get; decode it first if the model is shared
+type LazyJSON[T any] struct {
+	raw     []byte
+	data    T
+	decoded bool
+}
+
+// NewLazyJSON creates a new LazyJSON wrapper holding the decoded value v.
+func NewLazyJSON[T any](v T) LazyJSON[T] {
+	return LazyJSON[T]{data: v, decoded: true}
+}
+
+// Get returns the data, decoding the raw document on the first call.
+// A NULL or empty document decodes to the zero value.
+func (j *LazyJSON[T]) Get() (T, error) {
+	if !j.decoded {
+		if len(j.raw) > 0 {
+			var data T
+			if err := json.Unmarshal(j.raw, &data); err != nil {
+				return data, fmt.Errorf("sqlc: failed to decode JSON: %w", err)
+			}
+			j.data = data
+		}
+		j.raw, j.decoded = nil, true
+	}
+	return j.data, nil
+}
+
+// Set replaces the data with v.
+func (j *LazyJSON[T]) Set(v T) {
+	j.raw, j.data, j.decoded = nil, v, true
+}
+
+// Decoded reports whether the data has been decoded (or set) already.
+func (j LazyJSON[T]) Decoded() bool {
+	return j.decoded
+}
+
+// Raw returns the JSON document without decoding it; a decoded value is encoded again.
+func (j LazyJSON[T]) Raw() (json.RawMessage, error) {
+	if !j.decoded && j.raw != nil {
+		return j.raw, nil
+	}
+	return json.Marshal(j.data)
+}
+
+// Scan implements the sql.Scanner interface. The document is copied, not decoded.
+func (j *LazyJSON[T]) Scan(value any) error {
+	var zero T
+	j.data, j.decoded = zero, false
+	switch v := value.(type) {
+	case nil:
+		j.raw = nil
+	case []byte:
+		// Drivers may reuse the buffer after Scan returns
+		j.raw = bytes.Clone(v)
+	case string:
+		j.raw = []byte(v)
+	default:
+		return fmt.Errorf("sqlc: failed to scan JSON: expected []byte or string, got %T", value)
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (j LazyJSON[T]) Value() (driver.Value, error) {
+	raw, err := j.Raw()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(raw), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (j LazyJSON[T]) MarshalJSON() ([]byte, error) {
+	return j.Raw()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The document is kept for a later Get.
+func (j *LazyJSON[T]) UnmarshalJSON(data []byte) error {
+	return j.Scan(data)
+}
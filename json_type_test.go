@@ -123,3 +123,67 @@ func TestJSONNested(t *testing.T) {
 		assert.Equal(t, "Japan", j2.Data.Address.Country)
 	})
 }
+
+// TestLazyJSON tests the LazyJSON[T] generic type
+func TestLazyJSON(t *testing.T) {
+	type Metadata struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("Scan defers decoding", func(t *testing.T) {
+		var j LazyJSON[Metadata]
+		input := []byte(`{"name":"scanned"}`)
+		require.NoError(t, j.Scan(input))
+		copy(input, "xxxxxxxxxx") // The driver reuses its buffer
+
+		assert.False(t, j.Decoded())
+		raw, err := j.Raw()
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"scanned"}`, string(raw))
+
+		data, err := j.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "scanned", data.Name)
+		assert.True(t, j.Decoded())
+	})
+
+	t.Run("Scan from nil", func(t *testing.T) {
+		j := NewLazyJSON(Metadata{Name: "preset"})
+		require.NoError(t, j.Scan(nil))
+		data, err := j.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "", data.Name)
+	})
+
+	t.Run("Value passes the raw document through", func(t *testing.T) {
+		var j LazyJSON[Metadata]
+		require.NoError(t, j.Scan(`{"name": "kept", "extra": 1}`))
+		val, err := j.Value()
+		require.NoError(t, err)
+		assert.Equal(t, []byte(`{"name": "kept", "extra": 1}`), val)
+
+		j.Set(Metadata{Name: "set"})
+		val, err = j.Value()
+		require.NoError(t, err)
+		assert.Equal(t, []byte(`{"name":"set"}`), val)
+	})
+
+	t.Run("JSON round trip", func(t *testing.T) {
+		var out struct {
+			Meta LazyJSON[Metadata] `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"meta":{"name":"api"}}`), &out))
+		assert.False(t, out.Meta.Decoded())
+		encoded, err := json.Marshal(out)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"meta":{"name":"api"}}`, string(encoded))
+	})
+
+	t.Run("Invalid document", func(t *testing.T) {
+		var j LazyJSON[Metadata]
+		require.NoError(t, j.Scan(`{"name":`))
+		_, err := j.Get()
+		assert.Error(t, err)
+		assert.False(t, j.Decoded())
+	})
+}
@@ -0,0 +1,220 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements many-to-many relationships through a join table.
+//
+// A ManyToMany relation links parent and child models by the rows of a join table
+// (users <-> user_roles <-> roles). Preloading batches through the join table in two
+// queries, independent of the number of parents:
+//  1. SELECT user_id, role_id FROM user_roles WHERE user_id IN (...)
+//  2. SELECT ... FROM roles WHERE id IN (...)   -- with the preload's query options
+//
+// Usage example:
+//
+//	// Generated from: Roles []*Role `db:"-" relation:"many2many,joinTable:user_roles"`
+//	userRoles := sqlc.ManyToMany[User, Role, int64](
+//	    "user_roles",
+//	    clause.Column{Name: "user_id"},
+//	    clause.Column{Name: "role_id"},
+//	    clause.Column{Name: "id"},
+//	    clause.Column{Name: "id"},
+//	    func(u *User, roles []*Role) { u.Roles = roles },
+//	    func(u *User) int64 { return u.ID },
+//	    func(r *Role) int64 { return r.ID },
+//	)
+//
+//	users, err := userRepo.Query().WithPreload(sqlc.Preload(userRoles)).Find(ctx)
+package sqlc
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// ManyToMany creates a ManyToMany relationship definition: parent P and child C are
+// linked by rows of joinTable.
+//
+// Type parameters:
+//   - P: Parent model type (e.g., User)
+//   - C: Child model type (e.g., Role)
+//   - K: Key type of both models (e.g., int64, string)
+//
+// Parameters:
+//   - joinTable: Join table name (e.g. "user_roles")
+//   - joinForeignKey: Join table column referencing the parent (e.g. user_id)
+//   - joinReferences: Join table column referencing the child (e.g. role_id)
+//   - localKey: Parent key column referenced by joinForeignKey (usually its primary key)
+//   - targetKey: Child key column referenced by joinReferences (usually its primary key)
+//   - setter: Sets the loaded children on the parent
+//   - getLocalKey: Extracts the key value from the parent
+//   - getTargetKey: Extracts the key value from the child
+//
+// Example:
+//
+//	userRoles := sqlc.ManyToMany[User, Role, int64](
+//	    "user_roles",
+//	    clause.Column{Name: "user_id"},
+//	    clause.Column{Name: "role_id"},
+//	    clause.Column{Name: "id"},
+//	    clause.Column{Name: "id"},
+//	    func(u *User, roles []*Role) { u.Roles = roles },
+//	    func(u *User) int64 { return u.ID },
+//	    func(r *Role) int64 { return r.ID },
+//	)
+//
+// Note:
+//   - Parents without join rows get an empty slice, like HasMany
+//   - A child linked to several parents is loaded once and shared by them
+//   - Preload options (Where, OrderBy, ...) apply to the child query; Limit limits
+//     the children of all parents together, not per parent
+func ManyToMany[P, C any, K comparable](
+	joinTable string,
+	joinForeignKey clause.Column,
+	joinReferences clause.Column,
+	localKey clause.Column,
+	targetKey clause.Column,
+	setter func(*P, []*C),
+	getLocalKey func(*P) K,
+	getTargetKey func(*C) K,
+) Relation[P, C, K] {
+	return Relation[P, C, K]{
+		Type:       RelationManyToMany,
+		ForeignKey: targetKey,
+		LocalKey:   localKey,
+		Setter: func(p *P, children []*C) {
+			if len(children) == 0 {
+				children = []*C{}
+			}
+			setter(p, children)
+		},
+		GetLocalKeyValue:   getLocalKey,
+		GetForeignKeyValue: getTargetKey,
+		JoinTable:          joinTable,
+		JoinForeignKey:     joinForeignKey,
+		JoinReferences:     joinReferences,
+	}
+}
+
+// HasManyThrough creates a relationship reaching children C through the rows of an
+// intermediate model's table, e.g. users to teams through memberships
+// (memberships.user_id, memberships.team_id). It is loaded like ManyToMany: the
+// intermediate rows of the parents, then the children in one IN query.
+//
+// Parameters:
+//   - through: Intermediate table name (e.g. "memberships")
+//   - foreignKey: Intermediate table column referencing the parent (e.g. user_id)
+//   - throughKey: Intermediate table column referencing the child (e.g. team_id)
+//   - localKey: Parent key column referenced by foreignKey (usually its primary key)
+//   - targetKey: Child key column referenced by throughKey (usually its primary key)
+//   - setter: Sets the loaded children on the parent
+//   - getLocalKey: Extracts the key value from the parent
+//   - getTargetKey: Extracts the key value from the child
+//
+// Example:
+//
+//	// Generated from: Teams []*Team `db:"-" relation:"hasManyThrough,through:memberships,throughKey:team_id"`
+//	userTeams := sqlc.HasManyThrough[User, Team, int64](
+//	    "memberships",
+//	    clause.Column{Name: "user_id"},
+//	    clause.Column{Name: "team_id"},
+//	    clause.Column{Name: "id"},
+//	    clause.Column{Name: "id"},
+//	    func(u *User, teams []*Team) { u.Teams = teams },
+//	    func(u *User) int64 { return u.ID },
+//	    func(t *Team) int64 { return t.ID },
+//	)
+//
+//	users, err := userRepo.Query().WithPreload(sqlc.Preload(userTeams)).Find(ctx)
+//
+// Note:
+//   - The relation has type RelationManyToMany, so WithCount and the relation's
+//     preload options behave as for ManyToMany
+//   - Association only writes the two key columns of intermediate rows; create rows
+//     carrying more data (role, joined_at) through the intermediate model's repository
+//   - Soft-deleted intermediate rows still link their parent and child
+func HasManyThrough[P, C any, K comparable](
+	through string,
+	foreignKey clause.Column,
+	throughKey clause.Column,
+	localKey clause.Column,
+	targetKey clause.Column,
+	setter func(*P, []*C),
+	getLocalKey func(*P) K,
+	getTargetKey func(*C) K,
+) Relation[P, C, K] {
+	return ManyToMany(through, foreignKey, throughKey, localKey, targetKey, setter, getLocalKey, getTargetKey)
+}
+
+// joinRow is a row of a many-to-many join table
+type joinRow[K comparable] struct {
+	Parent K `db:"parent_key"`
+	Child  K `db:"child_key"`
+}
+
+// loadManyToMany queries the children of parents through the join table and groups
+// them by parent key.
+func (p RelationPreload[P, C, K]) loadManyToMany(ctx context.Context, session *Session, parents []*P) (map[K][]*C, error) {
+	childMap := make(map[K][]*C)
+
+	// Step 1: Collect and deduplicate parent key values
+	seen := make(map[K]struct{}, len(parents))
+	parentKeys := make([]any, 0, len(parents))
+	for i := range parents {
+		k := p.rel.GetLocalKeyValue(parents[i])
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			parentKeys = append(parentKeys, k)
+		}
+	}
+
+	// Step 2: Read the join rows of the parents
+	joinSQL, joinArgs, err := sq.Select(
+		p.rel.JoinForeignKey.Name+" AS parent_key",
+		p.rel.JoinReferences.Name+" AS child_key",
+	).
+		From(p.rel.JoinTable).
+		Where(sq.Eq{p.rel.JoinForeignKey.Name: parentKeys}).
+		PlaceholderFormat(session.dialect.PlaceholderFormat()).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+	var links []joinRow[K]
+	if err := session.reader(ctx).Select(ctx, &links, joinSQL, joinArgs...); err != nil {
+		return nil, fmt.Errorf("sqlc: failed to read join table %s: %w", p.rel.JoinTable, err)
+	}
+	if len(links) == 0 {
+		return childMap, nil
+	}
+
+	// Step 3: Map child keys to the parents linking them
+	parentsOf := make(map[K][]K, len(links))
+	childKeys := make([]any, 0, len(links))
+	for _, link := range links {
+		if _, ok := parentsOf[link.Child]; !ok {
+			childKeys = append(childKeys, link.Child)
+		}
+		parentsOf[link.Child] = append(parentsOf[link.Child], link.Parent)
+	}
+
+	// Step 4: Load the children
+	query := Query[C](session)
+	if len(childKeys) == 1 {
+		query = query.Where(clause.Eq{Column: p.rel.ForeignKey, Value: childKeys[0]})
+	} else {
+		query = query.Where(clause.IN{Column: p.rel.ForeignKey, Values: childKeys})
+	}
+	children, err := p.customize(query).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 5: Group children by parent key, keeping the child query order
+	for _, child := range children {
+		for _, parentKey := range parentsOf[p.rel.GetForeignKeyValue(child)] {
+			childMap[parentKey] = append(childMap[parentKey], child)
+		}
+	}
+	return childMap, nil
+}
@@ -0,0 +1,225 @@
+package sqlc_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Student Model
+type Student struct {
+	ID      int64     `db:"id,primaryKey,autoIncrement"`
+	Name    string    `db:"name"`
+	Courses []*Course `db:"-"`
+}
+
+type StudentSchema struct{}
+
+func (StudentSchema) TableName() string       { return "students" }
+func (StudentSchema) SelectColumns() []string { return []string{"id", "name"} }
+func (StudentSchema) InsertRow(m *Student) ([]string, []any) {
+	return []string{"name"}, []any{m.Name}
+}
+func (StudentSchema) PK(m *Student) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (StudentSchema) SetPK(m *Student, val int64)         { m.ID = val }
+func (StudentSchema) AutoIncrement() bool                 { return true }
+func (StudentSchema) SoftDeleteColumn() string            { return "" }
+func (StudentSchema) SoftDeleteValue() any                { return nil }
+func (StudentSchema) SetDeletedAt(m *Student)             {}
+func (StudentSchema) UpdateMap(m *Student) map[string]any { return nil }
+
+// Course Model
+type Course struct {
+	ID    int64  `db:"id,primaryKey,autoIncrement"`
+	Title string `db:"title"`
+}
+
+type CourseSchema struct{}
+
+func (CourseSchema) TableName() string       { return "courses" }
+func (CourseSchema) SelectColumns() []string { return []string{"id", "title"} }
+func (CourseSchema) InsertRow(m *Course) ([]string, []any) {
+	return []string{"title"}, []any{m.Title}
+}
+func (CourseSchema) PK(m *Course) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (CourseSchema) SetPK(m *Course, val int64)         { m.ID = val }
+func (CourseSchema) AutoIncrement() bool                { return true }
+func (CourseSchema) SoftDeleteColumn() string           { return "" }
+func (CourseSchema) SoftDeleteValue() any               { return nil }
+func (CourseSchema) SetDeletedAt(m *Course)             {}
+func (CourseSchema) UpdateMap(m *Course) map[string]any { return nil }
+
+// StudentCourses defines the ManyToMany relation: Student <-> Course via enrollments
+var StudentCourses = sqlc.ManyToMany[Student, Course, int64](
+	"enrollments",
+	clause.Column{Name: "student_id"},
+	clause.Column{Name: "course_id"},
+	clause.Column{Name: "id"},
+	clause.Column{Name: "id"},
+	func(s *Student, courses []*Course) { s.Courses = courses },
+	func(s *Student) int64 { return s.ID },
+	func(c *Course) int64 { return c.ID },
+)
+
+func TestManyToManyPreload(t *testing.T) {
+	sqlc.RegisterSchema(StudentSchema{})
+	sqlc.RegisterSchema(CourseSchema{})
+
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	for _, ddl := range []string{
+		`CREATE TABLE students (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`,
+		`CREATE TABLE courses (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT)`,
+		`CREATE TABLE enrollments (student_id INTEGER, course_id INTEGER, PRIMARY KEY (student_id, course_id))`,
+	} {
+		if _, err := db.Exec(ddl); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+	}
+
+	studentRepo := sqlc.NewRepository[Student](session)
+	courseRepo := sqlc.NewRepository[Course](session)
+
+	students := []*Student{{Name: "alice"}, {Name: "bob"}, {Name: "carol"}}
+	courses := []*Course{{Title: "Databases"}, {Title: "Algebra"}, {Title: "Compilers"}}
+	if err := studentRepo.BatchCreate(ctx, students); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+	if err := courseRepo.BatchCreate(ctx, courses); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+	// alice: Databases, Algebra; bob: Algebra; carol: none
+	for _, link := range [][2]int64{{students[0].ID, courses[0].ID}, {students[0].ID, courses[1].ID}, {students[1].ID, courses[1].ID}} {
+		if _, err := session.Exec(ctx, "INSERT INTO enrollments (student_id, course_id) VALUES (?, ?)", link[0], link[1]); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+	}
+
+	byTitle := func(q *sqlc.QueryBuilder[Course]) *sqlc.QueryBuilder[Course] {
+		return q.OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "title"}})
+	}
+	titles := func(cs []*Course) []string {
+		out := []string{}
+		for _, c := range cs {
+			out = append(out, c.Title)
+		}
+		return out
+	}
+
+	t.Run("Preload", func(t *testing.T) {
+		got, err := studentRepo.Query().
+			WithPreload(sqlc.Preload(StudentCourses, byTitle)).
+			OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("expected 3 students, got %d", len(got))
+		}
+		want := [][]string{{"Algebra", "Databases"}, {"Algebra"}, {}}
+		for i, s := range got {
+			if s.Courses == nil {
+				t.Errorf("%s: expected empty slice, got nil", s.Name)
+			}
+			if g := titles(s.Courses); !reflect.DeepEqual(g, want[i]) {
+				t.Errorf("%s: got courses %v, want %v", s.Name, g, want[i])
+			}
+		}
+		// Algebra is loaded once and shared by alice and bob
+		if got[0].Courses[0] != got[1].Courses[0] {
+			t.Error("expected the shared course to be loaded once")
+		}
+	})
+
+	t.Run("FilteredChildren", func(t *testing.T) {
+		got, err := studentRepo.Query().
+			Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: "alice"}).
+			WithPreload(sqlc.Preload(StudentCourses, func(q *sqlc.QueryBuilder[Course]) *sqlc.QueryBuilder[Course] {
+				return q.Where(clause.Eq{Column: clause.Column{Name: "title"}, Value: "Databases"})
+			})).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 1 || len(got[0].Courses) != 1 || got[0].Courses[0].Title != "Databases" {
+			t.Fatalf("expected alice with Databases only, got %+v", got)
+		}
+	})
+
+	t.Run("IntoMap", func(t *testing.T) {
+		res, err := sqlc.FindWithMap(ctx, studentRepo.Query(), sqlc.Preload(StudentCourses).IntoMap())
+		if err != nil {
+			t.Fatalf("FindWithMap failed: %v", err)
+		}
+		if n := len(res.Related[students[0].ID]); n != 2 {
+			t.Errorf("alice: expected 2 courses, got %d", n)
+		}
+		if _, ok := res.Related[students[2].ID]; ok {
+			t.Error("carol: expected no entry")
+		}
+	})
+
+	t.Run("NoJoinRows", func(t *testing.T) {
+		got, err := studentRepo.Query().
+			Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: "carol"}).
+			WithPreload(sqlc.Preload(StudentCourses)).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(got) != 1 || got[0].Courses == nil || len(got[0].Courses) != 0 {
+			t.Fatalf("expected carol with no courses, got %+v", got)
+		}
+	})
+	t.Run("HasManyThrough", func(t *testing.T) {
+		// grades rows carry their own data; bob passed Compilers
+		if _, err := db.Exec(`CREATE TABLE grades (id INTEGER PRIMARY KEY AUTOINCREMENT, student_id INTEGER, course_id INTEGER, score INTEGER)`); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+		if _, err := session.Exec(ctx, "INSERT INTO grades (student_id, course_id, score) VALUES (?, ?, 90)", students[1].ID, courses[2].ID); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		graded := sqlc.HasManyThrough[Student, Course, int64](
+			"grades",
+			clause.Column{Name: "student_id"},
+			clause.Column{Name: "course_id"},
+			clause.Column{Name: "id"},
+			clause.Column{Name: "id"},
+			func(s *Student, courses []*Course) { s.Courses = courses },
+			func(s *Student) int64 { return s.ID },
+			func(c *Course) int64 { return c.ID },
+		)
+
+		got, err := studentRepo.Query().
+			WithPreload(sqlc.Preload(graded)).
+			OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		want := [][]string{{}, {"Compilers"}, {}}
+		for i, s := range got {
+			if !reflect.DeepEqual(titles(s.Courses), want[i]) {
+				t.Errorf("%s: got %v, want %v", s.Name, titles(s.Courses), want[i])
+			}
+		}
+	})
+}
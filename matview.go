@@ -0,0 +1,162 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements models backed by materialized views.
+//
+// A materialized view stores the result of a query so reporting read models can be
+// read like tables and recomputed on demand. PostgreSQL materializes views natively;
+// on MySQL and SQLite the view is emulated by a table that Refresh rebuilds and swaps
+// in, so readers never observe a half-built result.
+//
+// Usage example:
+//
+//	func init() {
+//	    sqlc.RegisterMaterializedView[models.DailySales](`
+//	        SELECT date(created_at) AS day, SUM(total) AS revenue
+//	        FROM orders GROUP BY date(created_at)`)
+//	}
+//
+//	// At startup or in a migration
+//	err := sqlc.CreateMaterializedView[models.DailySales](ctx, session)
+//
+//	// Periodically
+//	err = salesRepo.Refresh(ctx, true)
+//	days, err := salesRepo.Query().Where(generated.DailySales.Revenue.Gt(1000)).Find(ctx)
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// materializedViews maps model types to the defining query of their materialized view.
+// Like schemas, it is written during initialization and read-only afterwards.
+var materializedViews = make(map[reflect.Type]string)
+
+// RegisterMaterializedView declares that model T is backed by a materialized view
+// (the table name of T's schema) defined by query.
+//
+// Parameters:
+//   - query: SELECT statement producing the rows of the view; its columns must match T's schema
+//
+// Example:
+//
+//	func init() {
+//	    sqlc.RegisterMaterializedView[models.UserStats](
+//	        "SELECT user_id, COUNT(*) AS orders FROM orders GROUP BY user_id")
+//	}
+//
+// Note:
+//   - T's schema must be registered as well (generated code does this)
+//   - The view is read-only: write through the tables of query, then Refresh
+func RegisterMaterializedView[T any](query string) {
+	materializedViews[reflect.TypeFor[T]()] = query
+}
+
+// materializedViewQuery returns the defining query of model T's materialized view.
+func materializedViewQuery[T any]() (string, error) {
+	typ := reflect.TypeFor[T]()
+	query, ok := materializedViews[typ]
+	if !ok {
+		return "", fmt.Errorf("sqlc: %s is not registered as a materialized view", typ)
+	}
+	return query, nil
+}
+
+// CreateMaterializedView creates the materialized view of model T if it does not exist,
+// populated with the current result of its query.
+//
+// Example:
+//
+//	if err := sqlc.CreateMaterializedView[models.UserStats](ctx, session); err != nil {
+//	    return err
+//	}
+//
+// Note:
+//   - MySQL and SQLite get a regular table with the view's name
+func CreateMaterializedView[T any](ctx context.Context, session *Session) error {
+	query, err := materializedViewQuery[T]()
+	if err != nil {
+		return err
+	}
+	table := LoadSchema[T]().TableName()
+
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s AS %s", table, query)
+	if session.dialect.Name() == PostgreSQL.Name() {
+		stmt = fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %s AS %s", table, query)
+	}
+	if _, err := session.Exec(ctx, stmt); err != nil {
+		return fmt.Errorf("sqlc: failed to create materialized view %s: %w", table, err)
+	}
+	return nil
+}
+
+// Refresh recomputes the materialized view backing the repository's model.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - concurrently: PostgreSQL only; refresh without blocking readers (REFRESH ... CONCURRENTLY)
+//
+// Returns:
+//   - error: Refresh error, or an error if T is not registered with RegisterMaterializedView
+//
+// Example:
+//
+//	// Nightly job
+//	if err := statsRepo.Refresh(ctx, true); err != nil {
+//	    return err
+//	}
+//
+// Note:
+//   - CONCURRENTLY requires a unique index on the view and an already populated view
+//   - MySQL and SQLite rebuild the data into a new table and swap it in; readers keep
+//     seeing the old rows until the swap, so concurrently is ignored
+//   - On MySQL indexes added to the emulated table are not carried over by the swap
+func (r *Repository[T]) Refresh(ctx context.Context, concurrently bool) error {
+	query, err := materializedViewQuery[T]()
+	if err != nil {
+		return err
+	}
+	table := r.schema.TableName()
+
+	switch r.session.dialect.Name() {
+	case PostgreSQL.Name():
+		stmt := "REFRESH MATERIALIZED VIEW "
+		if concurrently {
+			stmt += "CONCURRENTLY "
+		}
+		_, err = r.session.Exec(ctx, stmt+table)
+	case MySQL.Name():
+		// RENAME TABLE swaps both tables atomically; MySQL DDL cannot run in a transaction
+		err = r.execAll(ctx,
+			fmt.Sprintf("DROP TABLE IF EXISTS %s__new", table),
+			fmt.Sprintf("CREATE TABLE %s__new AS %s", table, query),
+			fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s LIKE %s__new", table, table),
+			fmt.Sprintf("RENAME TABLE %s TO %s__old, %s__new TO %s", table, table, table, table),
+			fmt.Sprintf("DROP TABLE %s__old", table),
+		)
+	default:
+		// Transactional DDL: readers see either the old or the new table
+		err = r.session.Transaction(ctx, func(tx *Session) error {
+			return r.WithSession(tx).execAll(ctx,
+				fmt.Sprintf("DROP TABLE IF EXISTS %s__new", table),
+				fmt.Sprintf("CREATE TABLE %s__new AS %s", table, query),
+				fmt.Sprintf("DROP TABLE IF EXISTS %s", table),
+				fmt.Sprintf("ALTER TABLE %s__new RENAME TO %s", table, table),
+			)
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to refresh materialized view %s: %w", table, err)
+	}
+	return nil
+}
+
+// execAll executes statements in order, stopping at the first error.
+func (r *Repository[T]) execAll(ctx context.Context, stmts ...string) error {
+	for _, stmt := range stmts {
+		if _, err := r.session.Exec(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package sqlc_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// DepartmentHeadcount is a read model backed by a materialized view over members
+type DepartmentHeadcount struct {
+	DepartmentID int64 `db:"department_id"`
+	Members      int64 `db:"members"`
+}
+
+type DepartmentHeadcountSchema struct{}
+
+func (DepartmentHeadcountSchema) TableName() string { return "department_headcounts" }
+func (DepartmentHeadcountSchema) SelectColumns() []string {
+	return []string{"department_id", "members"}
+}
+func (DepartmentHeadcountSchema) InsertRow(m *DepartmentHeadcount) ([]string, []any) { return nil, nil }
+func (DepartmentHeadcountSchema) UpdateMap(m *DepartmentHeadcount) map[string]any    { return nil }
+func (DepartmentHeadcountSchema) PK(m *DepartmentHeadcount) sqlc.PK {
+	return sqlc.PK{Column: clause.Column{Name: "department_id"}}
+}
+func (DepartmentHeadcountSchema) SetPK(m *DepartmentHeadcount, val int64) {}
+func (DepartmentHeadcountSchema) AutoIncrement() bool                     { return false }
+func (DepartmentHeadcountSchema) SoftDeleteColumn() string                { return "" }
+func (DepartmentHeadcountSchema) SoftDeleteValue() any                    { return nil }
+func (DepartmentHeadcountSchema) SetDeletedAt(m *DepartmentHeadcount)     {}
+
+func init() {
+	sqlc.RegisterSchema(DepartmentHeadcountSchema{})
+	sqlc.RegisterMaterializedView[DepartmentHeadcount](
+		"SELECT department_id, COUNT(*) AS members FROM members GROUP BY department_id")
+}
+
+func TestMaterializedView(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	memberRepo := sqlc.NewRepository[Member](session)
+	headcountRepo := sqlc.NewRepository[DepartmentHeadcount](session)
+
+	addMember := func(email string, dept int) {
+		t.Helper()
+		m := &Member{Name: email, Email: email, DepartmentID: dept, CreatedAt: time.Now()}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	headcounts := func() map[int64]int64 {
+		t.Helper()
+		rows, err := headcountRepo.Query().Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		got := make(map[int64]int64)
+		for _, r := range rows {
+			got[r.DepartmentID] = r.Members
+		}
+		return got
+	}
+
+	addMember("a@test.com", 1)
+	addMember("b@test.com", 1)
+	if err := sqlc.CreateMaterializedView[DepartmentHeadcount](ctx, session); err != nil {
+		t.Fatalf("CreateMaterializedView failed: %v", err)
+	}
+	if got, want := headcounts(), map[int64]int64{1: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v after create, got %v", want, got)
+	}
+
+	// Stale until refreshed
+	addMember("c@test.com", 2)
+	if got, want := headcounts(), map[int64]int64{1: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected stale %v before refresh, got %v", want, got)
+	}
+	if err := headcountRepo.Refresh(ctx, true); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got, want := headcounts(), map[int64]int64{1: 2, 2: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v after refresh, got %v", want, got)
+	}
+
+	// Refresh is repeatable
+	if err := headcountRepo.Refresh(ctx, false); err != nil {
+		t.Fatalf("second Refresh failed: %v", err)
+	}
+
+	if err := memberRepo.Refresh(ctx, false); err == nil {
+		t.Error("expected error refreshing a model without materialized view")
+	}
+}
+
+func TestMaterializedViewSQL(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		dialect      sqlc.Dialect
+		concurrently bool
+		want         []string
+	}{
+		{
+			name:         "PostgreSQL",
+			dialect:      sqlc.PostgreSQL,
+			concurrently: true,
+			want: []string{
+				"CREATE MATERIALIZED VIEW IF NOT EXISTS department_headcounts AS SELECT department_id, COUNT(*) AS members FROM members GROUP BY department_id",
+				"REFRESH MATERIALIZED VIEW CONCURRENTLY department_headcounts",
+			},
+		},
+		{
+			name:    "MySQL",
+			dialect: sqlc.MySQL,
+			want: []string{
+				"CREATE TABLE IF NOT EXISTS department_headcounts AS SELECT department_id, COUNT(*) AS members FROM members GROUP BY department_id",
+				"DROP TABLE IF EXISTS department_headcounts__new",
+				"CREATE TABLE department_headcounts__new AS SELECT department_id, COUNT(*) AS members FROM members GROUP BY department_id",
+				"CREATE TABLE IF NOT EXISTS department_headcounts LIKE department_headcounts__new",
+				"RENAME TABLE department_headcounts TO department_headcounts__old, department_headcounts__new TO department_headcounts",
+				"DROP TABLE department_headcounts__old",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dry := sqlc.NewSession(nil, tt.dialect).DryRun()
+			if err := sqlc.CreateMaterializedView[DepartmentHeadcount](ctx, dry); err != nil {
+				t.Fatalf("CreateMaterializedView failed: %v", err)
+			}
+			if err := sqlc.NewRepository[DepartmentHeadcount](dry).Refresh(ctx, tt.concurrently); err != nil {
+				t.Fatalf("Refresh failed: %v", err)
+			}
+			var got []string
+			for _, stmt := range dry.Recorder().Statements() {
+				got = append(got, stmt.SQL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statements mismatch:\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,362 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements the SQL standard MERGE statement.
+//
+// MERGE reconciles a table with a source (in-memory models, a staging table, or a query)
+// in one statement, with an action per outcome of the match:
+//   - WHEN MATCHED [AND condition] THEN UPDATE / DELETE / DO NOTHING
+//   - WHEN NOT MATCHED [AND condition] THEN INSERT
+//
+// Unlike Upsert, which only updates on a unique index conflict, MERGE matches on any
+// columns, takes conditions per action (e.g. only apply newer versions), and can delete
+// matched rows, which suits synchronization workloads.
+//
+// MERGE is supported by dialects implementing Merger (PostgreSQL 15+). Other dialects
+// return ErrMergeUnsupported; on them, use Upsert, or Find and write in a transaction.
+//
+// Usage example:
+//
+//	// Sync products from a feed: update changed ones, delete discontinued ones, insert new ones
+//	n, err := productRepo.Merge().
+//	    Using(feed...).
+//	    On(generated.Product.SKU).
+//	    WhenMatchedDelete(clause.Expr{SQL: "src.discontinued"}).
+//	    WhenMatchedUpdate(clause.Expr{SQL: "src.updated_at > tgt.updated_at"}).
+//	    WhenNotMatchedInsert(nil).
+//	    Exec(ctx)
+package sqlc
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Aliases of the tables in a MERGE statement, for use in conditions:
+// clause.Expr{SQL: "src.version > tgt.version"}.
+const (
+	MergeTarget = "tgt" // The model's table
+	MergeSource = "src" // The source rows
+)
+
+// ErrMergeUnsupported is returned by MergeBuilder when the dialect has no MERGE statement.
+var ErrMergeUnsupported = errors.New("sqlc: MERGE is not supported by the dialect")
+
+// mergeWhen is one WHEN clause of a MERGE statement
+type mergeWhen struct {
+	matched bool              // WHEN MATCHED or WHEN NOT MATCHED
+	cond    clause.Expression // Additional AND condition (nil = none)
+	action  string            // UPDATE, DELETE, INSERT or DO NOTHING
+	columns []string          // Updated columns (nil = all but the On columns)
+}
+
+// MergeBuilder builds a MERGE statement into the table of T.
+// Create it with Repository.Merge. Like QueryBuilder it is immutable: every method
+// returns a new builder.
+type MergeBuilder[T any] struct {
+	session *Session
+	schema  Schema[T]
+
+	models     []*T   // Source rows (Using)
+	hasModels  bool   // Using was called
+	source     string // Source table or subquery (UsingTable, UsingQuery)
+	sourceArgs []any
+	on         []string
+	whens      []mergeWhen
+	err        error
+}
+
+// Merge starts a MERGE statement into the repository's table.
+//
+// Returns:
+//   - *MergeBuilder[T]: Builder; set a source, the On columns, and at least one WHEN action
+//
+// Example:
+//
+//	n, err := userRepo.Merge().
+//	    Using(users...).
+//	    On(generated.User.Email).
+//	    WhenMatchedUpdate(nil, generated.User.Name).
+//	    WhenNotMatchedInsert(nil).
+//	    Exec(ctx)
+//
+// Note:
+//   - Lifecycle hooks are not triggered, and search indexes are not updated
+//   - Soft-deleted rows match like any other row; add "tgt.deleted_at IS NULL" to the
+//     WHEN MATCHED conditions to leave them alone
+func (r *Repository[T]) Merge() *MergeBuilder[T] {
+	return &MergeBuilder[T]{session: r.session, schema: r.schema}
+}
+
+// Using sets models as the source rows, with the columns of the schema's InsertRow.
+// Each target row may match at most one source row.
+func (m *MergeBuilder[T]) Using(models ...*T) *MergeBuilder[T] {
+	nb := *m
+	nb.models, nb.hasModels = models, true
+	nb.source, nb.sourceArgs = "", nil
+	return &nb
+}
+
+// UsingTable sets a table as the source, e.g. a staging table loaded by a bulk import.
+// The table must have columns named like the InsertRow columns of the model.
+func (m *MergeBuilder[T]) UsingTable(table string) *MergeBuilder[T] {
+	nb := *m
+	nb.models, nb.hasModels = nil, false
+	nb.source, nb.sourceArgs = table, nil
+	return &nb
+}
+
+// UsingQuery sets the result of a query as the source.
+// The query must return columns named like the InsertRow columns of the model.
+//
+// Example:
+//
+//	recent := stagingRepo.Query().Where(generated.Staging.Batch.Eq(batchID))
+//	n, err := userRepo.Merge().UsingQuery(recent).On(generated.User.Email).
+//	    WhenNotMatchedInsert(nil).
+//	    Exec(ctx)
+func (m *MergeBuilder[T]) UsingQuery(sub Subquery) *MergeBuilder[T] {
+	nb := *m
+	nb.models, nb.hasModels = nil, false
+	sb, err := sub.selectBuilder()
+	if err != nil {
+		nb.err = err
+		return &nb
+	}
+	query, args, err := sb.PlaceholderFormat(sq.Question).ToSql()
+	if err != nil {
+		nb.err = err
+		return &nb
+	}
+	nb.source, nb.sourceArgs = "("+query+")", args
+	return &nb
+}
+
+// On sets the columns matching source rows to target rows: tgt.col = src.col for each column.
+func (m *MergeBuilder[T]) On(columns ...clause.Columnar) *MergeBuilder[T] {
+	nb := *m
+	nb.on = ResolveColumnNames(columns)
+	return &nb
+}
+
+// when returns a new builder with w appended
+func (m *MergeBuilder[T]) when(w mergeWhen) *MergeBuilder[T] {
+	nb := *m
+	nb.whens = append(slices.Clone(m.whens), w)
+	return &nb
+}
+
+// WhenMatchedUpdate updates matched rows with the source values of columns
+// (default: every source column but the On columns), if cond holds (nil = always).
+// WHEN clauses are evaluated in order; the first one whose condition holds applies.
+func (m *MergeBuilder[T]) WhenMatchedUpdate(cond clause.Expression, columns ...clause.Columnar) *MergeBuilder[T] {
+	return m.when(mergeWhen{matched: true, cond: cond, action: "UPDATE", columns: ResolveColumnNames(columns)})
+}
+
+// WhenMatchedDelete deletes matched rows if cond holds (nil = always).
+// Rows are deleted physically, including for soft-delete schemas.
+func (m *MergeBuilder[T]) WhenMatchedDelete(cond clause.Expression) *MergeBuilder[T] {
+	return m.when(mergeWhen{matched: true, cond: cond, action: "DELETE"})
+}
+
+// WhenMatchedDoNothing leaves matched rows unchanged if cond holds (nil = always),
+// skipping the later WHEN MATCHED clauses for them.
+func (m *MergeBuilder[T]) WhenMatchedDoNothing(cond clause.Expression) *MergeBuilder[T] {
+	return m.when(mergeWhen{matched: true, cond: cond, action: "DO NOTHING"})
+}
+
+// WhenNotMatchedInsert inserts the source rows without a matching row if cond holds (nil = always).
+func (m *MergeBuilder[T]) WhenNotMatchedInsert(cond clause.Expression) *MergeBuilder[T] {
+	return m.when(mergeWhen{cond: cond, action: "INSERT"})
+}
+
+// ToSQL returns the SQL string and arguments without executing the statement.
+//
+// Returns:
+//   - error: ErrMergeUnsupported (wrapped) if the dialect has no MERGE, or an invalid builder
+func (m *MergeBuilder[T]) ToSQL() (string, []any, error) {
+	if m.err != nil {
+		return "", nil, m.err
+	}
+	merger, ok := m.session.dialect.(Merger)
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", ErrMergeUnsupported, m.session.dialect.Name())
+	}
+	if len(m.on) == 0 {
+		return "", nil, fmt.Errorf("sqlc: Merge requires On columns")
+	}
+	if len(m.whens) == 0 {
+		return "", nil, fmt.Errorf("sqlc: Merge requires at least one WHEN clause")
+	}
+
+	// Resolve the source and its columns
+	table := m.schema.TableName()
+	cols, _ := m.schema.InsertRow(new(T))
+	source, args := m.source, slices.Clone(m.sourceArgs)
+	switch {
+	case m.hasModels:
+		rows := make([][]any, len(m.models))
+		for i, model := range m.models {
+			cols, rows[i] = m.schema.InsertRow(model)
+		}
+		s, a, err := merger.MergeRowsSource(table, cols, rows)
+		if err != nil {
+			return "", nil, fmt.Errorf("sqlc: failed to build MERGE source: %w", err)
+		}
+		source, args = s, a
+	case source == "":
+		return "", nil, fmt.Errorf("sqlc: Merge requires a source (Using, UsingTable or UsingQuery)")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "MERGE INTO %s AS %s USING %s AS %s ON ", table, MergeTarget, source, MergeSource)
+	for i, col := range m.on {
+		if i > 0 {
+			b.WriteString(" AND ")
+		}
+		fmt.Fprintf(&b, "%s.%s = %s.%s", MergeTarget, col, MergeSource, col)
+	}
+	for _, w := range m.whens {
+		if w.matched {
+			b.WriteString(" WHEN MATCHED")
+		} else {
+			b.WriteString(" WHEN NOT MATCHED")
+		}
+		if w.cond != nil {
+			condSQL, condArgs, err := w.cond.Build()
+			if err != nil {
+				return "", nil, fmt.Errorf("sqlc: failed to build MERGE condition: %w", err)
+			}
+			b.WriteString(" AND (" + condSQL + ")")
+			args = append(args, condArgs...)
+		}
+		b.WriteString(" THEN ")
+		switch w.action {
+		case "UPDATE":
+			updateCols := w.columns
+			if len(updateCols) == 0 {
+				for _, col := range cols {
+					if !slices.Contains(m.on, col) {
+						updateCols = append(updateCols, col)
+					}
+				}
+			}
+			sets := make([]string, len(updateCols))
+			for i, col := range updateCols {
+				sets[i] = col + " = " + MergeSource + "." + col
+			}
+			b.WriteString("UPDATE SET " + strings.Join(sets, ", "))
+		case "INSERT":
+			values := make([]string, len(cols))
+			for i, col := range cols {
+				values[i] = MergeSource + "." + col
+			}
+			fmt.Fprintf(&b, "INSERT (%s) VALUES (%s)", strings.Join(cols, ", "), strings.Join(values, ", "))
+		default:
+			b.WriteString(w.action)
+		}
+	}
+
+	query, err := m.session.dialect.PlaceholderFormat().ReplacePlaceholders(b.String())
+	if err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}
+
+// Exec executes the MERGE statement.
+//
+// Returns:
+//   - int64: Number of rows inserted, updated or deleted
+//   - error: ErrMergeUnsupported (wrapped) if the dialect has no MERGE, or a database error
+//
+// Note:
+//   - A target row matching more than one source row fails the statement
+//   - Using with no models is a no-op
+//   - With row policies (WithPolicies), fails with ErrPolicyDenied unless the roles of ctx
+//     grant the update, delete and create actions of the WHEN clauses on all rows
+func (m *MergeBuilder[T]) Exec(ctx context.Context) (int64, error) {
+	if err := m.policy(ctx); err != nil {
+		return 0, err
+	}
+	query, args, err := m.ToSQL()
+	if err != nil {
+		return 0, err
+	}
+	if m.hasModels && len(m.models) == 0 {
+		return 0, nil
+	}
+	result, err := m.session.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// policy fails with ErrPolicyDenied unless the roles of ctx grant every action of the
+// WHEN clauses on all rows: MERGE cannot carry the conditions of row policies.
+func (m *MergeBuilder[T]) policy(ctx context.Context) error {
+	actions := map[string]PolicyAction{"UPDATE": PolicyUpdate, "DELETE": PolicyDelete, "INSERT": PolicyCreate}
+	for _, w := range m.whens {
+		if action, ok := actions[w.action]; ok {
+			if err := policyGranted[T](ctx, m.session, action); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonRecordset encodes rows as a JSON array of objects keyed by column name,
+// in the text formats PostgreSQL parses for the columns' types.
+func jsonRecordset(columns []string, rows [][]any) (string, error) {
+	records := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		record := make(map[string]any, len(columns))
+		for j, col := range columns {
+			v, err := jsonRecordValue(row[j])
+			if err != nil {
+				return "", fmt.Errorf("column %s: %w", col, err)
+			}
+			record[col] = v
+		}
+		records[i] = record
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// jsonRecordValue converts a column value for jsonRecordset: driver.Valuer values are
+// resolved, JSON documents (e.g. JSON[T]) are embedded as is, and other byte slices are
+// encoded in bytea's hex format.
+func jsonRecordValue(v any) (any, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil, nil
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := dv.([]byte); ok && json.Valid(b) {
+			return json.RawMessage(b), nil
+		}
+		v = dv
+	}
+	if b, ok := v.([]byte); ok {
+		return `\x` + hex.EncodeToString(b), nil
+	}
+	return v, nil
+}
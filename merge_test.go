@@ -0,0 +1,113 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestMerge(t *testing.T) {
+	sqlc.RegisterSchema(SubscriberSchema{})
+	ctx := context.Background()
+	email := clause.Column{Name: "email"}
+	name := clause.Column{Name: "name"}
+
+	// MERGE is not executed: SQLite has no MERGE, the SQL is checked with the PostgreSQL dialect
+	pgRepo := func(t *testing.T) *sqlc.Repository[Subscriber] {
+		t.Helper()
+		db, _ := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		return sqlc.NewRepository[Subscriber](sqlc.NewSession(db, sqlc.PostgreSQLDialect{}))
+	}
+
+	t.Run("Models", func(t *testing.T) {
+		query, args, err := pgRepo(t).Merge().
+			Using(&Subscriber{Email: "ann@test.com", Name: "Ann"}, &Subscriber{Email: "bob@test.com", Name: "Bob"}).
+			On(email).
+			WhenMatchedDelete(clause.Expr{SQL: "src.name = ?", Vars: []any{"(deleted)"}}).
+			WhenMatchedUpdate(clause.Expr{SQL: "tgt.deleted_at IS NULL"}).
+			WhenNotMatchedInsert(nil).
+			ToSQL()
+		if err != nil {
+			t.Fatalf("ToSQL failed: %v", err)
+		}
+		want := "MERGE INTO subscribers AS tgt USING (SELECT * FROM jsonb_populate_recordset(NULL::subscribers, CAST($1 AS jsonb))) AS src" +
+			" ON tgt.email = src.email" +
+			" WHEN MATCHED AND (src.name = $2) THEN DELETE" +
+			" WHEN MATCHED AND (tgt.deleted_at IS NULL) THEN UPDATE SET name = src.name, deleted_at = src.deleted_at" +
+			" WHEN NOT MATCHED THEN INSERT (email, name, deleted_at) VALUES (src.email, src.name, src.deleted_at)"
+		if query != want {
+			t.Errorf("unexpected SQL\n got: %s\nwant: %s", query, want)
+		}
+		wantArgs := []any{
+			`[{"deleted_at":null,"email":"ann@test.com","name":"Ann"},{"deleted_at":null,"email":"bob@test.com","name":"Bob"}]`,
+			"(deleted)",
+		}
+		if !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("unexpected args %v", args)
+		}
+	})
+
+	t.Run("SourceQuery", func(t *testing.T) {
+		repo := pgRepo(t)
+		staged := repo.Query().WithTrashed().Where(clause.Eq{Column: name, Value: "Ann"})
+		query, args, err := repo.Merge().
+			UsingQuery(staged).
+			On(email).
+			WhenMatchedUpdate(nil, name).
+			WhenMatchedDoNothing(nil).
+			ToSQL()
+		if err != nil {
+			t.Fatalf("ToSQL failed: %v", err)
+		}
+		want := "MERGE INTO subscribers AS tgt USING (SELECT id, email, name, deleted_at FROM subscribers WHERE name = $1) AS src" +
+			" ON tgt.email = src.email WHEN MATCHED THEN UPDATE SET name = src.name WHEN MATCHED THEN DO NOTHING"
+		if query != want || !reflect.DeepEqual(args, []any{"Ann"}) {
+			t.Errorf("unexpected SQL %s %v", query, args)
+		}
+
+		query, _, err = repo.Merge().UsingTable("staging_subscribers").On(email, name).WhenNotMatchedInsert(nil).ToSQL()
+		if err != nil {
+			t.Fatalf("ToSQL failed: %v", err)
+		}
+		want = "MERGE INTO subscribers AS tgt USING staging_subscribers AS src ON tgt.email = src.email AND tgt.name = src.name" +
+			" WHEN NOT MATCHED THEN INSERT (email, name, deleted_at) VALUES (src.email, src.name, src.deleted_at)"
+		if query != want {
+			t.Errorf("unexpected SQL %s", query)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		merge := pgRepo(t).Merge()
+		for label, m := range map[string]*sqlc.MergeBuilder[Subscriber]{
+			"no source": merge.On(email).WhenNotMatchedInsert(nil),
+			"no on":     merge.UsingTable("staging").WhenNotMatchedInsert(nil),
+			"no when":   merge.UsingTable("staging").On(email),
+		} {
+			if _, _, err := m.ToSQL(); err == nil {
+				t.Errorf("%s: expected error", label)
+			}
+		}
+		// Using nothing is a no-op
+		if n, err := merge.Using().On(email).WhenNotMatchedInsert(nil).Exec(ctx); err != nil || n != 0 {
+			t.Errorf("unexpected result %d, %v", n, err)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		db, session := setupTestDB(t)
+		defer db.Close()
+		_, err := sqlc.NewRepository[Subscriber](session).Merge().
+			Using(&Subscriber{Email: "ann@test.com"}).
+			On(email).
+			WhenNotMatchedInsert(nil).
+			Exec(ctx)
+		if !errors.Is(err, sqlc.ErrMergeUnsupported) {
+			t.Errorf("expected ErrMergeUnsupported, got %v", err)
+		}
+	})
+}
@@ -34,7 +34,12 @@ package sqlc
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -61,6 +66,8 @@ const (
 //   - QueryCount: Counter, records total number of queries
 //   - QueryDuration: Histogram, records query latency distribution
 //   - QueryErrors: Counter, records total number of query errors
+//   - QueryCanceled / QueryTimeouts: Counters of queries killed by their context
+//   - QueryTimeRemaining: Histogram, context budget left when a query starts
 //
 // Usage scenarios:
 //   - Monitor database load and throughput
@@ -110,6 +117,44 @@ type Metrics struct {
 	//   - Identify anomaly patterns
 	//   - Set up error alerts
 	QueryErrors metric.Int64Counter
+
+	// QueryCanceled records queries that failed because their context was canceled
+	// (client went away, sibling request failed).
+	//
+	// Metric attributes:
+	//   - db.operation: Operation type
+	//   - db.system: Database type
+	//   - db.sql.table: Table of the statement (first FROM/INTO/UPDATE table, "" if unknown)
+	QueryCanceled metric.Int64Counter
+
+	// QueryTimeouts records queries that failed because their context deadline expired.
+	// Attributes as QueryCanceled.
+	//
+	// Usage:
+	//   - Together with QueryTimeRemaining, tell budget exhaustion (little time left at
+	//     start) from database slowness (ample time left, query still timed out)
+	QueryTimeouts metric.Int64Counter
+
+	// QueryTimeRemaining records the time left until the context deadline when a query
+	// starts. Only queries whose context has a deadline are recorded.
+	// Attributes as QueryCanceled.
+	//
+	// Unit: milliseconds (ms)
+	//
+	// Predefined bucket boundaries: 10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000
+	QueryTimeRemaining metric.Float64Histogram
+
+	// DualWriteDivergence records divergences between the primary and secondary
+	// databases of a DualWrite (see DualWrite), on the primary session's meter.
+	//
+	// Metric attributes:
+	//   - db.sql.table: Table of the primary repository
+	//   - sqlc.divergence: Kind of divergence (write_failed, queue_full, read_mismatch)
+	//
+	// Usage:
+	//   - Alert while migrating; zero read mismatches over time means the secondary
+	//     is ready to become the primary
+	DualWriteDivergence metric.Int64Counter
 }
 
 // ObservabilityConfig holds configuration for logging, tracing, and metrics.
@@ -194,6 +239,20 @@ type ObservabilityConfig struct {
 	//   - For production, recommend disabling or using sampling
 	//   - Slow queries and error queries are always logged
 	LogQueries bool
+
+	// QueryLogSampleRate is the fraction of queries logged at Debug level when
+	// LogQueries is true, from 0 (none) to 1 (all).
+	//
+	// Default: 1
+	//
+	// Note:
+	//   - Slow queries and error queries are always logged, whatever the rate
+	QueryLogSampleRate float64
+}
+
+// sampled reports whether a query is picked for Debug logging by QueryLogSampleRate.
+func (c *ObservabilityConfig) sampled() bool {
+	return c.QueryLogSampleRate >= 1 || rand.Float64() < c.QueryLogSampleRate
 }
 
 // defaultObservabilityConfig returns the default observability configuration.
@@ -206,6 +265,7 @@ type ObservabilityConfig struct {
 //   - Metrics: nil
 //   - SlowQueryThreshold: 200 milliseconds
 //   - LogQueries: false
+//   - QueryLogSampleRate: 1
 //
 // Returns:
 //   - *ObservabilityConfig: Default configuration instance
@@ -217,6 +277,53 @@ func defaultObservabilityConfig() *ObservabilityConfig {
 		Metrics:            nil,
 		SlowQueryThreshold: 200 * time.Millisecond,
 		LogQueries:         false,
+		QueryLogSampleRate: 1,
+	}
+}
+
+// observability returns the current observability configuration of the session.
+// Sessions derived from s (transactions, replica reads, dry runs) share it.
+func (s *Session) observability() *ObservabilityConfig {
+	return s.obs.Load()
+}
+
+// Observability returns a copy of the session's current observability configuration.
+func (s *Session) Observability() ObservabilityConfig {
+	return *s.observability()
+}
+
+// UpdateObservability changes the observability configuration of a running session,
+// e.g. to turn on verbose SQL logging during an incident without a restart.
+// The options are applied to a copy of the current configuration, which then replaces
+// it atomically: concurrent queries see either the old or the new configuration, never
+// a mix, and the change applies to transactions and other sessions derived from s too.
+//
+// Parameters:
+//   - opts: Observability options (WithLogger, WithQueryLogging, WithSlowQueryThreshold,
+//     WithQueryLogSampling, WithTracer, WithMeter, ...); other options are ignored
+//
+// Example:
+//
+//	// Log 10% of all queries for the next 15 minutes
+//	session.UpdateObservability(sqlc.WithQueryLogging(true), sqlc.WithQueryLogSampling(0.1))
+//	time.AfterFunc(15*time.Minute, func() {
+//	    session.UpdateObservability(sqlc.WithQueryLogging(false))
+//	})
+//
+// Note:
+//   - WithMeter creates its instruments again on each call; prefer setting it up front
+func (s *Session) UpdateObservability(opts ...SessionOption) {
+	for {
+		current := s.obs.Load()
+		next := *current
+		scratch := &Session{dialect: s.dialect, obs: new(atomic.Pointer[ObservabilityConfig])}
+		scratch.obs.Store(&next)
+		for _, opt := range opts {
+			opt(scratch)
+		}
+		if s.obs.CompareAndSwap(current, &next) {
+			return
+		}
 	}
 }
 
@@ -265,7 +372,7 @@ type SessionOption func(*Session)
 //   - Slow queries and error queries are always logged
 func WithLogger(logger *slog.Logger) SessionOption {
 	return func(s *Session) {
-		s.obs.Logger = logger
+		s.observability().Logger = logger
 	}
 }
 
@@ -294,7 +401,7 @@ func WithLogger(logger *slog.Logger) SessionOption {
 //   - Recommend using WithDefaultTracer() for simpler configuration
 func WithTracer(tracer trace.Tracer) SessionOption {
 	return func(s *Session) {
-		s.obs.Tracer = tracer
+		s.observability().Tracer = tracer
 	}
 }
 
@@ -317,7 +424,7 @@ func WithTracer(tracer trace.Tracer) SessionOption {
 //   - Tracer name is "github.com/arllen133/sqlc"
 func WithDefaultTracer() SessionOption {
 	return func(s *Session) {
-		s.obs.Tracer = otel.Tracer(tracerName)
+		s.observability().Tracer = otel.Tracer(tracerName)
 	}
 }
 
@@ -345,8 +452,8 @@ func WithDefaultTracer() SessionOption {
 //   - Recommend using WithDefaultMeter() for simpler configuration
 func WithMeter(meter metric.Meter) SessionOption {
 	return func(s *Session) {
-		s.obs.Meter = meter
-		s.obs.Metrics = initMetrics(meter)
+		s.observability().Meter = meter
+		s.observability().Metrics = initMetrics(meter)
 	}
 }
 
@@ -392,8 +499,8 @@ func WithMeter(meter metric.Meter) SessionOption {
 func WithDefaultMeter() SessionOption {
 	return func(s *Session) {
 		meter := otel.Meter(meterName)
-		s.obs.Meter = meter
-		s.obs.Metrics = initMetrics(meter)
+		s.observability().Meter = meter
+		s.observability().Metrics = initMetrics(meter)
 	}
 }
 
@@ -410,6 +517,10 @@ func WithDefaultMeter() SessionOption {
 //   - sqlc.query.count (Int64Counter): Query counter
 //   - sqlc.query.duration (Float64Histogram): Latency histogram
 //   - sqlc.query.errors (Int64Counter): Error counter
+//   - sqlc.query.canceled (Int64Counter): Queries aborted by context cancellation
+//   - sqlc.query.timeouts (Int64Counter): Queries aborted by context deadline
+//   - sqlc.query.time_remaining (Float64Histogram): Context budget left at query start
+//   - sqlc.dualwrite.divergence (Int64Counter): Primary/secondary divergences of DualWrite
 //
 // Note:
 //   - If metric creation fails, errors are ignored (uses no-op implementation)
@@ -438,10 +549,37 @@ func initMetrics(meter metric.Meter) *Metrics {
 		metric.WithUnit("{error}"),
 	)
 
+	// Create cancellation and timeout counters
+	queryCanceled, _ := meter.Int64Counter("sqlc.query.canceled",
+		metric.WithDescription("Number of queries aborted because their context was canceled"),
+		metric.WithUnit("{query}"),
+	)
+	queryTimeouts, _ := meter.Int64Counter("sqlc.query.timeouts",
+		metric.WithDescription("Number of queries aborted because their context deadline expired"),
+		metric.WithUnit("{query}"),
+	)
+
+	// Create remaining budget histogram
+	queryTimeRemaining, _ := meter.Float64Histogram("sqlc.query.time_remaining",
+		metric.WithDescription("Time left until the context deadline when a query starts, in milliseconds"),
+		metric.WithUnit("ms"),
+		metric.WithExplicitBucketBoundaries(10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000),
+	)
+
+	// Create dual-write divergence counter
+	dualWriteDivergence, _ := meter.Int64Counter("sqlc.dualwrite.divergence",
+		metric.WithDescription("Number of divergences between the primary and secondary database of a dual write"),
+		metric.WithUnit("{divergence}"),
+	)
+
 	return &Metrics{
-		QueryCount:    queryCount,
-		QueryDuration: queryDuration,
-		QueryErrors:   queryErrors,
+		QueryCount:          queryCount,
+		QueryDuration:       queryDuration,
+		QueryErrors:         queryErrors,
+		QueryCanceled:       queryCanceled,
+		QueryTimeouts:       queryTimeouts,
+		QueryTimeRemaining:  queryTimeRemaining,
+		DualWriteDivergence: dualWriteDivergence,
 	}
 }
 
@@ -471,7 +609,7 @@ func initMetrics(meter metric.Meter) *Metrics {
 //   - Default threshold is 200ms
 func WithSlowQueryThreshold(d time.Duration) SessionOption {
 	return func(s *Session) {
-		s.obs.SlowQueryThreshold = d
+		s.observability().SlowQueryThreshold = d
 	}
 }
 
@@ -506,7 +644,30 @@ func WithSlowQueryThreshold(d time.Duration) SessionOption {
 //   - Requires Logger configuration to take effect
 func WithQueryLogging(enabled bool) SessionOption {
 	return func(s *Session) {
-		s.obs.LogQueries = enabled
+		s.observability().LogQueries = enabled
+	}
+}
+
+// WithQueryLogSampling logs only a fraction of the queries logged by WithQueryLogging,
+// picked at random, keeping verbose logging affordable on busy services.
+//
+// Parameter:
+//   - rate: Fraction of queries to log, from 0 (none) to 1 (all, the default)
+//
+// Usage example:
+//
+//	// Log about 1 query in 100
+//	session := sqlc.NewSession(db, sqlc.MySQL{},
+//	    sqlc.WithLogger(slog.Default()),
+//	    sqlc.WithQueryLogging(true),
+//	    sqlc.WithQueryLogSampling(0.01),
+//	)
+//
+// Note:
+//   - Slow queries and failed queries are always logged
+func WithQueryLogSampling(rate float64) SessionOption {
+	return func(s *Session) {
+		s.observability().QueryLogSampleRate = rate
 	}
 }
 
@@ -588,16 +749,57 @@ func (w spanWrapper) SetAttributes(kv ...attribute.KeyValue) {
 //	}
 func (s *Session) startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, spanWrapper) {
 	// Check if tracer is configured
-	if s.obs.Tracer == nil {
+	tracer := s.observability().Tracer
+	if tracer == nil {
 		// Not configured, return nil span wrapper
 		return ctx, spanWrapper{nil}
 	}
 
+	// Append the business operation name, if any
+	if op := OperationName(ctx); op != "" {
+		name += " " + op
+		opts = append(opts, trace.WithAttributes(attribute.String(operationNameAttr, op)))
+	}
+
 	// Start new span
-	ctx, span := s.obs.Tracer.Start(ctx, name, opts...)
+	ctx, span := tracer.Start(ctx, name, opts...)
 	return ctx, spanWrapper{span}
 }
 
+// operationNameAttr is the span and metric attribute carrying the operation name set by WithOperationName.
+const operationNameAttr = "sqlc.operation_name"
+
+// operationNameKey is the context key of the operation name.
+type operationNameKey struct{}
+
+// WithOperationName returns a context that tags database operations with a
+// business-level operation name. Operations executed with the context carry it:
+//   - Span names get the name as a suffix (e.g. "sqlc.Exec checkout.create_order")
+//   - Spans and metrics get a "sqlc.operation_name" attribute
+//   - Query logs get an "operation_name" field
+//
+// Parameters:
+//   - ctx: Parent context
+//   - name: Operation name, e.g. "checkout.create_order"
+//
+// Example:
+//
+//	ctx = sqlc.WithOperationName(ctx, "checkout.create_order")
+//	err := orderRepo.Create(ctx, order)
+//
+// Note:
+//   - The name becomes a metric attribute; use a fixed set of names, never IDs or user input
+//   - An inner WithOperationName replaces the outer name
+func WithOperationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, name)
+}
+
+// OperationName returns the operation name set by WithOperationName, or "" if none is set.
+func OperationName(ctx context.Context) string {
+	name, _ := ctx.Value(operationNameKey{}).(string)
+	return name
+}
+
 // recordMetrics records query metrics.
 // If metrics are not enabled (Metrics is nil), this is a no-op.
 //
@@ -625,25 +827,98 @@ func (s *Session) startSpan(ctx context.Context, name string, opts ...trace.Span
 //	s.recordMetrics(ctx, "select", duration, err)
 func (s *Session) recordMetrics(ctx context.Context, operation string, duration time.Duration, err error) {
 	// Check if metrics are configured
-	if s.obs.Metrics == nil {
+	metrics := s.observability().Metrics
+	if metrics == nil {
 		return
 	}
 
 	// Prepare metric attributes
-	attrs := metric.WithAttributes(
+	kv := []attribute.KeyValue{
 		attribute.String("db.operation", operation),
 		attribute.String("db.system", s.dialect.Name()),
-	)
+	}
+	if op := OperationName(ctx); op != "" {
+		kv = append(kv, attribute.String(operationNameAttr, op))
+	}
+	attrs := metric.WithAttributes(kv...)
 
 	// Record query count (increment by 1 for each query)
-	s.obs.Metrics.QueryCount.Add(ctx, 1, attrs)
+	metrics.QueryCount.Add(ctx, 1, attrs)
 
 	// Record query latency (milliseconds)
-	s.obs.Metrics.QueryDuration.Record(ctx, float64(duration.Milliseconds()), attrs)
+	metrics.QueryDuration.Record(ctx, float64(duration.Milliseconds()), attrs)
 
 	// If error exists, record error count
 	if err != nil {
-		s.obs.Metrics.QueryErrors.Add(ctx, 1, attrs)
+		metrics.QueryErrors.Add(ctx, 1, attrs)
+	}
+}
+
+// statementTableRegexp matches the first table introduced by FROM, INTO or UPDATE
+var statementTableRegexp = regexp.MustCompile("(?i)\\b(?:FROM|INTO|UPDATE)\\s+((?:[`\"]?\\w+[`\"]?\\.)?[`\"]?\\w+[`\"]?)")
+
+// statementTable returns the table a statement operates on, without quotes, or "" if
+// none is found (e.g. a FROM subquery).
+func statementTable(query string) string {
+	m := statementTableRegexp.FindStringSubmatch(query)
+	if m == nil {
+		return ""
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(m[1], "`", ""), `"`, "")
+}
+
+// recordContextMetrics records the context budget left at query start and counts queries
+// aborted by their context. If metrics are not enabled (Metrics is nil), this is a no-op.
+//
+// Parameters:
+//   - ctx: Context the query ran with
+//   - operation: Operation type (select, exec, query, etc.)
+//   - query: SQL statement, used for the table attribute
+//   - remaining: Time left until the deadline when the query started
+//   - hasDeadline: Whether ctx has a deadline
+//   - err: Query error (if any)
+//
+// Note:
+//   - Drivers do not always wrap context errors (e.g. PostgreSQL reports "canceling statement
+//     due to user request"), so a failed query is attributed to ctx.Err() when it is set
+func (s *Session) recordContextMetrics(ctx context.Context, operation, query string, remaining time.Duration, hasDeadline bool, err error) {
+	metrics := s.observability().Metrics
+	if metrics == nil {
+		return
+	}
+
+	var cause error
+	if err != nil {
+		cause = ctx.Err()
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			cause = context.DeadlineExceeded
+		case errors.Is(err, context.Canceled):
+			cause = context.Canceled
+		}
+	}
+	if !hasDeadline && cause == nil {
+		return
+	}
+
+	kv := []attribute.KeyValue{
+		attribute.String("db.operation", operation),
+		attribute.String("db.system", s.dialect.Name()),
+		attribute.String("db.sql.table", statementTable(query)),
+	}
+	if op := OperationName(ctx); op != "" {
+		kv = append(kv, attribute.String(operationNameAttr, op))
+	}
+	attrs := metric.WithAttributes(kv...)
+
+	if hasDeadline {
+		metrics.QueryTimeRemaining.Record(ctx, float64(max(remaining, 0).Milliseconds()), attrs)
+	}
+	switch cause {
+	case context.Canceled:
+		metrics.QueryCanceled.Add(ctx, 1, attrs)
+	case context.DeadlineExceeded:
+		metrics.QueryTimeouts.Add(ctx, 1, attrs)
 	}
 }
 
@@ -677,7 +952,8 @@ func (s *Session) recordMetrics(ctx context.Context, operation string, duration
 //	s.logQuery(ctx, "select", query, duration, err)
 func (s *Session) logQuery(ctx context.Context, operation, query string, duration time.Duration, err error) {
 	// Check if logger is configured
-	if s.obs.Logger == nil {
+	obs := s.observability()
+	if obs.Logger == nil {
 		return
 	}
 
@@ -686,27 +962,30 @@ func (s *Session) logQuery(ctx context.Context, operation, query string, duratio
 		slog.String("operation", operation),
 		slog.Duration("duration", duration),
 	}
+	if op := OperationName(ctx); op != "" {
+		attrs = append(attrs, slog.String("operation_name", op))
+	}
 
 	// If query logging is enabled, add SQL statement
-	if s.obs.LogQueries {
+	if obs.LogQueries {
 		attrs = append(attrs, slog.String("query", query))
 	}
 
 	// Error case: Log at Error level
 	if err != nil {
-		s.obs.Logger.LogAttrs(ctx, slog.LevelError, "query failed",
+		obs.Logger.LogAttrs(ctx, slog.LevelError, "query failed",
 			append(attrs, slog.String("error", err.Error()))...)
 		return
 	}
 
 	// Slow query: Log at Warn level
-	if duration > s.obs.SlowQueryThreshold {
-		s.obs.Logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+	if duration > obs.SlowQueryThreshold {
+		obs.Logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
 		return
 	}
 
-	// Normal query: Log at Debug level (requires LogQueries = true)
-	if s.obs.LogQueries {
-		s.obs.Logger.LogAttrs(ctx, slog.LevelDebug, "query executed", attrs...)
+	// Normal query: Log at Debug level (requires LogQueries = true), sampled
+	if obs.LogQueries && obs.sampled() {
+		obs.Logger.LogAttrs(ctx, slog.LevelDebug, "query executed", attrs...)
 	}
 }
@@ -5,12 +5,18 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/arllen133/sqlc"
 	"github.com/arllen133/sqlc/clause"
 	_ "github.com/mattn/go-sqlite3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // ObsTestModel is a simple model for observability tests
@@ -218,3 +224,214 @@ func TestCombinedObservability(t *testing.T) {
 		t.Error("expected some log output")
 	}
 }
+
+// recordingTracer records the names and start attributes of its spans.
+type recordingTracer struct {
+	noop.Tracer
+	names []string
+	attrs []attribute.KeyValue
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	r.names = append(r.names, name)
+	cfg := trace.NewSpanStartConfig(opts...)
+	r.attrs = append(r.attrs, cfg.Attributes()...)
+	return r.Tracer.Start(ctx, name, opts...)
+}
+
+func TestWithOperationName(t *testing.T) {
+	db, cleanup := setupObsTestDB(t)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	tracer := &recordingTracer{}
+	sess := sqlc.NewSession(db, &sqlc.SQLiteDialect{},
+		sqlc.WithTracer(tracer),
+		sqlc.WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+		sqlc.WithQueryLogging(true),
+	)
+	repo := sqlc.NewRepository[ObsTestModel](sess)
+
+	ctx := sqlc.WithOperationName(context.Background(), "checkout.create_order")
+	if got := sqlc.OperationName(ctx); got != "checkout.create_order" {
+		t.Errorf("expected operation name, got %q", got)
+	}
+	if err := repo.Create(ctx, &ObsTestModel{Name: "Test"}); err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+
+	if len(tracer.names) != 1 || tracer.names[0] != "sqlc.Exec checkout.create_order" {
+		t.Errorf("expected suffixed span name, got %v", tracer.names)
+	}
+	want := attribute.String("sqlc.operation_name", "checkout.create_order")
+	if len(tracer.attrs) != 1 || tracer.attrs[0] != want {
+		t.Errorf("expected operation name attribute, got %v", tracer.attrs)
+	}
+	if !strings.Contains(buf.String(), "operation_name=checkout.create_order") {
+		t.Errorf("expected operation name in log, got %s", buf.String())
+	}
+
+	// Untagged operations keep the generic span name
+	tracer.names = nil
+	if _, err := repo.Query().Find(context.Background()); err != nil {
+		t.Fatalf("failed to find: %v", err)
+	}
+	if len(tracer.names) != 1 || strings.Contains(tracer.names[0], " ") {
+		t.Errorf("expected generic span name, got %v", tracer.names)
+	}
+}
+
+// recordingMeter records the values of its counters and histograms by instrument name.
+type recordingMeter struct {
+	metricnoop.Meter
+	values map[string][]float64
+	attrs  map[string][]attribute.Set
+}
+
+type recordingCounter struct {
+	metricnoop.Int64Counter
+	name string
+	m    *recordingMeter
+}
+
+func (c recordingCounter) Add(ctx context.Context, v int64, opts ...metric.AddOption) {
+	c.m.record(c.name, float64(v), metric.NewAddConfig(opts).Attributes())
+}
+
+type recordingHistogram struct {
+	metricnoop.Float64Histogram
+	name string
+	m    *recordingMeter
+}
+
+func (h recordingHistogram) Record(ctx context.Context, v float64, opts ...metric.RecordOption) {
+	h.m.record(h.name, v, metric.NewRecordConfig(opts).Attributes())
+}
+
+func (r *recordingMeter) record(name string, v float64, attrs attribute.Set) {
+	r.values[name] = append(r.values[name], v)
+	r.attrs[name] = append(r.attrs[name], attrs)
+}
+
+func (r *recordingMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return recordingCounter{name: name, m: r}, nil
+}
+
+func (r *recordingMeter) Float64Histogram(name string, opts ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return recordingHistogram{name: name, m: r}, nil
+}
+
+func TestQueryContextMetrics(t *testing.T) {
+	db, cleanup := setupObsTestDB(t)
+	defer cleanup()
+
+	meter := &recordingMeter{values: make(map[string][]float64), attrs: make(map[string][]attribute.Set)}
+	sess := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithMeter(meter))
+	repo := sqlc.NewRepository[ObsTestModel](sess)
+
+	// No deadline, no failure: nothing recorded
+	if err := repo.Create(context.Background(), &ObsTestModel{Name: "a"}); err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if n := len(meter.values["sqlc.query.time_remaining"]); n != 0 {
+		t.Errorf("expected no budget samples without deadline, got %d", n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := repo.Query().Find(ctx); err != nil {
+		t.Fatalf("failed to find: %v", err)
+	}
+	remaining := meter.values["sqlc.query.time_remaining"]
+	if len(remaining) != 1 || remaining[0] <= 50000 || remaining[0] > 60000 {
+		t.Errorf("expected ~60000ms remaining, got %v", remaining)
+	}
+	table, _ := meter.attrs["sqlc.query.time_remaining"][0].Value("db.sql.table")
+	if table.AsString() != "obs_test" {
+		t.Errorf("expected table attribute obs_test, got %q", table.AsString())
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := repo.Query().Find(canceled); err == nil {
+		t.Fatal("expected error for canceled context")
+	}
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	if err := repo.Create(expired, &ObsTestModel{Name: "b"}); err == nil {
+		t.Fatal("expected error for expired context")
+	}
+
+	if got := meter.values["sqlc.query.canceled"]; len(got) != 1 {
+		t.Errorf("expected 1 canceled query, got %v", got)
+	}
+	if got := meter.values["sqlc.query.timeouts"]; len(got) != 1 {
+		t.Errorf("expected 1 timed-out query, got %v", got)
+	} else if op, _ := meter.attrs["sqlc.query.timeouts"][0].Value("db.operation"); op.AsString() != "exec" {
+		t.Errorf("expected exec operation, got %q", op.AsString())
+	}
+	// The expired context is recorded with no budget left
+	if remaining := meter.values["sqlc.query.time_remaining"]; remaining[len(remaining)-1] != 0 {
+		t.Errorf("expected 0ms remaining for expired context, got %v", remaining)
+	}
+}
+
+func TestUpdateObservability(t *testing.T) {
+	db, cleanup := setupObsTestDB(t)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	sess := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithLogger(logger))
+	repo := sqlc.NewRepository[ObsTestModel](sess)
+	ctx := context.Background()
+	executed := func() int {
+		t.Helper()
+		buf.Reset()
+		for range 20 {
+			if _, err := repo.Query().Find(ctx); err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+		}
+		return strings.Count(buf.String(), "query executed")
+	}
+
+	if n := executed(); n != 0 {
+		t.Errorf("expected no query logs by default, got %d", n)
+	}
+
+	// A transaction started before the update sees it too
+	tx, err := sess.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	sess.UpdateObservability(sqlc.WithQueryLogging(true))
+	buf.Reset()
+	if _, err := sqlc.NewRepository[ObsTestModel](tx).Query().Find(ctx); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "query executed") {
+		t.Error("expected the transaction's query logged")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+
+	if n := executed(); n != 20 {
+		t.Errorf("expected 20 query logs, got %d", n)
+	}
+
+	sess.UpdateObservability(sqlc.WithQueryLogSampling(0))
+	if n := executed(); n != 0 {
+		t.Errorf("expected sampled out query logs, got %d", n)
+	}
+
+	sess.UpdateObservability(sqlc.WithSlowQueryThreshold(time.Nanosecond))
+	if n := executed(); n != 0 || !strings.Contains(buf.String(), "slow query") {
+		t.Errorf("expected slow query warnings regardless of sampling, got %s", buf.String())
+	}
+
+	if cfg := sess.Observability(); !cfg.LogQueries || cfg.QueryLogSampleRate != 0 || cfg.SlowQueryThreshold != time.Nanosecond {
+		t.Errorf("unexpected configuration %+v", cfg)
+	}
+}
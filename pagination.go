@@ -0,0 +1,171 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements page-number pagination with validated page inputs.
+//
+// Page translates a page number and page size, typically taken straight from request
+// parameters, into LIMIT/OFFSET. Invalid inputs (page < 1, perPage < 1 or above the
+// session's maximum) fail the query with ErrInvalidPage instead of reaching the database
+// as LIMIT 0 or a page of a million rows.
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.MySQL, sqlc.WithMaxPerPage(50))
+//
+//	result, err := userRepo.Query().
+//	    Where(generated.User.Active.Eq(true)).
+//	    OrderBy(generated.User.ID.Asc()).
+//	    Page(page, perPage).
+//	    Paginate(ctx)
+//	if errors.Is(err, sqlc.ErrInvalidPage) {
+//	    // 400 Bad Request
+//	}
+//	fmt.Println(result.Items, result.Total, result.TotalPages, result.HasNext())
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// DefaultMaxPerPage is the largest page size accepted by Page unless changed with WithMaxPerPage.
+const DefaultMaxPerPage = 100
+
+// ErrInvalidPage is returned by queries whose Page inputs are out of bounds.
+var ErrInvalidPage = errors.New("sqlc: invalid page")
+
+// WithMaxPerPage sets the largest page size accepted by Page for the session and its
+// transaction sessions.
+//
+// Parameters:
+//   - n: Maximum page size; values <= 0 restore DefaultMaxPerPage
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.MySQL, sqlc.WithMaxPerPage(500))
+func WithMaxPerPage(n int) SessionOption {
+	return func(s *Session) {
+		s.maxPerPage = max(0, n)
+	}
+}
+
+// pageRequest is the page selected via Page()
+type pageRequest struct {
+	page    int
+	perPage int
+}
+
+// Page selects page number page (starting at 1) of perPage records, rendered as
+// LIMIT perPage OFFSET (page-1)*perPage. The page is recorded for Paginate.
+//
+// Parameters:
+//   - page: Page number, starting at 1
+//   - perPage: Page size, between 1 and the session's maximum (see WithMaxPerPage)
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	// Page 3 of 20 users: LIMIT 20 OFFSET 40
+//	users, err := userRepo.Query().OrderBy(generated.User.ID.Asc()).Page(3, 20).Find(ctx)
+//
+// Note:
+//   - Out-of-bounds inputs fail the query with an error wrapping ErrInvalidPage
+//   - The primary key is appended to ORDER BY as a tie-breaker, so rows with equal sort
+//     values cannot move between pages; disable with TieBreaker(false)
+//   - Limit/Offset called after Page override its LIMIT/OFFSET, but not the page recorded for Paginate
+func (q *QueryBuilder[T]) Page(page, perPage int) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+
+	maxPerPage := q.session.maxPerPage
+	if maxPerPage == 0 {
+		maxPerPage = DefaultMaxPerPage
+	}
+	switch {
+	case page < 1:
+		q.err = fmt.Errorf("%w: page %d, must be at least 1", ErrInvalidPage, page)
+	case perPage < 1 || perPage > maxPerPage:
+		q.err = fmt.Errorf("%w: per page %d, must be between 1 and %d", ErrInvalidPage, perPage, maxPerPage)
+	case page-1 > math.MaxInt64/perPage:
+		q.err = fmt.Errorf("%w: page %d is too large", ErrInvalidPage, page)
+	}
+	if q.err != nil {
+		return q
+	}
+
+	q.page = &pageRequest{page: page, perPage: perPage}
+	if !q.tieBreakSet {
+		q.tieBreak = true
+	}
+	q.builder = q.builder.Limit(uint64(perPage)).Offset(uint64(page-1) * uint64(perPage))
+	return q
+}
+
+// PageResult is a page of records returned by Paginate.
+type PageResult[T any] struct {
+	Items      []*T  // Records of the page
+	Page       int   // Page number, starting at 1
+	PerPage    int   // Page size
+	Total      int64 // Number of records matching the query on all pages
+	TotalPages int   // Number of pages (0 when no record matches)
+}
+
+// HasNext reports whether a page follows this one.
+func (p *PageResult[T]) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+// HasPrev reports whether a page precedes this one.
+func (p *PageResult[T]) HasPrev() bool {
+	return p.Page > 1
+}
+
+// Paginate executes the page selected with Page, and counts the records on all pages.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//
+// Returns:
+//   - *PageResult[T]: The page's records and page metadata
+//   - error: ErrInvalidPage if Page was not called or its inputs are invalid, or a query error
+//
+// Example:
+//
+//	result, err := userRepo.Query().OrderBy(generated.User.ID.Asc()).Page(2, 20).Paginate(ctx)
+//	// result.Items: users 21-40, result.Total: 95, result.TotalPages: 5
+//
+// Note:
+//   - Runs two queries: COUNT(*) without LIMIT/OFFSET, then the page itself
+//   - Pages past the last one return no items, not an error
+func (q *QueryBuilder[T]) Paginate(ctx context.Context) (*PageResult[T], error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.page == nil {
+		return nil, fmt.Errorf("%w: Paginate requires Page()", ErrInvalidPage)
+	}
+
+	total, err := q.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &PageResult[T]{
+		Page:       q.page.page,
+		PerPage:    q.page.perPage,
+		Total:      total,
+		TotalPages: int((total + int64(q.page.perPage) - 1) / int64(q.page.perPage)),
+	}
+	if total <= int64(q.page.page-1)*int64(q.page.perPage) {
+		result.Items = []*T{}
+		return result, nil // Past the last page: skip the query
+	}
+
+	if result.Items, err = q.Find(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestPage(t *testing.T) {
+	ctx := context.Background()
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	repo := sqlc.NewRepository[Member](session)
+
+	for i := 1; i <= 45; i++ {
+		m := &Member{Name: fmt.Sprintf("m%02d", i), Email: fmt.Sprintf("m%02d@test.com", i), CreatedAt: time.Now()}
+		if err := repo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	byID := func() *sqlc.QueryBuilder[Member] {
+		return repo.Query().OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}})
+	}
+
+	t.Run("LimitOffset", func(t *testing.T) {
+		sql, _, err := byID().Page(3, 20).ToSQL()
+		if err != nil {
+			t.Fatalf("ToSQL failed: %v", err)
+		}
+		if !strings.Contains(sql, "LIMIT 20 OFFSET 40") {
+			t.Errorf("sql = %s, want LIMIT 20 OFFSET 40", sql)
+		}
+	})
+
+	t.Run("Paginate", func(t *testing.T) {
+		tests := []struct {
+			page, perPage    int
+			wantFirst        string
+			wantLen          int
+			wantNext, wantPv bool
+		}{
+			{page: 1, perPage: 20, wantFirst: "m01", wantLen: 20, wantNext: true},
+			{page: 3, perPage: 20, wantFirst: "m41", wantLen: 5, wantPv: true},
+			{page: 4, perPage: 20, wantLen: 0, wantPv: true},
+		}
+		for _, tt := range tests {
+			res, err := byID().Page(tt.page, tt.perPage).Paginate(ctx)
+			if err != nil {
+				t.Fatalf("Paginate(%d, %d) failed: %v", tt.page, tt.perPage, err)
+			}
+			if res.Total != 45 || res.TotalPages != 3 || res.Page != tt.page || res.PerPage != tt.perPage {
+				t.Errorf("page %d: metadata = %+v", tt.page, res)
+			}
+			if len(res.Items) != tt.wantLen || (tt.wantLen > 0 && res.Items[0].Name != tt.wantFirst) {
+				t.Errorf("page %d: got %d items, want %d starting at %s", tt.page, len(res.Items), tt.wantLen, tt.wantFirst)
+			}
+			if res.HasNext() != tt.wantNext || res.HasPrev() != tt.wantPv {
+				t.Errorf("page %d: HasNext=%v HasPrev=%v", tt.page, res.HasNext(), res.HasPrev())
+			}
+		}
+	})
+
+	t.Run("InvalidInputs", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			page, perPage int
+		}{
+			{"ZeroPage", 0, 10},
+			{"NegativePage", -1, 10},
+			{"ZeroPerPage", 1, 0},
+			{"AboveDefaultMax", 1, sqlc.DefaultMaxPerPage + 1},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := repo.Query().Page(tt.page, tt.perPage).Find(ctx)
+				if !errors.Is(err, sqlc.ErrInvalidPage) {
+					t.Errorf("err = %v, want ErrInvalidPage", err)
+				}
+			})
+		}
+	})
+
+	t.Run("PaginateWithoutPage", func(t *testing.T) {
+		if _, err := repo.Query().Paginate(ctx); !errors.Is(err, sqlc.ErrInvalidPage) {
+			t.Errorf("err = %v, want ErrInvalidPage", err)
+		}
+	})
+
+	t.Run("TieBreaker", func(t *testing.T) {
+		byLevel := clause.OrderByColumn{Column: clause.Column{Name: "level"}}
+		tests := []struct {
+			name      string
+			query     *sqlc.QueryBuilder[Member]
+			wantOrder string
+		}{
+			{"PageDefault", repo.Query().OrderBy(byLevel).Page(1, 10), "ORDER BY level, id LIMIT"},
+			{"PageDisabled", repo.Query().OrderBy(byLevel).TieBreaker(false).Page(1, 10), "ORDER BY level LIMIT"},
+			{"PageUnsorted", repo.Query().Page(1, 10), "ORDER BY id LIMIT"},
+			{"AlreadyByPK", byID().Page(1, 10), "ORDER BY id LIMIT"},
+			{"Explicit", repo.Query().OrderBy(byLevel).TieBreaker(true), "ORDER BY level, id"},
+			{"Joined", repo.Query().OrderBy(byLevel).TieBreaker(true).LeftJoin(DeptSchema{}, sqlc.On(clause.Column{Name: "department_id"}, clause.Column{Name: "id"})), "ORDER BY level, members.id"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sql, _, err := tt.query.ToSQL()
+				if err != nil {
+					t.Fatalf("ToSQL failed: %v", err)
+				}
+				if !strings.Contains(sql, tt.wantOrder) {
+					t.Errorf("sql = %s, want %q", sql, tt.wantOrder)
+				}
+			})
+		}
+
+		// Off by default outside Page, and never for unsorted queries
+		for _, q := range []*sqlc.QueryBuilder[Member]{repo.Query().OrderBy(byLevel), repo.Query().Unordered().TieBreaker(true)} {
+			sql, _, _ := q.ToSQL()
+			if strings.Contains(sql, "level, id") || strings.Contains(sql, "ORDER BY id") {
+				t.Errorf("unexpected tie-breaker: %s", sql)
+			}
+		}
+
+		// All rows share the level: pages still cover every row exactly once
+		seen := map[int64]bool{}
+		for page := 1; page <= 5; page++ {
+			items, err := repo.Query().OrderBy(byLevel).Page(page, 10).Find(ctx)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			for _, m := range items {
+				if seen[m.ID] {
+					t.Errorf("member %d on two pages", m.ID)
+				}
+				seen[m.ID] = true
+			}
+		}
+		if len(seen) != 45 {
+			t.Errorf("pages covered %d members, want 45", len(seen))
+		}
+	})
+
+	t.Run("SessionMaximum", func(t *testing.T) {
+		limited := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithMaxPerPage(10))
+		q := sqlc.NewRepository[Member](limited).Query()
+		if _, err := q.Page(1, 11).Find(ctx); !errors.Is(err, sqlc.ErrInvalidPage) {
+			t.Errorf("err = %v, want ErrInvalidPage above the session maximum", err)
+		}
+		if items, err := q.Page(1, 10).Find(ctx); err != nil || len(items) != 10 {
+			t.Errorf("Find = %d items, %v; want 10", len(items), err)
+		}
+
+		// Transaction sessions inherit the maximum
+		err := limited.Transaction(ctx, func(tx *sqlc.Session) error {
+			_, err := sqlc.NewRepository[Member](tx).Query().Page(1, 11).Find(ctx)
+			return err
+		})
+		if !errors.Is(err, sqlc.ErrInvalidPage) {
+			t.Errorf("tx err = %v, want ErrInvalidPage", err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements reads into partial structs: column subsets of a model.
+//
+// Frequent narrow reads (auth lookups, list views) often need a handful of columns of a
+// wide model. Partials generated from the Partials config select and scan only their own
+// columns, so heavy columns (bios, JSON blobs) are never transferred.
+//
+// Usage example:
+//
+//	// config.go: Partials: map[string]string{"User": "Identity: id, username, password_hash"}
+//	ident, err := sqlc.FirstPartial[generated.UserIdentity](ctx,
+//	    userRepo.Query().Where(generated.User.Username.Eq(name)))
+package sqlc
+
+import (
+	"context"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Partial is implemented by column subset structs of a model, usually generated by sqlcli.
+// Fields map to columns through db tags, like models.
+type Partial interface {
+	// SelectColumns returns the columns of the partial, in field order
+	SelectColumns() []string
+}
+
+// FindPartial executes q selecting only the columns of partial P and returns the matching rows as P values.
+//
+// Type parameters:
+//   - P: Partial struct (e.g. generated.UserIdentity)
+//   - T: Model type of the query, inferred from q
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - q: Query whose conditions, ordering and limits apply
+//
+// Returns:
+//   - []P: Matching rows; empty slice if none
+//   - error: Query error
+//
+// Example:
+//
+//	cards, err := sqlc.FindPartial[generated.UserCard](ctx,
+//	    userRepo.Query().Where(generated.User.TeamID.Eq(teamID)).Limit(50))
+//
+// Note:
+//   - P's columns replace any Select/SelectExpr of q
+//   - Columns are qualified with the model's table when q has joins
+func FindPartial[P Partial, T any](ctx context.Context, q *QueryBuilder[T]) ([]P, error) {
+	var p P
+	q = q.Clone()
+	q.columns = nil
+	q.selectExprs = nil
+	for _, col := range p.SelectColumns() {
+		column := clause.Column{Name: col}
+		if q.hasJoin {
+			column.Table = q.table
+		}
+		q.columns = append(q.columns, column.ColumnName())
+	}
+
+	results := []P{}
+	if err := q.Scan(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FirstPartial is like QueryBuilder.First but returns partial P:
+// the first matching row ordered by primary key, or ErrNotFound.
+//
+// Example:
+//
+//	ident, err := sqlc.FirstPartial[generated.UserIdentity](ctx,
+//	    userRepo.Query().Where(generated.User.Email.Eq(email)))
+//	if errors.Is(err, sqlc.ErrNotFound) {
+//	    return ErrInvalidCredentials
+//	}
+func FirstPartial[P Partial, T any](ctx context.Context, q *QueryBuilder[T]) (*P, error) {
+	pk := q.schema.PK(nil).Column
+	if pk.Table == "" {
+		pk.Table = q.table
+	}
+	results, err := FindPartial[P](ctx, q.OrderBy(clause.OrderByColumn{Column: pk}).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+	return &results[0], nil
+}
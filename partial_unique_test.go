@@ -0,0 +1,155 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Subscriber Model: email is unique among records not soft-deleted
+type Subscriber struct {
+	ID        int64      `db:"id,primaryKey,autoIncrement"`
+	Email     string     `db:"email"`
+	Name      string     `db:"name"`
+	DeletedAt *time.Time `db:"deleted_at"`
+}
+
+type SubscriberSchema struct{}
+
+func (SubscriberSchema) TableName() string { return "subscribers" }
+func (SubscriberSchema) SelectColumns() []string {
+	return []string{"id", "email", "name", "deleted_at"}
+}
+func (SubscriberSchema) InsertRow(m *Subscriber) ([]string, []any) {
+	return []string{"email", "name", "deleted_at"}, []any{m.Email, m.Name, m.DeletedAt}
+}
+func (SubscriberSchema) PK(m *Subscriber) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (SubscriberSchema) SetPK(m *Subscriber, val int64)         { m.ID = val }
+func (SubscriberSchema) AutoIncrement() bool                    { return true }
+func (SubscriberSchema) SoftDeleteColumn() string               { return "deleted_at" }
+func (SubscriberSchema) SoftDeleteValue() any                   { return time.Now() }
+func (SubscriberSchema) SetDeletedAt(m *Subscriber)             { now := time.Now(); m.DeletedAt = &now }
+func (SubscriberSchema) UpdateMap(m *Subscriber) map[string]any { return nil }
+
+func TestPartialUniqueIndex(t *testing.T) {
+	sqlc.RegisterSchema(SubscriberSchema{})
+	ctx := context.Background()
+	email := clause.Column{Name: "email"}
+
+	// ann@test.com: one deleted and one live record; bob@test.com: deleted only
+	setup := func(t *testing.T) (*sqlc.Session, *sqlc.Repository[Subscriber]) {
+		t.Helper()
+		db, _ := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		for _, stmt := range []string{
+			`CREATE TABLE subscribers (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT, name TEXT, deleted_at DATETIME)`,
+			`CREATE UNIQUE INDEX subscribers_email ON subscribers (email) WHERE deleted_at IS NULL`,
+			`INSERT INTO subscribers (email, name, deleted_at) VALUES
+				('ann@test.com', 'Ann (old)', '2024-01-01 00:00:00'),
+				('ann@test.com', 'Ann', NULL),
+				('bob@test.com', 'Bob (old)', '2024-01-01 00:00:00')`,
+		} {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+		}
+		session := sqlc.NewSession(db, sqlc.SQLiteDialect{}, sqlc.WithQueryCapture(4))
+		return session, sqlc.NewRepository[Subscriber](session)
+	}
+	names := func(t *testing.T, repo *sqlc.Repository[Subscriber], addr string) []string {
+		t.Helper()
+		subs, err := repo.Query().WithTrashed().
+			Where(clause.Eq{Column: email, Value: addr}).
+			OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		var out []string
+		for _, s := range subs {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+
+	t.Run("UpsertLive", func(t *testing.T) {
+		session, repo := setup(t)
+		if err := repo.Upsert(ctx, &Subscriber{Email: "ann@test.com", Name: "Ann B."}, sqlc.OnConflict(email), sqlc.OnConflictLive()); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if q := session.RecentQueries(1); len(q) != 1 || !strings.Contains(q[0].SQL, "ON CONFLICT (email) WHERE deleted_at IS NULL DO UPDATE SET") {
+			t.Errorf("unexpected upsert SQL %v", q)
+		}
+		if got := strings.Join(names(t, repo, "ann@test.com"), ", "); got != "Ann (old), Ann B." {
+			t.Errorf("expected the live record updated, got %s", got)
+		}
+
+		// Only a deleted record: a new record is inserted
+		if err := repo.Upsert(ctx, &Subscriber{Email: "bob@test.com", Name: "Bob"}, sqlc.OnConflict(email), sqlc.OnConflictLive()); err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if got := strings.Join(names(t, repo, "bob@test.com"), ", "); got != "Bob (old), Bob" {
+			t.Errorf("expected a new record, got %s", got)
+		}
+	})
+
+	t.Run("UpsertWhere", func(t *testing.T) {
+		_, repo := setup(t)
+		err := repo.Upsert(ctx, &Subscriber{Email: "ann@test.com", Name: "Ann C."},
+			sqlc.OnConflict(email),
+			sqlc.OnConflictWhere(clause.Expr{SQL: "deleted_at IS NULL"}),
+		)
+		if err != nil {
+			t.Fatalf("Upsert failed: %v", err)
+		}
+		if got := strings.Join(names(t, repo, "ann@test.com"), ", "); got != "Ann (old), Ann C." {
+			t.Errorf("expected the live record updated, got %s", got)
+		}
+	})
+
+	t.Run("UpsertWithoutPredicate", func(t *testing.T) {
+		_, repo := setup(t)
+		// The partial index does not match a conflict target without its predicate
+		if err := repo.Upsert(ctx, &Subscriber{Email: "ann@test.com", Name: "Ann D."}, sqlc.OnConflict(email)); err == nil {
+			t.Error("expected the conflict target to match no unique index")
+		}
+	})
+
+	t.Run("EnsureUnique", func(t *testing.T) {
+		_, repo := setup(t)
+		live := []sqlc.UpsertOption{sqlc.OnConflict(email), sqlc.OnConflictLive()}
+
+		if err := repo.EnsureUnique(ctx, &Subscriber{Email: "ann@test.com"}, live...); !errors.Is(err, sqlc.ErrUniqueViolation) {
+			t.Errorf("expected ErrUniqueViolation for a live duplicate, got %v", err)
+		}
+		if err := repo.EnsureUnique(ctx, &Subscriber{Email: "bob@test.com"}, live...); err != nil {
+			t.Errorf("expected deleted records to be ignored, got %v", err)
+		}
+		// A full unique index covers deleted records too
+		if err := repo.EnsureUnique(ctx, &Subscriber{Email: "bob@test.com"}, sqlc.OnConflict(email)); !errors.Is(err, sqlc.ErrUniqueViolation) {
+			t.Errorf("expected ErrUniqueViolation without a predicate, got %v", err)
+		}
+		// The record itself is not a conflict
+		if err := repo.EnsureUnique(ctx, &Subscriber{ID: 2, Email: "ann@test.com"}, live...); err != nil {
+			t.Errorf("expected the model's own record to be ignored, got %v", err)
+		}
+		// Restoring the deleted Ann would collide with the live one
+		if err := repo.EnsureUnique(ctx, &Subscriber{ID: 1, Email: "ann@test.com"}, live...); !errors.Is(err, sqlc.ErrUniqueViolation) {
+			t.Errorf("expected ErrUniqueViolation for the restored record, got %v", err)
+		}
+		if err := repo.EnsureUnique(ctx, &Subscriber{Email: "ann@test.com"}); err == nil {
+			t.Error("expected error without OnConflict columns")
+		}
+	})
+}
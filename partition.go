@@ -0,0 +1,309 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements hash-partitioned repositories (table-per-hash partitioning).
+//
+// Event-heavy tables often outgrow a single physical table long before the database
+// offers native partitioning (MySQL 5.7 without PARTITION BY maintenance, SQLite).
+// PartitionedRepository spreads rows of one model across N physical tables:
+//   - Writes are routed to exactly one table, chosen by hashing a partition key
+//   - Reads for a known key hit a single table via Partition(key)
+//   - Reads across all keys fan out with UNION ALL via Query()
+//
+// Physical tables are named "<table>_<index>" (e.g. events_0 ... events_7) and must
+// share the model's column layout. The generator emits the table list and a typed
+// constructor for models tagged with `db:"<column>,partition:N"`.
+//
+// Usage example:
+//
+//	events := sqlc.NewPartitionedRepository[models.Event](session, 8,
+//	    func(e *models.Event) int64 { return e.UserID },
+//	)
+//
+//	// Routed to events_<hash(UserID) % 8>
+//	err := events.Create(ctx, &models.Event{UserID: 42, Kind: "login"})
+//
+//	// Single-partition read
+//	recent, err := events.Partition(42).Query().
+//	    Where(generated.Event.UserID.Eq(42)).
+//	    Find(ctx)
+//
+//	// Fan-out read across all partitions
+//	total, err := events.Query().Where(generated.Event.Kind.Eq("login")).Count(ctx)
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// PartitionTableName returns the physical table name of a partition.
+//
+// Example:
+//
+//	sqlc.PartitionTableName("events", 3) // Returns: "events_3"
+func PartitionTableName(table string, index int) string {
+	return fmt.Sprintf("%s_%d", table, index)
+}
+
+// PartitionIndex returns the partition index of a key for the given partition count.
+// The hash is FNV-1a (32-bit) over fmt.Sprint(key), so other services and
+// migration scripts can reproduce the routing without this library.
+func PartitionIndex[K comparable](key K, partitions int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprint(key)))
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// partitionSchema overrides the table name of a schema so that a regular
+// Repository / QueryBuilder operates on one physical partition table.
+// The optional schema interfaces are forwarded to the wrapped schema.
+type partitionSchema[T any] struct {
+	Schema[T]
+	table string
+}
+
+func (s partitionSchema[T]) TableName() string { return s.table }
+
+// SoftDeleteActiveValue forwards the live soft delete value of the wrapped schema.
+func (s partitionSchema[T]) SoftDeleteActiveValue() any {
+	return softDeleteActive(s.Schema)
+}
+
+// ClearDeletedAt forwards the soft delete reset of the wrapped schema.
+func (s partitionSchema[T]) ClearDeletedAt(m *T) {
+	clearDeletedAt(s.Schema, m)
+}
+
+// DefaultOrder forwards the default order of the wrapped schema.
+func (s partitionSchema[T]) DefaultOrder() []clause.OrderByColumn {
+	if d, ok := s.Schema.(DefaultOrderer); ok {
+		return d.DefaultOrder()
+	}
+	return nil
+}
+
+// ValidationRules forwards the validate rules of the wrapped schema.
+func (s partitionSchema[T]) ValidationRules() []FieldRule[T] {
+	return validationRules(s.Schema)
+}
+
+// PartitionedRepository manages model T stored across N hash-partitioned tables.
+// Writes are routed by partition key, reads fan out with UNION ALL.
+//
+// Design principles:
+//   - Immutability: Where() returns a new instance, like Repository
+//   - Reuse: each partition is a regular Repository, so hooks, soft delete
+//     and upsert behave exactly as on an unpartitioned table
+//
+// Note:
+//   - Auto-increment IDs are generated per physical table and are therefore not
+//     unique across partitions; prefer application-generated IDs (UUID, snowflake)
+//   - Changing the partition count requires re-distributing existing rows
+type PartitionedRepository[T any, K comparable] struct {
+	session    *Session            // Database session
+	schema     Schema[T]           // Model's Schema implementation (logical table)
+	partitions int                 // Number of physical tables
+	key        func(*T) K          // Extracts the partition key from a model
+	scopes     []clause.Expression // Conditions applied to every partition
+}
+
+// NewPartitionedRepository creates a repository spreading model T across partitions tables.
+//
+// Parameters:
+//   - session: Database session, can be regular session or transaction session
+//   - partitions: Number of physical tables (must be positive)
+//   - key: Function extracting the partition key from a model
+//
+// Returns:
+//   - *PartitionedRepository[T, K]: Initialized repository
+//
+// Note:
+//   - Model T must be registered via RegisterSchema[T]()
+//   - Panics if partitions is not positive (configuration error)
+//
+// Example:
+//
+//	events := sqlc.NewPartitionedRepository[models.Event](session, 16,
+//	    func(e *models.Event) string { return e.TenantID },
+//	)
+func NewPartitionedRepository[T any, K comparable](session *Session, partitions int, key func(*T) K) *PartitionedRepository[T, K] {
+	if partitions <= 0 {
+		panic(fmt.Sprintf("sqlc: partition count must be positive, got %d", partitions))
+	}
+	return &PartitionedRepository[T, K]{
+		session:    session,
+		schema:     LoadSchema[T](),
+		partitions: partitions,
+		key:        key,
+		scopes:     make([]clause.Expression, 0),
+	}
+}
+
+// Where returns a new PartitionedRepository with appended conditions.
+// Conditions apply to partition writes (Update, Delete) and are pushed into
+// every UNION ALL branch of Query().
+//
+// Note:
+//   - Use unqualified columns; partition tables have different names
+func (r *PartitionedRepository[T, K]) Where(conds ...clause.Expression) *PartitionedRepository[T, K] {
+	newRepo := *r
+	newRepo.scopes = make([]clause.Expression, len(r.scopes), len(r.scopes)+len(conds))
+	copy(newRepo.scopes, r.scopes)
+	newRepo.scopes = append(newRepo.scopes, conds...)
+	return &newRepo
+}
+
+// Partitions returns the number of physical tables.
+func (r *PartitionedRepository[T, K]) Partitions() int {
+	return r.partitions
+}
+
+// TableFor returns the physical table name a key is routed to.
+func (r *PartitionedRepository[T, K]) TableFor(key K) string {
+	return PartitionTableName(r.schema.TableName(), PartitionIndex(key, r.partitions))
+}
+
+// Tables returns all physical table names in partition order.
+func (r *PartitionedRepository[T, K]) Tables() []string {
+	tables := make([]string, r.partitions)
+	for i := range tables {
+		tables[i] = PartitionTableName(r.schema.TableName(), i)
+	}
+	return tables
+}
+
+// Partition returns a regular Repository bound to the physical table of key.
+// All Repository operations (FindOne, Query, UpdateColumns, Restore, ...) are available.
+//
+// Example:
+//
+//	// All events of one user live in a single partition
+//	events, err := eventRepo.Partition(userID).Query().
+//	    Where(generated.Event.UserID.Eq(userID)).
+//	    OrderBy(generated.Event.CreatedAt.Desc()).
+//	    Find(ctx)
+func (r *PartitionedRepository[T, K]) Partition(key K) *Repository[T] {
+	return r.partitionRepo(r.TableFor(key))
+}
+
+// partitionRepo creates a Repository bound to a physical table, carrying scopes.
+func (r *PartitionedRepository[T, K]) partitionRepo(table string) *Repository[T] {
+	scopes := make([]clause.Expression, len(r.scopes))
+	copy(scopes, r.scopes)
+	return &Repository[T]{
+		session: r.session,
+		schema:  partitionSchema[T]{Schema: r.schema, table: table},
+		scopes:  scopes,
+	}
+}
+
+// Create inserts a record into the partition selected by its key.
+// Lifecycle hooks are triggered as with Repository.Create().
+func (r *PartitionedRepository[T, K]) Create(ctx context.Context, model *T) error {
+	return r.Partition(r.key(model)).Create(ctx, model)
+}
+
+// BatchCreate inserts records with one INSERT statement per touched partition.
+//
+// Note:
+//   - Statements are executed sequentially; wrap the call in Session.Transaction
+//     if all partitions must succeed or fail together
+//   - Auto-increment IDs are not backfilled (same as Repository.BatchCreate)
+func (r *PartitionedRepository[T, K]) BatchCreate(ctx context.Context, models []*T) error {
+	// Group models by physical table, preserving first-seen order
+	groups := make(map[string][]*T)
+	var order []string
+	for _, model := range models {
+		table := r.TableFor(r.key(model))
+		if _, ok := groups[table]; !ok {
+			order = append(order, table)
+		}
+		groups[table] = append(groups[table], model)
+	}
+
+	for _, table := range order {
+		if err := r.partitionRepo(table).BatchCreate(ctx, groups[table]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Upsert inserts or updates a record in the partition selected by its key.
+func (r *PartitionedRepository[T, K]) Upsert(ctx context.Context, model *T, opts ...UpsertOption) error {
+	return r.Partition(r.key(model)).Upsert(ctx, model, opts...)
+}
+
+// Update updates a record in the partition selected by its key.
+//
+// Note:
+//   - The partition key must not change; moving a row between partitions
+//     requires DeleteModel() followed by Create()
+func (r *PartitionedRepository[T, K]) Update(ctx context.Context, model *T) error {
+	return r.Partition(r.key(model)).Update(ctx, model)
+}
+
+// DeleteModel deletes (or soft-deletes) a record in the partition selected by its key.
+func (r *PartitionedRepository[T, K]) DeleteModel(ctx context.Context, model *T) error {
+	return r.Partition(r.key(model)).DeleteModel(ctx, model)
+}
+
+// Query returns a QueryBuilder reading from all partitions.
+// The partitions are combined with UNION ALL into a derived table aliased as the
+// logical table name, so the full QueryBuilder API (Where, OrderBy, Limit, Count,
+// aggregates, soft delete filtering) works unchanged.
+//
+// Returns:
+//   - *QueryBuilder[T]: Query builder over the union of all partitions
+//
+// Example:
+//
+//	// Latest 50 failed events across all users
+//	events, err := eventRepo.Query().
+//	    Where(generated.Event.Status.Eq("failed")).
+//	    OrderBy(generated.Event.CreatedAt.Desc()).
+//	    Limit(50).
+//	    Find(ctx)
+//
+// Note:
+//   - Repository scopes (Where) are pushed into every branch so each table can use its indexes;
+//     conditions added on the QueryBuilder are applied to the combined result
+//   - Prefer Partition(key) when the key is known; it reads a single table
+func (r *PartitionedRepository[T, K]) Query() *QueryBuilder[T] {
+	q := newQueryBuilder(r.session, r.schema)
+
+	cols := r.schema.SelectColumns()
+	branch := func(table string) sq.SelectBuilder {
+		b := sq.Select(cols...).From(table)
+		for _, scope := range r.scopes {
+			b = b.Where(scopeSqlizer{scope})
+		}
+		return b
+	}
+
+	// First branch carries the remaining branches as a UNION ALL suffix
+	union := branch(PartitionTableName(r.schema.TableName(), 0))
+	if r.partitions > 1 {
+		var rest []string
+		var restArgs []any
+		for i := 1; i < r.partitions; i++ {
+			sql, args, err := branch(PartitionTableName(r.schema.TableName(), i)).ToSql()
+			if err != nil {
+				q.err = fmt.Errorf("sqlc: failed to build partition query: %w", err)
+				return q
+			}
+			rest = append(rest, "UNION ALL "+sql)
+			restArgs = append(restArgs, args...)
+		}
+		union = union.Suffix(strings.Join(rest, " "), restArgs...)
+	}
+
+	q.builder = sq.Select().
+		FromSelect(union, r.schema.TableName()).
+		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
+	return q
+}
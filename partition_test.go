@@ -0,0 +1,262 @@
+package sqlc_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestPartitionIndex(t *testing.T) {
+	if got := sqlc.PartitionTableName("events", 3); got != "events_3" {
+		t.Errorf("PartitionTableName = %q, want events_3", got)
+	}
+
+	// Routing must be deterministic and within range
+	for _, key := range []any{0, 1, 42, "tenant-a", int64(-7)} {
+		first := sqlc.PartitionIndex(key, 4)
+		if first < 0 || first >= 4 {
+			t.Fatalf("PartitionIndex(%v) = %d out of range", key, first)
+		}
+		if again := sqlc.PartitionIndex(key, 4); again != first {
+			t.Errorf("PartitionIndex(%v) not deterministic: %d != %d", key, first, again)
+		}
+	}
+
+	if got := sqlc.PartitionIndex("anything", 1); got != 0 {
+		t.Errorf("single partition must map to 0, got %d", got)
+	}
+}
+
+func TestPartitionedRepository(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	const partitions = 3
+	for i := 0; i < partitions; i++ {
+		ddl := fmt.Sprintf(`CREATE TABLE members_%d (
+			id INTEGER PRIMARY KEY,
+			name TEXT,
+			email TEXT UNIQUE,
+			level INTEGER,
+			department_id INTEGER,
+			created_at DATETIME
+		)`, i)
+		if _, err := db.Exec(ddl); err != nil {
+			t.Fatalf("failed to create partition table: %v", err)
+		}
+	}
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{})
+	repo := sqlc.NewPartitionedRepository(session, partitions,
+		func(m *Member) int { return m.DepartmentID },
+	)
+
+	countIn := func(table string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	t.Run("Tables", func(t *testing.T) {
+		want := []string{"members_0", "members_1", "members_2"}
+		got := repo.Tables()
+		if len(got) != len(want) {
+			t.Fatalf("Tables() = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Tables()[%d] = %q, want %q", i, got[i], want[i])
+			}
+		}
+	})
+
+	t.Run("CreateRoutesByKey", func(t *testing.T) {
+		m := &Member{ID: 1, Name: "Alice", Email: "alice@example.com", Level: 1, DepartmentID: 10}
+		if err := repo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if n := countIn(repo.TableFor(10)); n != 1 {
+			t.Errorf("expected row in %s, got %d rows", repo.TableFor(10), n)
+		}
+
+		found, err := repo.Partition(10).FindOne(ctx, int64(1))
+		if err != nil {
+			t.Fatalf("FindOne on partition failed: %v", err)
+		}
+		if found.Name != "Alice" {
+			t.Errorf("expected Alice, got %s", found.Name)
+		}
+	})
+
+	t.Run("BatchCreateGroupsByPartition", func(t *testing.T) {
+		var members []*Member
+		for i := 0; i < 12; i++ {
+			members = append(members, &Member{
+				ID:           int64(100 + i),
+				Name:         fmt.Sprintf("m%d", i),
+				Email:        fmt.Sprintf("m%d@example.com", i),
+				Level:        i % 4,
+				DepartmentID: i,
+			})
+		}
+		if err := repo.BatchCreate(ctx, members); err != nil {
+			t.Fatalf("BatchCreate failed: %v", err)
+		}
+
+		for _, m := range members {
+			var name string
+			err := db.QueryRow("SELECT name FROM "+repo.TableFor(m.DepartmentID)+" WHERE id = ?", m.ID).Scan(&name)
+			if err != nil {
+				t.Errorf("member %d not found in %s: %v", m.ID, repo.TableFor(m.DepartmentID), err)
+			}
+		}
+	})
+
+	t.Run("FanOutQuery", func(t *testing.T) {
+		total, err := repo.Query().Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if total != 13 {
+			t.Errorf("expected 13 rows across partitions, got %d", total)
+		}
+
+		top, err := repo.Query().
+			Where(clause.Gte{Column: clause.Column{Name: "level"}, Value: 3}).
+			OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(top) != 3 || top[0].ID != 103 || top[2].ID != 111 {
+			t.Errorf("unexpected fan-out result: %+v", top)
+		}
+	})
+
+	t.Run("ScopesPushedIntoBranches", func(t *testing.T) {
+		scoped := repo.Where(clause.Eq{Column: clause.Column{Name: "level"}, Value: 0})
+
+		sqlStr, args, err := scoped.Query().ToSQL()
+		if err != nil {
+			t.Fatalf("ToSQL failed: %v", err)
+		}
+		if !contains(sqlStr, "FROM members_2 WHERE (level = ?)") || !contains(sqlStr, "UNION ALL") {
+			t.Errorf("expected scoped UNION ALL branches, got %s", sqlStr)
+		}
+		if len(args) != partitions {
+			t.Errorf("expected one arg per branch, got %v", args)
+		}
+
+		count, err := scoped.Query().Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("expected 3 level-0 members, got %d", count)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		m, err := repo.Partition(10).FindOne(ctx, int64(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Name = "Alice Updated"
+		if err := repo.Update(ctx, m); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		got, err := repo.Partition(10).FindOne(ctx, int64(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "Alice Updated" {
+			t.Errorf("expected updated name, got %s", got.Name)
+		}
+
+		if err := repo.DeleteModel(ctx, m); err != nil {
+			t.Fatalf("DeleteModel failed: %v", err)
+		}
+		if _, err := repo.Partition(10).FindOne(ctx, int64(1)); err == nil {
+			t.Error("expected member to be deleted")
+		}
+	})
+}
+
+func TestPartitionedSoftDeleteFlag(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	const partitions = 2
+	for i := 0; i < partitions; i++ {
+		ddl := fmt.Sprintf(`CREATE TABLE legacy_notes_%d (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			body TEXT,
+			is_deleted BOOLEAN NOT NULL DEFAULT 0,
+			removed_at DATETIME,
+			removed_ms INTEGER NOT NULL DEFAULT 0,
+			archived_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'
+		)`, i)
+		if _, err := db.Exec(ddl); err != nil {
+			t.Fatalf("failed to create partition table: %v", err)
+		}
+	}
+
+	sqlc.RegisterSoftDeleteFlag[LegacyNote]("is_deleted")
+	defer sqlc.RegisterSoftDelete[LegacyNote]("") // Back to hard deletes
+
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{})
+	repo := sqlc.NewPartitionedRepository(session, partitions,
+		func(n *LegacyNote) string { return n.Body },
+	)
+
+	keep, drop := &LegacyNote{Body: "keep"}, &LegacyNote{Body: "drop"}
+	for _, n := range []*LegacyNote{keep, drop} {
+		if err := repo.Create(ctx, n); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := repo.DeleteModel(ctx, drop); err != nil {
+		t.Fatalf("DeleteModel failed: %v", err)
+	}
+
+	// Live rows have is_deleted = false, not NULL
+	assertCount := func(q *sqlc.QueryBuilder[LegacyNote], want int64) {
+		t.Helper()
+		got, err := q.Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("expected %d, got %d", want, got)
+		}
+	}
+	assertCount(repo.Query(), 1)
+	assertCount(repo.Query().WithTrashed(), 2)
+	assertCount(repo.Partition("drop").Trashed(), 1)
+
+	if err := repo.Partition("drop").RestoreModel(ctx, drop); err != nil {
+		t.Fatalf("RestoreModel failed: %v", err)
+	}
+	if drop.IsDeleted {
+		t.Errorf("expected RestoreModel to reset the flag, got %+v", drop)
+	}
+	assertCount(repo.Query(), 2)
+}
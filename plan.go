@@ -0,0 +1,134 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements per-query plan hints for hot queries on PostgreSQL.
+//
+// PostgreSQL switches a prepared statement from custom plans to a cached generic plan
+// after a few executions. On skewed data the generic plan can be much worse for some
+// parameter values ("plan flips"). QueryPlan lets a specific hot query:
+//   - Pin the planner mode via plan_cache_mode (force_generic_plan / force_custom_plan)
+//   - Carry a label comment, so it is easy to find in pg_stat_activity and server logs
+//
+// Usage example:
+//
+//	users, err := userRepo.Query().
+//	    Where(generated.User.TenantID.Eq(tenantID)).
+//	    WithPlan(sqlc.QueryPlan{Label: "users_by_tenant", CacheMode: sqlc.PlanForceCustom}).
+//	    Find(ctx)
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// PlanCacheMode is a PostgreSQL plan_cache_mode setting.
+type PlanCacheMode string
+
+const (
+	// PlanAuto lets PostgreSQL choose between custom and generic plans (server default).
+	PlanAuto PlanCacheMode = "auto"
+
+	// PlanForceGeneric always uses the cached generic plan (cheap planning, parameter-blind).
+	PlanForceGeneric PlanCacheMode = "force_generic_plan"
+
+	// PlanForceCustom re-plans for every execution using the actual parameter values.
+	PlanForceCustom PlanCacheMode = "force_custom_plan"
+)
+
+// QueryPlan configures how a single query is planned.
+// The zero value applies no hints.
+type QueryPlan struct {
+	// Label is sent as a leading SQL comment (/* label */), making the statement easy
+	// to find in pg_stat_activity and server logs. It does not name a prepared
+	// statement: database/sql drivers choose server-side statement names themselves.
+	// Allowed characters: letters, digits, '_', '.', ':' and '-'.
+	Label string
+
+	// CacheMode sets plan_cache_mode for the statement (PostgreSQL only).
+	// Outside a transaction the statement runs in a short transaction using SET LOCAL;
+	// inside a transaction the previous setting is restored afterwards.
+	CacheMode PlanCacheMode
+}
+
+// planLabelRegexp matches labels that are safe inside a SQL comment.
+var planLabelRegexp = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// validate checks the plan label and cache mode.
+func (p QueryPlan) validate() error {
+	if p.Label != "" && !planLabelRegexp.MatchString(p.Label) {
+		return fmt.Errorf("sqlc: invalid plan label %q", p.Label)
+	}
+	switch p.CacheMode {
+	case "", PlanAuto, PlanForceGeneric, PlanForceCustom:
+		return nil
+	default:
+		return fmt.Errorf("sqlc: invalid plan cache mode %q", p.CacheMode)
+	}
+}
+
+// annotate prefixes query with the label comment.
+func (p QueryPlan) annotate(query string) string {
+	if p.Label == "" {
+		return query
+	}
+	return "/* " + p.Label + " */ " + query
+}
+
+// WithPlan attaches plan hints to the query.
+// Applied by Find (and Take, First, Last), Scan and Count; other terminal methods ignore it.
+//
+// Example:
+//
+//	// Skewed tenant sizes: always plan with the actual tenant ID
+//	orders, err := orderRepo.Query().
+//	    Where(generated.Order.TenantID.Eq(tenantID)).
+//	    WithPlan(sqlc.QueryPlan{Label: "orders_by_tenant", CacheMode: sqlc.PlanForceCustom}).
+//	    Find(ctx)
+//
+// Note:
+//   - CacheMode is ignored on MySQL and SQLite; Label is applied on every dialect
+func (q *QueryBuilder[T]) WithPlan(plan QueryPlan) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	if err := plan.validate(); err != nil {
+		q.err = err
+		return q
+	}
+	q.plan = plan
+	return q
+}
+
+// withPlanCacheMode runs fn with plan_cache_mode set to mode on the connection fn uses.
+func (s *Session) withPlanCacheMode(ctx context.Context, mode PlanCacheMode, fn func(s *Session) error) error {
+	if mode == "" || s.dialect.Name() != PostgreSQL.Name() {
+		return fn(s)
+	}
+
+	const setMode = "SELECT set_config('plan_cache_mode', $1, true)"
+
+	// Not in a transaction: SET LOCAL needs one to pin the connection and scope the setting
+	if s.tx == nil {
+		return s.Transaction(ctx, func(tx *Session) error {
+			if _, err := tx.Exec(ctx, setMode, string(mode)); err != nil {
+				return fmt.Errorf("sqlc: failed to set plan cache mode: %w", err)
+			}
+			return fn(tx)
+		})
+	}
+
+	// In a transaction: restore the previous value so later statements are unaffected
+	var prev string
+	if err := s.Get(ctx, &prev, "SELECT current_setting('plan_cache_mode')"); err != nil {
+		return fmt.Errorf("sqlc: failed to read plan cache mode: %w", err)
+	}
+	if _, err := s.Exec(ctx, setMode, string(mode)); err != nil {
+		return fmt.Errorf("sqlc: failed to set plan cache mode: %w", err)
+	}
+	fnErr := fn(s)
+	if _, err := s.Exec(ctx, setMode, prev); err != nil && fnErr == nil {
+		return fmt.Errorf("sqlc: failed to restore plan cache mode: %w", err)
+	}
+	return fnErr
+}
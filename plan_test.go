@@ -0,0 +1,93 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestQueryPlan(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var seen []string
+	record := func(ctx context.Context, query string) error {
+		seen = append(seen, query)
+		return nil
+	}
+	session := sqlc.NewSession(db, sqlc.SQLite, sqlc.WithSQLGuard(record))
+	memberRepo := sqlc.NewRepository[Member](session)
+	if err := memberRepo.Create(ctx, &Member{Name: "Plan", Email: "plan@test.com", Level: 3, DepartmentID: 1, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	level := field.Number[int]{}.WithColumn("level")
+
+	t.Run("LabeledStatement", func(t *testing.T) {
+		seen = nil
+		q := memberRepo.Query().Where(level.Eq(3)).WithPlan(sqlc.QueryPlan{
+			Label:     "members_by_level",
+			CacheMode: sqlc.PlanForceCustom, // ignored on SQLite
+		})
+		members, err := q.Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(members) != 1 {
+			t.Errorf("expected 1 member, got %d", len(members))
+		}
+		if n, err := q.Count(ctx); err != nil || n != 1 {
+			t.Errorf("expected count 1, got %d (err %v)", n, err)
+		}
+		if len(seen) != 2 {
+			t.Fatalf("expected 2 statements, got %q", seen)
+		}
+		for _, query := range seen {
+			if !strings.HasPrefix(query, "/* members_by_level */ SELECT ") {
+				t.Errorf("statement not annotated: %s", query)
+			}
+		}
+	})
+
+	t.Run("InvalidPlan", func(t *testing.T) {
+		tests := []sqlc.QueryPlan{
+			{Label: "x */ DROP TABLE members; /*"},
+			{CacheMode: "sometimes"},
+		}
+		for _, plan := range tests {
+			if _, err := memberRepo.Query().WithPlan(plan).Find(ctx); err == nil {
+				t.Errorf("expected error for plan %+v", plan)
+			}
+		}
+	})
+}
+
+func TestQueryPlanCacheModePostgres(t *testing.T) {
+	db, _ := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var seen []string
+	capture := func(ctx context.Context, query string) error {
+		seen = append(seen, query)
+		return fmt.Errorf("%w: captured", sqlc.ErrStatementRejected)
+	}
+	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithSQLGuard(capture))
+
+	_, err := sqlc.NewRepository[Member](session).Query().
+		WithPlan(sqlc.QueryPlan{CacheMode: sqlc.PlanForceGeneric}).
+		Find(ctx)
+	if !errors.Is(err, sqlc.ErrStatementRejected) {
+		t.Fatalf("expected guard rejection, got %v", err)
+	}
+	want := []string{"SELECT set_config('plan_cache_mode', $1, true)"}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("expected %q before the query, got %q", want, seen)
+	}
+}
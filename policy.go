@@ -0,0 +1,315 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements row-level permission policies tied to the roles of a request.
+//
+// Handlers enforcing row-level authorization (own records only, team visibility) tend
+// to repeat the same Where filters everywhere, and one forgotten filter leaks data.
+// Policies centralize them: a registry maps (model, action, role) to a condition, and
+// a session created WithPolicies adds the condition for the roles of the context to
+// every query, update and delete of the model:
+//
+//	policies := sqlc.NewPolicies()
+//	sqlc.AddPolicy[models.Document](policies, sqlc.PolicyRead, "member", func(ctx context.Context) clause.Expression {
+//	    return generated.Document.OwnerID.Eq(auth.UserID(ctx))
+//	})
+//	sqlc.AddPolicy[models.Document](policies, sqlc.PolicyRead, "admin", nil) // All rows
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithPolicies(policies))
+//
+//	// In the request middleware
+//	ctx = sqlc.WithRoles(ctx, "member")
+//
+//	docs, err := docRepo.Query().Find(ctx)
+//	// SELECT ... FROM documents WHERE (owner_id = $1)
+//
+// Rules:
+//   - Models without policies are not restricted
+//   - Once a model has a policy, an action is denied (ErrPolicyDenied) unless a role of the
+//     context grants it; the conditions of several granting roles are combined with OR
+//   - Inserts, Upsert and Merge cannot filter rows by a condition, so they require a role
+//     granting the action on all rows (nil condition)
+//   - WithoutPolicies(ctx) lifts the policies for trusted code (migrations, background jobs)
+package sqlc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// ErrPolicyDenied is returned when no role of the context grants the action on a model
+// that has policies.
+var ErrPolicyDenied = errors.New("sqlc: denied by row policy")
+
+// PolicyAction is the kind of access a policy grants.
+type PolicyAction string
+
+const (
+	PolicyRead   PolicyAction = "read"   // Queries (Find, Count, aggregates, ...)
+	PolicyUpdate PolicyAction = "update" // Update, UpdateColumns, UpdateWhere, Restore
+	PolicyDelete PolicyAction = "delete" // Delete, DeleteWhere, EmptyTrash, soft deletes
+	PolicyCreate PolicyAction = "create" // Create, CreateOrIgnore, BatchCreate, Upsert, Merge inserts
+)
+
+// PolicyFunc returns the condition limiting the rows a role may access, built from the
+// request context (e.g. the current user ID). A nil condition grants all rows.
+type PolicyFunc func(ctx context.Context) clause.Expression
+
+// Policies is a registry of row-level policies, installed on sessions with WithPolicies.
+// It is safe for concurrent use.
+type Policies struct {
+	mu    sync.RWMutex
+	rules map[reflect.Type]map[PolicyAction]map[string]PolicyFunc // model -> action -> role
+}
+
+// NewPolicies creates an empty policy registry.
+func NewPolicies() *Policies {
+	return &Policies{rules: make(map[reflect.Type]map[PolicyAction]map[string]PolicyFunc)}
+}
+
+// AddPolicy grants role the action on rows of model T matching the condition returned by fn.
+// A nil fn grants all rows. Adding a policy for the same model, action and role replaces it.
+//
+// Parameters:
+//   - p: Policy registry
+//   - action: PolicyRead, PolicyUpdate, PolicyDelete or PolicyCreate
+//   - role: Role name, as passed to WithRoles
+//   - fn: Condition builder, called with the context of each statement
+//
+// Example:
+//
+//	// Members see their team's projects, and edit only their own
+//	sqlc.AddPolicy[models.Project](policies, sqlc.PolicyRead, "member", func(ctx context.Context) clause.Expression {
+//	    return generated.Project.TeamID.Eq(auth.TeamID(ctx))
+//	})
+//	sqlc.AddPolicy[models.Project](policies, sqlc.PolicyUpdate, "member", func(ctx context.Context) clause.Expression {
+//	    return generated.Project.OwnerID.Eq(auth.UserID(ctx))
+//	})
+//	sqlc.AddPolicy[models.Project](policies, sqlc.PolicyCreate, "member", nil)
+//
+// Note:
+//   - New rows have no condition to match, so PolicyCreate is granted only when fn is nil
+//     or returns nil; the same holds for PolicyUpdate and PolicyDelete with Upsert and Merge
+func AddPolicy[T any](p *Policies, action PolicyAction, role string, fn PolicyFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	model := reflect.TypeFor[T]()
+	if p.rules[model] == nil {
+		p.rules[model] = make(map[PolicyAction]map[string]PolicyFunc)
+	}
+	if p.rules[model][action] == nil {
+		p.rules[model][action] = make(map[string]PolicyFunc)
+	}
+	p.rules[model][action][role] = fn
+}
+
+// WithPolicies applies the row-level policies of p to the queries, inserts, updates and
+// deletes run through repositories of the session.
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.MySQL, sqlc.WithPolicies(policies))
+//
+// Note:
+//   - Inserts, Upsert and Merge fail with ErrPolicyDenied unless the roles grant their
+//     actions on all rows, as their rows cannot be filtered
+//   - Raw SQL, query templates of restricted models, and relation counts and trees
+//     loaded by preloads are not filtered; query templates of a restricted model fail
+//   - Subqueries of a restricted model must be authorized with QueryBuilder.Authorize
+//   - In joined queries, write policy conditions with table-qualified columns
+func WithPolicies(p *Policies) SessionOption {
+	return func(s *Session) {
+		s.policies = p
+	}
+}
+
+type rolesKey struct{}
+
+type skipPoliciesKey struct{}
+
+// WithRoles returns a context carrying the roles of the current request, which select
+// the policies applied to its statements.
+//
+// Example:
+//
+//	func authMiddleware(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        user := authenticate(r)
+//	        next.ServeHTTP(w, r.WithContext(sqlc.WithRoles(r.Context(), user.Roles...)))
+//	    })
+//	}
+func WithRoles(ctx context.Context, roles ...string) context.Context {
+	return context.WithValue(ctx, rolesKey{}, slices.Clone(roles))
+}
+
+// RolesFromContext returns the roles set by WithRoles, or nil if none.
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesKey{}).([]string)
+	return slices.Clone(roles)
+}
+
+// WithoutPolicies returns a context whose statements are not restricted by policies,
+// for trusted code such as migrations and background jobs.
+func WithoutPolicies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipPoliciesKey{}, true)
+}
+
+// policiesSkipped reports whether ctx was created by WithoutPolicies.
+func policiesSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipPoliciesKey{}).(bool)
+	return skip
+}
+
+// policyCondition returns the condition restricting action on rows of T for the roles of ctx:
+// nil if the rows are not restricted, or ErrPolicyDenied if no role grants the action.
+func policyCondition[T any](ctx context.Context, s *Session, action PolicyAction) (clause.Expression, error) {
+	p := s.policies
+	if p == nil || policiesSkipped(ctx) {
+		return nil, nil
+	}
+	model := reflect.TypeFor[T]()
+
+	p.mu.RLock()
+	actions, restricted := p.rules[model]
+	var grants []PolicyFunc
+	if restricted {
+		for _, role := range RolesFromContext(ctx) {
+			if fn, ok := actions[action][role]; ok {
+				grants = append(grants, fn)
+			}
+		}
+	}
+	p.mu.RUnlock()
+
+	if !restricted {
+		return nil, nil
+	}
+	if len(grants) == 0 {
+		return nil, fmt.Errorf("%w: %s on %s", ErrPolicyDenied, action, model)
+	}
+	var conds clause.Or
+	for _, fn := range grants {
+		if fn == nil {
+			return nil, nil
+		}
+		cond := fn(ctx)
+		if cond == nil {
+			return nil, nil
+		}
+		conds = append(conds, cond)
+	}
+	if len(conds) == 1 {
+		return conds[0], nil
+	}
+	return conds, nil
+}
+
+// policyScopes returns the repository's scopes plus the policy condition of action for ctx.
+func (r *Repository[T]) policyScopes(ctx context.Context, action PolicyAction) ([]clause.Expression, error) {
+	cond, err := policyCondition[T](ctx, r.session, action)
+	if err != nil || cond == nil {
+		return r.scopes, err
+	}
+	return append(slices.Clip(r.scopes), cond), nil
+}
+
+// policyGranted fails with ErrPolicyDenied unless the roles of ctx grant each action on
+// all rows of T. Statements whose rows cannot be filtered by a condition (inserts, Upsert
+// conflict updates, MERGE actions) require such unconditional grants.
+func policyGranted[T any](ctx context.Context, s *Session, actions ...PolicyAction) error {
+	for _, action := range actions {
+		cond, err := policyCondition[T](ctx, s, action)
+		if err != nil {
+			return err
+		}
+		if cond != nil {
+			return fmt.Errorf("%w: %s on %s is limited to some rows", ErrPolicyDenied, action, reflect.TypeFor[T]())
+		}
+	}
+	return nil
+}
+
+// authorize returns the query restricted by the read policy for ctx. The condition goes on
+// the base builder, like repository scopes, so OrWhere cannot bypass it.
+func (q *QueryBuilder[T]) authorize(ctx context.Context) *QueryBuilder[T] {
+	if q.err != nil || q.authorized {
+		return q
+	}
+	cond, err := policyCondition[T](ctx, q.session, PolicyRead)
+	if err == nil && cond == nil {
+		return q
+	}
+	q = q.Clone()
+	q.authorized = true
+	if err != nil {
+		q.err = err
+		return q
+	}
+	sql, args, err := clause.BindTypes(cond, typeNamer(q.session.dialect)).Build()
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.builder = q.builder.Where(sq.Expr("("+sql+")", args...))
+	return q
+}
+
+// Authorize applies the read policy of the roles of ctx to the query now, instead of when
+// it is executed. Queries embedded in another one (FromSubquery, LeftJoinLateral, merge
+// sources and IN subqueries) are rendered without a context, so a subquery of a model
+// with policies must be authorized explicitly; otherwise the outer query fails.
+//
+// Parameters:
+//   - ctx: Context carrying the roles (WithRoles) or WithoutPolicies
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder restricted by the policy; ErrPolicyDenied surfaces
+//     when the query is executed
+//
+// Example:
+//
+//	mine := docRepo.Query().Select(generated.Document.ID).Authorize(ctx)
+//	comments, err := commentRepo.Query().Where(generated.Comment.DocumentID.InExpr(mine)).Find(ctx)
+func (q *QueryBuilder[T]) Authorize(ctx context.Context) *QueryBuilder[T] {
+	q = q.authorize(ctx)
+	if q.err == nil && !q.authorized {
+		q = q.Clone()
+		q.authorized = true
+	}
+	return q
+}
+
+// subqueryPolicy fails if T has policies and the query was not authorized: embedded
+// queries are rendered without the request context, so the read policy cannot be applied.
+func (q *QueryBuilder[T]) subqueryPolicy() error {
+	if q.authorized || !policyRestricted[T](q.session) {
+		return nil
+	}
+	return fmt.Errorf("sqlc: subquery of %s must be authorized with Authorize(ctx) to apply its row policy", reflect.TypeFor[T]())
+}
+
+// policyRestricted reports whether the session has policies for rows of T.
+func policyRestricted[T any](s *Session) bool {
+	p := s.policies
+	if p == nil {
+		return false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.rules[reflect.TypeFor[T]()]) > 0
+}
+
+// templatePolicy fails if the read policy restricts rows of T for ctx: query templates
+// are rendered once, so they cannot carry conditions built per request.
+func templatePolicy[T any](ctx context.Context, s *Session) error {
+	cond, err := policyCondition[T](ctx, s, PolicyRead)
+	if err == nil && cond != nil {
+		err = fmt.Errorf("sqlc: query templates cannot apply the row policy of %s", reflect.TypeFor[T]())
+	}
+	return err
+}
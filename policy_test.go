@@ -0,0 +1,235 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/field"
+)
+
+type deptKey struct{}
+
+func TestPolicies(t *testing.T) {
+	db, _ := setupIntegrationDB(t)
+	defer db.Close()
+	dept := clause.Column{Name: "department_id"}
+	name := clause.Column{Name: "name"}
+	level := clause.Column{Name: "level"}
+
+	policies := sqlc.NewPolicies()
+	ownDepartment := func(ctx context.Context) clause.Expression {
+		return clause.Eq{Column: dept, Value: ctx.Value(deptKey{})}
+	}
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyRead, "member", ownDepartment)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyUpdate, "member", ownDepartment)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyRead, "auditor", func(context.Context) clause.Expression {
+		return clause.Gte{Column: level, Value: 5}
+	})
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyRead, "admin", nil)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyUpdate, "admin", nil)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyDelete, "admin", nil)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyCreate, "admin", nil)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyCreate, "member", nil)
+	sqlc.AddPolicy[Member](policies, sqlc.PolicyCreate, "auditor", ownDepartment)
+	// Editors may update products but not (soft) delete them
+	sqlc.AddPolicy[SoftDeleteProduct](policies, sqlc.PolicyUpdate, "editor", nil)
+	sqlc.AddPolicy[SoftDeleteProduct](policies, sqlc.PolicyDelete, "admin", nil)
+
+	session := sqlc.NewSession(db, sqlc.SQLite, sqlc.WithPolicies(policies))
+	repo := sqlc.NewRepository[Member](session)
+	admin := sqlc.WithRoles(context.Background(), "admin")
+	member := sqlc.WithRoles(context.WithValue(context.Background(), deptKey{}, 1), "member")
+
+	seed := []*Member{
+		{Name: "Ann", Email: "ann@test.com", Level: 1, DepartmentID: 1},
+		{Name: "Bob", Email: "bob@test.com", Level: 2, DepartmentID: 1},
+		{Name: "Cid", Email: "cid@test.com", Level: 7, DepartmentID: 2},
+	}
+	if err := repo.BatchCreate(admin, seed); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		count := func(ctx context.Context, q *sqlc.QueryBuilder[Member]) int64 {
+			t.Helper()
+			n, err := q.Count(ctx)
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			return n
+		}
+		if n := count(admin, repo.Query()); n != 3 {
+			t.Errorf("admin: expected 3 members, got %d", n)
+		}
+		if n := count(member, repo.Query()); n != 2 {
+			t.Errorf("member: expected 2 members, got %d", n)
+		}
+		// OrWhere cannot widen the policy
+		if n := count(member, repo.Query().Where(clause.Eq{Column: name, Value: "Ann"}).OrWhere(clause.Eq{Column: name, Value: "Cid"})); n != 1 {
+			t.Errorf("member: expected OrWhere to stay within the policy, got %d", n)
+		}
+		// Conditions of several roles are combined with OR
+		both := sqlc.WithRoles(member, "member", "auditor")
+		if members, err := repo.Query().Find(both); err != nil || len(members) != 3 {
+			t.Errorf("member+auditor: expected 3 members, got %d (err %v)", len(members), err)
+		}
+		if _, err := repo.FindOne(member, seed[2].ID); !errors.Is(err, sqlc.ErrNotFound) {
+			t.Errorf("member: expected other departments to be hidden, got %v", err)
+		}
+
+		if _, err := repo.Query().Find(context.Background()); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("no role: expected ErrPolicyDenied, got %v", err)
+		}
+		if n := count(sqlc.WithoutPolicies(context.Background()), repo.Query()); n != 3 {
+			t.Errorf("WithoutPolicies: expected 3 members, got %d", n)
+		}
+		// Models without policies are not restricted
+		if _, err := sqlc.NewRepository[Department](session).Query().Count(context.Background()); err != nil {
+			t.Errorf("unrestricted model: %v", err)
+		}
+	})
+
+	t.Run("Subquery", func(t *testing.T) {
+		id := field.Number[int64]{}.WithColumn("id")
+		ids := repo.Query().Select(id)
+
+		// Embedded queries have no context: they must be authorized explicitly
+		if _, err := repo.Query().Where(id.InExpr(ids)).Count(admin); err == nil {
+			t.Error("expected an unauthorized IN subquery to fail")
+		}
+		if _, err := repo.Query().FromSubquery(repo.Query(), "m").Find(admin); err == nil {
+			t.Error("expected an unauthorized FromSubquery to fail")
+		}
+
+		n, err := repo.Query().Where(id.InExpr(ids.Authorize(member))).Count(admin)
+		if err != nil || n != 2 {
+			t.Errorf("expected the subquery restricted to the member's department, got %d (err %v)", n, err)
+		}
+		found, err := repo.Query().FromSubquery(repo.Query().Authorize(admin), "m").Find(admin)
+		if err != nil || len(found) != 3 {
+			t.Errorf("admin: expected 3 members from the subquery, got %d (err %v)", len(found), err)
+		}
+		if _, err := repo.Query().Where(id.InExpr(ids.Authorize(context.Background()))).Count(admin); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("no role: expected ErrPolicyDenied, got %v", err)
+		}
+	})
+
+	t.Run("Insert", func(t *testing.T) {
+		auditor := sqlc.WithRoles(context.WithValue(context.Background(), deptKey{}, 1), "auditor")
+		if err := repo.Create(context.Background(), &Member{Name: "Nobody", Email: "nobody@test.com"}); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("no role: expected Create to be denied, got %v", err)
+		}
+		// A conditional create policy cannot filter new rows, so it denies
+		if err := repo.Create(auditor, &Member{Name: "Aud", Email: "aud@test.com", DepartmentID: 1}); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("auditor: expected Create to be denied, got %v", err)
+		}
+		if _, err := repo.CreateOrIgnore(auditor, &Member{Name: "Aud", Email: "aud@test.com"}); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("auditor: expected CreateOrIgnore to be denied, got %v", err)
+		}
+		if err := repo.BatchCreate(auditor, []*Member{{Name: "Aud", Email: "aud@test.com"}}); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("auditor: expected BatchCreate to be denied, got %v", err)
+		}
+		dan := &Member{Name: "Dan", Email: "dan@test.com", DepartmentID: 1}
+		if err := repo.Create(member, dan); err != nil {
+			t.Fatalf("member: Create failed: %v", err)
+		}
+		if err := repo.Delete(admin, dan.ID); err != nil {
+			t.Fatalf("admin: Delete failed: %v", err)
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		// The member's update policy is limited to its department, which ON CONFLICT DO UPDATE
+		// cannot enforce: overwriting another department's row must be refused
+		hijack := &Member{ID: seed[2].ID, Name: "Hacked", Email: "cid@test.com", Level: 7, DepartmentID: 2}
+		if err := repo.Upsert(member, hijack); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("member: expected Upsert to be denied, got %v", err)
+		}
+		if got, err := repo.FindOne(admin, seed[2].ID); err != nil || got.Name != "Cid" {
+			t.Errorf("expected the row to be untouched, got %+v (err %v)", got, err)
+		}
+		if err := repo.Upsert(admin, &Member{ID: seed[2].ID, Name: "Cid", Email: "cid@test.com", Level: 7, DepartmentID: 2}); err != nil {
+			t.Errorf("admin: Upsert failed: %v", err)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		merge := repo.Merge().Using(seed[2]).On(clause.Column{Name: "id"})
+		if _, err := merge.WhenMatchedUpdate(nil).Exec(member); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("member: expected MERGE update to be denied, got %v", err)
+		}
+		if _, err := merge.WhenMatchedDelete(nil).Exec(member); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("member: expected MERGE delete to be denied, got %v", err)
+		}
+		// Granted: the statement reaches the dialect, which has no MERGE
+		if _, err := merge.WhenMatchedDelete(nil).WhenNotMatchedInsert(nil).Exec(admin); !errors.Is(err, sqlc.ErrMergeUnsupported) {
+			t.Errorf("admin: expected ErrMergeUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		n, err := repo.UpdateColumnsResult(member, seed[2].ID, clause.Assignment{Column: name, Value: "Hacked"})
+		if err != nil || n != 0 {
+			t.Errorf("member: expected no update outside the department, got %d (err %v)", n, err)
+		}
+		n, err = repo.UpdateColumnsResult(member, seed[0].ID, clause.Assignment{Column: level, Value: 3})
+		if err != nil || n != 1 {
+			t.Errorf("member: expected own department update, got %d (err %v)", n, err)
+		}
+		if err := repo.Delete(member, seed[0].ID); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("member: expected delete to be denied, got %v", err)
+		}
+		if err := repo.Delete(admin, seed[0].ID); err != nil {
+			t.Errorf("admin: Delete failed: %v", err)
+		}
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		if _, err := db.Exec(`CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT,
+			deleted_at DATETIME
+		)`); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+		if _, err := db.Exec(`INSERT INTO products (name) VALUES ('a'), ('b')`); err != nil {
+			t.Fatalf("failed to seed table: %v", err)
+		}
+		products := sqlc.NewRepository[SoftDeleteProduct](session)
+		editor := sqlc.WithRoles(context.Background(), "editor")
+		byName := products.Where(clause.Eq{Column: name, Value: "b"})
+
+		// Soft deletes are deletes: the update policy does not grant them
+		if err := products.UpdateColumns(editor, 1, clause.Assignment{Column: name, Value: "a2"}); err != nil {
+			t.Errorf("editor: UpdateColumns failed: %v", err)
+		}
+		if err := products.Delete(editor, 1); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("editor: expected soft delete to be denied, got %v", err)
+		}
+		if _, err := byName.DeleteWhere(editor); !errors.Is(err, sqlc.ErrPolicyDenied) {
+			t.Errorf("editor: expected DeleteWhere to be denied, got %v", err)
+		}
+		if n, err := products.DeleteResult(admin, 1); err != nil || n != 1 {
+			t.Errorf("admin: expected soft delete, got %d (err %v)", n, err)
+		}
+		if n, err := byName.DeleteWhere(admin); err != nil || n != 1 {
+			t.Errorf("admin: expected DeleteWhere, got %d (err %v)", n, err)
+		}
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		tpl, err := repo.Query().Where(clause.Eq{Column: level, Value: sqlc.Param("level")}).Template()
+		if err != nil {
+			t.Fatalf("Template failed: %v", err)
+		}
+		if _, err := tpl.Bind("level", 2).Find(member); err == nil {
+			t.Error("expected a restricted template query to fail")
+		}
+		if _, err := tpl.Bind("level", 2).Find(admin); err != nil {
+			t.Errorf("admin: template Find failed: %v", err)
+		}
+	})
+}
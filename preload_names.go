@@ -0,0 +1,195 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements the named relation registry and preloads resolved by name at runtime.
+//
+// Generated code registers every relation under its field name (User_Posts as "Posts"),
+// so callers that only know relation names at runtime, such as GraphQL resolvers translating
+// requested fields, can preload them without hardcoding Preload(generated.X_Y) combinations.
+// Dotted paths preload nested relations of the loaded children.
+//
+// Usage example:
+//
+//	// Generated: sqlc.RegisterRelation("Posts", User_Posts), sqlc.RegisterRelation("Comments", Post_Comments)
+//	users, err := userRepo.Query().
+//	    PreloadByName("Posts", "Posts.Comments").
+//	    Find(ctx)
+//
+//	// Relation metadata, e.g. to validate a GraphQL selection up front
+//	for _, rel := range sqlc.RelationsOf[models.User]() {
+//	    fmt.Println(rel.Name, rel.Target) // Posts models.Post
+//	}
+package sqlc
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// RelationInfo describes a relation registered with RegisterRelation.
+type RelationInfo struct {
+	Name       string        // Registered name (the relation field, e.g. "Posts")
+	Type       RelationType  // HasOne, HasMany, BelongsTo or ManyToMany
+	Parent     reflect.Type  // Parent model type
+	Target     reflect.Type  // Child model type
+	ForeignKey clause.Column // Foreign key column in the child table
+	LocalKey   clause.Column // Local key column in the parent table
+	JoinTable  string        // Join table (ManyToMany only)
+}
+
+// namedRelation is a registered relation of parent model P.
+type namedRelation[P any] struct {
+	info RelationInfo
+	// preload creates the preload of the relation, preloading nested paths on the children
+	preload func(nested []*preloadPath) (preloadExecutor[P], error)
+	// associate saves the models set on the relation field of a created parent (WithAssociations)
+	associate func(ctx context.Context, repo *Repository[P], parent *P) error
+}
+
+// relations is the global registry of named relations, keyed by parent model type.
+// Values are map[string]namedRelation[P]. Like schemas, it is populated during
+// initialization and read-only afterwards.
+var relations = make(map[reflect.Type]any)
+
+// RegisterRelation registers rel under name for PreloadByName and RelationsOf.
+// Usually called by generated code in init().
+//
+// Parameters:
+//   - name: Relation name, usually the relation field (e.g. "Posts")
+//   - rel: Relation definition
+//
+// Example:
+//
+//	func init() {
+//	    sqlc.RegisterRelation("Posts", User_Posts)
+//	}
+//
+// Note:
+//   - Registering a name twice for the same parent overwrites the first registration
+func RegisterRelation[P, C any, K comparable](name string, rel Relation[P, C, K]) {
+	typ := reflect.TypeFor[P]()
+	named, _ := relations[typ].(map[string]namedRelation[P])
+	if named == nil {
+		named = make(map[string]namedRelation[P])
+		relations[typ] = named
+	}
+	named[name] = namedRelation[P]{
+		info: RelationInfo{
+			Name:       name,
+			Type:       rel.Type,
+			Parent:     typ,
+			Target:     reflect.TypeFor[C](),
+			ForeignKey: rel.ForeignKey,
+			LocalKey:   rel.LocalKey,
+			JoinTable:  rel.JoinTable,
+		},
+		preload: func(nested []*preloadPath) (preloadExecutor[P], error) {
+			children, err := resolvePreloads[C](nested)
+			if err != nil {
+				return nil, err
+			}
+			return Preload(rel).Then(children...), nil
+		},
+		associate: func(ctx context.Context, repo *Repository[P], parent *P) error {
+			children, err := relationField[C](parent, name)
+			if err != nil {
+				return err
+			}
+			return Association(repo, parent, rel).Append(ctx, children...)
+		},
+	}
+}
+
+// RelationsOf returns the relations registered for model T, sorted by name.
+func RelationsOf[T any]() []RelationInfo {
+	named, _ := relations[reflect.TypeFor[T]()].(map[string]namedRelation[T])
+	infos := make([]RelationInfo, 0, len(named))
+	for _, rel := range named {
+		infos = append(infos, rel.info)
+	}
+	slices.SortFunc(infos, func(a, b RelationInfo) int { return cmp.Compare(a.Name, b.Name) })
+	return infos
+}
+
+// preloadPath is a node of the tree of requested preload paths
+type preloadPath struct {
+	name   string
+	nested []*preloadPath
+}
+
+// parsePreloadPaths merges dotted paths ("Posts", "Posts.Comments") into a tree,
+// keeping the order in which relations were first requested.
+func parsePreloadPaths(paths []string) []*preloadPath {
+	var roots []*preloadPath
+	for _, path := range paths {
+		level := &roots
+		for name := range strings.SplitSeq(path, ".") {
+			name = strings.TrimSpace(name)
+			i := slices.IndexFunc(*level, func(p *preloadPath) bool { return p.name == name })
+			if i < 0 {
+				*level = append(*level, &preloadPath{name: name})
+				i = len(*level) - 1
+			}
+			level = &(*level)[i].nested
+		}
+	}
+	return roots
+}
+
+// resolvePreloads creates the preloads of model T for paths.
+func resolvePreloads[T any](paths []*preloadPath) ([]preloadExecutor[T], error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	named, _ := relations[reflect.TypeFor[T]()].(map[string]namedRelation[T])
+	preloads := make([]preloadExecutor[T], 0, len(paths))
+	for _, path := range paths {
+		rel, ok := named[path.name]
+		if !ok {
+			return nil, fmt.Errorf("sqlc: unknown relation %q of %v", path.name, reflect.TypeFor[T]())
+		}
+		preload, err := rel.preload(path.nested)
+		if err != nil {
+			return nil, err
+		}
+		preloads = append(preloads, preload)
+	}
+	return preloads, nil
+}
+
+// PreloadByName preloads relations by registered name (see RegisterRelation).
+// Dotted paths preload relations of the loaded children; "Posts.Comments" implies "Posts".
+//
+// Parameters:
+//   - paths: Relation names or dotted paths (e.g. "Posts", "Posts.Comments", "Profile")
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	// GraphQL resolver: preload what the client selected
+//	users, err := userRepo.Query().
+//	    PreloadByName(requestedRelations(ctx)...).
+//	    Find(ctx)
+//
+// Note:
+//   - An unknown name fails the query with an error naming the relation and model
+//   - Children are loaded without conditions; use WithPreload(Preload(rel, opts...)) to filter them
+func (q *QueryBuilder[T]) PreloadByName(paths ...string) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	preloads, err := resolvePreloads[T](parsePreloadPaths(paths))
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.preloads = append(q.preloads, preloads...)
+	return q
+}
@@ -0,0 +1,91 @@
+package sqlc_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+)
+
+func init() {
+	sqlc.RegisterRelation("Members", DepartmentHasMembers)
+	sqlc.RegisterRelation("Department", MemberDepartment)
+}
+
+func TestPreloadByName(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	deptRepo := sqlc.NewRepository[Department](session)
+	memberRepo := sqlc.NewRepository[Member](session)
+
+	eng := &Department{Name: "Engineering"}
+	if err := deptRepo.Create(ctx, eng); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	for _, name := range []string{"alice", "bob"} {
+		m := &Member{Name: name, Email: name + "@test.com", DepartmentID: int(eng.ID), CreatedAt: time.Now()}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	t.Run("Single", func(t *testing.T) {
+		depts, err := deptRepo.Query().PreloadByName("Members").Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(depts) != 1 || len(depts[0].Members) != 2 {
+			t.Fatalf("expected 1 department with 2 members, got %+v", depts)
+		}
+		if depts[0].Members[0].Department != nil {
+			t.Error("nested relation must not be loaded without a dotted path")
+		}
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		depts, err := deptRepo.Query().PreloadByName("Members.Department").Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(depts) != 1 || len(depts[0].Members) != 2 {
+			t.Fatalf("expected 1 department with 2 members, got %+v", depts)
+		}
+		for _, m := range depts[0].Members {
+			if m.Department == nil || m.Department.ID != eng.ID {
+				t.Errorf("member %s: expected department %d, got %+v", m.Name, eng.ID, m.Department)
+			}
+		}
+	})
+
+	t.Run("UnknownRelation", func(t *testing.T) {
+		for path, want := range map[string]string{
+			"Posts":           `unknown relation "Posts" of sqlc_test.Department`,
+			"Members.Manager": `unknown relation "Manager" of sqlc_test.Member`,
+		} {
+			_, err := deptRepo.Query().PreloadByName(path).Find(ctx)
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected error containing %q, got %v", path, want, err)
+			}
+		}
+	})
+
+	t.Run("RelationsOf", func(t *testing.T) {
+		rels := sqlc.RelationsOf[Department]()
+		if len(rels) != 1 {
+			t.Fatalf("expected 1 relation, got %+v", rels)
+		}
+		rel := rels[0]
+		if rel.Name != "Members" || rel.Type != sqlc.RelationHasMany || rel.ForeignKey.Name != "department_id" ||
+			rel.Parent != reflect.TypeFor[Department]() || rel.Target != reflect.TypeFor[Member]() {
+			t.Errorf("unexpected relation info: %+v", rel)
+		}
+		if rels := sqlc.RelationsOf[SoftDeleteProduct](); len(rels) != 0 {
+			t.Errorf("expected no relations, got %+v", rels)
+		}
+	})
+}
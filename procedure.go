@@ -0,0 +1,236 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements calls to stored procedures and functions.
+//
+// Codebases with existing stored procedures can adopt sqlc incrementally: CallProc and
+// CallFunc render the dialect's call syntax and run the call like any other session
+// statement (guards, logging, tracing, metrics, transactions, dry runs):
+//
+//	MySQL:      CALL transfer_funds(?, ?, ?, @sqlc_out1); SELECT @sqlc_out1
+//	PostgreSQL: CALL transfer_funds($1, $2, $3, NULL)
+//	Functions:  SELECT * FROM account_balance($1) (PostgreSQL), SELECT account_balance(?) (others)
+//
+// Usage example:
+//
+//	var balance float64
+//	err := session.CallProc(ctx, "transfer_funds", fromID, toID, amount, sqlc.OutParam{}).Scan(&balance)
+//
+//	var total int64
+//	err = session.CallFunc(ctx, "order_total", orderID).Scan(&total)
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrProcUnsupported is returned by CallProc on dialects without stored procedures (SQLite).
+var ErrProcUnsupported = errors.New("sqlc: stored procedures are not supported by this dialect")
+
+// procNameRegexp matches a procedure or function name, optionally schema-qualified.
+// Names are inlined into the SQL, so only plain identifiers are accepted.
+var procNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.[A-Za-z_][A-Za-z0-9_$]*)?$`)
+
+// OutParam marks an OUT parameter in the arguments of CallProc.
+// The values of the OUT parameters are the row read by Scan, in parameter order.
+//
+// Example:
+//
+//	// PROCEDURE get_stats(IN user_id BIGINT, OUT orders INT, OUT spent DECIMAL)
+//	var orders int
+//	var spent float64
+//	err := session.CallProc(ctx, "get_stats", userID, sqlc.OutParam{}, sqlc.OutParam{}).Scan(&orders, &spent)
+//
+// Note:
+//   - PostgreSQL returns OUT and INOUT parameters as the row of CALL (PostgreSQL 14+ for OUT)
+//   - MySQL reads them back from session variables on the same connection
+type OutParam struct{}
+
+// ProcCall is a pending call of a stored procedure or function, created by
+// Session.CallProc or Session.CallFunc. It runs when Scan or Exec is called.
+type ProcCall struct {
+	ctx      context.Context
+	session  *Session
+	name     string
+	args     []any
+	function bool
+}
+
+// CallProc prepares a call of a stored procedure.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - name: Procedure name, optionally schema-qualified ("billing.close_month")
+//   - args: Arguments in parameter order; pass OutParam{} for OUT parameters
+//
+// Returns:
+//   - *ProcCall: Call to run with Scan or Exec
+//
+// Example:
+//
+//	// Procedure returning a result set (MySQL) or INOUT parameters (PostgreSQL)
+//	var status string
+//	err := session.CallProc(ctx, "close_order", orderID).Scan(&status)
+//
+//	// Procedure returning nothing
+//	err = session.CallProc(ctx, "refresh_rankings").Exec()
+//
+// Note:
+//   - SQLite has no stored procedures: Scan and Exec return ErrProcUnsupported
+//   - The call runs on the primary, never on a read replica
+func (s *Session) CallProc(ctx context.Context, name string, args ...any) *ProcCall {
+	return &ProcCall{ctx: ctx, session: s, name: name, args: args}
+}
+
+// CallFunc prepares a call of a stored function, selected with SELECT on every dialect.
+// On PostgreSQL, set-returning functions and functions with OUT parameters return their
+// columns as the row read by Scan.
+//
+// Example:
+//
+//	var total int64
+//	err := session.CallFunc(ctx, "order_total", orderID).Scan(&total)
+//
+// Note:
+//   - OutParam is not accepted: functions return their result as the selected row
+func (s *Session) CallFunc(ctx context.Context, name string, args ...any) *ProcCall {
+	return &ProcCall{ctx: ctx, session: s, name: name, args: args, function: true}
+}
+
+// Scan runs the call and scans the first row it returns into dest: the OUT parameters if
+// any were passed, otherwise the first row of the procedure's result set or the function's
+// result.
+//
+// Returns:
+//   - error: Call or scan error; sql.ErrNoRows if the call returned no row
+func (c *ProcCall) Scan(dest ...any) error {
+	return c.run(dest, true)
+}
+
+// Exec runs the call and discards anything it returns.
+func (c *ProcCall) Exec() error {
+	return c.run(nil, false)
+}
+
+// procStatements is the rendered call: the statement calling the procedure, and for
+// MySQL OUT parameters, the statement reading them back.
+type procStatements struct {
+	call  string
+	args  []any
+	fetch string // "" if the call itself returns the row
+}
+
+// statements renders the call for the session's dialect.
+func (c *ProcCall) statements() (procStatements, error) {
+	if !procNameRegexp.MatchString(c.name) {
+		return procStatements{}, fmt.Errorf("sqlc: invalid procedure name %q", c.name)
+	}
+	dialect := c.session.dialect.Name()
+	if !c.function && dialect == "sqlite3" {
+		return procStatements{}, ErrProcUnsupported
+	}
+
+	var st procStatements
+	var fetch []string
+	params := make([]string, len(c.args))
+	for i, arg := range c.args {
+		if _, ok := arg.(OutParam); !ok {
+			params[i] = "?"
+			st.args = append(st.args, arg)
+			continue
+		}
+		switch {
+		case c.function:
+			return procStatements{}, fmt.Errorf("sqlc: CallFunc does not accept OutParam (argument %d of %s)", i+1, c.name)
+		case dialect == "postgres":
+			params[i] = "NULL"
+		default:
+			params[i] = fmt.Sprintf("@sqlc_out%d", len(fetch)+1)
+			fetch = append(fetch, params[i])
+		}
+	}
+
+	call := fmt.Sprintf("CALL %s(%s)", c.name, strings.Join(params, ", "))
+	if c.function {
+		call = fmt.Sprintf("SELECT %s(%s)", c.name, strings.Join(params, ", "))
+		if dialect == "postgres" {
+			call = fmt.Sprintf("SELECT * FROM %s(%s)", c.name, strings.Join(params, ", "))
+		}
+	}
+	var err error
+	if st.call, err = c.session.rebind(call); err != nil {
+		return procStatements{}, err
+	}
+	if len(fetch) > 0 {
+		st.fetch = "SELECT " + strings.Join(fetch, ", ")
+	}
+	return st, nil
+}
+
+// procExecutor is the part of Executor used by calls, also implemented by *sql.Conn.
+type procExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// run executes the call, scanning its row into dest if scan is set.
+func (c *ProcCall) run(dest []any, scan bool) error {
+	st, err := c.statements()
+	if err != nil {
+		return err
+	}
+	s := c.session
+	spanName := "sqlc.CallProc"
+	if c.function {
+		spanName = "sqlc.CallFunc"
+	}
+
+	// MySQL session variables belong to a connection: outside transactions, pin one
+	var exec procExecutor = s.executor
+	if st.fetch != "" && scan && s.tx == nil && s.recorder == nil {
+		conn, err := s.db.Conn(c.ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		exec = conn
+	}
+
+	if !scan || st.fetch != "" {
+		err := s.instrument(c.ctx, spanName, "call", st.call, st.args, func() error {
+			_, e := exec.ExecContext(c.ctx, st.call, st.args...)
+			return e
+		})
+		if err != nil || !scan {
+			return err
+		}
+		return s.instrument(c.ctx, spanName, "call", st.fetch, nil, func() error {
+			return scanFirstRow(c.ctx, exec, st.fetch, nil, dest)
+		})
+	}
+	return s.instrument(c.ctx, spanName, "call", st.call, st.args, func() error {
+		return scanFirstRow(c.ctx, exec, st.call, st.args, dest)
+	})
+}
+
+// scanFirstRow runs query and scans its first row into dest.
+func scanFirstRow(ctx context.Context, exec procExecutor, query string, args []any, dest []any) error {
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return err
+	}
+	return rows.Close()
+}
@@ -0,0 +1,76 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestCallProc(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Function", func(t *testing.T) {
+		db, _ := setupTestDB(t)
+		defer db.Close()
+		session := sqlc.NewSession(db, sqlc.SQLite, sqlc.WithQueryCapture(10))
+
+		var n int64
+		if err := session.CallFunc(ctx, "abs", -42).Scan(&n); err != nil || n != 42 {
+			t.Fatalf("expected 42, got %d (err %v)", n, err)
+		}
+		recent := session.RecentQueries(1)
+		if len(recent) != 1 || recent[0].Operation != "call" || recent[0].SQL != "SELECT abs(?)" {
+			t.Errorf("expected the call to be captured, got %+v", recent)
+		}
+
+		if err := session.CallFunc(ctx, "abs", sqlc.OutParam{}).Scan(&n); err == nil {
+			t.Error("expected OutParam to be rejected by CallFunc")
+		}
+		if err := session.CallFunc(ctx, "abs(1); DROP TABLE users; --").Exec(); err == nil {
+			t.Error("expected an invalid name to be rejected")
+		}
+		if err := session.CallProc(ctx, "refresh_rankings").Exec(); !errors.Is(err, sqlc.ErrProcUnsupported) {
+			t.Errorf("expected ErrProcUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("PostgreSQL", func(t *testing.T) {
+		dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+		if err := dry.CallProc(ctx, "billing.transfer", 1, 2, 9.5, sqlc.OutParam{}).Exec(); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		if err := dry.CallFunc(ctx, "order_total", 7).Exec(); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		want := []sqlc.Statement{
+			{Operation: "exec", SQL: "CALL billing.transfer($1, $2, $3, NULL)", Args: []any{1, 2, 9.5}},
+			{Operation: "exec", SQL: "SELECT * FROM order_total($1)", Args: []any{7}},
+		}
+		if got := dry.Recorder().Statements(); !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected statements %+v", got)
+		}
+	})
+
+	t.Run("MySQL", func(t *testing.T) {
+		dry := sqlc.NewSession(nil, sqlc.MySQL).DryRun()
+		var orders, spent int
+		err := dry.CallProc(ctx, "get_stats", 5, sqlc.OutParam{}, sqlc.OutParam{}).Scan(&orders, &spent)
+		if !errors.Is(err, sqlc.ErrDryRun) {
+			t.Fatalf("expected ErrDryRun, got %v", err)
+		}
+		if err := dry.CallFunc(ctx, "order_total", 7).Exec(); err != nil {
+			t.Fatalf("Exec failed: %v", err)
+		}
+		want := []sqlc.Statement{
+			{Operation: "exec", SQL: "CALL get_stats(?, @sqlc_out1, @sqlc_out2)", Args: []any{5}},
+			{Operation: "query", SQL: "SELECT @sqlc_out1, @sqlc_out2"},
+			{Operation: "exec", SQL: "SELECT order_total(?)", Args: []any{7}},
+		}
+		if got := dry.Recorder().Statements(); !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected statements %+v", got)
+		}
+	})
+}
@@ -13,6 +13,7 @@
 //   - Aggregation (COUNT)
 //   - Preloading (Preload)
 //   - Subqueries (Subquery)
+//   - Row locking (FOR UPDATE / FOR SHARE)
 //
 // Usage examples:
 //
@@ -45,7 +46,7 @@
 //	    Count(ctx)
 //
 // Design principles:
-//   - Immutability: Most methods return new QueryBuilder instances
+//   - Immutability: Chainable methods return new QueryBuilder instances
 //   - Type safety: Leverages generics for compile-time type checking
 //   - Composability: Build complex queries through method chaining
 //   - Automation: Automatically handles soft delete, column selection, etc.
@@ -55,6 +56,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
+	"reflect"
+	"slices"
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
@@ -102,8 +106,8 @@ var ErrNotFound = errors.New("sqlc: record not found")
 //	    Find(ctx)
 //
 // Notes:
-//   - QueryBuilder is not completely immutable, some methods modify internal state
-//   - If you need to reuse query, use WithBuilder() to create a copy
+//   - QueryBuilder is immutable: every chainable method returns a new instance
+//   - A base query can be shared (including across goroutines) and extended independently
 //   - Soft delete filter is automatically applied on creation
 type QueryBuilder[T any] struct {
 	// session is the database session for executing queries
@@ -115,9 +119,20 @@ type QueryBuilder[T any] struct {
 	// builder is the underlying Squirrel SelectBuilder for building SQL
 	builder sq.SelectBuilder
 
-	// columns is the list of column names to select
-	// If empty, uses schema.SelectColumns()
-	columns []string
+	// columns is the SELECT list set via Select(): column names (string)
+	// or built expressions (sq.Sqlizer). If empty, uses schema.SelectColumns()
+	columns []any
+
+	// selectExprs are computed select expressions added via SelectExpr()
+	// Rendered after columns, arguments are bound as parameters
+	selectExprs []sq.Sqlizer
+
+	// jsonPaths maps JSON columns to the key paths selected via SelectJSONPaths()
+	// Replaced on write, so clones may share it
+	jsonPaths map[string][][]string
+
+	// authorized is set once the read policy of the session (WithPolicies) is applied
+	authorized bool
 
 	// table is the main table name
 	table string
@@ -138,41 +153,77 @@ type QueryBuilder[T any] struct {
 	// When set, only returns records where deleted_at IS NOT NULL
 	onlyTrashed bool
 
+	// stages are post-processing steps added via Map()/Filter()
+	// Executed in order on scanned results, after preloads
+	stages []resultStage[T]
+
+	// wheres are the conditions added via Where()/OrWhere()/WhereGroup()
+	// Rendered as a single predicate in resolveBuilder()
+	wheres []whereCond
+
+	// plan holds per-query plan hints set via WithPlan()
+	plan QueryPlan
+
+	// debug logs the interpolated SQL on execution, set via WithDebug()
+	debug bool
+
+	// ordered indicates an explicit ORDER BY was added via OrderBy()/OrderByExpr()
+	ordered bool
+
+	// limited indicates a LIMIT or OFFSET was set, so subqueries keep their order
+	limited bool
+
+	// orderColumns are the column names sorted via OrderBy()
+	// Used to skip a redundant primary key tie-breaker
+	orderColumns []string
+
+	// tieBreak appends the primary key as the last ORDER BY column (see TieBreaker)
+	tieBreak bool
+
+	// tieBreakSet indicates TieBreaker() was called, so Page() keeps its choice
+	tieBreakSet bool
+
+	// unordered disables the schema's default order (see DefaultOrderer).
+	// Set by Unordered(), and by Distinct()/DistinctOn()/GroupBy(), whose
+	// queries cannot sort by arbitrary columns on PostgreSQL
+	unordered bool
+
+	// lock is the row locking mode (FOR UPDATE / FOR SHARE)
+	// Rendered by the dialect as a suffix on row-returning SELECTs
+	lock LockMode
+
+	// page is the page selected via Page(), read by Paginate()
+	page *pageRequest
+
 	// err stores the first error that occurred during query building
 	err error
 }
 
-// preloadExecutor is the function type for preload operations.
-// Called after main query completes, used to load associated data.
+// whereCond is a built WHERE condition and the connector joining it to the previous one.
+type whereCond struct {
+	sql  string
+	args []any
+	or   bool
+}
+
+// resultStage post-processes one scanned result.
+// Returns keep=false to drop the result from the output.
+type resultStage[T any] func(item *T) (keep bool, err error)
+
+// preloadExecutor loads associated data for the results of a query.
+// Called after main query completes; created by Preload(), or a Relation itself.
 //
-// Parameters:
+// load parameters:
 //   - ctx: Context for propagating cancellation signals and trace information
 //   - session: Database session for executing associated queries
 //   - results: Result list from main query
 //
-// Returns:
-//   - error: Preload error
-//
 // Use cases:
 //   - HasOne relation: Load single associated model
 //   - HasMany relation: Load multiple associated models
-//
-// Example (internal use):
-//
-//	preload := func(ctx context.Context, session *Session, users []*User) error {
-//	    // Load user's posts
-//	    for _, user := range users {
-//	        posts, err := postRepo.Query().
-//	            Where(generated.Post.UserID.Eq(user.ID)).
-//	            Find(ctx)
-//	        if err != nil {
-//	            return err
-//	        }
-//	        user.Posts = posts
-//	    }
-//	    return nil
-//	}
-type preloadExecutor[T any] func(ctx context.Context, session *Session, results []*T) error
+type preloadExecutor[T any] interface {
+	load(ctx context.Context, session *Session, results []*T) error
+}
 
 // Query creates a new QueryBuilder instance.
 // This is the starting point for building queries, usually called via Repository.Query().
@@ -204,7 +255,12 @@ type preloadExecutor[T any] func(ctx context.Context, session *Session, results
 //   - Returned QueryBuilder already contains soft delete filter (if applicable)
 func Query[T any](session *Session) *QueryBuilder[T] {
 	// Load model's Schema
-	schema := LoadSchema[T]()
+	return newQueryBuilder(session, LoadSchema[T]())
+}
+
+// newQueryBuilder creates a QueryBuilder selecting from the given schema's table.
+// Used by Query() and by repositories that carry their own schema (e.g. partition tables).
+func newQueryBuilder[T any](session *Session, schema Schema[T]) *QueryBuilder[T] {
 	table := schema.TableName()
 
 	// Create Squirrel SelectBuilder
@@ -226,6 +282,31 @@ func Query[T any](session *Session) *QueryBuilder[T] {
 	return q
 }
 
+// Clone returns a deep copy of the query builder.
+// Chainable methods already clone internally, so Clone is only needed when
+// handing a query to code that should not observe later changes.
+//
+// Returns:
+//   - *QueryBuilder[T]: Independent copy sharing no mutable state with q
+//
+// Example:
+//
+//	active := userRepo.Query().Where(generated.User.Status.Eq("active"))
+//
+//	// Both branches start from the same base query
+//	admins := active.Where(generated.User.Role.Eq("admin"))
+//	recent := active.OrderBy(generated.User.CreatedAt.Desc()).Limit(10)
+func (q *QueryBuilder[T]) Clone() *QueryBuilder[T] {
+	c := *q
+	c.columns = slices.Clone(q.columns)
+	c.selectExprs = slices.Clone(q.selectExprs)
+	c.preloads = slices.Clone(q.preloads)
+	c.wheres = slices.Clone(q.wheres)
+	c.stages = slices.Clone(q.stages)
+	c.orderColumns = slices.Clone(q.orderColumns)
+	return &c
+}
+
 // Where adds WHERE condition to the query.
 // Multiple calls to Where() will connect all conditions with AND.
 //
@@ -233,7 +314,7 @@ func Query[T any](session *Session) *QueryBuilder[T] {
 //   - expr: Condition expression (e.g., clause.Eq, clause.And, clause.Or, etc.)
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Supported expression types:
 //   - clause.Eq: Equals (=)
@@ -273,24 +354,149 @@ func Query[T any](session *Session) *QueryBuilder[T] {
 //	})
 //
 // Note:
-//   - Modifies current QueryBuilder instance and returns it
+//   - Returns a new QueryBuilder; the receiver is left unchanged
 //   - Conditions are connected with AND
-//   - Use clause.Or for OR conditions
+//   - Use OrWhere / WhereGroup (or clause.Or) for OR conditions
 func (q *QueryBuilder[T]) Where(expr clause.Expression) *QueryBuilder[T] {
+	return q.addWhere(expr, false)
+}
+
+// OrWhere adds a condition connected to the previous conditions with OR.
+// AND binds tighter than OR, so Where(a).Where(b).OrWhere(c) means (a AND b) OR c.
+// The whole condition list is parenthesized, so soft delete filters and
+// repository scopes still apply to every branch.
+//
+// Example:
+//
+//	// WHERE (users.role = ? OR users.id = ?) AND users.deleted_at IS NULL
+//	users, err := userRepo.Query().
+//	    Where(generated.User.Role.Eq("admin")).
+//	    OrWhere(generated.User.ID.Eq(1)).
+//	    Find(ctx)
+//
+// Note:
+//   - OrWhere on a query without conditions behaves like Where
+func (q *QueryBuilder[T]) OrWhere(expr clause.Expression) *QueryBuilder[T] {
+	return q.addWhere(expr, true)
+}
+
+// WhereGroup adds a parenthesized group of conditions connected with AND.
+// fn receives an empty query and returns it with the group's conditions;
+// only conditions (Where, OrWhere and nested groups) are taken from the result.
+//
+// Example:
+//
+//	// WHERE users.status = ? AND (users.age > ? OR users.parent_consent = ?)
+//	users, err := userRepo.Query().
+//	    Where(generated.User.Status.Eq("active")).
+//	    WhereGroup(func(g *sqlc.QueryBuilder[models.User]) *sqlc.QueryBuilder[models.User] {
+//	        return g.Where(generated.User.Age.Gt(18)).
+//	            OrWhere(generated.User.ParentConsent.Eq(true))
+//	    }).
+//	    Find(ctx)
+func (q *QueryBuilder[T]) WhereGroup(fn func(g *QueryBuilder[T]) *QueryBuilder[T]) *QueryBuilder[T] {
+	return q.addWhereGroup(fn, false)
+}
+
+// OrWhereGroup adds a parenthesized group of conditions connected with OR.
+// See WhereGroup for how the group is built.
+func (q *QueryBuilder[T]) OrWhereGroup(fn func(g *QueryBuilder[T]) *QueryBuilder[T]) *QueryBuilder[T] {
+	return q.addWhereGroup(fn, true)
+}
+
+// WhereIf adds expr as a WHERE condition only when cond is true.
+// Avoids if-chains around the builder for optional filters.
+//
+// Example:
+//
+//	users, err := userRepo.Query().
+//	    WhereIf(req.Status != "", generated.User.Status.Eq(req.Status)).
+//	    WhereIf(req.MinAge > 0, generated.User.Age.Gte(req.MinAge)).
+//	    Find(ctx)
+func (q *QueryBuilder[T]) WhereIf(cond bool, expr clause.Expression) *QueryBuilder[T] {
+	if !cond {
+		return q
+	}
+	return q.Where(expr)
+}
+
+// WhereNotZero adds an equality condition on col unless value is nil or
+// the zero value of its type ("", 0, false, nil pointer, ...).
+//
+// Example:
+//
+//	// Filters are applied only for non-empty query parameters
+//	users, err := userRepo.Query().
+//	    WhereNotZero(generated.User.Status, r.URL.Query().Get("status")).
+//	    WhereNotZero(generated.User.DepartmentID, req.DepartmentID).
+//	    Find(ctx)
+//
+// Note:
+//   - Use a pointer value to filter on a legitimate zero value (e.g. *bool for false)
+func (q *QueryBuilder[T]) WhereNotZero(col interface{ Column() clause.Column }, value any) *QueryBuilder[T] {
+	if value == nil || reflect.ValueOf(value).IsZero() {
+		return q
+	}
+	return q.Where(clause.Eq{Column: col.Column(), Value: value})
+}
+
+// addWhere builds expr and appends it to the condition list.
+func (q *QueryBuilder[T]) addWhere(expr clause.Expression, or bool) *QueryBuilder[T] {
+	q = q.Clone()
 	if q.err != nil {
 		return q
 	}
 	// Build expression to SQL and parameters
-	sql, args, err := expr.Build()
+	sql, args, err := clause.BindTypes(expr, typeNamer(q.session.dialect)).Build()
 	if err != nil {
 		q.err = err
 		return q
 	}
-	// Add to WHERE clause
-	q.builder = q.builder.Where(sq.Expr(sql, args...))
+	// clause.Or renders without outer parentheses; keep it atomic next to other conditions
+	if _, ok := expr.(clause.Or); ok {
+		sql = "(" + sql + ")"
+	}
+	q.wheres = append(q.wheres, whereCond{sql: sql, args: args, or: or})
+	return q
+}
+
+// addWhereGroup builds the group returned by fn and appends it as one condition.
+func (q *QueryBuilder[T]) addWhereGroup(fn func(g *QueryBuilder[T]) *QueryBuilder[T], or bool) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	g := fn(newQueryBuilder(q.session, q.schema))
+	if g.err != nil {
+		q.err = g.err
+		return q
+	}
+	if len(g.wheres) == 0 {
+		return q
+	}
+	sql, args := joinWheres(g.wheres)
+	q.wheres = append(q.wheres, whereCond{sql: "(" + sql + ")", args: args, or: or})
 	return q
 }
 
+// joinWheres joins conditions with their connectors into one predicate.
+func joinWheres(wheres []whereCond) (string, []any) {
+	var sb strings.Builder
+	var args []any
+	for i, w := range wheres {
+		if i > 0 {
+			if w.or {
+				sb.WriteString(" OR ")
+			} else {
+				sb.WriteString(" AND ")
+			}
+		}
+		sb.WriteString(w.sql)
+		args = append(args, w.args...)
+	}
+	return sb.String(), args
+}
+
 // OrderBy adds ORDER BY clause to the query.
 // Supports ascending (ASC) and descending (DESC) sorting.
 //
@@ -298,7 +504,7 @@ func (q *QueryBuilder[T]) Where(expr clause.Expression) *QueryBuilder[T] {
 //   - orders: Sort columns (variadic)
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -321,6 +527,7 @@ func (q *QueryBuilder[T]) Where(expr clause.Expression) *QueryBuilder[T] {
 //   - Multiple calls will append sort columns
 //   - Asc() means ascending, Desc() means descending
 func (q *QueryBuilder[T]) OrderBy(orders ...clause.OrderByColumn) *QueryBuilder[T] {
+	q = q.Clone()
 	if q.err != nil {
 		return q
 	}
@@ -332,10 +539,103 @@ func (q *QueryBuilder[T]) OrderBy(orders ...clause.OrderByColumn) *QueryBuilder[
 			return q
 		}
 		q.builder = q.builder.OrderBy(sql)
+		q.ordered = true
+		q.orderColumns = append(q.orderColumns, order.Column.Name)
+	}
+	return q
+}
+
+// OrderByExpr adds ORDER BY clauses built from arbitrary expressions.
+// Use it for orderings that are not plain columns, such as CASE priorities
+// or computed relevance scores. Expression arguments are bound as parameters.
+//
+// Parameters:
+//   - exprs: Sort expressions (variadic); wrap in clause.OrderByExpression for DESC
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	// Urgent tickets first, then high priority, then everything else
+//	query.OrderByExpr(
+//	    clause.Case{Operand: generated.Ticket.Status}.
+//	        When("urgent", 0).
+//	        When("high", 1).
+//	        Else(2),
+//	)
+//
+//	// Highest relevance first
+//	query.OrderByExpr(clause.OrderByExpression{
+//	    Expr: clause.Expr{SQL: "MATCH(title, body) AGAINST (?)", Vars: []any{term}},
+//	    Desc: true,
+//	})
+//
+// Note:
+//   - Can be mixed with OrderBy(); clauses are applied in call order
+func (q *QueryBuilder[T]) OrderByExpr(exprs ...clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	for _, expr := range exprs {
+		sql, args, err := clause.BindTypes(expr, typeNamer(q.session.dialect)).Build()
+		if err != nil {
+			q.err = err
+			return q
+		}
+		q.builder = q.builder.OrderByClause(sql, args...)
+		q.ordered = true
 	}
 	return q
 }
 
+// TieBreaker appends the primary key as the last ORDER BY column, so rows with equal
+// sort values (e.g. the same created_at) are returned in a stable order and pages
+// neither repeat nor skip them. Enabled by default for Page() queries.
+//
+// Parameters:
+//   - enabled: Whether to append the tie-breaker
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	// ORDER BY created_at DESC, id
+//	posts, err := postRepo.Query().
+//	    OrderBy(generated.Post.CreatedAt.Desc()).
+//	    TieBreaker(true).
+//	    Limit(20).
+//	    Find(ctx)
+//
+//	// Page without the tie-breaker
+//	page, err := postRepo.Query().OrderBy(generated.Post.Slug.Asc()).TieBreaker(false).Page(1, 20).Paginate(ctx)
+//
+// Note:
+//   - Not appended when OrderBy already sorts by the primary key
+//   - Sorts unordered queries by the primary key, except after Unordered(), Distinct()
+//     and GroupBy(), whose queries are not sorted
+func (q *QueryBuilder[T]) TieBreaker(enabled bool) *QueryBuilder[T] {
+	q = q.Clone()
+	q.tieBreak = enabled
+	q.tieBreakSet = true
+	return q
+}
+
+// Unordered disables the model's default order (see DefaultOrderer) for this query.
+// Explicit OrderBy clauses are kept.
+//
+// Example:
+//
+//	// Unsorted export; the database may return rows in any order
+//	posts, err := postRepo.Query().Unordered().Find(ctx)
+func (q *QueryBuilder[T]) Unordered() *QueryBuilder[T] {
+	q = q.Clone()
+	q.unordered = true
+	return q
+}
+
 // Limit limits the number of records returned by the query.
 // Used to implement pagination or limit result set size.
 //
@@ -343,7 +643,7 @@ func (q *QueryBuilder[T]) OrderBy(orders ...clause.OrderByColumn) *QueryBuilder[
 //   - n: Maximum number of records to return
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -357,7 +657,9 @@ func (q *QueryBuilder[T]) OrderBy(orders ...clause.OrderByColumn) *QueryBuilder[
 //   - 0 means no limit (some databases may not support this)
 //   - Usually used with Offset() for pagination
 func (q *QueryBuilder[T]) Limit(n uint64) *QueryBuilder[T] {
+	q = q.Clone()
 	q.builder = q.builder.Limit(n)
+	q.limited = true
 	return q
 }
 
@@ -368,7 +670,7 @@ func (q *QueryBuilder[T]) Limit(n uint64) *QueryBuilder[T] {
 //   - n: Number of records to skip
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -384,21 +686,139 @@ func (q *QueryBuilder[T]) Limit(n uint64) *QueryBuilder[T] {
 //   - Large offsets may impact performance
 //   - Consider using cursor pagination instead of large offsets
 func (q *QueryBuilder[T]) Offset(n uint64) *QueryBuilder[T] {
+	q = q.Clone()
 	q.builder = q.builder.Offset(n)
+	q.limited = true
 	return q
 }
 
 // Distinct adds DISTINCT to the SELECT clause, removing duplicate rows from results.
 // Example: repo.Query().Distinct().Select(UserFields.Email).Find(ctx)
 func (q *QueryBuilder[T]) Distinct() *QueryBuilder[T] {
+	q = q.Clone()
 	q.builder = q.builder.Distinct()
+	q.unordered = true
+	return q
+}
+
+// DistinctOn adds DISTINCT ON (columns) to the SELECT clause (PostgreSQL only).
+// Of each group of rows with equal values in columns, only the first row is kept;
+// combined with OrderBy this selects e.g. the latest row per group.
+//
+// Parameters:
+//   - columns: Columns that define the groups
+//
+// Example:
+//
+//	// Latest order of each user
+//	orders, err := orderRepo.Query().
+//	    DistinctOn(generated.Order.UserID).
+//	    OrderBy(generated.Order.UserID.Asc(), generated.Order.CreatedAt.Desc()).
+//	    Find(ctx)
+//
+// Note:
+//   - PostgreSQL requires ORDER BY to start with the DISTINCT ON columns
+//   - On MySQL and SQLite the query fails with an error instead of sending unsupported SQL
+func (q *QueryBuilder[T]) DistinctOn(columns ...clause.Columnar) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	if name := q.session.dialect.Name(); name != PostgreSQL.Name() {
+		q.err = fmt.Errorf("sqlc: DISTINCT ON is not supported by dialect %s", name)
+		return q
+	}
+	if len(columns) == 0 {
+		q.err = errors.New("sqlc: DistinctOn requires at least one column")
+		return q
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.ColumnName()
+	}
+	q.builder = q.builder.Options("DISTINCT ON (" + strings.Join(names, ", ") + ")")
+	q.unordered = true
+	return q
+}
+
+// Select replaces the selected columns.
+// Each item is a clause.Columnar (e.g. field.Field, clause.Column) or a clause.Expression
+// such as a function or aggregate call, a cast, a CASE expression, a raw clause.Expr, or
+// an aliased expression (the clause.SelectItem types); items are rendered in the given
+// order. Any other item fails the query.
+//
+// Example:
+//
+//	// SELECT users.department_id, COUNT(*) AS members, AVG(users.level) AS avg_level ...
+//	var rows []DeptStats
+//	err := userRepo.Query().
+//	    Select(
+//	        generated.User.DepartmentID,
+//	        clause.As(clause.CountAll(), "members"),
+//	        clause.Avg(generated.User.Level).As("avg_level"),
+//	    ).
+//	    GroupBy(generated.User.DepartmentID).
+//	    Scan(ctx, &rows)
+func (q *QueryBuilder[T]) Select(columns ...any) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	selected := make([]any, 0, len(columns))
+	for _, col := range columns {
+		switch c := col.(type) {
+		case clause.Expression:
+			sql, args, err := clause.BindTypes(c, typeNamer(q.session.dialect)).Build()
+			if err != nil {
+				q.err = err
+				return q
+			}
+			selected = append(selected, sq.Expr(sql, args...))
+		case clause.Columnar:
+			selected = append(selected, c.ColumnName())
+		default:
+			q.err = fmt.Errorf("sqlc: unsupported select item %T", col)
+			return q
+		}
+	}
+	q.columns = selected
 	return q
 }
 
-// Select replaces the selected columns
-// arguments must implement clause.Columnar (e.g. field.Field, clause.Column)
-func (q *QueryBuilder[T]) Select(columns ...clause.Columnar) *QueryBuilder[T] {
-	q.columns = ResolveColumnNames(columns)
+// SelectExpr adds computed expressions to the SELECT list.
+// Expressions are rendered after the selected columns (or the schema's default
+// columns when Select() was not called), with arguments bound as parameters.
+// Cast type names are translated for the session dialect.
+//
+// Parameters:
+//   - exprs: Select expressions (variadic); use .As("alias") to name the result column
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	var rows []struct {
+//	    ID          int64  `db:"id"`
+//	    DisplayName string `db:"display_name"`
+//	}
+//	err := userRepo.Query().
+//	    Select(generated.User.ID).
+//	    SelectExpr(clause.Coalesce(generated.User.Nickname, generated.User.Username).As("display_name")).
+//	    Scan(ctx, &rows)
+func (q *QueryBuilder[T]) SelectExpr(exprs ...clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	for _, expr := range exprs {
+		sql, args, err := clause.BindTypes(expr, typeNamer(q.session.dialect)).Build()
+		if err != nil {
+			q.err = err
+			return q
+		}
+		q.selectExprs = append(q.selectExprs, sq.Expr(sql, args...))
+	}
 	return q
 }
 
@@ -409,6 +829,7 @@ func (q *QueryBuilder[T]) Select(columns ...clause.Columnar) *QueryBuilder[T] {
 //
 //	repo.Query().WithTrashed().Find(ctx)
 func (q *QueryBuilder[T]) WithTrashed() *QueryBuilder[T] {
+	q = q.Clone()
 	q.withTrashed = true
 	return q
 }
@@ -419,11 +840,72 @@ func (q *QueryBuilder[T]) WithTrashed() *QueryBuilder[T] {
 //
 //	repo.Query().OnlyTrashed().Find(ctx)
 func (q *QueryBuilder[T]) OnlyTrashed() *QueryBuilder[T] {
+	q = q.Clone()
 	q.onlyTrashed = true
 	q.withTrashed = true
 	return q
 }
 
+// ForUpdate locks the selected rows for update (SELECT ... FOR UPDATE).
+// Should be used inside session.Transaction so locks are held until commit.
+//
+// Example:
+//
+//	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+//	    account, err := sqlc.NewRepository[models.Account](tx).Query().
+//	        Where(generated.Account.ID.Eq(id)).
+//	        ForUpdate().
+//	        Take(ctx)
+//	    // ... modify and save account
+//	})
+//
+// Note:
+//   - Rendered per dialect; SQLite ignores locking modifiers
+//   - Not applied to Count() and aggregate queries
+func (q *QueryBuilder[T]) ForUpdate() *QueryBuilder[T] {
+	q = q.Clone()
+	q.lock.Strength = LockForUpdate
+	return q
+}
+
+// ForShare locks the selected rows in shared mode (SELECT ... FOR SHARE).
+// Other transactions can read but not modify the rows until commit.
+//
+// Note:
+//   - MySQL renders LOCK IN SHARE MODE unless NoWait()/SkipLocked() is set
+//   - SQLite ignores locking modifiers
+func (q *QueryBuilder[T]) ForShare() *QueryBuilder[T] {
+	q = q.Clone()
+	q.lock.Strength = LockForShare
+	return q
+}
+
+// SkipLocked skips rows that are locked by other transactions (SKIP LOCKED).
+// Only takes effect together with ForUpdate() or ForShare().
+//
+// Example:
+//
+//	// Job queue: claim up to 10 pending jobs without blocking other workers
+//	jobs, err := jobRepo.Query().
+//	    Where(generated.Job.Status.Eq("pending")).
+//	    Limit(10).
+//	    ForUpdate().
+//	    SkipLocked().
+//	    Find(ctx)
+func (q *QueryBuilder[T]) SkipLocked() *QueryBuilder[T] {
+	q = q.Clone()
+	q.lock.Wait = LockSkipLocked
+	return q
+}
+
+// NoWait fails immediately instead of waiting when a row is locked (NOWAIT).
+// Only takes effect together with ForUpdate() or ForShare().
+func (q *QueryBuilder[T]) NoWait() *QueryBuilder[T] {
+	q = q.Clone()
+	q.lock.Wait = LockNoWait
+	return q
+}
+
 type tableNamer interface {
 	TableName() string
 }
@@ -434,10 +916,16 @@ const (
 	joinTypeInner joinType = iota
 	joinTypeLeft
 	joinTypeRight
+	joinTypeFull
 )
 
 func (q *QueryBuilder[T]) join(joinType joinType, target tableNamer, alias string, ons ...JoinOn) *QueryBuilder[T] {
-	if len(ons) == 0 {
+	q = q.Clone()
+	if len(ons) == 0 || q.err != nil {
+		return q
+	}
+	if joinType == joinTypeFull && q.session.dialect.Name() == MySQL.Name() {
+		q.err = fmt.Errorf("sqlc: FULL JOIN is not supported by dialect %s; combine a LEFT JOIN and a RIGHT JOIN query instead", MySQL.Name())
 		return q
 	}
 
@@ -468,6 +956,8 @@ func (q *QueryBuilder[T]) join(joinType joinType, target tableNamer, alias strin
 		q.builder = q.builder.LeftJoin(joinTableRef + " ON " + onSQL)
 	case joinTypeRight:
 		q.builder = q.builder.RightJoin(joinTableRef + " ON " + onSQL)
+	case joinTypeFull:
+		q.builder = q.builder.JoinClause("FULL JOIN " + joinTableRef + " ON " + onSQL)
 	default:
 		q.builder = q.builder.Join(joinTableRef + " ON " + onSQL)
 	}
@@ -483,7 +973,7 @@ func (q *QueryBuilder[T]) join(joinType joinType, target tableNamer, alias strin
 //   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -508,7 +998,7 @@ func (q *QueryBuilder[T]) Join(target tableNamer, ons ...JoinOn) *QueryBuilder[T
 //   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -532,7 +1022,7 @@ func (q *QueryBuilder[T]) JoinAs(target tableNamer, alias string, ons ...JoinOn)
 //   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -557,7 +1047,7 @@ func (q *QueryBuilder[T]) LeftJoin(target tableNamer, ons ...JoinOn) *QueryBuild
 //   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -577,7 +1067,7 @@ func (q *QueryBuilder[T]) LeftJoinAs(target tableNamer, alias string, ons ...Joi
 //   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -602,7 +1092,7 @@ func (q *QueryBuilder[T]) RightJoin(target tableNamer, ons ...JoinOn) *QueryBuil
 //   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -613,64 +1103,137 @@ func (q *QueryBuilder[T]) RightJoinAs(target tableNamer, alias string, ons ...Jo
 	return q.join(joinTypeRight, target, alias, ons...)
 }
 
-// JoinTable adds an INNER JOIN clause using raw table name and expression.
-// This provides maximum flexibility for complex join conditions.
+// FullJoin adds a FULL JOIN (FULL OUTER JOIN) clause to the query.
+// Returns all records from both tables, matched where possible.
+// Unmatched records will have NULL values for the columns of the other table.
 //
 // Parameters:
-//   - table: Raw table name (can include alias, e.g., "orders o")
-//   - on: Join condition as a clause.Expression
+//   - target: The schema of the table to join
+//   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
-//	// Simple join
-//	query.JoinTable("orders", clause.Expr{
-//	    SQL:  "users.id = orders.user_id",
-//	    Vars: nil,
-//	})
-//
-//	// Join with parameters
-//	query.JoinTable("orders", clause.Expr{
-//	    SQL:  "users.id = orders.user_id AND orders.total > ?",
-//	    Vars: []any{100},
-//	})
+//	// All users and all orders, paired where they match
+//	query.FullJoin(generated.OrderSchema{},
+//	    sqlc.On(generated.User.ID, generated.Order.UserID),
+//	)
 //
 // Note:
-//   - Use this for complex join conditions not supported by On()
-//   - Prefer Join() with On() for type safety when possible
-func (q *QueryBuilder[T]) JoinTable(table string, on clause.Expression) *QueryBuilder[T] {
-	if q.err != nil {
-		return q
-	}
-	sql, args, err := on.Build()
-	if err != nil {
-		q.err = err
-		return q
-	}
-	q.builder = q.builder.Join(table+" ON "+sql, args...)
-	q.hasJoin = true
-	return q
+//   - Supported by PostgreSQL and SQLite 3.39+
+//   - MySQL has no FULL JOIN; the query fails with an error
+func (q *QueryBuilder[T]) FullJoin(target tableNamer, ons ...JoinOn) *QueryBuilder[T] {
+	return q.join(joinTypeFull, target, "", ons...)
 }
 
-// LeftJoinTable adds a LEFT JOIN clause using raw table name and expression.
-// Combines LEFT JOIN behavior with maximum flexibility.
+// FullJoinAs adds a FULL JOIN clause with a custom table alias.
+// Combines FULL JOIN behavior with custom aliasing.
 //
 // Parameters:
-//   - table: Raw table name (can include alias)
-//   - on: Join condition as a clause.Expression
+//   - target: The schema of the table to join
+//   - alias: Custom alias for the joined table
+//   - ons: Join conditions created with On() function
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
-//	query.LeftJoinTable("orders o", clause.Expr{
-//	    SQL:  "users.id = o.user_id",
-//	    Vars: nil,
-//	})
-func (q *QueryBuilder[T]) LeftJoinTable(table string, on clause.Expression) *QueryBuilder[T] {
+//	query.FullJoinAs(generated.OrderSchema{}, "o",
+//	    sqlc.On(generated.User.ID, clause.Column{Name: "user_id", Table: "o"}),
+//	)
+func (q *QueryBuilder[T]) FullJoinAs(target tableNamer, alias string, ons ...JoinOn) *QueryBuilder[T] {
+	return q.join(joinTypeFull, target, alias, ons...)
+}
+
+// CrossJoin adds a CROSS JOIN clause to the query.
+// Returns every combination of rows from both tables (Cartesian product).
+//
+// Parameters:
+//   - target: The schema of the table to join
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Usage example:
+//
+//	// Every user paired with every plan
+//	query.CrossJoin(generated.PlanSchema{})
+//
+// Note:
+//   - The result size is the product of both tables; filter with Where()
+func (q *QueryBuilder[T]) CrossJoin(target tableNamer) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	q.builder = q.builder.JoinClause("CROSS JOIN " + target.TableName())
+	q.hasJoin = true
+	return q
+}
+
+// JoinTable adds an INNER JOIN clause using raw table name and expression.
+// This provides maximum flexibility for complex join conditions.
+//
+// Parameters:
+//   - table: Raw table name (can include alias, e.g., "orders o")
+//   - on: Join condition as a clause.Expression
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Usage example:
+//
+//	// Simple join
+//	query.JoinTable("orders", clause.Expr{
+//	    SQL:  "users.id = orders.user_id",
+//	    Vars: nil,
+//	})
+//
+//	// Join with parameters
+//	query.JoinTable("orders", clause.Expr{
+//	    SQL:  "users.id = orders.user_id AND orders.total > ?",
+//	    Vars: []any{100},
+//	})
+//
+// Note:
+//   - Use this for complex join conditions not supported by On()
+//   - Prefer Join() with On() for type safety when possible
+func (q *QueryBuilder[T]) JoinTable(table string, on clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	sql, args, err := on.Build()
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.builder = q.builder.Join(table+" ON "+sql, args...)
+	q.hasJoin = true
+	return q
+}
+
+// LeftJoinTable adds a LEFT JOIN clause using raw table name and expression.
+// Combines LEFT JOIN behavior with maximum flexibility.
+//
+// Parameters:
+//   - table: Raw table name (can include alias)
+//   - on: Join condition as a clause.Expression
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Usage example:
+//
+//	query.LeftJoinTable("orders o", clause.Expr{
+//	    SQL:  "users.id = o.user_id",
+//	    Vars: nil,
+//	})
+func (q *QueryBuilder[T]) LeftJoinTable(table string, on clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
 	if q.err != nil {
 		return q
 	}
@@ -692,7 +1255,7 @@ func (q *QueryBuilder[T]) LeftJoinTable(table string, on clause.Expression) *Que
 //   - on: Join condition as a clause.Expression
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -701,6 +1264,7 @@ func (q *QueryBuilder[T]) LeftJoinTable(table string, on clause.Expression) *Que
 //	    Vars: nil,
 //	})
 func (q *QueryBuilder[T]) RightJoinTable(table string, on clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
 	if q.err != nil {
 		return q
 	}
@@ -714,6 +1278,117 @@ func (q *QueryBuilder[T]) RightJoinTable(table string, on clause.Expression) *Qu
 	return q
 }
 
+// Subquery is a query that can be used as a derived table in FromSubquery() and LeftJoinLateral().
+// It is implemented by *QueryBuilder[T] for any model T.
+type Subquery interface {
+	clause.Expression
+	selectBuilder() (sq.SelectBuilder, error)
+}
+
+// FromSubquery selects from the result set of another query (a derived table)
+// instead of the model's table: SELECT ... FROM (subquery) AS alias.
+// Columns are resolved against alias, so the subquery must return the columns
+// being selected (T's columns by default).
+//
+// Parameters:
+//   - sub: Inner query, e.g. another QueryBuilder (any model type)
+//   - alias: Name of the derived table
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	// Top 2 members per department
+//	ranked := memberRepo.Query().
+//	    SelectExpr(clause.As(clause.Expr{SQL: "ROW_NUMBER() OVER (PARTITION BY department_id ORDER BY level DESC)"}, "rn"))
+//
+//	top, err := memberRepo.Query().
+//	    FromSubquery(ranked, "ranked").
+//	    Where(clause.Expr{SQL: "rn <= ?", Vars: []any{2}}).
+//	    Find(ctx)
+//
+// Note:
+//   - Only the SQL of sub is used; its preloads and Map/Filter stages are not run
+//   - Soft delete filtering of T is applied to the outer query as well
+func (q *QueryBuilder[T]) FromSubquery(sub Subquery, alias string) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	sb, err := sub.selectBuilder()
+	if err != nil {
+		q.err = err
+		return q
+	}
+	q.builder = q.builder.FromSelect(sb, alias)
+	q.table = alias
+	return q
+}
+
+// LeftJoinLateral adds a LEFT JOIN LATERAL clause (PostgreSQL only):
+// LEFT JOIN LATERAL (subquery) alias ON condition.
+// Unlike a plain derived table, a lateral subquery may reference columns of the
+// tables before it, e.g. to fetch the latest N child rows per parent row.
+//
+// Parameters:
+//   - sub: Inner query, usually correlated with the outer table
+//   - alias: Name of the lateral derived table
+//   - on: Join condition; nil renders ON true
+//
+// Returns:
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
+//
+// Example:
+//
+//	// Latest order of each user
+//	latest := orderRepo.Query().
+//	    Where(clause.Expr{SQL: "orders.user_id = users.id"}).
+//	    OrderBy(generated.Order.CreatedAt.Desc()).
+//	    Limit(1)
+//
+//	rows, err := userRepo.Query().
+//	    Select(generated.User.Name, clause.Column{Table: "o", Name: "total"}).
+//	    LeftJoinLateral(latest, "o", nil).
+//	    Find(ctx)
+//
+// Note:
+//   - On MySQL and SQLite the query fails with an error instead of sending unsupported SQL
+func (q *QueryBuilder[T]) LeftJoinLateral(sub Subquery, alias string, on clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
+	if q.err != nil {
+		return q
+	}
+	if name := q.session.dialect.Name(); name != PostgreSQL.Name() {
+		q.err = fmt.Errorf("sqlc: LATERAL joins are not supported by dialect %s", name)
+		return q
+	}
+	sb, err := sub.selectBuilder()
+	if err != nil {
+		q.err = err
+		return q
+	}
+	// Placeholders are numbered when the outer query is built
+	subSQL, args, err := sb.PlaceholderFormat(sq.Question).ToSql()
+	if err != nil {
+		q.err = err
+		return q
+	}
+	onSQL := "true"
+	if on != nil {
+		sql, onArgs, err := on.Build()
+		if err != nil {
+			q.err = err
+			return q
+		}
+		onSQL = sql
+		args = append(args, onArgs...)
+	}
+	q.builder = q.builder.JoinClause("LEFT JOIN LATERAL ("+subSQL+") "+alias+" ON "+onSQL, args...)
+	q.hasJoin = true
+	return q
+}
+
 // GroupBy adds GROUP BY clause to the query for aggregation.
 // Used with aggregate functions like COUNT, SUM, AVG, MAX, MIN.
 //
@@ -721,7 +1396,7 @@ func (q *QueryBuilder[T]) RightJoinTable(table string, on clause.Expression) *Qu
 //   - columns: Columns to group by (must implement clause.Columnar)
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -736,7 +1411,7 @@ func (q *QueryBuilder[T]) RightJoinTable(table string, on clause.Expression) *Qu
 //
 //	// With aggregation
 //	query.
-//	    Select(generated.User.Status, clause.Count("*")).
+//	    Select(generated.User.Status, clause.As(clause.CountAll(), "total")).
 //	    GroupBy(generated.User.Status)
 //
 // Note:
@@ -744,7 +1419,9 @@ func (q *QueryBuilder[T]) RightJoinTable(table string, on clause.Expression) *Qu
 //   - Use Having() to filter grouped results
 //   - Arguments must implement clause.Columnar (e.g., field.Field, clause.Column)
 func (q *QueryBuilder[T]) GroupBy(columns ...clause.Columnar) *QueryBuilder[T] {
+	q = q.Clone()
 	q.builder = q.builder.GroupBy(ResolveColumnNames(columns)...)
+	q.unordered = true
 	return q
 }
 
@@ -755,7 +1432,7 @@ func (q *QueryBuilder[T]) GroupBy(columns ...clause.Columnar) *QueryBuilder[T] {
 //   - expr: Filter condition expression
 //
 // Returns:
-//   - *QueryBuilder[T]: Returns itself to support chaining
+//   - *QueryBuilder[T]: New QueryBuilder, supports method chaining
 //
 // Usage example:
 //
@@ -778,10 +1455,11 @@ func (q *QueryBuilder[T]) GroupBy(columns ...clause.Columnar) *QueryBuilder[T] {
 //   - Can reference aggregate functions in conditions
 //   - Conditions are applied after grouping, not before
 func (q *QueryBuilder[T]) Having(expr clause.Expression) *QueryBuilder[T] {
+	q = q.Clone()
 	if q.err != nil {
 		return q
 	}
-	sql, args, err := expr.Build()
+	sql, args, err := clause.BindTypes(expr, typeNamer(q.session.dialect)).Build()
 	if err != nil {
 		q.err = err
 		return q
@@ -790,10 +1468,58 @@ func (q *QueryBuilder[T]) Having(expr clause.Expression) *QueryBuilder[T] {
 	return q
 }
 
+// Map adds a post-processing step that runs on every result after scanning and preloads.
+// Use it to enrich or sanitize models next to the query that loads them.
+// Steps added with Map and Filter run in the order they were added.
+//
+// Example:
+//
+//	users, err := userRepo.Query().
+//	    WithPreload(sqlc.Preload(generated.User_Posts)).
+//	    Map(func(u *models.User) error {
+//	        u.PasswordHash = "" // never leaves the data layer
+//	        u.PostCount = len(u.Posts)
+//	        return nil
+//	    }).
+//	    Find(ctx)
+//
+// Note:
+//   - An error aborts the query and is returned wrapped by Find()
+//   - Applied by Find, Take, First, Last, Chunk, ChunkStream and Rows; not by Scan/Pluck
+func (q *QueryBuilder[T]) Map(fn func(*T) error) *QueryBuilder[T] {
+	q = q.Clone()
+	q.stages = append(q.stages, func(item *T) (bool, error) {
+		return true, fn(item)
+	})
+	return q
+}
+
+// Filter adds a post-processing step that drops results for which keep returns false.
+// Runs after scanning and preloads, in order with Map steps.
+//
+// Example:
+//
+//	visible, err := postRepo.Query().
+//	    Filter(func(p *models.Post) bool { return acl.CanRead(user, p) }).
+//	    Find(ctx)
+//
+// Note:
+//   - Filtering happens in Go after LIMIT/OFFSET, so pages may contain fewer
+//     rows (and Take/First may return ErrNotFound); prefer Where() when possible
+func (q *QueryBuilder[T]) Filter(keep func(*T) bool) *QueryBuilder[T] {
+	q = q.Clone()
+	q.stages = append(q.stages, func(item *T) (bool, error) {
+		return keep(item), nil
+	})
+	return q
+}
+
 // WithPreload adds a preload executor to load related data after the main query.
 // Use with Preload() function to create type-safe preload executors.
 // It supports customizing the loaded child models by providing optional query builder functions to sqlc.Preload().
+// Hand-written preload functions are wrapped with PreloadFunc.
 func (q *QueryBuilder[T]) WithPreload(preload preloadExecutor[T]) *QueryBuilder[T] {
+	q = q.Clone()
 	q.preloads = append(q.preloads, preload)
 	return q
 }
@@ -833,28 +1559,44 @@ func (q *QueryBuilder[T]) WithPreload(preload preloadExecutor[T]) *QueryBuilder[
 //   - Preloads are executed in the order they were added
 //   - Context is propagated to all database operations
 func (q *QueryBuilder[T]) Find(ctx context.Context) ([]*T, error) {
+	q = q.authorize(ctx)
 	if q.err != nil {
 		return nil, q.err
 	}
-	b := q.resolveBuilder().Columns(q.resolveColumns()...)
+	if q.hasJoinedPreloads() {
+		return q.findJoined(ctx)
+	}
+	b := q.applyLock(q.applyOrder(q.applySelect(q.resolveBuilder())))
 	query, args, err := b.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
 	}
+	return q.find(ctx, query, args)
+}
 
-	var results []*T
-	if err := q.session.Select(ctx, &results, query, args...); err != nil {
-		return nil, fmt.Errorf("sqlc: query failed: %w", err)
+// find runs the built SELECT of Find, then preloads and result stages.
+func (q *QueryBuilder[T]) find(ctx context.Context, query string, args []any) ([]*T, error) {
+	if err := q.checkColumns(ctx); err != nil {
+		return nil, err
+	}
+	q.logDebug(ctx, query, args)
+	results, err := q.findShared(ctx, query, args)
+	if err != nil {
+		return nil, q.queryFailed(err)
 	}
+	return q.finish(ctx, results)
+}
 
+// finish runs the preloads and result stages on the results of Find.
+func (q *QueryBuilder[T]) finish(ctx context.Context, results []*T) ([]*T, error) {
 	// Execute preloads
 	for _, preload := range q.preloads {
-		if err := preload(ctx, q.session, results); err != nil {
+		if err := preload.load(ctx, q.session, results); err != nil {
 			return nil, fmt.Errorf("sqlc: preload failed: %w", err)
 		}
 	}
 
-	return results, nil
+	return q.applyStages(results)
 }
 
 // Pluck queries a single column and returns the values as a slice.
@@ -866,17 +1608,18 @@ func (q *QueryBuilder[T]) Find(ctx context.Context) ([]*T, error) {
 //	var emails []string
 //	userRepo.Query().Where(generated.User.Active.Eq(true)).Pluck(ctx, generated.User.Email, &emails)
 func (q *QueryBuilder[T]) Pluck(ctx context.Context, column clause.Columnar, dest any) error {
+	q = q.authorize(ctx)
 	if q.err != nil {
 		return q.err
 	}
 	colName := column.ColumnName()
-	b := q.resolveBuilder().Columns(colName)
+	b := q.applyLock(q.resolveBuilder().Columns(colName))
 	query, args, err := b.ToSql()
 	if err != nil {
 		return fmt.Errorf("sqlc: failed to build sql: %w", err)
 	}
 
-	if err := q.session.Select(ctx, dest, query, args...); err != nil {
+	if err := q.reader(ctx).Select(ctx, dest, query, args...); err != nil {
 		return fmt.Errorf("sqlc: pluck failed: %w", err)
 	}
 
@@ -896,19 +1639,22 @@ func (q *QueryBuilder[T]) Pluck(ctx context.Context, column clause.Columnar, des
 //	    }
 //	    return nil
 //	})
+//
+// Note:
+//   - Each batch re-issues the query with a growing OFFSET; for large tables or
+//     tables with concurrent writes prefer ChunkStream, which reads a single cursor
 func (q *QueryBuilder[T]) Chunk(ctx context.Context, size int, fn func([]*T) error) error {
 	if size <= 0 {
 		return fmt.Errorf("sqlc: chunk size must be positive, got %d", size)
 	}
 
+	// Stages run per page after paging decisions, since Filter() may shrink a page
+	page := q.Clone()
+	page.stages = nil
+
 	offset := uint64(0)
 	for {
-		// Copy the current QueryBuilder to avoid mutation issues.
-		// Uses struct copy (sq.SelectBuilder is a value type, so this is safe).
-		chunkQuery := *q
-		chunkQuery.builder = q.builder.Limit(uint64(size)).Offset(offset)
-
-		results, err := chunkQuery.Find(ctx)
+		results, err := page.Limit(uint64(size)).Offset(offset).Find(ctx)
 		if err != nil {
 			return err
 		}
@@ -917,9 +1663,15 @@ func (q *QueryBuilder[T]) Chunk(ctx context.Context, size int, fn func([]*T) err
 			break
 		}
 
-		if err := fn(results); err != nil {
+		filtered, err := q.applyStages(results)
+		if err != nil {
 			return err
 		}
+		if len(filtered) > 0 {
+			if err := fn(filtered); err != nil {
+				return err
+			}
+		}
 
 		if len(results) < size {
 			break // Last batch
@@ -931,21 +1683,250 @@ func (q *QueryBuilder[T]) Chunk(ctx context.Context, size int, fn func([]*T) err
 	return nil
 }
 
+// ChunkStream processes query results in batches like Chunk, but reads all batches
+// from a single cursor instead of re-issuing the query with a growing OFFSET.
+// The query runs once, so large tables are read in O(n) and concurrent writes
+// cannot cause rows to be skipped or returned twice between batches.
+//
+// Parameters:
+//   - ctx: Context for cancellation; canceling stops the stream mid-iteration
+//   - size: Maximum number of records per batch (must be positive)
+//   - fn: Callback receiving each batch; returning an error stops streaming
+//
+// Returns:
+//   - error: Query, scan or callback error
+//
+// Example:
+//
+//	err := userRepo.Query().
+//	    Where(generated.User.Active.Eq(true)).
+//	    ChunkStream(ctx, 500, func(users []*models.User) error {
+//	        return exportUsers(users)
+//	    })
+//
+// Note:
+//   - The cursor holds a connection until streaming finishes; inside a transaction
+//     (or with a single-connection pool) the callback must not run other queries
+//     on the same session, as most drivers cannot interleave them with an open cursor
+//   - Preload is not supported, since preload queries would run while the cursor is open;
+//     use Chunk instead
+func (q *QueryBuilder[T]) ChunkStream(ctx context.Context, size int, fn func([]*T) error) error {
+	q = q.authorize(ctx)
+	if q.err != nil {
+		return q.err
+	}
+	if size <= 0 {
+		return fmt.Errorf("sqlc: chunk size must be positive, got %d", size)
+	}
+	if len(q.preloads) > 0 {
+		return fmt.Errorf("sqlc: ChunkStream does not support Preload, use Chunk")
+	}
+
+	b := q.applyLock(q.applyOrder(q.applySelect(q.resolveBuilder())))
+	query, args, err := b.ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	rows, err := q.reader(ctx).queryx(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("sqlc: query failed: %w", err)
+	}
+	defer rows.Close()
+
+	batch := make([]*T, 0, size)
+	flush := func() error {
+		filtered, err := q.applyStages(batch)
+		if err != nil {
+			return err
+		}
+		if len(filtered) > 0 {
+			if err := fn(filtered); err != nil {
+				return err
+			}
+		}
+		batch = make([]*T, 0, size)
+		return nil
+	}
+
+	for rows.Next() {
+		item := new(T)
+		if err := rows.StructScan(item); err != nil {
+			return q.scanFailed(err)
+		}
+		batch = append(batch, item)
+		if len(batch) == size {
+			if err := flush(); err != nil {
+				return err
+			}
+			// Stop promptly if the callback canceled the context
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("sqlc: query failed: %w", err)
+	}
+
+	if len(batch) > 0 {
+		return flush()
+	}
+	return nil
+}
+
+// Rows executes the query and returns an iterator over the results.
+// Records are scanned one at a time from a single cursor, so the full result
+// set is never held in memory.
+//
+// Parameters:
+//   - ctx: Context for cancellation; checked before every record
+//
+// Returns:
+//   - iter.Seq2[*T, error]: Iterator yielding each record, or a single error
+//     (build, query, scan or cancellation) after which iteration ends
+//
+// Example:
+//
+//	for user, err := range userRepo.Query().Where(generated.User.Active.Eq(true)).Rows(ctx) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(user)
+//	}
+//
+// Note:
+//   - The query runs when iteration starts; breaking out of the loop closes the cursor
+//   - Preload is not supported (it would query per record); use Chunk instead
+//   - As with ChunkStream, avoid running other queries on the same transaction
+//     session inside the loop
+func (q *QueryBuilder[T]) Rows(ctx context.Context) iter.Seq2[*T, error] {
+	q = q.authorize(ctx)
+	return func(yield func(*T, error) bool) {
+		if q.err != nil {
+			yield(nil, q.err)
+			return
+		}
+		if len(q.preloads) > 0 {
+			yield(nil, fmt.Errorf("sqlc: Rows does not support Preload, use Chunk"))
+			return
+		}
+
+		b := q.applyLock(q.applyOrder(q.applySelect(q.resolveBuilder())))
+		query, args, err := b.ToSql()
+		if err != nil {
+			yield(nil, fmt.Errorf("sqlc: failed to build sql: %w", err))
+			return
+		}
+
+		rows, err := q.reader(ctx).queryx(ctx, query, args...)
+		if err != nil {
+			yield(nil, fmt.Errorf("sqlc: query failed: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			item := new(T)
+			if err := rows.StructScan(item); err != nil {
+				yield(nil, q.scanFailed(err))
+				return
+			}
+			keep, err := q.runStages(item)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if keep && !yield(item, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(nil, fmt.Errorf("sqlc: query failed: %w", err))
+		}
+	}
+}
+
+// FindChan executes the query and streams the scanned records into a channel as they
+// arrive, so pipeline consumers (ETL stages) overlap processing with the database read
+// instead of waiting for the full result.
+//
+// Parameters:
+//   - ctx: Context for cancellation; canceling stops the producer and closes the cursor
+//   - buf: Channel buffer size; records read ahead of the consumer (0: unbuffered)
+//
+// Returns:
+//   - <-chan *T: Records in query order; closed when the query ends or fails
+//   - <-chan error: Receives the query, scan or cancellation error, if any, before the
+//     record channel is closed; closed afterwards
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	defer cancel() // Releases the cursor if the consumer stops early
+//
+//	users, errc := userRepo.Query().Where(generated.User.Active.Eq(true)).FindChan(ctx, 100)
+//	for u := range users {
+//	    export(u)
+//	}
+//	if err := <-errc; err != nil {
+//	    return err
+//	}
+//
+// Note:
+//   - The records are read by a goroutine through Rows; a consumer that stops receiving
+//     must cancel ctx, otherwise the goroutine and its connection are held forever
+//   - Preload is not supported, as with Rows; use Chunk instead
+func (q *QueryBuilder[T]) FindChan(ctx context.Context, buf int) (<-chan *T, <-chan error) {
+	out := make(chan *T, max(0, buf))
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		for item, err := range q.Rows(ctx) {
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return out, errc
+}
+
 // Scan executes the query and scans the results into a custom destination.
 // dest can be a pointer to a struct or a pointer to a slice of structs.
 // This is useful for partial selections or joins mapping to DTOs.
 func (q *QueryBuilder[T]) Scan(ctx context.Context, dest any) error {
+	q = q.authorize(ctx)
 	if q.err != nil {
 		return q.err
 	}
 	// Apply columns to builder
-	b := q.resolveBuilder().Columns(q.resolveColumns()...)
+	b := q.applyLock(q.applyOrder(q.applySelect(q.resolveBuilder())))
 	query, args, err := b.ToSql()
 	if err != nil {
 		return fmt.Errorf("sqlc: failed to build sql: %w", err)
 	}
+	return q.scan(ctx, dest, query, args)
+}
 
-	if err := q.session.Select(ctx, dest, query, args...); err != nil {
+// scan runs the built SELECT of Scan into dest.
+func (q *QueryBuilder[T]) scan(ctx context.Context, dest any, query string, args []any) error {
+	q.logDebug(ctx, query, args)
+	err := q.reader(ctx).withPlanCacheMode(ctx, q.plan.CacheMode, func(s *Session) error {
+		return s.Select(ctx, dest, q.plan.annotate(query), args...)
+	})
+	if err != nil {
 		return fmt.Errorf("sqlc: query failed: %w", err)
 	}
 	return nil
@@ -1102,6 +2083,7 @@ func (q *QueryBuilder[T]) FirstOr(ctx context.Context, fallback func() *T) (*T,
 //   - Respects soft delete filter (unless WithTrashed() called)
 //   - Does not execute preloads
 func (q *QueryBuilder[T]) Count(ctx context.Context) (int64, error) {
+	q = q.authorize(ctx)
 	if q.err != nil {
 		return 0, q.err
 	}
@@ -1117,23 +2099,82 @@ func (q *QueryBuilder[T]) Count(ctx context.Context) (int64, error) {
 		return 0, fmt.Errorf("sqlc: failed to build count sql: %w", err)
 	}
 
-	var count int64
-	err = q.session.Get(ctx, &count, query, args...)
-	return count, err
+	q.logDebug(ctx, query, args)
+	v, err := q.session.deduplicate(ctx, dedupKey[T](query, args), func(ctx context.Context) (any, error) {
+		var count int64
+		err := q.reader(ctx).withPlanCacheMode(ctx, q.plan.CacheMode, func(s *Session) error {
+			return s.Get(ctx, &count, q.plan.annotate(query), args...)
+		})
+		return count, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int64), nil
+}
+
+// Exists reports whether any record matches the query conditions.
+// Renders SELECT EXISTS(SELECT 1 FROM ... WHERE ...), which lets the database stop
+// at the first matching row instead of counting all of them.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//
+// Returns:
+//   - bool: true if at least one record matches
+//   - error: Query execution error
+//
+// Usage example:
+//
+//	// Check for duplicate email before registration
+//	taken, err := userRepo.Query().
+//	    Where(generated.User.Email.Eq(email)).
+//	    Exists(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	if taken {
+//	    return ErrEmailTaken
+//	}
+//
+// Note:
+//   - Ignores Limit/Offset and row locking
+//   - Respects soft delete filter (unless WithTrashed() called)
+func (q *QueryBuilder[T]) Exists(ctx context.Context) (bool, error) {
+	q = q.authorize(ctx)
+	if q.err != nil {
+		return false, q.err
+	}
+	b := q.resolveBuilder().Columns("1").RemoveLimit().RemoveOffset()
+
+	subQuery, args, err := b.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("sqlc: failed to build exists sql: %w", err)
+	}
+
+	var exists bool
+	err = q.reader(ctx).Get(ctx, &exists, "SELECT EXISTS("+subQuery+")", args...)
+	return exists, err
 }
 
 // WithBuilder allow users to manipulate the underlying squirrel.SelectBuilder.
 // This provides an escape hatch for complex queries (Joins, CTEs, Window functions)
 // that are not directly supported by the simplified ORM API.
 func (q *QueryBuilder[T]) WithBuilder(fn func(b sq.SelectBuilder) sq.SelectBuilder) *QueryBuilder[T] {
+	q = q.Clone()
 	q.builder = fn(q.builder)
 	return q
 }
 
 // Build implements clause.Expression, enabling QueryBuilder to be used as a subquery.
 // This allows nesting queries in WHERE clauses like: WHERE id IN (SELECT ...)
+// It renders like the derived tables of FromSubquery: without the default order unless limited.
 func (q *QueryBuilder[T]) Build() (string, []any, error) {
-	return q.ToSQL()
+	sb, err := q.selectBuilder()
+	if err != nil {
+		return "", nil, err
+	}
+	return sb.ToSql()
 }
 
 // ToSQL returns the SQL string and arguments without executing the query.
@@ -1142,39 +2183,178 @@ func (q *QueryBuilder[T]) ToSQL() (string, []any, error) {
 	if q.err != nil {
 		return "", nil, q.err
 	}
-	b := q.resolveBuilder().Columns(q.resolveColumns()...)
+	b := q.applyLock(q.applyOrder(q.applySelect(q.resolveBuilder())))
 	return b.ToSql()
 }
 
-// resolveBuilder returns the builder with soft delete conditions applied.
+// selectBuilder implements Subquery with the fully rendered SELECT builder.
+func (q *QueryBuilder[T]) selectBuilder() (sq.SelectBuilder, error) {
+	if q.err != nil {
+		return sq.SelectBuilder{}, q.err
+	}
+	if err := q.subqueryPolicy(); err != nil {
+		return sq.SelectBuilder{}, err
+	}
+	b := q.applySelect(q.resolveBuilder())
+	if q.ordered || q.limited {
+		// The rows of a limited subquery depend on its order
+		b = q.applyOrder(b)
+	}
+	return q.applyLock(b), nil
+}
+
+// resolveBuilder returns the builder with WHERE and soft delete conditions applied.
 // Soft delete conditions are injected lazily here (not in Query() constructor)
 // so that WithTrashed()/OnlyTrashed() flags work correctly regardless of call order.
 func (q *QueryBuilder[T]) resolveBuilder() sq.SelectBuilder {
-	b := q.builder
+	b := q.applyWheres(q.builder)
 	sdCol := q.schema.SoftDeleteColumn()
+	if sdCol != "" && q.hasJoin {
+		sdCol = q.table + "." + sdCol // Joined tables may have the same column
+	}
 	if sdCol == "" || q.withTrashed {
 		// No soft delete, or explicitly including trashed records
 		if q.onlyTrashed && sdCol != "" {
 			// OnlyTrashed: return only soft-deleted records
-			b = b.Where(sq.NotEq{sdCol: nil})
+			b = b.Where(sq.NotEq{sdCol: softDeleteActive(q.schema)})
 		}
 		return b
 	}
 	// Default: exclude soft-deleted records
-	b = b.Where(sq.Eq{sdCol: nil})
+	b = b.Where(sq.Eq{sdCol: softDeleteActive(q.schema)})
+	return b
+}
+
+// applyWheres adds the condition list to the builder.
+// Pure AND lists are added as separate conditions; lists containing OR are
+// parenthesized so conditions added later cannot bind to a single branch.
+func (q *QueryBuilder[T]) applyWheres(b sq.SelectBuilder) sq.SelectBuilder {
+	hasOr := false
+	for _, w := range q.wheres[min(1, len(q.wheres)):] {
+		hasOr = hasOr || w.or
+	}
+	if !hasOr {
+		for _, w := range q.wheres {
+			b = b.Where(sq.Expr(w.sql, w.args...))
+		}
+		return b
+	}
+	sql, args := joinWheres(q.wheres)
+	return b.Where(sq.Expr("("+sql+")", args...))
+}
+
+// applyStages runs the Map/Filter stages on results, filtering in place.
+func (q *QueryBuilder[T]) applyStages(results []*T) ([]*T, error) {
+	if len(q.stages) == 0 {
+		return results, nil
+	}
+	kept := results[:0]
+	for _, item := range results {
+		keep, err := q.runStages(item)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+// runStages runs the Map/Filter stages on one result and reports whether to keep it.
+func (q *QueryBuilder[T]) runStages(item *T) (bool, error) {
+	for _, stage := range q.stages {
+		keep, err := stage(item)
+		if err != nil {
+			return false, fmt.Errorf("sqlc: result stage failed: %w", err)
+		}
+		if !keep {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// applySelect sets the SELECT list: selected columns (or the schema's default
+// columns) followed by computed expressions.
+func (q *QueryBuilder[T]) applySelect(b sq.SelectBuilder) sq.SelectBuilder {
+	if len(q.columns) == 0 {
+		b = b.Columns(q.defaultColumns()...)
+	}
+	for _, col := range q.columns {
+		b = b.Column(col)
+	}
+	for _, expr := range q.selectExprs {
+		b = b.Column(expr)
+	}
 	return b
 }
 
-func (q *QueryBuilder[T]) resolveColumns() []string {
-	cols := q.columns
-	if len(cols) == 0 {
-		cols = q.schema.SelectColumns()
-		if q.hasJoin {
-			qualified := make([]string, len(cols))
-			for i, col := range cols {
-				qualified[i] = q.table + "." + col
+// applyOrder adds the schema's default order to a row-returning SELECT without explicit ordering.
+// Subqueries and aggregates are not sorted, unless a subquery is limited (see selectBuilder).
+func (q *QueryBuilder[T]) applyOrder(b sq.SelectBuilder) sq.SelectBuilder {
+	if q.unordered {
+		return b
+	}
+	sorted := q.orderColumns
+	if d, ok := q.schema.(DefaultOrderer); ok && !q.ordered {
+		for _, order := range d.DefaultOrder() {
+			if q.hasJoin && order.Column.Table == "" {
+				order.Column.Table = q.table
+			}
+			sql, _, _ := order.Build()
+			b = b.OrderBy(sql)
+			sorted = append(sorted, order.Column.Name)
+		}
+	}
+
+	// Primary key tie-breaker: rows with equal sort values keep a stable order
+	if !q.tieBreak {
+		return b
+	}
+	pk := q.schema.PK(nil).Column
+	if pk.Name == "" || slices.Contains(sorted, pk.Name) {
+		return b
+	}
+	if q.hasJoin && pk.Table == "" {
+		pk.Table = q.table
+	}
+	return b.OrderBy(pk.ColumnName())
+}
+
+// reader returns the session running the query's reads: a read replica (WithReadReplicas)
+// unless the query locks rows, which must happen on the primary.
+func (q *QueryBuilder[T]) reader(ctx context.Context) *Session {
+	if q.lock.Strength != LockNone {
+		return q.session
+	}
+	return q.session.reader(ctx)
+}
+
+// applyLock appends the dialect's row locking clause to a row-returning SELECT.
+// Kept separate from resolveBuilder() because aggregates (Count, Sum, ...) cannot be locked.
+func (q *QueryBuilder[T]) applyLock(b sq.SelectBuilder) sq.SelectBuilder {
+	if q.lock.Strength == LockNone {
+		return b
+	}
+	if lockSQL := lockClause(q.session.dialect, q.lock); lockSQL != "" {
+		b = b.Suffix(lockSQL)
+	}
+	return b
+}
+
+func (q *QueryBuilder[T]) defaultColumns() []string {
+	names := q.schema.SelectColumns()
+	cols := names
+	if q.hasJoin {
+		cols = qualifyColumns(cols, q.table)
+	}
+	if len(q.jsonPaths) > 0 {
+		cols = slices.Clone(cols)
+		for i, name := range names {
+			if paths, ok := q.jsonPaths[name]; ok {
+				cols[i] = jsonProjection(q.session.dialect, cols[i], paths) + " AS " + name
 			}
-			cols = qualified
 		}
 	}
 	return cols
@@ -1,5 +1,5 @@
 // Package sqlc provides aggregate function support for QueryBuilder.
-// This file implements common SQL aggregate functions like SUM, AVG, MIN, MAX.
+// This file implements common SQL aggregate functions like SUM, AVG, MIN, MAX and COUNT(DISTINCT).
 //
 // Aggregate functions operate on a set of values and return a single summary value.
 // They are commonly used with GROUP BY clauses for data analysis and reporting.
@@ -18,6 +18,9 @@
 //	minPrice, err := productRepo.Query().Min(ctx, generated.Product.Price)
 //	maxPrice, err := productRepo.Query().Max(ctx, generated.Product.Price)
 //
+//	// Count unique buyers
+//	buyers, err := orderRepo.Query().CountDistinct(ctx, generated.Order.UserID)
+//
 // Design considerations:
 //   - All aggregate functions respect WHERE conditions and soft delete filters
 //   - LIMIT and OFFSET are ignored in aggregate calculations
@@ -28,6 +31,7 @@ package sqlc
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/arllen133/sqlc/clause"
 )
@@ -201,6 +205,46 @@ func (q *QueryBuilder[T]) Max(ctx context.Context, column clause.Columnar) (any,
 	return q.aggregateAny(ctx, "MAX", column.ColumnName())
 }
 
+// CountDistinct counts the number of distinct non-NULL values in a column.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - column: The column whose distinct values are counted (must implement clause.Columnar)
+//
+// Returns:
+//   - int64: Number of distinct values (0 if no rows)
+//   - error: Query execution error
+//
+// Usage example:
+//
+//	// Number of customers who placed an order this month
+//	buyers, err := orderRepo.Query().
+//	    Where(generated.Order.CreatedAt.Gte(monthStart)).
+//	    CountDistinct(ctx, generated.Order.UserID)
+//
+// Note:
+//   - NULL values are not counted
+//   - LIMIT and OFFSET are ignored
+//   - Respects soft delete filter (unless WithTrashed() called)
+func (q *QueryBuilder[T]) CountDistinct(ctx context.Context, column clause.Columnar) (int64, error) {
+	q = q.authorize(ctx)
+	if q.err != nil {
+		return 0, q.err
+	}
+
+	b := q.resolveBuilder().Columns(fmt.Sprintf("COUNT(DISTINCT %s)", column.ColumnName()))
+	b = b.RemoveLimit().RemoveOffset()
+
+	query, args, err := b.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("sqlc: failed to build aggregate sql: %w", err)
+	}
+
+	var count int64
+	err = q.reader(ctx).Get(ctx, &count, query, args...)
+	return count, err
+}
+
 // aggregateFloat executes an aggregate function that returns a float64 value.
 // This is an internal helper method used by Sum() and Avg().
 //
@@ -216,7 +260,7 @@ func (q *QueryBuilder[T]) Max(ctx context.Context, column clause.Columnar) (any,
 // Type handling:
 //   - float64: returned as-is
 //   - int64: converted to float64
-//   - []byte: returns error (unexpected type)
+//   - []byte, string: parsed as decimal text (e.g. MySQL DECIMAL results)
 //   - other types: returns error
 //
 // Note:
@@ -237,9 +281,10 @@ func (q *QueryBuilder[T]) aggregateFloat(ctx context.Context, funcName, column s
 	case int64:
 		return float64(v), nil
 	case []byte:
-		// Handle SQLite weirdness where numbers might be returned as bytes?
-		// Or if database returns string.
-		return 0, fmt.Errorf("unexpected type %T for aggregate", val)
+		// Drivers return DECIMAL/NUMERIC aggregates as text
+		return parseAggregateFloat(string(v))
+	case string:
+		return parseAggregateFloat(v)
 	default:
 		return 0, fmt.Errorf("unexpected type %T for aggregate", val)
 	}
@@ -269,6 +314,7 @@ func (q *QueryBuilder[T]) aggregateFloat(ctx context.Context, funcName, column s
 //   - Type depends on database driver and column type
 //   - Respects all query conditions (WHERE, soft delete, etc.)
 func (q *QueryBuilder[T]) aggregateAny(ctx context.Context, funcName, column string) (any, error) {
+	q = q.authorize(ctx)
 	if q.err != nil {
 		return nil, q.err
 	}
@@ -287,8 +333,17 @@ func (q *QueryBuilder[T]) aggregateAny(ctx context.Context, funcName, column str
 	}
 
 	var result any
-	if err := q.session.Get(ctx, &result, query, args...); err != nil {
+	if err := q.reader(ctx).Get(ctx, &result, query, args...); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
+
+// parseAggregateFloat parses a textual aggregate result into float64.
+func parseAggregateFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("sqlc: failed to parse aggregate result %q: %w", s, err)
+	}
+	return f, nil
+}
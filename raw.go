@@ -0,0 +1,143 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements the raw SQL API for statements the QueryBuilder cannot express.
+//
+// Raw queries are still first-class session operations:
+//   - Logging, tracing and metrics are recorded like any other statement
+//   - SQL guard rules are applied before execution
+//   - Placeholders are written as ? and rebound to the session dialect
+//     (e.g. $1, $2 on PostgreSQL), so the same SQL runs on every database
+//   - RawNamed / RawExecNamed accept :name parameters from a map or struct
+//
+// Usage example:
+//
+//	type DeptStats struct {
+//	    DepartmentID int64   `db:"department_id"`
+//	    Members      int64   `db:"members"`
+//	    AvgLevel     float64 `db:"avg_level"`
+//	}
+//
+//	stats, err := sqlc.Raw[DeptStats](ctx, session,
+//	    "SELECT department_id, COUNT(*) AS members, AVG(level) AS avg_level FROM members WHERE level > ? GROUP BY department_id",
+//	    1,
+//	)
+//
+//	res, err := sqlc.RawExec(ctx, session, "UPDATE members SET level = level + 1 WHERE department_id = ?", 7)
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Raw executes a raw SQL query and scans all rows into a slice of T.
+// Columns are mapped by `db` tags like Find(); T may also be a scalar type
+// (e.g. int64, string) for single-column queries.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - session: Database session (regular or transaction)
+//   - query: SQL query using ? placeholders
+//   - args: Query parameters
+//
+// Returns:
+//   - []*T: Scanned rows (empty slice if no rows match)
+//   - error: Query or scan error
+//
+// Example:
+//
+//	ids, err := sqlc.Raw[int64](ctx, session, "SELECT id FROM users WHERE email LIKE ?", "%@example.com")
+//
+// Note:
+//   - Model T does not need to be registered; any struct with db tags works
+//   - Hooks, soft delete filters and repository scopes are NOT applied
+//   - On PostgreSQL, write a literal ? (e.g. JSONB operators) as ??
+func Raw[T any](ctx context.Context, session *Session, query string, args ...any) ([]*T, error) {
+	query, err := session.rebind(query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*T, 0)
+	if err := session.Select(ctx, &results, query, args...); err != nil {
+		return nil, fmt.Errorf("sqlc: raw query failed: %w", err)
+	}
+	return results, nil
+}
+
+// RawExec executes a raw SQL statement that returns no rows (INSERT/UPDATE/DELETE/DDL).
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - session: Database session (regular or transaction)
+//   - query: SQL statement using ? placeholders
+//   - args: Statement parameters
+//
+// Returns:
+//   - sql.Result: Result containing affected rows and last insert ID
+//   - error: Execution error
+//
+// Example:
+//
+//	res, err := sqlc.RawExec(ctx, session,
+//	    "UPDATE orders SET status = ? WHERE created_at < ?",
+//	    "expired", cutoff,
+//	)
+//	affected, _ := res.RowsAffected()
+func RawExec(ctx context.Context, session *Session, query string, args ...any) (sql.Result, error) {
+	query, err := session.rebind(query)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := session.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: raw exec failed: %w", err)
+	}
+	return res, nil
+}
+
+// RawNamed executes a raw SQL query with :name parameters and scans all rows into a slice of T.
+// arg is a map[string]any or a struct with db tags; literal colons are doubled (x::::date).
+//
+// Example:
+//
+//	members, err := sqlc.RawNamed[models.Member](ctx, session,
+//	    "SELECT * FROM members WHERE department_id = :dept AND (level >= :min OR name = :name)",
+//	    map[string]any{"dept": 7, "min": 3, "name": "alice"},
+//	)
+func RawNamed[T any](ctx context.Context, session *Session, query string, arg any) ([]*T, error) {
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to bind named parameters: %w", err)
+	}
+	return Raw[T](ctx, session, query, args...)
+}
+
+// RawExecNamed executes a raw SQL statement with :name parameters.
+// arg is a map[string]any or a struct with db tags; literal colons are doubled (x::::date).
+//
+// Example:
+//
+//	_, err := sqlc.RawExecNamed(ctx, session,
+//	    "UPDATE members SET level = :level WHERE id = :id",
+//	    member, // struct fields are matched by db tag
+//	)
+func RawExecNamed(ctx context.Context, session *Session, query string, arg any) (sql.Result, error) {
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to bind named parameters: %w", err)
+	}
+	return RawExec(ctx, session, query, args...)
+}
+
+// rebind converts ? placeholders to the dialect's placeholder format.
+func (s *Session) rebind(query string) (string, error) {
+	rebound, err := s.dialect.PlaceholderFormat().ReplacePlaceholders(query)
+	if err != nil {
+		return "", fmt.Errorf("sqlc: failed to rebind placeholders: %w", err)
+	}
+	return rebound, nil
+}
@@ -0,0 +1,159 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestRawQuery(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	for i := 0; i < 4; i++ {
+		m := &Member{
+			Name:         fmt.Sprintf("Raw%d", i),
+			Email:        fmt.Sprintf("raw%d@test.com", i),
+			Level:        i,
+			DepartmentID: 1 + i%2,
+			CreatedAt:    time.Now(),
+		}
+		if err := memberRepo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	t.Run("Struct", func(t *testing.T) {
+		type deptStats struct {
+			DepartmentID int64 `db:"department_id"`
+			Members      int64 `db:"members"`
+		}
+		stats, err := sqlc.Raw[deptStats](ctx, session,
+			"SELECT department_id, COUNT(*) AS members FROM members WHERE level >= ? GROUP BY department_id ORDER BY department_id",
+			1,
+		)
+		if err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		if len(stats) != 2 || stats[0].Members != 1 || stats[1].Members != 2 {
+			t.Errorf("unexpected stats: %+v, %+v", stats[0], stats[1])
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		members, err := sqlc.Raw[Member](ctx, session, "SELECT * FROM members WHERE name = ?", "Raw2")
+		if err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		if len(members) != 1 || members[0].Level != 2 {
+			t.Errorf("unexpected members: %+v", members)
+		}
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		names, err := sqlc.Raw[string](ctx, session, "SELECT name FROM members ORDER BY id")
+		if err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		if len(names) != 4 || *names[0] != "Raw0" {
+			t.Errorf("unexpected names: %v", names)
+		}
+	})
+
+	t.Run("NoRows", func(t *testing.T) {
+		members, err := sqlc.Raw[Member](ctx, session, "SELECT * FROM members WHERE level > ?", 100)
+		if err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		if members == nil || len(members) != 0 {
+			t.Errorf("expected empty slice, got %v", members)
+		}
+	})
+
+	t.Run("Named", func(t *testing.T) {
+		members, err := sqlc.RawNamed[Member](ctx, session,
+			"SELECT * FROM members WHERE department_id = :dept AND level >= :min ORDER BY id",
+			map[string]any{"dept": 2, "min": 1},
+		)
+		if err != nil {
+			t.Fatalf("RawNamed failed: %v", err)
+		}
+		if len(members) != 2 || members[0].Name != "Raw1" || members[1].Name != "Raw3" {
+			t.Errorf("unexpected members: %+v", members)
+		}
+
+		if _, err := sqlc.RawNamed[Member](ctx, session, "SELECT * FROM members WHERE id = :id", map[string]any{}); err == nil {
+			t.Error("expected error for missing named parameter")
+		}
+	})
+
+	t.Run("NamedExecStruct", func(t *testing.T) {
+		m := Member{ID: 1, Name: "Renamed"}
+		res, err := sqlc.RawExecNamed(ctx, session, "UPDATE members SET name = :name WHERE id = :id", m)
+		if err != nil {
+			t.Fatalf("RawExecNamed failed: %v", err)
+		}
+		if n, _ := res.RowsAffected(); n != 1 {
+			t.Errorf("expected 1 row affected, got %d", n)
+		}
+	})
+
+	t.Run("NamedExprInQuery", func(t *testing.T) {
+		count, err := memberRepo.Query().
+			Where(clause.Named{SQL: "level >= :min AND level <= :max", Arg: map[string]any{"min": 1, "max": 2}}).
+			Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 members, got %d", count)
+		}
+	})
+
+	t.Run("Exec", func(t *testing.T) {
+		res, err := sqlc.RawExec(ctx, session, "UPDATE members SET level = level + 10 WHERE department_id = ?", 2)
+		if err != nil {
+			t.Fatalf("RawExec failed: %v", err)
+		}
+		if n, _ := res.RowsAffected(); n != 2 {
+			t.Errorf("expected 2 rows affected, got %d", n)
+		}
+	})
+}
+
+func TestRawQueryRebindAndGuards(t *testing.T) {
+	db, _ := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	var seen []string
+	capture := func(ctx context.Context, query string) error {
+		seen = append(seen, query)
+		return fmt.Errorf("%w: captured", sqlc.ErrStatementRejected)
+	}
+	session := sqlc.NewSession(db, &sqlc.PostgreSQLDialect{}, sqlc.WithSQLGuard(capture))
+
+	_, err := sqlc.Raw[Member](ctx, session, "SELECT * FROM members WHERE level > ? AND name <> ?", 1, "x")
+	if !errors.Is(err, sqlc.ErrStatementRejected) {
+		t.Errorf("expected guard rejection, got %v", err)
+	}
+	_, err = sqlc.RawExec(ctx, session, "DELETE FROM members WHERE id = ?", 1)
+	if !errors.Is(err, sqlc.ErrStatementRejected) {
+		t.Errorf("expected guard rejection, got %v", err)
+	}
+
+	want := []string{
+		"SELECT * FROM members WHERE level > $1 AND name <> $2",
+		"DELETE FROM members WHERE id = $1",
+	}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("expected rebound queries %q, got %q", want, seen)
+	}
+}
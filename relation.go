@@ -2,15 +2,19 @@
 // This file implements model relationship definitions and eager loading functionality.
 //
 // Relationships are one of the core features of ORM, allowing automatic loading of
-// associated models when querying the main model. sqlc supports two main types:
+// associated models when querying the main model. sqlc supports these types:
 //   - HasOne: One-to-one relationship (e.g., user has one profile)
 //   - HasMany: One-to-many relationship (e.g., user has many posts)
+//   - BelongsTo: Inverse relationship, the model holds the foreign key (e.g., post belongs to user)
+//   - ManyToMany: Relationship through a join table (e.g., users and roles via user_roles)
+//   - HasManyThrough: ManyToMany through an intermediate model (e.g., users and teams via memberships)
 //
 // Relationship implementation:
 //  1. Define Relation struct describing foreign key and local key mappings
 //  2. Use Preload() function to create preload executor
 //  3. Automatically execute associated queries after main query
-//  4. Populate associated data into main model
+//  4. Populate associated data into main model, or return it as a map
+//     keyed by parent (Preload(...).IntoMap() with FindWithMap)
 //
 // Usage example:
 //
@@ -33,10 +37,15 @@
 //   - Uses native typed map keys instead of fmt.Sprint for zero-overhead grouping
 //   - Deduplicates IN values to minimize query size
 //   - Supports child query customization via options
+//   - HasOne/BelongsTo can be loaded in the main query instead (PreloadJoin)
+//   - Children counts are loaded with one grouped COUNT query (WithCount)
+//   - Trees of self relations are loaded with one recursive query (PreloadTree)
 package sqlc
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/arllen133/sqlc/clause"
 )
@@ -52,6 +61,14 @@ const (
 	// RelationHasMany indicates a one-to-many relationship.
 	// Parent model has many child models.
 	RelationHasMany
+
+	// RelationBelongsTo indicates an inverse relationship.
+	// The model holds a foreign key referencing its owner model.
+	RelationBelongsTo
+
+	// RelationManyToMany indicates a many-to-many relationship.
+	// Parent and child models are linked by rows of a join table.
+	RelationManyToMany
 )
 
 // Relation defines a relationship between parent model P and child model C,
@@ -62,13 +79,16 @@ const (
 //   - C: Child model type (e.g., Post)
 //   - K: Key type for matching (must be comparable, e.g., int64, string)
 type Relation[P, C any, K comparable] struct {
-	// Type is the relationship type (HasOne or HasMany).
+	// Type is the relationship type (HasOne, HasMany, BelongsTo or ManyToMany).
 	Type RelationType
 
 	// ForeignKey is the foreign key column in child table.
+	// For BelongsTo, it is the owner's key column (e.g. users.id) matched by LocalKey values.
+	// For ManyToMany, it is the child's key column (e.g. roles.id) matched by JoinReferences values.
 	ForeignKey clause.Column
 
 	// LocalKey is the local key column in parent table.
+	// For BelongsTo, it is the foreign key column of the model (e.g. posts.user_id).
 	LocalKey clause.Column
 
 	// Setter sets loaded child models into parent model.
@@ -79,6 +99,15 @@ type Relation[P, C any, K comparable] struct {
 
 	// GetForeignKeyValue extracts typed foreign key value from child model.
 	GetForeignKeyValue func(child *C) K
+
+	// JoinTable is the join table of a ManyToMany relationship (e.g. user_roles).
+	JoinTable string
+
+	// JoinForeignKey is the join table column referencing the parent (e.g. user_id).
+	JoinForeignKey clause.Column
+
+	// JoinReferences is the join table column referencing the child (e.g. role_id).
+	JoinReferences clause.Column
 }
 
 // HasOne creates a HasOne relationship definition.
@@ -156,6 +185,67 @@ func HasMany[P, C any, K comparable](
 	}
 }
 
+// BelongsTo creates a BelongsTo relationship definition: model C holds a foreign key
+// referencing its owner P, and preloading C loads the owners of the loaded models.
+//
+// Type parameters:
+//   - C: Model holding the foreign key (e.g., Post)
+//   - P: Owner model (e.g., User)
+//   - K: Key type (e.g., int64, string)
+//
+// Parameters:
+//   - foreignKey: Foreign key column of C (e.g. user_id)
+//   - ownerKey: Referenced key column of P (usually its primary key)
+//   - setter: Sets the loaded owner on the model
+//   - getForeignKey: Extracts the foreign key value from the model
+//   - getOwnerKey: Extracts the key value from the owner
+//
+// Example:
+//
+//	postBelongsToUser := sqlc.BelongsTo[Post, User, int64](
+//	    clause.Column{Name: "user_id"},
+//	    clause.Column{Name: "id"},
+//	    func(p *Post, u *User) { p.Author = u },
+//	    func(p *Post) int64 { return p.UserID },
+//	    func(u *User) int64 { return u.ID },
+//	)
+//
+//	posts, err := postRepo.Query().WithPreload(sqlc.Preload(postBelongsToUser)).Find(ctx)
+//
+// Note:
+//   - Models whose foreign key is the zero value (e.g. 0 for a missing optional owner) are
+//     skipped: no owner is queried for them and their relation field is left unchanged
+//   - Owners shared by several models are loaded once and set on each of them
+func BelongsTo[C, P any, K comparable](
+	foreignKey clause.Column,
+	ownerKey clause.Column,
+	setter func(*C, *P),
+	getForeignKey func(*C) K,
+	getOwnerKey func(*P) K,
+) Relation[C, P, K] {
+	return Relation[C, P, K]{
+		Type:       RelationBelongsTo,
+		ForeignKey: ownerKey,
+		LocalKey:   foreignKey,
+		Setter: func(c *C, owners []*P) {
+			if len(owners) > 0 {
+				setter(c, owners[0])
+			}
+		},
+		GetLocalKeyValue:   getForeignKey,
+		GetForeignKeyValue: getOwnerKey,
+	}
+}
+
+// RelationPreload is a preload of one relationship, created by Preload().
+// Pass it to QueryBuilder.WithPreload() to populate parent models, or call
+// IntoMap() to receive the children grouped by parent key instead.
+type RelationPreload[P, C any, K comparable] struct {
+	rel    Relation[P, C, K]
+	opts   []func(*QueryBuilder[C]) *QueryBuilder[C]
+	nested []preloadExecutor[C]
+}
+
 // Preload creates a preload executor for given relationship.
 // Supports optional child query customization via variadic options.
 //
@@ -178,65 +268,212 @@ func HasMany[P, C any, K comparable](
 func Preload[P, C any, K comparable](
 	rel Relation[P, C, K],
 	opts ...func(*QueryBuilder[C]) *QueryBuilder[C],
-) preloadExecutor[P] {
-	return func(ctx context.Context, session *Session, parents []*P) error {
-		if len(parents) == 0 {
-			return nil
-		}
+) RelationPreload[P, C, K] {
+	return RelationPreload[P, C, K]{rel: rel, opts: opts}
+}
 
-		// Step 1: Collect and deduplicate local key values
-		seen := make(map[K]struct{}, len(parents))
-		foreignKeys := make([]any, 0, len(parents))
-		for i := range parents {
-			k := rel.GetLocalKeyValue(parents[i])
-			if _, ok := seen[k]; !ok {
-				seen[k] = struct{}{}
-				foreignKeys = append(foreignKeys, k)
-			}
-		}
+// PreloadFunc adapts a hand-written preload function to WithPreload and Then.
+// The function receives the results of the main query and loads their associated
+// data itself, with the signature preload executors had before RelationPreload.
+//
+// Example:
+//
+//	loadStats := sqlc.PreloadFunc[models.User](func(ctx context.Context, session *sqlc.Session, users []*models.User) error {
+//	    for _, u := range users {
+//	        u.Stats = statsCache.Get(u.ID)
+//	    }
+//	    return nil
+//	})
+//	users, err := userRepo.Query().WithPreload(loadStats).Find(ctx)
+type PreloadFunc[T any] func(ctx context.Context, session *Session, results []*T) error
 
-		// Fast return: all keys deduplicated to empty (e.g., all zero values filtered)
-		if len(foreignKeys) == 0 {
-			return nil
-		}
+// load implements preloadExecutor by calling f.
+func (f PreloadFunc[T]) load(ctx context.Context, session *Session, results []*T) error {
+	return f(ctx, session, results)
+}
 
-		// Step 2: Build query with optimal expression
-		query := Query[C](session)
-		if len(foreignKeys) == 1 {
-			query = query.Where(clause.Eq{
-				Column: rel.ForeignKey,
-				Value:  foreignKeys[0],
-			})
-		} else {
-			query = query.Where(clause.IN{
-				Column: rel.ForeignKey,
-				Values: foreignKeys,
-			})
-		}
+// Then preloads relations of the loaded children, one extra query per relation,
+// e.g. the comments of the posts of the users. Accepts relations and preloads of C;
+// use a preload to customize the nested query or to chain a further level.
+//
+// Parameters:
+//   - nested: Relations (generated.Post_Comments) or preloads (sqlc.Preload(...)) of C
+//
+// Returns:
+//   - RelationPreload[P, C, K]: New preload, the receiver is not modified
+//
+// Example:
+//
+//	// users -> posts -> comments: 3 queries
+//	users, err := userRepo.Query().
+//	    WithPreload(sqlc.Preload(generated.User_Posts).Then(generated.Post_Comments)).
+//	    Find(ctx)
+//
+//	// users -> posts -> comments -> author: 4 queries
+//	sqlc.Preload(generated.User_Posts).Then(
+//	    sqlc.Preload(generated.Post_Comments).Then(generated.Comment_Author),
+//	)
+//
+// Note:
+//   - Several relations passed to Then (or successive Then calls) are loaded side by side
+//     on the children, not one below the other
+func (p RelationPreload[P, C, K]) Then(nested ...preloadExecutor[C]) RelationPreload[P, C, K] {
+	p.nested = append(slices.Clone(p.nested), nested...)
+	return p
+}
 
-		// Apply user-provided query customizations
-		for _, opt := range opts {
-			query = opt(query)
-		}
+// IntoMap switches the preload to map mode: children are returned to the caller
+// grouped by parent key instead of being set on the parent models.
+// Use with FindWithMap(); useful for read models without relation fields.
+//
+// Example:
+//
+//	res, err := sqlc.FindWithMap(ctx, userRepo.Query(), sqlc.Preload(userHasManyPosts).IntoMap())
+//	for _, u := range res.Items {
+//	    posts := res.Related[u.ID]
+//	}
+func (p RelationPreload[P, C, K]) IntoMap() PreloadMap[P, C, K] {
+	return PreloadMap[P, C, K]{preload: p}
+}
 
-		children, err := query.Find(ctx)
-		if err != nil {
-			return err
-		}
+// load implements preloadExecutor, so a relation can be passed to WithPreload and Then
+// without wrapping it in Preload.
+func (r Relation[P, C, K]) load(ctx context.Context, session *Session, parents []*P) error {
+	return Preload(r).load(ctx, session, parents)
+}
 
-		// Step 3: Group child models by foreign key using typed map (no fmt.Sprint)
-		childMap := make(map[K][]*C)
-		for _, child := range children {
-			fk := rel.GetForeignKeyValue(child)
-			childMap[fk] = append(childMap[fk], child)
-		}
+// customize applies the query options and nested preloads to a child query.
+func (p RelationPreload[P, C, K]) customize(query *QueryBuilder[C]) *QueryBuilder[C] {
+	for _, opt := range p.opts {
+		query = opt(query)
+	}
+	for _, nested := range p.nested {
+		query = query.WithPreload(nested)
+	}
+	return query
+}
+
+// load implements preloadExecutor by setting the loaded children on each parent.
+func (p RelationPreload[P, C, K]) load(ctx context.Context, session *Session, parents []*P) error {
+	childMap, err := p.loadChildren(ctx, session, parents)
+	if err != nil {
+		return err
+	}
+
+	// Step 4: Set child models into corresponding parent models
+	for _, parent := range parents {
+		k := p.rel.GetLocalKeyValue(parent)
+		p.rel.Setter(parent, childMap[k])
+	}
+	return nil
+}
+
+// loadChildren queries the children of parents and groups them by foreign key.
+func (p RelationPreload[P, C, K]) loadChildren(ctx context.Context, session *Session, parents []*P) (map[K][]*C, error) {
+	childMap := make(map[K][]*C)
+	if len(parents) == 0 {
+		return childMap, nil
+	}
 
-		// Step 4: Set child models into corresponding parent models
-		for _, p := range parents {
-			k := rel.GetLocalKeyValue(p)
-			rel.Setter(p, childMap[k])
+	if p.rel.Type == RelationManyToMany {
+		return p.loadManyToMany(ctx, session, parents)
+	}
+
+	// Step 1: Collect and deduplicate local key values
+	// BelongsTo skips zero foreign keys (models without an owner)
+	var zero K
+	seen := make(map[K]struct{}, len(parents))
+	foreignKeys := make([]any, 0, len(parents))
+	for i := range parents {
+		k := p.rel.GetLocalKeyValue(parents[i])
+		if p.rel.Type == RelationBelongsTo && k == zero {
+			continue
+		}
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			foreignKeys = append(foreignKeys, k)
 		}
+	}
+	if len(foreignKeys) == 0 {
+		return childMap, nil
+	}
+
+	// Step 2: Build query with optimal expression
+	query := Query[C](session)
+	if len(foreignKeys) == 1 {
+		query = query.Where(clause.Eq{
+			Column: p.rel.ForeignKey,
+			Value:  foreignKeys[0],
+		})
+	} else {
+		query = query.Where(clause.IN{
+			Column: p.rel.ForeignKey,
+			Values: foreignKeys,
+		})
+	}
+
+	// Apply user-provided query customizations and nested preloads
+	query = p.customize(query)
+
+	children, err := query.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: Group child models by foreign key using typed map (no fmt.Sprint)
+	for _, child := range children {
+		fk := p.rel.GetForeignKeyValue(child)
+		childMap[fk] = append(childMap[fk], child)
+	}
+	return childMap, nil
+}
+
+// PreloadMap is a relationship preload in map mode, created by RelationPreload.IntoMap().
+type PreloadMap[P, C any, K comparable] struct {
+	preload RelationPreload[P, C, K]
+}
+
+// MapResult is the result of FindWithMap: the parent models and their
+// children keyed by the parent's local key value.
+type MapResult[P, C any, K comparable] struct {
+	// Items are the parent models returned by the query.
+	Items []*P
+
+	// Related maps each parent key to its children.
+	// Parents without children have no entry.
+	Related map[K][]*C
+}
+
+// FindWithMap executes the query and loads the relationship of m into a map
+// instead of setting it on the parent models.
+//
+// Parameters:
+//   - ctx: Context for cancellation and tracing
+//   - q: Parent query; its own WithPreload() preloads are still applied
+//   - m: Map mode preload created by Preload(...).IntoMap()
+//
+// Returns:
+//   - *MapResult[P, C, K]: Parent models and children grouped by parent key
+//   - error: Query or preload error
+//
+// Example:
+//
+//	res, err := sqlc.FindWithMap(ctx,
+//	    userRepo.Query().Where(generated.User.Status.Eq("active")),
+//	    sqlc.Preload(generated.User_Posts).IntoMap(),
+//	)
+//	for _, u := range res.Items {
+//	    fmt.Println(u.Name, len(res.Related[u.ID]))
+//	}
+func FindWithMap[P, C any, K comparable](ctx context.Context, q *QueryBuilder[P], m PreloadMap[P, C, K]) (*MapResult[P, C, K], error) {
+	items, err := q.Find(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
+	related, err := m.preload.loadChildren(ctx, q.session, items)
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: preload failed: %w", err)
 	}
+	return &MapResult[P, C, K]{Items: items, Related: related}, nil
 }
@@ -0,0 +1,139 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements relation count preloading.
+//
+// WithCount loads the number of children of each parent with one grouped COUNT query,
+// instead of loading the children themselves. Listing pages can show "12 posts" per user
+// without transferring every post.
+//
+// Usage example:
+//
+//	users, err := userRepo.Query().
+//	    WithPreload(sqlc.WithCount(generated.User_Posts, func(u *User, n int64) { u.PostCount = n })).
+//	    Find(ctx)
+//
+//	// SELECT posts.user_id AS parent_key, COUNT(*) AS n FROM posts
+//	// WHERE posts.user_id IN (?, ?, ...) GROUP BY posts.user_id
+package sqlc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// RelationCount is a preload of the children count of a relationship, created by WithCount().
+// Pass it to QueryBuilder.WithPreload().
+type RelationCount[P, C any, K comparable] struct {
+	rel    Relation[P, C, K]
+	setter func(*P, int64)
+	opts   []func(*QueryBuilder[C]) *QueryBuilder[C]
+}
+
+// countRow is one row of the grouped count query
+type countRow[K comparable] struct {
+	Parent K     `db:"parent_key"`
+	N      int64 `db:"n"`
+}
+
+// WithCount creates a preload that counts the children of each parent of a HasOne,
+// HasMany or ManyToMany relation, and passes the count to setter.
+//
+// Parameters:
+//   - rel: Relationship to count (e.g. generated.User_Posts)
+//   - setter: Receives each parent and its children count; parents without children get 0
+//   - opts: Optional child query customizations, e.g. conditions on the counted children
+//
+// Returns:
+//   - RelationCount[P, C, K]: Preload to pass to QueryBuilder.WithPreload()
+//
+// Example:
+//
+//	// Count published posts only
+//	users, err := userRepo.Query().
+//	    WithPreload(sqlc.WithCount(generated.User_Posts,
+//	        func(u *User, n int64) { u.PublishedPosts = n },
+//	        func(q *sqlc.QueryBuilder[Post]) *sqlc.QueryBuilder[Post] {
+//	            return q.Where(generated.Post.Status.Eq("published"))
+//	        },
+//	    )).
+//	    Find(ctx)
+//
+// Note:
+//   - Runs one query for all parents; soft-deleted children are not counted
+//   - ManyToMany counts join the child table with the join table, so conditions in opts
+//     should qualify columns the two tables share
+//   - LIMIT and OFFSET set in opts do not apply to the count; leave out ORDER BY, which
+//     some databases reject in grouped queries
+//   - BelongsTo relations are rejected: a model has at most one owner
+func WithCount[P, C any, K comparable](
+	rel Relation[P, C, K],
+	setter func(*P, int64),
+	opts ...func(*QueryBuilder[C]) *QueryBuilder[C],
+) RelationCount[P, C, K] {
+	return RelationCount[P, C, K]{rel: rel, setter: setter, opts: opts}
+}
+
+// load implements preloadExecutor by setting the children count of each parent.
+func (c RelationCount[P, C, K]) load(ctx context.Context, session *Session, parents []*P) error {
+	if c.rel.Type == RelationBelongsTo {
+		return fmt.Errorf("sqlc: WithCount requires a HasOne, HasMany or ManyToMany relation")
+	}
+	if len(parents) == 0 {
+		return nil
+	}
+
+	// Step 1: Collect and deduplicate parent key values
+	seen := make(map[K]struct{}, len(parents))
+	parentKeys := make([]any, 0, len(parents))
+	for i := range parents {
+		k := c.rel.GetLocalKeyValue(parents[i])
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			parentKeys = append(parentKeys, k)
+		}
+	}
+
+	// Step 2: Group the children by the column referencing the parent
+	query := Query[C](session)
+	key := clause.Column{Table: query.table, Name: c.rel.ForeignKey.Name}
+	if c.rel.Type == RelationManyToMany {
+		query.hasJoin = true
+		query.builder = query.builder.Join(fmt.Sprintf("%s ON %s.%s = %s.%s",
+			c.rel.JoinTable, c.rel.JoinTable, c.rel.JoinReferences.Name, query.table, c.rel.ForeignKey.Name))
+		key = clause.Column{Table: c.rel.JoinTable, Name: c.rel.JoinForeignKey.Name}
+	}
+	query = query.Where(clause.IN{Column: key, Values: parentKeys})
+	for _, opt := range c.opts {
+		query = opt(query)
+	}
+	if query.err != nil {
+		return query.err
+	}
+
+	countSQL, args, err := query.resolveBuilder().
+		Columns(key.ColumnName()+" AS parent_key", "COUNT(*) AS n").
+		GroupBy(key.ColumnName()).
+		RemoveLimit().
+		RemoveOffset().
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("sqlc: failed to build count sql: %w", err)
+	}
+	query.logDebug(ctx, countSQL, args)
+
+	var rows []countRow[K]
+	if err := session.reader(ctx).Select(ctx, &rows, countSQL, args...); err != nil {
+		return fmt.Errorf("sqlc: failed to count %s: %w", query.table, err)
+	}
+
+	// Step 3: Set the counts, 0 for parents without children
+	counts := make(map[K]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Parent] = row.N
+	}
+	for _, parent := range parents {
+		c.setter(parent, counts[c.rel.GetLocalKeyValue(parent)])
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package sqlc_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestWithCount(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("HasMany", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		deptRepo := sqlc.NewRepository[Department](session)
+		memberRepo := sqlc.NewRepository[Member](session)
+
+		depts := map[string]*Department{}
+		for _, name := range []string{"Engineering", "Sales", "Empty"} {
+			d := &Department{Name: name}
+			if err := deptRepo.Create(ctx, d); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			depts[name] = d
+		}
+		for i, dept := range []string{"Engineering", "Engineering", "Engineering", "Sales"} {
+			m := &Member{Name: fmt.Sprintf("m%d", i), Email: fmt.Sprintf("m%d@test.com", i), Level: i, DepartmentID: int(depts[dept].ID), CreatedAt: time.Now()}
+			if err := memberRepo.Create(ctx, m); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+		}
+
+		tests := []struct {
+			name string
+			opts []func(*sqlc.QueryBuilder[Member]) *sqlc.QueryBuilder[Member]
+			want map[string]int64
+		}{
+			{"All", nil, map[string]int64{"Engineering": 3, "Sales": 1, "Empty": 0}},
+			{"Filtered", []func(*sqlc.QueryBuilder[Member]) *sqlc.QueryBuilder[Member]{
+				func(q *sqlc.QueryBuilder[Member]) *sqlc.QueryBuilder[Member] {
+					return q.Where(clause.Gte{Column: clause.Column{Name: "level"}, Value: 2}).Limit(1)
+				},
+			}, map[string]int64{"Engineering": 1, "Sales": 1, "Empty": 0}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := map[string]int64{}
+				_, err := deptRepo.Query().
+					WithPreload(sqlc.WithCount(DepartmentHasMembers, func(d *Department, n int64) { got[d.Name] = n }, tt.opts...)).
+					Find(ctx)
+				if err != nil {
+					t.Fatalf("Find failed: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("counts = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("ManyToMany", func(t *testing.T) {
+		sqlc.RegisterSchema(StudentSchema{})
+		sqlc.RegisterSchema(CourseSchema{})
+		db, session := setupTestDB(t)
+		defer db.Close()
+		for _, stmt := range []string{
+			`CREATE TABLE students (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)`,
+			`CREATE TABLE courses (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT)`,
+			`CREATE TABLE enrollments (student_id INTEGER, course_id INTEGER, PRIMARY KEY (student_id, course_id))`,
+			`INSERT INTO students (name) VALUES ('alice'), ('bob'), ('carol')`,
+			`INSERT INTO courses (title) VALUES ('Databases'), ('Algebra')`,
+			`INSERT INTO enrollments VALUES (1, 1), (1, 2), (2, 2)`,
+		} {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatalf("failed to set up tables: %v", err)
+			}
+		}
+
+		got := map[string]int64{}
+		_, err := sqlc.NewRepository[Student](session).Query().
+			WithPreload(sqlc.WithCount(StudentCourses, func(s *Student, n int64) { got[s.Name] = n })).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if want := map[string]int64{"alice": 2, "bob": 1, "carol": 0}; !reflect.DeepEqual(got, want) {
+			t.Errorf("counts = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("BelongsToRejected", func(t *testing.T) {
+		db, session := setupIntegrationDB(t)
+		defer db.Close()
+		if err := sqlc.NewRepository[Member](session).Create(ctx, &Member{Name: "a", Email: "a@test.com", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		_, err := sqlc.NewRepository[Member](session).Query().
+			WithPreload(sqlc.WithCount(MemberDepartment, func(*Member, int64) {})).
+			Find(ctx)
+		if err == nil {
+			t.Error("expected error counting a BelongsTo relation")
+		}
+	})
+}
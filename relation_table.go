@@ -0,0 +1,149 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements the relation table describing the model graph of a generated package.
+//
+// The generator emits a generated.Relations table listing every relation of the package's
+// models by name, kind and key columns. Runtime features (cascade delete, graph insert) and
+// third-party tooling can navigate the model graph from it, including in the reverse
+// direction, without reflecting over model structs.
+//
+// Usage example:
+//
+//	// Generated:
+//	// var Relations = sqlc.RelationTable{
+//	//     {Model: "Post", Table: "posts", Name: "Author", Kind: sqlc.KindBelongsTo, Target: "User", TargetTable: "users", ForeignKey: "user_id", References: "id"},
+//	//     {Model: "User", Table: "users", Name: "Posts", Kind: sqlc.KindHasMany, Target: "Post", TargetTable: "posts", ForeignKey: "user_id", References: "id"},
+//	// }
+//
+//	// Tables whose rows reference users, e.g. to delete them first
+//	for _, rel := range generated.Relations.Dependents("User") {
+//	    fmt.Println(rel.FKTable(), rel.ForeignKey) // posts user_id
+//	}
+package sqlc
+
+// RelationKind is the kind of a relation in a RelationTable.
+type RelationKind string
+
+const (
+	// KindHasOne: the target holds a foreign key referencing the model (user has one profile)
+	KindHasOne RelationKind = "hasOne"
+	// KindHasMany: many targets hold a foreign key referencing the model (user has many posts)
+	KindHasMany RelationKind = "hasMany"
+	// KindBelongsTo: the model holds a foreign key referencing the target (post belongs to user)
+	KindBelongsTo RelationKind = "belongsTo"
+	// KindManyToMany: rows of a join table link the model and the target (users and roles via user_roles)
+	KindManyToMany RelationKind = "many2many"
+)
+
+// RelationDesc describes one relation of a model.
+type RelationDesc struct {
+	Model       string       // Declaring model type name (e.g. "User")
+	Table       string       // Declaring model table
+	Name        string       // Relation field name (e.g. "Posts")
+	Kind        RelationKind // hasOne, hasMany, belongsTo or many2many
+	Target      string       // Target model type name (e.g. "Post")
+	TargetTable string       // Target model table
+	ForeignKey  string       // Foreign key column, in FKTable()
+	References  string       // Column referenced by the foreign key, in the other table
+
+	JoinTable      string // Join table (many2many only)
+	JoinReferences string // Join table column referencing the target (many2many only)
+}
+
+// FKTable returns the table holding the foreign key: the target table for hasOne and
+// hasMany, the declaring model's table for belongsTo, the join table for many2many.
+func (r RelationDesc) FKTable() string {
+	switch r.Kind {
+	case KindBelongsTo:
+		return r.Table
+	case KindManyToMany:
+		return r.JoinTable
+	}
+	return r.TargetTable
+}
+
+// RelationTable lists the relations of the models of a generated package.
+type RelationTable []RelationDesc
+
+// Of returns the relations declared by model, in declaration order.
+func (t RelationTable) Of(model string) []RelationDesc {
+	var out []RelationDesc
+	for _, r := range t {
+		if r.Model == model {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Get returns the relation name of model.
+func (t RelationTable) Get(model, name string) (RelationDesc, bool) {
+	for _, r := range t {
+		if r.Model == model && r.Name == name {
+			return r, true
+		}
+	}
+	return RelationDesc{}, false
+}
+
+// Targeting returns the relations of other models whose target is model
+// (reverse lookup: "who points at User?").
+func (t RelationTable) Targeting(model string) []RelationDesc {
+	var out []RelationDesc
+	for _, r := range t {
+		if r.Target == model {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Inverse returns the relation declared on rel's target that maps the same foreign key
+// back to rel's model, e.g. Post.Author for User.Posts. The inverse of a many2many
+// relation uses the same join table with the join columns swapped (Role.Users for User.Roles).
+func (t RelationTable) Inverse(rel RelationDesc) (RelationDesc, bool) {
+	for _, r := range t {
+		if r.Model != rel.Target || r.Target != rel.Model || r.FKTable() != rel.FKTable() || r == rel {
+			continue
+		}
+		if rel.Kind == KindManyToMany {
+			if r.Kind == KindManyToMany && r.ForeignKey == rel.JoinReferences && r.JoinReferences == rel.ForeignKey {
+				return r, true
+			}
+			continue
+		}
+		if r.ForeignKey == rel.ForeignKey && r.References == rel.References {
+			return r, true
+		}
+	}
+	return RelationDesc{}, false
+}
+
+// Dependents returns one relation per foreign key referencing model's table, whichever
+// side declares it: hasOne/hasMany relations of model and belongsTo relations targeting it.
+// These are the rows to delete (or detach) before deleting a model row.
+// For many2many relations of model, the dependents are its join table rows; join rows
+// referencing model as the target are listed only if the inverse relation is declared.
+//
+// Example:
+//
+//	for _, rel := range generated.Relations.Dependents("User") {
+//	    // DELETE FROM <rel.FKTable()> WHERE <rel.ForeignKey> = <user.<rel.References>>
+//	}
+func (t RelationTable) Dependents(model string) []RelationDesc {
+	var out []RelationDesc
+	seen := make(map[[3]string]bool)
+	for _, r := range t {
+		refersToModel := (r.Model == model && r.Kind != KindBelongsTo) ||
+			(r.Target == model && r.Kind == KindBelongsTo)
+		if !refersToModel {
+			continue
+		}
+		key := [3]string{r.FKTable(), r.ForeignKey, r.References}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package sqlc_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestRelationTable(t *testing.T) {
+	userPosts := sqlc.RelationDesc{Model: "User", Table: "users", Name: "Posts", Kind: sqlc.KindHasMany, Target: "Post", TargetTable: "posts", ForeignKey: "user_id", References: "id"}
+	userProfile := sqlc.RelationDesc{Model: "User", Table: "users", Name: "Profile", Kind: sqlc.KindHasOne, Target: "Profile", TargetTable: "profiles", ForeignKey: "user_id", References: "id"}
+	postAuthor := sqlc.RelationDesc{Model: "Post", Table: "posts", Name: "Author", Kind: sqlc.KindBelongsTo, Target: "User", TargetTable: "users", ForeignKey: "user_id", References: "id"}
+	commentAuthor := sqlc.RelationDesc{Model: "Comment", Table: "comments", Name: "Author", Kind: sqlc.KindBelongsTo, Target: "User", TargetTable: "users", ForeignKey: "author_id", References: "id"}
+	table := sqlc.RelationTable{userPosts, userProfile, postAuthor, commentAuthor}
+
+	if got := table.Of("User"); !reflect.DeepEqual(got, []sqlc.RelationDesc{userPosts, userProfile}) {
+		t.Errorf("Of: got %+v", got)
+	}
+	if got, ok := table.Get("Post", "Author"); !ok || got != postAuthor {
+		t.Errorf("Get: got %+v, %v", got, ok)
+	}
+	if _, ok := table.Get("Post", "Comments"); ok {
+		t.Error("Get: expected no relation")
+	}
+	if got := table.Targeting("User"); !reflect.DeepEqual(got, []sqlc.RelationDesc{postAuthor, commentAuthor}) {
+		t.Errorf("Targeting: got %+v", got)
+	}
+
+	tests := []struct {
+		name string
+		rel  sqlc.RelationDesc
+		want sqlc.RelationDesc
+		ok   bool
+	}{
+		{"HasManyToBelongsTo", userPosts, postAuthor, true},
+		{"BelongsToHasMany", postAuthor, userPosts, true},
+		{"NoInverse", commentAuthor, sqlc.RelationDesc{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.Inverse(tt.rel)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Inverse: got %+v, %v", got, ok)
+			}
+		})
+	}
+
+	// posts.user_id is declared on both sides but reported once
+	deps := table.Dependents("User")
+	if !reflect.DeepEqual(deps, []sqlc.RelationDesc{userPosts, userProfile, commentAuthor}) {
+		t.Errorf("Dependents: got %+v", deps)
+	}
+	for i, want := range []string{"posts", "profiles", "comments"} {
+		if deps[i].FKTable() != want {
+			t.Errorf("FKTable of %s: got %s, want %s", deps[i].Name, deps[i].FKTable(), want)
+		}
+	}
+}
+
+func TestRelationTable_ManyToMany(t *testing.T) {
+	userRoles := sqlc.RelationDesc{Model: "User", Table: "users", Name: "Roles", Kind: sqlc.KindManyToMany, Target: "Role", TargetTable: "roles", ForeignKey: "user_id", References: "id", JoinTable: "user_roles", JoinReferences: "role_id"}
+	roleUsers := sqlc.RelationDesc{Model: "Role", Table: "roles", Name: "Users", Kind: sqlc.KindManyToMany, Target: "User", TargetTable: "users", ForeignKey: "role_id", References: "id", JoinTable: "user_roles", JoinReferences: "user_id"}
+	userGroups := sqlc.RelationDesc{Model: "User", Table: "users", Name: "Groups", Kind: sqlc.KindManyToMany, Target: "Role", TargetTable: "roles", ForeignKey: "user_id", References: "id", JoinTable: "user_groups", JoinReferences: "group_id"}
+	table := sqlc.RelationTable{userRoles, roleUsers, userGroups}
+
+	if got := userRoles.FKTable(); got != "user_roles" {
+		t.Errorf("FKTable: got %s, want user_roles", got)
+	}
+	if got, ok := table.Inverse(userRoles); !ok || got != roleUsers {
+		t.Errorf("Inverse: got %+v, %v", got, ok)
+	}
+	if got, ok := table.Inverse(userGroups); ok {
+		t.Errorf("Inverse: expected none for another join table, got %+v", got)
+	}
+	if got := table.Dependents("User"); !reflect.DeepEqual(got, []sqlc.RelationDesc{userRoles, userGroups}) {
+		t.Errorf("Dependents: got %+v", got)
+	}
+}
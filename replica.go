@@ -0,0 +1,385 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements read replica routing with fallback to the primary.
+//
+// With WithReadReplicas, the reads of the query builder (Find, Count, Exists, Pluck,
+// aggregates, ChunkStream/Rows and preloads) run on a replica; every other statement, and
+// everything in a transaction, runs on the primary. Replicas lag behind the primary,
+// which shows in two ways, both handled here:
+//   - Schema lag: a table or column created by a migration is not on the replica yet.
+//     Reads failing with an error matched by the lag matchers are retried on the primary
+//   - Stale reads: a request reading right after its own write may not see it.
+//     ReadYourWrites marks a request context; once the request writes, its reads go to
+//     the primary, or to a replica that has caught up with the write (WithReplicaFreshness)
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(primary, sqlc.PostgreSQL,
+//	    sqlc.WithReadReplicas(replica1, replica2),
+//	    sqlc.WithReplicaFreshness(sqlc.PostgreSQLLSN(50*time.Millisecond)),
+//	)
+//
+//	// In an HTTP middleware
+//	ctx := sqlc.ReadYourWrites(r.Context())
+//
+//	users, err := userRepo.Query().Find(ctx)  // replica
+//	err = userRepo.Create(ctx, user)          // primary
+//	user, err = userRepo.FindOne(ctx, user.ID) // primary, or a replica past the write
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaSet holds the read replicas of a session.
+type replicaSet struct {
+	dbs       []*sqlx.DB         // Replica connection pools
+	next      atomic.Uint64      // Round-robin counter
+	lagErrors []func(error) bool // Extra matchers of errors retried on the primary
+	freshness ReplicaFreshness   // Replica position check (nil: pin to the primary after writes)
+}
+
+// replicaSet returns the replica configuration of the session, creating it if needed.
+func (s *Session) replicaSet() *replicaSet {
+	if s.replicas == nil {
+		s.replicas = &replicaSet{}
+	}
+	return s.replicas
+}
+
+// WithReadReplicas routes the reads of the query builder to replicas, picked round-robin.
+//
+// Parameters:
+//   - replicas: Connection pools of the read replicas
+//
+// Example:
+//
+//	session := sqlc.NewSession(primary, sqlc.MySQL, sqlc.WithReadReplicas(replica1, replica2))
+//
+// Note:
+//   - Read on a replica: Find (and First, Take, Last, FindOne), Count, Exists, Pluck, Scan,
+//     aggregates, ChunkStream, Rows and the queries of preloads
+//   - Always on the primary: writes, locking reads (ForUpdate/ForShare), transactions,
+//     and statements run directly through Session (Query, Select, Get, Raw)
+//   - Reads failing because the replica misses a table or column (IsSchemaLagError) are
+//     retried on the primary; see WithReplicaLagErrors for other errors
+func WithReadReplicas(replicas ...*sql.DB) SessionOption {
+	return func(s *Session) {
+		rs := s.replicaSet()
+		for _, db := range replicas {
+			rs.dbs = append(rs.dbs, sqlx.NewDb(db, s.dialect.Name()))
+		}
+	}
+}
+
+// WithReplicaLagErrors retries reads failing on a replica with an error matched by one of
+// match on the primary, in addition to the errors matched by IsSchemaLagError.
+//
+// Example:
+//
+//	// PostgreSQL hot standby canceling a query that conflicts with replayed WAL
+//	sqlc.WithReplicaLagErrors(func(err error) bool {
+//	    return strings.Contains(err.Error(), "conflict with recovery")
+//	})
+func WithReplicaLagErrors(match ...func(error) bool) SessionOption {
+	return func(s *Session) {
+		rs := s.replicaSet()
+		rs.lagErrors = append(rs.lagErrors, match...)
+	}
+}
+
+// WithReplicaFreshness lets the reads of a ReadYourWrites context that wrote use a replica
+// that has applied the write, instead of pinning them to the primary.
+//
+// After each write of the request, the position of the primary is read with f; a later
+// read waits (up to the timeout of f) for its replica to reach it, and runs on the
+// primary if the replica does not.
+//
+// Example:
+//
+//	sqlc.WithReplicaFreshness(sqlc.MySQLGTID(100 * time.Millisecond))
+func WithReplicaFreshness(f ReplicaFreshness) SessionOption {
+	return func(s *Session) {
+		s.replicaSet().freshness = f
+	}
+}
+
+// ReplicaFreshness tracks how far replicas have applied the writes of the primary.
+// Built-in implementations: PostgreSQLLSN and MySQLGTID.
+type ReplicaFreshness interface {
+	// Position returns the current write position of the primary (e.g. WAL LSN, GTID set)
+	Position(ctx context.Context, primary *sql.DB) (string, error)
+	// WaitFor waits until replica has applied position, up to an implementation-defined
+	// timeout, and reports whether it did
+	WaitFor(ctx context.Context, replica *sql.DB, position string) (bool, error)
+}
+
+// freshnessPollInterval is how often PostgreSQLLSN checks the replay position of a replica
+const freshnessPollInterval = 5 * time.Millisecond
+
+// postgresLSN implements ReplicaFreshness with WAL positions.
+type postgresLSN struct {
+	timeout time.Duration
+}
+
+// PostgreSQLLSN tracks PostgreSQL streaming replicas by WAL position: the primary's
+// pg_current_wal_lsn() after a write, against the replica's pg_last_wal_replay_lsn().
+// Reads wait at most timeout for the replica to catch up.
+func PostgreSQLLSN(timeout time.Duration) ReplicaFreshness {
+	return postgresLSN{timeout: timeout}
+}
+
+// Position implements ReplicaFreshness.
+func (f postgresLSN) Position(ctx context.Context, primary *sql.DB) (string, error) {
+	var lsn string
+	err := primary.QueryRowContext(ctx, "SELECT pg_current_wal_lsn()::text").Scan(&lsn)
+	return lsn, err
+}
+
+// WaitFor implements ReplicaFreshness by polling the replay position of the replica.
+func (f postgresLSN) WaitFor(ctx context.Context, replica *sql.DB, position string) (bool, error) {
+	deadline := time.Now().Add(f.timeout)
+	for {
+		// NULL replay position: not a standby, so always current
+		var caughtUp bool
+		err := replica.QueryRowContext(ctx,
+			"SELECT COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, true)", position).Scan(&caughtUp)
+		if err != nil || caughtUp {
+			return caughtUp, err
+		}
+		if time.Now().After(deadline) {
+			return false, nil
+		}
+		if err := waitUntil(ctx, time.Now().Add(freshnessPollInterval)); err != nil {
+			return false, err
+		}
+	}
+}
+
+// mysqlGTID implements ReplicaFreshness with GTID sets.
+type mysqlGTID struct {
+	timeout time.Duration
+}
+
+// MySQLGTID tracks MySQL replicas with GTID-based replication: the primary's
+// @@GLOBAL.gtid_executed after a write, awaited on the replica with
+// WAIT_FOR_EXECUTED_GTID_SET. Reads wait at most timeout for the replica to catch up.
+func MySQLGTID(timeout time.Duration) ReplicaFreshness {
+	return mysqlGTID{timeout: timeout}
+}
+
+// Position implements ReplicaFreshness.
+func (f mysqlGTID) Position(ctx context.Context, primary *sql.DB) (string, error) {
+	var gtids string
+	err := primary.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_executed").Scan(&gtids)
+	return gtids, err
+}
+
+// WaitFor implements ReplicaFreshness; WAIT_FOR_EXECUTED_GTID_SET returns 1 on timeout.
+func (f mysqlGTID) WaitFor(ctx context.Context, replica *sql.DB, position string) (bool, error) {
+	var timedOut int
+	err := replica.QueryRowContext(ctx, "SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)", position, f.timeout.Seconds()).Scan(&timedOut)
+	return err == nil && timedOut == 0, err
+}
+
+// writeFence records the writes of a ReadYourWrites context.
+type writeFence struct {
+	mu       sync.Mutex
+	wrote    bool   // The context wrote through a session with replicas
+	position string // Primary position after the last write ("" if unknown)
+}
+
+// writeFenceKey is the context key of the write fence.
+type writeFenceKey struct{}
+
+// usePrimaryKey is the context key of UsePrimary.
+type usePrimaryKey struct{}
+
+// ReadYourWrites returns a context whose reads see its own writes: once a statement
+// executed with it (or a context derived from it) writes through a session with read
+// replicas, the following reads run on the primary, or on a replica that has caught up
+// when WithReplicaFreshness is set. Reads before the first write still use replicas.
+//
+// Parameters:
+//   - ctx: Request context
+//
+// Returns:
+//   - context.Context: Context tracking its writes
+//
+// Example:
+//
+//	func middleware(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        next.ServeHTTP(w, r.WithContext(sqlc.ReadYourWrites(r.Context())))
+//	    })
+//	}
+//
+// Note:
+//   - If ctx already tracks its writes, it is returned unchanged
+//   - Writes are statements other than SELECT, SHOW and EXPLAIN executed outside
+//     transactions, and committed transactions started with the context
+func ReadYourWrites(ctx context.Context) context.Context {
+	if fenceFromContext(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, writeFenceKey{}, &writeFence{})
+}
+
+// UsePrimary returns a context whose reads always run on the primary, e.g. for a read
+// that must not be stale whether or not the request wrote.
+//
+// Example:
+//
+//	balance, err := accountRepo.Query().Where(...).Pluck(sqlc.UsePrimary(ctx), ...)
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, usePrimaryKey{}, true)
+}
+
+// fenceFromContext returns the write fence of a ReadYourWrites context, or nil.
+func fenceFromContext(ctx context.Context) *writeFence {
+	fence, _ := ctx.Value(writeFenceKey{}).(*writeFence)
+	return fence
+}
+
+// IsSchemaLagError reports whether err is a replica missing a table or column, as when a
+// migration has not been replicated yet. Matches the errors of MySQL ("Table ... doesn't
+// exist", "Unknown column"), PostgreSQL ("relation/column ... does not exist") and SQLite
+// ("no such table/column").
+func IsSchemaLagError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, s := range []string{"doesn't exist", "Unknown column", "does not exist", "no such table", "no such column"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// lagging reports whether a failed replica read should be retried on the primary.
+func (rs *replicaSet) lagging(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsSchemaLagError(err) {
+		return true
+	}
+	for _, match := range rs.lagErrors {
+		if match(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryOnly reports whether reads with ctx must not be served by a replica or shared
+// with other callers: the context forces the primary, or it wrote.
+func primaryOnly(ctx context.Context) bool {
+	if primary, _ := ctx.Value(usePrimaryKey{}).(bool); primary {
+		return true
+	}
+	if fence := fenceFromContext(ctx); fence != nil {
+		fence.mu.Lock()
+		defer fence.mu.Unlock()
+		return fence.wrote
+	}
+	return false
+}
+
+// reader returns the session to run a read-only statement with: s itself, or a copy of s
+// executing on a replica that falls back to s.
+func (s *Session) reader(ctx context.Context) *Session {
+	if s.replicas == nil || len(s.replicas.dbs) == 0 || s.tx != nil || s.recorder != nil {
+		return s
+	}
+	if primary, _ := ctx.Value(usePrimaryKey{}).(bool); primary {
+		return s
+	}
+	rs := s.replicas
+	replica := rs.dbs[(rs.next.Add(1)-1)%uint64(len(rs.dbs))]
+
+	if fence := fenceFromContext(ctx); fence != nil {
+		fence.mu.Lock()
+		wrote, position := fence.wrote, fence.position
+		fence.mu.Unlock()
+		if wrote {
+			if rs.freshness == nil || position == "" {
+				return s
+			}
+			caughtUp, err := rs.freshness.WaitFor(ctx, replica.DB, position)
+			if logger := s.observability().Logger; err != nil && logger != nil {
+				logger.WarnContext(ctx, "replica freshness check failed", "error", err)
+			}
+			if !caughtUp {
+				return s
+			}
+		}
+	}
+
+	r := *s
+	r.executor = replica
+	r.primary = s
+	return &r
+}
+
+// retryOnPrimary reports whether a read that failed with err on a replica should be
+// retried on the primary (s.primary).
+func (s *Session) retryOnPrimary(ctx context.Context, err error) bool {
+	if err == nil || s.primary == nil || !s.primary.replicas.lagging(err) {
+		return false
+	}
+	if logger := s.observability().Logger; logger != nil {
+		logger.DebugContext(ctx, "replica read retried on primary", "error", err)
+	}
+	return true
+}
+
+// noteWrite records a write of s in the ReadYourWrites fence of ctx, if any.
+// Sessions without replicas have nothing to fence.
+func (s *Session) noteWrite(ctx context.Context) {
+	if s.replicas == nil || len(s.replicas.dbs) == 0 {
+		return
+	}
+	fence := fenceFromContext(ctx)
+	if fence == nil {
+		return
+	}
+	var position string
+	if s.replicas.freshness != nil {
+		pos, err := s.replicas.freshness.Position(ctx, s.db.DB)
+		if logger := s.observability().Logger; err != nil && logger != nil {
+			logger.WarnContext(ctx, "failed to read primary position", "error", err)
+		}
+		position = pos
+	}
+	fence.mu.Lock()
+	defer fence.mu.Unlock()
+	fence.wrote = true
+	fence.position = position
+}
+
+// isWrite reports whether a statement executed outside a transaction may write.
+func isWrite(query string) bool {
+	switch statementKind(query) {
+	case "SELECT", "SHOW", "EXPLAIN":
+		return false
+	}
+	return true
+}
+
+// resetDest clears a partially scanned destination before a read is retried.
+func resetDest(dest any) {
+	if v := reflect.ValueOf(dest); v.Kind() == reflect.Pointer && !v.IsNil() {
+		v.Elem().SetZero()
+	}
+}
@@ -0,0 +1,184 @@
+package sqlc_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// fakeFreshness reports a fixed replica state
+type fakeFreshness struct {
+	caughtUp bool
+	waits    *int
+}
+
+func (f fakeFreshness) Position(ctx context.Context, primary *sql.DB) (string, error) {
+	return "pos-1", nil
+}
+
+func (f fakeFreshness) WaitFor(ctx context.Context, replica *sql.DB, position string) (bool, error) {
+	*f.waits++
+	return f.caughtUp && position == "pos-1", nil
+}
+
+func TestReadReplicas(t *testing.T) {
+	sqlc.RegisterSchema(TagSchema{})
+	ctx := context.Background()
+
+	open := func(t *testing.T, tagName string) *sql.DB {
+		t.Helper()
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatalf("failed to open database: %v", err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		if tagName != "" {
+			if _, err := db.Exec("CREATE TABLE tags (id TEXT PRIMARY KEY, name TEXT)"); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+			if _, err := db.Exec("INSERT INTO tags (id, name) VALUES ('go', ?)", tagName); err != nil {
+				t.Fatalf("failed to insert tag: %v", err)
+			}
+		}
+		return db
+	}
+	// The replica holds a stale name of the tag
+	setup := func(t *testing.T, opts ...sqlc.SessionOption) (*sqlc.Session, *sqlc.Repository[Tag]) {
+		t.Helper()
+		primary, replica := open(t, "golang"), open(t, "go")
+		session := sqlc.NewSession(primary, sqlc.SQLiteDialect{}, append([]sqlc.SessionOption{sqlc.WithReadReplicas(replica)}, opts...)...)
+		return session, sqlc.NewRepository[Tag](session)
+	}
+	tagName := func(t *testing.T, ctx context.Context, repo *sqlc.Repository[Tag]) string {
+		t.Helper()
+		tag, err := repo.FindOne(ctx, "go")
+		if err != nil {
+			t.Fatalf("FindOne failed: %v", err)
+		}
+		return tag.Name
+	}
+	rename := func(t *testing.T, ctx context.Context, repo *sqlc.Repository[Tag], name string) {
+		t.Helper()
+		nameCol := clause.Column{Name: "name"}
+		if err := repo.UpdateColumns(ctx, "go", clause.Assignment{Column: nameCol, Value: name}); err != nil {
+			t.Fatalf("UpdateColumns failed: %v", err)
+		}
+	}
+
+	t.Run("ReadsFromReplica", func(t *testing.T) {
+		_, repo := setup(t)
+		if got := tagName(t, ctx, repo); got != "go" {
+			t.Errorf("expected the replica's row, got %q", got)
+		}
+		count, err := repo.Query().Count(ctx)
+		if err != nil || count != 1 {
+			t.Errorf("unexpected count %d, %v", count, err)
+		}
+	})
+
+	t.Run("WritesAndLocksOnPrimary", func(t *testing.T) {
+		session, repo := setup(t)
+		if err := repo.Create(ctx, &Tag{ID: "sql", Name: "SQL"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		var n int
+		if err := session.Get(ctx, &n, "SELECT COUNT(*) FROM tags"); err != nil || n != 2 {
+			t.Errorf("expected the insert on the primary, got %d, %v", n, err)
+		}
+		err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+			if got := tagName(t, ctx, repo.WithSession(tx)); got != "golang" {
+				t.Errorf("expected transactions to read the primary, got %q", got)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		if got := tagName(t, sqlc.UsePrimary(ctx), repo); got != "golang" {
+			t.Errorf("expected UsePrimary to read the primary, got %q", got)
+		}
+	})
+
+	t.Run("SchemaLagRetriedOnPrimary", func(t *testing.T) {
+		session := sqlc.NewSession(open(t, "golang"), sqlc.SQLiteDialect{}, sqlc.WithReadReplicas(open(t, "")))
+		repo := sqlc.NewRepository[Tag](session)
+		if got := tagName(t, ctx, repo); got != "golang" {
+			t.Errorf("expected the primary's row, got %q", got)
+		}
+		if ok, err := repo.Query().Exists(ctx); err != nil || !ok {
+			t.Errorf("unexpected Exists result %v, %v", ok, err)
+		}
+	})
+
+	t.Run("LagErrors", func(t *testing.T) {
+		// A replica that is down fails every read
+		primary, replica := open(t, "golang"), open(t, "go")
+		replica.Close()
+		repo := sqlc.NewRepository[Tag](sqlc.NewSession(primary, sqlc.SQLiteDialect{}, sqlc.WithReadReplicas(replica)))
+		if _, err := repo.FindOne(ctx, "go"); err == nil {
+			t.Fatal("expected the replica error without a matcher")
+		}
+
+		repo = sqlc.NewRepository[Tag](sqlc.NewSession(primary, sqlc.SQLiteDialect{},
+			sqlc.WithReadReplicas(replica),
+			sqlc.WithReplicaLagErrors(func(err error) bool { return strings.Contains(err.Error(), "database is closed") }),
+		))
+		if got := tagName(t, ctx, repo); got != "golang" {
+			t.Errorf("expected the read retried on the primary, got %q", got)
+		}
+	})
+
+	t.Run("ReadYourWrites", func(t *testing.T) {
+		_, repo := setup(t)
+		rctx := sqlc.ReadYourWrites(ctx)
+		if got := tagName(t, rctx, repo); got != "go" {
+			t.Errorf("expected reads before a write on the replica, got %q", got)
+		}
+		rename(t, rctx, repo, "Go")
+		if got := tagName(t, rctx, repo); got != "Go" {
+			t.Errorf("expected the request's write, got %q", got)
+		}
+		if got := tagName(t, ctx, repo); got != "go" {
+			t.Errorf("expected other requests on the replica, got %q", got)
+		}
+	})
+
+	t.Run("ReadYourWritesTransaction", func(t *testing.T) {
+		session, repo := setup(t)
+		rctx := sqlc.ReadYourWrites(ctx)
+		err := session.Transaction(rctx, func(tx *sqlc.Session) error {
+			rename(t, rctx, repo.WithSession(tx), "Go")
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Transaction failed: %v", err)
+		}
+		if got := tagName(t, rctx, repo); got != "Go" {
+			t.Errorf("expected the committed write, got %q", got)
+		}
+	})
+
+	t.Run("Freshness", func(t *testing.T) {
+		for _, tt := range []struct {
+			caughtUp bool
+			want     string
+		}{
+			{true, "go"}, // The fake replica claims to have caught up
+			{false, "Go"},
+		} {
+			waits := 0
+			_, repo := setup(t, sqlc.WithReplicaFreshness(fakeFreshness{caughtUp: tt.caughtUp, waits: &waits}))
+			rctx := sqlc.ReadYourWrites(ctx)
+			tagName(t, rctx, repo)
+			rename(t, rctx, repo, "Go")
+			if got := tagName(t, rctx, repo); got != tt.want || waits != 1 {
+				t.Errorf("caught up %v: got %q after %d waits, want %q after 1", tt.caughtUp, got, waits, tt.want)
+			}
+		}
+	})
+}
@@ -3,22 +3,28 @@
 //
 // Repository is the core component of sqlc ORM, providing type-safe database operations for model T.
 // It encapsulates all common database operations, including:
-//   - Create (Create, BatchCreate, Upsert)
-//   - Read (FindOne, Query)
-//   - Update (Update, UpdateColumns)
+//   - Create (Create, CreateOrIgnore, BatchCreate, Upsert)
+//   - Read (FindOne, FindMany, Query)
+//   - Update (Update, UpdateColumns, Increment, Touch)
 //   - Delete (Delete, DeleteModel, SoftDelete, ForceDelete)
-//   - Soft delete support (SoftDelete, Restore)
+//   - Soft delete support (SoftDelete, Restore, RestoreMany, RestoreModel, RestoreWhere, Trashed, EmptyTrash)
 //   - Conditional scoping (Where)
 package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"maps"
+	"reflect"
 	"slices"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/clause/funcs"
 )
 
 // Repository manages all CRUD operations for model T.
@@ -60,6 +66,44 @@ type Repository[T any] struct {
 	schema   Schema[T]           // Model's Schema implementation
 	scopes   []clause.Expression // Query condition scopes
 	unscoped bool                // Whether to bypass soft delete
+	selected []string            // Columns written by Update (nil = all of UpdateMap)
+	omitted  []string            // Columns skipped by Update
+	noHooks  bool                // Whether to skip lifecycle hooks (SkipHooks)
+
+	noValidation bool // Whether to skip validate rules (SkipValidation)
+}
+
+// exprSqlizer adapts a clause.Expression to squirrel's Sqlizer interface,
+// so scopes can be applied to squirrel UPDATE/DELETE builders.
+type exprSqlizer struct {
+	expr clause.Expression
+}
+
+func (e exprSqlizer) ToSql() (string, []any, error) {
+	return e.expr.Build()
+}
+
+// scopeSqlizer adapts a scope to squirrel's Sqlizer interface, parenthesized so a raw
+// expression containing OR cannot widen the statement it is ANDed into.
+type scopeSqlizer struct {
+	expr clause.Expression
+}
+
+func (s scopeSqlizer) ToSql() (string, []any, error) {
+	sql, args, err := s.expr.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + sql + ")", args, nil
+}
+
+// assignmentValue adapts expression values of assignments (e.g. column + ?) so
+// squirrel renders them inline instead of binding them as arguments.
+func assignmentValue(v any) any {
+	if expr, ok := v.(clause.Expression); ok {
+		return exprSqlizer{expr}
+	}
+	return v
 }
 
 // NewRepository creates a new Repository instance.
@@ -139,7 +183,7 @@ func (r *Repository[T]) Where(conds ...clause.Expression) *Repository[T] {
 
 // Unscoped returns a new Repository instance that bypasses soft delete.
 // When unscoped is set to true, Delete() and DeleteModel() will perform hard delete
-// even if the model supports soft delete.
+// even if the model supports soft delete, and Query() includes soft-deleted records.
 //
 // Example:
 //
@@ -150,6 +194,113 @@ func (r *Repository[T]) Unscoped() *Repository[T] {
 	return &newRepo
 }
 
+// SkipHooks returns a new Repository instance whose writes do not run lifecycle hooks
+// (Before/After Create, Update, Delete and Restore). Use it for bulk imports and data
+// migrations where hook side effects are undesirable; see WithoutHooks for a context-wide
+// switch.
+//
+// Example:
+//
+//	err := userRepo.SkipHooks().BatchCreate(ctx, imported)
+func (r *Repository[T]) SkipHooks() *Repository[T] {
+	newRepo := *r
+	newRepo.noHooks = true
+	return &newRepo
+}
+
+// hookContext returns the context passed to lifecycle hooks: it carries the session of
+// the repository (SessionFromContext), and disables hooks for SkipHooks.
+func (r *Repository[T]) hookContext(ctx context.Context) context.Context {
+	ctx = withHookSession(ctx, r.session)
+	if r.noHooks {
+		return WithoutHooks(ctx)
+	}
+	return ctx
+}
+
+// Select returns a new Repository whose Update only writes the given columns
+// instead of the model's full UpdateMap.
+//
+// Example:
+//
+//	// UPDATE users SET email = ? WHERE id = ?
+//	user.Email = "new@example.com"
+//	err := userRepo.Select(generated.User.Email).Update(ctx, user)
+//
+// Note:
+//   - Update fails if a column is not part of the model's UpdateMap (e.g. the primary key)
+//   - Can be combined with Omit; omitted columns win
+func (r *Repository[T]) Select(columns ...clause.Columnar) *Repository[T] {
+	newRepo := *r
+	newRepo.selected = appendColumnNames(slices.Clip(r.selected), columns)
+	return &newRepo
+}
+
+// Omit returns a new Repository whose Update skips the given columns,
+// e.g. to never overwrite created_at from a partially loaded model.
+//
+// Example:
+//
+//	err := userRepo.Omit(generated.User.CreatedAt, generated.User.PasswordHash).Update(ctx, user)
+func (r *Repository[T]) Omit(columns ...clause.Columnar) *Repository[T] {
+	newRepo := *r
+	newRepo.omitted = appendColumnNames(slices.Clip(r.omitted), columns)
+	return &newRepo
+}
+
+// appendColumnNames appends the column names of columns to names
+func appendColumnNames(names []string, columns []clause.Columnar) []string {
+	for _, c := range columns {
+		names = append(names, c.ColumnName())
+	}
+	return names
+}
+
+// updateSet restricts an UpdateMap to the Select/Omit columns of the repository.
+func (r *Repository[T]) updateSet(setMap map[string]any) (map[string]any, error) {
+	if r.selected != nil {
+		selected := make(map[string]any, len(r.selected))
+		for _, col := range r.selected {
+			v, ok := setMap[col]
+			if !ok {
+				return nil, fmt.Errorf("sqlc: column %q is not updatable", col)
+			}
+			selected[col] = v
+		}
+		setMap = selected
+	}
+	if len(r.omitted) > 0 {
+		setMap = maps.Clone(setMap)
+		for _, col := range r.omitted {
+			delete(setMap, col)
+		}
+	}
+	if len(setMap) == 0 {
+		return nil, fmt.Errorf("sqlc: Update has no columns to set")
+	}
+	return setMap, nil
+}
+
+// WithSession returns a copy of the Repository bound to session s, typically a
+// transaction session. Scopes and the unscoped flag are preserved.
+//
+// Parameters:
+//   - s: Session to run operations on
+//
+// Example:
+//
+//	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+//	    if err := userRepo.WithSession(tx).Create(ctx, user); err != nil {
+//	        return err // Auto rollback
+//	    }
+//	    return orderRepo.WithSession(tx).Create(ctx, order)
+//	})
+func (r *Repository[T]) WithSession(s *Session) *Repository[T] {
+	newRepo := *r
+	newRepo.session = s
+	return &newRepo
+}
+
 // Create inserts a new record into the database.
 // This is the recommended way to create a single record.
 //
@@ -163,6 +314,7 @@ func (r *Repository[T]) Unscoped() *Repository[T] {
 // Parameters:
 //   - ctx: Context, supports cancellation and timeout
 //   - model: Model instance pointer, may be modified after insertion (auto-increment ID backfilled)
+//   - opts: Options (WithAssociations to also save populated relation fields)
 //
 // Returns:
 //   - error: Insertion error or hook error
@@ -183,9 +335,29 @@ func (r *Repository[T]) Unscoped() *Repository[T] {
 //	}
 //
 //	fmt.Println("Created user ID:", user.ID) // Auto-increment ID backfilled
-func (r *Repository[T]) Create(ctx context.Context, model *T) error {
+//
+//	// With its posts, in one transaction
+//	user.Posts = []*models.Post{{Title: "Hello"}}
+//	err := userRepo.Create(ctx, user, sqlc.WithAssociations("Posts"))
+func (r *Repository[T]) Create(ctx context.Context, model *T, opts ...CreateOption) error {
+	if len(opts) > 0 {
+		var cfg createConfig
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+		if cfg.associations != nil || cfg.allAssociations {
+			return r.createWithAssociations(ctx, model, cfg)
+		}
+	}
+	if err := policyGranted[T](ctx, r.session, PolicyCreate); err != nil {
+		return err
+	}
+
 	// Trigger BeforeCreate hook
-	if err := triggerBeforeCreate(ctx, model); err != nil {
+	if err := triggerBeforeCreate(r.hookContext(ctx), model); err != nil {
+		return err
+	}
+	if err := r.validateModel(model, nil); err != nil {
 		return err
 	}
 
@@ -218,35 +390,207 @@ func (r *Repository[T]) Create(ctx context.Context, model *T) error {
 		}
 	}
 
+	// Record the change for the session's search indexer
+	if err := indexModels(ctx, r.session, ChangeCreate, r.schema.TableName(), model); err != nil {
+		return err
+	}
+
+	// Trigger AfterCreate hook
+	return triggerAfterCreate(r.hookContext(ctx), model)
+}
+
+// CreateOrIgnore inserts a new record unless it conflicts with an existing one, in which
+// case nothing is written. Unlike Create, a duplicate key is not an error.
+//
+// Database dialect differences:
+//   - MySQL: INSERT IGNORE (onConflict is ignored, any unique key counts)
+//   - PostgreSQL: INSERT ... ON CONFLICT (...) DO NOTHING
+//   - SQLite: INSERT ... ON CONFLICT (...) DO NOTHING
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - model: Model instance pointer; the auto-increment ID is backfilled if inserted
+//   - onConflict: Conflict target columns; if empty, any unique or primary key conflict is ignored
+//
+// Returns:
+//   - bool: true if the row was inserted, false if it was skipped due to a conflict
+//   - error: Insertion error or hook error
+//
+// Example:
+//
+//	inserted, err := tagRepo.CreateOrIgnore(ctx, &models.Tag{Name: "go"}, generated.Tag.Name)
+//	if err != nil {
+//	    return err
+//	}
+//	if !inserted {
+//	    log.Println("tag already exists")
+//	}
+//
+// Note:
+//   - BeforeCreate always runs; AfterCreate only runs when the row was inserted
+//   - MySQL's INSERT IGNORE also downgrades other errors (e.g. truncation) to warnings
+//   - Dry-run sessions report false, as no row is inserted
+func (r *Repository[T]) CreateOrIgnore(ctx context.Context, model *T, onConflict ...clause.Columnar) (bool, error) {
+	if err := policyGranted[T](ctx, r.session, PolicyCreate); err != nil {
+		return false, err
+	}
+
+	// Trigger BeforeCreate hook
+	if err := triggerBeforeCreate(r.hookContext(ctx), model); err != nil {
+		return false, err
+	}
+	if err := r.validateModel(model, nil); err != nil {
+		return false, err
+	}
+
+	// Build INSERT IGNORE / INSERT ... ON CONFLICT DO NOTHING statement
+	cols, vals := r.schema.InsertRow(model)
+	builder := sq.Insert(r.schema.TableName()).
+		Columns(cols...).
+		Values(vals...).
+		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
+	if insertIgnore(r.session.dialect) {
+		builder = builder.Options("IGNORE")
+	} else {
+		builder = builder.Suffix(buildOnConflictDoNothing(ResolveColumnNames(onConflict)))
+	}
+
+	// Return the generated ID where the dialect supports INSERT ... RETURNING:
+	// an ignored row returns no rows
+	returning := r.schema.AutoIncrement() && insertReturning(r.session.dialect) && r.session.recorder == nil
+	if returning {
+		builder = builder.Suffix("RETURNING " + r.schema.PK(nil).Column.Name)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var inserted bool
+	if returning {
+		var ids []int64
+		if err := r.session.Select(ctx, &ids, query, args...); err != nil {
+			return false, err
+		}
+		if inserted = len(ids) > 0; inserted {
+			r.schema.SetPK(model, ids[0])
+		}
+	} else {
+		result, err := r.session.Exec(ctx, query, args...)
+		if err != nil {
+			return false, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return false, err
+		}
+		inserted = n > 0
+		if inserted && r.schema.AutoIncrement() {
+			if id, err := result.LastInsertId(); err == nil {
+				r.schema.SetPK(model, id)
+			}
+		}
+	}
+
+	if !inserted {
+		return false, nil
+	}
+	if err := indexModels(ctx, r.session, ChangeCreate, r.schema.TableName(), model); err != nil {
+		return true, err
+	}
 	// Trigger AfterCreate hook
-	return triggerAfterCreate(ctx, model)
+	return true, triggerAfterCreate(r.hookContext(ctx), model)
+}
+
+// Batch Create Options
+type batchConfig struct {
+	size int    // Maximum rows per INSERT statement (0 = dialect placeholder limit only)
+	tx   bool   // Run all statements in one transaction
+	key  string // Unique column matching returned IDs to models ("" = ascending ID order)
+}
+
+// BatchOption configures BatchCreate.
+// Uses functional options pattern to provide flexible configuration.
+type BatchOption func(*batchConfig)
+
+// WithBatchSize limits the number of rows inserted per INSERT statement.
+// Larger slices are split into several statements.
+//
+// Example:
+//
+//	err := eventRepo.BatchCreate(ctx, events, sqlc.WithBatchSize(500))
+func WithBatchSize(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.size = n
+	}
+}
+
+// WithBatchTransaction runs all INSERT statements of a chunked BatchCreate in one
+// transaction, so either every row is inserted or none is. Inside a transaction
+// session the existing transaction is joined.
+//
+// Example:
+//
+//	err := eventRepo.BatchCreate(ctx, events, sqlc.WithBatchSize(500), sqlc.WithBatchTransaction())
+func WithBatchTransaction() BatchOption {
+	return func(c *batchConfig) {
+		c.tx = true
+	}
+}
+
+// WithReturningKey matches the IDs returned by INSERT ... RETURNING to the models on a
+// unique inserted column instead of relying on the order of the generated IDs.
+// The column is returned alongside the primary key and each row is assigned the ID
+// whose key equals its own value.
+//
+// Parameters:
+//   - column: Unique column among the inserted ones (string or integer values)
+//
+// Returns:
+//   - BatchOption: Configuration function
+//
+// Example:
+//
+//	err := userRepo.BatchCreate(ctx, users, sqlc.WithReturningKey(generated.User.Email))
+//
+// Note:
+//   - Only used where IDs are backfilled (PostgreSQL, SQLite 3.35+, MariaDB 10.5+)
+//   - Fails if the column is not inserted or a returned key matches no model
+func WithReturningKey(column clause.Columnar) BatchOption {
+	return func(c *batchConfig) {
+		c.key = column.ColumnName()
+	}
 }
 
-// BatchCreate inserts multiple records in a single SQL statement.
+// BatchCreate inserts multiple records with multi-row INSERT statements.
 // This is more efficient than calling Create() in a loop, suitable for batch import scenarios.
 //
 // Operation flow:
 //  1. Trigger BeforeCreate hook for each model
-//  2. Build batch INSERT statement (single SQL, multiple VALUES)
-//  3. Execute batch insertion
+//  2. Split models into chunks (WithBatchSize and the dialect's placeholder limit)
+//  3. Execute one multi-row INSERT per chunk
 //  4. Trigger AfterCreate hook for each model
 //
 // Parameters:
 //   - ctx: Context, supports cancellation and timeout
 //   - models: Model instance pointer slice
+//   - opts: Options (WithBatchSize, WithBatchTransaction, WithReturningKey)
 //
 // Returns:
 //   - error: Insertion error or hook error
 //
 // Note:
 //   - Empty slice will immediately return nil (no-op)
-//   - Auto-increment IDs will not be backfilled to models (database limitation)
+//   - Auto-increment IDs are backfilled via INSERT ... RETURNING on PostgreSQL, SQLite 3.35+
+//     and MariaDB 10.5+ (MariaDBDialect); MySQL cannot return them, so IDs stay unset
+//   - RETURNING does not guarantee row order, so the returned IDs are sorted and assigned
+//     in insertion order, which assumes the sequence allocates them ascending per statement.
+//     Use WithReturningKey to match them on a unique column instead
 //   - If any hook fails, entire operation aborts
-//   - Does not support partial rollback within transaction (should be called outside transaction)
-//
-// Performance suggestions:
-//   - For large amounts of data (>1000 records), consider calling in batches
-//   - Database may have single SQL size limits
+//   - Chunks are always kept under the dialect's bind variable limit (e.g. 999 on SQLite),
+//     even without WithBatchSize
+//   - Without WithBatchTransaction, a failing chunk leaves the earlier chunks inserted
 //
 // Example:
 //
@@ -260,62 +604,236 @@ func (r *Repository[T]) Create(ctx context.Context, model *T) error {
 //	    return err
 //	}
 //
-//	// Note: users[i].ID will not be set
-func (r *Repository[T]) BatchCreate(ctx context.Context, models []*T) error {
+//	fmt.Println(users[0].ID) // Backfilled except on MySQL
+//
+//	// Large import: 500 rows per statement, all or nothing
+//	err := userRepo.BatchCreate(ctx, imported, sqlc.WithBatchSize(500), sqlc.WithBatchTransaction())
+func (r *Repository[T]) BatchCreate(ctx context.Context, models []*T, opts ...BatchOption) error {
 	// Empty slice fast return
 	if len(models) == 0 {
 		return nil
 	}
 
-	// Trigger BeforeCreate hook for all models
+	cfg := batchConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := policyGranted[T](ctx, r.session, PolicyCreate); err != nil {
+		return err
+	}
+
+	// Trigger BeforeCreate hook for all models, then validate them all before inserting any
+	for _, model := range models {
+		if err := triggerBeforeCreate(r.hookContext(ctx), model); err != nil {
+			return err
+		}
+	}
+	for i, model := range models {
+		if err := r.validateModel(model, nil); err != nil {
+			return fmt.Errorf("sqlc: record %d: %w", i, err)
+		}
+	}
+
+	// Rows per statement: WithBatchSize, capped by the dialect's placeholder limit
+	size := len(models)
+	if cfg.size > 0 {
+		size = min(size, cfg.size)
+	}
+	if limit := maxPlaceholders(r.session.dialect); limit > 0 {
+		cols, _ := r.schema.InsertRow(models[0])
+		size = min(size, max(1, limit/max(1, len(cols))))
+	}
+
+	insert := func(s *Session) error {
+		repo := r.WithSession(s)
+		for chunk := range slices.Chunk(models, size) {
+			if err := repo.insertBatch(ctx, chunk, cfg.key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var err error
+	if cfg.tx && size < len(models) {
+		err = r.session.Transaction(ctx, insert)
+	} else {
+		err = insert(r.session)
+	}
+	if err != nil {
+		return err
+	}
+	if err := indexModels(ctx, r.session, ChangeCreate, r.schema.TableName(), models...); err != nil {
+		return err
+	}
+
+	// Trigger AfterCreate hook for all models
 	for _, model := range models {
-		if err := triggerBeforeCreate(ctx, model); err != nil {
+		if err := triggerAfterCreate(r.hookContext(ctx), model); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
+// insertBatch inserts models with a single multi-row INSERT statement.
+// key is the unique column matching returned IDs to models ("" = ascending ID order).
+func (r *Repository[T]) insertBatch(ctx context.Context, models []*T, key string) error {
 	// Build batch INSERT statement
 	builder := sq.Insert(r.schema.TableName()).
 		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
 
-	// Add each row of data
+	// Return generated IDs where the dialect supports INSERT ... RETURNING
+	returning := r.schema.AutoIncrement() && insertReturning(r.session.dialect)
+	if !returning {
+		key = ""
+	}
+
+	// Add each row of data, remembering the backfill key of each model
+	var keys []any
 	for i, model := range models {
 		cols, vals := r.schema.InsertRow(model)
 		if i == 0 {
 			// First row sets column names
 			builder = builder.Columns(cols...)
 		}
+		if key != "" {
+			idx := slices.Index(cols, key)
+			if idx < 0 {
+				return fmt.Errorf("sqlc: backfill key %q is not an inserted column", key)
+			}
+			keys = append(keys, vals[idx])
+		}
 		// Add values
 		builder = builder.Values(vals...)
 	}
 
+	if returning {
+		suffix := "RETURNING " + r.schema.PK(nil).Column.Name
+		if key != "" {
+			suffix += ", " + key
+		}
+		builder = builder.Suffix(suffix)
+	}
+
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
 	if err != nil {
 		return err
 	}
 
+	// Dry-run sessions record the statement without returning rows
+	if returning && r.session.recorder == nil {
+		return r.backfillIDs(ctx, models, keys, query, args)
+	}
 	_, err = r.session.Exec(ctx, query, args...)
+	return err
+}
+
+// backfillIDs runs a batch INSERT ... RETURNING query and sets the returned IDs on models.
+// RETURNING does not guarantee that rows come back in insertion order, so with keys the
+// second returned column is matched against each model's key value; without keys the IDs
+// are sorted and assigned in insertion order, relying on ascending ID allocation.
+func (r *Repository[T]) backfillIDs(ctx context.Context, models []*T, keys []any, query string, args []any) error {
+	rows, err := r.session.Query(ctx, query, args...)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
+
+	var ids []int64
+	byKey := make(map[string]int64, len(keys))
+	for rows.Next() {
+		var id int64
+		var key any
+		dest := []any{&id}
+		if keys != nil {
+			dest = append(dest, &key)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("sqlc: failed to scan returned id: %w", err)
+		}
+		ids = append(ids, id)
+		if keys != nil {
+			byKey[backfillKey(key)] = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(ids) != len(models) {
+		return fmt.Errorf("sqlc: batch insert returned %d ids for %d rows", len(ids), len(models))
+	}
 
-	// Note: Cannot easily get all auto-increment IDs for batch insert across all databases
-	// For MVP version, we skip updating model IDs
-
-	// Trigger AfterCreate hook for all models
-	for _, model := range models {
-		if err := triggerAfterCreate(ctx, model); err != nil {
-			return err
+	if keys == nil {
+		slices.Sort(ids)
+		for i, model := range models {
+			r.schema.SetPK(model, ids[i])
+		}
+		return nil
+	}
+	for i, model := range models {
+		id, ok := byKey[backfillKey(keys[i])]
+		if !ok {
+			return fmt.Errorf("sqlc: record %d: no returned id for backfill key %v", i, keys[i])
 		}
+		r.schema.SetPK(model, id)
 	}
 	return nil
 }
 
+// backfillKey normalizes an inserted or returned key value for comparison.
+// Drivers may return text as []byte and integers as int64 whatever the field type.
+func backfillKey(v any) string {
+	if cv, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		v = cv
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
 // Upsert Options
 type upsertConfig struct {
-	conflictCols []string // Conflict detection columns (unique constraint or primary key)
-	updateCols   []string // Columns to update when conflict occurs
+	conflictCols []string          // Conflict detection columns (unique constraint or primary key)
+	updateCols   []string          // Columns to update when conflict occurs
+	where        clause.Expression // Predicate of a partial unique index (nil for full indexes)
+	live         bool              // The unique index only covers records not soft-deleted
+}
+
+// predicate returns the index predicate of the conflict target, or nil for a full index.
+// OnConflictLive contributes the live record condition of the soft delete column sdCol.
+func (c *upsertConfig) predicate(sdCol string, live any) clause.Expression {
+	var preds []clause.Expression
+	if c.live && sdCol != "" {
+		preds = append(preds, liveRecordPredicate(sdCol, live))
+	}
+	if c.where != nil {
+		preds = append(preds, c.where)
+	}
+	switch len(preds) {
+	case 0:
+		return nil
+	case 1:
+		return preds[0]
+	}
+	return clause.And(preds)
+}
+
+// liveRecordPredicate returns the condition matching live records of a soft delete column.
+// Integer and boolean live values are inlined: PostgreSQL only infers a partial index
+// from predicates with constants, not bind parameters.
+func liveRecordPredicate(column string, live any) clause.Expression {
+	col := clause.Column{Name: column}
+	if live == nil {
+		return clause.IsNull{Column: col}
+	}
+	switch reflect.ValueOf(live).Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return clause.Expr{SQL: fmt.Sprintf("%s = %v", col.ColumnName(), live)}
+	}
+	return clause.Eq{Column: col, Value: live}
 }
 
 // UpsertOption defines configuration function for Upsert operation.
@@ -350,6 +868,61 @@ func OnConflict(columns ...clause.Columnar) UpsertOption {
 	}
 }
 
+// OnConflictWhere sets the predicate of a partial unique index used as conflict target:
+// ON CONFLICT (columns) WHERE predicate. Required on PostgreSQL and SQLite when the unique
+// index of the OnConflict columns is partial, e.g. CREATE UNIQUE INDEX ... WHERE archived = false.
+//
+// Parameters:
+//   - predicate: Condition implying the index predicate (usually the same condition)
+//
+// Returns:
+//   - UpsertOption: Configuration function
+//
+// Example:
+//
+//	// CREATE UNIQUE INDEX users_email_active ON users (email) WHERE status <> 'banned'
+//	err := userRepo.Upsert(ctx, user,
+//	    sqlc.OnConflict(generated.User.Email),
+//	    sqlc.OnConflictWhere(clause.Expr{SQL: "status <> 'banned'"}),
+//	)
+//
+// Note:
+//   - MySQL has no partial indexes; the predicate is ignored there
+//   - PostgreSQL only infers a partial index from a predicate written with constants:
+//     prefer clause.Expr with literal values over conditions with bind parameters
+//   - Combined with OnConflictLive, both conditions apply
+func OnConflictWhere(predicate clause.Expression) UpsertOption {
+	return func(c *upsertConfig) {
+		c.where = predicate
+	}
+}
+
+// OnConflictLive declares that the unique index of the OnConflict columns only covers
+// records that are not soft-deleted, as in
+// CREATE UNIQUE INDEX users_email ON users (email) WHERE deleted_at IS NULL.
+// The conflict target gets the live record condition of the model's soft delete column
+// (deleted_at IS NULL, or its sentinel/zero value), so a soft-deleted record with the
+// same values does not conflict. Has no effect on models without soft delete.
+//
+// Returns:
+//   - UpsertOption: Configuration function
+//
+// Example:
+//
+//	// Re-registering an email whose previous account was deleted inserts a new user
+//	err := userRepo.Upsert(ctx, user,
+//	    sqlc.OnConflict(generated.User.Email),
+//	    sqlc.OnConflictLive(),
+//	)
+//
+//	// The same index definition for a uniqueness check before restoring
+//	err := userRepo.EnsureUnique(ctx, user, sqlc.OnConflict(generated.User.Email), sqlc.OnConflictLive())
+func OnConflictLive() UpsertOption {
+	return func(c *upsertConfig) {
+		c.live = true
+	}
+}
+
 // DoUpdate specifies which columns to update when a conflict occurs.
 // If not specified, all model columns (except conflict columns) are updated.
 //
@@ -401,7 +974,7 @@ func DoUpdate(columns ...clause.Columnar) UpsertOption {
 // Parameters:
 //   - ctx: Context, supports cancellation and timeout
 //   - model: Model instance pointer
-//   - opts: Optional configuration (OnConflict, DoUpdate)
+//   - opts: Optional configuration (OnConflict, OnConflictWhere, OnConflictLive, DoUpdate)
 //
 // Returns:
 //   - error: Insert/update error or hook error
@@ -421,6 +994,14 @@ func DoUpdate(columns ...clause.Columnar) UpsertOption {
 //	    sqlc.OnConflict(generated.User.Email),
 //	    sqlc.DoUpdate(generated.User.Name, generated.User.LastLoginAt),
 //	)
+//
+// Note:
+//   - With row policies (WithPolicies), fails with ErrPolicyDenied unless the roles of ctx
+//     grant PolicyCreate and PolicyUpdate on all rows, as the conflict update is unfiltered
+//   - Search indexes (WithIndexer) receive ChangeCreate for an inserted row and ChangeUpdate
+//     for an updated one on PostgreSQL and MySQL (dialects implementing UpsertOutcome).
+//     SQLite cannot tell them apart, so it always reports ChangeUpdate
+//   - With clientFoundRows=true, MySQL counts an unchanged row as 1 and reports ChangeCreate
 func (r *Repository[T]) Upsert(ctx context.Context, model *T, opts ...UpsertOption) error {
 	// Apply configuration options
 	config := &upsertConfig{}
@@ -428,8 +1009,16 @@ func (r *Repository[T]) Upsert(ctx context.Context, model *T, opts ...UpsertOpti
 		opt(config)
 	}
 
+	// Conflict updates cannot be filtered by the update policy: it must grant all rows
+	if err := policyGranted[T](ctx, r.session, PolicyCreate, PolicyUpdate); err != nil {
+		return err
+	}
+
 	// Trigger BeforeCreate hook
-	if err := triggerBeforeCreate(ctx, model); err != nil {
+	if err := triggerBeforeCreate(r.hookContext(ctx), model); err != nil {
+		return err
+	}
+	if err := r.validateModel(model, nil); err != nil {
 		return err
 	}
 
@@ -454,29 +1043,159 @@ func (r *Repository[T]) Upsert(ctx context.Context, model *T, opts ...UpsertOpti
 		}
 	}
 
-	// Get dialect-specific Upsert clause
+	// Get dialect-specific Upsert clause, targeting a partial unique index if configured
 	upsertClause := r.session.dialect.UpsertClause(r.schema.TableName(), conflictCols, updateCols)
+	var predicateArgs []any
+	if pred := config.predicate(r.schema.SoftDeleteColumn(), softDeleteActive(r.schema)); pred != nil {
+		if d, ok := r.session.dialect.(ConflictPredicate); ok {
+			predicateSQL, args, err := pred.Build()
+			if err != nil {
+				return fmt.Errorf("sqlc: failed to build conflict predicate: %w", err)
+			}
+			upsertClause = d.UpsertWhereClause(r.schema.TableName(), conflictCols, predicateSQL, updateCols)
+			predicateArgs = args
+		}
+	}
 
 	// Build INSERT ... ON CONFLICT statement
 	builder := sq.Insert(r.schema.TableName()).
 		Columns(cols...).
 		Values(vals...).
-		Suffix(upsertClause).
+		Suffix(upsertClause, predicateArgs...).
 		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
 
+	// Tell an inserted row from an updated one where the dialect can;
+	// dry-run sessions record the statement without returning rows
+	outcome, known := r.session.dialect.(UpsertOutcome)
+	known = known && r.session.recorder == nil
+	var returning string
+	if known {
+		returning = outcome.UpsertInserted()
+	}
+	if returning != "" {
+		builder = builder.Suffix("RETURNING " + returning)
+	}
+
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.session.Exec(ctx, query, args...)
-	if err != nil {
+	op := ChangeUpdate
+	if returning != "" {
+		var inserted []bool
+		if err := r.session.Select(ctx, &inserted, query, args...); err != nil {
+			return err
+		}
+		if len(inserted) > 0 && inserted[0] {
+			op = ChangeCreate
+		}
+	} else {
+		result, err := r.session.Exec(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); known && err == nil && n == 1 {
+			op = ChangeCreate
+		}
+	}
+	if err := indexModels(ctx, r.session, op, r.schema.TableName(), model); err != nil {
 		return err
 	}
 
 	// Trigger AfterCreate hook
-	return triggerAfterCreate(ctx, model)
+	return triggerAfterCreate(r.hookContext(ctx), model)
+}
+
+// ErrUniqueViolation is returned by EnsureUnique when another record holds the same unique values.
+var ErrUniqueViolation = errors.New("sqlc: unique value already taken")
+
+// EnsureUnique checks that no other record holds the values of model's OnConflict columns,
+// using the same index definition as Upsert: without a predicate the unique index covers
+// every record, soft-deleted ones included; with OnConflictLive and OnConflictWhere only
+// the records matching the index predicate count.
+//
+// Typical uses are validating input before a write, and BeforeRestore hooks checking that
+// no live record took over a unique value while the model was soft-deleted.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - model: Model instance pointer; the record with its primary key is not a conflict
+//   - opts: OnConflict (required), OnConflictWhere, OnConflictLive
+//
+// Returns:
+//   - error: ErrUniqueViolation (wrapped, naming the columns) if taken, or a query error
+//
+// Example:
+//
+//	func (u *User) BeforeRestore(ctx context.Context) error {
+//	    return userRepo.EnsureUnique(ctx, u,
+//	        sqlc.OnConflict(generated.User.Email),
+//	        sqlc.OnConflictLive(),
+//	    )
+//	}
+//
+// Note:
+//   - The check runs on the primary, but is not atomic with a later write: keep the unique
+//     index, and treat its violations as the authoritative answer under concurrency
+//   - NULL never conflicts, as in unique indexes; a model with a NULL value passes
+func (r *Repository[T]) EnsureUnique(ctx context.Context, model *T, opts ...UpsertOption) error {
+	config := &upsertConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if len(config.conflictCols) == 0 {
+		return fmt.Errorf("sqlc: EnsureUnique requires OnConflict columns")
+	}
+
+	// Values of the unique columns, the primary key included for auto-increment models
+	pk := r.schema.PK(model)
+	cols, vals := r.schema.InsertRow(model)
+	cols, vals = append(cols, pk.Column.Name), append(vals, pk.Value)
+
+	q := r.Query().WithTrashed()
+	if pred := config.predicate(r.schema.SoftDeleteColumn(), softDeleteActive(r.schema)); pred != nil {
+		q = q.Where(pred)
+	}
+	for _, col := range config.conflictCols {
+		i := slices.Index(cols, col)
+		if i < 0 {
+			return fmt.Errorf("sqlc: EnsureUnique column %s is not a column of %s", col, r.schema.TableName())
+		}
+		if isNullValue(vals[i]) {
+			return nil
+		}
+		q = q.Where(clause.Eq{Column: clause.Column{Name: col}, Value: vals[i]})
+	}
+	if !isZeroValue(pk.Value) {
+		q = q.Where(clause.Neq{Column: pk.Column, Value: pk.Value})
+	}
+
+	taken, err := q.Exists(UsePrimary(ctx))
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("%w: %s", ErrUniqueViolation, strings.Join(config.conflictCols, ", "))
+	}
+	return nil
+}
+
+// isNullValue reports whether v is bound as NULL: nil, a nil pointer, or a driver.Valuer
+// whose value is nil.
+func isNullValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return true
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		return err == nil && dv == nil
+	}
+	return false
 }
 
 // Update updates a record in the database.
@@ -516,13 +1235,38 @@ func (r *Repository[T]) Upsert(ctx context.Context, model *T, opts ...UpsertOpti
 //	    return err
 //	}
 func (r *Repository[T]) Update(ctx context.Context, model *T) error {
+	_, err := r.UpdateResult(ctx, model)
+	return err
+}
+
+// UpdateResult is like Update but also returns the number of affected rows.
+// Zero means no record matched the primary key and scopes, e.g. the record was
+// deleted concurrently or a scope used as an optimistic check did not match.
+//
+// Example:
+//
+//	n, err := orderRepo.Where(generated.Order.Version.Eq(order.Version)).UpdateResult(ctx, order)
+//	if err == nil && n == 0 {
+//	    return ErrConcurrentModification
+//	}
+//
+// Note:
+//   - MySQL counts only rows whose values changed unless the DSN sets clientFoundRows=true
+//   - Hooks run as in Update, even if no row was affected
+func (r *Repository[T]) UpdateResult(ctx context.Context, model *T) (int64, error) {
 	// Trigger BeforeUpdate hook
-	if err := triggerBeforeUpdate(ctx, model); err != nil {
-		return err
+	if err := triggerBeforeUpdate(r.hookContext(ctx), model); err != nil {
+		return 0, err
 	}
 
-	// Extract update data from model
-	setMap := r.schema.UpdateMap(model)
+	// Extract update data from model, restricted to Select/Omit columns
+	setMap, err := r.updateSet(r.schema.UpdateMap(model))
+	if err != nil {
+		return 0, err
+	}
+	if err := r.validateModel(model, setMap); err != nil {
+		return 0, err
+	}
 	pk := r.schema.PK(model)
 
 	// Build UPDATE statement
@@ -530,9 +1274,13 @@ func (r *Repository[T]) Update(ctx context.Context, model *T) error {
 		SetMap(setMap).
 		Where(sq.Eq{pk.Column.Name: pk.Value})
 
-	// Apply Scopes
-	for _, scope := range r.scopes {
-		builder = builder.Where(scope)
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyUpdate)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
 	}
 
 	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
@@ -540,16 +1288,24 @@ func (r *Repository[T]) Update(ctx context.Context, model *T) error {
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = r.session.Exec(ctx, query, args...)
+	res, err := r.session.Exec(ctx, query, args...)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := indexModels(ctx, r.session, ChangeUpdate, r.schema.TableName(), model); err != nil {
+		return affected, err
 	}
 
 	// Trigger AfterUpdate hook
-	return triggerAfterUpdate(ctx, model)
+	return affected, triggerAfterUpdate(r.hookContext(ctx), model)
 }
 
 // UpdateColumns updates specific columns for a record identified by id.
@@ -595,9 +1351,28 @@ func (r *Repository[T]) Update(ctx context.Context, model *T) error {
 //	        clause.Assignment{Column: generated.User.Status.Column(), Value: "processed"},
 //	    )
 func (r *Repository[T]) UpdateColumns(ctx context.Context, id any, assignments ...clause.Assignment) error {
+	_, err := r.UpdateColumnsResult(ctx, id, assignments...)
+	return err
+}
+
+// UpdateColumnsResult is like UpdateColumns but also returns the number of affected rows.
+// Zero means no record matched the id and scopes.
+//
+// Note:
+//   - MySQL counts only rows whose values changed unless the DSN sets clientFoundRows=true
+func (r *Repository[T]) UpdateColumnsResult(ctx context.Context, id any, assignments ...clause.Assignment) (int64, error) {
+	return r.updateColumns(ctx, PolicyUpdate, id, assignments...)
+}
+
+// updateColumns updates columns of the record with the given id, restricted by the
+// scopes and the row policy of action (PolicyDelete for soft deletes).
+func (r *Repository[T]) updateColumns(ctx context.Context, action PolicyAction, id any, assignments ...clause.Assignment) (int64, error) {
 	// Empty assignment fast return
 	if len(assignments) == 0 {
-		return nil
+		return 0, nil
+	}
+	if err := r.validateAssignments(assignments); err != nil {
+		return 0, err
 	}
 
 	// Get primary key metadata
@@ -607,26 +1382,187 @@ func (r *Repository[T]) UpdateColumns(ctx context.Context, id any, assignments .
 	builder := sq.Update(r.schema.TableName()).
 		Where(sq.Eq{pkMeta.Column.Name: id})
 
-	// Apply Scopes
-	for _, scope := range r.scopes {
-		builder = builder.Where(scope)
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, action)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
 	}
 
 	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
 
 	// Add column assignments
 	for _, assignment := range assignments {
-		builder = builder.Set(assignment.Column.ColumnName(), assignment.Value)
+		builder = builder.Set(assignment.Column.ColumnName(), assignmentValue(assignment.Value))
 	}
 
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = r.session.Exec(ctx, query, args...)
-	return err
+	res, err := r.session.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// UpdateWhere updates all records matching the repository's scopes in a single
+// UPDATE statement and returns the number of affected rows.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - assignments: Column assignment list (column = value)
+//
+// Returns:
+//   - int64: Number of updated rows
+//   - error: Update error
+//
+// Note:
+//   - At least one scope (Where) is required, so a missing condition cannot update the whole table
+//   - Soft-deleted records are skipped unless the repository is Unscoped()
+//   - Empty assignments will immediately return 0 (no-op)
+//   - Does not trigger lifecycle hooks (no model instances)
+//   - MySQL counts only rows whose values actually changed
+//
+// Example:
+//
+//	// Archive all inactive users
+//	archived, err := userRepo.
+//	    Where(generated.User.LastLoginAt.Lt(time.Now().AddDate(-1, 0, 0))).
+//	    UpdateWhere(ctx,
+//	        clause.Assignment{Column: generated.User.Status.Column(), Value: "archived"},
+//	    )
+func (r *Repository[T]) UpdateWhere(ctx context.Context, assignments ...clause.Assignment) (int64, error) {
+	// Refuse to update the whole table
+	if len(r.scopes) == 0 {
+		return 0, fmt.Errorf("sqlc: UpdateWhere requires at least one Where scope")
+	}
+	return r.updateWhere(ctx, PolicyUpdate, assignments...)
+}
+
+// updateWhere updates the live records matching the scopes, restricted by the row
+// policy of action (PolicyDelete for soft deletes). Callers check that a scope is set.
+func (r *Repository[T]) updateWhere(ctx context.Context, action PolicyAction, assignments ...clause.Assignment) (int64, error) {
+	// Empty assignment fast return
+	if len(assignments) == 0 {
+		return 0, nil
+	}
+	if err := r.validateAssignments(assignments); err != nil {
+		return 0, err
+	}
+
+	// Build UPDATE statement with scopes
+	builder := sq.Update(r.schema.TableName())
+	scopes, err := r.policyScopes(ctx, action)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
+	}
+
+	// Skip soft-deleted records
+	if sdCol := r.schema.SoftDeleteColumn(); sdCol != "" && !r.unscoped {
+		builder = builder.Where(sq.Eq{sdCol: softDeleteActive(r.schema)})
+	}
+
+	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
+
+	// Add column assignments
+	for _, assignment := range assignments {
+		builder = builder.Set(assignment.Column.ColumnName(), assignmentValue(assignment.Value))
+	}
+
+	// Generate and execute SQL
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := r.session.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Increment atomically adds delta to a numeric column of the record with the given id
+// (column = column + delta), avoiding read-modify-write races between concurrent updates.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - id: Primary key value
+//   - column: Numeric column to increment (e.g. generated.Post.ViewCount)
+//   - delta: Amount to add; negative values decrement
+//
+// Returns:
+//   - error: Update error
+//
+// Example:
+//
+//	err := postRepo.Increment(ctx, postID, generated.Post.ViewCount, 1)
+//
+//	// Equivalent field-level assignment, combinable with other columns
+//	err := postRepo.UpdateColumns(ctx, postID,
+//	    generated.Post.ViewCount.Incr(1),
+//	    generated.Post.UpdatedAt.Set(time.Now()),
+//	)
+//
+// Note:
+//   - Scopes apply as in UpdateColumns; does not trigger lifecycle hooks
+//   - The in-memory model is not updated; reload it to read the new value
+func (r *Repository[T]) Increment(ctx context.Context, id any, column clause.Columnar, delta any) error {
+	return r.UpdateColumns(ctx, id, clause.Incr(clause.Column{Name: column.ColumnName()}, delta))
+}
+
+// Decrement atomically subtracts delta from a numeric column of the record with the given id
+// (column = column - delta). See Increment.
+//
+// Example:
+//
+//	err := productRepo.Decrement(ctx, productID, generated.Product.Stock, 1)
+func (r *Repository[T]) Decrement(ctx context.Context, id any, column clause.Columnar, delta any) error {
+	return r.UpdateColumns(ctx, id, clause.Decr(clause.Column{Name: column.ColumnName()}, delta))
+}
+
+// Touch sets one or more timestamp columns of the record with the given id to the
+// database's current time (NOW() / CURRENT_TIMESTAMP), without loading the model.
+// Useful for heartbeats and cache invalidation markers.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - id: Primary key value
+//   - columns: Timestamp columns to set (e.g. generated.User.UpdatedAt)
+//
+// Returns:
+//   - error: Update error, or an error if no column is given
+//
+// Example:
+//
+//	// UPDATE users SET updated_at = NOW() WHERE id = ?
+//	err := userRepo.Touch(ctx, userID, generated.User.UpdatedAt)
+//
+//	err := sessionRepo.Touch(ctx, sessionID, generated.Session.LastSeenAt, generated.Session.UpdatedAt)
+//
+// Note:
+//   - The time comes from the database clock, not the application's
+//   - SQLite stores CURRENT_TIMESTAMP as UTC text ("YYYY-MM-DD HH:MM:SS")
+//   - Scopes apply as in UpdateColumns; does not trigger lifecycle hooks
+func (r *Repository[T]) Touch(ctx context.Context, id any, columns ...clause.Columnar) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("sqlc: Touch requires at least one column")
+	}
+	now := clause.BindTypes(funcs.Now(), typeNamer(r.session.dialect))
+	assignments := make([]clause.Assignment, len(columns))
+	for i, col := range columns {
+		assignments[i] = clause.Assignment{Column: clause.Column{Name: col.ColumnName()}, Value: now}
+	}
+	return r.UpdateColumns(ctx, id, assignments...)
 }
 
 // Delete deletes a record by primary key.
@@ -659,12 +1595,27 @@ func (r *Repository[T]) UpdateColumns(ctx context.Context, id any, assignments .
 //	    return err
 //	}
 func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	_, err := r.DeleteResult(ctx, id)
+	return err
+}
+
+// DeleteResult is like Delete but also returns the number of affected rows.
+// Zero means no record matched the id and scopes, e.g. it was already deleted;
+// idempotent handlers can use it to tell a repeated request from the first one.
+//
+// Example:
+//
+//	n, err := orderRepo.DeleteResult(ctx, orderID)
+//	if err == nil && n == 0 {
+//	    return ErrNotFound
+//	}
+func (r *Repository[T]) DeleteResult(ctx context.Context, id any) (int64, error) {
 	// Check if model supports soft delete and we are not in unscoped mode
 	sdCol := r.schema.SoftDeleteColumn()
 	if sdCol != "" && !r.unscoped {
-		// Perform soft delete
+		// Perform soft delete; records already in the trash keep their deletion marker
 		sdVal := r.schema.SoftDeleteValue()
-		return r.UpdateColumns(ctx, id, clause.Assignment{
+		return r.Where(softDeleteActiveCond(r.schema)).updateColumns(ctx, PolicyDelete, id, clause.Assignment{
 			Column: clause.Column{Name: sdCol},
 			Value:  sdVal,
 		})
@@ -677,9 +1628,13 @@ func (r *Repository[T]) Delete(ctx context.Context, id any) error {
 	builder := sq.Delete(r.schema.TableName()).
 		Where(sq.Eq{pkMeta.Column.Name: id})
 
-	// Apply Scopes
-	for _, scope := range r.scopes {
-		builder = builder.Where(scope)
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyDelete)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
 	}
 
 	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
@@ -687,11 +1642,14 @@ func (r *Repository[T]) Delete(ctx context.Context, id any) error {
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = r.session.Exec(ctx, query, args...)
-	return err
+	res, err := r.session.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 // DeleteModel deletes a record by model instance, triggering lifecycle hooks.
@@ -724,9 +1682,19 @@ func (r *Repository[T]) Delete(ctx context.Context, id any) error {
 //	    return err
 //	}
 func (r *Repository[T]) DeleteModel(ctx context.Context, model *T) error {
+	_, err := r.DeleteModelResult(ctx, model)
+	return err
+}
+
+// DeleteModelResult is like DeleteModel but also returns the number of affected rows.
+// Zero means no record matched the model's primary key and scopes.
+//
+// Note:
+//   - Hooks run as in DeleteModel, even if no row was affected
+func (r *Repository[T]) DeleteModelResult(ctx context.Context, model *T) (int64, error) {
 	// Trigger BeforeDelete hook
-	if err := triggerBeforeDelete(ctx, model); err != nil {
-		return err
+	if err := triggerBeforeDelete(r.hookContext(ctx), model); err != nil {
+		return 0, err
 	}
 
 	// Check if model supports soft delete and we are not in unscoped mode
@@ -740,29 +1708,41 @@ func (r *Repository[T]) DeleteModel(ctx context.Context, model *T) error {
 		builder := sq.Update(r.schema.TableName()).
 			Set(sdCol, sdVal).
 			Where(sq.Eq{pk.Column.Name: pk.Value}).
+			Where(sq.Eq{sdCol: softDeleteActive(r.schema)}). // Keep the marker of trashed records
 			PlaceholderFormat(r.session.dialect.PlaceholderFormat())
 
-		// Apply Scopes
-		for _, scope := range r.scopes {
-			builder = builder.Where(scope)
+		// Apply Scopes and the row policy
+		scopes, err := r.policyScopes(ctx, PolicyDelete)
+		if err != nil {
+			return 0, err
+		}
+		for _, scope := range scopes {
+			builder = builder.Where(scopeSqlizer{scope})
 		}
 
 		// Generate and execute SQL
 		query, args, err := builder.ToSql()
 		if err != nil {
-			return err
+			return 0, err
 		}
 
-		_, err = r.session.Exec(ctx, query, args...)
+		res, err := r.session.Exec(ctx, query, args...)
 		if err != nil {
-			return err
+			return 0, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
 		}
 
 		// Sync model instance's soft delete field
 		r.schema.SetDeletedAt(model)
+		if err := indexModels(ctx, r.session, ChangeDelete, r.schema.TableName(), model); err != nil {
+			return affected, err
+		}
 
 		// Trigger AfterDelete hook
-		return triggerAfterDelete(ctx, model)
+		return affected, triggerAfterDelete(r.hookContext(ctx), model)
 	}
 
 	// Extract primary key from model
@@ -772,9 +1752,13 @@ func (r *Repository[T]) DeleteModel(ctx context.Context, model *T) error {
 	builder := sq.Delete(r.schema.TableName()).
 		Where(sq.Eq{pk.Column.Name: pk.Value})
 
-	// Apply Scopes
-	for _, scope := range r.scopes {
-		builder = builder.Where(scope)
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyDelete)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
 	}
 
 	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
@@ -782,16 +1766,85 @@ func (r *Repository[T]) DeleteModel(ctx context.Context, model *T) error {
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = r.session.Exec(ctx, query, args...)
+	res, err := r.session.Exec(ctx, query, args...)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := indexModels(ctx, r.session, ChangeDelete, r.schema.TableName(), model); err != nil {
+		return affected, err
 	}
 
 	// Trigger AfterDelete hook
-	return triggerAfterDelete(ctx, model)
+	return affected, triggerAfterDelete(r.hookContext(ctx), model)
+}
+
+// DeleteWhere deletes all records matching the repository's scopes in a single
+// statement and returns the number of affected rows.
+// Soft-delete models are soft-deleted (UPDATE ... SET deleted_at) unless the repository is Unscoped().
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//
+// Returns:
+//   - int64: Number of deleted rows
+//   - error: Delete error
+//
+// Note:
+//   - At least one scope (Where) is required, so a missing condition cannot delete the whole table
+//   - Already soft-deleted records are not counted again; Unscoped() permanently deletes them too
+//   - Does not trigger lifecycle hooks (no model instances)
+//
+// Example:
+//
+//	// Purge expired sessions
+//	purged, err := sessionRepo.
+//	    Where(generated.Session.ExpiresAt.Lt(time.Now())).
+//	    Unscoped().
+//	    DeleteWhere(ctx)
+func (r *Repository[T]) DeleteWhere(ctx context.Context) (int64, error) {
+	// Refuse to delete the whole table
+	if len(r.scopes) == 0 {
+		return 0, fmt.Errorf("sqlc: DeleteWhere requires at least one Where scope")
+	}
+
+	// Check if model supports soft delete and we are not in unscoped mode
+	if sdCol := r.schema.SoftDeleteColumn(); sdCol != "" && !r.unscoped {
+		return r.updateWhere(ctx, PolicyDelete, clause.Assignment{
+			Column: clause.Column{Name: sdCol},
+			Value:  r.schema.SoftDeleteValue(),
+		})
+	}
+
+	// Build DELETE statement with scopes
+	builder := sq.Delete(r.schema.TableName())
+	scopes, err := r.policyScopes(ctx, PolicyDelete)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
+	}
+
+	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
+
+	// Generate and execute SQL
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := r.session.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 // Query returns a QueryBuilder for building complex queries.
@@ -801,7 +1854,8 @@ func (r *Repository[T]) DeleteModel(ctx context.Context, model *T) error {
 //   - *QueryBuilder[T]: Query builder
 //
 // Query features:
-//   - Automatically applies soft delete filter (if model supports)
+//   - Applies the repository's scopes (Where); they bind to every OrWhere branch
+//   - Automatically applies soft delete filter (if model supports), unless the repository is Unscoped()
 //   - Supports conditions, sorting, pagination, aggregation
 //   - Supports relation preloading
 //   - Supports subqueries
@@ -828,7 +1882,20 @@ func (r *Repository[T]) DeleteModel(ctx context.Context, model *T) error {
 //	    Where(generated.User.Status.Eq("active")).
 //	    Count(ctx)
 func (r *Repository[T]) Query() *QueryBuilder[T] {
-	return Query[T](r.session)
+	q := newQueryBuilder(r.session, r.schema)
+
+	// Scopes go on the base builder, outside the condition list, so OrWhere cannot bypass them
+	for _, scope := range r.scopes {
+		sql, args, err := clause.BindTypes(scope, typeNamer(r.session.dialect)).Build()
+		if err != nil {
+			q.err = err
+			return q
+		}
+		// Parenthesized like scopeSqlizer, so raw expressions containing OR keep their precedence
+		q.builder = q.builder.Where(sq.Expr("("+sql+")", args...))
+	}
+	q.withTrashed = r.unscoped
+	return q
 }
 
 // FindOne queries a single record by primary key.
@@ -862,12 +1929,124 @@ func (r *Repository[T]) FindOne(ctx context.Context, id any) (*T, error) {
 	// Get primary key metadata
 	pkMeta := r.schema.PK(nil)
 	query := r.Query().Where(clause.Eq{Column: pkMeta.Column, Value: id})
+	return query.First(ctx)
+}
 
-	// Apply Scopes to Query
-	for _, scope := range r.scopes {
-		query = query.Where(scope)
+// FindMany queries the records with the given primary keys in a single WHERE pk IN (...)
+// query and returns them in the order of ids.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - ids: Primary key values
+//
+// Returns:
+//   - []*T: Found records ordered like ids; missing ids are skipped, duplicates returned once
+//   - error: Query error
+//
+// Example:
+//
+//	// Hydrate search hits ranked by an external index
+//	users, err := userRepo.FindMany(ctx, hitIDs...)
+//
+// Note:
+//   - Automatically applies soft delete filter and scope conditions, like FindOne
+//   - Keys are matched by value, so int IDs find int64 primary keys
+//   - Long id lists are split into several queries under the dialect's placeholder limit
+func (r *Repository[T]) FindMany(ctx context.Context, ids ...any) ([]*T, error) {
+	results := make([]*T, 0, len(ids))
+	if len(ids) == 0 {
+		return results, nil
 	}
-	return query.First(ctx)
+
+	// Leave half of the placeholder limit for scope arguments
+	size := len(ids)
+	if limit := maxPlaceholders(r.session.dialect); limit > 0 {
+		size = min(size, limit/2)
+	}
+
+	pk := r.schema.PK(nil).Column
+	byKey := make(map[any]*T, len(ids))
+	for chunk := range slices.Chunk(ids, size) {
+		models, err := r.Query().Where(clause.IN{Column: pk, Values: chunk}).Unordered().Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			byKey[pkKey(r.schema.PK(m).Value)] = m
+		}
+	}
+
+	// Restore input order
+	for _, id := range ids {
+		key := pkKey(id)
+		if m, ok := byKey[key]; ok {
+			results = append(results, m)
+			delete(byKey, key) // Duplicated ids yield the record once
+		}
+	}
+	return results, nil
+}
+
+// pkKey normalizes a primary key value for map lookups: integers of any width and
+// signedness compare equal, as do strings and byte slices.
+func pkKey(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint())
+	case reflect.String:
+		return rv.String()
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes())
+		}
+	}
+	if rv.IsValid() && rv.Comparable() {
+		return rv.Interface()
+	}
+	return fmt.Sprint(v)
+}
+
+// FindOneForUpdate queries a single record by primary key and locks it for update
+// (SELECT ... FOR UPDATE) until the transaction ends, so read-modify-write flows
+// such as transfers cannot interleave with concurrent writers.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - id: Record's primary key value
+//
+// Returns:
+//   - *T: Found and locked model instance
+//   - error: Query error (ErrNotFound indicates not found; sql.ErrTxDone outside a transaction)
+//
+// Example:
+//
+//	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+//	    accounts := accountRepo.WithSession(tx)
+//	    from, err := accounts.FindOneForUpdate(ctx, fromID)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    from.Balance -= amount
+//	    return accounts.Update(ctx, from)
+//	})
+//
+// Note:
+//   - Must be called on a repository bound to a transaction session; outside a
+//     transaction the lock would be released immediately
+//   - Scopes and the soft delete filter apply as in FindOne
+//   - SQLite ignores the locking clause (writes are serialized per database)
+func (r *Repository[T]) FindOneForUpdate(ctx context.Context, id any) (*T, error) {
+	if r.session.tx == nil {
+		return nil, fmt.Errorf("sqlc: FindOneForUpdate requires a transaction session: %w", sql.ErrTxDone)
+	}
+	pkMeta := r.schema.PK(nil)
+	return r.Query().Where(clause.Eq{Column: pkMeta.Column, Value: id}).ForUpdate().First(ctx)
 }
 
 // Restore restores a soft-deleted record by clearing the soft delete marker.
@@ -906,14 +2085,266 @@ func (r *Repository[T]) Restore(ctx context.Context, id any) error {
 
 	// Build UPDATE statement, clear soft delete marker
 	builder := sq.Update(r.schema.TableName()).
-		Set(sdCol, nil).
+		Set(sdCol, softDeleteActive(r.schema)).
 		Where(sq.Eq{pkMeta.Column.Name: id}).
 		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
 
-	// Apply Scopes
-	for _, scope := range r.scopes {
-		builder = builder.Where(scope)
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyUpdate)
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
+	}
+
+	// Generate and execute SQL
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.session.Exec(ctx, query, args...)
+	return err
+}
+
+// RestoreMany restores multiple soft-deleted records in a single UPDATE statement.
+// Only rows that are currently soft-deleted are touched.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - ids: Primary key values of records to restore
+//
+// Returns:
+//   - error: Restore error, returns error if model doesn't support soft delete
+//
+// Note:
+//   - Empty ids will immediately return nil (no-op)
+//   - Scope conditions will be combined with primary key condition
+//
+// Example:
+//
+//	// Restore records selected in a trash-bin UI
+//	if err := userRepo.RestoreMany(ctx, 1, 2, 3); err != nil {
+//	    return err
+//	}
+func (r *Repository[T]) RestoreMany(ctx context.Context, ids ...any) error {
+	sdCol := r.schema.SoftDeleteColumn()
+	if sdCol == "" {
+		return fmt.Errorf("sqlc: model does not support soft delete")
+	}
+
+	// Empty ids fast return
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Get primary key metadata
+	pkMeta := r.schema.PK(nil)
+
+	// Build UPDATE statement, clear soft delete marker on trashed rows only
+	builder := sq.Update(r.schema.TableName()).
+		Set(sdCol, softDeleteActive(r.schema)).
+		Where(sq.Eq{pkMeta.Column.Name: ids}).
+		Where(sq.NotEq{sdCol: softDeleteActive(r.schema)}).
+		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
+
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyUpdate)
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
+	}
+
+	// Generate and execute SQL
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.session.Exec(ctx, query, args...)
+	return err
+}
+
+// RestoreModel restores a soft-deleted record by model instance, triggering lifecycle hooks
+// and clearing the model's soft delete field.
+//
+// Operation flow:
+//  1. Trigger BeforeRestore hook (if model implements BeforeRestoreInterface)
+//  2. Clear the soft delete marker of the record with the model's primary key
+//  3. Clear the model instance's soft delete field (NULL timestamp, false flag)
+//  4. Trigger AfterRestore hook (if model implements AfterRestoreInterface)
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//   - model: Model instance pointer, must contain valid primary key value
+//
+// Returns:
+//   - error: Restore error or hook error, returns error if model doesn't support soft delete
+//
+// Example:
+//
+//	post, err := postRepo.Trashed().Where(generated.Post.ID.Eq(id)).First(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	if err := postRepo.RestoreModel(ctx, post); err != nil {
+//	    return err
+//	}
+//	// post.DeletedAt is nil again
+func (r *Repository[T]) RestoreModel(ctx context.Context, model *T) error {
+	if r.schema.SoftDeleteColumn() == "" {
+		return fmt.Errorf("sqlc: model does not support soft delete")
+	}
+
+	// Trigger BeforeRestore hook
+	if err := triggerBeforeRestore(r.hookContext(ctx), model); err != nil {
+		return err
+	}
+
+	if err := r.Restore(ctx, r.schema.PK(model).Value); err != nil {
+		return err
+	}
+
+	// Sync model instance's soft delete field
+	clearDeletedAt(r.schema, model)
+	if err := indexModels(ctx, r.session, ChangeUpdate, r.schema.TableName(), model); err != nil {
+		return err
+	}
+
+	// Trigger AfterRestore hook
+	return triggerAfterRestore(r.hookContext(ctx), model)
+}
+
+// RestoreWhere restores all soft-deleted records matching the repository's scopes in a
+// single UPDATE statement and returns the number of restored rows.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//
+// Returns:
+//   - int64: Number of restored rows
+//   - error: Restore error, returns error if model doesn't support soft delete
+//
+// Note:
+//   - At least one scope (Where) is required, so a missing condition cannot restore the whole trash
+//   - Only rows that are currently soft-deleted are touched and counted
+//   - Does not trigger lifecycle hooks (no model instances)
+//
+// Example:
+//
+//	// Undo the deletion of a user's posts
+//	restored, err := postRepo.
+//	    Where(generated.Post.UserID.Eq(userID)).
+//	    RestoreWhere(ctx)
+func (r *Repository[T]) RestoreWhere(ctx context.Context) (int64, error) {
+	sdCol := r.schema.SoftDeleteColumn()
+	if sdCol == "" {
+		return 0, fmt.Errorf("sqlc: model does not support soft delete")
+	}
+
+	// Refuse to restore the whole table
+	if len(r.scopes) == 0 {
+		return 0, fmt.Errorf("sqlc: RestoreWhere requires at least one Where scope")
+	}
+
+	// Build UPDATE statement, clear soft delete marker on trashed rows only
+	builder := sq.Update(r.schema.TableName()).
+		Set(sdCol, softDeleteActive(r.schema)).
+		Where(sq.NotEq{sdCol: softDeleteActive(r.schema)}).
+		PlaceholderFormat(r.session.dialect.PlaceholderFormat())
+
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyUpdate)
+	if err != nil {
+		return 0, err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
+	}
+
+	// Generate and execute SQL
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := r.session.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Trashed returns a QueryBuilder that only matches soft-deleted records.
+// This is the starting point for trash-bin listings; pagination, ordering and
+// counting work as with any other query.
+//
+// Returns:
+//   - *QueryBuilder[T]: Query builder pre-configured with OnlyTrashed() and repository scopes
+//
+// Note:
+//   - If the model doesn't support soft delete, the returned builder fails on execution
+//
+// Example:
+//
+//	// Page 2 of the trash bin, most recently deleted first
+//	deleted, err := userRepo.Trashed().
+//	    OrderBy(generated.User.DeletedAt.Desc()).
+//	    Limit(20).
+//	    Offset(20).
+//	    Find(ctx)
+//
+//	total, err := userRepo.Trashed().Count(ctx)
+func (r *Repository[T]) Trashed() *QueryBuilder[T] {
+	query := r.Query()
+	if r.schema.SoftDeleteColumn() == "" {
+		query.err = fmt.Errorf("sqlc: model does not support soft delete")
+		return query
 	}
+	return query.OnlyTrashed()
+}
+
+// EmptyTrash permanently deletes all soft-deleted records.
+// Live records are never touched.
+//
+// Parameters:
+//   - ctx: Context, supports cancellation and timeout
+//
+// Returns:
+//   - error: Deletion error, returns error if model doesn't support soft delete
+//
+// Note:
+//   - This is hard delete, records will be permanently removed
+//   - Scope conditions restrict which trashed records are purged
+//   - Does not trigger lifecycle hooks (no model instances)
+//
+// Example:
+//
+//	// Purge one tenant's trash bin
+//	err := docRepo.Where(generated.Document.TenantID.Eq(tenantID)).EmptyTrash(ctx)
+func (r *Repository[T]) EmptyTrash(ctx context.Context) error {
+	sdCol := r.schema.SoftDeleteColumn()
+	if sdCol == "" {
+		return fmt.Errorf("sqlc: model does not support soft delete")
+	}
+
+	// Build DELETE statement restricted to trashed rows
+	builder := sq.Delete(r.schema.TableName()).
+		Where(sq.NotEq{sdCol: softDeleteActive(r.schema)})
+
+	// Apply Scopes and the row policy
+	scopes, err := r.policyScopes(ctx, PolicyDelete)
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		builder = builder.Where(scopeSqlizer{scope})
+	}
+
+	builder = builder.PlaceholderFormat(r.session.dialect.PlaceholderFormat())
 
 	// Generate and execute SQL
 	query, args, err := builder.ToSql()
@@ -969,11 +2400,6 @@ func (r *Repository[T]) FirstOrCreate(ctx context.Context, defaults *T) (*T, err
 	// Build query
 	query := r.Query()
 
-	// Apply Scopes to Query
-	for _, scope := range r.scopes {
-		query = query.Where(scope)
-	}
-
 	// Try to find record
 	result, err := query.Take(ctx)
 	if err == nil {
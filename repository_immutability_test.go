@@ -1,6 +1,9 @@
 package sqlc_test
 
 import (
+	"context"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/arllen133/sqlc"
@@ -46,3 +49,87 @@ func TestRepositoryImmutability(t *testing.T) {
 		_ = r2
 	})
 }
+
+func TestQueryBuilderImmutability(t *testing.T) {
+	session := setupGenSession()
+	base := sqlc.Query[GenUser](session).Where(GenUserFields.ID.Gt(10))
+	baseSQL, _, _ := base.ToSQL()
+
+	t.Run("BranchesAreIndependent", func(t *testing.T) {
+		byName := base.Where(GenUserFields.Username.Eq("alice")).Select(GenUserFields.ID)
+		paged := base.OrderBy(GenUserFields.ID.Desc()).Limit(5).ForUpdate()
+
+		tests := []struct {
+			name string
+			q    *sqlc.QueryBuilder[GenUser]
+			want string
+		}{
+			{"base", base, "SELECT id, username, email, created_at FROM users WHERE users.id > ?"},
+			{"byName", byName, "SELECT users.id FROM users WHERE users.id > ? AND users.username = ?"},
+			{"paged", paged, "SELECT id, username, email, created_at FROM users WHERE users.id > ? ORDER BY users.id DESC LIMIT 5"},
+		}
+		for _, tt := range tests {
+			got, _, err := tt.q.ToSQL()
+			if err != nil {
+				t.Fatalf("%s: ToSQL() error = %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s:\ngot:  %s\nwant: %s", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		c := base.Clone()
+		if c == base {
+			t.Fatal("Clone() returned the receiver")
+		}
+		c = c.Select(GenUserFields.Email)
+		if got, _, _ := base.ToSQL(); got != baseSQL {
+			t.Errorf("base query changed after modifying clone: %s", got)
+		}
+	})
+
+	t.Run("ConcurrentBranches", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 16 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				q := base.Where(GenUserFields.Username.Eq("user")).Limit(uint64(i + 1))
+				if _, _, err := q.ToSQL(); err != nil {
+					t.Errorf("ToSQL() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		if got, _, _ := base.ToSQL(); got != baseSQL {
+			t.Errorf("base query changed by concurrent branches: %s", got)
+		}
+	})
+}
+
+func TestRepositoryWithSession(t *testing.T) {
+	ctx := context.Background()
+	base := sqlc.NewRepository[SoftDeleteProduct](sqlc.NewSession(nil, sqlc.PostgreSQL)).
+		Where(clause.Eq{Column: clause.Column{Name: "tenant_id"}, Value: 7}).
+		Unscoped()
+
+	dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+	if err := base.WithSession(dry).Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	stmts := dry.Recorder().Statements()
+	if len(stmts) != 1 {
+		t.Fatalf("expected 1 statement on the new session, got %d", len(stmts))
+	}
+	// Unscoped: hard delete; scope condition preserved
+	want := "DELETE FROM products WHERE id = $1 AND (tenant_id = $2)"
+	if stmts[0].SQL != want {
+		t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", stmts[0].SQL, want)
+	}
+	if !reflect.DeepEqual(stmts[0].Args, []any{1, 7}) {
+		t.Errorf("args mismatch: got %v", stmts[0].Args)
+	}
+}
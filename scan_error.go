@@ -0,0 +1,93 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements model context for result scanning errors.
+//
+// database/sql reports a failed scan by column index and name only
+// ("sql: Scan error on column index 4, name \"department_id\": converting NULL to int
+// is unsupported"). Queries of a model wrap such errors in a ScanError naming the table,
+// the column and the Go field it was scanned into, which usually points straight at the
+// schema drift: a column that became nullable, or changed type, without the model.
+//
+// Usage example:
+//
+//	users, err := userRepo.Query().Find(ctx)
+//	var scanErr *sqlc.ScanError
+//	if errors.As(err, &scanErr) {
+//	    log.Printf("fix %s.%s (%s)", scanErr.Model, scanErr.Field, scanErr.FieldType)
+//	}
+//	// sqlc: cannot scan users.department_id into models.User.DepartmentID (int):
+//	//   converting NULL to int is unsupported
+package sqlc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ScanError is a failure to scan a column of a query result into a model.
+type ScanError struct {
+	Model     string // Go model type (e.g. "models.User")
+	Table     string // Table of the model (e.g. "users")
+	Column    string // Result column (e.g. "department_id")
+	Field     string // Go field the column maps to; empty if no field maps it
+	FieldType string // Go type of Field (e.g. "int")
+	Err       error  // Error reported by the driver or the scanner
+}
+
+// Error implements error.
+func (e *ScanError) Error() string {
+	// Drop the "sql: Scan error on column index..." prefix repeated by the fields
+	cause := e.Err.Error()
+	if loc := scanErrorColumn.FindStringSubmatchIndex(cause); loc != nil {
+		if loc[4] >= 0 { // sqlx: missing destination name
+			return fmt.Sprintf("sqlc: cannot scan %s.%s into %s: no field maps the column", e.Table, e.Column, e.Model)
+		}
+		cause = strings.TrimPrefix(cause[loc[1]:], ": ")
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("sqlc: cannot scan %s.%s into %s: %s", e.Table, e.Column, e.Model, cause)
+	}
+	return fmt.Sprintf("sqlc: cannot scan %s.%s into %s.%s (%s): %s", e.Table, e.Column, e.Model, e.Field, e.FieldType, cause)
+}
+
+// Unwrap returns the underlying scan error.
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// scanErrorColumn extracts the column of database/sql and sqlx scan errors
+var scanErrorColumn = regexp.MustCompile(`sql: Scan error on column index \d+, name "([^"]*)"|missing destination name (\S+) in `)
+
+// scanError returns err as a *ScanError of model T if it is a scan error, or nil.
+func (q *QueryBuilder[T]) scanError(err error) *ScanError {
+	m := scanErrorColumn.FindStringSubmatch(err.Error())
+	if m == nil {
+		return nil
+	}
+	typ := reflect.TypeFor[T]()
+	scanErr := &ScanError{Model: typ.String(), Table: q.table, Column: m[1] + m[2], Err: err}
+	if m[1] != "" && q.session.db != nil {
+		if fi := q.session.db.Mapper.TypeMap(typ).GetByPath(scanErr.Column); fi != nil {
+			scanErr.Field = fi.Field.Name
+			scanErr.FieldType = fi.Field.Type.String()
+		}
+	}
+	return scanErr
+}
+
+// queryFailed wraps an error of a query of model T, adding model context to scan errors.
+func (q *QueryBuilder[T]) queryFailed(err error) error {
+	if scanErr := q.scanError(err); scanErr != nil {
+		return scanErr
+	}
+	return fmt.Errorf("sqlc: query failed: %w", err)
+}
+
+// scanFailed wraps an error of scanning a row into model T, adding model context.
+func (q *QueryBuilder[T]) scanFailed(err error) error {
+	if scanErr := q.scanError(err); scanErr != nil {
+		return scanErr
+	}
+	return fmt.Errorf("sqlc: scan failed: %w", err)
+}
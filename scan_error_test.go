@@ -0,0 +1,81 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestScanError(t *testing.T) {
+	ctx := context.Background()
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	repo := sqlc.NewRepository[Member](session)
+
+	// Schema drift: department_id became nullable, Member.DepartmentID is still an int
+	if _, err := db.Exec(`INSERT INTO members (name, email, level, department_id, created_at) VALUES ('alice', 'alice@test.com', 1, NULL, CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	nullDept := &sqlc.ScanError{Model: "sqlc_test.Member", Table: "members", Column: "department_id", Field: "DepartmentID", FieldType: "int"}
+	tests := []struct {
+		name string
+		run  func() error
+		want *sqlc.ScanError
+	}{
+		{"Find", func() error {
+			_, err := repo.Query().Find(ctx)
+			return err
+		}, nullDept},
+		{"FindOne", func() error {
+			_, err := repo.FindOne(ctx, 1)
+			return err
+		}, nullDept},
+		{"Chunk", func() error {
+			return repo.Query().Chunk(ctx, 10, func([]*Member) error { return nil })
+		}, nullDept},
+		{"Rows", func() error {
+			for _, err := range repo.Query().Rows(ctx) {
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nullDept},
+		{"UnmappedColumn", func() error {
+			_, err := repo.Query().Select(clause.Column{Name: "id"}).SelectExpr(clause.Expr{SQL: "1 AS extra"}).Find(ctx)
+			return err
+		}, &sqlc.ScanError{Model: "sqlc_test.Member", Table: "members", Column: "extra"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			var scanErr *sqlc.ScanError
+			if !errors.As(err, &scanErr) {
+				t.Fatalf("expected *ScanError, got %v", err)
+			}
+			got := *scanErr
+			got.Err = nil
+			if got != *tt.want {
+				t.Errorf("ScanError = %+v, want %+v", got, *tt.want)
+			}
+			if scanErr.Err == nil || errors.Unwrap(scanErr) != scanErr.Err {
+				t.Errorf("expected the underlying error to be wrapped, got %v", scanErr.Err)
+			}
+		})
+	}
+
+	_, err := repo.Query().Find(ctx)
+	want := "sqlc: cannot scan members.department_id into sqlc_test.Member.DepartmentID (int): converting NULL to int is unsupported"
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %v\nwant %s", err, want)
+	}
+	_, err = repo.Query().Select(clause.Column{Name: "id"}).SelectExpr(clause.Expr{SQL: "1 AS extra"}).Find(ctx)
+	if err == nil || !strings.HasSuffix(err.Error(), "extra into sqlc_test.Member: no field maps the column") {
+		t.Errorf("Error() = %v, want no field message", err)
+	}
+}
@@ -214,6 +214,24 @@ type Schema[T any] interface {
 	SetDeletedAt(m *T)
 }
 
+// DefaultOrderer is optionally implemented by schemas to give queries of the model a
+// default ORDER BY. It applies to row-returning queries without OrderBy/OrderByExpr,
+// so paginated results stay deterministic when callers forget to sort.
+//
+// Example:
+//
+//	func (s PostSchema) DefaultOrder() []clause.OrderByColumn {
+//	    return []clause.OrderByColumn{{Column: clause.Column{Name: "created_at"}, Desc: true}}
+//	}
+//
+// Note:
+//   - Generated schemas implement it from the DefaultOrder config option
+//   - Opt out per query with QueryBuilder.Unordered()
+type DefaultOrderer interface {
+	// DefaultOrder returns the sort columns applied to queries without explicit ordering.
+	DefaultOrder() []clause.OrderByColumn
+}
+
 // schemas is the global Schema registry.
 // Uses reflect.Type as key to support any model type.
 // Thread safety: All registrations should be completed during program initialization, after which it's read-only.
@@ -243,6 +261,7 @@ func RegisterSchema[T any](schema Schema[T]) {
 	var t T
 	typ := reflect.TypeOf(t)
 	schemas[typ] = schema
+	registerFixtureTable(schema)
 }
 
 // LoadSchema loads the registered Schema for a model.
@@ -276,5 +295,39 @@ func LoadSchema[T any]() Schema[T] {
 	panic(fmt.Sprintf("sqlc: schema not registered for type %v", typ))
 }
 
+// QualifiedSelectColumns returns the select columns of T's registered schema,
+// each prefixed with alias (or the table name if alias is empty).
+// Useful for explicit column lists in joins and raw SQL instead of SELECT *.
+//
+// Type parameter:
+//   - T: Model type (must be registered)
+//
+// Parameters:
+//   - alias: Table alias used in the query, or "" for the table name
+//
+// Returns:
+//   - []string: Qualified column names (e.g., ["u.id", "u.email"])
+//
+// Example:
+//
+//	cols := sqlc.QualifiedSelectColumns[models.User]("u")
+//	query := "SELECT " + strings.Join(cols, ", ") + " FROM users u JOIN orders o ON o.user_id = u.id"
+func QualifiedSelectColumns[T any](alias string) []string {
+	schema := LoadSchema[T]()
+	if alias == "" {
+		alias = schema.TableName()
+	}
+	return qualifyColumns(schema.SelectColumns(), alias)
+}
+
+// qualifyColumns prefixes every column with table.
+func qualifyColumns(cols []string, table string) []string {
+	qualified := make([]string, len(cols))
+	for i, col := range cols {
+		qualified[i] = table + "." + col
+	}
+	return qualified
+}
+
 // ScanRows was removed as part of sqlx refactor.
 // Now directly use sqlx's SelectContext and GetContext methods.
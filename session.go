@@ -6,11 +6,19 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/sync/singleflight"
 )
 
 // Executor defines the common database operations for both DB and Tx.
@@ -61,10 +69,32 @@ type Executor interface {
 //	    return nil // Auto commit
 //	})
 type Session struct {
-	db       *sqlx.DB             // Underlying database connection for starting transactions
-	executor Executor             // Current executor (DB or Tx)
-	dialect  Dialect              // Database dialect for handling SQL differences
-	obs      *ObservabilityConfig // Observability configuration (logging, tracing, metrics)
+	db       *sqlx.DB                             // Underlying database connection for starting transactions
+	executor Executor                             // Current executor (DB or Tx)
+	dialect  Dialect                              // Database dialect for handling SQL differences
+	obs      *atomic.Pointer[ObservabilityConfig] // Observability configuration (logging, tracing, metrics), shared with derived sessions
+	guards   []GuardRule                          // SQL guard rules checked before execution
+
+	recoverPanics bool                // Transaction converts callback panics into *PanicError
+	strict        bool                // Validate placeholders and warn about inline literals (WithStrictSQL)
+	tx            *txState            // Transaction-scoped state (nil outside transactions)
+	dedup         *singleflight.Group // Shares concurrent identical SELECTs (nil when disabled)
+
+	columnCheck func(ctx context.Context, drift *ColumnDrift) error // Model/schema drift callback (nil when disabled)
+	recorder    *Recorder                                           // Statement recorder of dry-run sessions (nil otherwise)
+	captured    *queryRing                                          // Recently executed statements (nil when disabled)
+	indexer     *Indexer                                            // Search index maintenance (nil when disabled)
+	maxPerPage  int                                                 // Largest page size accepted by Page (0: DefaultMaxPerPage)
+	replicas    *replicaSet                                         // Read replicas (nil when none)
+	primary     *Session                                            // Session a replica read falls back to (nil unless reading from a replica)
+	policies    *Policies                                           // Row-level policies (nil when disabled)
+}
+
+// txState holds state shared by all users of one transaction session.
+type txState struct {
+	mu           sync.Mutex                    // Guards beforeCommit and indexEvents
+	beforeCommit []func(context.Context) error // Hooks run right before COMMIT
+	indexEvents  []IndexEvent                  // Changes delivered to the indexer after COMMIT
 }
 
 // NewSession creates a new database session.
@@ -103,8 +133,9 @@ func NewSession(db *sql.DB, dialect Dialect, opts ...SessionOption) *Session {
 		db:       xdb,
 		executor: xdb, // Default to DB as executor
 		dialect:  dialect,
-		obs:      defaultObservabilityConfig(),
+		obs:      new(atomic.Pointer[ObservabilityConfig]),
 	}
+	s.obs.Store(defaultObservabilityConfig())
 
 	// Apply all optional configurations
 	for _, opt := range opts {
@@ -114,17 +145,25 @@ func NewSession(db *sql.DB, dialect Dialect, opts ...SessionOption) *Session {
 	return s
 }
 
+// Dialect returns the SQL dialect of the session.
+// Useful for helpers that emit dialect-specific SQL such as DDL.
+func (s *Session) Dialect() Dialect {
+	return s.dialect
+}
+
 // instrument wraps a database operation with observability.
 // This is an internal method that provides for each database operation:
 //   - OpenTelemetry tracing (span creation, error recording)
 //   - Structured logging (query statement, execution time, error info)
 //   - Performance metrics (operation count, latency distribution, error rate)
+//   - SQL guard checks (rejected statements are never executed)
 //
 // Parameters:
 //   - ctx: Context for propagating trace information and cancellation signals
 //   - spanName: Trace span name (e.g., "sqlc.Query")
 //   - operation: Operation type for logging and metrics (e.g., "select", "exec")
 //   - query: SQL query statement
+//   - args: Query arguments, kept by query capture (WithQueryCapture)
 //   - fn: Actual database operation function
 //
 // Returns:
@@ -132,16 +171,26 @@ func NewSession(db *sql.DB, dialect Dialect, opts ...SessionOption) *Session {
 //
 // This method ensures all database operations have consistent observability,
 // making it easy to monitor and debug in production environments.
-func (s *Session) instrument(ctx context.Context, spanName, operation, query string, fn func() error) error {
+func (s *Session) instrument(ctx context.Context, spanName, operation, query string, args []any, fn func() error) error {
 	// Start trace span
 	ctx, span := s.startSpan(ctx, spanName)
 	defer span.End()
 
-	// Record start time
+	// Record start time and the context budget left
 	start := time.Now()
+	deadline, hasDeadline := ctx.Deadline()
 
-	// Execute actual database operation
-	err := fn()
+	// Run guard rules and strict checks, then execute actual database operation
+	err := s.checkGuards(ctx, query)
+	if err == nil {
+		err = s.checkStrict(ctx, query, args)
+	}
+	if err == nil {
+		err = fn()
+	}
+	if err == nil && s.tx == nil && s.primary == nil && isWrite(query) {
+		s.noteWrite(ctx)
+	}
 
 	// Calculate execution duration
 	duration := time.Since(start)
@@ -160,6 +209,11 @@ func (s *Session) instrument(ctx context.Context, spanName, operation, query str
 
 	// Record metrics
 	s.recordMetrics(ctx, operation, duration, err)
+	s.recordContextMetrics(ctx, operation, query, deadline.Sub(start), hasDeadline, err)
+
+	// Capture statement and count it in the request statistics
+	s.capture(ctx, operation, query, args, start, duration, err)
+	s.recordRequestStats(ctx, query, duration, err)
 
 	return err
 }
@@ -193,14 +247,27 @@ func (s *Session) instrument(ctx context.Context, spanName, operation, query str
 //	}
 func (s *Session) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	var rows *sql.Rows
-	err := s.instrument(ctx, "sqlc.Query", "query", query, func() error {
+	err := s.instrument(ctx, "sqlc.Query", "query", query, args, func() error {
 		var e error
 		rows, e = s.executor.QueryContext(ctx, query, args...)
 		return e
 	})
+	if s.retryOnPrimary(ctx, err) {
+		return s.primary.Query(ctx, query, args...)
+	}
 	return rows, err
 }
 
+// queryx executes a query like Query and wraps the cursor for struct scanning.
+// Column mapping uses the same mapper as Select, so rows.StructScan matches Find.
+func (s *Session) queryx(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	rows, err := s.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlx.Rows{Rows: rows, Mapper: s.db.Mapper}, nil
+}
+
 // QueryRow executes a SQL query expecting at most one row.
 // The returned *sql.Row needs to call Scan() method to retrieve data.
 //
@@ -208,6 +275,10 @@ func (s *Session) Query(ctx context.Context, query string, args ...any) (*sql.Ro
 // provide complete observability (execution duration, error statistics).
 // For complete observability, use the Get() method instead.
 //
+// If a guard rule rejects the query, it is not sent to the database; the rejection
+// is recorded on the span and logged, and Scan() returns the rejection error
+// (errors.Is(err, ErrStatementRejected) for guard rules).
+//
 // Parameters:
 //   - ctx: Context supporting cancellation and timeout
 //   - query: SQL query statement (using placeholders)
@@ -230,16 +301,64 @@ func (s *Session) QueryRow(ctx context.Context, query string, args ...any) *sql.
 	span.SetAttributes(attribute.String("db.statement", query))
 
 	// Log query (without duration/error since execution is deferred to Scan())
-	if s.obs.Logger != nil && s.obs.LogQueries {
-		s.obs.Logger.DebugContext(ctx, "query row",
+	if obs := s.observability(); obs.Logger != nil && obs.LogQueries && obs.sampled() {
+		obs.Logger.DebugContext(ctx, "query row",
 			"operation", "query_row",
 			"query", query,
 		)
 	}
 
+	// Capture with zero duration, since execution is deferred to Scan()
+	start := time.Now()
+
+	// Run guard rules and strict checks; *sql.Row cannot carry our error, so run against a canceled context
+	err := s.checkGuards(ctx, query)
+	if err == nil {
+		err = s.checkStrict(ctx, query, args)
+	}
+	if err != nil {
+		s.capture(ctx, "query_row", query, args, start, 0, err)
+		s.recordRequestStats(ctx, query, 0, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if logger := s.observability().Logger; logger != nil {
+			logger.ErrorContext(ctx, "query rejected",
+				"operation", "query_row",
+				"query", query,
+				"error", err,
+			)
+		}
+		return failedRow(err)
+	}
+
+	s.capture(ctx, "query_row", query, args, start, 0, nil)
+	s.recordRequestStats(ctx, query, 0, nil)
+	if s.tx == nil && isWrite(query) {
+		s.noteWrite(ctx)
+	}
 	return s.executor.QueryRowContext(ctx, query, args...)
 }
 
+// failedRow returns a *sql.Row whose Scan returns err.
+// *sql.Row cannot be built directly; a database whose connections fail with err yields one.
+func failedRow(err error) *sql.Row {
+	db := sql.OpenDB(errConnector{err: err})
+	defer db.Close()
+	return db.QueryRowContext(context.Background(), "")
+}
+
+// errConnector is a driver.Connector whose connections always fail with err.
+type errConnector struct {
+	err error
+}
+
+func (c errConnector) Connect(context.Context) (driver.Conn, error) { return nil, c.err }
+func (c errConnector) Driver() driver.Driver                        { return errDriver(c) }
+
+type errDriver errConnector
+
+func (d errDriver) Open(string) (driver.Conn, error) { return nil, d.err }
+
 // Exec executes a SQL statement that doesn't return rows (INSERT/UPDATE/DELETE).
 // Suitable for data modification operations.
 //
@@ -264,7 +383,7 @@ func (s *Session) QueryRow(ctx context.Context, query string, args ...any) *sql.
 //	rowsAffected, _ := result.RowsAffected()
 func (s *Session) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	var result sql.Result
-	err := s.instrument(ctx, "sqlc.Exec", "exec", query, func() error {
+	err := s.instrument(ctx, "sqlc.Exec", "exec", query, args, func() error {
 		var e error
 		result, e = s.executor.ExecContext(ctx, query, args...)
 		return e
@@ -292,9 +411,14 @@ func (s *Session) Exec(ctx context.Context, query string, args ...any) (sql.Resu
 //	    18,
 //	)
 func (s *Session) Select(ctx context.Context, dest any, query string, args ...any) error {
-	return s.instrument(ctx, "sqlc.Select", "select", query, func() error {
+	err := s.instrument(ctx, "sqlc.Select", "select", query, args, func() error {
 		return s.executor.SelectContext(ctx, dest, query, args...)
 	})
+	if s.retryOnPrimary(ctx, err) {
+		resetDest(dest)
+		return s.primary.Select(ctx, dest, query, args...)
+	}
+	return err
 }
 
 // Get executes a query and scans a single row result into a struct.
@@ -320,9 +444,14 @@ func (s *Session) Select(ctx context.Context, dest any, query string, args ...an
 //	    // User not found
 //	}
 func (s *Session) Get(ctx context.Context, dest any, query string, args ...any) error {
-	return s.instrument(ctx, "sqlc.Get", "get", query, func() error {
+	err := s.instrument(ctx, "sqlc.Get", "get", query, args, func() error {
 		return s.executor.GetContext(ctx, dest, query, args...)
 	})
+	if s.retryOnPrimary(ctx, err) {
+		resetDest(dest)
+		return s.primary.Get(ctx, dest, query, args...)
+	}
+	return err
 }
 
 // Begin starts a new transaction.
@@ -352,12 +481,42 @@ func (s *Session) Get(ctx context.Context, dest any, query string, args ...any)
 //	    return err
 //	}
 func (s *Session) Begin(ctx context.Context) (*Session, error) {
+	return s.BeginTx(ctx, nil)
+}
+
+// BeginTx starts a new transaction with the given options, like Begin.
+// A nil opts uses the driver defaults, as Begin does.
+//
+// Parameters:
+//   - ctx: Context supporting cancellation and timeout
+//   - opts: Isolation level and read-only mode of the transaction
+//
+// Returns:
+//   - *Session: New session instance bound to the transaction
+//   - error: Error starting transaction (e.g. isolation level not supported by the driver)
+//
+// Example:
+//
+//	txSession, err := session.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+func (s *Session) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Session, error) {
+	if s.recorder != nil {
+		return s.beginDryRun(), nil
+	}
+
 	// Start trace span
-	ctx, span := s.startSpan(ctx, "sqlc.Begin")
+	spanCtx, span := s.startSpan(ctx, "sqlc.Begin")
 	defer span.End()
+	if opts != nil {
+		span.SetAttributes(
+			attribute.String("db.transaction.isolation", opts.Isolation.String()),
+			attribute.Bool("db.transaction.read_only", opts.ReadOnly),
+		)
+	}
 
 	// Begin transaction
-	tx, err := s.db.BeginTxx(ctx, nil)
+	start := time.Now()
+	tx, err := s.db.BeginTxx(spanCtx, opts)
+	s.capture(ctx, "begin", "BEGIN", nil, start, time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -371,33 +530,149 @@ func (s *Session) Begin(ctx context.Context) (*Session, error) {
 		executor: tx,        // Use transaction as executor
 		dialect:  s.dialect, // Inherit dialect configuration
 		obs:      s.obs,     // Inherit observability configuration
+		guards:   s.guards,  // Inherit SQL guard rules
+
+		recoverPanics: s.recoverPanics,
+		strict:        s.strict,
+		tx:            &txState{},
+		columnCheck:   s.columnCheck,
+		captured:      s.captured,
+		indexer:       s.indexer,
+		maxPerPage:    s.maxPerPage,
+		replicas:      s.replicas,
+		policies:      s.policies,
 	}, nil
 }
 
 // Commit commits the current transaction.
+// It is CommitContext with context.Background(); prefer CommitContext to pass the
+// request context to BeforeCommit hooks, logging and the indexer.
+//
+// Returns:
+//   - error: Hook or commit error, returns sql.ErrTxDone if not in a transaction
+func (s *Session) Commit() error {
+	return s.CommitContext(context.Background())
+}
+
+// CommitContext commits the current transaction.
 // Only effective in transaction mode (after calling Begin()).
+// Hooks registered via BeforeCommit() run first with ctx; if any fails, the transaction
+// is rolled back and the hook error is returned. After a successful commit, the
+// changes recorded for the session's Indexer (WithIndexer) are delivered.
+//
+// Parameters:
+//   - ctx: Context passed to BeforeCommit hooks, statement capture and the indexer
 //
 // Returns:
-//   - error: Commit error, returns sql.ErrTxDone if not in a transaction
+//   - error: Hook or commit error, returns sql.ErrTxDone if not in a transaction
 //
 // Example:
 //
 //	txSession, _ := session.Begin(ctx)
 //	// ... execute operations
-//	if err := txSession.Commit(); err != nil {
+//	if err := txSession.CommitContext(ctx); err != nil {
 //	    log.Error("commit failed", "error", err)
 //	}
-func (s *Session) Commit() error {
+func (s *Session) CommitContext(ctx context.Context) error {
+	if s.recorder != nil {
+		return s.endDryRun(ctx, true)
+	}
+
 	// Check if in a transaction
-	if tx, ok := s.executor.(*sqlx.Tx); ok {
-		return tx.Commit()
+	tx, ok := s.executor.(*sqlx.Tx)
+	if !ok {
+		return sql.ErrTxDone
+	}
+
+	if err := s.runBeforeCommit(ctx); err != nil {
+		start := time.Now()
+		rbErr := tx.Rollback()
+		s.capture(ctx, "rollback", "ROLLBACK", nil, start, time.Since(start), rbErr)
+		return errors.Join(err, rbErr)
+	}
+	start := time.Now()
+	err := tx.Commit()
+	s.capture(ctx, "commit", "COMMIT", nil, start, time.Since(start), err)
+	if err == nil {
+		s.flushIndexEvents(ctx)
+		s.noteWrite(ctx)
+	}
+	return err
+}
+
+// BeforeCommit registers a hook that runs right before the transaction commits.
+// Use it to validate invariants spanning several repositories (e.g. an order total
+// matching its line items) once all writes of the transaction are done.
+//
+// Hooks run in registration order with the context passed to CommitContext (the
+// context of Transaction, or context.Background() for Commit).
+// They may query and write through the transaction session. If a hook returns an
+// error, the remaining hooks are skipped, the transaction is rolled back and
+// Commit() / Transaction() return the error.
+//
+// Parameters:
+//   - fn: Validation hook
+//
+// Returns:
+//   - error: sql.ErrTxDone if the session is not a transaction session
+//
+// Example:
+//
+//	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+//	    if err := tx.BeforeCommit(func(ctx context.Context) error {
+//	        return checkOrderTotal(ctx, tx, order.ID)
+//	    }); err != nil {
+//	        return err
+//	    }
+//	    // ... create order and line items through any repositories
+//	    return nil
+//	})
+func (s *Session) BeforeCommit(fn func(ctx context.Context) error) error {
+	if s.tx == nil {
+		return sql.ErrTxDone
+	}
+	s.tx.mu.Lock()
+	defer s.tx.mu.Unlock()
+	s.tx.beforeCommit = append(s.tx.beforeCommit, fn)
+	return nil
+}
+
+// runBeforeCommit runs the registered BeforeCommit hooks with ctx.
+// Hooks registered while hooks are running are executed as well.
+func (s *Session) runBeforeCommit(ctx context.Context) error {
+	if s.tx == nil {
+		return nil
+	}
+	for i := 0; ; i++ {
+		s.tx.mu.Lock()
+		if i >= len(s.tx.beforeCommit) {
+			s.tx.mu.Unlock()
+			return nil
+		}
+		hook := s.tx.beforeCommit[i]
+		s.tx.mu.Unlock()
+
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("sqlc: before commit hook failed: %w", err)
+		}
 	}
-	return sql.ErrTxDone
 }
 
 // Rollback rolls back the current transaction.
+// It is RollbackContext with context.Background().
+//
+// Returns:
+//   - error: Rollback error, returns sql.ErrTxDone if not in a transaction
+func (s *Session) Rollback() error {
+	return s.RollbackContext(context.Background())
+}
+
+// RollbackContext rolls back the current transaction.
 // Only effective in transaction mode (after calling Begin()).
 //
+// Parameters:
+//   - ctx: Context passed to statement capture
+//
 // Returns:
 //   - error: Rollback error, returns sql.ErrTxDone if not in a transaction
 //
@@ -405,13 +680,22 @@ func (s *Session) Commit() error {
 //
 //	txSession, _ := session.Begin(ctx)
 //	// ... execute operations
-//	if err := txSession.Rollback(); err != nil {
+//	if err := txSession.RollbackContext(ctx); err != nil {
 //	    log.Error("rollback failed", "error", err)
 //	}
-func (s *Session) Rollback() error {
+func (s *Session) RollbackContext(ctx context.Context) error {
+	if s.recorder != nil {
+		return s.endDryRun(ctx, false)
+	}
+
 	// Check if in a transaction
 	if tx, ok := s.executor.(*sqlx.Tx); ok {
-		return tx.Rollback()
+		start := time.Now()
+		err := tx.Rollback()
+		if s.tx != nil && !errors.Is(err, sql.ErrTxDone) {
+			s.capture(ctx, "rollback", "ROLLBACK", nil, start, time.Since(start), err)
+		}
+		return err
 	}
 	return sql.ErrTxDone
 }
@@ -450,30 +734,81 @@ func (s *Session) Rollback() error {
 //	if err != nil {
 //	    log.Error("transaction failed", "error", err)
 //	}
+//
+// Panics:
+//   - If fn panics, the transaction is rolled back and the panic is re-raised
+//     with its original value (the rollback error, if any, is logged)
+//   - With WithPanicRecovery(), the panic is returned as a *PanicError instead,
+//     joined with the rollback error (errors.Join)
 func (s *Session) Transaction(ctx context.Context, fn func(txSession *Session) error) (err error) {
+	return s.TransactionWithOptions(ctx, nil, fn)
+}
+
+// TransactionWithOptions executes a function within a transaction started with the given
+// options, with the automatic commit and rollback of Transaction.
+//
+// Parameters:
+//   - ctx: Context supporting cancellation and timeout
+//   - opts: Isolation level and read-only mode; nil uses the driver defaults
+//   - fn: Transaction function, receives transaction session and returns error
+//
+// Returns:
+//   - error: Function error, commit error, or error starting the transaction
+//
+// Example:
+//
+//	// Serializable transaction
+//	err := session.TransactionWithOptions(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *sqlc.Session) error {
+//	    return transfer(ctx, tx, from, to, amount)
+//	})
+//
+//	// Read-only transaction: consistent snapshot across several reports
+//	err = session.TransactionWithOptions(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *sqlc.Session) error {
+//	    return buildReports(ctx, tx)
+//	})
+//
+// Note:
+//   - Inside a running transaction, fn joins it; requesting an isolation level or
+//     read-only mode there fails, since they cannot be changed once it has started
+//   - Serializable transactions may fail with serialization errors; retry the whole function
+func (s *Session) TransactionWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(txSession *Session) error) (err error) {
 	// Check if already in a transaction
 	// If so, execute function directly to avoid nested transactions
-	if _, ok := s.executor.(*sqlx.Tx); ok {
+	if s.tx != nil {
+		if opts != nil && (opts.Isolation != sql.LevelDefault || opts.ReadOnly) {
+			return fmt.Errorf("sqlc: cannot start a %s transaction inside a running transaction", txOptionsLabel(opts))
+		}
 		return fn(s)
 	}
 
 	// Begin new transaction
-	txSession, err := s.Begin(ctx)
+	txSession, err := s.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
 
 	// Use defer to ensure transaction is always handled (commit or rollback)
 	defer func() {
-		// Handle panic: rollback and re-panic
+		// Handle panic: rollback, then re-panic or convert to error
 		if p := recover(); p != nil {
-			_ = txSession.Rollback()
-			panic(p)
+			rbErr := txSession.RollbackContext(ctx)
+			panicErr := &PanicError{Value: p, Stack: debug.Stack(), Queries: s.RecentQueries(0)}
+			if logger := s.observability().Logger; logger != nil {
+				logger.ErrorContext(ctx, "transaction panicked",
+					"panic", p,
+					"rollback_error", rbErr,
+				)
+			}
+			if !s.recoverPanics {
+				panic(p)
+			}
+			err = errors.Join(panicErr, rbErr)
+			return
 		}
 
 		// Handle error: rollback transaction
 		if err != nil {
-			_ = txSession.Rollback()
+			_ = txSession.RollbackContext(ctx)
 		}
 	}()
 
@@ -484,5 +819,65 @@ func (s *Session) Transaction(ctx context.Context, fn func(txSession *Session) e
 	}
 
 	// Function succeeded, commit transaction
-	return txSession.Commit()
+	return txSession.CommitContext(ctx)
+}
+
+// txOptionsLabel describes transaction options in errors (e.g. "serializable read-only").
+func txOptionsLabel(opts *sql.TxOptions) string {
+	var parts []string
+	if opts.Isolation != sql.LevelDefault {
+		parts = append(parts, strings.ToLower(opts.Isolation.String()))
+	}
+	if opts.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	return strings.Join(parts, " ")
+}
+
+// PanicError is returned by Transaction when the callback panics and the session
+// was created with WithPanicRecovery().
+//
+// Example:
+//
+//	var pe *sqlc.PanicError
+//	if errors.As(err, &pe) {
+//	    log.Error("transaction panicked", "panic", pe.Value, "stack", string(pe.Stack))
+//	}
+type PanicError struct {
+	Value   any             // Value passed to panic()
+	Stack   []byte          // Stack trace captured at recovery
+	Queries []CapturedQuery // Statements captured before the panic, including the rollback (WithQueryCapture)
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("sqlc: transaction panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is/As can match it.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// WithPanicRecovery makes Transaction convert callback panics into errors.
+// The transaction is rolled back and Transaction returns a *PanicError
+// (joined with the rollback error, if any) instead of re-raising the panic.
+// Transaction sessions inherit the setting.
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithPanicRecovery())
+//
+//	err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+//	    var m map[string]int
+//	    m["boom"] = 1 // panics
+//	    return nil
+//	})
+//	// err is *sqlc.PanicError, the transaction was rolled back
+func WithPanicRecovery() SessionOption {
+	return func(s *Session) {
+		s.recoverPanics = true
+	}
 }
@@ -1,9 +1,15 @@
 package sqlc_test
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/field"
 )
 
 func TestSoftDeleteSQLGeneration(t *testing.T) {
@@ -56,3 +62,658 @@ func TestSoftDeleteChunk(t *testing.T) {
 		_ = q
 	})
 }
+
+func TestTrashBin(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE products (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO products (name, deleted_at) VALUES
+		('live', NULL),
+		('trashed-1', CURRENT_TIMESTAMP),
+		('trashed-2', CURRENT_TIMESTAMP),
+		('trashed-3', CURRENT_TIMESTAMP)`)
+	if err != nil {
+		t.Fatalf("failed to seed table: %v", err)
+	}
+
+	productRepo := sqlc.NewRepository[SoftDeleteProduct](session)
+
+	t.Run("TrashedPagination", func(t *testing.T) {
+		total, err := productRepo.Trashed().Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if total != 3 {
+			t.Errorf("expected 3 trashed products, got %d", total)
+		}
+
+		page, err := productRepo.Trashed().Limit(2).Offset(2).Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if len(page) != 1 || page[0].Name != "trashed-3" {
+			t.Errorf("unexpected second page: %+v", page)
+		}
+	})
+
+	t.Run("RestoreMany", func(t *testing.T) {
+		// id 1 is live and must be left untouched
+		if err := productRepo.RestoreMany(ctx, 1, 2); err != nil {
+			t.Fatalf("RestoreMany failed: %v", err)
+		}
+		live, err := productRepo.Query().Count(ctx)
+		if err != nil {
+			t.Fatalf("Count failed: %v", err)
+		}
+		if live != 2 {
+			t.Errorf("expected 2 live products, got %d", live)
+		}
+		if err := productRepo.RestoreMany(ctx); err != nil {
+			t.Errorf("RestoreMany with no ids should be a no-op, got %v", err)
+		}
+	})
+
+	t.Run("DeleteTrashed", func(t *testing.T) {
+		// id 3 is still in the trash: deleting it again keeps its deletion time
+		deletedAt := func() string {
+			var at string
+			if err := db.QueryRow("SELECT deleted_at FROM products WHERE id = 3").Scan(&at); err != nil {
+				t.Fatalf("failed to read deleted_at: %v", err)
+			}
+			return at
+		}
+		before := deletedAt()
+		n, err := productRepo.DeleteResult(ctx, 3)
+		if err != nil {
+			t.Fatalf("DeleteResult failed: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("expected no affected rows, got %d", n)
+		}
+		n, err = productRepo.DeleteModelResult(ctx, &SoftDeleteProduct{ID: 3})
+		if err != nil {
+			t.Fatalf("DeleteModelResult failed: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("expected no affected rows, got %d", n)
+		}
+		if after := deletedAt(); after != before {
+			t.Errorf("deleted_at changed from %s to %s", before, after)
+		}
+	})
+
+	t.Run("EmptyTrash", func(t *testing.T) {
+		if err := productRepo.EmptyTrash(ctx); err != nil {
+			t.Fatalf("EmptyTrash failed: %v", err)
+		}
+		trashed, _ := productRepo.Trashed().Count(ctx)
+		if trashed != 0 {
+			t.Errorf("expected empty trash, got %d", trashed)
+		}
+		all, _ := productRepo.Query().WithTrashed().Count(ctx)
+		if all != 2 {
+			t.Errorf("expected 2 remaining products, got %d", all)
+		}
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		userRepo := sqlc.NewRepository[GenUser](session)
+		if _, err := userRepo.Trashed().Find(ctx); err == nil {
+			t.Error("expected error for model without soft delete")
+		}
+		if err := userRepo.EmptyTrash(ctx); err == nil {
+			t.Error("expected error for model without soft delete")
+		}
+		if err := userRepo.RestoreMany(ctx, 1); err == nil {
+			t.Error("expected error for model without soft delete")
+		}
+	})
+}
+
+// BeforeRestore refuses to restore products named "locked"
+func (p *SoftDeleteProduct) BeforeRestore(ctx context.Context) error {
+	if p.Name == "locked" {
+		return errors.New("product is locked")
+	}
+	return nil
+}
+
+// AfterRestore marks the restored instance
+func (p *SoftDeleteProduct) AfterRestore(ctx context.Context) error {
+	p.Name += " (restored)"
+	return nil
+}
+
+func TestRestoreModelAndWhere(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE products (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO products (name, deleted_at) VALUES
+		('widget', CURRENT_TIMESTAMP),
+		('locked', CURRENT_TIMESTAMP),
+		('bulk-a', CURRENT_TIMESTAMP),
+		('bulk-b', CURRENT_TIMESTAMP),
+		('bulk-c', NULL),
+		('other', CURRENT_TIMESTAMP)`)
+	if err != nil {
+		t.Fatalf("failed to seed table: %v", err)
+	}
+
+	productRepo := sqlc.NewRepository[SoftDeleteProduct](session)
+	trashed := func(name string) *SoftDeleteProduct {
+		t.Helper()
+		p, err := productRepo.Trashed().Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: name}).First(ctx)
+		if err != nil {
+			t.Fatalf("Trashed %s: %v", name, err)
+		}
+		return p
+	}
+
+	t.Run("RestoreModel", func(t *testing.T) {
+		p := trashed("widget")
+		if p.DeletedAt == nil {
+			t.Fatal("expected loaded DeletedAt")
+		}
+		if err := productRepo.RestoreModel(ctx, p); err != nil {
+			t.Fatalf("RestoreModel failed: %v", err)
+		}
+		if p.DeletedAt != nil || p.Name != "widget (restored)" {
+			t.Errorf("expected cleared field and AfterRestore, got %+v", p)
+		}
+		if _, err := productRepo.FindOne(ctx, p.ID); err != nil {
+			t.Errorf("restored product not live: %v", err)
+		}
+	})
+
+	t.Run("BeforeRestoreAborts", func(t *testing.T) {
+		p := trashed("locked")
+		if err := productRepo.RestoreModel(ctx, p); err == nil || err.Error() != "product is locked" {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+		if p.DeletedAt == nil {
+			t.Error("aborted restore must not clear the field")
+		}
+		trashed("locked") // still trashed
+	})
+
+	t.Run("RestoreWhere", func(t *testing.T) {
+		if _, err := productRepo.RestoreWhere(ctx); err == nil {
+			t.Error("expected error without scopes")
+		}
+		restored, err := productRepo.
+			Where(clause.Expr{SQL: "name LIKE ?", Vars: []any{"bulk-%"}}).
+			RestoreWhere(ctx)
+		if err != nil {
+			t.Fatalf("RestoreWhere failed: %v", err)
+		}
+		// bulk-c was live and is not counted
+		if restored != 2 {
+			t.Errorf("expected 2 restored rows, got %d", restored)
+		}
+		live, _ := productRepo.Query().Count(ctx)
+		if live != 4 {
+			t.Errorf("expected 4 live products, got %d", live)
+		}
+	})
+
+	t.Run("UnsupportedModel", func(t *testing.T) {
+		userRepo := sqlc.NewRepository[GenUser](session)
+		if err := userRepo.RestoreModel(ctx, &GenUser{ID: 1}); err == nil {
+			t.Error("expected error for model without soft delete")
+		}
+		if _, err := userRepo.Where(clause.Expr{SQL: "id = ?", Vars: []any{1}}).RestoreWhere(ctx); err == nil {
+			t.Error("expected error for model without soft delete")
+		}
+	})
+}
+
+func TestUpdateWhere(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE products (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO products (name, deleted_at) VALUES
+		('old', NULL),
+		('old', NULL),
+		('old', CURRENT_TIMESTAMP),
+		('new', NULL)`)
+	if err != nil {
+		t.Fatalf("failed to seed table: %v", err)
+	}
+
+	productRepo := sqlc.NewRepository[SoftDeleteProduct](session)
+	name := field.String{}.WithColumn("name")
+
+	tests := []struct {
+		name string
+		repo *sqlc.Repository[SoftDeleteProduct]
+		want int64
+	}{
+		{"SkipsTrashed", productRepo.Where(name.Eq("old")), 2},
+		{"Unscoped", productRepo.Where(name.Eq("old")).Unscoped(), 1},
+		{"NoMatch", productRepo.Where(name.Eq("missing")), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.repo.UpdateWhere(ctx, name.Set("archived"))
+			if err != nil {
+				t.Fatalf("UpdateWhere failed: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("expected %d rows affected, got %d", tt.want, n)
+			}
+		})
+	}
+
+	t.Run("RequiresScope", func(t *testing.T) {
+		if _, err := productRepo.UpdateWhere(ctx, name.Set("x")); err == nil {
+			t.Error("expected error without scopes")
+		}
+	})
+}
+
+func TestDeleteWhere(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE products (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO products (name, deleted_at) VALUES
+		('old', NULL),
+		('old', NULL),
+		('old', CURRENT_TIMESTAMP),
+		('new', NULL)`)
+	if err != nil {
+		t.Fatalf("failed to seed table: %v", err)
+	}
+
+	productRepo := sqlc.NewRepository[SoftDeleteProduct](session)
+	name := field.String{}.WithColumn("name")
+
+	// Steps run in order against the same table
+	tests := []struct {
+		name        string
+		repo        *sqlc.Repository[SoftDeleteProduct]
+		want        int64
+		wantRemain  int64 // rows left in the table, including trashed ones
+		wantTrashed int64
+	}{
+		{"SoftDeletes", productRepo.Where(name.Eq("old")), 2, 4, 3},
+		{"SkipsTrashed", productRepo.Where(name.Eq("old")), 0, 4, 3},
+		{"UnscopedHardDeletes", productRepo.Where(name.Eq("old")).Unscoped(), 3, 1, 0},
+		{"NoMatch", productRepo.Where(name.Eq("missing")), 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.repo.DeleteWhere(ctx)
+			if err != nil {
+				t.Fatalf("DeleteWhere failed: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("expected %d rows affected, got %d", tt.want, n)
+			}
+			if total, _ := productRepo.Query().WithTrashed().Count(ctx); total != tt.wantRemain {
+				t.Errorf("expected %d remaining rows, got %d", tt.wantRemain, total)
+			}
+			if trashed, _ := productRepo.Trashed().Count(ctx); trashed != tt.wantTrashed {
+				t.Errorf("expected %d trashed rows, got %d", tt.wantTrashed, trashed)
+			}
+		})
+	}
+
+	t.Run("RequiresScope", func(t *testing.T) {
+		if _, err := productRepo.DeleteWhere(ctx); err == nil {
+			t.Error("expected error without scopes")
+		}
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+		level := field.Number[int]{}.WithColumn("level")
+		if _, err := sqlc.NewRepository[Member](dry).Where(level.Lt(2)).DeleteWhere(ctx); err != nil {
+			t.Fatalf("DeleteWhere failed: %v", err)
+		}
+		stmts := dry.Recorder().Statements()
+		want := "DELETE FROM members WHERE (level < $1)"
+		if len(stmts) != 1 || stmts[0].SQL != want {
+			t.Errorf("SQL mismatch:\ngot:  %v\nwant: %s", stmts, want)
+		}
+	})
+}
+
+// LegacyNote soft deletes through columns its schema does not declare.
+type LegacyNote struct {
+	ID         int64      `db:"id,primaryKey,autoIncrement"`
+	Body       string     `db:"body"`
+	IsDeleted  bool       `db:"is_deleted"`
+	RemovedAt  *time.Time `db:"removed_at"`
+	RemovedMs  int64      `db:"removed_ms"`
+	ArchivedAt time.Time  `db:"archived_at"`
+}
+
+type LegacyNoteSchema struct{}
+
+func (LegacyNoteSchema) TableName() string { return "legacy_notes" }
+func (LegacyNoteSchema) SelectColumns() []string {
+	return []string{"id", "body", "is_deleted", "removed_at", "removed_ms", "archived_at"}
+}
+func (LegacyNoteSchema) InsertRow(m *LegacyNote) ([]string, []any) {
+	return []string{"body", "is_deleted"}, []any{m.Body, m.IsDeleted}
+}
+func (LegacyNoteSchema) UpdateMap(m *LegacyNote) map[string]any {
+	return map[string]any{"body": m.Body}
+}
+func (LegacyNoteSchema) PK(m *LegacyNote) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (LegacyNoteSchema) SetPK(m *LegacyNote, val int64) { m.ID = val }
+func (LegacyNoteSchema) AutoIncrement() bool            { return true }
+func (LegacyNoteSchema) SoftDeleteColumn() string       { return "" }
+func (LegacyNoteSchema) SoftDeleteValue() any           { return nil }
+func (LegacyNoteSchema) SetDeletedAt(m *LegacyNote)     {}
+
+func init() {
+	sqlc.RegisterSchema(LegacyNoteSchema{})
+}
+
+func TestRegisterSoftDelete(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE legacy_notes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		body TEXT,
+		is_deleted BOOLEAN NOT NULL DEFAULT 0,
+		removed_at DATETIME,
+		removed_ms INTEGER NOT NULL DEFAULT 0,
+		archived_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	defer sqlc.RegisterSoftDelete[LegacyNote]("") // Back to hard deletes
+	epoch := time.Unix(0, 0).UTC()
+
+	tests := []struct {
+		name     string
+		register func()
+		deleted  func(n *LegacyNote) bool
+	}{
+		{
+			name:     "Flag",
+			register: func() { sqlc.RegisterSoftDeleteFlag[LegacyNote]("is_deleted") },
+			deleted:  func(n *LegacyNote) bool { return n.IsDeleted },
+		},
+		{
+			name:     "Timestamp",
+			register: func() { sqlc.RegisterSoftDelete[LegacyNote]("removed_at") },
+			deleted:  func(n *LegacyNote) bool { return n.RemovedAt != nil },
+		},
+		{
+			name:     "UnixMilli",
+			register: func() { sqlc.RegisterSoftDelete[LegacyNote]("removed_ms", sqlc.SoftDeleteUnixMilli()) },
+			// Seconds since 1970 stay far below 1e12 until the year 33658
+			deleted: func(n *LegacyNote) bool { return n.RemovedMs > 1e12 },
+		},
+		{
+			name:     "Sentinel",
+			register: func() { sqlc.RegisterSoftDelete[LegacyNote]("archived_at", sqlc.SoftDeleteSentinel(epoch)) },
+			deleted:  func(n *LegacyNote) bool { return !n.ArchivedAt.Equal(epoch) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := db.Exec("DELETE FROM legacy_notes"); err != nil {
+				t.Fatalf("failed to clean table: %v", err)
+			}
+			tt.register()
+			repo := sqlc.NewRepository[LegacyNote](session)
+
+			keep, drop := &LegacyNote{Body: "keep"}, &LegacyNote{Body: "drop"}
+			for _, n := range []*LegacyNote{keep, drop} {
+				if err := repo.Create(ctx, n); err != nil {
+					t.Fatalf("Create failed: %v", err)
+				}
+			}
+			if err := repo.DeleteModel(ctx, drop); err != nil {
+				t.Fatalf("DeleteModel failed: %v", err)
+			}
+			if !tt.deleted(drop) {
+				t.Errorf("expected model deletion marker to be set, got %+v", drop)
+			}
+
+			assertCount := func(q *sqlc.QueryBuilder[LegacyNote], want int64) {
+				t.Helper()
+				got, err := q.Count(ctx)
+				if err != nil {
+					t.Fatalf("Count failed: %v", err)
+				}
+				if got != want {
+					t.Errorf("expected %d, got %d", want, got)
+				}
+			}
+			assertCount(repo.Query(), 1)
+			assertCount(repo.Trashed(), 1)
+			assertCount(repo.Query().WithTrashed(), 2)
+
+			if err := repo.Restore(ctx, drop.ID); err != nil {
+				t.Fatalf("Restore failed: %v", err)
+			}
+			assertCount(repo.Query(), 2)
+			assertCount(repo.Trashed(), 0)
+			if live, err := repo.FindOne(ctx, drop.ID); err != nil || tt.deleted(live) {
+				t.Errorf("expected restored record, got %+v, %v", live, err)
+			}
+
+			if err := repo.DeleteModel(ctx, drop); err != nil {
+				t.Fatalf("DeleteModel failed: %v", err)
+			}
+			if err := repo.RestoreModel(ctx, drop); err != nil {
+				t.Fatalf("RestoreModel failed: %v", err)
+			}
+			if tt.deleted(drop) {
+				t.Errorf("expected RestoreModel to reset the marker, got %+v", drop)
+			}
+		})
+	}
+
+	t.Run("UnknownColumn", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for unknown column")
+			}
+		}()
+		sqlc.RegisterSoftDelete[LegacyNote]("missing")
+	})
+
+	t.Run("FlagRequiresBool", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for non-bool flag")
+			}
+		}()
+		sqlc.RegisterSoftDeleteFlag[LegacyNote]("removed_at")
+	})
+
+	t.Run("UnixMilliRequiresInteger", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for non-integer unix milli column")
+			}
+		}()
+		sqlc.RegisterSoftDelete[LegacyNote]("removed_at", sqlc.SoftDeleteUnixMilli())
+	})
+
+	t.Run("SentinelType", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for sentinel of wrong type")
+			}
+		}()
+		sqlc.RegisterSoftDelete[LegacyNote]("archived_at", sqlc.SoftDeleteSentinel("never"))
+	})
+}
+
+func TestRepositoryQueryInheritsScopes(t *testing.T) {
+	productRepo := sqlc.NewRepository[SoftDeleteProduct](sqlc.NewSession(nil, sqlc.PostgreSQL))
+	tenant := clause.Eq{Column: clause.Column{Name: "tenant_id"}, Value: 7}
+	name := func(v string) clause.Expression { return clause.Eq{Column: clause.Column{Name: "name"}, Value: v} }
+
+	tests := []struct {
+		name     string
+		query    *sqlc.QueryBuilder[SoftDeleteProduct]
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "Scopes",
+			query:    productRepo.Where(tenant).Query(),
+			wantSQL:  "SELECT id, name, deleted_at FROM products WHERE (tenant_id = $1) AND deleted_at IS NULL",
+			wantArgs: []any{7},
+		},
+		{
+			name:     "OrWhereKeepsScopes",
+			query:    productRepo.Where(tenant).Query().Where(name("a")).OrWhere(name("b")),
+			wantSQL:  "SELECT id, name, deleted_at FROM products WHERE (tenant_id = $1) AND (name = $2 OR name = $3) AND deleted_at IS NULL",
+			wantArgs: []any{7, "a", "b"},
+		},
+		{
+			name:     "Unscoped",
+			query:    productRepo.Where(tenant).Unscoped().Query(),
+			wantSQL:  "SELECT id, name, deleted_at FROM products WHERE (tenant_id = $1)",
+			wantArgs: []any{7},
+		},
+		{
+			name:     "RawOrScope",
+			query:    productRepo.Where(clause.Expr{SQL: "tenant_id = ? OR shared", Vars: []any{7}}).Query(),
+			wantSQL:  "SELECT id, name, deleted_at FROM products WHERE (tenant_id = $1 OR shared) AND deleted_at IS NULL",
+			wantArgs: []any{7},
+		},
+		{
+			name:     "UnscopedTrashed",
+			query:    productRepo.Unscoped().Trashed(),
+			wantSQL:  "SELECT id, name, deleted_at FROM products WHERE deleted_at IS NOT NULL",
+			wantArgs: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.query.ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL failed: %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args mismatch: got %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFindMany(t *testing.T) {
+	db, session := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE products (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO products (name, deleted_at) VALUES
+		('a', NULL),
+		('b', NULL),
+		('c', CURRENT_TIMESTAMP),
+		('d', NULL)`)
+	if err != nil {
+		t.Fatalf("failed to seed table: %v", err)
+	}
+
+	productRepo := sqlc.NewRepository[SoftDeleteProduct](session)
+	name := field.String{}.WithColumn("name")
+
+	tests := []struct {
+		name string
+		repo *sqlc.Repository[SoftDeleteProduct]
+		ids  []any
+		want []string
+	}{
+		{"InputOrder", productRepo, []any{4, 1, 2}, []string{"d", "a", "b"}},
+		{"MissingAndDuplicate", productRepo, []any{2, 99, 2, int64(1)}, []string{"b", "a"}},
+		{"SoftDeleted", productRepo, []any{3, 1}, []string{"a"}},
+		{"Unscoped", productRepo.Unscoped(), []any{3, 1}, []string{"c", "a"}},
+		{"Scoped", productRepo.Where(name.Neq("a")), []any{1, 2}, []string{"b"}},
+		{"Empty", productRepo, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, err := tt.repo.FindMany(ctx, tt.ids...)
+			if err != nil {
+				t.Fatalf("FindMany failed: %v", err)
+			}
+			var got []string
+			for _, p := range products {
+				got = append(got, p.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// One query per chunk under SQLite's 999 placeholder limit
+	dry := sqlc.NewSession(nil, sqlc.SQLite).DryRun()
+	ids := make([]any, 1200)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	if _, err := sqlc.NewRepository[SoftDeleteProduct](dry).FindMany(ctx, ids...); err != nil {
+		t.Fatalf("FindMany failed: %v", err)
+	}
+	if n := len(dry.Recorder().Statements()); n != 3 {
+		t.Errorf("expected 3 queries, got %d", n)
+	}
+}
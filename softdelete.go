@@ -0,0 +1,349 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements runtime configuration of soft delete columns.
+//
+// Generated schemas detect the soft delete column from the DeletedAt field or the
+// softDelete tag. RegisterSoftDelete and RegisterSoftDeleteFlag override that choice
+// for a model at runtime, e.g. for legacy tables with a "removed_at" timestamp or an
+// "is_deleted" flag. Queries, Delete, Restore, Trashed and EmptyTrash all honor the
+// configured column.
+//
+// Supported strategies:
+//   - Nullable timestamp: NULL = live, deletion time = deleted (default)
+//   - Boolean flag: false = live, true = deleted (RegisterSoftDeleteFlag)
+//   - Integer Unix seconds or milliseconds: 0 = live (SoftDeleteUnixMilli)
+//   - Sentinel: a NOT NULL column whose live value is a fixed sentinel (SoftDeleteSentinel)
+//
+// Usage example:
+//
+//	func init() {
+//	    sqlc.RegisterSoftDelete[models.Account]("removed_at")
+//	    sqlc.RegisterSoftDeleteFlag[models.Post]("is_deleted")
+//	    sqlc.RegisterSoftDelete[models.Event]("deleted_at", sqlc.SoftDeleteUnixMilli())
+//	}
+package sqlc
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// SoftDeleteActive is optionally implemented by schemas whose soft delete column is
+// not NULL on live records, such as boolean flag columns. Without it, live records
+// are those where the soft delete column IS NULL.
+type SoftDeleteActive interface {
+	// SoftDeleteActiveValue returns the soft delete column value of live (not deleted) records.
+	SoftDeleteActiveValue() any
+}
+
+// softDeleteActive returns the soft delete column value of live records of schema.
+func softDeleteActive(schema any) any {
+	if s, ok := schema.(SoftDeleteActive); ok {
+		return s.SoftDeleteActiveValue()
+	}
+	return nil
+}
+
+// softDeleteActiveCond returns the condition matching the live records of schema.
+func softDeleteActiveCond[T any](schema Schema[T]) clause.Expression {
+	column := clause.Column{Name: schema.SoftDeleteColumn()}
+	if live := softDeleteActive(schema); live != nil {
+		return clause.Eq{Column: column, Value: live}
+	}
+	return clause.IsNull{Column: column}
+}
+
+// SoftDeleteOption configures the soft delete strategy of RegisterSoftDelete.
+// Uses functional options pattern to provide flexible configuration.
+type SoftDeleteOption func(*softDeleteConfig)
+
+// softDeleteConfig holds the options of RegisterSoftDelete
+type softDeleteConfig struct {
+	milli   bool // Integer fields store Unix milliseconds
+	live    any  // Sentinel value of live records
+	hasLive bool // live is set
+}
+
+// SoftDeleteUnixMilli stores the deletion time of integer fields in Unix milliseconds
+// instead of seconds (e.g. a deleted_at BIGINT column written by JavaScript services).
+//
+// Example:
+//
+//	sqlc.RegisterSoftDelete[models.Event]("deleted_at", sqlc.SoftDeleteUnixMilli())
+func SoftDeleteUnixMilli() SoftDeleteOption {
+	return func(c *softDeleteConfig) {
+		c.milli = true
+	}
+}
+
+// SoftDeleteSentinel marks live records with the sentinel value live instead of the
+// default (NULL for timestamps, 0 for integers). Useful for NOT NULL columns that take
+// part in unique indexes, such as UNIQUE (email, deleted_at) with a zero-date sentinel.
+//
+// Example:
+//
+//	epoch := time.Unix(0, 0).UTC()
+//	sqlc.RegisterSoftDelete[models.User]("deleted_at", sqlc.SoftDeleteSentinel(epoch))
+//
+// Note:
+//   - live must be convertible to the type of the soft delete field
+func SoftDeleteSentinel(live any) SoftDeleteOption {
+	return func(c *softDeleteConfig) {
+		c.live = live
+		c.hasLive = true
+	}
+}
+
+// RegisterSoftDelete sets the soft delete column of model T to the timestamp column column,
+// replacing the column of its registered schema. Deleting sets the column to the current
+// time (Unix seconds for integer fields, see SoftDeleteUnixMilli); live records have NULL,
+// or 0 for integer fields, unless a SoftDeleteSentinel is given.
+// An empty column disables soft delete for T.
+//
+// Parameters:
+//   - column: Column name of a time.Time, *time.Time, sql.NullTime or integer field of T
+//   - opts: Strategy options (SoftDeleteUnixMilli, SoftDeleteSentinel)
+//
+// Example:
+//
+//	func init() {
+//	    sqlc.RegisterSoftDelete[models.Account]("removed_at")
+//	    sqlc.RegisterSoftDelete[models.Event]("deleted_at", sqlc.SoftDeleteUnixMilli())
+//	}
+//
+// Note:
+//   - Must be called after the schema is registered (generated init) and before repositories are created
+//   - Panics if T has no field tagged with column, or the options don't fit its type (configuration error)
+func RegisterSoftDelete[T any](column string, opts ...SoftDeleteOption) {
+	var cfg softDeleteConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	registerSoftDelete[T](column, false, cfg)
+}
+
+// RegisterSoftDeleteFlag sets the soft delete column of model T to the boolean flag column
+// column. Deleting sets the flag to true; live records have false.
+//
+// Parameters:
+//   - column: Column name of a bool field of T
+//
+// Example:
+//
+//	func init() {
+//	    sqlc.RegisterSoftDeleteFlag[models.Post]("is_deleted")
+//	}
+//
+// Note:
+//   - Records are live only while the flag is false; NULL flags are neither live nor trashed
+//   - Panics if T has no bool field tagged with column (configuration error)
+func RegisterSoftDeleteFlag[T any](column string) {
+	registerSoftDelete[T](column, true, softDeleteConfig{})
+}
+
+func registerSoftDelete[T any](column string, flag bool, cfg softDeleteConfig) {
+	base := LoadSchema[T]()
+	if s, ok := base.(*softDeleteSchema[T]); ok {
+		base = s.Schema
+	}
+	s := &softDeleteSchema[T]{Schema: base, column: column, flag: flag}
+	if column != "" {
+		typ := reflect.TypeFor[T]()
+		index, ok := columnFieldIndex(typ, column)
+		if !ok {
+			panic(fmt.Sprintf("sqlc: %s has no field for soft delete column %q", typ, column))
+		}
+		fieldType := typ.FieldByIndex(index).Type
+		s.field = index
+		s.kind = softDeleteKindOf(fieldType, flag)
+		if s.kind == softDeleteUnsupported {
+			panic(fmt.Sprintf("sqlc: field for soft delete column %q of %s has unsupported type %s", column, typ, fieldType))
+		}
+		if cfg.milli && s.kind != softDeleteUnix {
+			panic(fmt.Sprintf("sqlc: SoftDeleteUnixMilli requires an integer field, column %q of %s is %s", column, typ, fieldType))
+		}
+		s.milli = cfg.milli
+		if cfg.hasLive {
+			live := reflect.ValueOf(cfg.live)
+			if !live.IsValid() || !live.Type().ConvertibleTo(fieldType) {
+				panic(fmt.Sprintf("sqlc: soft delete sentinel %v is not convertible to %s (column %q of %s)", cfg.live, fieldType, column, typ))
+			}
+			s.live = live.Convert(fieldType)
+		}
+	}
+	RegisterSchema[T](s)
+}
+
+// softDeleteKind is the Go representation of a soft delete field.
+type softDeleteKind int
+
+const (
+	softDeleteUnsupported softDeleteKind = iota
+	softDeleteTime                       // time.Time
+	softDeleteTimePtr                    // *time.Time
+	softDeleteNullTime                   // sql.NullTime
+	softDeleteUnix                       // Integer Unix seconds
+	softDeleteBool                       // Boolean flag
+)
+
+func softDeleteKindOf(typ reflect.Type, flag bool) softDeleteKind {
+	if flag {
+		if typ.Kind() == reflect.Bool {
+			return softDeleteBool
+		}
+		return softDeleteUnsupported
+	}
+	switch typ {
+	case reflect.TypeFor[time.Time]():
+		return softDeleteTime
+	case reflect.TypeFor[*time.Time]():
+		return softDeleteTimePtr
+	case reflect.TypeFor[sql.NullTime]():
+		return softDeleteNullTime
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return softDeleteUnix
+	}
+	return softDeleteUnsupported
+}
+
+// softDeleteSchema overrides the soft delete column of a registered schema.
+type softDeleteSchema[T any] struct {
+	Schema[T]
+	column string
+	flag   bool
+	field  []int          // Index path of the soft delete field in T
+	kind   softDeleteKind // Go representation of the field
+	milli  bool           // Integer fields store Unix milliseconds
+	live   reflect.Value  // Sentinel value of live records, converted to the field type; invalid if unset
+}
+
+func (s *softDeleteSchema[T]) SoftDeleteColumn() string {
+	return s.column
+}
+
+func (s *softDeleteSchema[T]) SoftDeleteValue() any {
+	switch {
+	case s.column == "":
+		return nil
+	case s.flag:
+		return true
+	case s.kind == softDeleteUnix && s.milli:
+		return time.Now().UnixMilli()
+	case s.kind == softDeleteUnix:
+		return time.Now().Unix()
+	default:
+		return time.Now()
+	}
+}
+
+func (s *softDeleteSchema[T]) SoftDeleteActiveValue() any {
+	switch {
+	case s.live.IsValid():
+		return s.live.Interface()
+	case s.flag:
+		return false
+	case s.kind == softDeleteUnix:
+		return 0
+	}
+	return nil
+}
+
+// DefaultOrder forwards the default order of the wrapped schema.
+func (s *softDeleteSchema[T]) DefaultOrder() []clause.OrderByColumn {
+	if d, ok := s.Schema.(DefaultOrderer); ok {
+		return d.DefaultOrder()
+	}
+	return nil
+}
+
+// ValidationRules forwards the validate rules of the wrapped schema.
+func (s *softDeleteSchema[T]) ValidationRules() []FieldRule[T] {
+	return validationRules(s.Schema)
+}
+
+func (s *softDeleteSchema[T]) SetDeletedAt(m *T) {
+	if s.column == "" || m == nil {
+		return
+	}
+	f := reflect.ValueOf(m).Elem().FieldByIndex(s.field)
+	now := time.Now()
+	switch s.kind {
+	case softDeleteTime:
+		f.Set(reflect.ValueOf(now))
+	case softDeleteTimePtr:
+		f.Set(reflect.ValueOf(&now))
+	case softDeleteNullTime:
+		f.Set(reflect.ValueOf(sql.NullTime{Time: now, Valid: true}))
+	case softDeleteUnix:
+		ts := now.Unix()
+		if s.milli {
+			ts = now.UnixMilli()
+		}
+		if f.CanInt() {
+			f.SetInt(ts)
+		} else {
+			f.SetUint(uint64(ts))
+		}
+	case softDeleteBool:
+		f.SetBool(true)
+	}
+}
+
+// ClearDeletedAt resets the soft delete field of m to the live value.
+func (s *softDeleteSchema[T]) ClearDeletedAt(m *T) {
+	if s.column == "" || m == nil {
+		return
+	}
+	f := reflect.ValueOf(m).Elem().FieldByIndex(s.field)
+	if s.live.IsValid() {
+		f.Set(s.live)
+		return
+	}
+	f.SetZero()
+}
+
+// clearDeletedAt resets the soft delete field of m to its live value: the configured
+// sentinel, or else the zero value, i.e. NULL for timestamps, 0 for integers and false
+// for flags. Models without a field tagged with the soft delete column are left unchanged.
+func clearDeletedAt[T any](schema Schema[T], m *T) {
+	if c, ok := schema.(interface{ ClearDeletedAt(*T) }); ok {
+		c.ClearDeletedAt(m)
+		return
+	}
+	column := schema.SoftDeleteColumn()
+	if column == "" || m == nil {
+		return
+	}
+	if index, ok := columnFieldIndex(reflect.TypeFor[T](), column); ok {
+		reflect.ValueOf(m).Elem().FieldByIndex(index).SetZero()
+	}
+}
+
+// columnFieldIndex returns the index path of the field of struct type typ tagged with column.
+// Fields of embedded structs are searched like taggedColumns does.
+func columnFieldIndex(typ reflect.Type, column string) ([]int, bool) {
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		name, _, _ := strings.Cut(tag, ",")
+		switch {
+		case name == "-":
+		case f.Anonymous && !hasTag && f.Type.Kind() == reflect.Struct:
+			if index, ok := columnFieldIndex(f.Type, column); ok {
+				return append([]int{i}, index...), true
+			}
+		case hasTag && name == column && f.IsExported():
+			return []int{i}, true
+		}
+	}
+	return nil, false
+}
@@ -1,17 +1,30 @@
 package sqlc_test
 
 import (
+	"context"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/arllen133/sqlc"
 	"github.com/arllen133/sqlc/clause"
 	"github.com/arllen133/sqlc/field"
+	"github.com/arllen133/sqlc/sqlctest"
 )
 
 // -- Mocks for SQL Generation Tests --
 
+// basicDialect is an external dialect implementing only the required Dialect methods.
+type basicDialect struct{}
+
+func (basicDialect) Name() string                            { return "basic" }
+func (basicDialect) PlaceholderFormat() sq.PlaceholderFormat { return sq.Question }
+func (basicDialect) UpsertClause(string, []string, []string) string {
+	return ""
+}
+
 type GenUser struct {
 	ID        int64     `db:"id"`
 	Username  string    `db:"username"`
@@ -261,6 +274,45 @@ func TestSQLGeneration(t *testing.T) {
 				"LEFT JOIN users ON posts.user_id = users.id",
 			},
 		},
+		{
+			name: "FullJoin",
+			buildQuery: func() (string, []any, error) {
+				return postRepo.Query().
+					FullJoin(&GenUser{},
+						sqlc.On(GenPostFields.UserID, GenUserFields.ID),
+					).
+					ToSQL()
+			},
+			wantContains: []string{
+				"FROM posts",
+				"FULL JOIN users ON posts.user_id = users.id",
+			},
+		},
+		{
+			name: "FullJoinAs",
+			buildQuery: func() (string, []any, error) {
+				return postRepo.Query().
+					FullJoinAs(&GenUser{}, "u",
+						sqlc.On(GenPostFields.UserID, clause.Column{Name: "id"}),
+					).
+					ToSQL()
+			},
+			wantContains: []string{
+				"FULL JOIN users u ON posts.user_id = u.id",
+			},
+		},
+		{
+			name: "CrossJoin",
+			buildQuery: func() (string, []any, error) {
+				return postRepo.Query().
+					CrossJoin(&GenUser{}).
+					ToSQL()
+			},
+			wantContains: []string{
+				"SELECT posts.id, posts.user_id, posts.title, posts.content, posts.metadata, posts.created_at, posts.updated_at FROM posts",
+				"CROSS JOIN users",
+			},
+		},
 		{
 			name: "JoinWithWhere",
 			buildQuery: func() (string, []any, error) {
@@ -439,7 +491,607 @@ func TestSubquerySQLGeneration(t *testing.T) {
 	}
 }
 
+func TestLockingSQLGeneration(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect sqlc.Dialect
+		build   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser]
+		wantSQL string
+	}{
+		{
+			name:    "PostgresForUpdate",
+			dialect: sqlc.PostgreSQL,
+			build:   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] { return q.ForUpdate() },
+			wantSQL: "SELECT id, username, email, created_at FROM users FOR UPDATE",
+		},
+		{
+			name:    "PostgresForShareSkipLocked",
+			dialect: sqlc.PostgreSQL,
+			build: func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] {
+				return q.Limit(5).ForShare().SkipLocked()
+			},
+			wantSQL: "SELECT id, username, email, created_at FROM users LIMIT 5 FOR SHARE SKIP LOCKED",
+		},
+		{
+			name:    "MySQLForUpdateNoWait",
+			dialect: sqlc.MySQL,
+			build:   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] { return q.ForUpdate().NoWait() },
+			wantSQL: "SELECT id, username, email, created_at FROM users FOR UPDATE NOWAIT",
+		},
+		{
+			name:    "MySQLForShareLegacy",
+			dialect: sqlc.MySQL,
+			build:   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] { return q.ForShare() },
+			wantSQL: "SELECT id, username, email, created_at FROM users LOCK IN SHARE MODE",
+		},
+		{
+			name:    "SQLiteIgnoresLock",
+			dialect: sqlc.SQLite,
+			build:   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] { return q.ForUpdate().SkipLocked() },
+			wantSQL: "SELECT id, username, email, created_at FROM users",
+		},
+		{
+			name:    "DialectWithoutRowLocker",
+			dialect: basicDialect{},
+			build:   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] { return q.ForUpdate() },
+			wantSQL: "SELECT id, username, email, created_at FROM users",
+		},
+		{
+			name:    "WaitPolicyWithoutStrength",
+			dialect: sqlc.PostgreSQL,
+			build:   func(q *sqlc.QueryBuilder[GenUser]) *sqlc.QueryBuilder[GenUser] { return q.NoWait() },
+			wantSQL: "SELECT id, username, email, created_at FROM users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := sqlc.NewSession(nil, tt.dialect)
+			gotSQL, _, err := tt.build(sqlc.Query[GenUser](session)).ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestWhereGroupSQLGeneration(t *testing.T) {
+	type userQuery = *sqlc.QueryBuilder[GenUser]
+	tests := []struct {
+		name     string
+		build    func(q userQuery) userQuery
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "OrWhere",
+			build: func(q userQuery) userQuery {
+				return q.Where(GenUserFields.ID.Gt(10)).
+					Where(GenUserFields.Username.Eq("alice")).
+					OrWhere(GenUserFields.Email.Eq("a@b.c"))
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE (users.id > ? AND users.username = ? OR users.email = ?)",
+			wantArgs: []any{int64(10), "alice", "a@b.c"},
+		},
+		{
+			name:     "OrWhereFirstActsAsWhere",
+			build:    func(q userQuery) userQuery { return q.OrWhere(GenUserFields.ID.Eq(1)) },
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE users.id = ?",
+			wantArgs: []any{int64(1)},
+		},
+		{
+			name: "WhereGroup",
+			build: func(q userQuery) userQuery {
+				return q.Where(GenUserFields.ID.Gt(10)).
+					WhereGroup(func(g userQuery) userQuery {
+						return g.Where(GenUserFields.Username.Eq("alice")).
+							OrWhere(GenUserFields.Username.Eq("bob"))
+					})
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE users.id > ? AND (users.username = ? OR users.username = ?)",
+			wantArgs: []any{int64(10), "alice", "bob"},
+		},
+		{
+			name: "NestedOrWhereGroup",
+			build: func(q userQuery) userQuery {
+				return q.Where(GenUserFields.ID.Eq(1)).
+					OrWhereGroup(func(g userQuery) userQuery {
+						return g.Where(GenUserFields.Username.Eq("alice")).
+							WhereGroup(func(g userQuery) userQuery {
+								return g.Where(GenUserFields.ID.Lt(5)).OrWhere(GenUserFields.ID.Gt(50))
+							})
+					})
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE (users.id = ? OR (users.username = ? AND (users.id < ? OR users.id > ?)))",
+			wantArgs: []any{int64(1), "alice", int64(5), int64(50)},
+		},
+		{
+			name: "EmptyGroupIgnored",
+			build: func(q userQuery) userQuery {
+				return q.WhereGroup(func(g userQuery) userQuery { return g })
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users",
+			wantArgs: nil,
+		},
+		{
+			name: "ClauseOrKeptAtomic",
+			build: func(q userQuery) userQuery {
+				return q.Where(clause.Or{GenUserFields.ID.Eq(1), GenUserFields.ID.Eq(2)}).
+					Where(GenUserFields.Username.Eq("alice"))
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE ((users.id = ?) OR (users.id = ?)) AND users.username = ?",
+			wantArgs: []any{int64(1), int64(2), "alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.build(sqlc.Query[GenUser](setupGenSession())).ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args mismatch: got %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+
+	t.Run("SoftDeleteAppliesToAllBranches", func(t *testing.T) {
+		gotSQL, _, err := sqlc.Query[SoftDeleteProduct](setupGenSession()).
+			Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: "a"}).
+			OrWhere(clause.Eq{Column: clause.Column{Name: "name"}, Value: "b"}).
+			ToSQL()
+		if err != nil {
+			t.Fatalf("ToSQL() error = %v", err)
+		}
+		want := "SELECT id, name, deleted_at FROM products WHERE (name = ? OR name = ?) AND deleted_at IS NULL"
+		if gotSQL != want {
+			t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, want)
+		}
+	})
+
+	t.Run("GroupErrorPropagates", func(t *testing.T) {
+		_, _, err := sqlc.Query[GenUser](setupGenSession()).
+			WhereGroup(func(g userQuery) userQuery { return g.OrderByExpr(clause.Case{}) }).
+			ToSQL()
+		if err == nil {
+			t.Error("expected error from group")
+		}
+	})
+}
+
+func TestConditionalWhereSQLGeneration(t *testing.T) {
+	type userQuery = *sqlc.QueryBuilder[GenUser]
+	alice := "alice"
+	tests := []struct {
+		name     string
+		build    func(q userQuery) userQuery
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "WhereIf",
+			build: func(q userQuery) userQuery {
+				return q.WhereIf(true, GenUserFields.ID.Gt(10)).
+					WhereIf(false, GenUserFields.Username.Eq("skipped"))
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE users.id > ?",
+			wantArgs: []any{int64(10)},
+		},
+		{
+			name: "WhereNotZeroSkipsZeroValues",
+			build: func(q userQuery) userQuery {
+				var none *string
+				return q.WhereNotZero(GenUserFields.Username, "").
+					WhereNotZero(GenUserFields.ID, int64(0)).
+					WhereNotZero(GenUserFields.Email, none).
+					WhereNotZero(GenUserFields.Email, nil)
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users",
+			wantArgs: nil,
+		},
+		{
+			name: "WhereNotZeroAppliesValues",
+			build: func(q userQuery) userQuery {
+				return q.WhereNotZero(GenUserFields.Username, "bob").
+					WhereNotZero(GenUserFields.Email, &alice)
+			},
+			wantSQL:  "SELECT id, username, email, created_at FROM users WHERE users.username = ? AND users.email = ?",
+			wantArgs: []any{"bob", &alice},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tt.build(sqlc.Query[GenUser](setupGenSession())).ToSQL()
+			if err != nil {
+				t.Fatalf("ToSQL() error = %v", err)
+			}
+			if gotSQL != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("args mismatch: got %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFromSubquerySQLGeneration(t *testing.T) {
+	session := sqlc.NewSession(nil, sqlc.PostgreSQL)
+	username := field.String{}.WithColumn("username")
+
+	sub := sqlc.Query[GenUser](session).
+		Where(GenUserFields.ID.Gt(10)).
+		SelectExpr(clause.As(clause.Coalesce(GenUserFields.Email, "n/a"), "contact"))
+	gotSQL, gotArgs, err := sqlc.Query[GenUser](session).
+		FromSubquery(sub, "u").
+		Where(username.Eq("alice")).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	// Nested placeholders are numbered by the outer query
+	want := "SELECT id, username, email, created_at FROM " +
+		"(SELECT id, username, email, created_at, COALESCE(users.email, $1) AS contact FROM users WHERE users.id > $2) AS u " +
+		"WHERE username = $3"
+	if gotSQL != want {
+		t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []any{"n/a", int64(10), "alice"}) {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+
+	t.Run("SubqueryError", func(t *testing.T) {
+		bad := sqlc.Query[GenUser](session).OrderByExpr(clause.Case{})
+		if _, _, err := sqlc.Query[GenUser](session).FromSubquery(bad, "u").ToSQL(); err == nil {
+			t.Error("expected error from invalid subquery")
+		}
+	})
+}
+
+func TestFullJoinUnsupported(t *testing.T) {
+	session := sqlc.NewSession(nil, sqlc.MySQL)
+	_, _, err := sqlc.Query[GenPost](session).
+		FullJoin(&GenUser{}, sqlc.On(GenPostFields.UserID, GenUserFields.ID)).
+		ToSQL()
+	if err == nil || !strings.Contains(err.Error(), "FULL JOIN") {
+		t.Errorf("expected FULL JOIN error on MySQL, got %v", err)
+	}
+}
+
+func TestLeftJoinLateralSQLGeneration(t *testing.T) {
+	session := sqlc.NewSession(nil, sqlc.PostgreSQL)
+
+	latest := sqlc.Query[GenUser](session).
+		Where(clause.Expr{SQL: "users.email IS NOT NULL"}).
+		Where(GenUserFields.Username.Neq("bot")).
+		Limit(1)
+
+	tests := []struct {
+		name     string
+		on       clause.Expression
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "OnTrue",
+			wantSQL: "SELECT users.id, next.username FROM users LEFT JOIN LATERAL " +
+				"(SELECT id, username, email, created_at FROM users WHERE users.email IS NOT NULL AND users.username <> $1 LIMIT 1) next ON true " +
+				"WHERE users.id = $2",
+			wantArgs: []any{"bot", int64(1)},
+		},
+		{
+			name: "OnCondition",
+			on:   clause.Expr{SQL: "next.email <> ?", Vars: []any{""}},
+			wantSQL: "SELECT users.id, next.username FROM users LEFT JOIN LATERAL " +
+				"(SELECT id, username, email, created_at FROM users WHERE users.email IS NOT NULL AND users.username <> $1 LIMIT 1) next ON next.email <> $2 " +
+				"WHERE users.id = $3",
+			wantArgs: []any{"bot", "", int64(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := sqlc.Query[GenUser](session).
+				Select(GenUserFields.ID, clause.Column{Table: "next", Name: "username"}).
+				LeftJoinLateral(latest, "next", tt.on).
+				Where(GenUserFields.ID.Eq(1))
+			sqlctest.ExpectSQL(t, q, tt.wantSQL, tt.wantArgs...)
+		})
+	}
+
+	t.Run("UnsupportedDialect", func(t *testing.T) {
+		for _, dialect := range []sqlc.Dialect{sqlc.MySQL, sqlc.SQLite} {
+			s := sqlc.NewSession(nil, dialect)
+			_, _, err := sqlc.Query[GenUser](s).LeftJoinLateral(sqlc.Query[GenUser](s), "x", nil).ToSQL()
+			if err == nil || !strings.Contains(err.Error(), "LATERAL") {
+				t.Errorf("%s: expected LATERAL error, got %v", dialect.Name(), err)
+			}
+		}
+	})
+}
+
+func TestDistinctOnSQLGeneration(t *testing.T) {
+	session := sqlc.NewSession(nil, sqlc.PostgreSQL)
+	gotSQL, gotArgs, err := sqlc.Query[GenUser](session).
+		DistinctOn(GenUserFields.Email, clause.Column{Name: "username"}).
+		Where(GenUserFields.ID.Gt(10)).
+		OrderBy(GenUserFields.Email.Asc(), GenUserFields.ID.Desc()).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	want := "SELECT DISTINCT ON (users.email, username) id, username, email, created_at FROM users WHERE users.id > $1 ORDER BY users.email, users.id DESC"
+	if gotSQL != want {
+		t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []any{int64(10)}) {
+		t.Errorf("args mismatch: got %v", gotArgs)
+	}
+
+	t.Run("NoColumns", func(t *testing.T) {
+		if _, _, err := sqlc.Query[GenUser](session).DistinctOn().ToSQL(); err == nil {
+			t.Error("expected error without columns")
+		}
+	})
+
+	t.Run("UnsupportedDialect", func(t *testing.T) {
+		for _, dialect := range []sqlc.Dialect{sqlc.MySQL, sqlc.SQLite} {
+			s := sqlc.NewSession(nil, dialect)
+			_, _, err := sqlc.Query[GenUser](s).DistinctOn(GenUserFields.Email).ToSQL()
+			if err == nil || !strings.Contains(err.Error(), "DISTINCT ON") {
+				t.Errorf("%s: expected DISTINCT ON error, got %v", dialect.Name(), err)
+			}
+		}
+	})
+}
+
 // contains checks if s contains substr (case-insensitive for SQL)
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestOrderByExprSQLGeneration(t *testing.T) {
+	q := sqlc.Query[GenUser](sqlc.NewSession(nil, sqlc.PostgreSQL)).
+		Where(GenUserFields.ID.Gt(10)).
+		OrderByExpr(clause.Case{Operand: GenUserFields.Username}.When("admin", 0).Else(1)).
+		OrderBy(GenUserFields.ID.Desc())
+
+	gotSQL, gotArgs, err := q.ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL() error = %v", err)
+	}
+	want := "SELECT id, username, email, created_at FROM users WHERE users.id > $1 ORDER BY CASE users.username WHEN $2 THEN $3 ELSE $4 END, users.id DESC"
+	if gotSQL != want {
+		t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", gotSQL, want)
+	}
+	if len(gotArgs) != 4 || gotArgs[1] != "admin" {
+		t.Errorf("unexpected args: %v", gotArgs)
+	}
+
+	_, _, err = sqlc.Query[GenUser](sqlc.NewSession(nil, sqlc.SQLite)).
+		OrderByExpr(clause.Case{}).
+		ToSQL()
+	if err == nil {
+		t.Error("expected error from invalid order expression")
+	}
+}
+
+func TestSQLFunctionsSQLGeneration(t *testing.T) {
+	build := func(dialect sqlc.Dialect) *sqlc.QueryBuilder[GenUser] {
+		return sqlc.Query[GenUser](sqlc.NewSession(nil, dialect)).
+			Select(GenUserFields.ID).
+			SelectExpr(clause.Coalesce(GenUserFields.Email, "n/a").As("contact")).
+			Where(clause.Cast(GenUserFields.Username, "bigint").Gt(100)).
+			OrderByExpr(clause.NullIf(GenUserFields.Email, "").Desc())
+	}
+
+	tests := []struct {
+		name    string
+		dialect sqlc.Dialect
+		wantSQL string
+	}{
+		{
+			name:    "MySQL",
+			dialect: sqlc.MySQL,
+			wantSQL: "SELECT users.id, COALESCE(users.email, ?) AS contact FROM users WHERE CAST(users.username AS SIGNED) > ? ORDER BY NULLIF(users.email, ?) DESC",
+		},
+		{
+			name:    "PostgreSQL",
+			dialect: sqlc.PostgreSQL,
+			wantSQL: "SELECT users.id, COALESCE(users.email, $1) AS contact FROM users WHERE CAST(users.username AS bigint) > $2 ORDER BY NULLIF(users.email, $3) DESC",
+		},
+		{
+			name:    "SQLite",
+			dialect: sqlc.SQLite,
+			wantSQL: "SELECT users.id, COALESCE(users.email, ?) AS contact FROM users WHERE CAST(users.username AS INTEGER) > ? ORDER BY NULLIF(users.email, ?) DESC",
+		},
+		{
+			name:    "DialectWithoutCastType",
+			dialect: basicDialect{},
+			wantSQL: "SELECT users.id, COALESCE(users.email, ?) AS contact FROM users WHERE CAST(users.username AS bigint) > ? ORDER BY NULLIF(users.email, ?) DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlctest.ExpectSQL(t, build(tt.dialect), tt.wantSQL, "n/a", 100, "")
+		})
+	}
+}
+
+// SortedNote has a default order: newest first
+type SortedNote struct {
+	ID        int64     `db:"id"`
+	Title     string    `db:"title"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type SortedNoteSchema struct{}
+
+func (SortedNoteSchema) TableName() string                         { return "notes" }
+func (SortedNoteSchema) SelectColumns() []string                   { return []string{"id", "title", "created_at"} }
+func (SortedNoteSchema) InsertRow(m *SortedNote) ([]string, []any) { return nil, nil }
+func (SortedNoteSchema) UpdateMap(m *SortedNote) map[string]any    { return nil }
+func (SortedNoteSchema) PK(m *SortedNote) sqlc.PK                  { return sqlc.PK{Column: clause.Column{Name: "id"}} }
+func (SortedNoteSchema) SetPK(m *SortedNote, val int64)            {}
+func (SortedNoteSchema) AutoIncrement() bool                       { return true }
+func (SortedNoteSchema) SoftDeleteColumn() string                  { return "" }
+func (SortedNoteSchema) SoftDeleteValue() any                      { return nil }
+func (SortedNoteSchema) SetDeletedAt(m *SortedNote)                {}
+func (SortedNoteSchema) DefaultOrder() []clause.OrderByColumn {
+	return []clause.OrderByColumn{
+		{Column: clause.Column{Name: "created_at"}, Desc: true},
+		{Column: clause.Column{Name: "id"}, Desc: true},
+	}
+}
+
+func init() {
+	sqlc.RegisterSchema(SortedNoteSchema{})
+}
+
+func TestDefaultOrderSQLGeneration(t *testing.T) {
+	ctx := context.Background()
+	title := clause.Column{Name: "title"}
+	noteID := field.Number[int64]{}.WithColumn("id")
+
+	tests := []struct {
+		name        string
+		partitioned bool // Query a partition of a PartitionedRepository
+		run         func(q *sqlc.QueryBuilder[SortedNote]) error
+		wantSQL     string
+	}{
+		{
+			name:    "Default",
+			run:     func(q *sqlc.QueryBuilder[SortedNote]) error { _, err := q.Find(ctx); return err },
+			wantSQL: "SELECT id, title, created_at FROM notes ORDER BY created_at DESC, id DESC",
+		},
+		{
+			name:        "Partition",
+			partitioned: true,
+			run:         func(q *sqlc.QueryBuilder[SortedNote]) error { _, err := q.Find(ctx); return err },
+			wantSQL:     "SELECT id, title, created_at FROM notes_0 ORDER BY created_at DESC, id DESC",
+		},
+		{
+			name: "ExplicitOrderBy",
+			run: func(q *sqlc.QueryBuilder[SortedNote]) error {
+				_, err := q.OrderBy(clause.OrderByColumn{Column: title}).Find(ctx)
+				return err
+			},
+			wantSQL: "SELECT id, title, created_at FROM notes ORDER BY title",
+		},
+		{
+			name:    "Unordered",
+			run:     func(q *sqlc.QueryBuilder[SortedNote]) error { _, err := q.Unordered().Limit(5).Find(ctx); return err },
+			wantSQL: "SELECT id, title, created_at FROM notes LIMIT 5",
+		},
+		{
+			name: "Join",
+			run: func(q *sqlc.QueryBuilder[SortedNote]) error {
+				_, err := q.JoinTable("tags", clause.Expr{SQL: "tags.note_id = notes.id"}).Find(ctx)
+				return err
+			},
+			wantSQL: "SELECT notes.id, notes.title, notes.created_at FROM notes JOIN tags ON tags.note_id = notes.id ORDER BY notes.created_at DESC, notes.id DESC",
+		},
+		{
+			name: "GroupBy",
+			run: func(q *sqlc.QueryBuilder[SortedNote]) error {
+				return q.Select(title).GroupBy(title).Scan(ctx, &[]SortedNote{})
+			},
+			wantSQL: "SELECT title FROM notes GROUP BY title",
+		},
+		{
+			name:    "Count",
+			run:     func(q *sqlc.QueryBuilder[SortedNote]) error { _, err := q.Count(ctx); return err },
+			wantSQL: "SELECT COUNT(*) FROM notes",
+		},
+		{
+			name: "InSubquery",
+			run: func(q *sqlc.QueryBuilder[SortedNote]) error {
+				_, err := q.Unordered().Where(noteID.InExpr(q.Select(noteID))).Find(ctx)
+				return err
+			},
+			wantSQL: "SELECT id, title, created_at FROM notes WHERE id IN (SELECT id FROM notes)",
+		},
+		{
+			name: "LimitedInSubquery",
+			run: func(q *sqlc.QueryBuilder[SortedNote]) error {
+				_, err := q.Unordered().Where(noteID.InExpr(q.Select(noteID).Limit(3))).Find(ctx)
+				return err
+			},
+			wantSQL: "SELECT id, title, created_at FROM notes WHERE id IN (SELECT id FROM notes ORDER BY created_at DESC, id DESC LIMIT 3)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dry := sqlc.NewSession(nil, sqlc.SQLite).DryRun()
+			q := sqlc.Query[SortedNote](dry)
+			if tt.partitioned {
+				q = sqlc.NewPartitionedRepository(dry, 1, func(n *SortedNote) int64 { return n.ID }).Partition(0).Query()
+			}
+			if err := tt.run(q); err != nil {
+				t.Fatalf("query failed: %v", err)
+			}
+			stmts := dry.Recorder().Statements()
+			if len(stmts) != 1 {
+				t.Fatalf("expected 1 statement, got %d", len(stmts))
+			}
+			if stmts[0].SQL != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %s\nwant: %s", stmts[0].SQL, tt.wantSQL)
+			}
+		})
+	}
+}
+
+func TestBatchCreateReturningSQL(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		dialect sqlc.Dialect
+		opts    []sqlc.BatchOption
+		wantSQL string
+	}{
+		{"MySQL", sqlc.MySQL, nil, "INSERT INTO departments (name) VALUES (?),(?)"},
+		{"MariaDB", sqlc.MariaDB, nil, "INSERT INTO departments (name) VALUES (?),(?) RETURNING id"},
+		{"PostgreSQL", sqlc.PostgreSQL, nil, "INSERT INTO departments (name) VALUES ($1),($2) RETURNING id"},
+		{
+			"ReturningKey", sqlc.PostgreSQL,
+			[]sqlc.BatchOption{sqlc.WithReturningKey(clause.Column{Name: "name"})},
+			"INSERT INTO departments (name) VALUES ($1),($2) RETURNING id, name",
+		},
+		{
+			"ReturningKeyMySQL", sqlc.MySQL,
+			[]sqlc.BatchOption{sqlc.WithReturningKey(clause.Column{Name: "name"})},
+			"INSERT INTO departments (name) VALUES (?),(?)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dry := sqlc.NewSession(nil, tt.dialect).DryRun()
+			depts := []*Department{{Name: "a"}, {Name: "b"}}
+			if err := sqlc.NewRepository[Department](dry).BatchCreate(ctx, depts, tt.opts...); err != nil {
+				t.Fatalf("BatchCreate failed: %v", err)
+			}
+			stmts := dry.Recorder().Statements()
+			if len(stmts) != 1 || stmts[0].SQL != tt.wantSQL {
+				t.Errorf("SQL mismatch:\ngot:  %+v\nwant: %s", stmts, tt.wantSQL)
+			}
+		})
+	}
+
+	t.Run("ReturningKeyNotInserted", func(t *testing.T) {
+		dry := sqlc.NewSession(nil, sqlc.PostgreSQL).DryRun()
+		depts := []*Department{{Name: "a"}}
+		err := sqlc.NewRepository[Department](dry).BatchCreate(ctx, depts, sqlc.WithReturningKey(clause.Column{Name: "code"}))
+		if err == nil || !strings.Contains(err.Error(), "not an inserted column") {
+			t.Errorf("expected backfill key error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+// Package sqlctest provides assertions on the SQL built by sqlc queries, for unit
+// tests that check query construction without a database.
+//
+// ExpectSQL compares the SQL and arguments of a query with the expected ones after
+// normalizing both: whitespace runs collapse to one space, spacing around parentheses
+// and commas is made uniform, and positional placeholders ($1, @p1, :1) are rewritten
+// to ?. The same expectation therefore holds for every dialect, and can be written
+// across several lines. Quoted strings and identifiers are compared verbatim.
+//
+// Usage example:
+//
+//	func TestActiveUsersQuery(t *testing.T) {
+//	    q := userRepo.Query().Where(generated.User.Active.Eq(true)).Limit(10)
+//	    sqlctest.ExpectSQL(t, q, `
+//	        SELECT id, name, active FROM users
+//	        WHERE users.active = ?
+//	        LIMIT 10`, true)
+//	}
+//
+// A mismatch reports both normalized statements with a marker under the first
+// difference, and each differing argument with its type.
+package sqlctest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// T is the subset of testing.TB used by ExpectSQL.
+type T interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Query is a query whose SQL can be rendered, such as *sqlc.QueryBuilder[T].
+type Query interface {
+	ToSQL() (string, []any, error)
+}
+
+// ExpectSQL reports a test error unless q renders want with arguments wantArgs,
+// after normalizing both statements (see Normalize).
+//
+// Parameters:
+//   - t: The test (*testing.T, *testing.B, ...)
+//   - q: Query to render, e.g. repo.Query().Where(...)
+//   - want: Expected SQL, with ? or dialect placeholders, on one or several lines
+//   - wantArgs: Expected bound arguments, compared with reflect.DeepEqual
+//
+// Returns:
+//   - bool: true if SQL and arguments match
+//
+// Example:
+//
+//	sqlctest.ExpectSQL(t, q, "SELECT id FROM users WHERE users.id IN (?, ?)", int64(1), int64(2))
+//
+// Note:
+//   - Arguments must have the rendered types: int64(1) does not match 1
+func ExpectSQL(t T, q Query, want string, wantArgs ...any) bool {
+	t.Helper()
+	got, gotArgs, err := q.ToSQL()
+	if err != nil {
+		t.Errorf("sqlctest: ToSQL failed: %v", err)
+		return false
+	}
+	if diff := Diff(got, gotArgs, want, wantArgs); diff != "" {
+		t.Errorf("sqlctest: unexpected query\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// Diff describes the differences between a rendered statement and the expected one,
+// after normalizing both. Returns "" if they match.
+func Diff(got string, gotArgs []any, want string, wantArgs []any) string {
+	var b strings.Builder
+
+	gotSQL, wantSQL := Normalize(got), Normalize(want)
+	if gotSQL != wantSQL {
+		at := 0
+		for at < len(gotSQL) && at < len(wantSQL) && gotSQL[at] == wantSQL[at] {
+			at++
+		}
+		fmt.Fprintf(&b, "SQL mismatch at offset %d:\n  got:  %s\n  want: %s\n        %s^\n",
+			at, gotSQL, wantSQL, strings.Repeat(" ", at))
+	}
+
+	if len(gotArgs) != len(wantArgs) {
+		fmt.Fprintf(&b, "args mismatch: got %d, want %d\n", len(gotArgs), len(wantArgs))
+	}
+	for i := range max(len(gotArgs), len(wantArgs)) {
+		switch {
+		case i >= len(gotArgs):
+			fmt.Fprintf(&b, "  [%d] missing, want %s\n", i, describeArg(wantArgs[i]))
+		case i >= len(wantArgs):
+			fmt.Fprintf(&b, "  [%d] unexpected %s\n", i, describeArg(gotArgs[i]))
+		case !reflect.DeepEqual(gotArgs[i], wantArgs[i]):
+			fmt.Fprintf(&b, "  [%d] got %s, want %s\n", i, describeArg(gotArgs[i]), describeArg(wantArgs[i]))
+		}
+	}
+	return b.String()
+}
+
+// describeArg formats an argument with its type, so 1 and int64(1) can be told apart
+func describeArg(v any) string {
+	return fmt.Sprintf("%#v (%T)", v, v)
+}
+
+// Normalize rewrites a SQL statement into a canonical form for comparison:
+//   - Whitespace runs become one space; leading and trailing whitespace and a
+//     trailing semicolon are removed
+//   - No space follows "(" or precedes ")" and ","; one space follows ","
+//   - Positional placeholders $N, @pN and :N become ?
+//
+// Text inside single quotes, double quotes and backticks is kept verbatim.
+//
+// Example:
+//
+//	sqlctest.Normalize("SELECT *\n  FROM users WHERE id IN ( $1,$2 );")
+//	// "SELECT * FROM users WHERE id IN (?, ?)"
+func Normalize(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	space := false // whitespace seen since the last written character
+
+	last := func() byte {
+		s := b.String()
+		if s == "" {
+			return 0
+		}
+		return s[len(s)-1]
+	}
+	write := func(s string) {
+		if space && last() != 0 && last() != '(' && s[0] != ')' && s[0] != ',' {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		case c == '\'' || c == '"' || c == '`':
+			// Quoted text: copy up to the closing quote; doubled quotes escape it
+			j := i + 1
+			for j < len(sql) {
+				if sql[j] == c {
+					if j+1 < len(sql) && sql[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			end := min(j+1, len(sql))
+			write(sql[i:end])
+			i = end - 1
+		case c == ',':
+			write(",")
+			space = true
+		case c == '$' || c == ':' || c == '@':
+			// Positional placeholder: $1, :1, @p1 (not a :: cast or an identifier suffix)
+			j := i + 1
+			if c == '@' && j < len(sql) && sql[j] == 'p' {
+				j++
+			}
+			k := j
+			for k < len(sql) && sql[k] >= '0' && sql[k] <= '9' {
+				k++
+			}
+			if k > j && (space || !isIdentByte(last()) && last() != ':') {
+				write("?")
+				i = k - 1
+				continue
+			}
+			write(string(c))
+		default:
+			write(string(c))
+		}
+	}
+	return strings.TrimSpace(strings.TrimSuffix(b.String(), ";"))
+}
+
+// isIdentByte reports whether c can be part of an identifier
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,139 @@
+package sqlctest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/sqlctest"
+)
+
+// recorder is a sqlctest.T that records reported errors
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// rawQuery is a sqlctest.Query rendering a fixed statement
+type rawQuery struct {
+	sql  string
+	args []any
+	err  error
+}
+
+func (q rawQuery) ToSQL() (string, []any, error) { return q.sql, q.args, q.err }
+
+type User struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type userSchema struct{}
+
+func (userSchema) TableName() string                   { return "users" }
+func (userSchema) SelectColumns() []string             { return []string{"id", "name"} }
+func (userSchema) InsertRow(m *User) ([]string, []any) { return nil, nil }
+func (userSchema) UpdateMap(m *User) map[string]any    { return nil }
+func (userSchema) PK(m *User) sqlc.PK                  { return sqlc.PK{Column: clause.Column{Name: "id"}} }
+func (userSchema) SetPK(m *User, val int64)            {}
+func (userSchema) AutoIncrement() bool                 { return true }
+func (userSchema) SoftDeleteColumn() string            { return "" }
+func (userSchema) SoftDeleteValue() any                { return nil }
+func (userSchema) SetDeletedAt(m *User)                {}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"Whitespace", "  SELECT id,\n\tname\n  FROM users  ", "SELECT id, name FROM users"},
+		{"Parentheses", "WHERE id IN ( ?,? ) AND (a = ?)", "WHERE id IN (?, ?) AND (a = ?)"},
+		{"Dollar", "WHERE a = $1 AND b IN ($2, $3) LIMIT $4", "WHERE a = ? AND b IN (?, ?) LIMIT ?"},
+		{"SQLServer", "WHERE a = @p1 AND b = @p2", "WHERE a = ? AND b = ?"},
+		{"Oracle", "WHERE a = :1", "WHERE a = ?"},
+		{"Cast", "SELECT a::int, b::text FROM t", "SELECT a::int, b::text FROM t"},
+		{"Quoted", `SELECT 'a  $1 ,b', "x  y", 'it''s' FROM t;`, `SELECT 'a  $1 ,b', "x  y", 'it''s' FROM t`},
+		{"Semicolon", "SELECT 1 ;\n", "SELECT 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sqlctest.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpectSQL(t *testing.T) {
+	sqlc.RegisterSchema(userSchema{})
+	where := clause.Eq{Column: clause.Column{Table: "users", Name: "id"}, Value: int64(7)}
+
+	t.Run("AcrossDialects", func(t *testing.T) {
+		want := `
+			SELECT id, name
+			FROM users
+			WHERE users.id = ?`
+		for _, dialect := range []sqlc.Dialect{sqlc.MySQL, sqlc.PostgreSQL, sqlc.SQLite} {
+			q := sqlc.Query[User](sqlc.NewSession(nil, dialect)).Where(where)
+			if !sqlctest.ExpectSQL(t, q, want, int64(7)) {
+				t.Errorf("%s: expected match", dialect.Name())
+			}
+		}
+	})
+
+	tests := []struct {
+		name      string
+		query     sqlctest.Query
+		want      string
+		wantArgs  []any
+		wantError []string
+	}{
+		{
+			name:      "SQLMismatch",
+			query:     rawQuery{sql: "SELECT id FROM users WHERE id = $1", args: []any{int64(7)}},
+			want:      "SELECT id FROM users WHERE name = ?",
+			wantArgs:  []any{int64(7)},
+			wantError: []string{"SQL mismatch at offset 27", "got:  SELECT id FROM users WHERE id = ?", "                                   ^"},
+		},
+		{
+			name:      "ArgType",
+			query:     rawQuery{sql: "SELECT 1 WHERE a = ?", args: []any{int64(7)}},
+			want:      "SELECT 1 WHERE a = ?",
+			wantArgs:  []any{7},
+			wantError: []string{"[0] got 7 (int64), want 7 (int)"},
+		},
+		{
+			name:      "ArgCount",
+			query:     rawQuery{sql: "SELECT 1 WHERE a = ? AND b = ?", args: []any{"x", "y"}},
+			want:      "SELECT 1 WHERE a = ? AND b = ?",
+			wantArgs:  []any{"x"},
+			wantError: []string{"args mismatch: got 2, want 1", `[1] unexpected "y" (string)`},
+		},
+		{
+			name:      "BuildError",
+			query:     rawQuery{err: fmt.Errorf("boom")},
+			wantError: []string{"ToSQL failed: boom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			if sqlctest.ExpectSQL(rec, tt.query, tt.want, tt.wantArgs...) {
+				t.Fatal("expected mismatch")
+			}
+			if len(rec.errors) != 1 {
+				t.Fatalf("expected one error, got %q", rec.errors)
+			}
+			for _, want := range tt.wantError {
+				if !strings.Contains(rec.errors[0], want) {
+					t.Errorf("error missing %q:\n%s", want, rec.errors[0])
+				}
+			}
+		})
+	}
+}
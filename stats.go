@@ -0,0 +1,222 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements request-scoped query statistics with N+1 detection.
+//
+// Metrics aggregate over all requests, so an endpoint that regresses from 3 queries to 300
+// (a relation loaded in a loop instead of with Preload) hides in the averages. WithRequestStats
+// attaches a RequestStats to a request context; every statement executed with that context is
+// counted and timed, and statements repeated with the same shape more than a threshold number
+// of times are reported as N+1 candidates.
+//
+// Usage example:
+//
+//	func StatsMiddleware(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        ctx := sqlc.WithRequestStats(r.Context())
+//	        next.ServeHTTP(w, r.WithContext(ctx))
+//
+//	        stats := sqlc.StatsFromContext(ctx)
+//	        slog.Info("request", "path", r.URL.Path, "db", stats)
+//	        if len(stats.NPlusOne()) > 0 {
+//	            slog.Warn("possible N+1 queries", "path", r.URL.Path, "queries", stats.NPlusOne())
+//	        }
+//	    })
+//	}
+package sqlc
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultNPlusOneThreshold is the number of executions of one statement shape per request
+// above which it is reported as an N+1 candidate
+const defaultNPlusOneThreshold = 10
+
+// RequestStats accumulates statistics of the statements executed with one request context.
+// It is safe for concurrent use.
+type RequestStats struct {
+	mu        sync.Mutex
+	threshold int            // Executions per fingerprint above which N+1 is reported
+	queries   int            // Statements executed
+	errors    int            // Statements that failed
+	dbTime    time.Duration  // Total execution time
+	counts    map[string]int // Executions per fingerprint
+}
+
+// RepeatedQuery is a statement shape executed more often than the N+1 threshold.
+type RepeatedQuery struct {
+	Fingerprint string // Normalized SQL (see QueryFingerprint)
+	Count       int    // Executions in the request
+}
+
+// RequestStatsOption configures WithRequestStats.
+// Uses functional options pattern to provide flexible configuration.
+type RequestStatsOption func(*RequestStats)
+
+// WithNPlusOneThreshold sets how many times one statement shape may run per request
+// before it is reported by NPlusOne (default 10).
+//
+// Example:
+//
+//	ctx = sqlc.WithRequestStats(ctx, sqlc.WithNPlusOneThreshold(3))
+func WithNPlusOneThreshold(n int) RequestStatsOption {
+	return func(s *RequestStats) {
+		if n > 0 {
+			s.threshold = n
+		}
+	}
+}
+
+// requestStatsKey is the context key of the request statistics.
+type requestStatsKey struct{}
+
+// WithRequestStats returns a context that collects statistics of every statement executed
+// with it (or a context derived from it), retrievable via StatsFromContext.
+//
+// Parameters:
+//   - ctx: Request context
+//   - opts: Options such as WithNPlusOneThreshold
+//
+// Returns:
+//   - context.Context: Context carrying a new RequestStats
+//
+// Example:
+//
+//	ctx := sqlc.WithRequestStats(r.Context())
+//	users, err := userRepo.Query().Find(ctx)
+//	fmt.Println(sqlc.StatsFromContext(ctx).Queries()) // 1
+//
+// Note:
+//   - If ctx already carries statistics, it is returned unchanged (opts are ignored),
+//     so nested middlewares share the outermost RequestStats
+//   - When a statement shape crosses the threshold, the session logger (if any)
+//     logs a "possible N+1 query" warning once per request and shape
+func WithRequestStats(ctx context.Context, opts ...RequestStatsOption) context.Context {
+	if StatsFromContext(ctx) != nil {
+		return ctx
+	}
+	stats := &RequestStats{threshold: defaultNPlusOneThreshold, counts: make(map[string]int)}
+	for _, opt := range opts {
+		opt(stats)
+	}
+	return context.WithValue(ctx, requestStatsKey{}, stats)
+}
+
+// StatsFromContext returns the statistics attached by WithRequestStats, or nil if none.
+func StatsFromContext(ctx context.Context) *RequestStats {
+	stats, _ := ctx.Value(requestStatsKey{}).(*RequestStats)
+	return stats
+}
+
+// record counts one statement. It reports whether the statement's shape has just
+// crossed the N+1 threshold.
+func (s *RequestStats) record(query string, duration time.Duration, err error) bool {
+	fp := QueryFingerprint(query)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries++
+	s.dbTime += duration
+	if err != nil {
+		s.errors++
+	}
+	s.counts[fp]++
+	return s.counts[fp] == s.threshold+1
+}
+
+// Queries returns the number of statements executed.
+func (s *RequestStats) Queries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries
+}
+
+// Errors returns the number of statements that failed.
+func (s *RequestStats) Errors() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.errors
+}
+
+// DBTime returns the total execution time of the statements.
+func (s *RequestStats) DBTime() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dbTime
+}
+
+// NPlusOne returns the statement shapes executed more often than the threshold,
+// most frequent first, or nil if there are none.
+func (s *RequestStats) NPlusOne() []RepeatedQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var repeated []RepeatedQuery
+	for fp, n := range s.counts {
+		if n > s.threshold {
+			repeated = append(repeated, RepeatedQuery{Fingerprint: fp, Count: n})
+		}
+	}
+	slices.SortFunc(repeated, func(a, b RepeatedQuery) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Fingerprint, b.Fingerprint))
+	})
+	return repeated
+}
+
+// LogValue implements slog.LogValuer, logging the statistics as a group.
+func (s *RequestStats) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("queries", s.Queries()),
+		slog.Int("errors", s.Errors()),
+		slog.Duration("db_time", s.DBTime()),
+		slog.Int("n_plus_one", len(s.NPlusOne())),
+	)
+}
+
+var (
+	// fingerprintDollar matches PostgreSQL placeholders ($1, $2, ...)
+	fingerprintDollar = regexp.MustCompile(`\$\d+`)
+	// fingerprintList matches placeholder lists (?, ?, ?)
+	fingerprintList = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+	// fingerprintRows matches multi-row VALUES lists (?), (?)
+	fingerprintRows = regexp.MustCompile(`\(\?\)(\s*,\s*\(\?\))+`)
+	// fingerprintSpace matches whitespace runs
+	fingerprintSpace = regexp.MustCompile(`\s+`)
+)
+
+// QueryFingerprint normalizes a parameterized statement to its shape, so statements that
+// differ only in placeholder style or the length of IN / VALUES lists compare equal.
+//
+// Example:
+//
+//	sqlc.QueryFingerprint("SELECT * FROM posts WHERE user_id IN ($1,$2,$3)")
+//	// "SELECT * FROM posts WHERE user_id IN (?)"
+func QueryFingerprint(query string) string {
+	fp := fingerprintDollar.ReplaceAllString(query, "?")
+	fp = fingerprintList.ReplaceAllString(fp, "?")
+	fp = fingerprintRows.ReplaceAllString(fp, "(?)")
+	return strings.TrimSpace(fingerprintSpace.ReplaceAllString(fp, " "))
+}
+
+// recordRequestStats counts a statement in the request statistics of ctx, if any,
+// and warns when its shape crosses the N+1 threshold.
+func (s *Session) recordRequestStats(ctx context.Context, query string, duration time.Duration, err error) {
+	stats := StatsFromContext(ctx)
+	if stats == nil {
+		return
+	}
+	if logger := s.observability().Logger; stats.record(query, duration, err) && logger != nil {
+		attrs := []slog.Attr{
+			slog.String("query", QueryFingerprint(query)),
+			slog.Int("threshold", stats.threshold),
+		}
+		if op := OperationName(ctx); op != "" {
+			attrs = append(attrs, slog.String("operation_name", op))
+		}
+		logger.LogAttrs(ctx, slog.LevelWarn, "possible N+1 query", attrs...)
+	}
+}
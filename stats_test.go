@@ -0,0 +1,101 @@
+package sqlc_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"Dollar", "SELECT * FROM users WHERE id = $1", "SELECT * FROM users WHERE id = ?"},
+		{"InList", "SELECT * FROM posts WHERE user_id IN (?,?,?)", "SELECT * FROM posts WHERE user_id IN (?)"},
+		{"DollarInList", "SELECT * FROM posts WHERE user_id IN ($1, $2)", "SELECT * FROM posts WHERE user_id IN (?)"},
+		{"Values", "INSERT INTO t (a,b) VALUES (?,?),(?,?)", "INSERT INTO t (a,b) VALUES (?)"},
+		{"Whitespace", "SELECT  id\n\tFROM users ", "SELECT id FROM users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sqlc.QueryFingerprint(tt.query); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestStats(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithLogger(logger))
+
+	ctx := sqlc.WithRequestStats(context.Background(), sqlc.WithNPlusOneThreshold(3))
+	if inner := sqlc.WithRequestStats(ctx); sqlc.StatsFromContext(inner) != sqlc.StatsFromContext(ctx) {
+		t.Error("nested WithRequestStats should share the outer stats")
+	}
+
+	// One list query, then a lookup per row: the N+1 pattern
+	if _, err := session.Exec(ctx, "INSERT INTO users (username) VALUES (?),(?),(?),(?),(?)", "a", "b", "c", "d", "e"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	for id := 1; id <= 5; id++ {
+		var name string
+		if err := session.Get(ctx, &name, "SELECT username FROM users WHERE id = ?", id); err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+	}
+	if _, err := session.Exec(ctx, "INSERT INTO missing (id) VALUES (?)", 1); err == nil {
+		t.Fatal("expected error")
+	}
+	// Not counted: no stats in context
+	if _, err := session.Exec(context.Background(), "DELETE FROM users WHERE id = ?", 5); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	stats := sqlc.StatsFromContext(ctx)
+	if stats.Queries() != 7 {
+		t.Errorf("expected 7 queries, got %d", stats.Queries())
+	}
+	if stats.Errors() != 1 {
+		t.Errorf("expected 1 error, got %d", stats.Errors())
+	}
+	if stats.DBTime() <= 0 {
+		t.Error("expected positive db time")
+	}
+	repeated := stats.NPlusOne()
+	if len(repeated) != 1 || repeated[0].Fingerprint != "SELECT username FROM users WHERE id = ?" || repeated[0].Count != 5 {
+		t.Errorf("unexpected N+1 report: %+v", repeated)
+	}
+	if n := strings.Count(buf.String(), "possible N+1 query"); n != 1 {
+		t.Errorf("expected one N+1 warning, got %d:\n%s", n, buf.String())
+	}
+
+	buf.Reset()
+	logger.Info("request", "db", stats)
+	if out := buf.String(); !strings.Contains(out, "db.queries=7") || !strings.Contains(out, "db.n_plus_one=1") {
+		t.Errorf("unexpected log value: %s", out)
+	}
+
+	if sqlc.StatsFromContext(context.Background()) != nil {
+		t.Error("expected nil stats without WithRequestStats")
+	}
+}
@@ -0,0 +1,97 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements strict SQL checks for development and CI sessions.
+//
+// Raw fragments (clause.Expr, JoinTable, session.Exec) are where placeholder mistakes
+// and string-concatenated values slip in. A session created with WithStrictSQL checks
+// every statement before execution:
+//   - The number of placeholders must match the arguments; mismatches fail with a
+//     *clause.BuildError instead of the driver's runtime error
+//   - Literal values compared in the SQL text (name = 'bob', id = 42) are logged as
+//     warnings, since they usually mean a value was concatenated instead of bound
+//
+// Usage example:
+//
+//	session := sqlc.NewSession(db, sqlc.PostgreSQL, sqlc.WithStrictSQL())
+//
+//	_, err := session.Exec(ctx, "UPDATE users SET name = ? WHERE id = ?", name)
+//	var be *clause.BuildError
+//	errors.As(err, &be) // true: 2 placeholders, 1 argument
+package sqlc
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// WithStrictSQL enables strict checks of every statement executed by the session
+// and its transaction sessions: placeholder/argument count validation and warnings
+// about inline literal values. Warnings go to the session logger, or slog.Default()
+// if none is configured.
+//
+// Example:
+//
+//	session := sqlc.NewSession(db, sqlc.SQLite, sqlc.WithStrictSQL())
+//
+// Note:
+//   - Intended for development and tests; the literal check is a text heuristic and
+//     may warn about intentional constants (status = 'active')
+//   - Rejected statements are never sent to the database
+func WithStrictSQL() SessionOption {
+	return func(s *Session) {
+		s.strict = true
+	}
+}
+
+// inlineLiteralRegexp matches a literal value on the right side of a comparison
+var inlineLiteralRegexp = regexp.MustCompile(`(?i)(?:=|<>|!=|<=|>=|<|>|\bLIKE)\s*('(?:[^']|'')*'|-?\d+(?:\.\d+)?\b)`)
+
+// constantLeftRegexp matches text ending in a numeric constant (the left side of 1 = 0)
+var constantLeftRegexp = regexp.MustCompile(`(?:^|[\s(])\d+\s*$`)
+
+// checkStrict validates query and args in strict mode: a placeholder/argument mismatch
+// is returned as a *clause.BuildError, inline literals are logged as warnings.
+func (s *Session) checkStrict(ctx context.Context, query string, args []any) error {
+	if !s.strict {
+		return nil
+	}
+
+	question, dollar := clause.Placeholders(query)
+	placeholders := question
+	if s.dialect.PlaceholderFormat() == sq.Dollar {
+		placeholders = dollar
+	}
+	if placeholders != len(args) {
+		return &clause.BuildError{SQL: query, Placeholders: placeholders, Args: len(args)}
+	}
+
+	if literal, ok := inlineLiteral(query); ok {
+		logger := s.observability().Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.LogAttrs(ctx, slog.LevelWarn, "suspicious inline SQL literal",
+			slog.String("query", query), slog.String("literal", literal))
+	}
+	return nil
+}
+
+// inlineLiteral returns the first literal compared in query. Constant conditions
+// such as 1 = 0 (rendered for empty IN lists) and JSON path operands (meta->>'city',
+// #>'{a,b}', @>) are not reported.
+func inlineLiteral(query string) (string, bool) {
+	for _, m := range inlineLiteralRegexp.FindAllStringSubmatchIndex(query, -1) {
+		if m[0] > 0 && strings.IndexByte("-#>@", query[m[0]-1]) >= 0 {
+			continue
+		}
+		if constantLeftRegexp.MatchString(query[:m[0]]) {
+			continue
+		}
+		return query[m[2]:m[3]], true
+	}
+	return "", false
+}
@@ -0,0 +1,90 @@
+package sqlc_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+	"github.com/arllen133/sqlc/field"
+)
+
+func TestStrictSQL(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT, email TEXT, created_at DATETIME)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	session := sqlc.NewSession(db, &sqlc.SQLiteDialect{}, sqlc.WithStrictSQL(), sqlc.WithLogger(logger))
+
+	tests := []struct {
+		name     string
+		query    string
+		args     []any
+		wantErr  bool // *clause.BuildError
+		wantWarn bool
+	}{
+		{"Bound", "INSERT INTO users (id, username) VALUES (?, ?)", []any{1, "alice"}, false, false},
+		{"TooFewArgs", "UPDATE users SET username = ? WHERE id = ?", []any{"bob"}, true, false},
+		{"InlineString", "UPDATE users SET username = ? WHERE username = 'alice'", []any{"bob"}, false, true},
+		{"InlineNumber", "DELETE FROM users WHERE id = 42", nil, false, true},
+		{"ConstantCondition", "DELETE FROM users WHERE 1 = 0", nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			_, err := session.Exec(ctx, tt.query, tt.args...)
+			var be *clause.BuildError
+			if got := errors.As(err, &be); got != tt.wantErr {
+				t.Fatalf("expected BuildError %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Contains(buf.String(), "suspicious inline SQL literal"); got != tt.wantWarn {
+				t.Errorf("expected warning %v, log:\n%s", tt.wantWarn, buf.String())
+			}
+		})
+	}
+
+	// Generated queries (including the 1 = 0 of an empty IN) pass without warnings
+	buf.Reset()
+	id := field.Number[int64]{}.WithColumn("id")
+	var n int
+	if err := session.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE id = ?").Scan(&n); err == nil {
+		t.Error("expected strict QueryRow to fail without its argument")
+	}
+	if _, err := sqlc.NewRepository[GenUser](session).Query().Where(id.In()).Find(ctx); err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "suspicious") {
+		t.Errorf("unexpected warning:\n%s", buf.String())
+	}
+}
+
+func TestJoinTablePlaceholderMismatch(t *testing.T) {
+	dry := sqlc.NewSession(nil, sqlc.SQLite).DryRun()
+	_, err := sqlc.NewRepository[Member](dry).Query().
+		JoinTable("departments", clause.Expr{SQL: "departments.id = members.department_id AND departments.name = ?"}).
+		Find(context.Background())
+	var be *clause.BuildError
+	if !errors.As(err, &be) || be.Placeholders != 1 || be.Args != 0 {
+		t.Errorf("expected BuildError with 1 placeholder and 0 args, got %v", err)
+	}
+	if len(dry.Recorder().Statements()) != 0 {
+		t.Error("statement must not be executed")
+	}
+}
@@ -0,0 +1,196 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements query templates: queries built once and executed many times.
+//
+// Building a query (conditions, columns, soft delete filter, SQL rendering) costs
+// allocations on every execution. For hot loops, a QueryTemplate captures the fully
+// built SQL once, with named parameters in place of the values that change; each
+// execution only binds those values.
+//
+// Usage example:
+//
+//	// At startup
+//	byStatus, err := userRepo.Query().
+//	    Where(clause.Eq{Column: generated.User.Status.Column(), Value: sqlc.Param("status")}).
+//	    OrderBy(generated.User.ID.Asc()).
+//	    Template()
+//
+//	// In the hot path
+//	users, err := byStatus.Bind("status", "active").Find(ctx)
+package sqlc
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"slices"
+)
+
+// Placeholder is a named query parameter whose value is bound when a QueryTemplate runs.
+// Create it with Param and use it wherever a query argument is accepted.
+type Placeholder struct {
+	Name string
+}
+
+// Param returns a named parameter for use in QueryTemplate conditions.
+//
+// Example:
+//
+//	clause.Eq{Column: generated.User.Status.Column(), Value: sqlc.Param("status")}
+//	clause.Expr{SQL: "created_at > ?", Vars: []any{sqlc.Param("since")}}
+func Param(name string) Placeholder {
+	return Placeholder{Name: name}
+}
+
+// Value implements driver.Valuer. It always fails: a placeholder executed outside
+// of a QueryTemplate would otherwise reach the database unbound.
+func (p Placeholder) Value() (driver.Value, error) {
+	return nil, fmt.Errorf("sqlc: parameter %q must be bound via QueryTemplate", p.Name)
+}
+
+// QueryTemplate is a query built once, with named parameters bound per execution.
+// It is immutable and safe for concurrent use.
+type QueryTemplate[T any] struct {
+	q      *QueryBuilder[T] // Source query for preloads, stages and plan hints
+	query  string           // Built SQL
+	args   []any            // Arguments; placeholders are replaced on execution
+	names  []string         // Distinct parameter names
+	params []templateParam  // Argument positions of the placeholders
+}
+
+// templateParam is the position of a placeholder in the template arguments.
+type templateParam struct {
+	arg  int // Index in QueryTemplate.args
+	name int // Index in QueryTemplate.names
+}
+
+// Template builds the query once and returns it as a reusable template.
+// Values passed as Param(name) become named parameters bound with Bind().
+//
+// Returns:
+//   - *QueryTemplate[T]: Reusable query
+//   - error: Query building error
+//
+// Example:
+//
+//	tpl, err := orderRepo.Query().
+//	    Where(clause.Eq{Column: generated.Order.UserID.Column(), Value: sqlc.Param("user")}).
+//	    WithPreload(sqlc.Preload(generated.OrderItems)).
+//	    Template()
+//
+//	for _, userID := range userIDs {
+//	    orders, err := tpl.Bind("user", userID).Find(ctx)
+//	    ...
+//	}
+//
+// Note:
+//   - Preloads, Map/Filter stages and plan hints of the query apply on every execution
+//   - Parameters can only replace argument values, not LIMIT/OFFSET or identifiers
+//   - Executions fail where a row policy (WithPolicies) restricts the rows of T
+func (q *QueryBuilder[T]) Template() (*QueryTemplate[T], error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	query, args, err := q.applyLock(q.applyOrder(q.applySelect(q.resolveBuilder()))).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build sql: %w", err)
+	}
+
+	t := &QueryTemplate[T]{q: q, query: query, args: args}
+	for i, arg := range args {
+		p, ok := arg.(Placeholder)
+		if !ok {
+			continue
+		}
+		name := slices.Index(t.names, p.Name)
+		if name < 0 {
+			name = len(t.names)
+			t.names = append(t.names, p.Name)
+		}
+		t.params = append(t.params, templateParam{arg: i, name: name})
+	}
+	return t, nil
+}
+
+// Params returns the names of the template's parameters in order of first appearance.
+func (t *QueryTemplate[T]) Params() []string {
+	return slices.Clone(t.names)
+}
+
+// Bind starts an execution of the template with parameter name set to value.
+func (t *QueryTemplate[T]) Bind(name string, value any) *BoundQuery[T] {
+	return t.bound().Bind(name, value)
+}
+
+// Find executes a template without parameters.
+func (t *QueryTemplate[T]) Find(ctx context.Context) ([]*T, error) {
+	return t.bound().Find(ctx)
+}
+
+// bound returns an execution of the template with no parameters bound yet.
+func (t *QueryTemplate[T]) bound() *BoundQuery[T] {
+	return &BoundQuery[T]{t: t, values: make([]any, len(t.names)), set: make([]bool, len(t.names))}
+}
+
+// BoundQuery is one execution of a QueryTemplate with its parameter values.
+// Unlike the template, it is not safe for concurrent use.
+type BoundQuery[T any] struct {
+	t      *QueryTemplate[T]
+	values []any  // Values by parameter index
+	set    []bool // Whether each parameter is bound
+	err    error  // First binding error
+}
+
+// Bind sets parameter name to value.
+// Binding a name the template does not have fails the execution.
+func (b *BoundQuery[T]) Bind(name string, value any) *BoundQuery[T] {
+	i := slices.Index(b.t.names, name)
+	if i < 0 {
+		if b.err == nil {
+			b.err = fmt.Errorf("sqlc: unknown template parameter %q", name)
+		}
+		return b
+	}
+	b.values[i], b.set[i] = value, true
+	return b
+}
+
+// Find executes the template and returns all matching records.
+func (b *BoundQuery[T]) Find(ctx context.Context) ([]*T, error) {
+	if err := templatePolicy[T](ctx, b.t.q.session); err != nil {
+		return nil, err
+	}
+	args, err := b.args()
+	if err != nil {
+		return nil, err
+	}
+	return b.t.q.find(ctx, b.t.query, args)
+}
+
+// Scan executes the template and scans the results into dest (see QueryBuilder.Scan).
+func (b *BoundQuery[T]) Scan(ctx context.Context, dest any) error {
+	if err := templatePolicy[T](ctx, b.t.q.session); err != nil {
+		return err
+	}
+	args, err := b.args()
+	if err != nil {
+		return err
+	}
+	return b.t.q.scan(ctx, dest, b.t.query, args)
+}
+
+// args returns the template arguments with the bound values in place.
+func (b *BoundQuery[T]) args() ([]any, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	for i, ok := range b.set {
+		if !ok {
+			return nil, fmt.Errorf("sqlc: template parameter %q not bound", b.t.names[i])
+		}
+	}
+	args := slices.Clone(b.t.args)
+	for _, p := range b.t.params {
+		args[p.arg] = b.values[p.name]
+	}
+	return args, nil
+}
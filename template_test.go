@@ -0,0 +1,116 @@
+package sqlc_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+func TestQueryTemplate(t *testing.T) {
+	db, session := setupIntegrationDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // Single in-memory database
+	ctx := context.Background()
+
+	memberRepo := sqlc.NewRepository[Member](session)
+	members := []*Member{
+		{Name: "Ann", Email: "ann@test.com", Level: 1, DepartmentID: 1, CreatedAt: time.Now()},
+		{Name: "Ben", Email: "ben@test.com", Level: 2, DepartmentID: 1, CreatedAt: time.Now()},
+		{Name: "Cid", Email: "cid@test.com", Level: 2, DepartmentID: 2, CreatedAt: time.Now()},
+	}
+	if err := memberRepo.BatchCreate(ctx, members); err != nil {
+		t.Fatalf("BatchCreate failed: %v", err)
+	}
+
+	level := clause.Column{Name: "level"}
+	tpl, err := memberRepo.Query().
+		Where(clause.Eq{Column: level, Value: sqlc.Param("level")}).
+		Where(clause.Expr{SQL: "(department_id = ? OR ? = 0)", Vars: []any{sqlc.Param("dept"), sqlc.Param("dept")}}).
+		OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+		Template()
+	if err != nil {
+		t.Fatalf("Template failed: %v", err)
+	}
+	if got := tpl.Params(); !reflect.DeepEqual(got, []string{"level", "dept"}) {
+		t.Errorf("unexpected params %v", got)
+	}
+
+	names := func(ms []*Member) string {
+		var out []string
+		for _, m := range ms {
+			out = append(out, m.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	t.Run("Bind", func(t *testing.T) {
+		tests := []struct {
+			level, dept int
+			want        string
+		}{
+			{1, 0, "Ann"},
+			{2, 0, "Ben,Cid"},
+			{2, 2, "Cid"},
+		}
+		for _, tt := range tests {
+			got, err := tpl.Bind("level", tt.level).Bind("dept", tt.dept).Find(ctx)
+			if err != nil {
+				t.Fatalf("Find failed: %v", err)
+			}
+			if names(got) != tt.want {
+				t.Errorf("level %d dept %d: expected %s, got %s", tt.level, tt.dept, tt.want, names(got))
+			}
+		}
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		var dest []Member
+		if err := tpl.Bind("level", 2).Bind("dept", 1).Scan(ctx, &dest); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		if len(dest) != 1 || dest[0].Name != "Ben" {
+			t.Errorf("unexpected scan result %+v", dest)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lvl := 1 + i%2
+				got, err := tpl.Bind("level", lvl).Bind("dept", 0).Find(ctx)
+				if err != nil || (lvl == 1) != (len(got) == 1) {
+					t.Errorf("level %d: unexpected result %d members, err %v", lvl, len(got), err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("BindingErrors", func(t *testing.T) {
+		if _, err := tpl.Bind("level", 1).Find(ctx); err == nil || !strings.Contains(err.Error(), `"dept" not bound`) {
+			t.Errorf("expected unbound parameter error, got %v", err)
+		}
+		if _, err := tpl.Bind("level", 1).Bind("dept", 0).Bind("nope", 1).Find(ctx); err == nil || !strings.Contains(err.Error(), `unknown template parameter "nope"`) {
+			t.Errorf("expected unknown parameter error, got %v", err)
+		}
+		if _, err := tpl.Find(ctx); err == nil {
+			t.Error("expected error executing template without bindings")
+		}
+	})
+
+	t.Run("UnboundOutsideTemplate", func(t *testing.T) {
+		_, err := memberRepo.Query().Where(clause.Eq{Column: level, Value: sqlc.Param("level")}).Find(ctx)
+		if err == nil || !strings.Contains(err.Error(), "must be bound via QueryTemplate") {
+			t.Errorf("expected placeholder error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package testharness
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/arllen133/sqlc"
+)
+
+// Credentials of the containers started by the harness
+const (
+	containerUser     = "sqlc"
+	containerPassword = "sqlc"
+)
+
+// StartPostgres starts a PostgreSQL container (default image postgres:16-alpine).
+// Requires the "postgres" database/sql driver to be registered (e.g. lib/pq or pgx stdlib).
+//
+// Returns ErrDockerUnavailable if Docker cannot be used.
+func StartPostgres(ctx context.Context, opts ...Option) (*Server, error) {
+	cfg := newConfig(opts)
+	if cfg.image == "" {
+		cfg.image = "postgres:16-alpine"
+	}
+	addr, stop, err := runContainer(ctx, cfg.image, "5432/tcp",
+		"POSTGRES_USER="+containerUser,
+		"POSTGRES_PASSWORD="+containerPassword,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := func(db string) string {
+		return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", containerUser, containerPassword, addr, db)
+	}
+	return newDockerServer(ctx, cfg, "postgres", sqlc.PostgreSQL, dsn, dsn("postgres"), stop)
+}
+
+// StartMySQL starts a MySQL container (default image mysql:8.4).
+// Requires the "mysql" database/sql driver to be registered (github.com/go-sql-driver/mysql).
+//
+// Returns ErrDockerUnavailable if Docker cannot be used.
+func StartMySQL(ctx context.Context, opts ...Option) (*Server, error) {
+	cfg := newConfig(opts)
+	if cfg.image == "" {
+		cfg.image = "mysql:8.4"
+	}
+	addr, stop, err := runContainer(ctx, cfg.image, "3306/tcp",
+		"MYSQL_ROOT_PASSWORD="+containerPassword,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn := func(db string) string {
+		return fmt.Sprintf("root:%s@tcp(%s)/%s?parseTime=true&multiStatements=true", containerPassword, addr, db)
+	}
+	return newDockerServer(ctx, cfg, "mysql", sqlc.MySQL, dsn, dsn(""), stop)
+}
+
+// newDockerServer waits for the container to accept connections and builds the Server.
+func newDockerServer(
+	ctx context.Context,
+	cfg config,
+	driver string,
+	dialect sqlc.Dialect,
+	dsn func(db string) string,
+	adminDSN string,
+	stop func() error,
+) (*Server, error) {
+	admin, err := sql.Open(driver, adminDSN)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("testharness: failed to open %s: %w", driver, err), stop())
+	}
+	if err := waitReady(ctx, admin, cfg.startupTimeout); err != nil {
+		return nil, errors.Join(err, admin.Close(), stop())
+	}
+	return &Server{
+		driver:     driver,
+		dialect:    dialect,
+		dsn:        dsn,
+		admin:      admin,
+		migrations: cfg.migrations,
+		stop:       stop,
+	}, nil
+}
+
+// runContainer starts a detached container publishing port and returns its host address.
+func runContainer(ctx context.Context, image, port string, env ...string) (string, func() error, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return "", nil, ErrDockerUnavailable
+	}
+	if err := exec.CommandContext(ctx, "docker", "info").Run(); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrDockerUnavailable, err)
+	}
+
+	args := []string{"run", "-d", "--rm", "-p", "127.0.0.1::" + strings.TrimSuffix(port, "/tcp")}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	args = append(args, image)
+	out, err := exec.CommandContext(ctx, "docker", args...).Output()
+	if err != nil {
+		return "", nil, fmt.Errorf("testharness: failed to start %s: %w", image, commandError(err))
+	}
+	id := strings.TrimSpace(string(out))
+	stop := func() error {
+		if err := exec.Command("docker", "rm", "-f", id).Run(); err != nil {
+			return fmt.Errorf("testharness: failed to remove container %s: %w", id, commandError(err))
+		}
+		return nil
+	}
+
+	out, err = exec.CommandContext(ctx, "docker", "port", id, port).Output()
+	if err != nil {
+		return "", nil, errors.Join(fmt.Errorf("testharness: failed to read port of %s: %w", image, commandError(err)), stop())
+	}
+	// Output may list several bindings (IPv4/IPv6); the first one is enough
+	addr := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", nil, errors.Join(fmt.Errorf("testharness: unexpected port binding %q: %w", addr, err), stop())
+	}
+	return addr, stop, nil
+}
+
+// waitReady pings db until it answers or the timeout expires.
+func waitReady(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("testharness: database not ready after %s: %w", timeout, err)
+		case <-ticker.C:
+		}
+	}
+}
+
+// commandError adds the stderr of a failed docker command to its error.
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,240 @@
+// Package testharness provides disposable databases for integration tests.
+//
+// A Server is a database server started for the test run (a MySQL or PostgreSQL
+// Docker container, or in-memory SQLite). Each test then asks the server for a
+// session that is isolated from other tests:
+//   - Session: a fresh database with migrations applied, dropped after the test
+//   - TxSession: a transaction on a shared migrated database, rolled back after the test
+//
+// Containers are managed through the docker CLI, so the only requirement is a
+// working `docker` binary; the database/sql driver must be imported by the test
+// package (e.g. _ "github.com/lib/pq", _ "github.com/go-sql-driver/mysql").
+//
+// Usage example:
+//
+//	var server *testharness.Server
+//
+//	func TestMain(m *testing.M) {
+//	    var err error
+//	    server, err = testharness.StartPostgres(context.Background(),
+//	        testharness.WithMigrations(schemaSQL...),
+//	    )
+//	    if errors.Is(err, testharness.ErrDockerUnavailable) {
+//	        os.Exit(0) // skip integration tests
+//	    }
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    code := m.Run()
+//	    server.Close()
+//	    os.Exit(code)
+//	}
+//
+//	func TestCreateUser(t *testing.T) {
+//	    session := server.TxSession(t) // rolled back when the test ends
+//	    repo := sqlc.NewRepository[models.User](session)
+//	    ...
+//	}
+package testharness
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/arllen133/sqlc"
+)
+
+// ErrDockerUnavailable is returned when the docker CLI is missing or the daemon is not reachable.
+var ErrDockerUnavailable = errors.New("testharness: docker is not available")
+
+// Option configures a Server.
+type Option func(*config)
+
+type config struct {
+	image          string        // Docker image
+	migrations     []string      // Statements applied to every new test database
+	startupTimeout time.Duration // Maximum wait for the server to accept connections
+}
+
+// WithImage overrides the default Docker image (e.g. "postgres:15-alpine").
+func WithImage(image string) Option {
+	return func(c *config) {
+		c.image = image
+	}
+}
+
+// WithMigrations sets the statements applied to every new test database, in order.
+// Typically CREATE TABLE statements for the models under test.
+func WithMigrations(stmts ...string) Option {
+	return func(c *config) {
+		c.migrations = append(c.migrations, stmts...)
+	}
+}
+
+// WithStartupTimeout sets how long to wait for the server to accept connections (default 60s).
+func WithStartupTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.startupTimeout = d
+	}
+}
+
+// Server is a database server that hands out isolated test databases.
+// It is safe for concurrent use by parallel tests.
+type Server struct {
+	driver     string                 // database/sql driver name
+	dialect    sqlc.Dialect           // Dialect for created sessions
+	dsn        func(db string) string // Builds the DSN of a database on this server
+	admin      *sql.DB                // Connection used for CREATE/DROP DATABASE
+	migrations []string               // Statements applied to every new database
+	stop       func() error           // Stops the server (removes the container)
+
+	seq      atomic.Int64 // Database name counter
+	sharedMu sync.Mutex   // Guards shared
+	shared   *sql.DB      // Migrated database used by TxSession
+}
+
+// NewSQLite returns a server backed by in-memory SQLite databases.
+// Requires the github.com/mattn/go-sqlite3 driver; no Docker needed.
+func NewSQLite(opts ...Option) *Server {
+	cfg := newConfig(opts)
+	s := &Server{
+		driver:     "sqlite3",
+		dialect:    sqlc.SQLite,
+		migrations: cfg.migrations,
+		stop:       func() error { return nil },
+	}
+	s.dsn = func(db string) string {
+		// Qualified by server so databases of different servers never share a cache
+		return fmt.Sprintf("file:%p_%s?mode=memory&cache=shared", s, db)
+	}
+	return s
+}
+
+// Session returns a session on a new database with migrations applied.
+// The database is dropped when the test finishes.
+func (s *Server) Session(tb testing.TB) *sqlc.Session {
+	tb.Helper()
+	db, err := s.createDatabase(context.Background())
+	if err != nil {
+		tb.Fatalf("testharness: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := db.close(); err != nil {
+			tb.Errorf("testharness: %v", err)
+		}
+	})
+	return sqlc.NewSession(db.DB, s.dialect)
+}
+
+// TxSession returns a transaction session on a shared migrated database.
+// The transaction is rolled back when the test finishes, so writes never leak
+// between tests. Code under test that calls Transaction() joins this transaction.
+func (s *Server) TxSession(tb testing.TB) *sqlc.Session {
+	tb.Helper()
+	shared, err := s.sharedDB(context.Background())
+	if err != nil {
+		tb.Fatalf("testharness: %v", err)
+	}
+	tx, err := sqlc.NewSession(shared, s.dialect).Begin(context.Background())
+	if err != nil {
+		tb.Fatalf("testharness: failed to begin transaction: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			tb.Errorf("testharness: rollback failed: %v", err)
+		}
+	})
+	return tx
+}
+
+// Dialect returns the dialect of the server's databases.
+func (s *Server) Dialect() sqlc.Dialect {
+	return s.dialect
+}
+
+// Close closes all connections and stops the server (removing its databases).
+func (s *Server) Close() error {
+	var errs []error
+	s.sharedMu.Lock()
+	if s.shared != nil {
+		errs = append(errs, s.shared.Close())
+	}
+	s.sharedMu.Unlock()
+	if s.admin != nil {
+		errs = append(errs, s.admin.Close())
+	}
+	errs = append(errs, s.stop())
+	return errors.Join(errs...)
+}
+
+// sharedDB returns the database used by TxSession, creating it on first use.
+func (s *Server) sharedDB(ctx context.Context) (*sql.DB, error) {
+	s.sharedMu.Lock()
+	defer s.sharedMu.Unlock()
+	if s.shared == nil {
+		db, err := s.createDatabase(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.shared = db.DB
+	}
+	return s.shared, nil
+}
+
+// testDB is a created test database and how to drop it.
+type testDB struct {
+	*sql.DB
+	drop func() error
+}
+
+// close closes the connection pool and drops the database.
+func (d testDB) close() error {
+	return errors.Join(d.DB.Close(), d.drop())
+}
+
+// createDatabase creates a uniquely named database and applies migrations.
+func (s *Server) createDatabase(ctx context.Context) (testDB, error) {
+	name := fmt.Sprintf("sqlc_test_%d", s.seq.Add(1))
+	drop := func() error { return nil }
+	if s.admin != nil {
+		if _, err := s.admin.ExecContext(ctx, "CREATE DATABASE "+name); err != nil {
+			return testDB{}, fmt.Errorf("failed to create database %s: %w", name, err)
+		}
+		drop = func() error {
+			if _, err := s.admin.ExecContext(context.Background(), "DROP DATABASE IF EXISTS "+name); err != nil {
+				return fmt.Errorf("failed to drop database %s: %w", name, err)
+			}
+			return nil
+		}
+	}
+
+	db, err := sql.Open(s.driver, s.dsn(name))
+	if err != nil {
+		return testDB{}, errors.Join(fmt.Errorf("failed to open database %s: %w", name, err), drop())
+	}
+	if s.driver == "sqlite3" {
+		// The in-memory database lives as long as one connection stays open
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxLifetime(0)
+	}
+	for _, stmt := range s.migrations {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return testDB{}, errors.Join(fmt.Errorf("migration failed on %s: %w", name, err), db.Close(), drop())
+		}
+	}
+	return testDB{DB: db, drop: drop}, nil
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{startupTimeout: 60 * time.Second}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,101 @@
+package testharness_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/testharness"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const notesDDL = `CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, body TEXT NOT NULL)`
+
+func countNotes(t *testing.T, session *sqlc.Session) int {
+	t.Helper()
+	var n int
+	if err := session.Get(context.Background(), &n, "SELECT COUNT(*) FROM notes"); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	return n
+}
+
+func TestSQLiteServer(t *testing.T) {
+	server := testharness.NewSQLite(testharness.WithMigrations(notesDDL))
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("SessionIsolated", func(t *testing.T) {
+		first := server.Session(t)
+		second := server.Session(t)
+		if _, err := first.Exec(ctx, "INSERT INTO notes (body) VALUES ('a')"); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+		if n := countNotes(t, first); n != 1 {
+			t.Errorf("expected 1 note in first database, got %d", n)
+		}
+		if n := countNotes(t, second); n != 0 {
+			t.Errorf("expected empty second database, got %d notes", n)
+		}
+	})
+
+	t.Run("TxSessionRollsBack", func(t *testing.T) {
+		t.Run("Write", func(t *testing.T) {
+			session := server.TxSession(t)
+			if _, err := session.Exec(ctx, "INSERT INTO notes (body) VALUES ('b')"); err != nil {
+				t.Fatalf("insert failed: %v", err)
+			}
+			// Nested Transaction joins the test transaction
+			err := session.Transaction(ctx, func(tx *sqlc.Session) error {
+				_, err := tx.Exec(ctx, "INSERT INTO notes (body) VALUES ('c')")
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Transaction failed: %v", err)
+			}
+			if n := countNotes(t, session); n != 2 {
+				t.Errorf("expected 2 notes inside transaction, got %d", n)
+			}
+		})
+		t.Run("Read", func(t *testing.T) {
+			if n := countNotes(t, server.TxSession(t)); n != 0 {
+				t.Errorf("expected writes of previous test to be rolled back, got %d notes", n)
+			}
+		})
+	})
+
+	t.Run("MigrationError", func(t *testing.T) {
+		bad := testharness.NewSQLite(testharness.WithMigrations("CREATE TABLE"))
+		defer bad.Close()
+		ft := &fatalRecorder{TB: t}
+		func() {
+			defer func() { _ = recover() }()
+			bad.Session(ft)
+		}()
+		if !ft.failed {
+			t.Error("expected Session to fail on invalid migration")
+		}
+	})
+}
+
+func TestStartWithoutDocker(t *testing.T) {
+	t.Setenv("PATH", "")
+	if _, err := testharness.StartPostgres(context.Background()); !errors.Is(err, testharness.ErrDockerUnavailable) {
+		t.Errorf("expected ErrDockerUnavailable, got %v", err)
+	}
+	if _, err := testharness.StartMySQL(context.Background()); !errors.Is(err, testharness.ErrDockerUnavailable) {
+		t.Errorf("expected ErrDockerUnavailable, got %v", err)
+	}
+}
+
+// fatalRecorder records Fatalf instead of failing the enclosing test.
+type fatalRecorder struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fatalRecorder) Fatalf(format string, args ...any) {
+	f.failed = true
+	panic("fatal")
+}
@@ -0,0 +1,197 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements recursive loading of self-referencing (tree) relations.
+//
+// A self relation links a model to models of the same type, e.g. categories with a
+// parent_id column: Category_Children is a HasMany[Category, Category, int64].
+// Preloading it loads one level; PreloadTree loads the descendants down to a maximum
+// depth. On dialects supporting WITH RECURSIVE the whole subtree is read with one
+// query; on others, one query is run per level.
+//
+// Usage example:
+//
+//	roots, err := categoryRepo.Query().
+//	    Where(generated.Category.ParentID.IsNull()).
+//	    WithPreload(sqlc.PreloadTree(generated.Category_Children, 5)).
+//	    Find(ctx)
+//
+//	// WITH RECURSIVE sqlc_tree AS (
+//	//     SELECT id, name, parent_id, 1 AS sqlc_depth FROM categories WHERE parent_id IN (?, ?)
+//	//     UNION ALL
+//	//     SELECT categories.id, categories.name, categories.parent_id, sqlc_tree.sqlc_depth + 1
+//	//     FROM categories JOIN sqlc_tree ON sqlc_tree.id = categories.parent_id
+//	//     WHERE sqlc_tree.sqlc_depth < ?
+//	// ) SELECT id, name, parent_id FROM sqlc_tree ORDER BY id
+package sqlc
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// treeCTE is the name of the recursive common table expression of PreloadTree
+const treeCTE = "sqlc_tree"
+
+// TreePreload is a recursive preload of a self relation, created by PreloadTree().
+// Pass it to QueryBuilder.WithPreload().
+type TreePreload[T any, K comparable] struct {
+	rel      Relation[T, T, K]
+	maxDepth int
+}
+
+// PreloadTree creates a preload that loads the descendants of the loaded models
+// through a self-referencing HasMany or HasOne relation, down to maxDepth levels.
+//
+// Parameters:
+//   - rel: Self relation (e.g. generated.Category_Children)
+//   - maxDepth: Number of levels to load below the loaded models (1 = children only)
+//
+// Returns:
+//   - TreePreload[T, K]: Preload to pass to QueryBuilder.WithPreload()
+//
+// Example:
+//
+//	// Roots with children, grandchildren and great-grandchildren
+//	roots, err := categoryRepo.Query().
+//	    Where(generated.Category.ParentID.IsNull()).
+//	    WithPreload(sqlc.PreloadTree(generated.Category_Children, 3)).
+//	    Find(ctx)
+//
+// Note:
+//   - One WITH RECURSIVE query on MySQL 8+, PostgreSQL and SQLite; one query per level
+//     on dialects without the RecursiveCTE capability
+//   - Models at maxDepth keep their relation field unset (nil): whether they have
+//     children is not known
+//   - Soft-deleted models are skipped along with their subtrees
+//   - Children are ordered by their local key (usually the primary key)
+//   - Cycles in the parent links are cut: a model is loaded once under each parent
+func PreloadTree[T any, K comparable](rel Relation[T, T, K], maxDepth int) TreePreload[T, K] {
+	return TreePreload[T, K]{rel: rel, maxDepth: maxDepth}
+}
+
+// load implements preloadExecutor by setting the children of each model of the tree.
+func (p TreePreload[T, K]) load(ctx context.Context, session *Session, roots []*T) error {
+	if p.rel.Type != RelationHasMany && p.rel.Type != RelationHasOne {
+		return fmt.Errorf("sqlc: PreloadTree requires a HasMany or HasOne relation")
+	}
+	if p.maxDepth <= 0 {
+		return fmt.Errorf("sqlc: PreloadTree requires a positive maxDepth, got %d", p.maxDepth)
+	}
+	if len(roots) == 0 {
+		return nil
+	}
+
+	if !recursiveCTE(session.dialect) {
+		return p.loadLevels(ctx, session, roots)
+	}
+	descendants, err := p.findDescendants(ctx, session, roots)
+	if err != nil {
+		return err
+	}
+
+	// Group the descendants by parent, then walk the tree level by level from the roots
+	childMap := make(map[K][]*T)
+	for _, child := range descendants {
+		k := p.rel.GetForeignKeyValue(child)
+		childMap[k] = append(childMap[k], child)
+	}
+	level := roots
+	seen := make(map[[2]K]struct{}, len(descendants))
+	for depth := 0; depth < p.maxDepth && len(level) > 0; depth++ {
+		level = p.setChildren(level, childMap, seen)
+	}
+	return nil
+}
+
+// findDescendants reads the descendants of roots down to maxDepth with one recursive query.
+func (p TreePreload[T, K]) findDescendants(ctx context.Context, session *Session, roots []*T) ([]*T, error) {
+	seen := make(map[K]struct{}, len(roots))
+	rootKeys := make([]any, 0, len(roots))
+	for i := range roots {
+		k := p.rel.GetLocalKeyValue(roots[i])
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			rootKeys = append(rootKeys, k)
+		}
+	}
+
+	// Anchor: the children of the roots, at depth 1
+	// Placeholders are numbered when the outer query is built
+	anchor := Query[T](session).Where(clause.IN{Column: p.rel.ForeignKey, Values: rootKeys})
+	if anchor.err != nil {
+		return nil, anchor.err
+	}
+	anchorSQL, anchorArgs, err := anchor.resolveBuilder().
+		Columns(anchor.defaultColumns()...).
+		Column("1 AS sqlc_depth").
+		PlaceholderFormat(sq.Question).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build tree sql: %w", err)
+	}
+
+	// Recursive step: the children of the previous level, until maxDepth
+	step := Query[T](session)
+	step.hasJoin = true
+	step.builder = step.builder.
+		Join(fmt.Sprintf("%s ON %s.%s = %s.%s", treeCTE, treeCTE, p.rel.LocalKey.Name, step.table, p.rel.ForeignKey.Name)).
+		Where(sq.Expr(treeCTE+".sqlc_depth < ?", p.maxDepth))
+	stepSQL, stepArgs, err := step.resolveBuilder().
+		Columns(step.defaultColumns()...).
+		Column(treeCTE + ".sqlc_depth + 1").
+		PlaceholderFormat(sq.Question).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build tree sql: %w", err)
+	}
+
+	query := Query[T](session)
+	query.builder = sq.Select(query.schema.SelectColumns()...).
+		Prefix("WITH RECURSIVE "+treeCTE+" AS ("+anchorSQL+" UNION ALL "+stepSQL+")", append(anchorArgs, stepArgs...)...).
+		From(treeCTE).
+		OrderBy(p.rel.LocalKey.Name).
+		PlaceholderFormat(session.dialect.PlaceholderFormat())
+	treeSQL, args, err := query.builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("sqlc: failed to build tree sql: %w", err)
+	}
+	return query.find(ctx, treeSQL, args)
+}
+
+// loadLevels loads the tree with one query per level, for dialects without WITH RECURSIVE.
+func (p TreePreload[T, K]) loadLevels(ctx context.Context, session *Session, roots []*T) error {
+	preload := Preload(p.rel, func(q *QueryBuilder[T]) *QueryBuilder[T] {
+		return q.OrderBy(clause.OrderByColumn{Column: p.rel.LocalKey})
+	})
+	level := roots
+	seen := make(map[[2]K]struct{})
+	for depth := 0; depth < p.maxDepth && len(level) > 0; depth++ {
+		childMap, err := preload.loadChildren(ctx, session, level)
+		if err != nil {
+			return err
+		}
+		level = p.setChildren(level, childMap, seen)
+	}
+	return nil
+}
+
+// setChildren sets the children of each node of level from childMap and returns them,
+// the next level. Parent-child links seen before are cut, so cycles end.
+func (p TreePreload[T, K]) setChildren(level []*T, childMap map[K][]*T, seen map[[2]K]struct{}) []*T {
+	var next []*T
+	for _, node := range level {
+		var children []*T
+		for _, child := range childMap[p.rel.GetLocalKeyValue(node)] {
+			link := [2]K{p.rel.GetForeignKeyValue(child), p.rel.GetLocalKeyValue(child)}
+			if _, ok := seen[link]; !ok {
+				seen[link] = struct{}{}
+				children = append(children, child)
+			}
+		}
+		p.rel.Setter(node, children)
+		next = append(next, children...)
+	}
+	return next
+}
@@ -0,0 +1,215 @@
+package sqlc_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Category Model: a tree through parent_id (NULL for roots)
+type Category struct {
+	ID       int64       `db:"id,primaryKey,autoIncrement"`
+	Name     string      `db:"name"`
+	ParentID *int64      `db:"parent_id"`
+	Children []*Category `db:"-"`
+}
+
+type CategorySchema struct{}
+
+func (CategorySchema) TableName() string       { return "categories" }
+func (CategorySchema) SelectColumns() []string { return []string{"id", "name", "parent_id"} }
+func (CategorySchema) InsertRow(m *Category) ([]string, []any) {
+	return []string{"name", "parent_id"}, []any{m.Name, m.ParentID}
+}
+func (CategorySchema) PK(m *Category) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (CategorySchema) SetPK(m *Category, val int64)         { m.ID = val }
+func (CategorySchema) AutoIncrement() bool                  { return true }
+func (CategorySchema) SoftDeleteColumn() string             { return "" }
+func (CategorySchema) SoftDeleteValue() any                 { return nil }
+func (CategorySchema) SetDeletedAt(m *Category)             {}
+func (CategorySchema) UpdateMap(m *Category) map[string]any { return nil }
+
+// CategoryChildren defines the self relation: Category has many child Categories
+var CategoryChildren = sqlc.HasMany[Category, Category, int64](
+	clause.Column{Name: "parent_id"},
+	clause.Column{Name: "id"},
+	func(p *Category, children []*Category) { p.Children = children },
+	func(p *Category) int64 { return p.ID },
+	func(c *Category) int64 {
+		if c.ParentID == nil {
+			return 0
+		}
+		return *c.ParentID
+	},
+)
+
+// noCTEDialect is SQLite without the RecursiveCTE capability
+type noCTEDialect struct {
+	sqlc.SQLiteDialect
+}
+
+func (noCTEDialect) RecursiveCTE() bool { return false }
+
+func TestPreloadTree(t *testing.T) {
+	sqlc.RegisterSchema(CategorySchema{})
+	ctx := context.Background()
+
+	// Electronics > (Computers > (Laptops > Ultrabooks), Phones); Books
+	setup := func(t *testing.T) (*sql.DB, *sqlc.Session) {
+		t.Helper()
+		db, session := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec(`CREATE TABLE categories (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, parent_id INTEGER)`); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+		repo := sqlc.NewRepository[Category](session)
+		add := func(name string, parent *Category) *Category {
+			c := &Category{Name: name}
+			if parent != nil {
+				c.ParentID = &parent.ID
+			}
+			if err := repo.Create(ctx, c); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			return c
+		}
+		electronics := add("Electronics", nil)
+		computers := add("Computers", electronics)
+		add("Books", nil)
+		laptops := add("Laptops", computers)
+		add("Phones", electronics)
+		add("Ultrabooks", laptops)
+		return db, session
+	}
+	// outline renders the loaded tree, "?" marking models whose children were not loaded
+	var outline func(cs []*Category) string
+	outline = func(cs []*Category) string {
+		var parts []string
+		for _, c := range cs {
+			switch {
+			case c.Children == nil:
+				parts = append(parts, c.Name+"?")
+			case len(c.Children) == 0:
+				parts = append(parts, c.Name)
+			default:
+				parts = append(parts, c.Name+"("+outline(c.Children)+")")
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+	roots := func(t *testing.T, session *sqlc.Session, maxDepth int) string {
+		t.Helper()
+		cats, err := sqlc.NewRepository[Category](session).Query().
+			Where(clause.IsNull{Column: clause.Column{Name: "parent_id"}}).
+			OrderBy(clause.OrderByColumn{Column: clause.Column{Name: "id"}}).
+			WithPreload(sqlc.PreloadTree(CategoryChildren, maxDepth)).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		return outline(cats)
+	}
+
+	for _, tt := range []struct {
+		maxDepth int
+		want     string
+	}{
+		{1, "Electronics(Computers? Phones?) Books"},
+		{2, "Electronics(Computers(Laptops?) Phones) Books"},
+		{10, "Electronics(Computers(Laptops(Ultrabooks)) Phones) Books"},
+	} {
+		t.Run("RecursiveCTE", func(t *testing.T) {
+			_, session := setup(t)
+			if got := roots(t, session, tt.maxDepth); got != tt.want {
+				t.Errorf("maxDepth %d: got %s, want %s", tt.maxDepth, got, tt.want)
+			}
+		})
+		t.Run("PerLevel", func(t *testing.T) {
+			db, _ := setup(t)
+			if got := roots(t, sqlc.NewSession(db, noCTEDialect{}), tt.maxDepth); got != tt.want {
+				t.Errorf("maxDepth %d: got %s, want %s", tt.maxDepth, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Subtree", func(t *testing.T) {
+		db, _ := setup(t)
+		session := sqlc.NewSession(db, sqlc.SQLiteDialect{}, sqlc.WithQueryCapture(8))
+		computers, err := sqlc.NewRepository[Category](session).Query().
+			Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: "Computers"}).
+			WithPreload(sqlc.PreloadTree(CategoryChildren, 5)).
+			Find(ctx)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if got := outline(computers); got != "Computers(Laptops(Ultrabooks))" {
+			t.Errorf("unexpected subtree %s", got)
+		}
+		// The roots query, then one recursive query for the whole subtree
+		queries := session.RecentQueries(8)
+		if len(queries) != 2 || !strings.HasPrefix(queries[1].SQL, "WITH RECURSIVE sqlc_tree AS (") {
+			t.Errorf("expected 2 queries, the second recursive, got %v", queries)
+		}
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		for name, dialect := range map[string]sqlc.Dialect{"RecursiveCTE": sqlc.SQLiteDialect{}, "PerLevel": noCTEDialect{}} {
+			t.Run(name, func(t *testing.T) {
+				db, _ := setup(t)
+				session := sqlc.NewSession(db, dialect)
+				// Electronics becomes a child of Ultrabooks
+				if _, err := session.Exec(ctx, "UPDATE categories SET parent_id = (SELECT id FROM categories WHERE name = 'Ultrabooks') WHERE name = 'Electronics'"); err != nil {
+					t.Fatalf("Exec failed: %v", err)
+				}
+				cats, err := sqlc.NewRepository[Category](session).Query().
+					Where(clause.Eq{Column: clause.Column{Name: "name"}, Value: "Electronics"}).
+					WithPreload(sqlc.PreloadTree(CategoryChildren, 10)).
+					Find(ctx)
+				if err != nil {
+					t.Fatalf("Find failed: %v", err)
+				}
+				want := "Electronics(Computers(Laptops(Ultrabooks(Electronics))) Phones)"
+				if got := outline(cats); got != want {
+					t.Errorf("got %s, want %s", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("InvalidDepth", func(t *testing.T) {
+		_, session := setup(t)
+		_, err := sqlc.NewRepository[Category](session).Query().
+			WithPreload(sqlc.PreloadTree(CategoryChildren, 0)).
+			Find(ctx)
+		if err == nil {
+			t.Fatal("expected error for maxDepth 0")
+		}
+	})
+
+	t.Run("BelongsToRejected", func(t *testing.T) {
+		parent := sqlc.BelongsTo[Category, Category, int64](
+			clause.Column{Name: "parent_id"},
+			clause.Column{Name: "id"},
+			func(c *Category, p *Category) {},
+			func(c *Category) int64 { return 0 },
+			func(p *Category) int64 { return p.ID },
+		)
+		_, session := setup(t)
+		_, err := sqlc.NewRepository[Category](session).Query().
+			WithPreload(sqlc.PreloadTree(parent, 2)).
+			Find(ctx)
+		if err == nil {
+			t.Fatal("expected error for a BelongsTo relation")
+		}
+	})
+}
@@ -21,7 +21,6 @@ import (
 //   - []string: Slice of column names, returns nil if input is empty
 //
 // Usage scenarios:
-//   - QueryBuilder.Select(): Resolve selected columns
 //   - QueryBuilder.GroupBy(): Resolve group-by columns
 //   - Repository.Upsert(): Resolve conflict columns and update columns
 //
@@ -39,10 +38,6 @@ import (
 //	})
 //	// columns = ["id", "email", "name"]
 //
-//	// Use in Select
-//	query.Select(generated.User.ID, generated.User.Email)
-//	// Internally calls: ResolveColumnNames([]clause.Columnar{...})
-//
 //	// Use in GroupBy
 //	query.GroupBy(generated.User.Status)
 //	// Internally calls: ResolveColumnNames([]clause.Columnar{...})
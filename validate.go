@@ -0,0 +1,335 @@
+// Package sqlc provides a type-safe ORM library using generics and code generation.
+// This file implements declarative model validation.
+//
+// Fields declare their constraints in a validate struct tag. The code generator copies
+// the tags into the schema (ValidatedSchema), and repositories check them before writing:
+//
+//	type User struct {
+//	    ID     int64  `db:"id,primaryKey,autoIncrement"`
+//	    Email  string `db:"email" validate:"required,email,max=255"`
+//	    Name   string `db:"name" validate:"required,max=100"`
+//	    Status string `db:"status" validate:"oneof=active suspended"`
+//	}
+//
+// Rules:
+//   - required: not nil, not the zero value ("" for strings, 0 for numbers)
+//   - max=N / min=N: string length in characters, number of items of slices and maps,
+//     or the value itself for numbers
+//   - email: an address of the form name@example.com
+//   - oneof=a b c: one of the space-separated values
+//
+// Rules other than required accept NULL and empty strings; combine them with required
+// to make a field mandatory.
+//
+// Usage example:
+//
+//	err := userRepo.Create(ctx, &models.User{Email: "not-an-email"})
+//	var invalid *sqlc.ValidationError
+//	if errors.As(err, &invalid) {
+//	    for _, f := range invalid.Fields {
+//	        fmt.Println(f.Field, f.Message) // Email must be a valid email address
+//	    }
+//	}
+package sqlc
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arllen133/sqlc/clause"
+)
+
+// FieldRule holds the validate tag of one model field, for ValidatedSchema.
+type FieldRule[T any] struct {
+	Field  string         // Go field name (e.g. "Email")
+	Column string         // Column name (e.g. "email")
+	Rules  string         // Content of the validate tag (e.g. "required,email")
+	Value  func(m *T) any // Returns the field's value
+}
+
+// ValidatedSchema is optionally implemented by schemas whose models declare validate tags.
+// Repositories check the rules before Create, BatchCreate, CreateOrIgnore, Upsert, Update,
+// UpdateColumns and UpdateWhere, and fail with a *ValidationError without writing.
+//
+// Example:
+//
+//	func (s UserSchema) ValidationRules() []sqlc.FieldRule[models.User] {
+//	    return []sqlc.FieldRule[models.User]{
+//	        {Field: "Email", Column: "email", Rules: "required,email", Value: func(m *models.User) any { return m.Email }},
+//	    }
+//	}
+//
+// Note:
+//   - Generated schemas implement it from the validate tags of the model
+type ValidatedSchema[T any] interface {
+	ValidationRules() []FieldRule[T]
+}
+
+// FieldError is a rule violated by a field.
+type FieldError struct {
+	Field   string // Go field name
+	Column  string // Column name
+	Rule    string // Violated rule (e.g. "max")
+	Param   string // Parameter of the rule (e.g. "100"), "" if none
+	Message string // Human-readable description (e.g. "must be at most 100 characters")
+}
+
+// ValidationError reports the fields of a model that violate their validate rules.
+type ValidationError struct {
+	Model  string       // Go model type (e.g. "models.User")
+	Fields []FieldError // Violations, in field declaration order
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+	return fmt.Sprintf("sqlc: invalid %s: %s", e.Model, strings.Join(msgs, "; "))
+}
+
+// SkipValidation returns a new Repository instance whose writes do not check validate
+// rules, e.g. for imports of data validated elsewhere.
+//
+// Example:
+//
+//	err := userRepo.SkipValidation().BatchCreate(ctx, imported)
+func (r *Repository[T]) SkipValidation() *Repository[T] {
+	newRepo := *r
+	newRepo.noValidation = true
+	return &newRepo
+}
+
+// Validate checks model against the validate rules of its schema without writing it,
+// e.g. to report every invalid field of a request before starting a transaction.
+//
+// Returns:
+//   - error: *ValidationError listing the violations, or nil if model is valid
+//
+// Example:
+//
+//	if err := userRepo.Validate(&input); err != nil {
+//	    return http.StatusUnprocessableEntity, err
+//	}
+func (r *Repository[T]) Validate(model *T) error {
+	return r.validateModel(model, nil)
+}
+
+// validationRules returns the validate rules of schema, or nil if it has none.
+func validationRules[T any](schema Schema[T]) []FieldRule[T] {
+	if vs, ok := schema.(ValidatedSchema[T]); ok {
+		return vs.ValidationRules()
+	}
+	return nil
+}
+
+// validateModel checks the rules of the fields of model, or only of the given columns
+// if columns is not nil. SkipValidation disables it.
+func (r *Repository[T]) validateModel(model *T, columns map[string]any) error {
+	if r.noValidation {
+		return nil
+	}
+	var fields []FieldError
+	for _, rule := range validationRules(r.schema) {
+		if columns != nil {
+			if _, ok := columns[rule.Column]; !ok {
+				continue
+			}
+		}
+		errs, err := checkRules(rule.Field, rule.Column, rule.Rules, rule.Value(model))
+		if err != nil {
+			return err
+		}
+		fields = append(fields, errs...)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Model: reflect.TypeFor[T]().String(), Fields: fields}
+}
+
+// validateAssignments checks the values of assignments against the rules of their columns.
+// SQL expressions (e.g. counter increments) are not checked.
+func (r *Repository[T]) validateAssignments(assignments []clause.Assignment) error {
+	rules := validationRules(r.schema)
+	if len(rules) == 0 || r.noValidation {
+		return nil
+	}
+	var fields []FieldError
+	for _, a := range assignments {
+		if _, ok := a.Value.(clause.Expression); ok {
+			continue
+		}
+		i := slices.IndexFunc(rules, func(rule FieldRule[T]) bool { return rule.Column == a.Column.Name })
+		if i < 0 {
+			continue
+		}
+		errs, err := checkRules(rules[i].Field, rules[i].Column, rules[i].Rules, a.Value)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, errs...)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Model: reflect.TypeFor[T]().String(), Fields: fields}
+}
+
+// validationRule is a parsed rule of a validate tag
+type validationRule struct {
+	name  string // Rule name (e.g. "max")
+	param string // Parameter after "=", "" if none
+}
+
+// CheckValidationTag reports whether tag is a valid validate tag: known rules, with the
+// parameters they require. The code generator uses it to reject invalid tags early.
+//
+// Example:
+//
+//	sqlc.CheckValidationTag("required,max=100") // nil
+//	sqlc.CheckValidationTag("maxlen=100")       // error: unknown validation rule "maxlen"
+func CheckValidationTag(tag string) error {
+	_, err := parseValidationTag(tag)
+	return err
+}
+
+// parseValidationTag parses a validate tag into its rules.
+func parseValidationTag(tag string) ([]validationRule, error) {
+	var rules []validationRule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "":
+			continue
+		case "required", "email":
+			if param != "" {
+				return nil, fmt.Errorf("sqlc: validation rule %q takes no parameter", name)
+			}
+		case "max", "min":
+			if _, err := strconv.ParseFloat(param, 64); err != nil {
+				return nil, fmt.Errorf("sqlc: validation rule %q requires a number, got %q", name, param)
+			}
+		case "oneof":
+			if len(strings.Fields(param)) == 0 {
+				return nil, fmt.Errorf("sqlc: validation rule %q requires values", name)
+			}
+		default:
+			return nil, fmt.Errorf("sqlc: unknown validation rule %q", name)
+		}
+		rules = append(rules, validationRule{name: name, param: param})
+	}
+	return rules, nil
+}
+
+// checkRules checks value against a validate tag, returning the violated rules.
+// An invalid tag, or a rule not applicable to the value's type, is an error rather than a violation.
+func checkRules(field, column, tag string, value any) ([]FieldError, error) {
+	rules, err := parseValidationTag(tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w on %s", err, field)
+	}
+	v, isNull := validationValue(value)
+	var fields []FieldError
+	for _, rule := range rules {
+		// Rules other than required accept NULL and empty strings
+		if rule.name != "required" && (isNull || v.Kind() == reflect.String && v.Len() == 0) {
+			continue
+		}
+		msg, err := checkRule(rule, v, isNull)
+		if err != nil {
+			return nil, fmt.Errorf("sqlc: validation rule %q on %s: %w", rule.name, field, err)
+		}
+		if msg != "" {
+			fields = append(fields, FieldError{Field: field, Column: column, Rule: rule.name, Param: rule.param, Message: msg})
+		}
+	}
+	return fields, nil
+}
+
+// validationValue dereferences pointers and resolves driver.Valuer values (sql.NullString,
+// ...), reporting whether the value is NULL.
+func validationValue(value any) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, true
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return v, true
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		if dv, err := valuer.Value(); err == nil {
+			if dv == nil {
+				return reflect.Value{}, true
+			}
+			if _, isBytes := dv.([]byte); !isBytes {
+				v = reflect.ValueOf(dv)
+			}
+		}
+	}
+	return v, false
+}
+
+// checkRule checks one rule, returning the violation message or "" if v satisfies it.
+func checkRule(rule validationRule, v reflect.Value, isNull bool) (string, error) {
+	param := rule.param
+	switch rule.name {
+	case "required":
+		if isNull || v.IsZero() {
+			return "is required", nil
+		}
+	case "max", "min":
+		limit, _ := strconv.ParseFloat(param, 64)
+		size, unit, ok := validationSize(v)
+		if !ok {
+			return "", fmt.Errorf("unsupported type %s", v.Type())
+		}
+		if rule.name == "max" && size > limit {
+			return "must be at most " + param + unit, nil
+		}
+		if rule.name == "min" && size < limit {
+			return "must be at least " + param + unit, nil
+		}
+	case "email":
+		if v.Kind() != reflect.String {
+			return "", fmt.Errorf("unsupported type %s", v.Type())
+		}
+		addr, err := mail.ParseAddress(v.String())
+		if err != nil || addr.Address != v.String() {
+			return "must be a valid email address", nil
+		}
+	case "oneof":
+		values := strings.Fields(param)
+		if !slices.Contains(values, fmt.Sprint(v.Interface())) {
+			return "must be one of " + strings.Join(values, ", "), nil
+		}
+	}
+	return "", nil
+}
+
+// validationSize returns the measure of v compared by max and min, and its unit.
+func validationSize(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	}
+	return 0, "", false
+}
@@ -0,0 +1,231 @@
+package sqlc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arllen133/sqlc"
+	"github.com/arllen133/sqlc/clause"
+)
+
+// Signup Model: fields declaring validate rules
+type Signup struct {
+	ID       int64    `db:"id,primaryKey,autoIncrement"`
+	Email    string   `db:"email" validate:"required,email,max=40"`
+	Name     string   `db:"name" validate:"required,max=10"`
+	Role     string   `db:"role" validate:"oneof=admin member"`
+	Nickname *string  `db:"nickname" validate:"min=3"`
+	Level    int      `db:"level" validate:"min=1,max=5"`
+	Tags     []string `db:"-" validate:"max=2"`
+	Removed  bool     `db:"removed"` // Soft delete flag, registered by the SoftDelete subtest
+}
+
+type SignupSchema struct{}
+
+func (SignupSchema) TableName() string { return "signups" }
+func (SignupSchema) SelectColumns() []string {
+	return []string{"id", "email", "name", "role", "nickname", "level"}
+}
+func (SignupSchema) InsertRow(m *Signup) ([]string, []any) {
+	return []string{"email", "name", "role", "nickname", "level"}, []any{m.Email, m.Name, m.Role, m.Nickname, m.Level}
+}
+func (SignupSchema) UpdateMap(m *Signup) map[string]any {
+	return map[string]any{"email": m.Email, "name": m.Name, "role": m.Role, "nickname": m.Nickname, "level": m.Level}
+}
+func (SignupSchema) PK(m *Signup) sqlc.PK {
+	var val any
+	if m != nil {
+		val = m.ID
+	}
+	return sqlc.PK{Column: clause.Column{Name: "id"}, Value: val}
+}
+func (SignupSchema) SetPK(m *Signup, val int64) { m.ID = val }
+func (SignupSchema) AutoIncrement() bool        { return true }
+func (SignupSchema) SoftDeleteColumn() string   { return "" }
+func (SignupSchema) SoftDeleteValue() any       { return nil }
+func (SignupSchema) SetDeletedAt(m *Signup)     {}
+func (SignupSchema) ValidationRules() []sqlc.FieldRule[Signup] {
+	return []sqlc.FieldRule[Signup]{
+		{Field: "Email", Column: "email", Rules: "required,email,max=40", Value: func(m *Signup) any { return m.Email }},
+		{Field: "Name", Column: "name", Rules: "required,max=10", Value: func(m *Signup) any { return m.Name }},
+		{Field: "Role", Column: "role", Rules: "oneof=admin member", Value: func(m *Signup) any { return m.Role }},
+		{Field: "Nickname", Column: "nickname", Rules: "min=3", Value: func(m *Signup) any { return m.Nickname }},
+		{Field: "Level", Column: "level", Rules: "min=1,max=5", Value: func(m *Signup) any { return m.Level }},
+		{Field: "Tags", Column: "tags", Rules: "max=2", Value: func(m *Signup) any { return m.Tags }},
+	}
+}
+
+func TestValidation(t *testing.T) {
+	sqlc.RegisterSchema(SignupSchema{})
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*sqlc.Session, *sqlc.Repository[Signup]) {
+		t.Helper()
+		db, session := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec(`CREATE TABLE signups (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT, name TEXT, role TEXT, nickname TEXT, level INTEGER)`); err != nil {
+			t.Fatalf("failed to create table: %v", err)
+		}
+		return session, sqlc.NewRepository[Signup](session)
+	}
+	count := func(t *testing.T, session *sqlc.Session) int {
+		t.Helper()
+		var n int
+		if err := session.Get(ctx, &n, "SELECT COUNT(*) FROM signups"); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		return n
+	}
+	violations := func(err error) string {
+		var invalid *sqlc.ValidationError
+		if err == nil {
+			return "no error"
+		}
+		if !errors.As(err, &invalid) {
+			return "no ValidationError: " + err.Error()
+		}
+		var out []string
+		for _, f := range invalid.Fields {
+			out = append(out, f.Field+":"+f.Rule)
+		}
+		return strings.Join(out, ",")
+	}
+	valid := func() *Signup {
+		return &Signup{Email: "ann@test.com", Name: "Ann", Role: "admin", Level: 1}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		session, repo := setup(t)
+		short := "x"
+		err := repo.Create(ctx, &Signup{Email: "ann(at)test.com", Role: "owner", Nickname: &short, Level: 9, Tags: []string{"a", "b", "c"}})
+		if got := violations(err); got != "Email:email,Name:required,Role:oneof,Nickname:min,Level:max,Tags:max" {
+			t.Errorf("unexpected violations %s", got)
+		}
+		if want := "sqlc: invalid sqlc_test.Signup: Email must be a valid email address; Name is required; Role must be one of admin, member;"; !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("unexpected message %q", err.Error())
+		}
+		if n := count(t, session); n != 0 {
+			t.Errorf("expected nothing inserted, got %d rows", n)
+		}
+
+		// NULL and empty values pass the rules other than required
+		m := valid()
+		m.Role = ""
+		if err := repo.Create(ctx, m); err != nil {
+			t.Errorf("Create failed: %v", err)
+		}
+	})
+
+	t.Run("BatchCreate", func(t *testing.T) {
+		session, repo := setup(t)
+		invalid := valid()
+		invalid.Name = "Bartholomew Jr."
+		err := repo.BatchCreate(ctx, []*Signup{valid(), invalid})
+		if got := violations(err); got != "Name:max" || !strings.Contains(err.Error(), "record 1") {
+			t.Errorf("unexpected error %v", err)
+		}
+		if n := count(t, session); n != 0 {
+			t.Errorf("expected nothing inserted, got %d rows", n)
+		}
+		if err := repo.SkipValidation().BatchCreate(ctx, []*Signup{valid(), invalid}); err != nil {
+			t.Errorf("expected SkipValidation to write, got %v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		_, repo := setup(t)
+		m := valid()
+		if err := repo.Create(ctx, m); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		m.Level = 0
+		if got := violations(repo.Update(ctx, m)); got != "Level:min" {
+			t.Errorf("unexpected violations %s", got)
+		}
+		// Only the written columns are checked
+		m.Name = "Annabel"
+		if err := repo.Select(clause.Column{Name: "name"}).Update(ctx, m); err != nil {
+			t.Errorf("expected the name update to pass, got %v", err)
+		}
+
+		email := clause.Column{Name: "email"}
+		err := repo.UpdateColumns(ctx, m.ID, clause.Assignment{Column: email, Value: "nope"})
+		if got := violations(err); got != "Email:email" {
+			t.Errorf("unexpected violations %s", got)
+		}
+		err = repo.UpdateColumns(ctx, m.ID, clause.Assignment{Column: email, Value: clause.Expr{SQL: "lower(email)"}})
+		if err != nil {
+			t.Errorf("expected expressions unchecked, got %v", err)
+		}
+		err = repo.Where(clause.Eq{Column: email, Value: "ann@test.com"}).
+			UpdateColumns(ctx, m.ID, clause.Assignment{Column: clause.Column{Name: "level"}, Value: 6})
+		if got := violations(err); got != "Level:max" {
+			t.Errorf("unexpected violations %s", got)
+		}
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		_, repo := setup(t)
+		if err := repo.Validate(valid()); err != nil {
+			t.Errorf("expected valid, got %v", err)
+		}
+		if got := violations(repo.Validate(&Signup{})); got != "Email:required,Name:required,Level:min" {
+			t.Errorf("unexpected violations %s", got)
+		}
+	})
+
+	// Partitioned and soft-delete repositories wrap the registered schema
+	t.Run("Partitioned", func(t *testing.T) {
+		db, session := setupTestDB(t)
+		t.Cleanup(func() { db.Close() })
+		for _, table := range []string{"signups_0", "signups_1"} {
+			if _, err := db.Exec(`CREATE TABLE ` + table + ` (id INTEGER PRIMARY KEY AUTOINCREMENT, email TEXT, name TEXT, role TEXT, nickname TEXT, level INTEGER)`); err != nil {
+				t.Fatalf("failed to create table: %v", err)
+			}
+		}
+		repo := sqlc.NewPartitionedRepository(session, 2, func(m *Signup) string { return m.Email })
+		invalid := valid()
+		invalid.Level = 9
+		if got := violations(repo.Create(ctx, invalid)); got != "Level:max" {
+			t.Errorf("unexpected violations %s", got)
+		}
+		if err := repo.Create(ctx, valid()); err != nil {
+			t.Errorf("Create failed: %v", err)
+		}
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		sqlc.RegisterSoftDeleteFlag[Signup]("removed")
+		defer sqlc.RegisterSoftDelete[Signup]("") // Back to hard deletes
+		session, repo := setup(t)
+		if _, err := session.Exec(ctx, "ALTER TABLE signups ADD COLUMN removed BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+			t.Fatalf("failed to add column: %v", err)
+		}
+		invalid := valid()
+		invalid.Level = 9
+		if got := violations(repo.Create(ctx, invalid)); got != "Level:max" {
+			t.Errorf("unexpected violations %s", got)
+		}
+		if got := violations(repo.Validate(&Signup{Email: "ann@test.com", Name: "Ann", Level: 1, Role: "guest"})); got != "Role:oneof" {
+			t.Errorf("unexpected violations %s", got)
+		}
+	})
+
+	t.Run("CheckValidationTag", func(t *testing.T) {
+		for tag, ok := range map[string]bool{
+			"required,email,max=10": true,
+			"oneof=a b":             true,
+			"":                      true,
+			"maxlen=10":             false,
+			"max=ten":               false,
+			"oneof=":                false,
+			"required=1":            false,
+		} {
+			if err := sqlc.CheckValidationTag(tag); (err == nil) != ok {
+				t.Errorf("%q: unexpected result %v", tag, err)
+			}
+		}
+	})
+}